@@ -22,7 +22,6 @@ import (
 	"sync/atomic"
 	"time"
 
-	"github.com/cheggaaa/pb"
 	json "github.com/minio/mc/pkg/colorjson"
 	"github.com/minio/mc/pkg/probe"
 )
@@ -44,7 +43,7 @@ type accounter struct {
 func newAccounter(total int64) *accounter {
 	acct := &accounter{
 		Total:        total,
-		startTime:    time.Now(),
+		startTime:    nowFunc(),
 		startValue:   0,
 		refreshRate:  time.Millisecond * 200,
 		isFinished:   make(chan struct{}),
@@ -56,7 +55,7 @@ func newAccounter(total int64) *accounter {
 
 // write calculate the final speed.
 func (a *accounter) write(current int64) float64 {
-	fromStart := time.Since(a.startTime)
+	fromStart := nowFunc().Sub(a.startTime)
 	currentFromStart := current - a.startValue
 	if currentFromStart > 0 {
 		speed := float64(currentFromStart) / (float64(fromStart) / float64(time.Second))
@@ -84,6 +83,8 @@ type accountStat struct {
 	Total       int64   `json:"total"`
 	Transferred int64   `json:"transferred"`
 	Speed       float64 `json:"speed"`
+	// Objects skipped by --max-object-size.
+	SkippedTooLarge int64 `json:"skippedTooLarge,omitempty"`
 }
 
 func (c accountStat) JSON() string {
@@ -95,14 +96,11 @@ func (c accountStat) JSON() string {
 }
 
 func (c accountStat) String() string {
-	speedBox := pb.Format(int64(c.Speed)).To(pb.U_BYTES).String()
-	if speedBox == "" {
-		speedBox = "0 MB"
-	} else {
-		speedBox = speedBox + "/s"
+	message := fmt.Sprintf("Total: %s, Transferred: %s, Speed: %s/s", humanizeSize(c.Total),
+		humanizeSize(c.Transferred), humanizeSize(int64(c.Speed)))
+	if c.SkippedTooLarge > 0 {
+		message += fmt.Sprintf(", Skipped (too large): %d", c.SkippedTooLarge)
 	}
-	message := fmt.Sprintf("Total: %s, Transferred: %s, Speed: %s", pb.Format(c.Total).To(pb.U_BYTES),
-		pb.Format(c.Transferred).To(pb.U_BYTES), speedBox)
 	return message
 }
 
@@ -114,6 +112,7 @@ func (a *accounter) Stat() accountStat {
 		acntStat.Total = a.Total
 		acntStat.Transferred = atomic.LoadInt64(&a.current)
 		acntStat.Speed = a.write(atomic.LoadInt64(&a.current))
+		acntStat.SkippedTooLarge = atomic.LoadInt64(&globalSkippedTooLarge)
 	})
 	return acntStat
 }
@@ -20,7 +20,6 @@ import (
 	"fmt"
 	"path/filepath"
 	"strings"
-	"time"
 
 	"github.com/fatih/color"
 	"github.com/minio/cli"
@@ -154,7 +153,7 @@ func (s backgroundHealStatusMessage) String() string {
 	healPrettyMsg += fmt.Sprintf("  Total items scanned: %s\n",
 		console.Colorize("HealBackground", s.HealInfo.ScannedItemsCount))
 	healPrettyMsg += fmt.Sprintf("  Last background heal check: %s\n",
-		console.Colorize("HealBackground", timeDurationToHumanizedDuration(time.Since(s.HealInfo.LastHealActivity)).String()+" ago"))
+		console.Colorize("HealBackground", timeDurationToHumanizedDuration(UTCNow().Sub(s.HealInfo.LastHealActivity)).String()+" ago"))
 	return healPrettyMsg
 }
 
@@ -110,7 +110,7 @@ func (u clusterStruct) String() (msg string) {
 
 		// Uptime
 		msg += fmt.Sprintf("   Uptime: %s\n", console.Colorize("Info",
-			humanize.RelTime(time.Now(), time.Now().Add(time.Duration(srv.Uptime)*time.Second), "", "")))
+			humanize.RelTime(nowFunc(), nowFunc().Add(time.Duration(srv.Uptime)*time.Second), "", "")))
 
 		// Version
 		version := srv.Version
@@ -173,7 +173,7 @@ func (u clusterStruct) String() (msg string) {
 
 	// Summary on used space, total no of buckets and
 	// total no of objects at the Cluster level
-	usedTotal := humanize.IBytes(uint64(u.Info.Usage.Size))
+	usedTotal := humanizeSize(int64(u.Info.Usage.Size))
 	if u.Info.Buckets.Count > 0 {
 		msg += fmt.Sprintf("%s Used, %s, %s", usedTotal,
 			english.Plural(int(u.Info.Buckets.Count), "Bucket", ""),
@@ -56,7 +56,7 @@ type lockMessage struct {
 }
 
 func getTimeDiff(timeStamp time.Time) (string, string) {
-	now := time.Now().UTC()
+	now := UTCNow()
 	diff := now.Sub(timeStamp)
 	hours := int(diff.Hours())
 	minutes := int(diff.Minutes()) % 60
@@ -17,10 +17,13 @@
 package cmd
 
 import (
+	"encoding/json"
+	"io/ioutil"
 	"os"
 	"path/filepath"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/minio/cli"
 	"github.com/posener/complete"
@@ -55,38 +58,123 @@ func completeAdminConfigKeys(aliasPath string, keyPrefix string) (prediction []s
 	return prediction
 }
 
-// Complete S3 path. If the prediction result is only one directory,
-// then recursively scans it. This is needed to satisfy posener/complete
-// (look at posener/complete.PredictFiles)
-func completeS3Path(s3Path string) (prediction []string) {
+const (
+	// Remote completion gives up listing after the timeout, so that
+	// the shell never hangs on a slow or unreachable host.
+	remoteCompletionTimeout = 2 * time.Second
 
-	// Convert alias/bucket/incompl to alias/bucket/ to list its contents
-	parentDirPath := filepath.Dir(s3Path) + "/"
-	clnt, err := newClient(parentDirPath)
+	// Listings are cached for the next tabs, which mostly complete the
+	// same folder.
+	remoteCompletionCacheTTL  = 30 * time.Second
+	remoteCompletionCacheFile = "completion-cache.json"
+)
+
+// completionCacheEntry - the paths listed in a folder.
+type completionCacheEntry struct {
+	Time  time.Time `json:"time"`
+	Paths []string  `json:"paths"`
+}
+
+// loadCompletionCache - returns the listings cached by earlier
+// completions, those older than the TTL are dropped.
+func loadCompletionCache() map[string]completionCacheEntry {
+	cache := make(map[string]completionCacheEntry)
+	configDir, err := getMcConfigDir()
+	if err != nil {
+		return cache
+	}
+	data, e := ioutil.ReadFile(filepath.Join(configDir, remoteCompletionCacheFile))
+	if e != nil || json.Unmarshal(data, &cache) != nil {
+		return make(map[string]completionCacheEntry)
+	}
+	for dirPath, entry := range cache {
+		if nowFunc().Sub(entry.Time) > remoteCompletionCacheTTL {
+			delete(cache, dirPath)
+		}
+	}
+	return cache
+}
+
+// saveCompletionCache - saves the cache, completions are never failed
+// by the cache.
+func saveCompletionCache(cache map[string]completionCacheEntry) {
+	configDir, err := getMcConfigDir()
+	if err != nil {
+		return
+	}
+	if data, e := json.Marshal(cache); e == nil {
+		ioutil.WriteFile(filepath.Join(configDir, remoteCompletionCacheFile), data, 0600)
+	}
+}
+
+// listCompletionPaths - lists the paths in dirPath, alias/bucket/ for
+// instance, until the deadline. The paths listed are cached unless the
+// listing was cut short or failed for some of them.
+func listCompletionPaths(dirPath string, deadline time.Time) []string {
+	cache := loadCompletionCache()
+	if entry, ok := cache[dirPath]; ok {
+		return entry.Paths
+	}
+
+	clnt, err := newClient(dirPath)
 	if err != nil {
 		return nil
 	}
 
 	// Calculate alias from the path
-	alias := splitStr(s3Path, "/", 3)[0]
+	alias := splitStr(dirPath, "/", 3)[0]
+
+	timer := time.NewTimer(deadline.Sub(nowFunc()))
+	defer timer.Stop()
+	var paths []string
+	listFailed := false
+	contentCh := clnt.List(false, false, false, DirFirst)
+	for {
+		select {
+		case content, ok := <-contentCh:
+			if !ok {
+				if !listFailed {
+					cache[dirPath] = completionCacheEntry{Time: nowFunc(), Paths: paths}
+					saveCompletionCache(cache)
+				}
+				return paths
+			}
+			if content.Err != nil {
+				listFailed = true
+				continue
+			}
+			cmplS3Path := alias + getKey(content)
+			if content.Type.IsDir() {
+				if !strings.HasSuffix(cmplS3Path, "/") {
+					cmplS3Path += "/"
+				}
+			}
+			paths = append(paths, cmplS3Path)
+		case <-timer.C:
+			return paths
+		}
+	}
+}
+
+// Complete S3 path. If the prediction result is only one directory,
+// then recursively scans it. This is needed to satisfy posener/complete
+// (look at posener/complete.PredictFiles)
+func completeS3Path(s3Path string, deadline time.Time) (prediction []string) {
+
+	// Convert alias/bucket/incompl to alias/bucket/ to list its contents
+	parentDirPath := filepath.Dir(s3Path) + "/"
 
 	// List dirPath content and only pick elements that corresponds
 	// to the path that we want to complete
-	for content := range clnt.List(false, false, false, DirFirst) {
-		cmplS3Path := alias + getKey(content)
-		if content.Type.IsDir() {
-			if !strings.HasSuffix(cmplS3Path, "/") {
-				cmplS3Path += "/"
-			}
-		}
+	for _, cmplS3Path := range listCompletionPaths(parentDirPath, deadline) {
 		if strings.HasPrefix(cmplS3Path, s3Path) {
 			prediction = append(prediction, cmplS3Path)
 		}
 	}
 
 	// If completion found only one directory, recursively scan it.
-	if len(prediction) == 1 && strings.HasSuffix(prediction[0], "/") {
-		prediction = append(prediction, completeS3Path(prediction[0])...)
+	if len(prediction) == 1 && strings.HasSuffix(prediction[0], "/") && nowFunc().Before(deadline) {
+		prediction = append(prediction, completeS3Path(prediction[0], deadline)...)
 	}
 
 	return
@@ -154,8 +242,8 @@ func (s3 s3Complete) Predict(a complete.Args) (prediction []string) {
 				prediction = append(prediction, alias+"/")
 			}
 		}
-		if len(prediction) == 1 && strings.HasSuffix(prediction[0], "/") {
-			prediction = append(prediction, completeS3Path(prediction[0])...)
+		if len(prediction) == 1 && strings.HasSuffix(prediction[0], "/") && conf.RemoteCompletion {
+			prediction = append(prediction, completeS3Path(prediction[0], nowFunc().Add(remoteCompletionTimeout))...)
 		}
 	} else {
 		// Complete S3 path until the specified path deep level
@@ -164,8 +252,10 @@ func (s3 s3Complete) Predict(a complete.Args) (prediction []string) {
 				return []string{arg}
 			}
 		}
-		// Predict S3 path
-		prediction = completeS3Path(arg)
+		// Predict S3 path, if remote completion is enabled.
+		if conf.RemoteCompletion {
+			prediction = completeS3Path(arg, nowFunc().Add(remoteCompletionTimeout))
+		}
 	}
 
 	return
@@ -208,9 +298,11 @@ var completeCmds = map[string]complete.Predictor{
 	"/cp":        complete.PredictOr(s3Completer, fsCompleter),
 	"/rm":        complete.PredictOr(s3Completer, fsCompleter),
 	"/rb":        complete.PredictOr(s3Complete{deepLevel: 2}, fsCompleter),
+	"/clean":     s3Completer,
 	"/cat":       complete.PredictOr(s3Completer, fsCompleter),
 	"/head":      complete.PredictOr(s3Completer, fsCompleter),
 	"/diff":      complete.PredictOr(s3Completer, fsCompleter),
+	"/compare":   complete.PredictOr(fsCompleter, s3Completer),
 	"/find":      complete.PredictOr(s3Completer, fsCompleter),
 	"/mirror":    complete.PredictOr(s3Completer, fsCompleter),
 	"/pipe":      complete.PredictOr(s3Completer, fsCompleter),
@@ -220,14 +312,23 @@ var completeCmds = map[string]complete.Predictor{
 	"/tree":      complete.PredictOr(s3Complete{deepLevel: 2}, fsCompleter),
 	"/du":        complete.PredictOr(s3Complete{deepLevel: 2}, fsCompleter),
 	"/retention": s3Completer,
+	"/legalhold": s3Completer,
 	"/sql":       s3Completer,
 	"/lock":      complete.PredictOr(s3Complete{deepLevel: 2}),
 	"/mb":        aliasCompleter,
+	"/ping":      aliasCompleter,
 
 	"/event/add":    aliasCompleter,
 	"/event/list":   aliasCompleter,
 	"/event/remove": aliasCompleter,
 
+	"/cors/get":    aliasCompleter,
+	"/cors/set":    aliasCompleter,
+	"/cors/remove": aliasCompleter,
+
+	"/trash/restore": complete.PredictOr(s3Completer, fsCompleter),
+	"/trash/empty":   complete.PredictOr(s3Complete{deepLevel: 2}, fsCompleter),
+
 	"/share/download": s3Completer,
 	"/share/list":     nil,
 	"/share/upload":   s3Completer,
@@ -288,6 +389,10 @@ var completeCmds = map[string]complete.Predictor{
 	"/config/host/add":    nil,
 	"/config/host/list":   aliasCompleter,
 	"/config/host/remove": aliasCompleter,
+	"/config/host/update": aliasCompleter,
+	"/config/use":         aliasCompleter,
+	"/config/current":     nil,
+	"/config/backends":    nil,
 
 	"/update":  nil,
 	"/version": nil,
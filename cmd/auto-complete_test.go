@@ -0,0 +1,109 @@
+/*
+ * MinIO Client (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package cmd
+
+import (
+	"io/ioutil"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/minio/mc/pkg/probe"
+	"github.com/posener/complete"
+)
+
+func TestS3CompleteRemote(t *testing.T) {
+	dir, e := ioutil.TempDir("", "mc-complete-")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.RemoveAll(dir)
+	defer setMcConfigDir(mcCustomConfigDir)
+	setMcConfigDir(dir)
+	defer func(load func() (*configV9, *probe.Error)) { loadMcConfig = load }(loadMcConfig)
+	defer func() { cacheCfgV9 = nil }()
+	cacheCfgV9 = nil
+
+	handler := newFakeS3Server(map[string][]byte{
+		"bucket/photos/2020/a.jpg": []byte("a"),
+		"bucket/photos/2021/b.jpg": []byte("b"),
+		"bucket/readme.txt":        []byte("readme"),
+	})
+	server := httptest.NewServer(handler)
+	defer server.Close()
+	cfg := newConfigV9()
+	cfg.Hosts["myminio"] = testHost(server.URL)
+	if err := saveMcConfig(cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	// Keys are not listed unless remote completion is enabled.
+	if prediction := s3Completer.Predict(complete.Args{Last: "myminio/bucket/ph"}); len(prediction) != 0 {
+		t.Fatalf("Expected no remote completion, got %v", prediction)
+	}
+	if prediction := s3Completer.Predict(complete.Args{Last: "mym"}); !reflect.DeepEqual(prediction, []string{"myminio/"}) {
+		t.Fatalf("Expected the alias to be completed, got %v", prediction)
+	}
+
+	cfg.RemoteCompletion = true
+	if err := saveMcConfig(cfg); err != nil {
+		t.Fatal(err)
+	}
+	testCases := []struct {
+		arg      string
+		expected []string
+	}{
+		{"myminio/bucket/r", []string{"myminio/bucket/readme.txt"}},
+		// A single folder is listed as well.
+		{"myminio/bucket/ph", []string{"myminio/bucket/photos/", "myminio/bucket/photos/2020/", "myminio/bucket/photos/2021/"}},
+		{"myminio/bucket/photos/2021", []string{"myminio/bucket/photos/2021/", "myminio/bucket/photos/2021/b.jpg"}},
+		{"myminio/bucket/x", nil},
+	}
+	for i, testCase := range testCases {
+		prediction := s3Completer.Predict(complete.Args{Last: testCase.arg})
+		if !reflect.DeepEqual(prediction, testCase.expected) {
+			t.Errorf("Test %d: expected %v, got %v", i+1, testCase.expected, prediction)
+		}
+	}
+
+	// The next tabs are completed from the cache.
+	handler.mutex.Lock()
+	delete(handler.objects, "bucket/readme.txt")
+	handler.mutex.Unlock()
+	if prediction := s3Completer.Predict(complete.Args{Last: "myminio/bucket/r"}); !reflect.DeepEqual(prediction, []string{"myminio/bucket/readme.txt"}) {
+		t.Fatalf("Expected the cached listing, got %v", prediction)
+	}
+	// Until the cache expires.
+	defer func() { nowFunc = time.Now }()
+	expired := time.Now().Add(remoteCompletionCacheTTL + time.Second)
+	nowFunc = func() time.Time { return expired }
+	if prediction := s3Completer.Predict(complete.Args{Last: "myminio/bucket/r"}); len(prediction) != 0 {
+		t.Fatalf("Expected the listing to be refreshed, got %v", prediction)
+	}
+
+	// Failed listings are not cached.
+	if prediction := s3Completer.Predict(complete.Args{Last: "myminio/other/n"}); len(prediction) != 0 {
+		t.Fatalf("Expected no completion of a missing bucket, got %v", prediction)
+	}
+	handler.mutex.Lock()
+	handler.objects["other/notes.txt"] = []byte("notes")
+	handler.mutex.Unlock()
+	if prediction := s3Completer.Predict(complete.Args{Last: "myminio/other/n"}); !reflect.DeepEqual(prediction, []string{"myminio/other/notes.txt"}) {
+		t.Fatalf("Expected the bucket to be listed again, got %v", prediction)
+	}
+}
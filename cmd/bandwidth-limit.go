@@ -0,0 +1,129 @@
+/*
+ * MinIO Client (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"io"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/minio/mc/pkg/probe"
+)
+
+// Largest read accounted for at once. Parallel transfers drawing from
+// the same limiter take turns every this many bytes.
+const bandwidthChunkSize = 32 * humanize.KiByte
+
+// bandwidthLimiter is a token bucket of bytes shared by all transfers
+// of this process in one direction, their combined rate never exceeds
+// the rate of the limiter whatever the number of parallel transfers.
+type bandwidthLimiter struct {
+	mutex *sync.Mutex
+	// Bytes per second.
+	rate float64
+	// Bytes which may be read at once after a pause.
+	burst float64
+
+	tokens float64
+	last   time.Time
+}
+
+// newBandwidthLimiter - returns a limiter of rate bytes per second.
+func newBandwidthLimiter(rate int64) *bandwidthLimiter {
+	burst := math.Min(float64(rate), bandwidthChunkSize)
+	return &bandwidthLimiter{mutex: &sync.Mutex{}, rate: float64(rate), burst: burst, tokens: burst, last: time.Now()}
+}
+
+// Limiters of uploads and downloads, set via --limit-upload and
+// --limit-download, nil if unlimited.
+var (
+	globalUploadLimiter   *bandwidthLimiter
+	globalDownloadLimiter *bandwidthLimiter
+)
+
+// wait - takes n bytes out of the bucket, sleeping while the bytes
+// taken before, by any transfer, exceed the rate.
+func (l *bandwidthLimiter) wait(n int) {
+	l.mutex.Lock()
+	now := time.Now()
+	l.tokens = math.Min(l.tokens+now.Sub(l.last).Seconds()*l.rate, l.burst)
+	l.last = now
+	l.tokens -= float64(n)
+	debt := -l.tokens
+	l.mutex.Unlock()
+
+	if debt > 0 {
+		time.Sleep(time.Duration(debt / l.rate * float64(time.Second)))
+	}
+}
+
+// limitedReader reads at the rate of its limiter.
+type limitedReader struct {
+	io.ReadCloser
+	limiter *bandwidthLimiter
+}
+
+func (r limitedReader) Read(p []byte) (int, error) {
+	if len(p) > bandwidthChunkSize {
+		p = p[:bandwidthChunkSize]
+	}
+	n, e := r.ReadCloser.Read(p)
+	r.limiter.wait(n)
+	return n, e
+}
+
+// limitBandwidth - returns reader limited by the upload limiter if the
+// target of urls is object storage and by the download limiter if its
+// source is. Readers are returned unchanged without limits.
+func limitBandwidth(reader io.ReadCloser, urls URLs) io.ReadCloser {
+	if globalDownloadLimiter != nil && urls.SourceContent.URL.Type == objectStorage {
+		reader = limitedReader{ReadCloser: reader, limiter: globalDownloadLimiter}
+	}
+	if globalUploadLimiter != nil && urls.TargetContent.URL.Type == objectStorage {
+		reader = limitedReader{ReadCloser: reader, limiter: globalUploadLimiter}
+	}
+	return reader
+}
+
+// parseBandwidthLimit - parses a rate such as `10MiB` or `10MiB/s`.
+func parseBandwidthLimit(limit string) (*bandwidthLimiter, *probe.Error) {
+	rate, err := parseSize(strings.TrimSuffix(strings.TrimSpace(limit), "/s"))
+	if err != nil {
+		return nil, err.Trace(limit)
+	}
+	if rate == 0 {
+		return nil, errInvalidSize(limit, "a bandwidth limit cannot be zero")
+	}
+	return newBandwidthLimiter(rate), nil
+}
+
+// setGlobalBandwidthLimits validates the limits requested on the command
+// line and applies them to all transfers.
+func setGlobalBandwidthLimits(upload, download string) {
+	var err *probe.Error
+	if upload != "" {
+		globalUploadLimiter, err = parseBandwidthLimit(upload)
+		fatalIf(err, "Unable to set the upload bandwidth limit.")
+	}
+	if download != "" {
+		globalDownloadLimiter, err = parseBandwidthLimit(download)
+		fatalIf(err, "Unable to set the download bandwidth limit.")
+	}
+}
@@ -0,0 +1,93 @@
+/*
+ * MinIO Client (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dustin/go-humanize"
+)
+
+func TestParseBandwidthLimit(t *testing.T) {
+	testCases := []struct {
+		limit   string
+		rate    float64
+		success bool
+	}{
+		{"10MiB", 10 * humanize.MiByte, true},
+		{"10MiB/s", 10 * humanize.MiByte, true},
+		{"512KB", 512 * humanize.KByte, true},
+		{"0", 0, false},
+		{"-1MiB", 0, false},
+		{"fast", 0, false},
+	}
+	for i, testCase := range testCases {
+		limiter, err := parseBandwidthLimit(testCase.limit)
+		if testCase.success != (err == nil) {
+			t.Fatalf("Test %d: expected success %t for %q, got %v", i+1, testCase.success, testCase.limit, err)
+		}
+		if err == nil && limiter.rate != testCase.rate {
+			t.Errorf("Test %d: expected a rate of %f for %q, got %f", i+1, testCase.rate, testCase.limit, limiter.rate)
+		}
+	}
+}
+
+func TestBandwidthLimitShared(t *testing.T) {
+	const (
+		transfers = 4
+		size      = 256 * humanize.KiByte
+		rate      = humanize.MiByte
+	)
+	limiter := newBandwidthLimiter(rate)
+
+	start := time.Now()
+	finished := make([]time.Duration, transfers)
+	var wg sync.WaitGroup
+	for i := 0; i < transfers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			reader := limitedReader{ReadCloser: ioutil.NopCloser(bytes.NewReader(make([]byte, size))), limiter: limiter}
+			if _, e := io.Copy(ioutil.Discard, reader); e != nil {
+				t.Error(e)
+			}
+			finished[i] = time.Since(start)
+		}(i)
+	}
+	wg.Wait()
+	elapsed := time.Since(start)
+
+	// Each transfer alone would complete in 250ms, all of them share
+	// the rate once the burst is consumed.
+	if expected := time.Duration(float64(transfers*size-bandwidthChunkSize) / rate * float64(time.Second)); elapsed < expected*9/10 {
+		t.Fatalf("Expected the transfers to take at least %s, took %s", expected, elapsed)
+	}
+	if elapsed > 5*time.Second {
+		t.Fatalf("Expected the transfers to complete at the rate of the limit, took %s", elapsed)
+	}
+	// Transfers take turns, none completes long before the others.
+	for i, d := range finished {
+		if d < elapsed/2 {
+			t.Errorf("Transfer %d completed after %s out of %s", i+1, d, elapsed)
+		}
+	}
+}
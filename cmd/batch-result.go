@@ -0,0 +1,97 @@
+/*
+ * MinIO Client (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+
+	json "github.com/minio/mc/pkg/colorjson"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/minio/pkg/console"
+)
+
+// batchResult accumulates the outcome of the targets of a command
+// working on several targets, a failing target does not stop the
+// others but makes the command exit with globalErrorExitStatus.
+type batchResult struct {
+	total  int
+	failed int
+}
+
+// success - records a target which succeeded.
+func (r *batchResult) success() {
+	r.total++
+}
+
+// failure - records a target which failed, its error is expected
+// to be reported already.
+func (r *batchResult) failure() {
+	r.total++
+	r.failed++
+}
+
+// record - records a target by the error of its operation.
+func (r *batchResult) record(e error) {
+	if e != nil {
+		r.failure()
+		return
+	}
+	r.success()
+}
+
+// summary - reports how many targets failed when some of several
+// targets did.
+func (r batchResult) summary() {
+	if r.failed == 0 || r.total < 2 {
+		return
+	}
+	msg := batchResultMessage{Status: "error", Failed: r.failed, Total: r.total}
+	if globalJSON {
+		console.Println(msg.JSON())
+	} else {
+		console.Errorln(msg.String())
+	}
+}
+
+// exit - returns the exit status of the command, after its summary.
+func (r batchResult) exit() error {
+	if r.failed == 0 {
+		return nil
+	}
+	r.summary()
+	return exitStatus(globalErrorExitStatus)
+}
+
+// batchResultMessage container for the trailing summary of failed targets.
+type batchResultMessage struct {
+	Status string `json:"status"`
+	Failed int    `json:"failed"`
+	Total  int    `json:"total"`
+}
+
+// String message of failed targets.
+func (m batchResultMessage) String() string {
+	return fmt.Sprintf("%d of %d failed.", m.Failed, m.Total)
+}
+
+// JSON jsonified message of failed targets.
+func (m batchResultMessage) JSON() string {
+	batchJSONBytes, e := json.MarshalIndent(m, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(batchJSONBytes)
+}
@@ -0,0 +1,88 @@
+/*
+ * MinIO Client (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"io"
+
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/probe"
+)
+
+// Flags selecting a slice of the source object, shared by cp and cat.
+var byteRangeFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:  "offset",
+		Usage: "start reading the source at this byte offset, e.g. 10MiB",
+	},
+	cli.StringFlag{
+		Name:  "length",
+		Usage: "read only this many bytes of the source, e.g. 1MiB (default: until the end)",
+	},
+}
+
+// parseByteRange - parses the --offset and --length flag values.
+func parseByteRange(offsetStr, lengthStr string) (offset, length int64, err *probe.Error) {
+	parse := func(s string) (int64, *probe.Error) {
+		if s == "" {
+			return 0, nil
+		}
+		n, err := parseSize(s)
+		if err != nil {
+			return 0, err.Trace(s)
+		}
+		return n, nil
+	}
+	if offset, err = parse(offsetStr); err != nil {
+		return 0, 0, err
+	}
+	if length, err = parse(lengthStr); err != nil {
+		return 0, 0, err
+	}
+	return offset, length, nil
+}
+
+// isByteRange - returns true if a slice of the source is requested.
+func isByteRange(offset, length int64) bool {
+	return offset > 0 || length > 0
+}
+
+// rangeLength - returns the number of bytes of a source of the given
+// size selected by offset and length, zero length meaning until the end.
+func rangeLength(offset, length, size int64) (int64, *probe.Error) {
+	if offset < 0 || length < 0 || offset > size || (offset == size && size > 0) {
+		return 0, errInvalidRange(offset, length, size)
+	}
+	if length == 0 {
+		return size - offset, nil
+	}
+	if offset+length > size {
+		return 0, errInvalidRange(offset, length, size)
+	}
+	return length, nil
+}
+
+// limitedReadCloser reads at most N bytes and closes the underlying reader.
+type limitedReadCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// newLimitedReadCloser - returns a ReadCloser reading at most n bytes of rc.
+func newLimitedReadCloser(rc io.ReadCloser, n int64) io.ReadCloser {
+	return limitedReadCloser{Reader: io.LimitReader(rc, n), Closer: rc}
+}
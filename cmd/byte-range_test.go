@@ -0,0 +1,112 @@
+/*
+ * MinIO Client (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const rangeTestContent = "0123456789abcdefghij"
+
+func TestRangeLength(t *testing.T) {
+	testCases := []struct {
+		offset, length, size int64
+		expected             int64
+		success              bool
+	}{
+		{0, 0, 20, 20, true},
+		{5, 0, 20, 15, true},
+		{5, 10, 20, 10, true},
+		{0, 20, 20, 20, true},
+		{0, 0, 0, 0, true},
+		{19, 1, 20, 1, true},
+		{20, 0, 20, 0, false},
+		{15, 10, 20, 0, false},
+		{-1, 0, 20, 0, false},
+	}
+	for i, testCase := range testCases {
+		length, err := rangeLength(testCase.offset, testCase.length, testCase.size)
+		if testCase.success != (err == nil) {
+			t.Fatalf("Test %d: Expected success %v, got %v", i+1, testCase.success, err)
+		}
+		if length != testCase.expected {
+			t.Fatalf("Test %d: Expected length %d, got %d", i+1, testCase.expected, length)
+		}
+	}
+}
+
+func TestGetRange(t *testing.T) {
+	dir, e := ioutil.TempDir("", "mc-range-")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "object")
+	if e = ioutil.WriteFile(filename, []byte(rangeTestContent), 0600); e != nil {
+		t.Fatal(e)
+	}
+	fsClnt, err := fsNew(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := httptest.NewServer(newFakeS3Server(map[string][]byte{"bucket/object": []byte(rangeTestContent)}))
+	defer server.Close()
+	conf := newTestS3Config(server.URL + "/bucket/object")
+	s3Clnt, err := s3New(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		offset, length int64
+		expected       string
+	}{
+		{0, 0, rangeTestContent},
+		{5, 4, "5678"},
+		{10, 0, "abcdefghij"},
+		{0, 3, "012"},
+	}
+	for _, clnt := range []Client{fsClnt, s3Clnt} {
+		for i, testCase := range testCases {
+			reader, err := clnt.GetRange(testCase.offset, testCase.length, nil)
+			if err != nil {
+				t.Fatalf("Test %d: %s: %s", i+1, clnt.GetURL(), err)
+			}
+			data, e := ioutil.ReadAll(reader)
+			reader.Close()
+			if e != nil {
+				t.Fatal(e)
+			}
+			if !bytes.Equal(data, []byte(testCase.expected)) {
+				t.Fatalf("Test %d: %s: Expected %q, got %q", i+1, clnt.GetURL(), testCase.expected, data)
+			}
+		}
+	}
+
+	// A range starting past the end of the object is reported right away.
+	if _, err = s3Clnt.GetRange(25, 5, nil); err == nil {
+		t.Fatal("Expected out of range request to fail")
+	} else if _, ok := err.ToGoError().(invalidRangeErr); !ok {
+		t.Fatalf("Expected invalid range error, got %v", err)
+	}
+}
@@ -18,10 +18,12 @@ package cmd
 
 import (
 	"bytes"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"io"
 	"os"
+	"strconv"
 	"strings"
 	"syscall"
 	"unicode"
@@ -35,9 +37,21 @@ var (
 	// This is kept dummy for future purposes
 	// and also to add ioFlags and globalFlags
 	// in CLI registration.
-	catFlags = []cli.Flag{}
+	catFlags = append([]cli.Flag{
+		cli.BoolFlag{
+			Name:  "hex",
+			Usage: "display contents as a hexdump with offsets and an ASCII column, like 'hexdump -C'",
+		},
+		cli.Int64Flag{
+			Name:  "head",
+			Usage: "display only the first N lines of the hexdump, N*16 bytes",
+		},
+	}, byteRangeFlags...)
 )
 
+// Number of bytes displayed on each line of a hexdump.
+const hexDumpLineSize = 16
+
 // Display contents of a file.
 var catCmd = cli.Command{
 	Name:   "cat",
@@ -73,6 +87,12 @@ EXAMPLES:
   5. Display the content of encrypted object. In case the encryption key contains non-printable character like tab, pass the
      base64 encoded string as key.
      {{.Prompt}} {{.HelpName}} --encrypt-key "play/my-bucket/=MzJieXRlc2xvbmdzZWNyZXRrZQltdXN0YmVnaXZlbjE="  play/my-bucket/my-object
+
+  6. Display 512 bytes starting at offset 1KiB of an object.
+     {{.Prompt}} {{.HelpName}} --offset 1KiB --length 512 play/my-bucket/my-object | xxd
+
+  7. Inspect the first 64 bytes of a binary object as a hexdump.
+     {{.Prompt}} {{.HelpName}} --hex --head 4 play/my-bucket/my-image.png
 `,
 }
 
@@ -136,10 +156,26 @@ func checkCatSyntax(ctx *cli.Context) {
 			fatalIf(probe.NewError(errors.New("")), fmt.Sprintf("Unknown flag `%s` passed.", arg))
 		}
 	}
+	if ctx.Int64("head") < 0 {
+		fatalIf(errInvalidArgument().Trace(args...), "--head cannot be negative.")
+	}
+	if ctx.IsSet("head") && !ctx.Bool("hex") {
+		fatalIf(errInvalidArgument().Trace(args...), "--head can only be used with --hex.")
+	}
+	offset, length, err := parseByteRange(ctx.String("offset"), ctx.String("length"))
+	fatalIf(err, "Unable to parse --offset and --length.")
+	if isByteRange(offset, length) {
+		for _, arg := range args {
+			if arg == "-" {
+				fatalIf(errInvalidArgument().Trace(args...), "--offset and --length cannot be used with standard input.")
+			}
+		}
+	}
 }
 
-// catURL displays contents of a URL to stdout.
-func catURL(sourceURL string, encKeyDB map[string][]prefixSSEPair) *probe.Error {
+// catURL displays contents of a URL to stdout, as a hexdump of at
+// most headLines lines if hexDump is set.
+func catURL(sourceURL string, offset, length int64, hexDump bool, headLines int64, encKeyDB map[string][]prefixSSEPair) *probe.Error {
 	var reader io.ReadCloser
 	size := int64(-1)
 	switch sourceURL {
@@ -156,20 +192,31 @@ func catURL(sourceURL string, encKeyDB map[string][]prefixSSEPair) *probe.Error
 		if err == nil && client.GetURL().Type == objectStorage {
 			size = content.Size
 		}
-		if reader, err = getSourceStreamFromURL(sourceURL, encKeyDB); err != nil {
+		if isByteRange(offset, length) {
+			if err != nil {
+				return err.Trace(sourceURL)
+			}
+			if length, err = rangeLength(offset, length, content.Size); err != nil {
+				return err.Trace(sourceURL)
+			}
+			if size >= 0 {
+				size = length
+			}
+		}
+		if reader, err = getSourceStreamRangeFromURL(sourceURL, offset, length, encKeyDB); err != nil {
 			return err.Trace(sourceURL)
 		}
 		defer reader.Close()
 	}
+	if hexDump {
+		return catHexOut(os.Stdout, reader, offset, size, headLines).Trace(sourceURL)
+	}
 	return catOut(reader, size).Trace(sourceURL)
 }
 
 // catOut reads from reader stream and writes to stdout. Also check the length of the
 // read bytes against size parameter (if not -1) and return the appropriate error
 func catOut(r io.Reader, size int64) *probe.Error {
-	var n int64
-	var e error
-
 	var stdout io.Writer
 
 	// In case of a user showing the object content in a terminal,
@@ -181,32 +228,98 @@ func catOut(r io.Reader, size int64) *probe.Error {
 		stdout = os.Stdout
 	}
 
+	_, err := copyOut(stdout, r, size)
+	return err
+}
+
+// offsetDumpWriter adds offset to the offsets starting the lines of a
+// hexdump written to it.
+type offsetDumpWriter struct {
+	w      io.Writer
+	offset int64
+	line   []byte
+}
+
+func (o *offsetDumpWriter) Write(p []byte) (int, error) {
+	for _, b := range p {
+		o.line = append(o.line, b)
+		if b != '\n' {
+			continue
+		}
+		line := o.line
+		if i := bytes.IndexByte(line, ' '); i > 0 {
+			if n, e := strconv.ParseInt(string(line[:i]), 16, 64); e == nil {
+				line = append([]byte(fmt.Sprintf("%08x", o.offset+n)), line[i:]...)
+			}
+		}
+		if _, e := o.w.Write(line); e != nil {
+			return 0, e
+		}
+		o.line = o.line[:0]
+	}
+	return len(p), nil
+}
+
+// catHexOut streams a hexdump of the reader to w in the format of
+// `hexdump -C` without collapsing repeated lines, the offsets starting
+// at offset. Only the first headLines lines are displayed if positive,
+// reading no further.
+func catHexOut(w io.Writer, r io.Reader, offset, size, headLines int64) *probe.Error {
+	if limit := headLines * hexDumpLineSize; limit > 0 {
+		r = io.LimitReader(r, limit)
+		if size > limit {
+			size = limit
+		}
+	}
+	dumper := hex.Dumper(&offsetDumpWriter{w: w, offset: offset})
+	n, err := copyOut(dumper, r, size)
+	if err != nil {
+		return err
+	}
+	if e := dumper.Close(); e != nil {
+		return probe.NewError(e)
+	}
+	// Like hexdump, end with the offset following the last byte.
+	if n > 0 {
+		if _, e := fmt.Fprintf(w, "%08x\n", offset+n); e != nil {
+			return probe.NewError(e)
+		}
+	}
+	return nil
+}
+
+// copyOut copies the reader stream to stdout, checking the length of the
+// copied bytes against size (if not -1).
+func copyOut(stdout io.Writer, r io.Reader, size int64) (int64, *probe.Error) {
+	var n int64
+	var e error
+
 	// Read till EOF.
 	if n, e = io.Copy(stdout, r); e != nil {
 		switch e := e.(type) {
 		case *os.PathError:
 			if e.Err == syscall.EPIPE {
 				// stdout closed by the user. Gracefully exit.
-				return nil
+				return n, nil
 			}
-			return probe.NewError(e)
+			return n, probe.NewError(e)
 		default:
-			return probe.NewError(e)
+			return n, probe.NewError(e)
 		}
 	}
 	if size != -1 && n < size {
-		return probe.NewError(UnexpectedEOF{
+		return n, probe.NewError(UnexpectedEOF{
 			TotalSize:    size,
 			TotalWritten: n,
 		})
 	}
 	if size != -1 && n > size {
-		return probe.NewError(UnexpectedEOF{
+		return n, probe.NewError(UnexpectedEOF{
 			TotalSize:    size,
 			TotalWritten: n,
 		})
 	}
-	return nil
+	return n, nil
 }
 
 // mainCat is the main entry point for cat command.
@@ -224,8 +337,14 @@ func mainCat(ctx *cli.Context) error {
 		stdinMode = true
 	}
 
+	hexDump, headLines := ctx.Bool("hex"), ctx.Int64("head")
+
 	// handle std input data.
 	if stdinMode {
+		if hexDump {
+			fatalIf(catHexOut(os.Stdout, os.Stdin, 0, -1, headLines).Trace(), "Unable to read from standard input.")
+			return nil
+		}
 		fatalIf(catOut(os.Stdin, -1).Trace(), "Unable to read from standard input.")
 		return nil
 	}
@@ -242,10 +361,19 @@ func mainCat(ctx *cli.Context) error {
 		}
 	}
 
+	// Range flags are already validated in checkCatSyntax.
+	offset, length, _ := parseByteRange(ctx.String("offset"), ctx.String("length"))
+
 	// Convert arguments to URLs: expand alias, fix format.
+	var result batchResult
 	for _, url := range args {
-		fatalIf(catURL(url, encKeyDB).Trace(url), "Unable to read from `"+url+"`.")
+		if err := catURL(url, offset, length, hexDump, headLines, encKeyDB); err != nil {
+			errorIf(err.Trace(url), "Unable to read from `"+url+"`.")
+			result.failure()
+			continue
+		}
+		result.success()
 	}
 
-	return nil
+	return result.exit()
 }
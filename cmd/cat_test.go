@@ -62,3 +62,37 @@ func TestPrettyStdout(t *testing.T) {
 		}
 	}
 }
+
+func TestCatHexOut(t *testing.T) {
+	data := []byte("MinIO \x00\x01\x02\x7f\xff objects\n")
+	testCases := []struct {
+		offset    int64
+		headLines int64
+		hexDump   string
+	}{
+		{0, 0, "00000000  4d 69 6e 49 4f 20 00 01  02 7f ff 20 6f 62 6a 65  |MinIO ..... obje|\n" +
+			"00000010  63 74 73 0a                                       |cts.|\n" +
+			"00000014\n"},
+		{0, 1, "00000000  4d 69 6e 49 4f 20 00 01  02 7f ff 20 6f 62 6a 65  |MinIO ..... obje|\n" +
+			"00000010\n"},
+		{0, 5, "00000000  4d 69 6e 49 4f 20 00 01  02 7f ff 20 6f 62 6a 65  |MinIO ..... obje|\n" +
+			"00000010  63 74 73 0a                                       |cts.|\n" +
+			"00000014\n"},
+		// Ranges read with --offset are dumped at their offset.
+		{0x1ff0, 0, "00001ff0  4d 69 6e 49 4f 20 00 01  02 7f ff 20 6f 62 6a 65  |MinIO ..... obje|\n" +
+			"00002000  63 74 73 0a                                       |cts.|\n" +
+			"00002004\n"},
+		{0x100000000, 1, "100000000  4d 69 6e 49 4f 20 00 01  02 7f ff 20 6f 62 6a 65  |MinIO ..... obje|\n" +
+			"100000010\n"},
+	}
+
+	for i, testCase := range testCases {
+		var output bytes.Buffer
+		if err := catHexOut(&output, bytes.NewReader(data), testCase.offset, int64(len(data)), testCase.headLines); err != nil {
+			t.Fatalf("Test %d: %s", i+1, err)
+		}
+		if output.String() != testCase.hexDump {
+			t.Fatalf("Test %d: expected hexdump\n%s\nfound\n%s", i+1, testCase.hexDump, output.String())
+		}
+	}
+}
@@ -17,6 +17,7 @@
 package cmd
 
 import (
+	"crypto/tls"
 	"crypto/x509"
 	"io/ioutil"
 	"os"
@@ -133,3 +134,20 @@ func loadRootCAs() {
 		globalRootCAs.AppendCertsFromPEM(caCert)
 	}
 }
+
+// loadClientCertificate loads the certificate presented to servers asking
+// for one, mutual TLS. The certificate and its private key are PEM files,
+// both or none must be given.
+func loadClientCertificate(certFile, keyFile string) (*tls.Certificate, *probe.Error) {
+	if certFile == "" && keyFile == "" {
+		return nil, nil
+	}
+	if certFile == "" || keyFile == "" {
+		return nil, errInvalidClientCert(certFile, keyFile, "a client certificate requires both a certificate and a private key")
+	}
+	cert, e := tls.LoadX509KeyPair(certFile, keyFile)
+	if e != nil {
+		return nil, errInvalidClientCert(certFile, keyFile, e.Error())
+	}
+	return &cert, nil
+}
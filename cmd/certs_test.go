@@ -0,0 +1,150 @@
+/*
+ * MinIO Client (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"log"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeClientCertificate - writes a self-signed client certificate and
+// its key as PEM files into dir.
+func writeClientCertificate(t *testing.T, dir, name string) (certFile, keyFile string, cert *x509.Certificate) {
+	key, e := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if e != nil {
+		t.Fatal(e)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		IsCA:         true,
+
+		BasicConstraintsValid: true,
+	}
+	der, e := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if e != nil {
+		t.Fatal(e)
+	}
+	if cert, e = x509.ParseCertificate(der); e != nil {
+		t.Fatal(e)
+	}
+	keyDER, e := x509.MarshalECPrivateKey(key)
+	if e != nil {
+		t.Fatal(e)
+	}
+	certFile, keyFile = filepath.Join(dir, name+".crt"), filepath.Join(dir, name+".key")
+	if e = ioutil.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600); e != nil {
+		t.Fatal(e)
+	}
+	if e = ioutil.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600); e != nil {
+		t.Fatal(e)
+	}
+	return certFile, keyFile, cert
+}
+
+func TestLoadClientCertificate(t *testing.T) {
+	dir, e := ioutil.TempDir("", "mc-client-cert-")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.RemoveAll(dir)
+	certFile, keyFile, _ := writeClientCertificate(t, dir, "client")
+	_, otherKeyFile, _ := writeClientCertificate(t, dir, "other")
+
+	testCases := []struct {
+		certFile, keyFile string
+		loaded            bool
+		success           bool
+	}{
+		{"", "", false, true},
+		{certFile, keyFile, true, true},
+		{certFile, "", false, false},
+		{"", keyFile, false, false},
+		// The key of another certificate.
+		{certFile, otherKeyFile, false, false},
+		{filepath.Join(dir, "missing.crt"), keyFile, false, false},
+	}
+	for i, testCase := range testCases {
+		cert, err := loadClientCertificate(testCase.certFile, testCase.keyFile)
+		if testCase.success != (err == nil) {
+			t.Fatalf("Test %d: expected success %t, got %v", i+1, testCase.success, err)
+		}
+		if testCase.loaded != (cert != nil) {
+			t.Fatalf("Test %d: expected a certificate %t, got %v", i+1, testCase.loaded, cert)
+		}
+	}
+}
+
+func TestClientCertificate(t *testing.T) {
+	dir, e := ioutil.TempDir("", "mc-client-cert-")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.RemoveAll(dir)
+	certFile, keyFile, cert := writeClientCertificate(t, dir, "client")
+
+	// The server accepts connections presenting the client certificate only.
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(cert)
+	server := httptest.NewUnstartedServer(http.HandlerFunc(pingHandler))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs}
+	server.Config.ErrorLog = log.New(ioutil.Discard, "", 0)
+	server.StartTLS()
+	defer server.Close()
+
+	defer func(rootCAs *x509.CertPool) { globalRootCAs = rootCAs }(globalRootCAs)
+	globalRootCAs = x509.NewCertPool()
+	globalRootCAs.AddCert(server.Certificate())
+
+	mtls := testHost(server.URL)
+	mtls.ClientCert, mtls.ClientKey = certFile, keyFile
+	// The same server without the certificate, which is not presented
+	// to hosts it is not configured for.
+	defer withTestConfig(map[string]hostConfigV9{"mtls": mtls, "plain": testHost(server.URL)})()
+
+	if _, err := pingAlias("mtls"); err != nil {
+		t.Fatalf("Expected the host with the client certificate to be reachable: %v", err)
+	}
+	if _, err := pingAlias("plain"); err == nil {
+		t.Fatal("Expected the host without the client certificate to be refused")
+	}
+
+	// Certificates given by flags are presented to every host.
+	defer func() { globalClientCert, globalClientKey = "", "" }()
+	globalClientCert, globalClientKey = certFile, keyFile
+	if _, err := pingAlias("plain"); err != nil {
+		t.Fatalf("Expected --client-cert to be presented: %v", err)
+	}
+}
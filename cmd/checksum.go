@@ -0,0 +1,117 @@
+/*
+ * MinIO Client (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"hash"
+	"hash/crc32"
+	"strconv"
+	"strings"
+
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/probe"
+)
+
+// checksumAlgorithm is a checksum S3 validates uploads against.
+type checksumAlgorithm string
+
+// Supported checksum algorithms.
+const (
+	checksumNone   checksumAlgorithm = ""
+	checksumMD5    checksumAlgorithm = "MD5"
+	checksumCRC32  checksumAlgorithm = "CRC32"
+	checksumCRC32C checksumAlgorithm = "CRC32C"
+	checksumSHA1   checksumAlgorithm = "SHA1"
+	checksumSHA256 checksumAlgorithm = "SHA256"
+)
+
+// Flag shared by cp and pipe.
+var checksumFlag = cli.StringFlag{
+	Name:  "checksum-algorithm",
+	Usage: "compute a checksum while uploading for the server to verify, one of MD5, CRC32, CRC32C, SHA1, SHA256",
+}
+
+// parseChecksumAlgorithm - parses the value of --checksum-algorithm.
+func parseChecksumAlgorithm(algorithm string) (checksumAlgorithm, *probe.Error) {
+	switch a := checksumAlgorithm(strings.ToUpper(algorithm)); a {
+	case checksumNone, checksumMD5, checksumCRC32, checksumCRC32C, checksumSHA1, checksumSHA256:
+		return a, nil
+	}
+	return checksumNone, errInvalidArgument().Trace(algorithm)
+}
+
+// newHash - returns a new hash computing the checksum.
+func (a checksumAlgorithm) newHash() hash.Hash {
+	switch a {
+	case checksumMD5:
+		return md5.New()
+	case checksumCRC32:
+		return crc32.NewIEEE()
+	case checksumCRC32C:
+		return crc32.New(crc32.MakeTable(crc32.Castagnoli))
+	case checksumSHA1:
+		return sha1.New()
+	}
+	return sha256.New()
+}
+
+// header - returns the request header carrying the checksum, MD5 uses
+// the standard Content-Md5 header.
+func (a checksumAlgorithm) header() string {
+	if a == checksumMD5 {
+		return "Content-Md5"
+	}
+	return "X-Amz-Checksum-" + strings.Title(strings.ToLower(string(a)))
+}
+
+// encodeChecksum - encodes a raw checksum the way S3 expects it.
+func encodeChecksum(sum []byte) string {
+	return base64.StdEncoding.EncodeToString(sum)
+}
+
+// compositeChecksum - returns the checksum of a multipart object, the
+// checksum of the concatenated part checksums followed by the part count.
+func (a checksumAlgorithm) compositeChecksum(partSums [][]byte) string {
+	h := a.newHash()
+	for _, sum := range partSums {
+		h.Write(sum)
+	}
+	return encodeChecksum(h.Sum(nil)) + "-" + strconv.Itoa(len(partSums))
+}
+
+// compositeETag - returns the ETag S3 computes for a multipart object,
+// the hex MD5 of the concatenated part MD5s followed by the part count.
+func compositeETag(partMD5s [][]byte) string {
+	h := md5.New()
+	for _, sum := range partMD5s {
+		h.Write(sum)
+	}
+	return hex.EncodeToString(h.Sum(nil)) + "-" + strconv.Itoa(len(partMD5s))
+}
+
+// setGlobalChecksumAlgorithm validates the checksum algorithm requested
+// on the command line and makes it the default for all new clients.
+func setGlobalChecksumAlgorithm(algorithm string) {
+	a, err := parseChecksumAlgorithm(algorithm)
+	fatalIf(err, "Invalid checksum algorithm. Valid options are `[MD5, CRC32, CRC32C, SHA1, SHA256]`.")
+	globalChecksumAlgorithm = a
+}
@@ -0,0 +1,333 @@
+/*
+ * MinIO Client (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/xml"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// checksumRequest is a request recorded by checksumHandler.
+type checksumRequest struct {
+	method   string
+	query    string
+	checksum string
+	body     string
+	// x-amz-* headers sent without being signed.
+	unsigned string
+}
+
+// checksumHandler records uploads and answers multipart requests,
+// reporting compositeChecksum on completion. The ETag of uploaded objects
+// is etag, "etag" if empty. The first failures uploads of parts fail with
+// InternalError and are not recorded.
+type checksumHandler struct {
+	mutex             *sync.Mutex
+	header            string
+	compositeChecksum string
+	etag              string
+	failures          *int
+	requests          *[]checksumRequest
+}
+
+func (h checksumHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	if _, ok := query["location"]; ok {
+		w.Write([]byte(`<LocationConstraint xmlns="http://s3.amazonaws.com/doc/2006-03-01/"></LocationConstraint>`))
+		return
+	}
+	etag := h.etag
+	if etag == "" {
+		etag = "etag"
+	}
+	body, _ := ioutil.ReadAll(r.Body)
+	h.mutex.Lock()
+	if h.failures != nil && *h.failures > 0 && r.Method == http.MethodPut {
+		*h.failures--
+		h.mutex.Unlock()
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte("<Error><Code>InternalError</Code><Message>We encountered an internal error, please try again.</Message></Error>"))
+		return
+	}
+	h.mutex.Unlock()
+	req := checksumRequest{method: r.Method, checksum: r.Header.Get(h.header), body: string(body), unsigned: unsignedHeaders(r)}
+	switch {
+	case query.Get("partNumber") != "":
+		req.query = "part"
+		w.Header().Set("ETag", `"etag`+query.Get("partNumber")+`"`)
+	case query.Get("uploadId") != "":
+		req.query = "complete"
+		w.Write([]byte("<CompleteMultipartUploadResult><Bucket>bucket</Bucket><Key>object</Key><ETag>&quot;" + etag + "&quot;</ETag><ChecksumSHA256>" + h.compositeChecksum + "</ChecksumSHA256></CompleteMultipartUploadResult>"))
+	case func() bool { _, ok := query["uploads"]; return ok }():
+		req.query = "uploads"
+		req.checksum = r.Header.Get("X-Amz-Checksum-Algorithm")
+		w.Write([]byte("<InitiateMultipartUploadResult><UploadId>upload1</UploadId></InitiateMultipartUploadResult>"))
+	default:
+		w.Header().Set("ETag", `"`+etag+`"`)
+	}
+	h.mutex.Lock()
+	*h.requests = append(*h.requests, req)
+	h.mutex.Unlock()
+}
+
+// unsignedHeaders - returns the x-amz-* headers of r missing from the
+// signed headers of its Authorization header, or the signature of its
+// query if it is presigned.
+func unsignedHeaders(r *http.Request) string {
+	if r.URL.Query().Get("X-Amz-Signature") != "" {
+		return "X-Amz-Signature"
+	}
+	signed := map[string]bool{}
+	for _, field := range strings.Split(r.Header.Get("Authorization"), ",") {
+		if field = strings.TrimSpace(field); strings.HasPrefix(field, "SignedHeaders=") {
+			for _, header := range strings.Split(strings.TrimPrefix(field, "SignedHeaders="), ";") {
+				signed[header] = true
+			}
+		}
+	}
+	var unsigned []string
+	for header := range r.Header {
+		if header = strings.ToLower(header); strings.HasPrefix(header, "x-amz-") && !signed[header] {
+			unsigned = append(unsigned, header)
+		}
+	}
+	sort.Strings(unsigned)
+	return strings.Join(unsigned, " ")
+}
+
+// newChecksumClient - returns a client uploading with the given checksum.
+func newChecksumClient(t *testing.T, handler checksumHandler, algorithm checksumAlgorithm, partSize uint64) (Client, func()) {
+	server := httptest.NewServer(handler)
+	conf := newTestS3Config(server.URL + "/bucket/object")
+	conf.Checksum = algorithm
+	conf.PartSize = partSize
+	clnt, err := s3New(conf)
+	if err != nil {
+		server.Close()
+		t.Fatal(err)
+	}
+	return clnt, server.Close
+}
+
+func TestPutChecksum(t *testing.T) {
+	testCases := []struct {
+		algorithm checksumAlgorithm
+		header    string
+		checksum  string
+	}{
+		{checksumMD5, "Content-Md5", "XrY7u+Ae7tCTyyK7j1rNww=="},
+		{checksumCRC32, "X-Amz-Checksum-Crc32", "DUoRhQ=="},
+		{checksumCRC32C, "X-Amz-Checksum-Crc32c", "yZRlqg=="},
+		{checksumSHA1, "X-Amz-Checksum-Sha1", "Kq5sNclPz7QV2+lfQIuc6R7oRu0="},
+		{checksumSHA256, "X-Amz-Checksum-Sha256", "uU0nuZNNPgilLlLX2n2r+sSE7+N6U4DukIj3rOLvzek="},
+	}
+	for i, testCase := range testCases {
+		if header := testCase.algorithm.header(); header != testCase.header {
+			t.Fatalf("Test %d: Expected header %s, got %s", i+1, testCase.header, header)
+		}
+		var requests []checksumRequest
+		handler := checksumHandler{mutex: &sync.Mutex{}, header: testCase.header, requests: &requests}
+		clnt, closeServer := newChecksumClient(t, handler, testCase.algorithm, 0)
+		// Unknown size as when streaming from stdin.
+		n, err := clnt.Put(context.Background(), strings.NewReader("hello world"), -1, map[string]string{}, nil, nil)
+		closeServer()
+		if err != nil {
+			t.Fatalf("Test %d: %s", i+1, err)
+		}
+		if n != 11 || len(requests) != 1 {
+			t.Fatalf("Test %d: Expected a single upload of 11 bytes, got %d bytes in %d requests", i+1, n, len(requests))
+		}
+		if requests[0].checksum != testCase.checksum || requests[0].body != "hello world" {
+			t.Fatalf("Test %d: Expected checksum %s, got %s", i+1, testCase.checksum, requests[0].checksum)
+		}
+	}
+}
+
+func TestPutChecksumMultipart(t *testing.T) {
+	var requests []checksumRequest
+	handler := checksumHandler{
+		mutex:             &sync.Mutex{},
+		header:            "X-Amz-Checksum-Sha256",
+		compositeChecksum: "J+iaQZsQ9GMOk85VB3k7HBTmmqC78J86d3OixvpestM=-3",
+		requests:          &requests,
+	}
+	clnt, closeServer := newChecksumClient(t, handler, checksumSHA256, 4)
+	defer closeServer()
+
+	n, err := clnt.Put(context.Background(), strings.NewReader("hello world"), -1, map[string]string{}, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 11 {
+		t.Fatalf("Expected 11 bytes, got %d", n)
+	}
+
+	expected := []checksumRequest{
+		{method: http.MethodPost, query: "uploads", checksum: "SHA256"},
+		{method: http.MethodPut, query: "part", checksum: "Dr3DMXt1g59kM4fXg1Na3DYMoB8zx198HnNzrc1nXAs=", body: "hell"},
+		{method: http.MethodPut, query: "part", checksum: "ivjmYqOyUe8C0u2FdFU1CGr3eqGsY6T1+AD1Acjl0JM=", body: "o wo"},
+		{method: http.MethodPut, query: "part", checksum: "hcdtyuIpnSI1x5P+NCW9iDdvXEchz93ByanEUsIMXTM=", body: "rld"},
+	}
+	if len(requests) != len(expected)+1 {
+		t.Fatalf("Expected %d requests, got %d", len(expected)+1, len(requests))
+	}
+	for i, req := range expected {
+		if requests[i] != req {
+			t.Fatalf("Request %d: Expected %+v, got %+v", i+1, req, requests[i])
+		}
+	}
+
+	var complete struct {
+		Parts []checksumPart `xml:"Part"`
+	}
+	if e := xml.Unmarshal([]byte(requests[len(expected)].body), &complete); e != nil {
+		t.Fatal(e)
+	}
+	if len(complete.Parts) != 3 || complete.Parts[2].ETag != `"etag3"` || complete.Parts[2].ChecksumSHA256 != expected[3].checksum {
+		t.Fatalf("Unexpected complete request %s", requests[len(expected)].body)
+	}
+
+	// A composite checksum not matching the parts fails the upload.
+	requests = nil
+	handler.compositeChecksum = "uU0nuZNNPgilLlLX2n2r+sSE7+N6U4DukIj3rOLvzek=-3"
+	clnt, closeServer = newChecksumClient(t, handler, checksumSHA256, 4)
+	defer closeServer()
+	if _, err = clnt.Put(context.Background(), strings.NewReader("hello world"), 11, map[string]string{}, nil, nil); err == nil {
+		t.Fatal("Expected a checksum mismatch to fail the upload")
+	}
+}
+
+// Tests that the requests of checksummed uploads are signed over all the
+// x-amz-* headers they send.
+func TestPutChecksumSignedHeaders(t *testing.T) {
+	var requests []checksumRequest
+	handler := checksumHandler{
+		mutex:             &sync.Mutex{},
+		header:            "X-Amz-Checksum-Sha256",
+		compositeChecksum: "J+iaQZsQ9GMOk85VB3k7HBTmmqC78J86d3OixvpestM=-3",
+		requests:          &requests,
+	}
+	for _, partSize := range []uint64{0, 4} {
+		requests = nil
+		clnt, closeServer := newChecksumClient(t, handler, checksumSHA256, partSize)
+		metadata := map[string]string{"X-Amz-Meta-Color": "blue", "X-Amz-Storage-Class": "REDUCED_REDUNDANCY"}
+		_, err := clnt.Put(context.Background(), strings.NewReader("hello world"), -1, metadata, nil, nil)
+		closeServer()
+		if err != nil {
+			t.Fatalf("Part size %d: %s", partSize, err)
+		}
+		if len(requests) == 0 {
+			t.Fatalf("Part size %d: Expected the upload to send requests", partSize)
+		}
+		for i, req := range requests {
+			if req.unsigned != "" {
+				t.Fatalf("Part size %d: Request %d sent unsigned %s", partSize, i+1, req.unsigned)
+			}
+		}
+	}
+}
+
+// Tests that parts failing on the server are sent again, reporting their
+// progress once.
+func TestPutChecksumPartRetry(t *testing.T) {
+	var requests []checksumRequest
+	failures := 2
+	handler := checksumHandler{
+		mutex:    &sync.Mutex{},
+		header:   "X-Amz-Checksum-Sha256",
+		failures: &failures,
+		requests: &requests,
+	}
+	clnt, closeServer := newChecksumClient(t, handler, checksumSHA256, 4)
+	defer closeServer()
+
+	progress := &copyProgress{}
+	n, err := clnt.Put(context.Background(), strings.NewReader("hello world"), 11, map[string]string{}, progress, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 11 || progress.copied != 11 {
+		t.Fatalf("Expected 11 bytes uploaded and reported, got %d and %d", n, progress.copied)
+	}
+	var parts []string
+	for _, req := range requests {
+		if req.query == "part" {
+			parts = append(parts, req.body)
+		}
+	}
+	if strings.Join(parts, ",") != "hell,o wo,rld" {
+		t.Fatalf("Expected every part to be uploaded once, got %v", parts)
+	}
+
+	// Parts still failing after the last attempt fail the upload.
+	requests = nil
+	failures = putPartAttempts
+	if _, err = clnt.Put(context.Background(), strings.NewReader("hello world"), 11, map[string]string{}, nil, nil); err == nil {
+		t.Fatal("Expected the upload to fail")
+	}
+}
+
+func TestPutVerify(t *testing.T) {
+	// ETag of a multipart object whose parts are "hell", "o wo" and "rld".
+	sums := make([][]byte, 0, 3)
+	for _, part := range []string{"hell", "o wo", "rld"} {
+		sum := md5.Sum([]byte(part))
+		sums = append(sums, sum[:])
+	}
+	if etag := compositeETag(sums); etag != "177e85e8bb233bd57a6aabda201a0c2c-3" {
+		t.Fatalf("Expected composite ETag 177e85e8bb233bd57a6aabda201a0c2c-3, got %s", etag)
+	}
+
+	testCases := []struct {
+		partSize uint64
+		etag     string
+		success  bool
+	}{
+		{0, "5eb63bbbe01eeed093cb22bb8f5acdc3", true},
+		{0, "etag", false},
+		{4, "177e85e8bb233bd57a6aabda201a0c2c-3", true},
+		{4, "5eb63bbbe01eeed093cb22bb8f5acdc3-3", false},
+	}
+	for i, testCase := range testCases {
+		var requests []checksumRequest
+		handler := checksumHandler{mutex: &sync.Mutex{}, etag: testCase.etag, requests: &requests}
+		server := httptest.NewServer(handler)
+		conf := newTestS3Config(server.URL + "/bucket/object")
+		conf.PartSize = testCase.partSize
+		conf.Verify = true
+		clnt, err := s3New(conf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = clnt.Put(context.Background(), strings.NewReader("hello world"), 11, map[string]string{}, nil, nil)
+		server.Close()
+		if testCase.success && err != nil {
+			t.Fatalf("Test %d: Expected the ETag %q to be verified, got %s", i+1, testCase.etag, err)
+		}
+		if !testCase.success && err == nil {
+			t.Fatalf("Test %d: Expected the ETag %q to fail the upload", i+1, testCase.etag)
+		}
+	}
+}
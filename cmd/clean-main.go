@@ -0,0 +1,182 @@
+/*
+ * MinIO Client (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/mattn/go-isatty"
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/minio/pkg/console"
+)
+
+var (
+	cleanFlags = []cli.Flag{
+		cli.StringFlag{
+			Name:  "older-than",
+			Usage: "abort uploads initiated longer than L days, e.g. 7d10h31s",
+		},
+		cli.BoolFlag{
+			Name:  "force",
+			Usage: "abort uploads without asking for confirmation",
+		},
+	}
+)
+
+// abort incomplete multipart uploads.
+var cleanCmd = cli.Command{
+	Name:   "clean",
+	Usage:  "abort incomplete multipart uploads",
+	Action: mainClean,
+	Before: setGlobalsFromContext,
+	Flags:  append(cleanFlags, globalFlags...),
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [FLAGS] TARGET [TARGET...]
+{{if .VisibleFlags}}
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}{{end}}
+EXAMPLES:
+  1. Abort all incomplete uploads in bucket 'jazz-songs' initiated more than a week ago.
+     {{.Prompt}} {{.HelpName}} --older-than 7d s3/jazz-songs
+
+  2. Abort all incomplete uploads under prefix 'louis/' without asking for confirmation.
+     {{.Prompt}} {{.HelpName}} --force s3/jazz-songs/louis/
+`,
+}
+
+// cleanMessage is container for aborted multipart uploads.
+type cleanMessage struct {
+	Status  string `json:"status"`
+	Target  string `json:"target"`
+	Found   int    `json:"found"`
+	Aborted int    `json:"aborted"`
+}
+
+// String colorized clean message.
+func (c cleanMessage) String() string {
+	return console.Colorize("Clean", fmt.Sprintf("Aborted %d of %d incomplete uploads in `%s`.", c.Aborted, c.Found, c.Target))
+}
+
+// JSON jsonified clean message.
+func (c cleanMessage) JSON() string {
+	c.Status = "success"
+	cleanJSONBytes, e := json.Marshal(c)
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(cleanJSONBytes)
+}
+
+// Validate command line arguments.
+func checkCleanSyntax(ctx *cli.Context) {
+	if !ctx.Args().Present() {
+		exitCode := 1
+		cli.ShowCommandHelpAndExit(ctx, "clean", exitCode)
+	}
+	if !ctx.Bool("force") && !isatty.IsTerminal(os.Stdin.Fd()) {
+		fatalIf(errDummy().Trace(),
+			"Aborting uploads requires --force flag when not running in a terminal. This operation is *IRREVERSIBLE*.")
+	}
+}
+
+// staleUploads - returns the uploads initiated before olderThan, all of them if olderThan is empty.
+func staleUploads(clnt Client, olderThan string) ([]multipartUpload, *probe.Error) {
+	uploads, err := clnt.ListMultipartUploads()
+	if err != nil {
+		return nil, err.Trace(clnt.GetURL().String())
+	}
+	var stale []multipartUpload
+	for _, upload := range uploads {
+		// Skip uploads younger than --older-than parameter, if specified.
+		if olderThan != "" && isOlder(upload.Initiated, olderThan) {
+			continue
+		}
+		stale = append(stale, upload)
+	}
+	return stale, nil
+}
+
+// abortUploads - aborts uploads and returns how many were aborted.
+func abortUploads(clnt Client, uploads []multipartUpload) int {
+	var aborted int
+	for _, upload := range uploads {
+		if err := clnt.AbortMultipartUpload(upload); err != nil {
+			errorIf(err.Trace(upload.Key, upload.UploadID), "Unable to abort upload of `"+upload.Key+"`.")
+			continue
+		}
+		aborted++
+	}
+	return aborted
+}
+
+// confirmClean - asks the user to confirm aborting uploads.
+func confirmClean(target string, count int) bool {
+	fmt.Fprintf(os.Stderr, "Abort %d incomplete uploads in `%s`? [y/N]: ", count, target)
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
+
+// mainClean is the main entry point for clean command.
+func mainClean(ctx *cli.Context) error {
+	checkCleanSyntax(ctx)
+
+	console.SetColor("Clean", color.New(color.FgGreen, color.Bold))
+
+	olderThan := ctx.String("older-than")
+	isForce := ctx.Bool("force")
+
+	var result batchResult
+	for _, url := range ctx.Args() {
+		clnt, err := newClient(url)
+		if err != nil {
+			errorIf(err.Trace(url), "Unable to initialize target `"+url+"`.")
+			result.failure()
+			continue
+		}
+
+		uploads, err := staleUploads(clnt, olderThan)
+		if err != nil {
+			errorIf(err, "Unable to list incomplete uploads in `"+url+"`.")
+			result.failure()
+			continue
+		}
+
+		msg := cleanMessage{Target: url, Found: len(uploads)}
+		failed := false
+		if len(uploads) > 0 && (isForce || confirmClean(url, len(uploads))) {
+			msg.Aborted = abortUploads(clnt, uploads)
+			failed = msg.Aborted < msg.Found
+		}
+		printMsg(msg)
+		if failed {
+			result.failure()
+			continue
+		}
+		result.success()
+	}
+	return result.exit()
+}
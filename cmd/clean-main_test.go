@@ -0,0 +1,128 @@
+/*
+ * MinIO Client (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+)
+
+// uploadsHandler serves a fixed set of multipart uploads and records aborts.
+type uploadsHandler struct {
+	mutex    *sync.Mutex
+	uploads  map[string]time.Time // uploadID -> initiated
+	aborted  []string
+	pageSize int
+}
+
+func (h *uploadsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	query := r.URL.Query()
+	_, isLocation := query["location"]
+	_, isUploads := query["uploads"]
+	switch {
+	case r.Method == http.MethodGet && isLocation:
+		w.Write([]byte(`<LocationConstraint xmlns="http://s3.amazonaws.com/doc/2006-03-01/"></LocationConstraint>`))
+	case r.Method == http.MethodGet && isUploads:
+		type upload struct {
+			Key       string
+			UploadID  string `xml:"UploadId"`
+			Initiated time.Time
+		}
+		type result struct {
+			XMLName            xml.Name `xml:"ListMultipartUploadsResult"`
+			Bucket             string
+			NextKeyMarker      string
+			NextUploadIDMarker string `xml:"NextUploadIdMarker"`
+			IsTruncated        bool
+			Uploads            []upload `xml:"Upload"`
+		}
+		var ids []string
+		for id := range h.uploads {
+			if id > query.Get("upload-id-marker") {
+				ids = append(ids, id)
+			}
+		}
+		sort.Strings(ids)
+		res := result{Bucket: "bucket"}
+		if len(ids) > h.pageSize {
+			ids = ids[:h.pageSize]
+			res.IsTruncated = true
+			res.NextKeyMarker = "object"
+			res.NextUploadIDMarker = ids[len(ids)-1]
+		}
+		for _, id := range ids {
+			res.Uploads = append(res.Uploads, upload{Key: "object", UploadID: id, Initiated: h.uploads[id]})
+		}
+		buf, _ := xml.Marshal(res)
+		w.Write(buf)
+	case r.Method == http.MethodDelete && query.Get("uploadId") != "":
+		h.aborted = append(h.aborted, query.Get("uploadId"))
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusBadRequest)
+	}
+}
+
+func TestCleanStaleUploads(t *testing.T) {
+	now := UTCNow()
+	handler := &uploadsHandler{
+		mutex: &sync.Mutex{},
+		uploads: map[string]time.Time{
+			"upload-1": now.Add(-30 * 24 * time.Hour),
+			"upload-2": now.Add(-8 * 24 * time.Hour),
+			"upload-3": now.Add(-6 * 24 * time.Hour),
+			"upload-4": now.Add(-time.Hour),
+		},
+		pageSize: 3,
+	}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	conf := newTestS3Config(server.URL + "/bucket")
+	clnt, err := s3New(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	all, err := staleUploads(clnt, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 4 {
+		t.Fatalf("Expected 4 uploads across pages, got %d", len(all))
+	}
+
+	stale, err := staleUploads(clnt, "7d")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if aborted := abortUploads(clnt, stale); aborted != 2 {
+		t.Fatalf("Expected 2 aborted uploads, got %d", aborted)
+	}
+	if !reflect.DeepEqual(handler.aborted, []string{"upload-1", "upload-2"}) {
+		t.Fatalf("Unexpected aborted uploads %v", handler.aborted)
+	}
+}
@@ -20,7 +20,6 @@ import (
 	"crypto/tls"
 	"fmt"
 	"hash/fnv"
-	"net"
 	"net/http"
 	"net/url"
 	"sync"
@@ -54,7 +53,7 @@ func newAdminFactory() func(config *Config) (*madmin.AdminClient, *probe.Error)
 
 		// Generate a hash out of s3Conf.
 		confHash := fnv.New32a()
-		confHash.Write([]byte(hostName + config.AccessKey + config.SecretKey))
+		confHash.Write([]byte(hostName + config.AccessKey + config.SecretKey + config.ClientCert + config.ClientKey))
 		confSum := confHash.Sum32()
 
 		// Lookup previous cache by hash.
@@ -75,13 +74,21 @@ func newAdminFactory() func(config *Config) (*madmin.AdminClient, *probe.Error)
 			if config.Insecure {
 				tlsConfig.InsecureSkipVerify = true
 			}
+			cert, err := loadClientCertificate(config.ClientCert, config.ClientKey)
+			if err != nil {
+				return nil, err.Trace(config.HostURL)
+			}
+			if cert != nil {
+				tlsConfig.Certificates = []tls.Certificate{*cert}
+			}
 
+			dialContext, err := newDialContext(config)
+			if err != nil {
+				return nil, err.Trace(config.HostURL)
+			}
 			var transport http.RoundTripper = &http.Transport{
-				Proxy: http.ProxyFromEnvironment,
-				DialContext: (&net.Dialer{
-					Timeout:   30 * time.Second,
-					KeepAlive: 30 * time.Second,
-				}).DialContext,
+				Proxy:                 transportProxy(config),
+				DialContext:           dialContext,
 				MaxIdleConns:          100,
 				IdleConnTimeout:       90 * time.Second,
 				TLSHandshakeTimeout:   10 * time.Second,
@@ -89,6 +96,9 @@ func newAdminFactory() func(config *Config) (*madmin.AdminClient, *probe.Error)
 				TLSClientConfig:       tlsConfig,
 			}
 
+			if config.UserAgent != "" {
+				transport = userAgentTransport{userAgent: config.UserAgent, transport: transport}
+			}
 			if config.Debug {
 				transport = httptracer.GetNewTraceTransport(newTraceV4(), transport)
 			}
@@ -16,7 +16,10 @@
 
 package cmd
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+)
 
 /// Collection of standard errors
 
@@ -42,6 +45,13 @@ func (e BucketDoesNotExist) Error() string {
 	return "Bucket `" + e.Bucket + "` does not exist."
 }
 
+// BucketCORSNotFound - bucket has no CORS configuration.
+type BucketCORSNotFound GenericBucketError
+
+func (e BucketCORSNotFound) Error() string {
+	return "Bucket `" + e.Bucket + "` has no CORS configuration."
+}
+
 // BucketExists - bucket exists.
 type BucketExists GenericBucketError
 
@@ -89,7 +99,7 @@ type ObjectOnGlacier struct {
 }
 
 func (e ObjectOnGlacier) Error() string {
-	return "Object `" + e.Object + "` is on Glacier storage."
+	return "Object `" + e.Object + "` is archived, restore it with `mc restore` before reading it."
 }
 
 // BucketNameTopLevel - generic error
@@ -183,6 +193,49 @@ func (e UnexpectedExcessRead) Error() string {
 	return msg
 }
 
+// ObjectPreconditionFailed - object exists and the upload was conditional
+// on its absence.
+type ObjectPreconditionFailed struct {
+	Object string
+}
+
+func (e ObjectPreconditionFailed) Error() string {
+	return "Precondition failed, object `" + e.Object + "` already exists."
+}
+
+// ObjectLocked - object is protected by its retention or a legal hold.
+type ObjectLocked struct {
+	Object string
+}
+
+func (e ObjectLocked) Error() string {
+	return "Object `" + e.Object + "` is locked, objects retained in GOVERNANCE mode can be removed with --bypass-governance."
+}
+
+// ChecksumMismatch - the checksum or the ETag returned by the server
+// is not the one of the uploaded data, or the file written by a copy is
+// not the one read from the source if Local is set.
+type ChecksumMismatch struct {
+	Checksum, Expected, Got string
+	Local                   bool
+}
+
+func (e ChecksumMismatch) Error() string {
+	if e.Local {
+		return e.Checksum + " mismatch, expected " + e.Expected + " but the file written has `" + e.Got + "`, it is removed"
+	}
+	return e.Checksum + " mismatch, expected " + e.Expected + " but the server returned `" + e.Got + "`"
+}
+
+// TransferIdleTimeout - no bytes of a transfer flowed for the idle timeout.
+type TransferIdleTimeout struct {
+	Timeout time.Duration
+}
+
+func (e TransferIdleTimeout) Error() string {
+	return fmt.Sprintf("Transfer aborted, no data was transferred for %s.", e.Timeout)
+}
+
 // SameFile - source and destination are same files.
 type SameFile struct {
 	Source, Destination string
@@ -191,3 +244,23 @@ type SameFile struct {
 func (e SameFile) Error() string {
 	return fmt.Sprintf("'%s' and '%s' are the same file", e.Source, e.Destination)
 }
+
+// NonSeekableRetry - upload failed after part of a source which cannot
+// be read again, such as stdin, was sent.
+type NonSeekableRetry struct {
+	Sent int64
+}
+
+func (e NonSeekableRetry) Error() string {
+	return fmt.Sprintf("Cannot retry non-seekable stream, the upload failed after `%d` bytes were sent. Copy again with --attempts to reopen the source, or resume with --continue.", e.Sent)
+}
+
+// URLSourceStatus - the web server of a cp --from-url source answered
+// with a status other than 2xx.
+type URLSourceStatus struct {
+	URL, Status string
+}
+
+func (e URLSourceStatus) Error() string {
+	return "`" + e.URL + "` answered " + e.Status
+}
@@ -0,0 +1,87 @@
+// +build !windows
+
+/*
+ * MinIO Client (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this fs except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	. "gopkg.in/check.v1"
+)
+
+// Test recursive listing of a tree with symlinks, a symlink loop and a FIFO.
+func (s *TestSuite) TestListLinksPolicy(c *C) {
+	root, e := ioutil.TempDir(os.TempDir(), "fs-")
+	c.Assert(e, IsNil)
+	defer os.RemoveAll(root)
+
+	outside, e := ioutil.TempDir(os.TempDir(), "fs-")
+	c.Assert(e, IsNil)
+	defer os.RemoveAll(outside)
+
+	c.Assert(os.MkdirAll(filepath.Join(root, "dir", "sub"), 0700), IsNil)
+	c.Assert(ioutil.WriteFile(filepath.Join(root, "dir", "a.txt"), []byte("a"), 0600), IsNil)
+	c.Assert(ioutil.WriteFile(filepath.Join(root, "dir", "sub", "b.txt"), []byte("b"), 0600), IsNil)
+	c.Assert(ioutil.WriteFile(filepath.Join(outside, "c.txt"), []byte("c"), 0600), IsNil)
+	// Link to a file, twice to a directory outside the tree and a loop back
+	// to the tree.
+	c.Assert(os.Symlink(filepath.Join(root, "dir", "a.txt"), filepath.Join(root, "dir", "link.txt")), IsNil)
+	c.Assert(os.Symlink(outside, filepath.Join(root, "dir", "outside")), IsNil)
+	c.Assert(os.Symlink(outside, filepath.Join(root, "dir", "sub", "outside")), IsNil)
+	c.Assert(os.Symlink(filepath.Join(root, "dir"), filepath.Join(root, "dir", "sub", "loop")), IsNil)
+	c.Assert(syscall.Mkfifo(filepath.Join(root, "dir", "fifo"), 0600), IsNil)
+
+	defer func(links linksPolicy) { globalLinks = links }(globalLinks)
+
+	testCases := []struct {
+		links    linksPolicy
+		expected []string
+	}{
+		{linksSkip, []string{"a.txt", "sub/b.txt"}},
+		{linksCopyAsFile, []string{"a.txt", "link.txt", "sub/b.txt"}},
+		{linksFollow, []string{"a.txt", "link.txt", "outside/c.txt", "sub/b.txt", "sub/outside/c.txt"}},
+	}
+	for _, testCase := range testCases {
+		globalLinks = testCase.links
+		fsClient, err := fsNew(filepath.Join(root, "dir") + string(filepath.Separator))
+		c.Assert(err, IsNil)
+
+		var listed []string
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			for content := range fsClient.List(true, false, false, DirNone) {
+				c.Assert(content.Err, IsNil)
+				listed = append(listed, strings.TrimPrefix(content.URL.Path, filepath.Join(root, "dir")+string(filepath.Separator)))
+			}
+		}()
+		select {
+		case <-done:
+		case <-time.After(10 * time.Second):
+			c.Fatalf("Listing with --links %s did not finish", testCase.links)
+		}
+		sort.Strings(listed)
+		c.Assert(listed, DeepEquals, testCase.expected)
+	}
+}
@@ -52,6 +52,38 @@ const (
 	slashSeperator = "/"
 )
 
+// linksPolicy - handling of symbolic links in recursive listings.
+type linksPolicy string
+
+const (
+	// Skip symbolic links with a notice.
+	linksSkip = linksPolicy("skip")
+	// Follow symbolic links to files and directories.
+	linksFollow = linksPolicy("follow")
+	// Treat symbolic links to files as regular files.
+	linksCopyAsFile = linksPolicy("copy-as-file")
+)
+
+// Mode bits of files which are neither regular files nor directories.
+const specialFileModes = os.ModeNamedPipe | os.ModeSocket | os.ModeDevice | os.ModeCharDevice | os.ModeIrregular
+
+// setGlobalLinks validates the --links flag and applies it to all
+// local listings of the command.
+func setGlobalLinks(links string) {
+	policy, err := parseLinksPolicy(links)
+	fatalIf(err, "Invalid value for `--links`, allowed values are [skip, follow, copy-as-file].")
+	globalLinks = policy
+}
+
+// parseLinksPolicy validates a --links value.
+func parseLinksPolicy(links string) (linksPolicy, *probe.Error) {
+	switch policy := linksPolicy(links); policy {
+	case linksSkip, linksFollow, linksCopyAsFile:
+		return policy, nil
+	}
+	return "", errInvalidArgument().Trace(links)
+}
+
 var ( // GOOS specific ignore list.
 	ignoreFiles = map[string][]string{
 		"darwin":  {"*.DS_Store"},
@@ -59,6 +91,22 @@ var ( // GOOS specific ignore list.
 	}
 )
 
+// isHiddenPath - returns true if fp or a folder of fp below root is
+// hidden, named with a leading dot. The root itself is never hidden,
+// hidden folders given on the command line are walked.
+func isHiddenPath(root, fp string) bool {
+	rel, e := filepath.Rel(root, fp)
+	if e != nil {
+		return false
+	}
+	for _, name := range strings.Split(rel, string(filepath.Separator)) {
+		if strings.HasPrefix(name, ".") && name != "." && name != ".." {
+			return true
+		}
+	}
+	return false
+}
+
 // fsNew - instantiate a new fs
 func fsNew(path string) (Client, *probe.Error) {
 	if strings.TrimSpace(path) == "" {
@@ -175,6 +223,9 @@ func (f *fsClient) Watch(params watchParams) (*watchObject, *probe.Error) {
 			if isIgnoredFile(event.Path()) {
 				continue
 			}
+			if globalExcludeHidden && isHiddenPath(f.PathURL.Path, event.Path()) {
+				continue
+			}
 			var i os.FileInfo
 			if IsPutEvent(event.Event()) {
 				// Look for any writes, send a response to indicate a full copy.
@@ -220,6 +271,9 @@ func (f *fsClient) Watch(params watchParams) (*watchObject, *probe.Error) {
 	}, nil
 }
 
+// isStreamFile - returns true for targets which cannot be resumed or
+// renamed into, like standard streams, named pipes and devices. They
+// are written to directly.
 func isStreamFile(objectPath string) bool {
 	switch objectPath {
 	case os.DevNull:
@@ -231,7 +285,8 @@ func isStreamFile(objectPath string) bool {
 	case os.Stderr.Name():
 		return true
 	}
-	return false
+	st, e := os.Stat(objectPath)
+	return e == nil && !st.Mode().IsRegular() && !st.IsDir()
 }
 
 func preserveAttributes(fd *os.File, attr map[string]string) *probe.Error {
@@ -301,7 +356,7 @@ func (f *fsClient) put(reader io.Reader, size int64, metadata map[string][]strin
 	}
 
 	attr := make(map[string]string)
-	if len(metadata["mc-attrs"]) != 0 {
+	if len(metadata["mc-attrs"]) != 0 && !avoidResumeUpload {
 		attr, e = parseAttribute(metadata["mc-attrs"][0])
 		if e != nil {
 			return 0, probe.NewError(e)
@@ -311,16 +366,18 @@ func (f *fsClient) put(reader io.Reader, size int64, metadata map[string][]strin
 		}
 	}
 
-	// Get stat to get the current size.
-	partSt, e := os.Stat(objectPartPath)
-	if e != nil {
-		err := f.toClientError(e, objectPartPath)
-		return 0, err.Trace(objectPartPath)
-	}
-
 	var totalWritten int64
-	// Current file offset.
-	var currentOffset = partSt.Size()
+	// Current file offset, streams are always written from the start.
+	var currentOffset int64
+	if !avoidResumeUpload {
+		// Get stat to get the current size.
+		partSt, e := os.Stat(objectPartPath)
+		if e != nil {
+			err := f.toClientError(e, objectPartPath)
+			return 0, err.Trace(objectPartPath)
+		}
+		currentOffset = partSt.Size()
+	}
 
 	if !isStdIO(reader) && size > 0 {
 		reader = hookreader.NewHook(reader, progress)
@@ -380,8 +437,23 @@ func (f *fsClient) put(reader io.Reader, size int64, metadata map[string][]strin
 		}
 	}
 	if !avoidResumeUpload {
-		// Safely completed put. Now commit by renaming to actual filename.
-		if e = os.Rename(objectPartPath, objectPath); e != nil {
+		if globalIfNotExists {
+			// Linking fails if the object exists, unlike renaming which
+			// replaces it.
+			if e = os.Link(objectPartPath, objectPath); e != nil {
+				if os.IsExist(e) {
+					os.Remove(objectPartPath)
+					return totalWritten, probe.NewError(ObjectPreconditionFailed{Object: objectPath})
+				}
+				err := f.toClientError(e, objectPath)
+				return totalWritten, err.Trace(objectPartPath, objectPath)
+			}
+			e = os.Remove(objectPartPath)
+		} else {
+			// Safely completed put. Now commit by renaming to actual filename.
+			e = os.Rename(objectPartPath, objectPath)
+		}
+		if e != nil {
 			err := f.toClientError(e, objectPath)
 			return totalWritten, err.Trace(objectPartPath, objectPath)
 		}
@@ -458,6 +530,25 @@ func (f *fsClient) Get(sse encrypt.ServerSide) (io.ReadCloser, *probe.Error) {
 	return fileData, nil
 }
 
+// GetRange returns length bytes of the file from offset, zero length
+// reads until the end of the file.
+func (f *fsClient) GetRange(offset, length int64, sse encrypt.ServerSide) (io.ReadCloser, *probe.Error) {
+	reader, err := f.Get(sse)
+	if err != nil || !isByteRange(offset, length) {
+		return reader, err
+	}
+	fileData := reader.(*os.File)
+	if _, e := fileData.Seek(offset, io.SeekStart); e != nil {
+		fileData.Close()
+		err = f.toClientError(e, f.PathURL.Path)
+		return nil, err.Trace(f.PathURL.Path)
+	}
+	if length == 0 {
+		return fileData, nil
+	}
+	return newLimitedReadCloser(fileData, length), nil
+}
+
 // Check if the given error corresponds to ENOTEMPTY for unix
 // and ERROR_DIR_NOT_EMPTY for windows (directory not empty).
 func isSysErrNotEmpty(err error) bool {
@@ -763,6 +854,9 @@ func (f *fsClient) listDirOpt(contentCh chan *clientContent, isIncomplete bool,
 		}
 
 		for _, file := range files {
+			if globalExcludeHidden && strings.HasPrefix(file.Name(), ".") {
+				continue
+			}
 			name := filepath.Join(currentPath, file.Name())
 			content := clientContent{
 				URL:  *newClientURL(name),
@@ -804,13 +898,31 @@ func (f *fsClient) listDirOpt(contentCh chan *clientContent, isIncomplete bool,
 	}
 }
 
+// isAncestorDir - reports whether dir is one of the directories walked
+// from root down to fp, compared by device and inode. Following a link
+// to one of them loops, while other links to an already walked directory
+// are walked again.
+func isAncestorDir(root, fp string, dir os.FileInfo) bool {
+	root = filepath.Clean(root)
+	for parent := filepath.Dir(fp); strings.HasPrefix(parent, root); parent = filepath.Dir(parent) {
+		if fi, e := os.Stat(parent); e == nil && os.SameFile(fi, dir) {
+			return true
+		}
+		if parent == root {
+			break
+		}
+	}
+	return false
+}
+
 func (f *fsClient) listRecursiveInRoutine(contentCh chan *clientContent, isMetadata bool) {
 	// close channels upon return.
 	defer close(contentCh)
 	var dirName string
 	var filePrefix string
 	pathURL := *f.PathURL
-	visitFS := func(fp string, fi os.FileInfo, e error) error {
+	var visitFS ioutils.FTWFunc
+	visitFS = func(fp string, fi os.FileInfo, e error) error {
 		// If file path ends with filepath.Separator and equals to root path, skip it.
 		if strings.HasSuffix(fp, string(pathURL.Separator)) {
 			if fp == dirName {
@@ -827,6 +939,14 @@ func (f *fsClient) listRecursiveInRoutine(contentCh chan *clientContent, isMetad
 			return nil
 		}
 
+		// Hidden files are skipped, hidden folders are not walked.
+		if globalExcludeHidden && e == nil && isHiddenPath(pathURL.Path, fp) {
+			if fi.IsDir() {
+				return ioutils.ErrSkipDir
+			}
+			return nil
+		}
+
 		/// In following situations we need to handle listing properly.
 		// - When filepath is '/usr' and prefix is '/usr/bi'
 		// - When filepath is '/usr/bin/subdir' and prefix is '/usr/bi'
@@ -866,11 +986,29 @@ func (f *fsClient) listRecursiveInRoutine(contentCh chan *clientContent, isMetad
 			return e
 		}
 		if fi.Mode()&os.ModeSymlink == os.ModeSymlink {
+			if globalLinks == linksSkip {
+				errorIf(probe.NewError(PathIsNotRegular{Path: fp}),
+					"Skipping symbolic link, use `--links` to follow it.")
+				return nil
+			}
 			fi, e = os.Stat(fp)
 			if e != nil {
 				// Ignore any errors for symlink
 				return nil
 			}
+			if fi.Mode().IsDir() && globalLinks == linksFollow {
+				if isAncestorDir(dirName, fp, fi) {
+					errorIf(probe.NewError(TooManyLevelsSymlink{Path: fp}),
+						"Skipping symbolic link loop.")
+					return nil
+				}
+				// Trailing separator makes the walk start at the link target.
+				return ioutils.FTW(fp+string(os.PathSeparator), visitFS)
+			}
+		}
+		if fi.Mode()&specialFileModes != 0 && globalLinks != "" {
+			errorIf(probe.NewError(PathIsNotRegular{Path: fp}), "Skipping special file.")
+			return nil
 		}
 		if fi.Mode().IsRegular() {
 			contentCh <- &clientContent{
@@ -945,6 +1083,30 @@ func (f *fsClient) GetAccessRules() (map[string]string, *probe.Error) {
 	})
 }
 
+// GetObjectACL - unsupported API
+func (f *fsClient) GetObjectACL() ([]aclGrant, *probe.Error) {
+	return nil, probe.NewError(APINotImplemented{
+		API:     "GetObjectACL",
+		APIType: "filesystem",
+	})
+}
+
+// ListMultipartUploads - unsupported API
+func (f *fsClient) ListMultipartUploads() ([]multipartUpload, *probe.Error) {
+	return nil, probe.NewError(APINotImplemented{
+		API:     "ListMultipartUploads",
+		APIType: "filesystem",
+	})
+}
+
+// AbortMultipartUpload - unsupported API
+func (f *fsClient) AbortMultipartUpload(upload multipartUpload) *probe.Error {
+	return probe.NewError(APINotImplemented{
+		API:     "AbortMultipartUpload",
+		APIType: "filesystem",
+	})
+}
+
 // Set object retention for a given object.
 func (f *fsClient) PutObjectRetention(mode *minio.RetentionMode, retainUntilDate *time.Time) *probe.Error {
 	return probe.NewError(APINotImplemented{
@@ -953,6 +1115,22 @@ func (f *fsClient) PutObjectRetention(mode *minio.RetentionMode, retainUntilDate
 	})
 }
 
+// Set object legal hold for a given object.
+func (f *fsClient) PutObjectLegalHold(status string) *probe.Error {
+	return probe.NewError(APINotImplemented{
+		API:     "PutObjectLegalHold",
+		APIType: "filesystem",
+	})
+}
+
+// RestoreObject - unsupported API
+func (f *fsClient) RestoreObject(days int, tier string) *probe.Error {
+	return probe.NewError(APINotImplemented{
+		API:     "RestoreObject",
+		APIType: "filesystem",
+	})
+}
+
 // GetAccess - get access policy permissions.
 func (f *fsClient) GetAccess() (access string, policyJSON string, err *probe.Error) {
 	// For windows this feature is not implemented.
@@ -19,12 +19,16 @@ package cmd
 import (
 	"bytes"
 	"context"
+	"fmt"
 	"io"
 	"io/ioutil"
 	"os"
 	"path/filepath"
 	"runtime"
+	"strings"
+	"sync"
 
+	"github.com/minio/mc/pkg/probe"
 	. "gopkg.in/check.v1"
 )
 
@@ -256,6 +260,62 @@ func (s *TestSuite) TestPut(c *C) {
 	c.Assert(n, Equals, int64(len(data)))
 }
 
+// Test put to a pipe is streamed without resuming or renaming.
+func (s *TestSuite) TestPutPipe(c *C) {
+	if runtime.GOOS == "windows" {
+		c.Skip("pipes are not addressable by path on windows")
+	}
+	r, w, e := os.Pipe()
+	c.Assert(e, IsNil)
+	defer r.Close()
+
+	objectPath := fmt.Sprintf("/dev/fd/%d", w.Fd())
+	c.Assert(isStreamFile(objectPath), Equals, true)
+	fsClient, err := fsNew(objectPath)
+	c.Assert(err, IsNil)
+
+	readCh := make(chan []byte, 1)
+	go func() {
+		data, _ := ioutil.ReadAll(r)
+		readCh <- data
+	}()
+
+	data := "hello"
+	n, err := fsClient.Put(context.Background(), bytes.NewReader([]byte(data)), int64(len(data)), map[string]string{}, nil, nil)
+	c.Assert(err, IsNil)
+	c.Assert(n, Equals, int64(len(data)))
+	c.Assert(w.Close(), IsNil)
+	c.Assert(string(<-readCh), Equals, data)
+}
+
+// Test parallel puts creating the same missing folders.
+func (s *TestSuite) TestPutConcurrentFolders(c *C) {
+	root, e := ioutil.TempDir(os.TempDir(), "fs-")
+	c.Assert(e, IsNil)
+	defer os.RemoveAll(root)
+
+	var wg sync.WaitGroup
+	errs := make([]*probe.Error, 16)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			fsClient, err := fsNew(filepath.Join(root, "a", "b", "c", fmt.Sprintf("object%d", i)))
+			if err == nil {
+				_, err = fsClient.Put(context.Background(), strings.NewReader("hello"), 5, map[string]string{}, nil, nil)
+			}
+			errs[i] = err
+		}(i)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		c.Assert(err, IsNil)
+	}
+	entries, e := ioutil.ReadDir(filepath.Join(root, "a", "b", "c"))
+	c.Assert(e, IsNil)
+	c.Assert(len(entries), Equals, len(errs))
+}
+
 // Test read a file.
 func (s *TestSuite) TestGet(c *C) {
 	root, e := ioutil.TempDir(os.TempDir(), "fs-")
@@ -0,0 +1,431 @@
+/*
+ * MinIO Client (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/minio/minio-go/v6"
+	"github.com/minio/minio-go/v6/pkg/credentials"
+	"github.com/minio/minio-go/v6/pkg/encrypt"
+	"github.com/minio/minio-go/v6/pkg/s3signer"
+	"github.com/minio/minio-go/v6/pkg/s3utils"
+)
+
+// Part size of checksummed multipart uploads when none is configured.
+const defaultChecksumPartSize = 64 * humanize.MiByte
+
+// Parts uploaded with requests of their own which are interrupted or
+// fail on the server are sent again, up to this number of attempts.
+const putPartAttempts = 3
+
+const (
+	// Hash of the empty payload of requests without a body.
+	emptySHA256Hex = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+	// Payload hash of requests whose body is not hashed.
+	unsignedPayload = "UNSIGNED-PAYLOAD"
+)
+
+// signedDo - sends a request signed over all its headers with the
+// credentials of the client, for APIs the minio-go client does not
+// offer. The URL and region are those minio-go uses for the request,
+// looked up by presigning it, and the presigned query is replaced by a
+// signature of the headers. Responses other than 2xx are returned as
+// minio.ErrorResponse.
+func (c *s3Client) signedDo(ctx context.Context, method, bucket, object string, params url.Values, header http.Header, body io.Reader, size int64) (*http.Response, error) {
+	u, e := c.getAPI().Presign(method, bucket, object, 15*time.Minute, params)
+	if e != nil {
+		return nil, e
+	}
+	query := u.Query()
+	region := "us-east-1"
+	if credential := strings.Split(query.Get("X-Amz-Credential"), "/"); len(credential) > 2 {
+		region = credential[2]
+	}
+	for key := range query {
+		if strings.HasPrefix(strings.ToLower(key), "x-amz-") || key == "AWSAccessKeyId" || key == "Signature" || key == "Expires" {
+			query.Del(key)
+		}
+	}
+	u.RawQuery = s3utils.QueryEncode(query)
+
+	req, e := http.NewRequest(method, u.String(), body)
+	if e != nil {
+		return nil, e
+	}
+	req = req.WithContext(ctx)
+	req.ContentLength = size
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	if req, e = c.signRequest(req, bucket, region, body != nil); e != nil {
+		return nil, e
+	}
+	resp, e := (&http.Client{Transport: c.transport}).Do(req)
+	if e != nil {
+		return nil, e
+	}
+	if resp.StatusCode/100 != 2 {
+		defer resp.Body.Close()
+		errResponse := minio.ErrorResponse{StatusCode: resp.StatusCode}
+		if xml.NewDecoder(resp.Body).Decode(&errResponse) != nil {
+			errResponse.Code = resp.Status
+			errResponse.Message = resp.Status
+		}
+		return nil, errResponse
+	}
+	return resp, nil
+}
+
+// signRequest - returns req signed for region with the credentials of
+// the client, unsigned if they are empty. Bodies are not hashed, they
+// are sent as unsigned payloads.
+func (c *s3Client) signRequest(req *http.Request, bucket, region string, hasBody bool) (*http.Request, error) {
+	value := credentials.Value{AccessKeyID: c.config.AccessKey, SecretAccessKey: c.config.SecretKey, SessionToken: c.config.SessionToken}
+	if c.config.Creds != nil {
+		var e error
+		if value, e = c.config.Creds.Get(); e != nil {
+			return nil, e
+		}
+	}
+	if value.AccessKeyID == "" || value.SecretAccessKey == "" {
+		return req, nil
+	}
+	if strings.ToUpper(c.config.Signature) == "S3V2" {
+		virtualHost := bucket != "" && strings.HasPrefix(req.URL.Host, bucket+".")
+		return s3signer.SignV2(*req, value.AccessKeyID, value.SecretAccessKey, virtualHost), nil
+	}
+	payloadHash := emptySHA256Hex
+	if hasBody {
+		payloadHash = unsignedPayload
+	}
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	return s3signer.SignV4(*req, value.AccessKeyID, value.SecretAccessKey, value.SessionToken, region), nil
+}
+
+// isRetriablePart - reports whether an upload of a part failing with e
+// should be sent again.
+func isRetriablePart(ctx context.Context, e error) bool {
+	return isInterruptedUpload(ctx, e) || minio.ToErrorResponse(e).StatusCode >= http.StatusInternalServerError
+}
+
+// partReader reads a part to upload, reporting to progress only the
+// bytes no earlier attempt of the part reported.
+type partReader struct {
+	reader   *bytes.Reader
+	progress io.Reader
+	read     int64
+	reported *int64
+}
+
+func (r *partReader) Read(b []byte) (int, error) {
+	n, e := r.reader.Read(b)
+	if end := r.read + int64(n); end > *r.reported {
+		skip := int64(0)
+		if *r.reported > r.read {
+			skip = *r.reported - r.read
+		}
+		r.progress.Read(b[skip:n])
+		*r.reported = end
+	}
+	r.read += int64(n)
+	return n, e
+}
+
+// putPart - uploads part with a request of its own, sending it again
+// after a backoff while the request is interrupted or fails on the
+// server.
+func (c *s3Client) putPart(ctx context.Context, bucket, object string, params url.Values, header http.Header, part []byte, progress io.Reader) (resp *http.Response, e error) {
+	doneCh := make(chan struct{})
+	defer close(doneCh)
+	var reported int64
+	for attempt := range newRetryTimerContinous(100*time.Millisecond, 5*time.Second, minio.MaxJitter, doneCh) {
+		var body io.Reader = bytes.NewReader(part)
+		if progress != nil {
+			body = &partReader{reader: bytes.NewReader(part), progress: progress, reported: &reported}
+		}
+		resp, e = c.signedDo(ctx, http.MethodPut, bucket, object, params, header, body, int64(len(part)))
+		if e == nil || attempt+1 >= putPartAttempts || !isRetriablePart(ctx, e) {
+			break
+		}
+	}
+	return resp, e
+}
+
+// checksumPart is a part of a checksummed multipart upload.
+type checksumPart struct {
+	PartNumber     int
+	ETag           string
+	ChecksumCRC32  string `xml:",omitempty"`
+	ChecksumCRC32C string `xml:",omitempty"`
+	ChecksumSHA1   string `xml:",omitempty"`
+	ChecksumSHA256 string `xml:",omitempty"`
+
+	// MD5 of the data of the part, for --verify.
+	md5Sum []byte
+}
+
+// setChecksum - sets the element of the part matching the algorithm,
+// MD5 is verified per part only.
+func (p *checksumPart) setChecksum(algorithm checksumAlgorithm, checksum string) {
+	switch algorithm {
+	case checksumCRC32:
+		p.ChecksumCRC32 = checksum
+	case checksumCRC32C:
+		p.ChecksumCRC32C = checksum
+	case checksumSHA1:
+		p.ChecksumSHA1 = checksum
+	case checksumSHA256:
+		p.ChecksumSHA256 = checksum
+	}
+}
+
+// checksum - returns the element of the part matching the algorithm.
+func (p checksumPart) checksum(algorithm checksumAlgorithm) string {
+	switch algorithm {
+	case checksumCRC32:
+		return p.ChecksumCRC32
+	case checksumCRC32C:
+		return p.ChecksumCRC32C
+	case checksumSHA1:
+		return p.ChecksumSHA1
+	case checksumSHA256:
+		return p.ChecksumSHA256
+	}
+	return ""
+}
+
+// putObjectSigned - uploads reader with requests of its own for the
+// upload options minio-go does not offer: the checksum of every request
+// sent for the server to verify, If-None-Match for uploads which must
+// not overwrite an existing object, the Expires header and the
+// verification of the returned ETag. The checksum is computed while the
+// parts are buffered, reading the source once so that unseekable sources
+// like stdin are supported, and every part can be sent again.
+func (c *s3Client) putObjectSigned(ctx context.Context, bucket, object string, reader io.Reader, size int64, opts minio.PutObjectOptions) (int64, error) {
+	algorithm := c.config.Checksum
+	// ETags of encrypted objects are not the MD5 of their data.
+	verify := c.config.Verify && opts.ServerSideEncryption == nil
+	partSize := int64(opts.PartSize)
+	if partSize == 0 {
+		partSize = int64(effectivePartSize(size, defaultChecksumPartSize))
+	}
+	if size >= 0 && size < partSize {
+		partSize = size
+	}
+
+	buf := make([]byte, partSize)
+	var peek []byte
+	// readPart - reads and hashes the next part, the first byte of the
+	// following part is peeked to detect the last part of sources of
+	// unknown size.
+	readPart := func() (part, sum []byte, last bool, e error) {
+		h := algorithm.newHash()
+		src := io.TeeReader(io.MultiReader(bytes.NewReader(peek), reader), h)
+		n, e := io.ReadFull(src, buf)
+		if e == io.EOF || e == io.ErrUnexpectedEOF {
+			return buf[:n], h.Sum(nil), true, nil
+		}
+		if e != nil {
+			return nil, nil, false, e
+		}
+		peek = make([]byte, 1)
+		if _, e = io.ReadFull(reader, peek); e == io.EOF {
+			return buf[:n], h.Sum(nil), true, nil
+		}
+		return buf[:n], h.Sum(nil), false, e
+	}
+	part, sum, last, e := readPart()
+	if e != nil {
+		return 0, e
+	}
+	header := opts.Header()
+	if last {
+		if algorithm != checksumNone {
+			header.Set(algorithm.header(), encodeChecksum(sum))
+		}
+		if c.config.IfNotExists {
+			header.Set("If-None-Match", "*")
+		}
+		resp, e := c.putPart(ctx, bucket, object, nil, header, part, opts.Progress)
+		if e != nil {
+			return 0, e
+		}
+		resp.Body.Close()
+		if verify {
+			sum := md5.Sum(part)
+			if e = verifyETag(resp.Header.Get("ETag"), hex.EncodeToString(sum[:])); e != nil {
+				return 0, e
+			}
+		}
+		return int64(len(part)), nil
+	}
+
+	if algorithm != checksumNone && algorithm != checksumMD5 {
+		header.Set("X-Amz-Checksum-Algorithm", string(algorithm))
+	}
+	resp, e := c.signedDo(ctx, http.MethodPost, bucket, object, url.Values{"uploads": []string{""}}, header, nil, 0)
+	if e != nil {
+		return 0, e
+	}
+	var initiated struct {
+		UploadID string `xml:"UploadId"`
+	}
+	e = xml.NewDecoder(resp.Body).Decode(&initiated)
+	resp.Body.Close()
+	if e != nil {
+		return 0, e
+	}
+	uploadParams := url.Values{"uploadId": []string{initiated.UploadID}}
+
+	n, parts, sums, e := c.uploadChecksumParts(ctx, bucket, object, uploadParams, part, sum, readPart, opts.Progress, opts.ServerSideEncryption)
+	if e != nil {
+		// Do not leave the parts behind, the error of the upload is
+		// more relevant than one aborting it.
+		if resp, ae := c.signedDo(context.Background(), http.MethodDelete, bucket, object, uploadParams, nil, nil, 0); ae == nil {
+			resp.Body.Close()
+		}
+		return n, e
+	}
+	return n, c.completeChecksumUpload(ctx, bucket, object, uploadParams, parts, sums, verify)
+}
+
+// uploadChecksumParts - uploads the parts of a multipart upload, starting
+// with the already read first one, reporting them to progress.
+func (c *s3Client) uploadChecksumParts(ctx context.Context, bucket, object string, uploadParams url.Values,
+	part, sum []byte, readPart func() ([]byte, []byte, bool, error), progress io.Reader,
+	sse encrypt.ServerSide) (n int64, parts []checksumPart, sums [][]byte, e error) {
+	algorithm := c.config.Checksum
+	for last := false; ; {
+		partNumber := len(parts) + 1
+		if partNumber > maxPartsCount {
+			return n, nil, nil, errors.New("object exceeds the maximum of " + strconv.Itoa(maxPartsCount) + " parts, raise --part-size")
+		}
+		header := make(http.Header)
+		if sse != nil && sse.Type() == encrypt.SSEC {
+			sse.Marshal(header)
+		}
+		checksum := encodeChecksum(sum)
+		if algorithm != checksumNone {
+			header.Set(algorithm.header(), checksum)
+		}
+		params := url.Values{"partNumber": []string{strconv.Itoa(partNumber)}}
+		for k, v := range uploadParams {
+			params[k] = v
+		}
+		resp, e := c.putPart(ctx, bucket, object, params, header, part, progress)
+		if e != nil {
+			return n, nil, nil, e
+		}
+		resp.Body.Close()
+		n += int64(len(part))
+
+		uploaded := checksumPart{PartNumber: partNumber, ETag: resp.Header.Get("ETag")}
+		uploaded.setChecksum(algorithm, checksum)
+		if c.config.Verify {
+			md5Sum := md5.Sum(part)
+			uploaded.md5Sum = md5Sum[:]
+		}
+		parts = append(parts, uploaded)
+		sums = append(sums, append([]byte{}, sum...))
+
+		if last {
+			return n, parts, sums, nil
+		}
+		if part, sum, last, e = readPart(); e != nil {
+			return n, nil, nil, e
+		}
+	}
+}
+
+// completeChecksumUpload - completes a multipart upload verifying the
+// composite checksum returned by the server if any, and the composite
+// ETag if verify is set. Conditional uploads are checked by the server on
+// completion.
+func (c *s3Client) completeChecksumUpload(ctx context.Context, bucket, object string, uploadParams url.Values, parts []checksumPart, sums [][]byte, verify bool) error {
+	algorithm := c.config.Checksum
+	complete := struct {
+		XMLName xml.Name       `xml:"http://s3.amazonaws.com/doc/2006-03-01/ CompleteMultipartUpload"`
+		Parts   []checksumPart `xml:"Part"`
+	}{Parts: parts}
+	completeBytes, e := xml.Marshal(complete)
+	if e != nil {
+		return e
+	}
+	header := http.Header{"Content-Type": []string{"application/xml"}}
+	if c.config.IfNotExists {
+		header.Set("If-None-Match", "*")
+	}
+	resp, e := c.signedDo(ctx, http.MethodPost, bucket, object, uploadParams, header,
+		bytes.NewReader(completeBytes), int64(len(completeBytes)))
+	if e != nil {
+		return e
+	}
+	defer resp.Body.Close()
+
+	// S3 may report errors of a complete with a successful status.
+	resultBytes, e := ioutil.ReadAll(resp.Body)
+	if e != nil {
+		return e
+	}
+	var errResponse minio.ErrorResponse
+	if xml.Unmarshal(resultBytes, &errResponse) == nil && errResponse.Code != "" {
+		errResponse.StatusCode = resp.StatusCode
+		return errResponse
+	}
+	var result checksumPart
+	e = xml.Unmarshal(resultBytes, &result)
+	if algorithm != checksumNone && e == nil {
+		expected := algorithm.compositeChecksum(sums)
+		if got := result.checksum(algorithm); got != "" && strings.Split(got, "-")[0] != strings.Split(expected, "-")[0] {
+			return ChecksumMismatch{Checksum: "checksum", Expected: expected, Got: got}
+		}
+	}
+	if verify {
+		if e != nil {
+			return e
+		}
+		md5Sums := make([][]byte, len(parts))
+		for i, part := range parts {
+			md5Sums[i] = part.md5Sum
+		}
+		return verifyETag(result.ETag, compositeETag(md5Sums))
+	}
+	return nil
+}
+
+// verifyETag - returns an error unless the ETag returned by the server is
+// the one expected from the uploaded data.
+func verifyETag(etag, expected string) error {
+	if etag = strings.Trim(etag, "\""); etag != expected {
+		return ChecksumMismatch{Checksum: "ETag", Expected: expected, Got: etag}
+	}
+	return nil
+}
@@ -0,0 +1,216 @@
+/*
+ * MinIO Client (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/minio-go/v6"
+)
+
+// corsRule - a CORS rule of a bucket. The JSON names are the ones used
+// by the AWS CLI, the XML names the ones of the S3 API.
+type corsRule struct {
+	AllowedHeaders []string `xml:"AllowedHeader,omitempty" json:"AllowedHeaders,omitempty"`
+	AllowedMethods []string `xml:"AllowedMethod" json:"AllowedMethods"`
+	AllowedOrigins []string `xml:"AllowedOrigin" json:"AllowedOrigins"`
+	ExposeHeaders  []string `xml:"ExposeHeader,omitempty" json:"ExposeHeaders,omitempty"`
+	ID             string   `xml:"ID,omitempty" json:"ID,omitempty"`
+	MaxAgeSeconds  int      `xml:"MaxAgeSeconds,omitempty" json:"MaxAgeSeconds,omitempty"`
+}
+
+// corsConfiguration - the CORS configuration of a bucket.
+type corsConfiguration struct {
+	XMLName xml.Name   `xml:"CORSConfiguration" json:"-"`
+	Xmlns   string     `xml:"xmlns,attr,omitempty" json:"-"`
+	Rules   []corsRule `xml:"CORSRule" json:"CORSRules"`
+}
+
+// Methods allowed in CORS rules.
+var corsAllowedMethods = []string{"GET", "PUT", "POST", "DELETE", "HEAD"}
+
+// validate - checks the rules, at least one is required and each one
+// needs an origin and valid methods. Methods are made upper case.
+func (cfg *corsConfiguration) validate() *probe.Error {
+	if len(cfg.Rules) == 0 {
+		return probe.NewError(fmt.Errorf("No CORS rules found"))
+	}
+	for i := range cfg.Rules {
+		rule := &cfg.Rules[i]
+		if len(rule.AllowedOrigins) == 0 {
+			return probe.NewError(fmt.Errorf("CORS rule %d has no allowed origin", i+1))
+		}
+		for _, origin := range rule.AllowedOrigins {
+			if origin == "" {
+				return probe.NewError(fmt.Errorf("CORS rule %d has an empty allowed origin", i+1))
+			}
+		}
+		if len(rule.AllowedMethods) == 0 {
+			return probe.NewError(fmt.Errorf("CORS rule %d has no allowed method", i+1))
+		}
+		for j, method := range rule.AllowedMethods {
+			method = strings.ToUpper(method)
+			valid := false
+			for _, m := range corsAllowedMethods {
+				if method == m {
+					valid = true
+					break
+				}
+			}
+			if !valid {
+				return probe.NewError(fmt.Errorf("CORS rule %d has an invalid method `%s`, valid methods are `[%s]`",
+					i+1, rule.AllowedMethods[j], strings.Join(corsAllowedMethods, ", ")))
+			}
+			rule.AllowedMethods[j] = method
+		}
+		if rule.MaxAgeSeconds < 0 {
+			return probe.NewError(fmt.Errorf("CORS rule %d has a negative max age", i+1))
+		}
+	}
+	return nil
+}
+
+// parseCORSJSON - reads a CORS configuration in the JSON format of the
+// AWS CLI, unknown fields are refused to catch misspelled names.
+func parseCORSJSON(data []byte) (*corsConfiguration, *probe.Error) {
+	cfg := &corsConfiguration{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if e := decoder.Decode(cfg); e != nil {
+		return nil, probe.NewError(e)
+	}
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// toXML - the CORS configuration as sent to S3.
+func (cfg *corsConfiguration) toXML() ([]byte, *probe.Error) {
+	xmlCfg := *cfg
+	xmlCfg.Xmlns = "http://s3.amazonaws.com/doc/2006-03-01/"
+	data, e := xml.Marshal(xmlCfg)
+	if e != nil {
+		return nil, probe.NewError(e)
+	}
+	return data, nil
+}
+
+// toJSON - the CORS configuration in the JSON format of the AWS CLI.
+func (cfg *corsConfiguration) toJSON() ([]byte, *probe.Error) {
+	data, e := json.MarshalIndent(cfg, "", "  ")
+	if e != nil {
+		return nil, probe.NewError(e)
+	}
+	return data, nil
+}
+
+// corsError - translates the errors of the CORS API.
+func (c *s3Client) corsError(e error, bucket string) *probe.Error {
+	switch minio.ToErrorResponse(e).Code {
+	case "NoSuchBucket":
+		return probe.NewError(BucketDoesNotExist{Bucket: bucket})
+	case "NoSuchCORSConfiguration":
+		return probe.NewError(BucketCORSNotFound{Bucket: bucket})
+	case "NotImplemented":
+		return probe.NewError(APINotImplemented{
+			API:     "BucketCORS",
+			APIType: c.targetURL.Scheme + "://" + c.targetURL.Host,
+		})
+	}
+	return probe.NewError(e)
+}
+
+// corsBucket - the bucket of the URL, CORS is configured per bucket.
+func (c *s3Client) corsBucket() (string, *probe.Error) {
+	bucket, object := c.url2BucketAndObject()
+	if bucket == "" {
+		return "", probe.NewError(BucketNameEmpty{})
+	}
+	if object != "" {
+		return "", probe.NewError(BucketInvalid{Bucket: bucket + "/" + object})
+	}
+	return bucket, nil
+}
+
+// The minio-go client does not have a CORS API yet, the requests are
+// signed with its credentials and region by signedDo.
+
+// GetBucketCORS - returns the CORS configuration of the bucket.
+func (c *s3Client) GetBucketCORS() (*corsConfiguration, *probe.Error) {
+	bucket, err := c.corsBucket()
+	if err != nil {
+		return nil, err
+	}
+	resp, e := c.signedDo(context.Background(), http.MethodGet, bucket, "", url.Values{"cors": []string{""}}, nil, nil, 0)
+	if e != nil {
+		return nil, c.corsError(e, bucket)
+	}
+	defer resp.Body.Close()
+	cfg := &corsConfiguration{}
+	if e = xml.NewDecoder(resp.Body).Decode(cfg); e != nil {
+		return nil, probe.NewError(e)
+	}
+	return cfg, nil
+}
+
+// SetBucketCORS - replaces the CORS configuration of the bucket.
+func (c *s3Client) SetBucketCORS(cfg *corsConfiguration) *probe.Error {
+	bucket, err := c.corsBucket()
+	if err != nil {
+		return err
+	}
+	body, err := cfg.toXML()
+	if err != nil {
+		return err
+	}
+	sum := md5.Sum(body)
+	header := http.Header{}
+	header.Set("Content-Md5", base64.StdEncoding.EncodeToString(sum[:]))
+	header.Set("Content-Type", "application/xml")
+	resp, e := c.signedDo(context.Background(), http.MethodPut, bucket, "", url.Values{"cors": []string{""}},
+		header, bytes.NewReader(body), int64(len(body)))
+	if e != nil {
+		return c.corsError(e, bucket)
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// RemoveBucketCORS - removes the CORS configuration of the bucket.
+func (c *s3Client) RemoveBucketCORS() *probe.Error {
+	bucket, err := c.corsBucket()
+	if err != nil {
+		return err
+	}
+	resp, e := c.signedDo(context.Background(), http.MethodDelete, bucket, "", url.Values{"cors": []string{""}}, nil, nil, 0)
+	if e != nil {
+		return c.corsError(e, bucket)
+	}
+	resp.Body.Close()
+	return nil
+}
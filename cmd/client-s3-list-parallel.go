@@ -0,0 +1,102 @@
+/*
+ * MinIO Client (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/minio/mc/pkg/probe"
+	minio "github.com/minio/minio-go/v6"
+)
+
+// Objects of a prefix listed ahead of the objects sent, about a page of
+// a listing.
+const listPrefixBufferSize = 1000
+
+// isCommonPrefix - returns true if entry is a prefix returned by a
+// listing with a delimiter rather than an object.
+func (c *s3Client) isCommonPrefix(entry minio.ObjectInfo) bool {
+	return strings.HasSuffix(entry.Key, string(c.targetURL.Separator)) && entry.Size == 0 && entry.LastModified.IsZero()
+}
+
+// listRecursiveParallel - lists the objects under prefix of bucket
+// recursively, listing up to parallel of the prefixes found right under
+// prefix at once. Objects are sent to contentCh in the order of a serial
+// listing, the lexical order of their keys. Listing stops at the first
+// error, which is sent to contentCh.
+func (c *s3Client) listRecursiveParallel(contentCh chan *clientContent, bucket, prefix string, metadata bool, parallel int) {
+	doneCh := make(chan struct{})
+	defer close(doneCh)
+
+	var entries []minio.ObjectInfo
+	for entry := range c.listObjectWrapper(bucket, prefix, false, doneCh, metadata) {
+		if entry.Err != nil {
+			contentCh <- &clientContent{Err: probe.NewError(entry.Err)}
+			return
+		}
+		entries = append(entries, entry)
+	}
+	// Listings return the objects of a page before its prefixes.
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Key < entries[j].Key })
+
+	// Objects of every prefix, listed in the order of the prefixes.
+	listings := make([]chan minio.ObjectInfo, len(entries))
+	for i, entry := range entries {
+		if c.isCommonPrefix(entry) {
+			listings[i] = make(chan minio.ObjectInfo, listPrefixBufferSize)
+		}
+	}
+	go func() {
+		slots := make(chan struct{}, parallel)
+		for i, entry := range entries {
+			if listings[i] == nil {
+				continue
+			}
+			select {
+			case slots <- struct{}{}:
+			case <-doneCh:
+				return
+			}
+			go func(key string, objectCh chan<- minio.ObjectInfo) {
+				defer func() { <-slots }()
+				defer close(objectCh)
+				for object := range c.listObjectWrapper(bucket, key, true, doneCh, metadata) {
+					select {
+					case objectCh <- object:
+					case <-doneCh:
+						return
+					}
+				}
+			}(entry.Key, listings[i])
+		}
+	}()
+
+	for i, entry := range entries {
+		if listings[i] == nil {
+			contentCh <- c.objectInfo2ClientContent(bucket, entry)
+			continue
+		}
+		for object := range listings[i] {
+			if object.Err != nil {
+				contentCh <- &clientContent{Err: probe.NewError(object.Err)}
+				return
+			}
+			contentCh <- c.objectInfo2ClientContent(bucket, object)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+/*
+ * MinIO Client (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/minio/mc/pkg/probe"
+)
+
+// countingListHandler counts the listings served at once by handler,
+// each of which takes a while. Listings of the prefix denied fail.
+type countingListHandler struct {
+	handler *fakeS3Server
+	denied  string
+
+	mutex   *sync.Mutex
+	running int
+	peak    int
+}
+
+func (h *countingListHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet || r.URL.Path != "/bucket/" || r.URL.Query().Get("list-type") != "2" {
+		h.handler.ServeHTTP(w, r)
+		return
+	}
+	if h.denied != "" && r.URL.Query().Get("prefix") == h.denied {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`<Error><Code>AccessDenied</Code><Message>Access Denied.</Message></Error>`))
+		return
+	}
+	h.mutex.Lock()
+	if h.running++; h.running > h.peak {
+		h.peak = h.running
+	}
+	h.mutex.Unlock()
+	time.Sleep(20 * time.Millisecond)
+	h.handler.ServeHTTP(w, r)
+	h.mutex.Lock()
+	h.running--
+	h.mutex.Unlock()
+}
+
+func TestListRecursiveParallel(t *testing.T) {
+	objects := map[string][]byte{"bucket/a": []byte("top"), "bucket/z": []byte("top")}
+	for i := 0; i < 8; i++ {
+		for j := 0; j < 3; j++ {
+			objects[fmt.Sprintf("bucket/prefix%d/sub/object%d", i, j)] = []byte("data")
+		}
+		// Sorted between the prefixes, but returned before them.
+		objects[fmt.Sprintf("bucket/prefix%d-object", i)] = []byte("data")
+	}
+	handler := &countingListHandler{handler: newFakeS3Server(objects), mutex: &sync.Mutex{}}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+	defer withTestConfig(map[string]hostConfigV9{"myminio": testHost(server.URL)})()
+	defer func(parallel int) { globalListParallel = parallel }(globalListParallel)
+
+	list := func(parallel int) (keys []string, err *probe.Error) {
+		globalListParallel = parallel
+		handler.peak = 0
+		clnt, err := newClient("myminio/bucket/")
+		if err != nil {
+			t.Fatal(err)
+		}
+		for content := range clnt.List(true, false, false, DirNone) {
+			if content.Err != nil {
+				err = content.Err
+				continue
+			}
+			keys = append(keys, content.URL.Path)
+		}
+		return keys, err
+	}
+
+	serial, err := list(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(serial) != len(objects) || handler.peak != 1 {
+		t.Fatalf("Expected %d objects listed one listing at a time, got %d with %d listings at once", len(objects), len(serial), handler.peak)
+	}
+	parallel, err := list(4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(parallel, serial) {
+		t.Fatalf("Expected the objects of the serial listing\n%v\ngot\n%v", serial, parallel)
+	}
+	if handler.peak != 4 {
+		t.Fatalf("Expected 4 listings at once, got %d", handler.peak)
+	}
+
+	// The error of a prefix ends the listing.
+	handler.denied = "prefix3/"
+	keys, err := list(4)
+	if err == nil {
+		t.Fatal("Expected the error of prefix3/ to be returned")
+	}
+	for _, key := range keys {
+		if key >= "/bucket/prefix3/" {
+			t.Fatalf("Expected no object after the failed prefix, got %s", key)
+		}
+	}
+}
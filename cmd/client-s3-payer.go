@@ -0,0 +1,141 @@
+/*
+ * MinIO Client (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/minio/minio-go/v6/pkg/s3signer"
+)
+
+const (
+	amzRequestPayer = "X-Amz-Request-Payer"
+	// Hint added to the access denied errors of buckets without
+	// --request-payer.
+	requestPayerHint = "If the bucket is a requester pays bucket, retry with --request-payer."
+)
+
+// requestPayerTransport sends `x-amz-request-payer: requester` with every
+// request if payer is set, signed again to include the header. Without
+// it, access denied errors of requests to a bucket hint at the flag:
+// requester pays buckets deny all requests which do not accept the
+// charges.
+type requestPayerTransport struct {
+	host      string
+	accessKey string
+	secretKey string
+	payer     bool
+	transport http.RoundTripper
+}
+
+func (t requestPayerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.payer {
+		return t.transport.RoundTrip(t.withRequestPayer(req))
+	}
+
+	resp, err := t.transport.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusForbidden {
+		return resp, err
+	}
+	bucket := bucketFromRequest(req, t.host)
+	if bucket == "" {
+		return resp, nil
+	}
+	var body []byte
+	if resp.Body != nil {
+		var e error
+		body, e = ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if e != nil {
+			return nil, e
+		}
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	// Bodies of HEAD requests are empty, minio-go reports them as
+	// access denied as well.
+	errBody := struct {
+		XMLName    xml.Name `xml:"Error"`
+		Code       string
+		Message    string
+		BucketName string `xml:",omitempty"`
+		Key        string `xml:",omitempty"`
+		RequestID  string `xml:"RequestId,omitempty"`
+		HostID     string `xml:"HostId,omitempty"`
+	}{Code: "AccessDenied", Message: "Access Denied.", BucketName: bucket}
+	if len(body) > 0 && (xml.Unmarshal(body, &errBody) != nil || errBody.Code != "AccessDenied") {
+		return resp, nil
+	}
+	errBody.Message = strings.TrimSpace(errBody.Message)
+	if !strings.HasSuffix(errBody.Message, ".") {
+		errBody.Message += "."
+	}
+	errBody.Message += " " + requestPayerHint
+	hinted, e := xml.Marshal(errBody)
+	if e != nil {
+		return resp, nil
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(hinted))
+	resp.ContentLength = int64(len(hinted))
+	resp.Header.Set("Content-Length", strconv.Itoa(len(hinted)))
+	resp.Header.Set("Content-Type", "application/xml")
+	return resp, nil
+}
+
+// withRequestPayer returns a copy of req with `x-amz-request-payer:
+// requester`, signed again at the time of its signature. Streaming
+// signatures chain the signature of the headers into every chunk, the
+// header of such requests is not signed.
+func (t requestPayerTransport) withRequestPayer(req *http.Request) *http.Request {
+	withPayer := req.Clone(req.Context())
+	withPayer.Header.Set(amzRequestPayer, "requester")
+
+	authorization := req.Header.Get("Authorization")
+	switch {
+	case strings.HasPrefix(authorization, signV4Algorithm+" "):
+		if strings.HasPrefix(req.Header.Get("X-Amz-Content-Sha256"), "STREAMING-") {
+			return withPayer
+		}
+		signedAt, e := time.Parse(iso8601DateFormat, req.Header.Get("X-Amz-Date"))
+		if e != nil {
+			return withPayer
+		}
+		// resignV4 signs the headers listed by the original signature.
+		parts := strings.Split(authorization, ",")
+		for i, part := range parts {
+			if trimmed := strings.TrimSpace(part); strings.HasPrefix(trimmed, "SignedHeaders=") {
+				parts[i] = " " + trimmed + ";" + strings.ToLower(amzRequestPayer)
+			}
+		}
+		withPayer.Header.Set("Authorization", strings.Join(parts, ","))
+		if signed := resignV4(withPayer, t.accessKey, t.secretKey, signedAt); signed != nil {
+			return signed
+		}
+		withPayer.Header.Set("Authorization", authorization)
+	case strings.HasPrefix(authorization, "AWS "):
+		// Signature version 2 signs all x-amz- headers.
+		virtualHost := req.URL.Host != t.host && strings.HasSuffix(req.URL.Host, "."+t.host)
+		return s3signer.SignV2(*withPayer, t.accessKey, t.secretKey, virtualHost)
+	}
+	return withPayer
+}
@@ -0,0 +1,120 @@
+/*
+ * MinIO Client (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/minio/mc/pkg/probe"
+)
+
+// requesterPaysHandler denies the requests of handler which do not
+// accept the charges, as requester pays buckets do. The signature
+// version 4 of accepted requests is verified to cover the header.
+type requesterPaysHandler struct {
+	t       *testing.T
+	handler *fakeS3Server
+}
+
+func (h requesterPaysHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if _, ok := r.URL.Query()["location"]; ok {
+		h.handler.ServeHTTP(w, r)
+		return
+	}
+	if r.Header.Get(amzRequestPayer) != "requester" {
+		w.WriteHeader(http.StatusForbidden)
+		if r.Method != http.MethodHead {
+			w.Write([]byte(`<Error><Code>AccessDenied</Code><Message>Access Denied</Message></Error>`))
+		}
+		return
+	}
+	if authorization := r.Header.Get("Authorization"); strings.HasPrefix(authorization, signV4Algorithm+" ") {
+		signedAt, _ := time.Parse(iso8601DateFormat, r.Header.Get("X-Amz-Date"))
+		signed := resignV4(r, testAccessKey, testSecretKey, signedAt)
+		if !strings.Contains(authorization, strings.ToLower(amzRequestPayer)) || signed == nil || signed.Header.Get("Authorization") != authorization {
+			h.t.Errorf("Expected %s %s to be signed with the request payer header, got %s", r.Method, r.URL, authorization)
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+	}
+	h.handler.ServeHTTP(w, r)
+}
+
+func TestRequestPayer(t *testing.T) {
+	handler := requesterPaysHandler{t: t, handler: newFakeS3Server(map[string][]byte{"bucket/object": []byte("data")})}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+	cfg := newConfigV9()
+	for alias, api := range map[string]string{"v4": "S3v4", "v2": "S3v2"} {
+		host := testHost(server.URL)
+		host.API = api
+		cfg.Hosts[alias] = host
+		payer := host
+		// Saved by config host add --request-payer.
+		payer.RequestPayer = true
+		cfg.Hosts[alias+"payer"] = payer
+	}
+	defer withTestConfig(cfg.Hosts)()
+
+	read := func(alias string) (data []byte, err *probe.Error) {
+		clnt, err := newClient(alias + "/bucket/object")
+		if err != nil {
+			t.Fatal(err)
+		}
+		for content := range clnt.List(false, false, false, DirNone) {
+			if content.Err != nil {
+				return nil, content.Err
+			}
+		}
+		reader, err := clnt.Get(nil)
+		if err != nil {
+			return nil, err
+		}
+		defer reader.Close()
+		data, e := ioutil.ReadAll(reader)
+		return data, probe.NewError(e)
+	}
+
+	for _, alias := range []string{"v4", "v2"} {
+		// Denied requests hint at the flag.
+		if _, err := read(alias); err == nil || !strings.Contains(err.ToGoError().Error(), requestPayerHint) {
+			t.Fatalf("%s: expected the access denied error to hint at --request-payer, got %v", alias, err)
+		}
+		data, err := read(alias + "payer")
+		if err != nil {
+			t.Fatalf("%s: expected requests accepting the charges to succeed, got %v", alias, err)
+		}
+		if !bytes.Equal(data, []byte("data")) {
+			t.Fatalf("%s: expected the object to be read, got %q", alias, data)
+		}
+	}
+
+	// Errors of other requests are left untouched.
+	clnt, err := newClient("v4payer/bucket/missing")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = clnt.Stat(false, false, false, nil); err == nil || strings.Contains(err.ToGoError().Error(), requestPayerHint) {
+		t.Fatalf("Expected the error of an allowed request to have no hint, got %v", err)
+	}
+}
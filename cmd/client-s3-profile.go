@@ -0,0 +1,223 @@
+/*
+ * MinIO Client (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net/http/httptrace"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fatih/color"
+	json "github.com/minio/mc/pkg/colorjson"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/minio/pkg/console"
+)
+
+// Requests are profiled one by one up to this many requests, beyond
+// only the aggregate of all requests is printed.
+const profileMaxRequests = 10
+
+// profileTrace holds the times of the phases of a request.
+type profileTrace struct {
+	mutex        sync.Mutex
+	start        time.Time
+	dnsStart     time.Time
+	dnsDone      time.Time
+	connectStart time.Time
+	connectDone  time.Time
+	tlsStart     time.Time
+	tlsDone      time.Time
+	gotConn      time.Time
+	wroteRequest time.Time
+	firstByte    time.Time
+	end          time.Time
+}
+
+// mark - sets t to the current time, the first call wins.
+func (p *profileTrace) mark(t *time.Time) {
+	p.mutex.Lock()
+	if t.IsZero() {
+		*t = nowFunc()
+	}
+	p.mutex.Unlock()
+}
+
+// clientTrace - adds the hooks timing the phases of a request to trace.
+func (p *profileTrace) clientTrace(trace *httptrace.ClientTrace) {
+	trace.DNSStart = func(httptrace.DNSStartInfo) { p.mark(&p.dnsStart) }
+	trace.DNSDone = func(httptrace.DNSDoneInfo) { p.mark(&p.dnsDone) }
+	trace.ConnectStart = func(string, string) { p.mark(&p.connectStart) }
+	trace.ConnectDone = func(string, string, error) { p.mark(&p.connectDone) }
+	trace.TLSHandshakeStart = func() { p.mark(&p.tlsStart) }
+	trace.TLSHandshakeDone = func(tls.ConnectionState, error) { p.mark(&p.tlsDone) }
+	trace.WroteRequest = func(httptrace.WroteRequestInfo) { p.mark(&p.wroteRequest) }
+	trace.GotFirstResponseByte = func() { p.mark(&p.firstByte) }
+	gotConn := trace.GotConn
+	trace.GotConn = func(info httptrace.GotConnInfo) {
+		p.mark(&p.gotConn)
+		if gotConn != nil {
+			gotConn(info)
+		}
+	}
+}
+
+// span - returns the time between from and to, zero when a phase did
+// not happen, e.g. no DNS lookup on reused connections.
+func span(from, to time.Time) time.Duration {
+	if from.IsZero() || to.IsZero() || to.Before(from) {
+		return 0
+	}
+	return to.Sub(from)
+}
+
+// profileMessage container for the time breakdown of requests printed
+// with --profile. The transfer is the time spent sending the request body
+// and receiving the response body.
+type profileMessage struct {
+	Status    string        `json:"status"`
+	Request   string        `json:"request,omitempty"`
+	Requests  int           `json:"requests"`
+	DNS       time.Duration `json:"dns"`
+	Connect   time.Duration `json:"connect"`
+	TLS       time.Duration `json:"tls"`
+	FirstByte time.Duration `json:"firstByte"`
+	Transfer  time.Duration `json:"transfer"`
+	Total     time.Duration `json:"total"`
+}
+
+// message - returns the time breakdown of the traced request.
+func (p *profileTrace) message(request string) profileMessage {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return profileMessage{
+		Request:   request,
+		Requests:  1,
+		DNS:       span(p.dnsStart, p.dnsDone),
+		Connect:   span(p.connectStart, p.connectDone),
+		TLS:       span(p.tlsStart, p.tlsDone),
+		FirstByte: span(p.wroteRequest, p.firstByte),
+		Transfer:  span(p.gotConn, p.wroteRequest) + span(p.firstByte, p.end),
+		Total:     span(p.start, p.end),
+	}
+}
+
+// add - accounts the phases of m to the aggregate p.
+func (p *profileMessage) add(m profileMessage) {
+	p.Requests += m.Requests
+	p.DNS += m.DNS
+	p.Connect += m.Connect
+	p.TLS += m.TLS
+	p.FirstByte += m.FirstByte
+	p.Transfer += m.Transfer
+	p.Total += m.Total
+}
+
+// String colorized profile message.
+func (p profileMessage) String() string {
+	title := p.Request
+	if title == "" {
+		title = fmt.Sprintf("%d requests", p.Requests)
+	}
+	rows := [][2]string{
+		{"DNS", p.DNS.String()},
+		{"Connect", p.Connect.String()},
+		{"TLS", p.TLS.String()},
+		{"First byte", p.FirstByte.String()},
+		{"Transfer", p.Transfer.String()},
+		{"Total", p.Total.String()},
+	}
+	lines := []string{console.Colorize("ProfileRequest", title)}
+	for _, row := range rows {
+		lines = append(lines, fmt.Sprintf("  %s %s", console.Colorize("StatsKey", fmt.Sprintf("%-10s:", row[0])), row[1]))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// JSON jsonified profile message.
+func (p profileMessage) JSON() string {
+	p.Status = "success"
+	profileJSONBytes, e := json.MarshalIndent(p, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(profileJSONBytes)
+}
+
+// requestProfiles collects the time breakdown of completed requests.
+type requestProfiles struct {
+	mutex    sync.Mutex
+	messages []profileMessage
+}
+
+// Requests profiled for --profile.
+var globalRequestProfiles = &requestProfiles{}
+
+func (r *requestProfiles) record(m profileMessage) {
+	r.mutex.Lock()
+	r.messages = append(r.messages, m)
+	r.mutex.Unlock()
+}
+
+// summary - returns a message per request, or their aggregate only when
+// too many requests were sent to be read one by one.
+func (r *requestProfiles) summary() []profileMessage {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if len(r.messages) <= profileMaxRequests {
+		return append([]profileMessage{}, r.messages...)
+	}
+	var aggregate profileMessage
+	for _, m := range r.messages {
+		aggregate.add(m)
+	}
+	return []profileMessage{aggregate}
+}
+
+// printProfile prints the profiled requests when --profile is set.
+func printProfile() {
+	if !globalProfile {
+		return
+	}
+	console.SetColor("StatsKey", color.New(color.FgCyan, color.Bold))
+	console.SetColor("ProfileRequest", color.New(color.Bold))
+	for _, msg := range globalRequestProfiles.summary() {
+		printMsg(msg)
+	}
+}
+
+// profileBody ends the trace of a request once its response body is
+// read or closed.
+type profileBody struct {
+	io.ReadCloser
+	done func()
+}
+
+func (b profileBody) Read(p []byte) (int, error) {
+	n, e := b.ReadCloser.Read(p)
+	if e == io.EOF {
+		b.done()
+	}
+	return n, e
+}
+
+func (b profileBody) Close() error {
+	b.done()
+	return b.ReadCloser.Close()
+}
@@ -0,0 +1,181 @@
+/*
+ * MinIO Client (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+
+	minio "github.com/minio/minio-go/v6"
+)
+
+// bucketRedirect is the regional endpoint a bucket was redirected to.
+type bucketRedirect struct {
+	Region   string
+	Endpoint string
+}
+
+// bucketRedirects caches redirects per original host and bucket, so
+// that clients created later go straight to the correct region.
+var bucketRedirects = struct {
+	sync.RWMutex
+	m map[string]bucketRedirect
+}{m: make(map[string]bucketRedirect)}
+
+func getBucketRedirect(host, bucket string) (bucketRedirect, bool) {
+	bucketRedirects.RLock()
+	defer bucketRedirects.RUnlock()
+	redirect, ok := bucketRedirects.m[host+"/"+bucket]
+	return redirect, ok
+}
+
+func setBucketRedirect(host, bucket string, redirect bucketRedirect) {
+	bucketRedirects.Lock()
+	defer bucketRedirects.Unlock()
+	// Keep an already known endpoint when only the region is reported.
+	if redirect.Endpoint == "" {
+		redirect.Endpoint = bucketRedirects.m[host+"/"+bucket].Endpoint
+	}
+	bucketRedirects.m[host+"/"+bucket] = redirect
+}
+
+// redirectTransport records the region and endpoint of buckets for
+// which the server answers with '301 Moved Permanently'. minio-go only
+// exposes the region of such responses, the endpoint is only present
+// in the response body.
+type redirectTransport struct {
+	host      string
+	transport http.RoundTripper
+}
+
+func (t redirectTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.transport.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusMovedPermanently {
+		return resp, err
+	}
+
+	var errBody struct {
+		Bucket   string
+		Endpoint string
+		Region   string
+	}
+	if resp.Body != nil {
+		body, e := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if e != nil {
+			return nil, e
+		}
+		resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+		// Error body is optional, HEAD requests have none.
+		xml.Unmarshal(body, &errBody)
+	}
+
+	bucket := errBody.Bucket
+	if bucket == "" {
+		bucket = bucketFromRequest(req, t.host)
+	}
+	region := resp.Header.Get("x-amz-bucket-region")
+	if region == "" {
+		region = errBody.Region
+	}
+	if bucket != "" && (region != "" || errBody.Endpoint != "") {
+		endpoint := strings.TrimPrefix(errBody.Endpoint, bucket+".")
+		setBucketRedirect(t.host, bucket, bucketRedirect{Region: region, Endpoint: endpoint})
+	}
+	return resp, nil
+}
+
+// bucketFromRequest extracts the bucket name from a virtual host style
+// or path style request sent to host.
+func bucketFromRequest(req *http.Request, host string) string {
+	if req.URL.Host != host && strings.HasSuffix(req.URL.Host, "."+host) {
+		return strings.TrimSuffix(req.URL.Host, "."+host)
+	}
+	return strings.SplitN(strings.TrimPrefix(req.URL.Path, "/"), "/", 2)[0]
+}
+
+// redirected reports whether e means bucket lives in another region,
+// in which case the client is switched over to the regional endpoint
+// so that the failed request can be retried once.
+func (c *s3Client) redirected(bucket string, e error) bool {
+	if bucket == "" || c.config == nil {
+		return false
+	}
+	errResponse := minio.ToErrorResponse(e)
+	switch {
+	case errResponse.StatusCode == http.StatusMovedPermanently:
+	case errResponse.Code == "PermanentRedirect":
+	case errResponse.Code == "AuthorizationHeaderMalformed":
+	default:
+		return false
+	}
+	if errResponse.Region != "" {
+		setBucketRedirect(c.hostName, bucket, bucketRedirect{Region: errResponse.Region})
+	}
+	redirect, ok := getBucketRedirect(c.hostName, bucket)
+	if !ok {
+		return false
+	}
+	if redirect == c.getRedirect() {
+		// Already switched over, by another transfer sharing the client
+		// if the request was sent before. Requests are retried once only.
+		return true
+	}
+	clnt, err := s3New(c.config)
+	if err != nil {
+		return false
+	}
+	s3Clnt := clnt.(*s3Client)
+	c.endpointMutex.Lock()
+	defer c.endpointMutex.Unlock()
+	c.api = s3Clnt.api
+	c.objectAPI = s3Clnt.objectAPI
+	c.virtualStyle = s3Clnt.virtualStyle
+	c.redirect = s3Clnt.redirect
+	return true
+}
+
+// getAPI - returns the client of the bucket operations.
+func (c *s3Client) getAPI() *minio.Client {
+	c.endpointMutex.RLock()
+	defer c.endpointMutex.RUnlock()
+	return c.api
+}
+
+// getObjectAPI - returns the client of the object uploads and downloads,
+// which go through the accelerate endpoint if enabled.
+func (c *s3Client) getObjectAPI() *minio.Client {
+	c.endpointMutex.RLock()
+	defer c.endpointMutex.RUnlock()
+	return c.objectAPI
+}
+
+func (c *s3Client) getVirtualStyle() bool {
+	c.endpointMutex.RLock()
+	defer c.endpointMutex.RUnlock()
+	return c.virtualStyle
+}
+
+func (c *s3Client) getRedirect() bucketRedirect {
+	c.endpointMutex.RLock()
+	defer c.endpointMutex.RUnlock()
+	return c.redirect
+}
@@ -0,0 +1,102 @@
+/*
+ * MinIO Client (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"io"
+
+	"github.com/minio/minio-go/v6"
+)
+
+// Uploads of seekable sources interrupted in the middle are sent again
+// from the start, up to this number of uploads in all.
+const putRewindAttempts = 3
+
+// sentCounter counts the bytes read from a source which cannot be read
+// again, reporting them to progress. Unlike the hooks of minio-go it is
+// no io.Seeker, so that minio-go does not replay what is left of the
+// source when a request fails.
+type sentCounter struct {
+	reader   io.Reader
+	progress io.Reader
+	n        int64
+	eof      bool
+}
+
+func (s *sentCounter) Read(b []byte) (int, error) {
+	n, e := s.reader.Read(b)
+	s.n += int64(n)
+	s.eof = s.eof || e == io.EOF
+	if s.progress != nil && n > 0 {
+		s.progress.Read(b[:n])
+	}
+	return n, e
+}
+
+// isInterruptedUpload - reports whether e means the connection failed
+// while uploading, rather than the server refusing the upload.
+func isInterruptedUpload(ctx context.Context, e error) bool {
+	return ctx.Err() == nil && minio.ToErrorResponse(e).StatusCode == 0
+}
+
+// putObjectRewind - uploads reader with minio-go, sending seekable
+// sources again from where they started when the upload is interrupted
+// or redirected to the region of the bucket. Pipes are files too, they
+// are told apart by failing to seek. Sources which cannot be read again
+// are not retried once part of them was sent, which fails with
+// NonSeekableRetry instead of uploading what is left of them.
+func (c *s3Client) putObjectRewind(ctx context.Context, bucket, object string, reader io.Reader, size int64, opts minio.PutObjectOptions) (int64, error) {
+	seeker, seekable := reader.(io.Seeker)
+	var start int64
+	if seekable {
+		var e error
+		if start, e = seeker.Seek(0, io.SeekCurrent); e != nil {
+			seekable = false
+		}
+	}
+
+	if !seekable {
+		counter := &sentCounter{reader: reader, progress: opts.Progress}
+		opts.Progress = nil
+		n, e := c.getObjectAPI().PutObjectWithContext(ctx, bucket, object, counter, size, opts)
+		// Sources ending early were not interrupted.
+		if e != nil && counter.eof && size >= 0 && counter.n < size {
+			return n, UnexpectedEOF{TotalSize: size, TotalWritten: counter.n}
+		}
+		if e != nil && counter.n > 0 && isInterruptedUpload(ctx, e) {
+			return n, NonSeekableRetry{Sent: counter.n}
+		}
+		if e != nil && counter.n == 0 && c.redirected(bucket, e) {
+			return c.getObjectAPI().PutObjectWithContext(ctx, bucket, object, counter, size, opts)
+		}
+		return n, e
+	}
+
+	for attempt := 1; ; attempt++ {
+		n, e := c.getObjectAPI().PutObjectWithContext(ctx, bucket, object, reader, size, opts)
+		if e == nil || attempt == putRewindAttempts {
+			return n, e
+		}
+		if !isInterruptedUpload(ctx, e) && !(n == 0 && c.redirected(bucket, e)) {
+			return n, e
+		}
+		if _, se := seeker.Seek(start, io.SeekStart); se != nil {
+			return n, e
+		}
+	}
+}
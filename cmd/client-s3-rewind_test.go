@@ -0,0 +1,106 @@
+/*
+ * MinIO Client (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/minio-go/v6"
+)
+
+// interruptingHandler drops the connection of the next interrupts
+// uploads after reading the start of their body.
+type interruptingHandler struct {
+	handler    *fakeS3Server
+	interrupts *int32
+	uploads    *int32
+}
+
+func (h interruptingHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodPut {
+		atomic.AddInt32(h.uploads, 1)
+		if atomic.AddInt32(h.interrupts, -1) >= 0 {
+			r.Body.Read(make([]byte, 10))
+			conn, _, e := w.(http.Hijacker).Hijack()
+			if e == nil {
+				conn.Close()
+			}
+			return
+		}
+	}
+	h.handler.ServeHTTP(w, r)
+}
+
+func TestPutRewind(t *testing.T) {
+	var interrupts, uploads int32
+	handler := newFakeS3Server(nil)
+	server := httptest.NewServer(interruptingHandler{handler: handler, interrupts: &interrupts, uploads: &uploads})
+	defer server.Close()
+	defer withTestConfig(map[string]hostConfigV9{"myminio": testHost(server.URL)})()
+	// Without the retries of minio-go, which replays seekable bodies too.
+	defer func(maxRetry int) { minio.MaxRetry = maxRetry }(minio.MaxRetry)
+	minio.MaxRetry = 1
+
+	data := bytes.Repeat([]byte("0123456789"), 100)
+	put := func(object string, reader io.Reader) *probe.Error {
+		clnt, err := newClient("myminio/bucket/" + object)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = clnt.Put(context.Background(), reader, int64(len(data)), map[string]string{}, nil, nil)
+		return err
+	}
+
+	// Seekable sources are sent again from the start.
+	atomic.StoreInt32(&interrupts, 1)
+	if err := put("seekable", bytes.NewReader(data)); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(handler.objects["bucket/seekable"], data) || atomic.LoadInt32(&uploads) != 2 {
+		t.Fatalf("Expected the whole object in a second upload, got %d bytes in %d uploads", len(handler.objects["bucket/seekable"]), uploads)
+	}
+
+	// Others fail instead of uploading what is left of them.
+	atomic.StoreInt32(&interrupts, 1)
+	atomic.StoreInt32(&uploads, 0)
+	err := put("stream", io.MultiReader(bytes.NewReader(data)))
+	if err == nil {
+		t.Fatal("Expected the interrupted upload of a stream to fail")
+	}
+	if _, ok := err.ToGoError().(NonSeekableRetry); !ok {
+		t.Fatalf("Expected NonSeekableRetry, got %v", err)
+	}
+	if _, ok := handler.objects["bucket/stream"]; ok || atomic.LoadInt32(&uploads) != 1 {
+		t.Fatalf("Expected no upload after the interrupted one, got %d uploads", uploads)
+	}
+
+	// Streams ending early are not taken for interrupted uploads.
+	atomic.StoreInt32(&interrupts, 0)
+	if err = put("short", io.MultiReader(bytes.NewReader(data[:500]))); err == nil {
+		t.Fatal("Expected the upload of a short stream to fail")
+	}
+	if _, ok := err.ToGoError().(UnexpectedEOF); !ok {
+		t.Fatalf("Expected UnexpectedEOF, got %v", err)
+	}
+}
@@ -0,0 +1,294 @@
+/*
+ * MinIO Client (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/minio/minio-go/v6/pkg/s3signer"
+	"github.com/minio/minio-go/v6/pkg/s3utils"
+)
+
+const (
+	signV4Algorithm   = "AWS4-HMAC-SHA256"
+	iso8601DateFormat = "20060102T150405Z"
+
+	// Requests signed further away from the server clock are rejected.
+	maxClockSkew = 15 * time.Minute
+)
+
+// clockOffset is the difference between the server clock and the
+// local clock in nanoseconds, learnt from RequestTimeTooSkewed errors
+// or the Date of rejected HEAD requests, and applied to all requests signed for the lifetime of the process.
+var clockOffset int64
+
+// signingTime returns the current time corrected by the clock offset.
+func signingTime() time.Time {
+	return UTCNow().Add(time.Duration(atomic.LoadInt64(&clockOffset)))
+}
+
+// skewTransport re-signs requests with the corrected time once the
+// local clock is known to be off, and retries a request rejected with
+// RequestTimeTooSkewed once. Responses to HEAD requests have no body to
+// tell the error, those rejected with a Date too far from the local
+// clock are retried alike.
+type skewTransport struct {
+	host      string
+	accessKey string
+	secretKey string
+	transport http.RoundTripper
+}
+
+func (t skewTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if atomic.LoadInt64(&clockOffset) != 0 {
+		if signed := t.resign(req, signingTime()); signed != nil {
+			req = signed
+		}
+	}
+
+	resp, err := t.transport.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusForbidden || resp.Body == nil {
+		return resp, err
+	}
+
+	body, e := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if e != nil {
+		return nil, e
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	var errBody struct {
+		Code       string
+		ServerTime string
+	}
+	skewed := xml.Unmarshal(body, &errBody) == nil && errBody.Code == "RequestTimeTooSkewed"
+	serverTime, e := http.ParseTime(resp.Header.Get("Date"))
+	if skewed && e != nil {
+		serverTime, e = time.Parse(time.RFC3339, errBody.ServerTime)
+	}
+	if !skewed && len(body) == 0 && e == nil {
+		skew := serverTime.Sub(signingTime())
+		skewed = skew > maxClockSkew || skew < -maxClockSkew
+	}
+	if !skewed || e != nil {
+		return resp, nil
+	}
+	atomic.StoreInt64(&clockOffset, int64(serverTime.Sub(UTCNow())))
+
+	// Requests with a body can only be retried if it can be replayed.
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return resp, nil
+	}
+	signed := t.resign(req, signingTime())
+	if signed == nil {
+		return resp, nil
+	}
+	if req.GetBody != nil {
+		if signed.Body, e = req.GetBody(); e != nil {
+			return resp, nil
+		}
+	}
+	resp.Body.Close()
+	return t.transport.RoundTrip(signed)
+}
+
+// resign returns a copy of req signed at time now, or nil if req can
+// not be signed again. Streaming signatures chain the signature of the
+// headers into every chunk, such requests are left untouched.
+func (t skewTransport) resign(req *http.Request, now time.Time) *http.Request {
+	authorization := req.Header.Get("Authorization")
+	switch {
+	case strings.HasPrefix(authorization, signV4Algorithm+" "):
+		if strings.HasPrefix(req.Header.Get("X-Amz-Content-Sha256"), "STREAMING-") {
+			return nil
+		}
+		return resignV4(req, t.accessKey, t.secretKey, now)
+	case strings.HasPrefix(authorization, "AWS "):
+		signed := req.Clone(req.Context())
+		signed.Header.Set("Date", now.Format(http.TimeFormat))
+		virtualHost := req.URL.Host != t.host && strings.HasSuffix(req.URL.Host, "."+t.host)
+		return s3signer.SignV2(*signed, t.accessKey, t.secretKey, virtualHost)
+	}
+	return nil
+}
+
+// resignV4 returns a copy of req with its AWS Signature Version 4
+// computed again at time now, signing the same headers and region as
+// the original signature.
+func resignV4(req *http.Request, accessKey, secretKey string, now time.Time) *http.Request {
+	var region string
+	var signedHeaders []string
+	for _, part := range strings.Split(strings.TrimPrefix(req.Header.Get("Authorization"), signV4Algorithm+" "), ",") {
+		part = strings.TrimSpace(part)
+		switch {
+		case strings.HasPrefix(part, "Credential="):
+			// Credential=<access-key>/<date>/<region>/s3/aws4_request
+			scope := strings.Split(strings.TrimPrefix(part, "Credential="), "/")
+			if len(scope) != 5 {
+				return nil
+			}
+			region = scope[2]
+		case strings.HasPrefix(part, "SignedHeaders="):
+			signedHeaders = strings.Split(strings.TrimPrefix(part, "SignedHeaders="), ";")
+		}
+	}
+	if len(signedHeaders) == 0 {
+		return nil
+	}
+	sort.Strings(signedHeaders)
+
+	signed := req.Clone(req.Context())
+	signed.Header.Set("X-Amz-Date", now.Format(iso8601DateFormat))
+
+	var canonicalHeaders bytes.Buffer
+	for _, k := range signedHeaders {
+		canonicalHeaders.WriteString(k + ":")
+		if k == "host" {
+			host := signed.Host
+			if host == "" {
+				host = signed.URL.Host
+			}
+			canonicalHeaders.WriteString(host)
+		} else {
+			values := signed.Header[http.CanonicalHeaderKey(k)]
+			for i, v := range values {
+				if i > 0 {
+					canonicalHeaders.WriteByte(',')
+				}
+				canonicalHeaders.WriteString(strings.Join(strings.Fields(v), " "))
+			}
+		}
+		canonicalHeaders.WriteByte('\n')
+	}
+
+	canonicalRequest := strings.Join([]string{
+		signed.Method,
+		s3utils.EncodePath(signed.URL.Path),
+		strings.Replace(signed.URL.Query().Encode(), "+", "%20", -1),
+		canonicalHeaders.String(),
+		strings.Join(signedHeaders, ";"),
+		signed.Header.Get("X-Amz-Content-Sha256"),
+	}, "\n")
+	canonicalRequestSum := sha256.Sum256([]byte(canonicalRequest))
+	scope := strings.Join([]string{now.Format("20060102"), region, "s3", "aws4_request"}, "/")
+	stringToSign := signV4Algorithm + "\n" + now.Format(iso8601DateFormat) + "\n" +
+		scope + "\n" + hex.EncodeToString(canonicalRequestSum[:])
+
+	signed.Header.Set("Authorization", signV4Algorithm+
+		" Credential="+s3signer.GetCredential(accessKey, region, now)+
+		", SignedHeaders="+strings.Join(signedHeaders, ";")+
+		", Signature="+s3signer.PostPresignSignatureV4(stringToSign, now, secretKey, region))
+	return signed
+}
+
+// represignV4 returns a copy of the presigned URL u with its query
+// signature computed again at time now, keeping its expiry, or nil if
+// u is not presigned with AWS Signature Version 4 for the host only.
+func represignV4(u *url.URL, method, secretKey string, now time.Time) *url.URL {
+	query := u.Query()
+	if query.Get("X-Amz-Algorithm") != signV4Algorithm || query.Get("X-Amz-SignedHeaders") != "host" {
+		return nil
+	}
+	// X-Amz-Credential=<access-key>/<date>/<region>/s3/aws4_request
+	scope := strings.Split(query.Get("X-Amz-Credential"), "/")
+	if len(scope) != 5 {
+		return nil
+	}
+	accessKey, region := scope[0], scope[2]
+
+	signed := *u
+	query.Del("X-Amz-Signature")
+	query.Set("X-Amz-Date", now.Format(iso8601DateFormat))
+	query.Set("X-Amz-Credential", s3signer.GetCredential(accessKey, region, now))
+	signed.RawQuery = query.Encode()
+
+	canonicalRequest := strings.Join([]string{
+		method,
+		s3utils.EncodePath(signed.Path),
+		strings.Replace(signed.RawQuery, "+", "%20", -1),
+		"host:" + signed.Host + "\n",
+		"host",
+		"UNSIGNED-PAYLOAD",
+	}, "\n")
+	canonicalRequestSum := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := signV4Algorithm + "\n" + now.Format(iso8601DateFormat) + "\n" +
+		strings.Join([]string{now.Format("20060102"), region, "s3", "aws4_request"}, "/") + "\n" +
+		hex.EncodeToString(canonicalRequestSum[:])
+	signed.RawQuery += "&X-Amz-Signature=" + s3signer.PostPresignSignatureV4(stringToSign, now, secretKey, region)
+	return &signed
+}
+
+// represignPostPolicyV4 signs the form data of a presigned POST policy
+// again at time now, replacing the date and credential of its policy.
+// It returns false if the form data is not signed with AWS Signature
+// Version 4, the form data is left untouched then.
+func represignPostPolicyV4(formData map[string]string, secretKey string, now time.Time) bool {
+	if formData["x-amz-algorithm"] != signV4Algorithm {
+		return false
+	}
+	scope := strings.Split(formData["x-amz-credential"], "/")
+	if len(scope) != 5 {
+		return false
+	}
+	policyJSON, e := base64.StdEncoding.DecodeString(formData["policy"])
+	if e != nil {
+		return false
+	}
+	var policy struct {
+		Expiration string          `json:"expiration"`
+		Conditions [][]interface{} `json:"conditions"`
+	}
+	if json.Unmarshal(policyJSON, &policy) != nil {
+		return false
+	}
+
+	date := now.Format(iso8601DateFormat)
+	credential := s3signer.GetCredential(scope[0], scope[2], now)
+	for _, condition := range policy.Conditions {
+		if len(condition) != 3 {
+			continue
+		}
+		switch condition[1] {
+		case "$x-amz-date":
+			condition[2] = date
+		case "$x-amz-credential":
+			condition[2] = credential
+		}
+	}
+	if policyJSON, e = json.Marshal(policy); e != nil {
+		return false
+	}
+
+	formData["policy"] = base64.StdEncoding.EncodeToString(policyJSON)
+	formData["x-amz-date"] = date
+	formData["x-amz-credential"] = credential
+	formData["x-amz-signature"] = s3signer.PostPresignSignatureV4(formData["policy"], now, secretKey, scope[2])
+	return true
+}
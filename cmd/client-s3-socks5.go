@@ -0,0 +1,95 @@
+/*
+ * MinIO Client (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/minio/mc/pkg/probe"
+	"golang.org/x/net/proxy"
+)
+
+// parseSOCKS5Proxy - parses a SOCKS5 proxy given as host:port or as
+// socks5://[user:password@]host:port.
+func parseSOCKS5Proxy(proxyURL string) (*url.URL, *probe.Error) {
+	if !strings.Contains(proxyURL, "://") {
+		proxyURL = "socks5://" + proxyURL
+	}
+	u, e := url.Parse(proxyURL)
+	if e != nil {
+		return nil, probe.NewError(e)
+	}
+	if u.Scheme != "socks5" && u.Scheme != "socks5h" {
+		return nil, probe.NewError(errors.New("unsupported proxy scheme `" + u.Scheme + "`, socks5 is expected"))
+	}
+	if u.Hostname() == "" || u.Port() == "" || (u.Path != "" && u.Path != "/") {
+		return nil, probe.NewError(errors.New("a SOCKS5 proxy of the form host:port is expected"))
+	}
+	return u, nil
+}
+
+// socks5ProxyFromEnvironment - returns the proxy of ALL_PROXY if it is
+// a SOCKS5 proxy. Proxies of other schemes are left to the transport.
+func socks5ProxyFromEnvironment() string {
+	for _, env := range []string{"ALL_PROXY", "all_proxy"} {
+		if proxyURL := os.Getenv(env); proxyURL != "" {
+			if strings.HasPrefix(proxyURL, "socks5://") || strings.HasPrefix(proxyURL, "socks5h://") {
+				return proxyURL
+			}
+			return ""
+		}
+	}
+	return ""
+}
+
+// transportProxy - returns the HTTP proxy of the transport of a client,
+// none if connections are dialed through a SOCKS5 proxy.
+func transportProxy(config *Config) func(*http.Request) (*url.URL, error) {
+	if config.SOCKS5Proxy != nil {
+		return nil
+	}
+	return http.ProxyFromEnvironment
+}
+
+// newDialContext - returns the function connections of a client are
+// dialed with, through the SOCKS5 proxy of config if any.
+func newDialContext(config *Config) (func(ctx context.Context, network, address string) (net.Conn, error), *probe.Error) {
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+	}
+	if config.SOCKS5Proxy == nil {
+		return dialer.DialContext, nil
+	}
+	var auth *proxy.Auth
+	if user := config.SOCKS5Proxy.User; user != nil {
+		password, _ := user.Password()
+		auth = &proxy.Auth{User: user.Username(), Password: password}
+	}
+	socksDialer, e := proxy.SOCKS5("tcp", config.SOCKS5Proxy.Host, auth, dialer)
+	if e != nil {
+		return nil, probe.NewError(e)
+	}
+	return socksDialer.(proxy.ContextDialer).DialContext, nil
+}
@@ -0,0 +1,232 @@
+/*
+ * MinIO Client (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptrace"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/fatih/color"
+	json "github.com/minio/mc/pkg/colorjson"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/minio/pkg/console"
+)
+
+// transferStats aggregates request metrics of all S3 clients in this process.
+type transferStats struct {
+	mutex     sync.Mutex
+	start     time.Time
+	latencies []time.Duration
+	bytes     int64
+	// Connections reused from the idle pool and newly dialed.
+	reusedConns int64
+	newConns    int64
+	// Requests answered with 503 Slow Down.
+	throttled int64
+}
+
+func newTransferStats() *transferStats {
+	return &transferStats{start: nowFunc()}
+}
+
+// Metrics collected for --stats.
+var globalTransferStats = newTransferStats()
+
+// record adds a completed request.
+func (s *transferStats) record(latency time.Duration) {
+	s.mutex.Lock()
+	s.latencies = append(s.latencies, latency)
+	s.mutex.Unlock()
+}
+
+// addBytes accounts bytes sent or received.
+func (s *transferStats) addBytes(n int64) {
+	atomic.AddInt64(&s.bytes, n)
+}
+
+// addConn accounts the connection a request was sent on.
+func (s *transferStats) addConn(reused bool) {
+	if reused {
+		atomic.AddInt64(&s.reusedConns, 1)
+	} else {
+		atomic.AddInt64(&s.newConns, 1)
+	}
+}
+
+// percentile returns the nearest-rank percentile p of the recorded latencies.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p/100*float64(len(sorted)))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	return sorted[rank]
+}
+
+// message summarizes the collected metrics.
+func (s *transferStats) message() statsMessage {
+	s.mutex.Lock()
+	sorted := make([]time.Duration, len(s.latencies))
+	copy(sorted, s.latencies)
+	s.mutex.Unlock()
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	msg := statsMessage{
+		Requests:    len(sorted),
+		Bytes:       atomic.LoadInt64(&s.bytes),
+		WallTime:    nowFunc().Sub(s.start),
+		P50:         percentile(sorted, 50),
+		P95:         percentile(sorted, 95),
+		ReusedConns: atomic.LoadInt64(&s.reusedConns),
+		NewConns:    atomic.LoadInt64(&s.newConns),
+		Throttled:   atomic.LoadInt64(&s.throttled),
+	}
+	if seconds := msg.WallTime.Seconds(); seconds > 0 {
+		msg.Throughput = float64(msg.Bytes) / seconds
+	}
+	return msg
+}
+
+// statsMessage container for --stats output.
+type statsMessage struct {
+	Status     string        `json:"status"`
+	Requests   int           `json:"requests"`
+	Bytes      int64         `json:"bytes"`
+	WallTime   time.Duration `json:"wallTime"`
+	Throughput float64       `json:"throughput"`
+	P50        time.Duration `json:"p50"`
+	P95        time.Duration `json:"p95"`
+	// Connections reused and dialed, few reuses with many requests
+	// hint at too few idle connections, see --max-idle-conns.
+	ReusedConns int64 `json:"reusedConns"`
+	NewConns    int64 `json:"newConns"`
+	// Requests the server asked to slow down, see throttleTransport.
+	Throttled int64 `json:"throttled"`
+}
+
+// String colorized stats message.
+func (s statsMessage) String() string {
+	rows := [][2]string{
+		{"Requests", fmt.Sprintf("%d", s.Requests)},
+		{"Transferred", humanizeSize(s.Bytes)},
+		{"Wall time", s.WallTime.Round(time.Millisecond).String()},
+		{"Throughput", humanizeSize(int64(s.Throughput)) + "/s"},
+		{"Latency p50", s.P50.Round(time.Microsecond).String()},
+		{"Latency p95", s.P95.Round(time.Microsecond).String()},
+		{"Connections", fmt.Sprintf("%d reused, %d new", s.ReusedConns, s.NewConns)},
+		{"Throttled", fmt.Sprintf("%d request(s)", s.Throttled)},
+	}
+	var lines []string
+	for _, row := range rows {
+		lines = append(lines, fmt.Sprintf("%s %s", console.Colorize("StatsKey", fmt.Sprintf("%-12s:", row[0])), row[1]))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// JSON jsonified stats message.
+func (s statsMessage) JSON() string {
+	s.Status = "success"
+	statsJSONBytes, e := json.MarshalIndent(s, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(statsJSONBytes)
+}
+
+// printStats prints the collected metrics when --stats is set.
+func printStats() {
+	if !globalStats {
+		return
+	}
+	console.SetColor("StatsKey", color.New(color.FgCyan, color.Bold))
+	printMsg(globalTransferStats.message())
+}
+
+// statsTransport times every round trip, counts transferred bytes and
+// whether connections are reused. Requests are broken down into their
+// phases as well when profiles is set.
+type statsTransport struct {
+	stats     *transferStats
+	profiles  *requestProfiles
+	transport http.RoundTripper
+}
+
+// countingBody counts bytes read from a response body.
+type countingBody struct {
+	io.ReadCloser
+	stats *transferStats
+}
+
+func (b countingBody) Read(p []byte) (int, error) {
+	n, e := b.ReadCloser.Read(p)
+	b.stats.addBytes(int64(n))
+	return n, e
+}
+
+func (t statsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	trace := &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			t.stats.addConn(info.Reused)
+		},
+	}
+	var profile *profileTrace
+	if t.profiles != nil {
+		profile = &profileTrace{start: nowFunc()}
+		profile.clientTrace(trace)
+	}
+	req = req.WithContext(httptrace.WithClientTrace(req.Context(), trace))
+	start := nowFunc()
+	resp, e := t.transport.RoundTrip(req)
+	t.stats.record(nowFunc().Sub(start))
+	if e != nil {
+		return resp, e
+	}
+	if resp.StatusCode == http.StatusServiceUnavailable {
+		atomic.AddInt64(&t.stats.throttled, 1)
+	}
+	if req.ContentLength > 0 {
+		t.stats.addBytes(req.ContentLength)
+	}
+	if resp.Body != nil {
+		resp.Body = countingBody{ReadCloser: resp.Body, stats: t.stats}
+	}
+	if profile != nil {
+		request := req.Method + " " + req.URL.Host + req.URL.Path
+		var once sync.Once
+		done := func() {
+			once.Do(func() {
+				profile.mark(&profile.end)
+				t.profiles.record(profile.message(request))
+			})
+		}
+		if resp.Body == nil {
+			done()
+		} else {
+			resp.Body = profileBody{ReadCloser: resp.Body, done: done}
+		}
+	}
+	return resp, nil
+}
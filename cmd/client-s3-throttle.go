@@ -0,0 +1,210 @@
+/*
+ * MinIO Client (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/minio/minio/pkg/console"
+)
+
+const (
+	// Answers to requests sent together fail together, a single 503
+	// Slow Down is taken into account per period.
+	throttleCooldown = time.Second
+
+	// Successful requests after which the concurrency is raised by one
+	// and the delay between requests halved.
+	throttleRampUpRequests = 16
+
+	// Bounds of the delay between requests while throttled.
+	throttleMinDelay = 50 * time.Millisecond
+	throttleMaxDelay = 2 * time.Second
+)
+
+// requestThrottle reduces the number of parallel tasks and the rate of
+// requests while servers answer 503 Slow Down, halving the concurrency
+// on every answer and raising it back by one step after a number of
+// successful requests. Retries of the requests are left to minio-go.
+type requestThrottle struct {
+	mutex *sync.Mutex
+	cond  *sync.Cond
+
+	// Tasks allowed to run at once, zero means no limit.
+	limit int
+	// Tasks running, and running when throttling started.
+	running int
+	peak    int
+	// Delay between requests, and when the next request may be sent.
+	delay time.Duration
+	next  time.Time
+
+	successes    int
+	lastSlowDown time.Time
+}
+
+func newRequestThrottle() *requestThrottle {
+	mutex := &sync.Mutex{}
+	return &requestThrottle{mutex: mutex, cond: sync.NewCond(mutex)}
+}
+
+// Throttle shared by all S3 clients and parallel tasks of this process.
+var globalThrottle = newRequestThrottle()
+
+// acquire - waits until one more task may run.
+func (t *requestThrottle) acquire() {
+	t.mutex.Lock()
+	for t.limit > 0 && t.running >= t.limit {
+		t.cond.Wait()
+	}
+	t.running++
+	t.mutex.Unlock()
+}
+
+// release - ends a task started with acquire.
+func (t *requestThrottle) release() {
+	t.mutex.Lock()
+	t.running--
+	t.cond.Broadcast()
+	t.mutex.Unlock()
+}
+
+// throttled - returns whether requests are currently throttled.
+func (t *requestThrottle) throttled() bool {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return t.limit > 0
+}
+
+// slowDown - halves the concurrency and doubles the delay between
+// requests, the server answered 503 Slow Down.
+func (t *requestThrottle) slowDown() {
+	t.mutex.Lock()
+	t.successes = 0
+	now := nowFunc()
+	if now.Sub(t.lastSlowDown) < throttleCooldown {
+		t.mutex.Unlock()
+		return
+	}
+	t.lastSlowDown = now
+	concurrency := t.running
+	if t.limit == 0 {
+		t.peak = t.running
+	} else if t.limit < concurrency {
+		concurrency = t.limit
+	}
+	t.limit = concurrency / 2
+	if t.limit < 1 {
+		t.limit = 1
+	}
+	t.delay *= 2
+	if t.delay < throttleMinDelay {
+		t.delay = throttleMinDelay
+	}
+	if t.delay > throttleMaxDelay {
+		t.delay = throttleMaxDelay
+	}
+	limit, delay := t.limit, t.delay
+	t.mutex.Unlock()
+
+	if !globalQuiet && !globalJSON {
+		console.Infoln(fmt.Sprintf("Server asked to slow down, throttling to %d parallel task(s) and one request every %s.", limit, delay))
+	}
+}
+
+// succeeded - raises the concurrency by one and halves the delay
+// between requests after enough successful requests, until neither is
+// limited anymore.
+func (t *requestThrottle) succeeded() {
+	t.mutex.Lock()
+	if t.limit == 0 && t.delay == 0 {
+		t.mutex.Unlock()
+		return
+	}
+	if t.successes++; t.successes < throttleRampUpRequests {
+		t.mutex.Unlock()
+		return
+	}
+	t.successes = 0
+	if t.delay /= 2; t.delay < throttleMinDelay {
+		t.delay = 0
+	}
+	if t.limit > 0 {
+		if t.limit++; t.limit > t.peak {
+			t.limit = 0
+		}
+	}
+	t.cond.Broadcast()
+	ended := t.limit == 0 && t.delay == 0
+	t.mutex.Unlock()
+
+	if ended && !globalQuiet && !globalJSON {
+		console.Infoln("Server stopped asking to slow down, throttling ended.")
+	}
+}
+
+// wait - sleeps until the request may be sent, requests are sent one
+// by one with the delay in between while throttled.
+func (t *requestThrottle) wait(req *http.Request) error {
+	t.mutex.Lock()
+	if t.delay == 0 {
+		t.mutex.Unlock()
+		return nil
+	}
+	now := nowFunc()
+	if t.next.Before(now) {
+		t.next = now
+	}
+	start := t.next
+	t.next = start.Add(t.delay)
+	t.mutex.Unlock()
+
+	timer := time.NewTimer(start.Sub(now))
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-req.Context().Done():
+		return req.Context().Err()
+	}
+}
+
+// throttleTransport slows requests down while the throttle is engaged
+// and reports the answers of the server to it.
+type throttleTransport struct {
+	throttle  *requestThrottle
+	transport http.RoundTripper
+}
+
+func (t throttleTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if e := t.throttle.wait(req); e != nil {
+		return nil, e
+	}
+	resp, e := t.transport.RoundTrip(req)
+	if e != nil {
+		return resp, e
+	}
+	if resp.StatusCode == http.StatusServiceUnavailable {
+		t.throttle.slowDown()
+	} else {
+		t.throttle.succeeded()
+	}
+	return resp, nil
+}
@@ -0,0 +1,197 @@
+/*
+ * MinIO Client (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRequestThrottle(t *testing.T) {
+	defer func(quiet bool) { globalQuiet = quiet }(globalQuiet)
+	globalQuiet = true
+
+	throttle := newRequestThrottle()
+	for i := 0; i < 8; i++ {
+		throttle.acquire()
+	}
+	throttle.slowDown()
+	if throttle.limit != 4 || throttle.delay != throttleMinDelay {
+		t.Fatalf("Expected 4 tasks and a delay of %s, got %d and %s", throttleMinDelay, throttle.limit, throttle.delay)
+	}
+	// Answers of requests sent together count once.
+	throttle.slowDown()
+	if throttle.limit != 4 {
+		t.Fatalf("Expected 4 tasks within the cooldown, got %d", throttle.limit)
+	}
+	throttle.lastSlowDown = time.Time{}
+	throttle.slowDown()
+	if throttle.limit != 2 || throttle.delay != 2*throttleMinDelay {
+		t.Fatalf("Expected 2 tasks and a delay of %s, got %d and %s", 2*throttleMinDelay, throttle.limit, throttle.delay)
+	}
+
+	// Tasks beyond the limit wait for running tasks to end.
+	for i := 0; i < 8; i++ {
+		throttle.release()
+	}
+	throttle.acquire()
+	throttle.acquire()
+	acquired := make(chan struct{})
+	go func() {
+		throttle.acquire()
+		close(acquired)
+	}()
+	select {
+	case <-acquired:
+		t.Fatal("Expected the third task to wait")
+	case <-time.After(50 * time.Millisecond):
+	}
+	throttle.release()
+	<-acquired
+
+	// Limits are raised step by step until the concurrency of before.
+	for step := 3; step <= 8; step++ {
+		for i := 0; i < throttleRampUpRequests; i++ {
+			throttle.succeeded()
+		}
+		if throttle.limit != step {
+			t.Fatalf("Expected %d tasks, got %d", step, throttle.limit)
+		}
+	}
+	for i := 0; i < throttleRampUpRequests; i++ {
+		throttle.succeeded()
+	}
+	if throttle.throttled() || throttle.delay != 0 {
+		t.Fatalf("Expected throttling to end, got %d tasks and a delay of %s", throttle.limit, throttle.delay)
+	}
+}
+
+// slowDownHandler answers the first uploads with 503 Slow Down and
+// stores the others, recording how many uploads it serves at once.
+type slowDownHandler struct {
+	mutex    *sync.Mutex
+	reject   int
+	inFlight int
+	// Most uploads served at once since the last reset.
+	maxInFlight int
+	objects     map[string]bool
+}
+
+func (h *slowDownHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if _, ok := r.URL.Query()["location"]; ok {
+		w.Write([]byte(`<LocationConstraint xmlns="http://s3.amazonaws.com/doc/2006-03-01/"></LocationConstraint>`))
+		return
+	}
+	if r.Method != http.MethodPut {
+		w.WriteHeader(http.StatusNotImplemented)
+		return
+	}
+	h.mutex.Lock()
+	h.inFlight++
+	if h.inFlight > h.maxInFlight {
+		h.maxInFlight = h.inFlight
+	}
+	rejected := h.reject > 0
+	h.reject--
+	h.mutex.Unlock()
+
+	// Uploads overlap for concurrency to show.
+	time.Sleep(20 * time.Millisecond)
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.inFlight--
+	if rejected {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte("<Error><Code>SlowDown</Code><Message>Please reduce your request rate.</Message></Error>"))
+		return
+	}
+	h.objects[strings.TrimPrefix(r.URL.Path, "/bucket/")] = true
+	w.WriteHeader(http.StatusOK)
+}
+
+func TestThrottleSlowDown(t *testing.T) {
+	const workers = 16
+	handler := &slowDownHandler{mutex: &sync.Mutex{}, reject: workers, objects: make(map[string]bool)}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+	defer withTestConfig(map[string]hostConfigV9{"myminio": testHost(server.URL)})()
+	defer func(throttle *requestThrottle, stats *transferStats, enabled, quiet bool) {
+		globalThrottle, globalTransferStats, globalStats, globalQuiet = throttle, stats, enabled, quiet
+	}(globalThrottle, globalTransferStats, globalStats, globalQuiet)
+	globalThrottle, globalTransferStats, globalStats, globalQuiet = newRequestThrottle(), newTransferStats(), true, true
+
+	statusCh := make(chan URLs)
+	parallel, queueCh := newParallelManager(statusCh)
+	for atomic.LoadUint32(&parallel.workersNum) < workers {
+		parallel.addWorker()
+	}
+	upload := func(key string) func() URLs {
+		return func() URLs {
+			clnt, err := newClient("myminio/bucket/" + key)
+			if err == nil {
+				_, err = clnt.Put(context.Background(), strings.NewReader("hello"), 5, map[string]string{}, nil, nil)
+			}
+			return URLs{Error: err}
+		}
+	}
+	run := func(phase int) {
+		go func() {
+			for i := 0; i < workers; i++ {
+				queueCh <- upload(fmt.Sprintf("object%d-%d", phase, i))
+			}
+		}()
+		for i := 0; i < workers; i++ {
+			if urls := <-statusCh; urls.Error != nil {
+				t.Fatalf("Expected uploads to complete despite throttling, got %v", urls.Error)
+			}
+		}
+	}
+
+	// The first upload of every worker is rejected.
+	run(1)
+	if !globalThrottle.throttled() {
+		t.Fatal("Expected the uploads to be throttled")
+	}
+	handler.mutex.Lock()
+	before := handler.maxInFlight
+	handler.maxInFlight = 0
+	handler.mutex.Unlock()
+
+	run(2)
+	close(queueCh)
+	parallel.wait()
+
+	handler.mutex.Lock()
+	defer handler.mutex.Unlock()
+	if handler.maxInFlight >= before {
+		t.Fatalf("Expected fewer than %d uploads at once once throttled, got %d", before, handler.maxInFlight)
+	}
+	if len(handler.objects) != 2*workers {
+		t.Fatalf("Expected %d objects, got %d", 2*workers, len(handler.objects))
+	}
+	if throttled := globalTransferStats.message().Throttled; throttled != workers {
+		t.Fatalf("Expected %d throttled requests, got %d", workers, throttled)
+	}
+}
@@ -0,0 +1,43 @@
+/*
+ * MinIO Client (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"net/http"
+	"strings"
+)
+
+// userAgentTransport replaces the User-Agent of every request. The token
+// mirror adds is kept, mirror recognizes the events of its own uploads by
+// it. Signatures are not affected, User-Agent is never signed.
+type userAgentTransport struct {
+	userAgent string
+	transport http.RoundTripper
+}
+
+func (t userAgentTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	userAgent := t.userAgent
+	for _, token := range strings.Fields(req.Header.Get("User-Agent")) {
+		if strings.HasPrefix(token, uaMirrorAppName+"/") {
+			userAgent += " " + token
+		}
+	}
+	// Round trippers must not modify the request.
+	req = req.Clone(req.Context())
+	req.Header.Set("User-Agent", userAgent)
+	return t.transport.RoundTrip(req)
+}
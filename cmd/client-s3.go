@@ -17,22 +17,27 @@
 package cmd
 
 import (
+	"bufio"
 	"bytes"
 	"context"
+	"crypto/md5"
 	"crypto/tls"
+	"encoding/base64"
 	"encoding/json"
+	"encoding/xml"
 	"errors"
 	"hash/fnv"
 	"io"
-	"net"
 	"net/http"
 	"net/url"
 	"os"
 	"path"
 	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/minio/mc/pkg/httptracer"
@@ -41,16 +46,27 @@ import (
 	"github.com/minio/minio-go/v6/pkg/credentials"
 	"github.com/minio/minio-go/v6/pkg/encrypt"
 	"github.com/minio/minio-go/v6/pkg/policy"
+	"github.com/minio/minio-go/v6/pkg/s3signer"
 	"github.com/minio/minio-go/v6/pkg/s3utils"
 	"github.com/minio/minio/pkg/mimedb"
 )
 
 // S3 client
 type s3Client struct {
-	mutex        *sync.Mutex
-	targetURL    *clientURL
-	api          *minio.Client
-	virtualStyle bool
+	mutex     *sync.Mutex
+	targetURL *clientURL
+	partSize  uint64
+	config    *Config
+	hostName  string
+	transport http.RoundTripper
+
+	// The endpoint, switched by redirected while requests are sent by
+	// other goroutines, read through getAPI and the other getters.
+	endpointMutex sync.RWMutex
+	api           *minio.Client
+	objectAPI     *minio.Client
+	virtualStyle  bool
+	redirect      bucketRedirect
 }
 
 const (
@@ -90,6 +106,8 @@ var timeSentinel = time.Unix(0, 0).UTC()
 // newFactory encloses New function with client cache.
 func newFactory() func(config *Config) (Client, *probe.Error) {
 	clientCache := make(map[uint32]*minio.Client)
+	transportCache := make(map[uint32]http.RoundTripper)
+	acceleratedCache := make(map[uint32]*minio.Client)
 	mutex := &sync.Mutex{}
 
 	// Return New function.
@@ -108,6 +126,10 @@ func newFactory() func(config *Config) (Client, *probe.Error) {
 		s3Clnt.mutex = new(sync.Mutex)
 		// Save the target URL.
 		s3Clnt.targetURL = targetURL
+		// Save the multipart part size.
+		s3Clnt.partSize = config.PartSize
+		// Save the config to follow region redirects.
+		s3Clnt.config = config
 
 		// Save if target supports virtual host style.
 		hostName := targetURL.Host
@@ -120,44 +142,70 @@ func newFactory() func(config *Config) (Client, *probe.Error) {
 				hostName = googleHostName
 			}
 		}
+		s3Clnt.hostName = hostName
+
+		// Go straight to the regional endpoint if the bucket
+		// was redirected before.
+		endpoint, region := hostName, config.Region
+		bucket, _ := s3Clnt.url2BucketAndObject()
+		if redirect, ok := getBucketRedirect(hostName, bucket); ok {
+			s3Clnt.redirect = redirect
+			region = redirect.Region
+			if redirect.Endpoint != "" {
+				endpoint = redirect.Endpoint
+			}
+		}
+
 		// Generate a hash out of s3Conf.
 		confHash := fnv.New32a()
-		confHash.Write([]byte(hostName + config.AccessKey + config.SecretKey))
+		confHash.Write([]byte(endpoint + region + config.AccessKey + config.SecretKey + config.SessionToken + config.ClientCert + config.ClientKey + strconv.FormatBool(config.RequestPayer)))
 		confSum := confHash.Sum32()
 
+		// Transfer acceleration is only offered by AWS and requires
+		// buckets usable as DNS names.
+		accelerate := config.Accelerate && isAmazon(hostName) && !isS3AcceleratedEndpoint
+		if accelerate && bucket != "" && (strings.Contains(bucket, ".") || s3utils.CheckValidBucketNameStrict(bucket) != nil) {
+			return nil, probe.NewError(minio.ErrTransferAccelerationBucket(bucket))
+		}
+
 		// Lookup previous cache by hash.
 		mutex.Lock()
 		defer mutex.Unlock()
+		// if Signature version '4' use NewV4 directly, unless the
+		// credentials are refreshed by their source.
+		creds := config.Creds
+		if creds == nil {
+			creds = credentials.NewStaticV4(config.AccessKey, config.SecretKey, config.SessionToken)
+		}
+		// if Signature version '2' use NewV2 directly.
+		if strings.ToUpper(config.Signature) == "S3V2" {
+			creds = credentials.NewStaticV2(config.AccessKey, config.SecretKey, config.SessionToken)
+		}
 		var api *minio.Client
 		var found bool
 		if api, found = clientCache[confSum]; !found {
-			// if Signature version '4' use NewV4 directly.
-			creds := credentials.NewStaticV4(config.AccessKey, config.SecretKey, "")
-			// if Signature version '2' use NewV2 directly.
-			if strings.ToUpper(config.Signature) == "S3V2" {
-				creds = credentials.NewStaticV2(config.AccessKey, config.SecretKey, "")
-			}
 			// Not found. Instantiate a new MinIO
 			var e error
 
 			options := minio.Options{
 				Creds:        creds,
 				Secure:       useTLS,
-				Region:       "",
+				Region:       region,
 				BucketLookup: config.Lookup,
 			}
 
-			api, e = minio.NewWithOptions(hostName, &options)
+			api, e = minio.NewWithOptions(endpoint, &options)
 			if e != nil {
 				return nil, probe.NewError(e)
 			}
 
+			dialContext, err := newDialContext(config)
+			if err != nil {
+				return nil, err.Trace(config.HostURL)
+			}
 			tr := &http.Transport{
-				Proxy: http.ProxyFromEnvironment,
-				DialContext: (&net.Dialer{
-					Timeout:   30 * time.Second,
-					KeepAlive: 30 * time.Second,
-				}).DialContext,
+				Proxy:                 transportProxy(config),
+				DialContext:           dialContext,
 				MaxIdleConns:          1024,
 				MaxIdleConnsPerHost:   1024,
 				IdleConnTimeout:       90 * time.Second,
@@ -171,6 +219,11 @@ func newFactory() func(config *Config) (Client, *probe.Error) {
 				//    https://golang.org/src/net/http/transport.go?h=roundTrip#L1843
 				DisableCompression: true,
 			}
+			if config.MaxIdleConns > 0 {
+				tr.MaxIdleConns = config.MaxIdleConns
+				tr.MaxIdleConnsPerHost = config.MaxIdleConns
+			}
+			tr.MaxConnsPerHost = config.MaxConnsPerHost
 
 			if useTLS {
 				// Keep TLS config.
@@ -184,6 +237,15 @@ func newFactory() func(config *Config) (Client, *probe.Error) {
 				if config.Insecure {
 					tlsConfig.InsecureSkipVerify = true
 				}
+				// The client certificate is kept in the transport of
+				// this host, other hosts never see it.
+				cert, err := loadClientCertificate(config.ClientCert, config.ClientKey)
+				if err != nil {
+					return nil, err.Trace(config.HostURL)
+				}
+				if cert != nil {
+					tlsConfig.Certificates = []tls.Certificate{*cert}
+				}
 				tr.TLSClientConfig = tlsConfig
 
 				// Because we create a custom TLSClientConfig, we have to opt-in to HTTP/2.
@@ -197,6 +259,33 @@ func newFactory() func(config *Config) (Client, *probe.Error) {
 			}
 
 			var transport http.RoundTripper = tr
+			if config.UserAgent != "" {
+				transport = userAgentTransport{userAgent: config.UserAgent, transport: transport}
+			}
+			if globalStats || globalProfile {
+				statsTr := statsTransport{stats: globalTransferStats, transport: transport}
+				if globalProfile {
+					statsTr.profiles = globalRequestProfiles
+				}
+				transport = statsTr
+			}
+			transport = throttleTransport{throttle: globalThrottle, transport: transport}
+			transport = redirectTransport{
+				host: hostName,
+				transport: skewTransport{
+					host:      endpoint,
+					accessKey: config.AccessKey,
+					secretKey: config.SecretKey,
+					transport: transport,
+				},
+			}
+			transport = requestPayerTransport{
+				host:      endpoint,
+				accessKey: config.AccessKey,
+				secretKey: config.SecretKey,
+				payer:     config.RequestPayer,
+				transport: transport,
+			}
 			if config.Debug {
 				if strings.EqualFold(config.Signature, "S3v4") {
 					transport = httptracer.GetNewTraceTransport(newTraceV4(), transport)
@@ -218,10 +307,36 @@ func newFactory() func(config *Config) (Client, *probe.Error) {
 
 			// Cache the new MinIO Client with hash of config as key.
 			clientCache[confSum] = api
+			transportCache[confSum] = transport
 		}
 
 		// Store the new api object.
 		s3Clnt.api = api
+		s3Clnt.transport = transportCache[confSum]
+
+		// Object uploads and downloads go through the accelerate endpoint,
+		// which does not serve bucket operations, with a client of their own.
+		s3Clnt.objectAPI = api
+		if accelerate {
+			objectAPI, found := acceleratedCache[confSum]
+			if !found {
+				var e error
+				objectAPI, e = minio.NewWithOptions(endpoint, &minio.Options{
+					Creds:        creds,
+					Secure:       useTLS,
+					Region:       region,
+					BucketLookup: config.Lookup,
+				})
+				if e != nil {
+					return nil, probe.NewError(e)
+				}
+				objectAPI.SetCustomTransport(s3Clnt.transport)
+				objectAPI.SetS3TransferAccelerate(amazonHostNameAccelerated)
+				objectAPI.SetAppInfo(config.AppName, config.AppVersion)
+				acceleratedCache[confSum] = objectAPI
+			}
+			s3Clnt.objectAPI = objectAPI
+		}
 
 		return s3Clnt, nil
 	}
@@ -246,7 +361,7 @@ func (c *s3Client) AddNotificationConfig(arn string, events []string, prefix, su
 	}
 
 	// Get any enabled notification.
-	mb, e := c.api.GetBucketNotification(bucket)
+	mb, e := c.getAPI().GetBucketNotification(bucket)
 	if e != nil {
 		return probe.NewError(e)
 	}
@@ -292,7 +407,7 @@ func (c *s3Client) AddNotificationConfig(arn string, events []string, prefix, su
 	}
 
 	// Set the new bucket configuration
-	if err := c.api.SetBucketNotification(bucket, mb); err != nil {
+	if err := c.getAPI().SetBucketNotification(bucket, mb); err != nil {
 		if ignoreExisting && strings.Contains(err.Error(), "An object key name filtering rule defined with overlapping prefixes, overlapping suffixes, or overlapping combinations of prefixes and suffixes for the same event types") {
 			return nil
 		}
@@ -306,13 +421,13 @@ func (c *s3Client) RemoveNotificationConfig(arn string) *probe.Error {
 	bucket, _ := c.url2BucketAndObject()
 	// Remove all notification configs if arn is empty
 	if arn == "" {
-		if err := c.api.RemoveAllBucketNotification(bucket); err != nil {
+		if err := c.getAPI().RemoveAllBucketNotification(bucket); err != nil {
 			return probe.NewError(err)
 		}
 		return nil
 	}
 
-	mb, e := c.api.GetBucketNotification(bucket)
+	mb, e := c.getAPI().GetBucketNotification(bucket)
 	if e != nil {
 		return probe.NewError(e)
 	}
@@ -335,7 +450,7 @@ func (c *s3Client) RemoveNotificationConfig(arn string) *probe.Error {
 	}
 
 	// Set the new bucket configuration
-	if e := c.api.SetBucketNotification(bucket, mb); e != nil {
+	if e := c.getAPI().SetBucketNotification(bucket, mb); e != nil {
 		return probe.NewError(e)
 	}
 	return nil
@@ -353,7 +468,7 @@ type notificationConfig struct {
 func (c *s3Client) ListNotificationConfigs(arn string) ([]notificationConfig, *probe.Error) {
 	var configs []notificationConfig
 	bucket, _ := c.url2BucketAndObject()
-	mb, e := c.api.GetBucketNotification(bucket)
+	mb, e := c.getAPI().GetBucketNotification(bucket)
 	if e != nil {
 		return nil, probe.NewError(e)
 	}
@@ -571,7 +686,7 @@ func (c *s3Client) Select(expression string, sse encrypt.ServerSide, selOpts Sel
 
 	opts.InputSerialization = selectObjectInputOpts(selOpts, object)
 	opts.OutputSerialization = selectObjectOutputOpts(selOpts, opts.InputSerialization)
-	reader, e := c.api.SelectObjectContent(context.Background(), bucket, object, opts)
+	reader, e := c.getAPI().SelectObjectContent(context.Background(), bucket, object, opts)
 	if e != nil {
 		return nil, probe.NewError(e)
 	}
@@ -580,7 +695,7 @@ func (c *s3Client) Select(expression string, sse encrypt.ServerSide, selOpts Sel
 
 func (c *s3Client) watchOneBucket(bucket, prefix, suffix string, events []string, doneCh chan struct{}, eventChan chan EventInfo, errorChan chan *probe.Error) {
 	// Start listening on all bucket events.
-	eventsCh := c.api.ListenBucketNotification(bucket, prefix, suffix, events, doneCh)
+	eventsCh := c.getAPI().ListenBucketNotification(bucket, prefix, suffix, events, doneCh)
 	for notificationInfo := range eventsCh {
 		if notificationInfo.Err != nil {
 			if nErr, ok := notificationInfo.Err.(minio.ErrorResponse); ok && nErr.Code == "APINotSupported" {
@@ -687,7 +802,7 @@ func (c *s3Client) Watch(params watchParams) (*watchObject, *probe.Error) {
 	// The list of buckets to watch
 	var buckets []string
 	if bucket == "" {
-		bkts, err := c.api.ListBuckets()
+		bkts, err := c.getAPI().ListBuckets()
 		if err != nil {
 			return nil, probe.NewError(err)
 		}
@@ -738,10 +853,57 @@ func (c *s3Client) Watch(params watchParams) (*watchObject, *probe.Error) {
 
 // Get - get object with metadata.
 func (c *s3Client) Get(sse encrypt.ServerSide) (io.ReadCloser, *probe.Error) {
+	return c.GetRange(0, 0, sse)
+}
+
+// GetRange - get length bytes of object from offset, zero length
+// reads until the end of the object.
+func (c *s3Client) GetRange(offset, length int64, sse encrypt.ServerSide) (io.ReadCloser, *probe.Error) {
+	return c.GetRangeWithContext(context.Background(), offset, length, sse)
+}
+
+// GetRangeWithContext - GetRange reading the object until ctx is done.
+func (c *s3Client) GetRangeWithContext(ctx context.Context, offset, length int64, sse encrypt.ServerSide) (io.ReadCloser, *probe.Error) {
 	bucket, object := c.url2BucketAndObject()
 	opts := minio.GetObjectOptions{}
 	opts.ServerSideEncryption = sse
-	reader, e := c.api.GetObject(bucket, object, opts)
+	if isByteRange(offset, length) {
+		end := int64(0)
+		if length > 0 {
+			end = offset + length - 1
+		}
+		if e := opts.SetRange(offset, end); e != nil {
+			return nil, probe.NewError(e)
+		}
+	}
+	get := func() (io.ReadCloser, error) {
+		if isByteRange(offset, length) {
+			// minio.Object re-requests absolute offsets when read, so
+			// issue a single ranged request instead which also reports
+			// an invalid range right away.
+			core := minio.Core{Client: c.getObjectAPI()}
+			reader, _, _, e := core.GetObjectWithContext(ctx, bucket, object, opts)
+			return reader, e
+		}
+		obj, e := c.getObjectAPI().GetObjectWithContext(ctx, bucket, object, opts)
+		if e != nil {
+			return nil, e
+		}
+		// An empty read sends the request right away, for errors like
+		// objects which are archived to be reported here.
+		if _, e = obj.Read(nil); e != nil && e != io.EOF {
+			obj.Close()
+			return nil, e
+		}
+		return obj, nil
+	}
+	reader, e := get()
+	if e != nil && c.redirected(bucket, e) {
+		reader, e = get()
+	}
+	if minio.ToErrorResponse(e).Code == "InvalidRange" {
+		return nil, errInvalidRange(offset, length, -1)
+	}
 	if e != nil {
 		errResponse := minio.ToErrorResponse(e)
 		if errResponse.Code == "NoSuchBucket" {
@@ -757,6 +919,9 @@ func (c *s3Client) Get(sse encrypt.ServerSide) (io.ReadCloser, *probe.Error) {
 		if errResponse.Code == "NoSuchKey" {
 			return nil, probe.NewError(ObjectMissing{})
 		}
+		if errResponse.Code == "InvalidObjectState" {
+			return nil, probe.NewError(ObjectOnGlacier{Object: object})
+		}
 		return nil, probe.NewError(e)
 	}
 	return reader, nil
@@ -782,7 +947,7 @@ func (c *s3Client) Copy(source string, size int64, progress io.Reader, srcSSE, t
 		return probe.NewError(e)
 	}
 
-	if e = c.api.ComposeObjectWithProgress(dst, []minio.SourceInfo{src}, progress); e != nil {
+	if e = c.getAPI().ComposeObjectWithProgress(dst, []minio.SourceInfo{src}, progress); e != nil {
 		errResponse := minio.ToErrorResponse(e)
 		if errResponse.Code == "AccessDenied" {
 			return probe.NewError(PathInsufficientPermission{
@@ -807,6 +972,17 @@ func (c *s3Client) Copy(source string, size int64, progress io.Reader, srcSSE, t
 	return nil
 }
 
+// peekEmptySource - returns a source of unknown size with a size of
+// zero if it turns out to be empty, peeking at its first byte. Errors
+// are returned by the first read of the returned source.
+func peekEmptySource(reader io.Reader, size int64) (io.Reader, int64) {
+	buffered := bufio.NewReader(reader)
+	if _, e := buffered.Peek(1); e == io.EOF {
+		return bytes.NewReader(nil), 0
+	}
+	return buffered, size
+}
+
 // Put - upload an object with custom metadata.
 func (c *s3Client) Put(ctx context.Context, reader io.Reader, size int64, metadata map[string]string, progress io.Reader, sse encrypt.ServerSide) (int64, *probe.Error) {
 	bucket, object := c.url2BucketAndObject()
@@ -866,6 +1042,7 @@ func (c *s3Client) Put(ctx context.Context, reader io.Reader, size int64, metada
 		UserMetadata:         metadata,
 		Progress:             progress,
 		NumThreads:           defaultMultipartThreadsNum,
+		PartSize:             effectivePartSize(size, c.partSize),
 		ContentType:          contentType,
 		CacheControl:         cacheControl,
 		ContentDisposition:   contentDisposition,
@@ -880,7 +1057,20 @@ func (c *s3Client) Put(ctx context.Context, reader io.Reader, size int64, metada
 	if lockModeStr != "" {
 		opts.Mode = &lockMode
 	}
-	n, e := c.api.PutObjectWithContext(ctx, bucket, object, reader, size, opts)
+	// Sources of unknown size are uploaded in parts, a multipart upload
+	// of a single empty part is mishandled by several backends.
+	if size < 0 {
+		reader, size = peekEmptySource(reader, size)
+	}
+	var n int64
+	var e error
+	// minio-go refuses Expires as metadata, it is sent as a header
+	// by uploads with requests of their own.
+	if _, ok := metadata["Expires"]; ok || c.config.Checksum != checksumNone || c.config.IfNotExists || c.config.Verify {
+		n, e = c.putObjectSigned(ctx, bucket, object, reader, size, opts)
+	} else {
+		n, e = c.putObjectRewind(ctx, bucket, object, reader, size, opts)
+	}
 	if e != nil {
 		errResponse := minio.ToErrorResponse(e)
 		if errResponse.Code == "UnexpectedEOF" || e == io.EOF {
@@ -894,6 +1084,19 @@ func (c *s3Client) Put(ctx context.Context, reader io.Reader, size int64, metada
 				Path: c.targetURL.String(),
 			})
 		}
+		if c.config.IfNotExists {
+			if errResponse.StatusCode == http.StatusPreconditionFailed || errResponse.Code == "PreconditionFailed" {
+				return n, probe.NewError(ObjectPreconditionFailed{
+					Object: object,
+				})
+			}
+			if errResponse.StatusCode == http.StatusNotImplemented || errResponse.Code == "NotImplemented" {
+				return n, probe.NewError(APINotImplemented{
+					API:     "If-None-Match",
+					APIType: c.targetURL.Scheme + "://" + c.targetURL.Host,
+				})
+			}
+		}
 		if errResponse.Code == "MethodNotAllowed" {
 			return n, probe.NewError(ObjectAlreadyExists{
 				Object: object,
@@ -931,7 +1134,7 @@ func (c *s3Client) removeIncompleteObjects(bucket string, objectsCh <-chan strin
 		defer close(removeObjectErrorCh)
 
 		for object := range objectsCh {
-			if err := c.api.RemoveIncompleteUpload(bucket, object); err != nil {
+			if err := c.getAPI().RemoveIncompleteUpload(bucket, object); err != nil {
 				removeObjectErrorCh <- minio.RemoveObjectError{ObjectName: object, Err: err}
 			}
 		}
@@ -940,8 +1143,117 @@ func (c *s3Client) removeIncompleteObjects(bucket string, objectsCh <-chan strin
 	return removeObjectErrorCh
 }
 
+// removeGovernanceObjects - removes the objects of objectsCh one by one,
+// bypassing their GOVERNANCE retention. Multi-object deletes of minio-go
+// do not send the bypass header.
+func (c *s3Client) removeGovernanceObjects(bucket string, objectsCh <-chan string) <-chan minio.RemoveObjectError {
+	removeObjectErrorCh := make(chan minio.RemoveObjectError)
+
+	go func() {
+		defer close(removeObjectErrorCh)
+
+		opts := minio.RemoveObjectOptions{GovernanceBypass: true}
+		for object := range objectsCh {
+			if err := c.getAPI().RemoveObjectWithOptions(bucket, object, opts); err != nil {
+				removeObjectErrorCh <- minio.RemoveObjectError{ObjectName: object, Err: err}
+			}
+		}
+	}()
+
+	return removeObjectErrorCh
+}
+
+// removeObjects - removes the objects or incomplete uploads of objectsCh.
+func (c *s3Client) removeObjects(bucket string, objectsCh <-chan string, isIncomplete bool) <-chan minio.RemoveObjectError {
+	switch {
+	case isIncomplete:
+		return c.removeIncompleteObjects(bucket, objectsCh)
+	case c.config.BypassGovernance:
+		return c.removeGovernanceObjects(bucket, objectsCh)
+	}
+	return c.getAPI().RemoveObjects(bucket, objectsCh)
+}
+
 func (c *s3Client) AddUserAgent(app string, version string) {
-	c.api.SetAppInfo(app, version)
+	c.getAPI().SetAppInfo(app, version)
+}
+
+// ListMultipartUploads - lists all in-progress multipart uploads under the URL prefix.
+func (c *s3Client) ListMultipartUploads() ([]multipartUpload, *probe.Error) {
+	bucket, prefix := c.url2BucketAndObject()
+	if bucket == "" {
+		return nil, probe.NewError(BucketNameEmpty{})
+	}
+	core := minio.Core{Client: c.getAPI()}
+	var uploads []multipartUpload
+	var keyMarker, uploadIDMarker string
+	for {
+		result, e := core.ListMultipartUploads(bucket, prefix, keyMarker, uploadIDMarker, "", 1000)
+		if e != nil {
+			errResponse := minio.ToErrorResponse(e)
+			if errResponse.Code == "NoSuchBucket" {
+				return nil, probe.NewError(BucketDoesNotExist{
+					Bucket: bucket,
+				})
+			}
+			return nil, probe.NewError(e)
+		}
+		for _, upload := range result.Uploads {
+			uploads = append(uploads, multipartUpload{
+				Key:       upload.Key,
+				UploadID:  upload.UploadID,
+				Initiated: upload.Initiated,
+			})
+		}
+		if !result.IsTruncated {
+			return uploads, nil
+		}
+		keyMarker, uploadIDMarker = result.NextKeyMarker, result.NextUploadIDMarker
+	}
+}
+
+// AbortMultipartUpload - aborts an in-progress multipart upload.
+func (c *s3Client) AbortMultipartUpload(upload multipartUpload) *probe.Error {
+	bucket, _ := c.url2BucketAndObject()
+	if bucket == "" {
+		return probe.NewError(BucketNameEmpty{})
+	}
+	core := minio.Core{Client: c.getAPI()}
+	if e := core.AbortMultipartUpload(bucket, upload.Key, upload.UploadID); e != nil {
+		errResponse := minio.ToErrorResponse(e)
+		if errResponse.Code == "NoSuchUpload" {
+			// Already completed or aborted.
+			return nil
+		}
+		return probe.NewError(e)
+	}
+	return nil
+}
+
+// removeStatusError - returns the error of a removal, errors of the
+// multi-object delete response are given the key they refer to.
+func removeStatusError(removeStatus minio.RemoveObjectError) *probe.Error {
+	if errResponse, ok := removeStatus.Err.(minio.ErrorResponse); ok {
+		if errResponse.Key == "" {
+			errResponse.Key = removeStatus.ObjectName
+		}
+		if isObjectLockedError(errResponse) {
+			return probe.NewError(ObjectLocked{Object: errResponse.Key})
+		}
+		return probe.NewError(errResponse)
+	}
+	return probe.NewError(removeStatus.Err)
+}
+
+// isObjectLockedError - returns true if a delete was denied by the
+// retention or the legal hold of the object. S3 denies access with a
+// message mentioning the object lock, MinIO with a code of its own.
+func isObjectLockedError(errResponse minio.ErrorResponse) bool {
+	if errResponse.Code == "ObjectLocked" {
+		return true
+	}
+	message := strings.ToLower(errResponse.Message)
+	return errResponse.Code == "AccessDenied" && (strings.Contains(message, "object lock") || strings.Contains(message, "worm protected"))
 }
 
 // Remove - remove object or bucket(s).
@@ -975,11 +1287,7 @@ func (c *s3Client) Remove(isIncomplete, isRemoveBucket bool, contentCh <-chan *c
 			if prevBucket == "" {
 				objectsCh = make(chan string)
 				prevBucket = bucket
-				if isIncomplete {
-					statusCh = c.removeIncompleteObjects(bucket, objectsCh)
-				} else {
-					statusCh = c.api.RemoveObjects(bucket, objectsCh)
-				}
+				statusCh = c.removeObjects(bucket, objectsCh, isIncomplete)
 			}
 
 			if prevBucket != bucket {
@@ -987,21 +1295,17 @@ func (c *s3Client) Remove(isIncomplete, isRemoveBucket bool, contentCh <-chan *c
 					close(objectsCh)
 				}
 				for removeStatus := range statusCh {
-					errorCh <- probe.NewError(removeStatus.Err)
+					errorCh <- removeStatusError(removeStatus)
 				}
 				// Remove bucket if it qualifies.
 				if isRemoveBucket && !isIncomplete {
-					if err := c.api.RemoveBucket(prevBucket); err != nil {
+					if err := c.getAPI().RemoveBucket(prevBucket); err != nil {
 						errorCh <- probe.NewError(err)
 					}
 				}
 				// Re-init objectsCh for next bucket
 				objectsCh = make(chan string)
-				if isIncomplete {
-					statusCh = c.removeIncompleteObjects(bucket, objectsCh)
-				} else {
-					statusCh = c.api.RemoveObjects(bucket, objectsCh)
-				}
+				statusCh = c.removeObjects(bucket, objectsCh, isIncomplete)
 				prevBucket = bucket
 			}
 
@@ -1015,7 +1319,7 @@ func (c *s3Client) Remove(isIncomplete, isRemoveBucket bool, contentCh <-chan *c
 					case objectsCh <- objectName:
 						sent = true
 					case removeStatus := <-statusCh:
-						errorCh <- probe.NewError(removeStatus.Err)
+						errorCh <- removeStatusError(removeStatus)
 					}
 				}
 			} else {
@@ -1033,12 +1337,12 @@ func (c *s3Client) Remove(isIncomplete, isRemoveBucket bool, contentCh <-chan *c
 		// Write remove objects status to errorCh
 		if statusCh != nil {
 			for removeStatus := range statusCh {
-				errorCh <- probe.NewError(removeStatus.Err)
+				errorCh <- removeStatusError(removeStatus)
 			}
 		}
 		// Remove last bucket if it qualifies.
 		if isRemoveBucket && prevBucket != "" && !isIncomplete {
-			if err := c.api.RemoveBucket(prevBucket); err != nil {
+			if err := c.getAPI().RemoveBucket(prevBucket); err != nil {
 				errorCh <- probe.NewError(err)
 			}
 		}
@@ -1061,7 +1365,7 @@ func (c *s3Client) MakeBucket(region string, ignoreExisting, withLock bool) *pro
 		}
 		var retried bool
 		for {
-			_, e := c.api.PutObject(bucket, object,
+			_, e := c.getAPI().PutObject(bucket, object,
 				bytes.NewReader([]byte("")), 0, minio.PutObjectOptions{})
 			if e == nil {
 				return nil
@@ -1072,9 +1376,9 @@ func (c *s3Client) MakeBucket(region string, ignoreExisting, withLock bool) *pro
 			switch minio.ToErrorResponse(e).Code {
 			case "NoSuchBucket":
 				if withLock {
-					e = c.api.MakeBucketWithObjectLock(bucket, region)
+					e = c.getAPI().MakeBucketWithObjectLock(bucket, region)
 				} else {
-					e = c.api.MakeBucket(bucket, region)
+					e = c.getAPI().MakeBucket(bucket, region)
 				}
 				if e != nil {
 					return probe.NewError(e)
@@ -1086,11 +1390,15 @@ func (c *s3Client) MakeBucket(region string, ignoreExisting, withLock bool) *pro
 		}
 	}
 
-	var e error
-	if withLock {
-		e = c.api.MakeBucketWithObjectLock(bucket, region)
-	} else {
-		e = c.api.MakeBucket(bucket, region)
+	makeBucket := func() error {
+		if withLock {
+			return c.getAPI().MakeBucketWithObjectLock(bucket, region)
+		}
+		return c.getAPI().MakeBucket(bucket, region)
+	}
+	e := makeBucket()
+	if e != nil && c.redirected(bucket, e) {
+		e = makeBucket()
 	}
 	if e != nil {
 		// Ignore bucket already existing error when ignoreExisting flag is enabled
@@ -1114,7 +1422,7 @@ func (c *s3Client) GetAccessRules() (map[string]string, *probe.Error) {
 		return map[string]string{}, probe.NewError(BucketNameEmpty{})
 	}
 	policies := map[string]string{}
-	policyStr, e := c.api.GetBucketPolicy(bucket)
+	policyStr, e := c.getAPI().GetBucketPolicy(bucket)
 	if e != nil {
 		return nil, probe.NewError(e)
 	}
@@ -1139,7 +1447,7 @@ func (c *s3Client) GetAccess() (string, string, *probe.Error) {
 	if bucket == "" {
 		return "", "", probe.NewError(BucketNameEmpty{})
 	}
-	policyStr, e := c.api.GetBucketPolicy(bucket)
+	policyStr, e := c.getAPI().GetBucketPolicy(bucket)
 	if e != nil {
 		return "", "", probe.NewError(e)
 	}
@@ -1157,6 +1465,42 @@ func (c *s3Client) GetAccess() (string, string, *probe.Error) {
 	return pType, policyStr, nil
 }
 
+// GetObjectACL get the access control list of an object.
+func (c *s3Client) GetObjectACL() ([]aclGrant, *probe.Error) {
+	bucket, object := c.url2BucketAndObject()
+	if bucket == "" {
+		return nil, probe.NewError(BucketNameEmpty{})
+	}
+	info, e := c.getAPI().GetObjectACL(bucket, object)
+	if e != nil {
+		errResponse := minio.ToErrorResponse(e)
+		if errResponse.Code == "NoSuchBucket" {
+			return nil, probe.NewError(BucketDoesNotExist{
+				Bucket: bucket,
+			})
+		}
+		if errResponse.Code == "NoSuchKey" {
+			return nil, probe.NewError(ObjectMissing{})
+		}
+		if errResponse.Code == "NotImplemented" {
+			return nil, probe.NewError(APINotImplemented{
+				API:     "GetObjectACL",
+				APIType: c.targetURL.Scheme + "://" + c.targetURL.Host,
+			})
+		}
+		return nil, probe.NewError(e)
+	}
+	grants := make([]aclGrant, 0, len(info.Grant))
+	for _, g := range info.Grant {
+		grants = append(grants, aclGrant{
+			GranteeID:  g.Grantee.ID,
+			GranteeURI: g.Grantee.URI,
+			Permission: g.Permission,
+		})
+	}
+	return grants, nil
+}
+
 // SetAccess set access policy permissions.
 func (c *s3Client) SetAccess(bucketPolicy string, isJSON bool) *probe.Error {
 	bucket, object := c.url2BucketAndObject()
@@ -1164,12 +1508,12 @@ func (c *s3Client) SetAccess(bucketPolicy string, isJSON bool) *probe.Error {
 		return probe.NewError(BucketNameEmpty{})
 	}
 	if isJSON {
-		if e := c.api.SetBucketPolicy(bucket, bucketPolicy); e != nil {
+		if e := c.getAPI().SetBucketPolicy(bucket, bucketPolicy); e != nil {
 			return probe.NewError(e)
 		}
 		return nil
 	}
-	policyStr, e := c.api.GetBucketPolicy(bucket)
+	policyStr, e := c.getAPI().GetBucketPolicy(bucket)
 	if e != nil {
 		return probe.NewError(e)
 	}
@@ -1181,7 +1525,7 @@ func (c *s3Client) SetAccess(bucketPolicy string, isJSON bool) *probe.Error {
 	}
 	p.Statements = policy.SetPolicy(p.Statements, policy.BucketPolicy(bucketPolicy), bucket, object)
 	if len(p.Statements) == 0 {
-		if e = c.api.SetBucketPolicy(bucket, ""); e != nil {
+		if e = c.getAPI().SetBucketPolicy(bucket, ""); e != nil {
 			return probe.NewError(e)
 		}
 		return nil
@@ -1190,18 +1534,47 @@ func (c *s3Client) SetAccess(bucketPolicy string, isJSON bool) *probe.Error {
 	if e != nil {
 		return probe.NewError(e)
 	}
-	if e = c.api.SetBucketPolicy(bucket, string(policyB)); e != nil {
+	if e = c.getAPI().SetBucketPolicy(bucket, string(policyB)); e != nil {
 		return probe.NewError(e)
 	}
 	return nil
 }
 
-// listObjectWrapper - select ObjectList version depending on the target hostname
+// listObjectWrapper - list objects, following a redirect to the bucket's region
 func (c *s3Client) listObjectWrapper(bucket, object string, isRecursive bool, doneCh chan struct{}, metadata bool) <-chan minio.ObjectInfo {
+	objectCh := c.listObjects(bucket, object, isRecursive, doneCh, metadata)
+	first, ok := <-objectCh
+	if ok && first.Err != nil && c.redirected(bucket, first.Err) {
+		return c.listObjects(bucket, object, isRecursive, doneCh, metadata)
+	}
+
+	resultCh := make(chan minio.ObjectInfo)
+	go func() {
+		defer close(resultCh)
+		if !ok {
+			return
+		}
+		entry := first
+		for {
+			select {
+			case resultCh <- entry:
+			case <-doneCh:
+				return
+			}
+			if entry, ok = <-objectCh; !ok {
+				return
+			}
+		}
+	}()
+	return resultCh
+}
+
+// listObjects - select ObjectList version depending on the target hostname
+func (c *s3Client) listObjects(bucket, object string, isRecursive bool, doneCh chan struct{}, metadata bool) <-chan minio.ObjectInfo {
 	if metadata {
-		return c.api.ListObjectsV2WithMetadata(bucket, object, isRecursive, doneCh)
+		return c.getAPI().ListObjectsV2WithMetadata(bucket, object, isRecursive, doneCh)
 	}
-	return c.api.ListObjectsV2(bucket, object, isRecursive, doneCh)
+	return c.getAPI().ListObjectsV2(bucket, object, isRecursive, doneCh)
 }
 
 // Stat - send a 'HEAD' on a bucket or object to fetch its metadata.
@@ -1238,7 +1611,7 @@ func (c *s3Client) Stat(isIncomplete, isFetchMeta, isPreserve bool, sse encrypt.
 
 	// If the request is for incomplete upload stat, handle it here.
 	if isIncomplete {
-		for objectMultipartInfo := range c.api.ListIncompleteUploads(bucket, prefix, nonRecursive, nil) {
+		for objectMultipartInfo := range c.getAPI().ListIncompleteUploads(bucket, prefix, nonRecursive, nil) {
 			if objectMultipartInfo.Err != nil {
 				return nil, probe.NewError(objectMultipartInfo.Err)
 			}
@@ -1313,7 +1686,10 @@ func (c *s3Client) Stat(isIncomplete, isFetchMeta, isPreserve bool, sse encrypt.
 // getObjectStat returns the metadata of an object from a HEAD call.
 func (c *s3Client) getObjectStat(bucket, object string, opts minio.StatObjectOptions) (*clientContent, *probe.Error) {
 	objectMetadata := &clientContent{}
-	objectStat, e := c.api.StatObject(bucket, object, opts)
+	objectStat, e := c.getAPI().StatObject(bucket, object, opts)
+	if e != nil && c.redirected(bucket, e) {
+		objectStat, e = c.getAPI().StatObject(bucket, object, opts)
+	}
 	if e != nil {
 		errResponse := minio.ToErrorResponse(e)
 		if errResponse.Code == "AccessDenied" {
@@ -1406,7 +1782,7 @@ func (c *s3Client) url2BucketAndObject() (bucketName, objectName string) {
 	// For the time being this check is introduced for S3,
 	// If you have custom virtual styled hosts please.
 	// List them below.
-	if c.virtualStyle {
+	if c.getVirtualStyle() {
 		var bucket string
 		hostIndex := strings.Index(c.targetURL.Host, "s3")
 		if hostIndex == -1 {
@@ -1429,7 +1805,7 @@ func (c *s3Client) splitPath(path string) (bucketName, objectName string) {
 	path = strings.TrimPrefix(path, string(c.targetURL.Separator))
 
 	// Handle path if its virtual style.
-	if c.virtualStyle {
+	if c.getVirtualStyle() {
 		hostIndex := strings.Index(c.targetURL.Host, "s3")
 		if hostIndex == -1 {
 			hostIndex = strings.Index(c.targetURL.Host, "s3-accelerate")
@@ -1487,7 +1863,7 @@ func (c *s3Client) listIncompleteInRoutine(contentCh chan *clientContent) {
 	b, o := c.url2BucketAndObject()
 	switch {
 	case b == "" && o == "":
-		buckets, err := c.api.ListBuckets()
+		buckets, err := c.getAPI().ListBuckets()
 		if err != nil {
 			contentCh <- &clientContent{
 				Err: probe.NewError(err),
@@ -1496,7 +1872,7 @@ func (c *s3Client) listIncompleteInRoutine(contentCh chan *clientContent) {
 		}
 		isRecursive := false
 		for _, bucket := range buckets {
-			for object := range c.api.ListIncompleteUploads(bucket.Name, o, isRecursive, nil) {
+			for object := range c.getAPI().ListIncompleteUploads(bucket.Name, o, isRecursive, nil) {
 				if object.Err != nil {
 					contentCh <- &clientContent{
 						Err: probe.NewError(object.Err),
@@ -1511,7 +1887,7 @@ func (c *s3Client) listIncompleteInRoutine(contentCh chan *clientContent) {
 				case strings.HasSuffix(object.Key, string(c.targetURL.Separator)):
 					// We need to keep the trailing Separator, do not use filepath.Join().
 					content.URL = url
-					content.Time = time.Now()
+					content.Time = nowFunc()
 					content.Type = os.ModeDir
 				default:
 					content.URL = url
@@ -1524,7 +1900,7 @@ func (c *s3Client) listIncompleteInRoutine(contentCh chan *clientContent) {
 		}
 	default:
 		isRecursive := false
-		for object := range c.api.ListIncompleteUploads(b, o, isRecursive, nil) {
+		for object := range c.getAPI().ListIncompleteUploads(b, o, isRecursive, nil) {
 			if object.Err != nil {
 				contentCh <- &clientContent{
 					Err: probe.NewError(object.Err),
@@ -1539,7 +1915,7 @@ func (c *s3Client) listIncompleteInRoutine(contentCh chan *clientContent) {
 			case strings.HasSuffix(object.Key, string(c.targetURL.Separator)):
 				// We need to keep the trailing Separator, do not use filepath.Join().
 				content.URL = url
-				content.Time = time.Now()
+				content.Time = nowFunc()
 				content.Type = os.ModeDir
 			default:
 				content.URL = url
@@ -1558,7 +1934,7 @@ func (c *s3Client) listIncompleteRecursiveInRoutine(contentCh chan *clientConten
 	b, o := c.url2BucketAndObject()
 	switch {
 	case b == "" && o == "":
-		buckets, err := c.api.ListBuckets()
+		buckets, err := c.getAPI().ListBuckets()
 		if err != nil {
 			contentCh <- &clientContent{
 				Err: probe.NewError(err),
@@ -1567,7 +1943,7 @@ func (c *s3Client) listIncompleteRecursiveInRoutine(contentCh chan *clientConten
 		}
 		isRecursive := true
 		for _, bucket := range buckets {
-			for object := range c.api.ListIncompleteUploads(bucket.Name, o, isRecursive, nil) {
+			for object := range c.getAPI().ListIncompleteUploads(bucket.Name, o, isRecursive, nil) {
 				if object.Err != nil {
 					contentCh <- &clientContent{
 						Err: probe.NewError(object.Err),
@@ -1586,7 +1962,7 @@ func (c *s3Client) listIncompleteRecursiveInRoutine(contentCh chan *clientConten
 		}
 	default:
 		isRecursive := true
-		for object := range c.api.ListIncompleteUploads(b, o, isRecursive, nil) {
+		for object := range c.getAPI().ListIncompleteUploads(b, o, isRecursive, nil) {
 			if object.Err != nil {
 				contentCh <- &clientContent{
 					Err: probe.NewError(object.Err),
@@ -1635,7 +2011,7 @@ func (c *s3Client) listIncompleteRecursiveInRoutineDirOpt(contentCh chan *client
 	var listDir func(bucket, object string) bool
 	listDir = func(bucket, object string) (isStop bool) {
 		isRecursive := false
-		for entry := range c.api.ListIncompleteUploads(bucket, object, isRecursive, nil) {
+		for entry := range c.getAPI().ListIncompleteUploads(bucket, object, isRecursive, nil) {
 			if entry.Err != nil {
 				url := *c.targetURL
 				url.Path = c.joinPath(bucket, object)
@@ -1678,7 +2054,7 @@ func (c *s3Client) listIncompleteRecursiveInRoutineDirOpt(contentCh chan *client
 	if bucket == "" && object == "" {
 		var e error
 		allBuckets = true
-		buckets, e = c.api.ListBuckets()
+		buckets, e = c.getAPI().ListBuckets()
 		if e != nil {
 			contentCh <- &clientContent{Err: probe.NewError(e)}
 			return
@@ -1733,6 +2109,15 @@ func (c *s3Client) joinPath(bucket string, objects ...string) string {
 	return p
 }
 
+// objectOwner - returns the display name of the owner of a listed
+// object, or its ID if the name is not reported.
+func objectOwner(entry minio.ObjectInfo) string {
+	if entry.Owner.DisplayName != "" {
+		return entry.Owner.DisplayName
+	}
+	return entry.Owner.ID
+}
+
 // Convert objectInfo to clientContent
 func (c *s3Client) objectInfo2ClientContent(bucket string, entry minio.ObjectInfo) *clientContent {
 	content := &clientContent{}
@@ -1742,6 +2127,8 @@ func (c *s3Client) objectInfo2ClientContent(bucket string, entry minio.ObjectInf
 	content.URL = url
 	content.Size = entry.Size
 	content.ETag = entry.ETag
+	content.Owner = objectOwner(entry)
+	content.StorageClass = entry.StorageClass
 	content.Time = entry.LastModified
 	content.Expires = entry.Expires
 	content.UserMetadata = map[string]string{}
@@ -1750,7 +2137,7 @@ func (c *s3Client) objectInfo2ClientContent(bucket string, entry minio.ObjectInf
 	}
 	if strings.HasSuffix(entry.Key, string(c.targetURL.Separator)) && entry.Size == 0 && entry.LastModified.IsZero() {
 		content.Type = os.ModeDir
-		content.Time = time.Now()
+		content.Time = nowFunc()
 	} else {
 		content.Type = os.FileMode(0664)
 	}
@@ -1760,7 +2147,10 @@ func (c *s3Client) objectInfo2ClientContent(bucket string, entry minio.ObjectInf
 
 // Returns bucket stat info of current bucket.
 func (c *s3Client) bucketStat(bucket string) (*clientContent, *probe.Error) {
-	exists, e := c.api.BucketExists(bucket)
+	exists, e := c.getAPI().BucketExists(bucket)
+	if e != nil && c.redirected(bucket, e) {
+		exists, e = c.getAPI().BucketExists(bucket)
+	}
 	if e != nil {
 		return nil, probe.NewError(e)
 	}
@@ -1820,7 +2210,7 @@ func (c *s3Client) listRecursiveInRoutineDirOpt(contentCh chan *clientContent, d
 	if bucket == "" && object == "" {
 		var e error
 		allBuckets = true
-		buckets, e = c.api.ListBuckets()
+		buckets, e = c.getAPI().ListBuckets()
 		if e != nil {
 			contentCh <- &clientContent{Err: probe.NewError(e)}
 			return
@@ -1874,7 +2264,7 @@ func (c *s3Client) listInRoutine(contentCh chan *clientContent, metadata bool) {
 	b, o := c.url2BucketAndObject()
 	switch {
 	case b == "" && o == "":
-		buckets, e := c.api.ListBuckets()
+		buckets, e := c.getAPI().ListBuckets()
 		if e != nil {
 			contentCh <- &clientContent{
 				Err: probe.NewError(e),
@@ -1929,6 +2319,8 @@ const (
 	// s3StorageClassRedundancy = "REDUCED_REDUNDANCY"
 	// Archive access.
 	s3StorageClassGlacier = "GLACIER"
+	// Long term archive access.
+	s3StorageClassDeepArchive = "DEEP_ARCHIVE"
 )
 
 func (c *s3Client) listRecursiveInRoutine(contentCh chan *clientContent, metadata bool) {
@@ -1937,7 +2329,7 @@ func (c *s3Client) listRecursiveInRoutine(contentCh chan *clientContent, metadat
 	b, o := c.url2BucketAndObject()
 	switch {
 	case b == "" && o == "":
-		buckets, err := c.api.ListBuckets()
+		buckets, err := c.getAPI().ListBuckets()
 		if err != nil {
 			contentCh <- &clientContent{
 				Err: probe.NewError(err),
@@ -1960,6 +2352,7 @@ func (c *s3Client) listRecursiveInRoutine(contentCh chan *clientContent, metadat
 				content.StorageClass = object.StorageClass
 				content.Size = object.Size
 				content.ETag = object.ETag
+				content.Owner = objectOwner(object)
 				content.Time = object.LastModified
 				content.Type = os.FileMode(0664)
 				content.Expires = object.Expires
@@ -1970,6 +2363,8 @@ func (c *s3Client) listRecursiveInRoutine(contentCh chan *clientContent, metadat
 				contentCh <- content
 			}
 		}
+	case c.config.ListParallel > 1:
+		c.listRecursiveParallel(contentCh, b, o, metadata, c.config.ListParallel)
 	default:
 		isRecursive := true
 		for object := range c.listObjectWrapper(b, o, isRecursive, nil, metadata) {
@@ -1986,6 +2381,8 @@ func (c *s3Client) listRecursiveInRoutine(contentCh chan *clientContent, metadat
 			content.URL = url
 			content.Size = object.Size
 			content.ETag = object.ETag
+			content.Owner = objectOwner(object)
+			content.StorageClass = object.StorageClass
 			content.Time = object.LastModified
 			content.Type = os.FileMode(0664)
 			content.Expires = object.Expires
@@ -2003,10 +2400,17 @@ func (c *s3Client) ShareDownload(expires time.Duration) (string, *probe.Error) {
 	bucket, object := c.url2BucketAndObject()
 	// No additional request parameters are set for the time being.
 	reqParams := make(url.Values)
-	presignedURL, e := c.api.PresignedGetObject(bucket, object, expires, reqParams)
+	presignedURL, e := c.getAPI().PresignedGetObject(bucket, object, expires, reqParams)
 	if e != nil {
 		return "", probe.NewError(e)
 	}
+	// Sign with the time of the server once the local clock is known to
+	// be off, the URL would be expired or not yet valid otherwise.
+	if atomic.LoadInt64(&clockOffset) != 0 {
+		if signed := represignV4(presignedURL, http.MethodGet, c.config.SecretKey, signingTime()); signed != nil {
+			presignedURL = signed
+		}
+	}
 	return presignedURL.String(), nil
 }
 
@@ -2014,7 +2418,7 @@ func (c *s3Client) ShareDownload(expires time.Duration) (string, *probe.Error) {
 func (c *s3Client) ShareUpload(isRecursive bool, expires time.Duration, contentType string) (string, map[string]string, *probe.Error) {
 	bucket, object := c.url2BucketAndObject()
 	p := minio.NewPostPolicy()
-	if e := p.SetExpires(UTCNow().Add(expires)); e != nil {
+	if e := p.SetExpires(signingTime().Add(expires)); e != nil {
 		return "", nil, probe.NewError(e)
 	}
 	if strings.TrimSpace(contentType) != "" || contentType != "" {
@@ -2033,10 +2437,13 @@ func (c *s3Client) ShareUpload(isRecursive bool, expires time.Duration, contentT
 			return "", nil, probe.NewError(e)
 		}
 	}
-	u, m, e := c.api.PresignedPostPolicy(p)
+	u, m, e := c.getAPI().PresignedPostPolicy(p)
 	if e != nil {
 		return "", nil, probe.NewError(e)
 	}
+	if atomic.LoadInt64(&clockOffset) != 0 {
+		represignPostPolicyV4(m, c.config.SecretKey, signingTime())
+	}
 	return u.String(), m, nil
 }
 
@@ -2044,7 +2451,7 @@ func (c *s3Client) ShareUpload(isRecursive bool, expires time.Duration, contentT
 func (c *s3Client) SetObjectLockConfig(mode *minio.RetentionMode, validity *uint, unit *minio.ValidityUnit) *probe.Error {
 	bucket, _ := c.url2BucketAndObject()
 
-	err := c.api.SetBucketObjectLockConfig(bucket, mode, validity, unit)
+	err := c.getAPI().SetBucketObjectLockConfig(bucket, mode, validity, unit)
 	if err != nil {
 		return probe.NewError(err)
 	}
@@ -2060,7 +2467,7 @@ func (c *s3Client) PutObjectRetention(mode *minio.RetentionMode, retainUntilDate
 		RetainUntilDate: retainUntilDate,
 		Mode:            mode,
 	}
-	err := c.api.PutObjectRetention(bucket, object, opts)
+	err := c.getAPI().PutObjectRetention(bucket, object, opts)
 	if err != nil {
 		return probe.NewError(err)
 	}
@@ -2068,14 +2475,171 @@ func (c *s3Client) PutObjectRetention(mode *minio.RetentionMode, retainUntilDate
 	return nil
 }
 
+// PutObjectLegalHold sets the legal hold status, ON or OFF, of an object.
+func (c *s3Client) PutObjectLegalHold(status string) *probe.Error {
+	bucket, object := c.url2BucketAndObject()
+	if bucket == "" {
+		return probe.NewError(BucketNameEmpty{})
+	}
+	if object == "" {
+		return probe.NewError(ObjectMissing{})
+	}
+
+	legalHold := struct {
+		XMLName xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ LegalHold"`
+		Status  string   `xml:"Status"`
+	}{Status: status}
+	body, e := xml.Marshal(legalHold)
+	if e != nil {
+		return probe.NewError(e)
+	}
+
+	// The minio-go client does not have a legal hold API yet,
+	// the request is signed with its credentials and region.
+	sum := md5.Sum(body)
+	header := http.Header{}
+	header.Set("Content-Md5", base64.StdEncoding.EncodeToString(sum[:]))
+	header.Set("Content-Type", "application/xml")
+	resp, e := c.signedDo(context.Background(), http.MethodPut, bucket, object, url.Values{"legal-hold": []string{""}},
+		header, bytes.NewReader(body), int64(len(body)))
+	if e != nil {
+		switch minio.ToErrorResponse(e).Code {
+		case "NoSuchBucket":
+			return probe.NewError(BucketDoesNotExist{Bucket: bucket})
+		case "NoSuchKey":
+			return probe.NewError(ObjectMissing{})
+		case "NotImplemented":
+			return probe.NewError(APINotImplemented{
+				API:     "PutObjectLegalHold",
+				APIType: c.targetURL.Scheme + "://" + c.targetURL.Host,
+			})
+		}
+		return probe.NewError(e)
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// RestoreObject requests a temporary copy of an archived object to be
+// restored for days, retrieved with the given tier.
+func (c *s3Client) RestoreObject(days int, tier string) *probe.Error {
+	bucket, object := c.url2BucketAndObject()
+	if bucket == "" {
+		return probe.NewError(BucketNameEmpty{})
+	}
+	if object == "" {
+		return probe.NewError(ObjectMissing{})
+	}
+
+	restore := struct {
+		XMLName              xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ RestoreRequest"`
+		Days                 int      `xml:"Days"`
+		GlacierJobParameters struct {
+			Tier string `xml:"Tier"`
+		} `xml:"GlacierJobParameters"`
+	}{Days: days}
+	restore.GlacierJobParameters.Tier = tier
+	body, e := xml.Marshal(restore)
+	if e != nil {
+		return probe.NewError(e)
+	}
+
+	// The minio-go client does not have a restore API yet.
+	sum := md5.Sum(body)
+	header := http.Header{}
+	header.Set("Content-Md5", base64.StdEncoding.EncodeToString(sum[:]))
+	header.Set("Content-Type", "application/xml")
+	resp, e := c.signedDo(context.Background(), http.MethodPost, bucket, object, url.Values{"restore": []string{""}},
+		header, bytes.NewReader(body), int64(len(body)))
+	if e != nil {
+		switch minio.ToErrorResponse(e).Code {
+		case "NoSuchBucket":
+			return probe.NewError(BucketDoesNotExist{Bucket: bucket})
+		case "NoSuchKey":
+			return probe.NewError(ObjectMissing{})
+		case "NotImplemented":
+			return probe.NewError(APINotImplemented{
+				API:     "RestoreObject",
+				APIType: c.targetURL.Scheme + "://" + c.targetURL.Host,
+			})
+		}
+		return probe.NewError(e)
+	}
+	resp.Body.Close()
+	return nil
+}
+
 // Get object lock configuration of bucket.
 func (c *s3Client) GetObjectLockConfig() (mode *minio.RetentionMode, validity *uint, unit *minio.ValidityUnit, perr *probe.Error) {
 	bucket, _ := c.url2BucketAndObject()
 
-	mode, validity, unit, err := c.api.GetBucketObjectLockConfig(bucket)
+	mode, validity, unit, err := c.getAPI().GetBucketObjectLockConfig(bucket)
 	if err != nil {
 		return nil, nil, nil, probe.NewError(err)
 	}
 
 	return mode, validity, unit, nil
 }
+
+// pingRegion - returns the region signing pings, the one of the host
+// if set, else the location of the bucket of the URL if any.
+func (c *s3Client) pingRegion() string {
+	if c.config.Region != "" {
+		return c.config.Region
+	}
+	if bucket, _ := c.url2BucketAndObject(); bucket != "" {
+		if location, e := c.getAPI().GetBucketLocation(bucket); e == nil && location != "" {
+			return location
+		}
+	}
+	return "us-east-1"
+}
+
+// Ping lists the buckets with a single signed request, without the
+// retries of the minio-go client, and returns its round-trip time.
+// Requests signed for another region than the one of the server are
+// sent again for the region it reports.
+func (c *s3Client) Ping() (time.Duration, *probe.Error) {
+	latency, e := c.ping(c.pingRegion())
+	if errResponse, ok := e.(minio.ErrorResponse); ok && errResponse.Region != "" &&
+		(errResponse.Code == "AuthorizationHeaderMalformed" || errResponse.Code == "InvalidRegion") {
+		latency, e = c.ping(errResponse.Region)
+	}
+	if e != nil {
+		return latency, probe.NewError(e)
+	}
+	return latency, nil
+}
+
+// ping - sends a ping signed for region.
+func (c *s3Client) ping(region string) (time.Duration, error) {
+	req, e := http.NewRequest(http.MethodGet, c.targetURL.Scheme+"://"+c.targetURL.Host+"/", nil)
+	if e != nil {
+		return 0, e
+	}
+	req.Header.Set("X-Amz-Content-Sha256", emptySHA256Hex)
+	if c.config.AccessKey != "" && c.config.SecretKey != "" {
+		if strings.ToUpper(c.config.Signature) == "S3V2" {
+			req = s3signer.SignV2(*req, c.config.AccessKey, c.config.SecretKey, false)
+		} else {
+			req = s3signer.SignV4(*req, c.config.AccessKey, c.config.SecretKey, "", region)
+		}
+	}
+
+	start := nowFunc()
+	resp, e := (&http.Client{Transport: c.transport}).Do(req)
+	if e != nil {
+		return 0, e
+	}
+	defer resp.Body.Close()
+	latency := nowFunc().Sub(start)
+	if resp.StatusCode != http.StatusOK {
+		errResponse := minio.ErrorResponse{StatusCode: resp.StatusCode}
+		if xml.NewDecoder(resp.Body).Decode(&errResponse) != nil {
+			errResponse.Code = resp.Status
+			errResponse.Message = resp.Status
+		}
+		return latency, errResponse
+	}
+	return latency, nil
+}
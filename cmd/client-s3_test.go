@@ -20,12 +20,23 @@ package cmd
 import (
 	"bytes"
 	"context"
+	"encoding/base64"
+	"encoding/json"
 	"io"
+	"io/ioutil"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
+	"github.com/minio/mc/pkg/probe"
 	minio "github.com/minio/minio-go/v6"
+	"github.com/minio/minio-go/v6/pkg/s3signer"
 	. "gopkg.in/check.v1"
 )
 
@@ -261,3 +272,887 @@ func (s *TestSuite) TestSelectCompressionType(c *C) {
 		c.Assert(cType, DeepEquals, test.compressionType)
 	}
 }
+
+// redirectHandler redirects every request to the regional endpoint.
+type redirectHandler struct {
+	bucket   string
+	region   string
+	endpoint string
+}
+
+func (h redirectHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("x-amz-bucket-region", h.region)
+	w.WriteHeader(http.StatusMovedPermanently)
+	if r.Method != http.MethodHead {
+		w.Write([]byte("<Error><Code>PermanentRedirect</Code><Message>The bucket you are attempting to access must be addressed using the specified endpoint.</Message><Bucket>" +
+			h.bucket + "</Bucket><Endpoint>" + h.endpoint + "</Endpoint></Error>"))
+	}
+}
+
+// recordingHandler records the authorization of the requests it serves.
+type recordingHandler struct {
+	mutex          *sync.Mutex
+	authorizations *[]string
+	handler        http.Handler
+}
+
+func (h recordingHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mutex.Lock()
+	*h.authorizations = append(*h.authorizations, r.Header.Get("Authorization"))
+	h.mutex.Unlock()
+	h.handler.ServeHTTP(w, r)
+}
+
+// Test that requests are retried against the region a bucket is redirected to.
+func (s *TestSuite) TestRegionRedirect(c *C) {
+	var authorizations []string
+	regional := httptest.NewServer(recordingHandler{
+		mutex:          &sync.Mutex{},
+		authorizations: &authorizations,
+		handler:        newFakeS3Server(map[string][]byte{"bucket/object": []byte("0123456789")}),
+	})
+	defer regional.Close()
+
+	regionalURL, e := url.Parse(regional.URL)
+	c.Assert(e, IsNil)
+	server := httptest.NewServer(redirectHandler{
+		bucket:   "bucket",
+		region:   "eu-west-1",
+		endpoint: regionalURL.Host,
+	})
+	defer server.Close()
+
+	conf := newTestS3Config(server.URL + "/bucket/object")
+	s3c, err := s3New(conf)
+	c.Assert(err, IsNil)
+
+	content, err := s3c.Stat(false, true, false, nil)
+	c.Assert(err, IsNil)
+	c.Assert(content.Size, Equals, int64(10))
+
+	c.Assert(len(authorizations) > 0, Equals, true)
+	for _, authorization := range authorizations {
+		c.Assert(strings.Contains(authorization, "/eu-west-1/s3/"), Equals, true)
+	}
+
+	// New clients for the bucket go straight to the regional endpoint.
+	authorizations = nil
+	s3c, err = s3New(conf)
+	c.Assert(err, IsNil)
+	_, err = s3c.Stat(false, false, false, nil)
+	c.Assert(err, IsNil)
+	c.Assert(len(authorizations) > 0, Equals, true)
+}
+
+// Test that uploads and downloads follow the region a bucket is
+// redirected to.
+func (s *TestSuite) TestRegionRedirectObjects(c *C) {
+	var authorizations []string
+	handler := newFakeS3Server(nil)
+	regional := httptest.NewServer(recordingHandler{
+		mutex:          &sync.Mutex{},
+		authorizations: &authorizations,
+		handler:        handler,
+	})
+	defer regional.Close()
+	regionalURL, e := url.Parse(regional.URL)
+	c.Assert(e, IsNil)
+
+	// A server of its own per client, the redirects of the others are
+	// not known yet.
+	var servers []*httptest.Server
+	defer func() {
+		for _, server := range servers {
+			server.Close()
+		}
+	}()
+	newClient := func() Client {
+		server := httptest.NewServer(redirectHandler{
+			bucket:   "bucket",
+			region:   "eu-west-1",
+			endpoint: regionalURL.Host,
+		})
+		servers = append(servers, server)
+		conf := newTestS3Config(server.URL + "/bucket/object")
+		conf.Lookup = minio.BucketLookupPath
+		clnt, err := s3New(conf)
+		c.Assert(err, IsNil)
+		return clnt
+	}
+	data := "uploaded to the region of the bucket"
+	// Seekable sources are rewound, others are sent again as long as
+	// nothing was sent.
+	for _, reader := range []io.Reader{strings.NewReader(data), ioutil.NopCloser(strings.NewReader(data))} {
+		handler.mutex.Lock()
+		delete(handler.objects, "bucket/object")
+		handler.mutex.Unlock()
+		n, err := newClient().Put(context.Background(), reader, int64(len(data)), map[string]string{}, nil, nil)
+		c.Assert(err, IsNil)
+		c.Assert(n, Equals, int64(len(data)))
+		handler.mutex.Lock()
+		c.Assert(string(handler.objects["bucket/object"]), Equals, data)
+		handler.mutex.Unlock()
+	}
+
+	reader, err := newClient().Get(nil)
+	c.Assert(err, IsNil)
+	got, e := ioutil.ReadAll(reader)
+	c.Assert(e, IsNil)
+	c.Assert(string(got), Equals, data)
+	reader.Close()
+
+	reader, err = newClient().GetRange(3, 0, nil)
+	c.Assert(err, IsNil)
+	got, e = ioutil.ReadAll(reader)
+	c.Assert(e, IsNil)
+	c.Assert(string(got), Equals, data[3:])
+	reader.Close()
+
+	// Parallel transfers of a client are all redirected.
+	clnt := newClient()
+	var wg sync.WaitGroup
+	errs := make(chan *probe.Error, 8)
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			reader, err := clnt.Get(nil)
+			if err == nil {
+				_, e := ioutil.ReadAll(reader)
+				err = probe.NewError(e)
+				reader.Close()
+			}
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		c.Assert(err, IsNil)
+	}
+
+	c.Assert(len(authorizations) > 0, Equals, true)
+	for _, authorization := range authorizations {
+		c.Assert(strings.Contains(authorization, "/eu-west-1/s3/"), Equals, true)
+	}
+}
+
+// Test that anonymous clients send no credentials.
+func (s *TestSuite) TestAnonymousRequests(c *C) {
+	var authorizations []string
+	server := httptest.NewServer(recordingHandler{
+		mutex:          &sync.Mutex{},
+		authorizations: &authorizations,
+		handler:        newFakeS3Server(map[string][]byte{"bucket/object": []byte("0123456789")}),
+	})
+	defer server.Close()
+
+	host := testHost(server.URL)
+	cfg := newConfigV9()
+	cfg.Hosts["signed"] = host
+	host.Anonymous = true
+	cfg.Hosts["public"] = host
+	defer withTestConfig(cfg.Hosts)()
+	defer func(anonymous bool) { globalAnonymous = anonymous }(globalAnonymous)
+
+	stat := func(alias string) {
+		authorizations = nil
+		clnt, err := newClient(alias + "/bucket/object")
+		c.Assert(err, IsNil)
+		content, err := clnt.Stat(false, false, false, nil)
+		c.Assert(err, IsNil)
+		c.Assert(content.Size, Equals, int64(10))
+		c.Assert(len(authorizations) > 0, Equals, true)
+	}
+
+	stat("signed")
+	c.Assert(authorizations[0], Not(Equals), "")
+
+	// Aliases marked anonymous, and any alias with --no-sign-request.
+	stat("public")
+	c.Assert(authorizations, DeepEquals, make([]string, len(authorizations)))
+	globalAnonymous = true
+	stat("signed")
+	c.Assert(authorizations, DeepEquals, make([]string, len(authorizations)))
+}
+
+// Test that re-signing a request at its original time reproduces the signature.
+func (s *TestSuite) TestResignV4(c *C) {
+	req, e := http.NewRequest(http.MethodGet, "http://localhost:9000/bucket/object?prefix=a b&max-keys=10", nil)
+	c.Assert(e, IsNil)
+	req.Header.Set("X-Amz-Content-Sha256", "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855")
+	req.Header.Set("X-Amz-Meta-Project", "  apollo   moon ")
+	signed := s3signer.SignV4(*req, testAccessKey, testSecretKey, "", "eu-west-1")
+
+	signedAt, e := time.Parse(iso8601DateFormat, signed.Header.Get("X-Amz-Date"))
+	c.Assert(e, IsNil)
+	resigned := resignV4(signed, testAccessKey, testSecretKey, signedAt)
+	c.Assert(resigned, NotNil)
+	c.Assert(resigned.Header.Get("Authorization"), Equals, signed.Header.Get("Authorization"))
+
+	later := signedAt.Add(time.Hour)
+	resigned = resignV4(signed, testAccessKey, testSecretKey, later)
+	c.Assert(resigned.Header.Get("X-Amz-Date"), Equals, later.Format(iso8601DateFormat))
+	c.Assert(resigned.Header.Get("Authorization"), Not(Equals), signed.Header.Get("Authorization"))
+}
+
+// skewedHandler rejects requests signed more than 15 minutes away from
+// its own clock, which runs ahead of the local clock by offset.
+type skewedHandler struct {
+	offset  time.Duration
+	mutex   *sync.Mutex
+	dates   *[]time.Time
+	handler http.Handler
+}
+
+func (h skewedHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	serverTime := UTCNow().Add(h.offset)
+	date, e := time.Parse(iso8601DateFormat, r.Header.Get("X-Amz-Date"))
+	if e != nil || date.Sub(serverTime) > 15*time.Minute || serverTime.Sub(date) > 15*time.Minute {
+		w.Header().Set("Date", serverTime.Format(http.TimeFormat))
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte("<Error><Code>RequestTimeTooSkewed</Code><Message>The difference between the request time and the server's time is too large.</Message></Error>"))
+		return
+	}
+	h.mutex.Lock()
+	*h.dates = append(*h.dates, date)
+	h.mutex.Unlock()
+	h.handler.ServeHTTP(w, r)
+}
+
+// Test that requests are retried with the server time after a clock skew error.
+func (s *TestSuite) TestClockSkewCorrection(c *C) {
+	defer atomic.StoreInt64(&clockOffset, 0)
+
+	var dates []time.Time
+	offset := 2 * time.Hour
+	server := httptest.NewServer(skewedHandler{
+		offset:  offset,
+		mutex:   &sync.Mutex{},
+		dates:   &dates,
+		handler: newFakeS3Server(map[string][]byte{"bucket/object": []byte("0123456789")}),
+	})
+	defer server.Close()
+
+	conf := newTestS3Config(server.URL + "/bucket/object")
+	s3c, err := s3New(conf)
+	c.Assert(err, IsNil)
+
+	content, err := s3c.Stat(false, true, false, nil)
+	c.Assert(err, IsNil)
+	c.Assert(content.Size, Equals, int64(10))
+
+	c.Assert(len(dates) > 0, Equals, true)
+	for _, date := range dates {
+		c.Assert(date.After(UTCNow().Add(offset-time.Minute)), Equals, true)
+	}
+	c.Assert(time.Duration(atomic.LoadInt64(&clockOffset)) > offset-time.Minute, Equals, true)
+}
+
+// Test that HEAD requests, rejected without a body, are retried with the
+// time of the Date header.
+func (s *TestSuite) TestClockSkewHead(c *C) {
+	defer atomic.StoreInt64(&clockOffset, 0)
+
+	var dates []time.Time
+	offset := -time.Hour
+	server := httptest.NewServer(skewedHandler{
+		offset:  offset,
+		mutex:   &sync.Mutex{},
+		dates:   &dates,
+		handler: newFakeS3Server(map[string][]byte{"bucket/object": []byte("0123456789")}),
+	})
+	defer server.Close()
+
+	req, e := http.NewRequest(http.MethodHead, server.URL+"/bucket/object", nil)
+	c.Assert(e, IsNil)
+	req.Header.Set("X-Amz-Content-Sha256", "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855")
+	signed := s3signer.SignV4(*req, testAccessKey, testSecretKey, "", "us-east-1")
+
+	transport := skewTransport{accessKey: testAccessKey, secretKey: testSecretKey, transport: http.DefaultTransport}
+	resp, e := transport.RoundTrip(signed)
+	c.Assert(e, IsNil)
+	resp.Body.Close()
+	c.Assert(resp.StatusCode, Equals, http.StatusOK)
+	c.Assert(len(dates), Equals, 1)
+	c.Assert(time.Duration(atomic.LoadInt64(&clockOffset)) < offset+time.Minute, Equals, true)
+}
+
+// delayedTransport advances a fake clock by the next latency on every round trip.
+type delayedTransport struct {
+	clock     *time.Time
+	latencies *[]time.Duration
+}
+
+func (t delayedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	latency := (*t.latencies)[0]
+	*t.latencies = (*t.latencies)[1:]
+	*t.clock = t.clock.Add(latency)
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       ioutil.NopCloser(strings.NewReader("0123456789")),
+		Request:    req,
+	}, nil
+}
+
+func (s *TestSuite) TestTransferStats(c *C) {
+	clock := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	nowFunc = func() time.Time { return clock }
+	defer func() { nowFunc = time.Now }()
+	stats := newTransferStats()
+
+	var latencies []time.Duration
+	for i := 100; i >= 1; i-- {
+		latencies = append(latencies, time.Duration(i)*time.Millisecond)
+	}
+	transport := statsTransport{
+		stats:     stats,
+		transport: delayedTransport{clock: &clock, latencies: &latencies},
+	}
+	for i := 0; i < 100; i++ {
+		req, e := http.NewRequest(http.MethodPut, "http://localhost:9000/bucket/object", strings.NewReader("abcde"))
+		c.Assert(e, IsNil)
+		resp, e := transport.RoundTrip(req)
+		c.Assert(e, IsNil)
+		_, e = io.Copy(ioutil.Discard, resp.Body)
+		c.Assert(e, IsNil)
+		resp.Body.Close()
+	}
+
+	msg := stats.message()
+	c.Assert(msg.Requests, Equals, 100)
+	c.Assert(msg.Bytes, Equals, int64(100*(5+10)))
+	c.Assert(msg.P50, Equals, 50*time.Millisecond)
+	c.Assert(msg.P95, Equals, 95*time.Millisecond)
+	c.Assert(msg.WallTime, Equals, 5050*time.Millisecond)
+}
+
+// Test that connections reused between sequential requests are counted.
+func (s *TestSuite) TestTransferStatsConnReuse(c *C) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	stats := newTransferStats()
+	tr := &http.Transport{}
+	defer tr.CloseIdleConnections()
+	transport := statsTransport{stats: stats, transport: tr}
+	for i := 0; i < 2; i++ {
+		req, e := http.NewRequest(http.MethodGet, server.URL+"/bucket/object", nil)
+		c.Assert(e, IsNil)
+		resp, e := transport.RoundTrip(req)
+		c.Assert(e, IsNil)
+		// Drain the body for the connection to return to the idle pool.
+		_, e = io.Copy(ioutil.Discard, resp.Body)
+		c.Assert(e, IsNil)
+		resp.Body.Close()
+	}
+
+	msg := stats.message()
+	c.Assert(msg.NewConns, Equals, int64(1))
+	c.Assert(msg.ReusedConns, Equals, int64(1))
+}
+
+// Test that a frozen clock decides the expiry of presigned upload policies.
+func (s *TestSuite) TestShareUploadFrozenClock(c *C) {
+	frozen := time.Date(2020, 1, 1, 10, 0, 0, 0, time.UTC)
+	nowFunc = func() time.Time { return frozen }
+	defer func() { nowFunc = time.Now }()
+
+	server := httptest.NewServer(newFakeS3Server(nil))
+	defer server.Close()
+
+	conf := newTestS3Config(server.URL + "/bucket/object")
+	s3c, err := s3New(conf)
+	c.Assert(err, IsNil)
+
+	_, formData, err := s3c.ShareUpload(false, 2*time.Hour, "")
+	c.Assert(err, IsNil)
+
+	policyJSON, e := base64.StdEncoding.DecodeString(formData["policy"])
+	c.Assert(e, IsNil)
+	var policy struct {
+		Expiration time.Time `json:"expiration"`
+	}
+	c.Assert(json.Unmarshal(policyJSON, &policy), IsNil)
+	c.Assert(policy.Expiration.Equal(frozen.Add(2*time.Hour)), Equals, true)
+}
+
+// Test that presigned URLs and upload policies are signed with the time
+// of the server once the offset of the local clock is known.
+func (s *TestSuite) TestShareClockOffset(c *C) {
+	frozen := time.Date(2020, 1, 1, 23, 30, 0, 0, time.UTC)
+	nowFunc = func() time.Time { return frozen }
+	defer func() { nowFunc = time.Now }()
+	defer atomic.StoreInt64(&clockOffset, 0)
+
+	server := httptest.NewServer(newFakeS3Server(nil))
+	defer server.Close()
+
+	conf := newTestS3Config(server.URL + "/bucket/object")
+	s3c, err := s3New(conf)
+	c.Assert(err, IsNil)
+
+	// Signing a URL again at its own date yields the same signature.
+	shareURL, err := s3c.ShareDownload(time.Hour)
+	c.Assert(err, IsNil)
+	u, e := url.Parse(shareURL)
+	c.Assert(e, IsNil)
+	date, e := time.Parse(iso8601DateFormat, u.Query().Get("X-Amz-Date"))
+	c.Assert(e, IsNil)
+	signed := represignV4(u, http.MethodGet, conf.SecretKey, date)
+	c.Assert(signed, NotNil)
+	c.Assert(signed.Query().Get("X-Amz-Signature"), Equals, u.Query().Get("X-Amz-Signature"))
+
+	// The server clock is two hours ahead, already past midnight.
+	serverTime := frozen.Add(2 * time.Hour)
+	atomic.StoreInt64(&clockOffset, int64(2*time.Hour))
+
+	shareURL, err = s3c.ShareDownload(time.Hour)
+	c.Assert(err, IsNil)
+	u, e = url.Parse(shareURL)
+	c.Assert(e, IsNil)
+	query := u.Query()
+	c.Assert(query.Get("X-Amz-Date"), Equals, serverTime.Format(iso8601DateFormat))
+	c.Assert(query.Get("X-Amz-Credential"), Equals, s3signer.GetCredential(conf.AccessKey, "us-east-1", serverTime))
+	c.Assert(query.Get("X-Amz-Expires"), Equals, "3600")
+
+	_, formData, err := s3c.ShareUpload(false, 2*time.Hour, "")
+	c.Assert(err, IsNil)
+	c.Assert(formData["x-amz-date"], Equals, serverTime.Format(iso8601DateFormat))
+	c.Assert(formData["x-amz-credential"], Equals, s3signer.GetCredential(conf.AccessKey, "us-east-1", serverTime))
+	c.Assert(formData["x-amz-signature"], Equals, s3signer.PostPresignSignatureV4(formData["policy"], serverTime, conf.SecretKey, "us-east-1"))
+
+	policyJSON, e := base64.StdEncoding.DecodeString(formData["policy"])
+	c.Assert(e, IsNil)
+	var policy struct {
+		Expiration time.Time       `json:"expiration"`
+		Conditions [][]interface{} `json:"conditions"`
+	}
+	c.Assert(json.Unmarshal(policyJSON, &policy), IsNil)
+	c.Assert(policy.Expiration.Equal(serverTime.Add(2*time.Hour)), Equals, true)
+	conditions := make(map[string]interface{})
+	for _, condition := range policy.Conditions {
+		conditions[condition[1].(string)] = condition[2]
+	}
+	c.Assert(conditions["$x-amz-date"], Equals, formData["x-amz-date"])
+	c.Assert(conditions["$x-amz-credential"], Equals, formData["x-amz-credential"])
+	c.Assert(conditions["$key"], Equals, "object")
+}
+
+// conditionalPutHandler stores uploads of single objects, answering uploads
+// with If-None-Match like S3 when supported and NotImplemented otherwise.
+type conditionalPutHandler struct {
+	mutex       *sync.Mutex
+	objects     map[string]string
+	unsupported bool
+}
+
+func (h conditionalPutHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if _, ok := r.URL.Query()["location"]; ok {
+		w.Write([]byte(`<LocationConstraint xmlns="http://s3.amazonaws.com/doc/2006-03-01/"></LocationConstraint>`))
+		return
+	}
+	if r.Method != http.MethodPut {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	if r.Header.Get("If-None-Match") == "*" {
+		if h.unsupported {
+			w.WriteHeader(http.StatusNotImplemented)
+			w.Write([]byte("<Error><Code>NotImplemented</Code><Message>A header you provided implies functionality that is not implemented</Message></Error>"))
+			return
+		}
+		if _, ok := h.objects[r.URL.Path]; ok {
+			w.WriteHeader(http.StatusPreconditionFailed)
+			w.Write([]byte("<Error><Code>PreconditionFailed</Code><Message>At least one of the pre-conditions you specified did not hold</Message></Error>"))
+			return
+		}
+	}
+	body, _ := ioutil.ReadAll(r.Body)
+	if strings.HasPrefix(r.Header.Get("X-Amz-Content-Sha256"), "STREAMING-") {
+		body = decodeAWSChunked(body)
+	}
+	h.objects[r.URL.Path] = string(body)
+	w.Header().Set("ETag", `"etag"`)
+}
+
+// Test that uploads with --if-not-exists do not overwrite existing objects.
+func (s *TestSuite) TestPutIfNotExists(c *C) {
+	handler := conditionalPutHandler{mutex: &sync.Mutex{}, objects: map[string]string{}}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	put := func(object, data string, ifNotExists bool) *probe.Error {
+		conf := newTestS3Config(server.URL + "/bucket/" + object)
+		conf.IfNotExists = ifNotExists
+		clnt, err := s3New(conf)
+		c.Assert(err, IsNil)
+		_, err = clnt.Put(context.Background(), strings.NewReader(data), int64(len(data)), map[string]string{}, nil, nil)
+		return err
+	}
+
+	c.Assert(put("lock", "owner1", true), IsNil)
+	c.Assert(handler.objects["/bucket/lock"], Equals, "owner1")
+
+	// The second claim is refused leaving the object in place.
+	err := put("lock", "owner2", true)
+	c.Assert(err, NotNil)
+	_, ok := err.ToGoError().(ObjectPreconditionFailed)
+	c.Assert(ok, Equals, true)
+	c.Assert(handler.objects["/bucket/lock"], Equals, "owner1")
+
+	// Uploads without the condition still overwrite the object.
+	c.Assert(put("lock", "owner2", false), IsNil)
+	c.Assert(handler.objects["/bucket/lock"], Equals, "owner2")
+
+	// Servers not implementing conditional uploads are reported as such.
+	handler.unsupported = true
+	unsupportedServer := httptest.NewServer(handler)
+	defer unsupportedServer.Close()
+	server = unsupportedServer
+	err = put("other", "owner1", true)
+	c.Assert(err, NotNil)
+	_, ok = err.ToGoError().(APINotImplemented)
+	c.Assert(ok, Equals, true)
+}
+
+// Test that empty sources are uploaded with a single empty PUT, their
+// size known or not and with checksums too.
+func (s *TestSuite) TestPutZeroBytes(c *C) {
+	handler := newFakeS3Server(nil)
+	var requestsMutex sync.Mutex
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if _, ok := query["location"]; !ok {
+			request := r.Method
+			if _, ok := query["uploads"]; ok {
+				request += " uploads"
+			}
+			if uploadID := query.Get("uploadId"); uploadID != "" {
+				request += " uploadId"
+			}
+			requestsMutex.Lock()
+			requests = append(requests, request)
+			requestsMutex.Unlock()
+		}
+		handler.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	for i, testCase := range []struct {
+		size     int64
+		checksum checksumAlgorithm
+	}{
+		{0, checksumNone},
+		{-1, checksumNone},
+		{0, checksumSHA256},
+		{-1, checksumSHA256},
+	} {
+		conf := newTestS3Config(server.URL + "/bucket/empty")
+		conf.Checksum = testCase.checksum
+		clnt, err := s3New(conf)
+		c.Assert(err, IsNil)
+
+		requests = nil
+		delete(handler.objects, "bucket/empty")
+		// Not seekable, as read from a pipe.
+		reader := io.MultiReader(strings.NewReader(""))
+		n, err := clnt.Put(context.Background(), reader, testCase.size, map[string]string{}, nil, nil)
+		c.Assert(err, IsNil, Commentf("Test %d", i+1))
+		c.Assert(n, Equals, int64(0), Commentf("Test %d", i+1))
+		data, ok := handler.objects["bucket/empty"]
+		c.Assert(ok, Equals, true, Commentf("Test %d", i+1))
+		c.Assert(len(data), Equals, 0, Commentf("Test %d", i+1))
+		c.Assert(requests, DeepEquals, []string{"PUT"}, Commentf("Test %d", i+1))
+	}
+}
+
+// hostRecorder answers all requests itself, recording the host each
+// request is sent to.
+type hostRecorder struct {
+	mutex *sync.Mutex
+	hosts *[]string
+}
+
+func (t hostRecorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.mutex.Lock()
+	*t.hosts = append(*t.hosts, req.Method+" "+req.URL.Host)
+	t.mutex.Unlock()
+	body := ""
+	if _, ok := req.URL.Query()["location"]; ok {
+		body = `<LocationConstraint xmlns="http://s3.amazonaws.com/doc/2006-03-01/"></LocationConstraint>`
+	}
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Etag": {`"etag"`}},
+		Body:       ioutil.NopCloser(strings.NewReader(body)),
+		Request:    req,
+	}, nil
+}
+
+// Test that only object data requests go through the accelerate endpoint.
+func (s *TestSuite) TestAccelerate(c *C) {
+	conf := newTestS3Config("https://s3.amazonaws.com/accelerated/object")
+	conf.Lookup = minio.BucketLookupAuto
+	conf.Accelerate = true
+	clnt, err := s3New(conf)
+	c.Assert(err, IsNil)
+
+	var hosts []string
+	recorder := hostRecorder{mutex: &sync.Mutex{}, hosts: &hosts}
+	s3c := clnt.(*s3Client)
+	s3c.api.SetCustomTransport(recorder)
+	s3c.objectAPI.SetCustomTransport(recorder)
+
+	_, err = s3c.Put(context.Background(), strings.NewReader("hello"), 5, map[string]string{}, nil, nil)
+	c.Assert(err, IsNil)
+	c.Assert(hosts[len(hosts)-1], Equals, "PUT accelerated.s3-accelerate.amazonaws.com")
+
+	// Clients of the same host share the recording transport.
+	conf.HostURL = "https://s3.amazonaws.com/accelerated"
+	clnt, err = s3New(conf)
+	c.Assert(err, IsNil)
+	hosts = nil
+	err = clnt.MakeBucket("", false, false)
+	c.Assert(err, IsNil)
+	c.Assert(len(hosts), Equals, 1)
+	c.Assert(strings.Contains(hosts[0], amazonHostNameAccelerated), Equals, false)
+
+	// Acceleration requires DNS compatible bucket names.
+	conf.HostURL = "https://s3.amazonaws.com/not.accelerated/object"
+	_, err = s3New(conf)
+	c.Assert(err, NotNil)
+}
+
+// Test the time breakdown of requests profiled with --profile.
+func (s *TestSuite) TestProfileBreakdown(c *C) {
+	start := time.Date(2020, 1, 1, 10, 0, 0, 0, time.UTC)
+	at := func(ms int) time.Time { return start.Add(time.Duration(ms) * time.Millisecond) }
+	trace := &profileTrace{
+		start:        start,
+		dnsStart:     at(1),
+		dnsDone:      at(21),
+		connectStart: at(21),
+		connectDone:  at(31),
+		tlsStart:     at(31),
+		tlsDone:      at(61),
+		gotConn:      at(61),
+		wroteRequest: at(161),
+		firstByte:    at(211),
+		end:          at(261),
+	}
+	msg := trace.message("PUT s3.amazonaws.com/bucket/object")
+	c.Assert(msg, DeepEquals, profileMessage{
+		Request:   "PUT s3.amazonaws.com/bucket/object",
+		Requests:  1,
+		DNS:       20 * time.Millisecond,
+		Connect:   10 * time.Millisecond,
+		TLS:       30 * time.Millisecond,
+		FirstByte: 50 * time.Millisecond,
+		Transfer:  150 * time.Millisecond,
+		Total:     261 * time.Millisecond,
+	})
+
+	// Reused connections skip connection setup.
+	reused := &profileTrace{start: start, gotConn: at(1), wroteRequest: at(1), firstByte: at(11), end: at(12)}
+	reusedMsg := reused.message("GET s3.amazonaws.com/bucket/object")
+	c.Assert(reusedMsg.DNS+reusedMsg.Connect+reusedMsg.TLS, Equals, time.Duration(0))
+	c.Assert(reusedMsg.FirstByte, Equals, 10*time.Millisecond)
+
+	// Requests are printed one by one, or aggregated when too many.
+	profiles := &requestProfiles{}
+	profiles.record(msg)
+	profiles.record(reusedMsg)
+	c.Assert(profiles.summary(), DeepEquals, []profileMessage{msg, reusedMsg})
+	for i := 2; i <= profileMaxRequests; i++ {
+		profiles.record(reusedMsg)
+	}
+	summary := profiles.summary()
+	c.Assert(summary, HasLen, 1)
+	c.Assert(summary[0].Requests, Equals, profileMaxRequests+1)
+	c.Assert(summary[0].TLS, Equals, 30*time.Millisecond)
+	c.Assert(summary[0].Total, Equals, 261*time.Millisecond+time.Duration(profileMaxRequests)*12*time.Millisecond)
+}
+
+// Test that profiled requests through the transport are recorded once
+// their body is read.
+func (s *TestSuite) TestProfileTransport(c *C) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	profiles := &requestProfiles{}
+	tr := &http.Transport{}
+	defer tr.CloseIdleConnections()
+	transport := statsTransport{stats: newTransferStats(), profiles: profiles, transport: tr}
+	req, e := http.NewRequest(http.MethodGet, server.URL+"/bucket/object", nil)
+	c.Assert(e, IsNil)
+	resp, e := transport.RoundTrip(req)
+	c.Assert(e, IsNil)
+	c.Assert(profiles.summary(), HasLen, 0)
+	_, e = io.Copy(ioutil.Discard, resp.Body)
+	c.Assert(e, IsNil)
+	resp.Body.Close()
+
+	summary := profiles.summary()
+	c.Assert(summary, HasLen, 1)
+	c.Assert(summary[0].Request, Equals, "GET "+req.URL.Host+"/bucket/object")
+	c.Assert(summary[0].Total > 0, Equals, true)
+}
+
+// socks5Stub is a SOCKS5 proxy accepting connections authenticated
+// with user and password, recording the address of every connect.
+type socks5Stub struct {
+	user, password string
+	mutex          *sync.Mutex
+	targets        *[]string
+}
+
+func (p socks5Stub) serve(l net.Listener) {
+	for {
+		conn, e := l.Accept()
+		if e != nil {
+			return
+		}
+		go p.handle(conn)
+	}
+}
+
+func (p socks5Stub) handle(conn net.Conn) {
+	defer conn.Close()
+	// Greeting, the username/password method is required.
+	header := make([]byte, 2)
+	if _, e := io.ReadFull(conn, header); e != nil || header[0] != 5 {
+		return
+	}
+	methods := make([]byte, header[1])
+	if _, e := io.ReadFull(conn, methods); e != nil || !bytes.Contains(methods, []byte{2}) {
+		conn.Write([]byte{5, 0xff})
+		return
+	}
+	conn.Write([]byte{5, 2})
+	readField := func() string {
+		size := make([]byte, 1)
+		io.ReadFull(conn, size)
+		field := make([]byte, size[0])
+		io.ReadFull(conn, field)
+		return string(field)
+	}
+	io.ReadFull(conn, header[:1])
+	if user, password := readField(), readField(); user != p.user || password != p.password {
+		conn.Write([]byte{1, 1})
+		return
+	}
+	conn.Write([]byte{1, 0})
+
+	// Connect request.
+	request := make([]byte, 4)
+	if _, e := io.ReadFull(conn, request); e != nil || request[1] != 1 {
+		return
+	}
+	var host string
+	switch request[3] {
+	case 1:
+		ip := make([]byte, net.IPv4len)
+		io.ReadFull(conn, ip)
+		host = net.IP(ip).String()
+	case 3:
+		host = readField()
+	case 4:
+		ip := make([]byte, net.IPv6len)
+		io.ReadFull(conn, ip)
+		host = net.IP(ip).String()
+	}
+	port := make([]byte, 2)
+	io.ReadFull(conn, port)
+	target := net.JoinHostPort(host, strconv.Itoa(int(port[0])<<8|int(port[1])))
+	upstream, e := net.Dial("tcp", target)
+	if e != nil {
+		conn.Write([]byte{5, 5, 0, 1, 0, 0, 0, 0, 0, 0})
+		return
+	}
+	defer upstream.Close()
+	p.mutex.Lock()
+	*p.targets = append(*p.targets, target)
+	p.mutex.Unlock()
+	conn.Write([]byte{5, 0, 0, 1, 0, 0, 0, 0, 0, 0})
+	go io.Copy(upstream, conn)
+	io.Copy(conn, upstream)
+}
+
+// Test that connections are dialed through the SOCKS5 proxy.
+func (s *TestSuite) TestSOCKS5Proxy(c *C) {
+	l, e := net.Listen("tcp", "127.0.0.1:0")
+	c.Assert(e, IsNil)
+	defer l.Close()
+	var targets []string
+	go socks5Stub{user: "tunnel", password: "secret", mutex: &sync.Mutex{}, targets: &targets}.serve(l)
+
+	// Clients are cached by host and credentials, every ping gets a server of its own.
+	ping := func(proxyURL string) (*httptest.Server, *probe.Error) {
+		server := httptest.NewServer(http.HandlerFunc(pingHandler))
+		conf := newTestS3Config(server.URL)
+		var err *probe.Error
+		conf.SOCKS5Proxy, err = parseSOCKS5Proxy(proxyURL)
+		c.Assert(err, IsNil)
+		clnt, err := s3New(conf)
+		c.Assert(err, IsNil)
+		_, err = clnt.(*s3Client).Ping()
+		return server, err
+	}
+
+	server, err := ping("socks5://tunnel:secret@" + l.Addr().String())
+	defer server.Close()
+	c.Assert(err, IsNil)
+	c.Assert(targets, DeepEquals, []string{strings.TrimPrefix(server.URL, "http://")})
+
+	// Rejected credentials fail the connection.
+	server, err = ping("socks5://tunnel:wrong@" + l.Addr().String())
+	defer server.Close()
+	c.Assert(err, NotNil)
+	c.Assert(targets, HasLen, 1)
+
+	for _, proxyURL := range []string{"http://proxy:3128", "socks5://proxy", "proxy:1080/path"} {
+		_, err = parseSOCKS5Proxy(proxyURL)
+		c.Assert(err, NotNil, Commentf("%s", proxyURL))
+	}
+	u, err := parseSOCKS5Proxy("bastion:1080")
+	c.Assert(err, IsNil)
+	c.Assert(u.String(), Equals, "socks5://bastion:1080")
+}
+
+// Test that the configured User-Agent is sent with every request.
+func (s *TestSuite) TestUserAgent(c *C) {
+	var mutex sync.Mutex
+	var userAgents []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		userAgents = append(userAgents, r.Header.Get("User-Agent"))
+		mutex.Unlock()
+		pingHandler(w, r)
+	}))
+	defer server.Close()
+
+	conf := newTestS3Config(server.URL)
+	conf.AppName = "mc"
+	conf.AppVersion = Version
+	conf.UserAgent = "team-data/1.0"
+	clnt, err := s3New(conf)
+	c.Assert(err, IsNil)
+	_, err = clnt.(*s3Client).Ping()
+	c.Assert(err, IsNil)
+	// Requests of minio-go are covered too, keeping the token of mirror.
+	clnt.AddUserAgent(uaMirrorAppName, Version)
+	_, e := clnt.(*s3Client).api.ListBuckets()
+	c.Assert(e, IsNil)
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	c.Assert(userAgents, DeepEquals, []string{"team-data/1.0", "team-data/1.0 " + uaMirrorAppName + "/" + Version})
+}
@@ -170,6 +170,22 @@ func urlJoinPath(url1, url2 string) string {
 	return joinURLs(u1, u2).String()
 }
 
+// checkTargetPath - verifies that targetPath, built from an object key,
+// is inside the local folder targetURL. Keys with `..` segments would
+// otherwise write outside of the target, e.g. when copying from an
+// untrusted bucket. Object storage targets are not verified.
+func checkTargetPath(targetURL, targetPath string) *probe.Error {
+	target := newClientURL(targetURL)
+	if target.Type != fileSystem {
+		return nil
+	}
+	rel, e := filepath.Rel(filepath.Clean(target.Path), filepath.Clean(newClientURL(targetPath).Path))
+	if e != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return errPathTraversal(targetPath).Trace(targetURL, targetPath)
+	}
+	return nil
+}
+
 // url2Stat returns stat info for URL.
 func url2Stat(urlStr string, isFetchMeta, fileAttr bool, encKeyDB map[string][]prefixSSEPair) (client Client, content *clientContent, err *probe.Error) {
 	client, err = newClient(urlStr)
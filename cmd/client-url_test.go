@@ -51,3 +51,38 @@ func (s *TestSuite) TestURLJoinPath(c *C) {
 	url = urlJoinPath(url1, url2)
 	c.Assert(url, Equals, "http://s3.mycompany.io/dev/mybucket/bin/")
 }
+
+// TestCheckTargetPath - tests that object keys cannot escape local targets.
+func (s *TestSuite) TestCheckTargetPath(c *C) {
+	testCases := []struct {
+		key  string
+		safe bool
+	}{
+		{"photos/2020/a.jpg", true},
+		{"/etc/passwd", true},
+		{"./a/./b", true},
+		{"a/../b", true},
+		{"../../etc/passwd", false},
+		{"a/../../etc/passwd", false},
+		{"..", false},
+		{"../dl-other/x", false},
+	}
+	sourceURL := newClientURL("http://localhost:9000/bucket/")
+	for _, testCase := range testCases {
+		content := &clientContent{URL: *newClientURL("http://localhost:9000/bucket/" + testCase.key)}
+		urls := makeCopyContentTypeC("myminio", *sourceURL, content, "", "/tmp/dl/", nil)
+		c.Assert(urls.Error == nil, Equals, testCase.safe, Commentf("key %s", testCase.key))
+		if !testCase.safe {
+			_, ok := urls.Error.ToGoError().(pathTraversalErr)
+			c.Assert(ok, Equals, true, Commentf("key %s", testCase.key))
+		}
+	}
+
+	// Files named `..` copied into a folder.
+	content := &clientContent{URL: *newClientURL("http://localhost:9000/bucket/a/..")}
+	urls := makeCopyContentTypeB("myminio", content, "", "/tmp/dl", nil)
+	c.Assert(urls.Error, NotNil)
+
+	// Object storage targets are not local paths.
+	c.Assert(checkTargetPath("http://localhost:9000/bucket/", "http://localhost:9000/bucket/../x"), IsNil)
+}
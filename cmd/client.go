@@ -19,11 +19,13 @@ package cmd
 import (
 	"context"
 	"io"
+	"net/url"
 	"os"
 	"time"
 
 	"github.com/minio/mc/pkg/probe"
 	minio "github.com/minio/minio-go/v6"
+	"github.com/minio/minio-go/v6/pkg/credentials"
 	"github.com/minio/minio-go/v6/pkg/encrypt"
 )
 
@@ -57,6 +59,7 @@ type Client interface {
 	GetAccess() (access string, policyJSON string, error *probe.Error)
 	GetAccessRules() (policyRules map[string]string, error *probe.Error)
 	SetAccess(access string, isJSON bool) *probe.Error
+	GetObjectACL() (grants []aclGrant, error *probe.Error)
 
 	// I/O operations
 	Copy(source string, size int64, progress io.Reader, srcSSE, tgtSSE encrypt.ServerSide, metadata map[string]string) *probe.Error
@@ -66,9 +69,14 @@ type Client interface {
 
 	// I/O operations with metadata.
 	Get(sse encrypt.ServerSide) (reader io.ReadCloser, err *probe.Error)
+	GetRange(offset, length int64, sse encrypt.ServerSide) (reader io.ReadCloser, err *probe.Error)
 	Put(ctx context.Context, reader io.Reader, size int64, metadata map[string]string, progress io.Reader, sse encrypt.ServerSide) (n int64, err *probe.Error)
 	// Object Locking related API
 	PutObjectRetention(mode *minio.RetentionMode, retainUntilDate *time.Time) *probe.Error
+	PutObjectLegalHold(status string) *probe.Error
+
+	// Restores a temporary copy of an archived object.
+	RestoreObject(days int, tier string) *probe.Error
 
 	// I/O operations with expiration
 	ShareDownload(expires time.Duration) (string, *probe.Error)
@@ -80,6 +88,10 @@ type Client interface {
 	// Delete operations
 	Remove(isIncomplete, isRemoveBucket bool, contentCh <-chan *clientContent) (errorCh <-chan *probe.Error)
 
+	// Multipart upload operations
+	ListMultipartUploads() (uploads []multipartUpload, err *probe.Error)
+	AbortMultipartUpload(upload multipartUpload) *probe.Error
+
 	// GetURL returns back internal url
 	GetURL() clientURL
 
@@ -96,24 +108,68 @@ type clientContent struct {
 	Metadata          map[string]string
 	UserMetadata      map[string]string
 	ETag              string
+	Owner             string
 	Expires           time.Time
 	EncryptionHeaders map[string]string
 	Retention         bool
 	Err               *probe.Error
 }
 
+// aclGrant - a single grant of an object access control list.
+type aclGrant struct {
+	GranteeID  string
+	GranteeURI string
+	Permission string
+}
+
+// multipartUpload - an in-progress multipart upload.
+type multipartUpload struct {
+	Key       string
+	UploadID  string
+	Initiated time.Time
+}
+
 // Config - see http://docs.amazonwebservices.com/AmazonS3/latest/dev/index.html?RESTAuthentication.html
 type Config struct {
-	AccessKey   string
-	SecretKey   string
-	Signature   string
-	HostURL     string
-	AppName     string
-	AppVersion  string
-	AppComments []string
-	Debug       bool
-	Insecure    bool
-	Lookup      minio.BucketLookupType
+	AccessKey    string
+	SecretKey    string
+	SessionToken string
+	Signature    string
+	HostURL      string
+	AppName      string
+	AppVersion   string
+	AppComments  []string
+	Debug        bool
+	Insecure     bool
+	Lookup       minio.BucketLookupType
+	PartSize     uint64
+	Checksum     checksumAlgorithm
+	IfNotExists  bool
+	Accelerate   bool
+	// Verify the ETags returned by uploads against the uploaded data.
+	Verify bool
+	// Accept the charges of requester pays buckets.
+	RequestPayer bool
+	// Remove objects retained in GOVERNANCE mode.
+	BypassGovernance bool
+	// Dial connections through this SOCKS5 proxy if set.
+	SOCKS5Proxy *url.URL
+	// Replaces the User-Agent of every request if set.
+	UserAgent string
+	// PEM files of the certificate presented for mutual TLS if set.
+	ClientCert string
+	ClientKey  string
+	// Prefixes listed at once by recursive listings, see
+	// listRecursiveParallel. Zero or one lists serially.
+	ListParallel int
+	// Connection pool limits, zero keeps the defaults.
+	MaxIdleConns    int
+	MaxConnsPerHost int
+	// Region signing requests, looked up per bucket if empty.
+	Region string
+	// Credentials signing requests instead of AccessKey, SecretKey and
+	// SessionToken if set, refreshed once expired.
+	Creds *credentials.Credentials
 }
 
 // SelectObjectOpts - opts entered for select API
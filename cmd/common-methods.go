@@ -17,6 +17,7 @@
 package cmd
 
 import (
+	"bytes"
 	"context"
 	"encoding/base64"
 	"errors"
@@ -32,6 +33,7 @@ import (
 	"gopkg.in/h2non/filetype.v1"
 
 	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/hookreader"
 	"github.com/minio/mc/pkg/probe"
 	minio "github.com/minio/minio-go/v6"
 	"github.com/minio/minio-go/v6/pkg/encrypt"
@@ -148,27 +150,37 @@ func getSourceStreamMetadataFromURL(urlStr string, encKeyDB map[string][]prefixS
 		return nil, nil, err.Trace(urlStr)
 	}
 	sseKey := getSSE(urlStr, encKeyDB[alias])
-	return getSourceStream(alias, urlStrFull, true, sseKey)
+	return getSourceStream(context.Background(), alias, urlStrFull, true, sseKey, 0, 0)
 }
 
 // getSourceStreamFromURL gets a reader from URL.
 func getSourceStreamFromURL(urlStr string, encKeyDB map[string][]prefixSSEPair) (reader io.ReadCloser, err *probe.Error) {
+	return getSourceStreamRangeFromURL(urlStr, 0, 0, encKeyDB)
+}
+
+// getSourceStreamRangeFromURL gets a reader of length bytes from offset from URL.
+func getSourceStreamRangeFromURL(urlStr string, offset, length int64, encKeyDB map[string][]prefixSSEPair) (reader io.ReadCloser, err *probe.Error) {
 	alias, urlStrFull, _, err := expandAlias(urlStr)
 	if err != nil {
 		return nil, err.Trace(urlStr)
 	}
 	sse := getSSE(urlStr, encKeyDB[alias])
-	reader, _, err = getSourceStream(alias, urlStrFull, false, sse)
+	reader, _, err = getSourceStream(context.Background(), alias, urlStrFull, false, sse, offset, length)
 	return reader, err
 }
 
-// getSourceStream gets a reader from URL.
-func getSourceStream(alias string, urlStr string, fetchStat bool, sse encrypt.ServerSide) (reader io.ReadCloser, metadata map[string]string, err *probe.Error) {
+// getSourceStream gets a reader from URL, optionally only length bytes from offset.
+func getSourceStream(ctx context.Context, alias string, urlStr string, fetchStat bool, sse encrypt.ServerSide, offset, length int64) (reader io.ReadCloser, metadata map[string]string, err *probe.Error) {
 	sourceClnt, err := newClientFromAlias(alias, urlStr)
 	if err != nil {
 		return nil, nil, err.Trace(alias, urlStr)
 	}
-	reader, err = sourceClnt.Get(sse)
+	// Downloads are aborted once ctx is done, local files need not be.
+	if s3Clnt, ok := sourceClnt.(*s3Client); ok {
+		reader, err = s3Clnt.GetRangeWithContext(ctx, offset, length, sse)
+	} else {
+		reader, err = sourceClnt.GetRange(offset, length, sse)
+	}
 	if err != nil {
 		return nil, nil, err.Trace(alias, urlStr)
 	}
@@ -184,28 +196,25 @@ func getSourceStream(alias string, urlStr string, fetchStat bool, sse encrypt.Se
 				metadata[k] = v
 			}
 		}
-		// If our reader is a seeker try to detect content-type further.
-		if s, ok := reader.(io.ReadSeeker); ok {
+		// minio-go parses Expires out of the metadata of objects.
+		if !st.Expires.IsZero() {
+			metadata["Expires"] = st.Expires.UTC().Format(http.TimeFormat)
+		}
+		// If our reader is a seeker try to detect content-type further,
+		// unless only a slice of the source is read.
+		if s, ok := reader.(io.ReadSeeker); ok && !isByteRange(offset, length) && !globalNoSniff {
 			// All unrecognized files have `application/octet-stream`
 			// So we continue our detection process.
 			if ctype := metadata["Content-Type"]; ctype == "application/octet-stream" {
 				// Read a chunk to decide between utf-8 text and binary
-				var buf [512]byte
+				var buf [sniffLen]byte
 				n, _ := io.ReadFull(reader, buf[:])
 				if n > 0 {
-					kind, e := filetype.Match(buf[:n])
-					if e != nil {
-						return nil, nil, probe.NewError(e)
-					}
 					// rewind to output whole file
 					if _, e := s.Seek(0, io.SeekStart); e != nil {
 						return nil, nil, probe.NewError(e)
 					}
-					ctype = kind.MIME.Value
-					if ctype == "" {
-						ctype = "application/octet-stream"
-					}
-					metadata["Content-Type"] = ctype
+					metadata["Content-Type"] = sniffContentType(buf[:n])
 				}
 			}
 		}
@@ -213,6 +222,36 @@ func getSourceStream(alias string, urlStr string, fetchStat bool, sse encrypt.Se
 	return reader, metadata, nil
 }
 
+// Number of leading bytes the content type is detected from.
+const sniffLen = 512
+
+// sniffContentType - returns the content type of data starting with head,
+// detected by the signature of known formats and falling back to the rules
+// of http.DetectContentType, which tell text apart from binary data.
+func sniffContentType(head []byte) string {
+	if kind, e := filetype.Match(head); e == nil && kind.MIME.Value != "" {
+		return kind.MIME.Value
+	}
+	return http.DetectContentType(head)
+}
+
+// sniffReader - detects the content type of the data of reader, which
+// cannot be rewound, from its first bytes. The returned reader yields
+// the whole data, starting with the buffered bytes.
+func sniffReader(reader io.Reader) (string, io.Reader, *probe.Error) {
+	head := make([]byte, sniffLen)
+	n, e := io.ReadFull(reader, head)
+	if e != nil && e != io.EOF && e != io.ErrUnexpectedEOF {
+		return "", nil, probe.NewError(e)
+	}
+	head = head[:n]
+	ctype := "application/octet-stream"
+	if n > 0 {
+		ctype = sniffContentType(head)
+	}
+	return ctype, io.MultiReader(bytes.NewReader(head), reader), nil
+}
+
 // putTargetRetention sets retention headers if any
 func putTargetRetention(ctx context.Context, alias string, urlStr string, metadata map[string]string) *probe.Error {
 	targetClnt, err := newClientFromAlias(alias, urlStr)
@@ -253,25 +292,96 @@ func putTargetStream(ctx context.Context, alias string, urlStr string, reader io
 	return n, nil
 }
 
-// putTargetStreamWithURL writes to URL from reader. If length=-1, read until EOF.
-func putTargetStreamWithURL(urlStr string, reader io.Reader, size int64, sse encrypt.ServerSide) (int64, *probe.Error) {
+// Suffix of the temporary keys of --atomic uploads, followed by random letters.
+const atomicTempSuffix = ".mc-tmp-"
+
+// putTargetStreamAtomic writes to urlStr from reader such that the target
+// only ever holds the complete object. Objects are uploaded to a temporary
+// key next to the target and copied into place on the server, local files
+// are written to a part file which is renamed. The temporary object or
+// part file is removed on failure.
+func putTargetStreamAtomic(ctx context.Context, alias string, urlStr string, reader io.Reader, size int64, metadata map[string]string, progress io.Reader, sse encrypt.ServerSide) (int64, *probe.Error) {
+	targetClnt, err := newClientFromAlias(alias, urlStr)
+	if err != nil {
+		return 0, err.Trace(alias, urlStr)
+	}
+	if targetClnt.GetURL().Type == fileSystem {
+		n, err := targetClnt.Put(ctx, reader, size, metadata, progress, sse)
+		if err != nil {
+			os.Remove(targetClnt.GetURL().Path + partSuffix)
+			return n, err.Trace(alias, urlStr)
+		}
+		return n, nil
+	}
+
+	tempURL := urlStr + atomicTempSuffix + newRandomID(8)
+	tempClnt, err := newClientFromAlias(alias, tempURL)
+	if err != nil {
+		return 0, err.Trace(alias, tempURL)
+	}
+	n, err := tempClnt.Put(ctx, reader, size, metadata, progress, sse)
+	if err == nil {
+		// Metadata of the temporary object is copied along.
+		err = targetClnt.Copy(tempClnt.GetURL().Path, n, nil, sse, sse, nil)
+	}
+
+	contentCh := make(chan *clientContent, 1)
+	contentCh <- &clientContent{URL: *newClientURL(tempClnt.GetURL().Path)}
+	close(contentCh)
+	for removeErr := range tempClnt.Remove(false, false, contentCh) {
+		if err == nil {
+			err = removeErr.Trace(alias, tempURL)
+		}
+	}
+	if err != nil {
+		return n, err.Trace(alias, urlStr)
+	}
+	return n, nil
+}
+
+// putTargetStreamWithURL writes to URL from reader with metadata, the
+// content type is guessed from URL or else from the first bytes read.
+// If length=-1, read until EOF.
+func putTargetStreamWithURL(urlStr string, reader io.Reader, size int64, metadata map[string]string, sse encrypt.ServerSide) (int64, *probe.Error) {
 	alias, urlStrFull, _, err := expandAlias(urlStr)
 	if err != nil {
 		return 0, err.Trace(alias, urlStr)
 	}
 	contentType := guessURLContentType(urlStr)
-	metadata := map[string]string{
+	userMetaMap := map[string]string{
 		"Content-Type": contentType,
 	}
-	return putTargetStream(context.Background(), alias, urlStrFull, reader, size, metadata, nil, sse)
+	for k, v := range metadata {
+		userMetaMap[k] = v
+	}
+	if userMetaMap["Content-Type"] == "application/octet-stream" && !globalNoSniff {
+		userMetaMap["Content-Type"], reader, err = sniffReader(reader)
+		if err != nil {
+			return 0, err.Trace(alias, urlStr)
+		}
+	}
+	if newClientURL(urlStrFull).Type == objectStorage {
+		if compressed := compressUpload(reader, userMetaMap["Content-Type"], userMetaMap); compressed != nil {
+			defer compressed.Close()
+			reader, size = compressed, -1
+		}
+	}
+	return putTargetStream(context.Background(), alias, urlStrFull, reader, size, userMetaMap, nil, sse)
 }
 
-// copySourceToTargetURL copies to targetURL from source.
+// copySourceToTargetURL copies to targetURL from source. Multipart
+// server-side copies advance the progress part by part, objects small
+// enough to be copied with a single request report no progress until
+// done, a spinner shows they are being copied meanwhile.
 func copySourceToTargetURL(alias string, urlStr string, source string, size int64, progress io.Reader, srcSSE, tgtSSE encrypt.ServerSide, metadata map[string]string) *probe.Error {
 	targetClnt, err := newClientFromAlias(alias, urlStr)
 	if err != nil {
 		return err.Trace(alias, urlStr)
 	}
+	if spinner, ok := progress.(progressSpinner); ok && size <= maxPartSize {
+		stop := spinner.spin()
+		defer stop()
+	}
 	err = targetClnt.Copy(source, size, progress, srcSSE, tgtSSE, metadata)
 	if err != nil {
 		return err.Trace(alias, urlStr)
@@ -310,6 +420,9 @@ func getAllMetadata(sourceAlias, sourceURLStr string, srcSSE encrypt.ServerSide,
 	for k, v := range st.Metadata {
 		metadata[k] = v
 	}
+	if !st.Expires.IsZero() {
+		metadata["Expires"] = st.Expires.UTC().Format(http.TimeFormat)
+	}
 
 	for k, v := range urls.TargetContent.UserMetadata {
 		metadata[k] = v
@@ -336,13 +449,29 @@ func uploadSourceToTargetURL(ctx context.Context, urls URLs, progress io.Reader,
 	var err *probe.Error
 	var metadata = map[string]string{}
 
-	// Optimize for server side copy if the host is same.
-	if sourceAlias == targetAlias {
-		for k, v := range urls.SourceContent.UserMetadata {
-			metadata[k] = v
-		}
-		for k, v := range urls.SourceContent.Metadata {
-			metadata[k] = v
+	// Optimize for server side copy if the host is same, ranges of
+	// the source, uploads computing a checksum, conditional or verified
+	// uploads and compressed transfers are always streamed.
+	if sourceAlias == targetAlias && !isByteRange(urls.sourceOffset, urls.sourceLength) && globalChecksumAlgorithm == checksumNone && !globalIfNotExists && !globalVerify && !globalCompress && !globalDecompress {
+		if urls.replaceMetadata {
+			// The server replaces all metadata of the source.
+			for k, v := range urls.TargetContent.Metadata {
+				metadata[k] = v
+			}
+			for k, v := range urls.TargetContent.UserMetadata {
+				metadata[k] = v
+			}
+			// Copies without metadata keep the metadata of the source.
+			if len(metadata) == 0 {
+				metadata["Content-Type"] = "application/octet-stream"
+			}
+		} else {
+			for k, v := range urls.SourceContent.UserMetadata {
+				metadata[k] = v
+			}
+			for k, v := range urls.SourceContent.Metadata {
+				metadata[k] = v
+			}
 		}
 		// If no metadata populated already by the caller
 		// just do a Stat() to obtain the metadata.
@@ -352,6 +481,13 @@ func uploadSourceToTargetURL(ctx context.Context, urls URLs, progress io.Reader,
 				return urls.WithError(err.Trace(sourceURL.String()))
 			}
 		}
+		// The metadata set for the target, e.g. its storage class, is
+		// added to the metadata of the source.
+		if !urls.replaceMetadata {
+			for k, v := range urls.TargetContent.Metadata {
+				metadata[k] = v
+			}
+		}
 
 		sourcePath := filepath.ToSlash(sourceURL.Path)
 		if urls.SourceContent.Retention {
@@ -371,13 +507,26 @@ func uploadSourceToTargetURL(ctx context.Context, urls URLs, progress io.Reader,
 			err = putTargetRetention(ctx, targetAlias, targetURL.String(), metadata)
 			return urls.WithError(err.Trace(sourceURL.String()))
 		}
+		// Transfers are aborted once idle, reads of remote sources are
+		// watched as well as the progress of the upload.
+		var idle *idleTimeout
+		ctx, idle = newIdleTimeout(ctx, globalIdleTimeout)
+		defer idle.stop()
 		var reader io.ReadCloser
 		// Proceed with regular stream copy.
-		reader, metadata, err = getSourceStream(sourceAlias, sourceURL.String(), true, srcSSE)
+		reader, metadata, err = getSourceStream(ctx, sourceAlias, sourceURL.String(), true, srcSSE, urls.sourceOffset, urls.sourceLength)
 		if err != nil {
-			return urls.WithError(err.Trace(sourceURL.String()))
+			return urls.WithError(idle.errOr(err).Trace(sourceURL.String()))
 		}
 		defer reader.Close()
+		reader = limitBandwidth(reader, urls)
+		if sourceURL.Type == objectStorage {
+			reader = idle.reader(reader)
+		}
+		progress = idle.progress(progress)
+		if urls.replaceMetadata {
+			metadata = make(map[string]string)
+		}
 		// Get metadata from target content as well
 		for k, v := range urls.TargetContent.Metadata {
 			metadata[k] = v
@@ -386,8 +535,37 @@ func uploadSourceToTargetURL(ctx context.Context, urls URLs, progress io.Reader,
 		for k, v := range urls.TargetContent.UserMetadata {
 			metadata[k] = v
 		}
-		_, err = putTargetStream(ctx, targetAlias, targetURL.String(), reader, length, filterMetadata(metadata),
+		// Compressed and decompressed streams are of unknown size, the
+		// bytes of the source are reported to progress instead.
+		var coded io.ReadCloser
+		if targetURL.Type == objectStorage {
+			coded = compressUpload(hookreader.NewHook(reader, progress), metadata["Content-Type"], metadata)
+		} else if sourceURL.Type == objectStorage {
+			coded, err = decompressDownload(hookreader.NewHook(reader, progress), metadata)
+			if err != nil {
+				return urls.WithError(err.Trace(sourceURL.String()))
+			}
+		}
+		if coded != nil {
+			defer coded.Close()
+			reader, length, progress = coded, -1, nil
+		}
+		// Local targets are verified reading back the file written.
+		var verify *verifyReader
+		if globalVerify && targetURL.Type == fileSystem && !isStreamFile(targetURL.Path) {
+			reader, verify = newVerifyReader(reader)
+		}
+		put := putTargetStream
+		if globalAtomic {
+			put = putTargetStreamAtomic
+		}
+		_, err = put(ctx, targetAlias, targetURL.String(), reader, length, filterMetadata(metadata),
 			progress, tgtSSE)
+		if err != nil {
+			err = idle.errOr(err)
+		} else if verify != nil {
+			err = verifyLocalCopy(targetURL.Path, verify.sum())
+		}
 	}
 	if err != nil {
 		return urls.WithError(err.Trace(sourceURL.String()))
@@ -396,6 +574,20 @@ func uploadSourceToTargetURL(ctx context.Context, urls URLs, progress io.Reader,
 	return urls.WithError(nil)
 }
 
+// createEmptyDir - creates the empty directory copied by urls, a local
+// directory or the marker object of isEmptyDirMarker.
+func createEmptyDir(ctx context.Context, urls URLs) URLs {
+	targetURL := urls.TargetContent.URL.String()
+	targetClnt, err := newClientFromAlias(urls.TargetAlias, targetURL)
+	if err != nil {
+		return urls.WithError(err.Trace(targetURL))
+	}
+	if _, err = targetClnt.Put(ctx, bytes.NewReader(nil), 0, map[string]string{}, nil, nil); err != nil {
+		return urls.WithError(err.Trace(targetURL))
+	}
+	return urls.WithError(nil)
+}
+
 // newClientFromAlias gives a new client interface for matching
 // alias entry in the mc config file. If no matching host config entry
 // is found, fs client is returned.
@@ -17,9 +17,23 @@
 package cmd
 
 import (
+	"bytes"
+	"context"
 	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"reflect"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
+
+	"github.com/minio/mc/pkg/probe"
 )
 
 func TestGetDecodedKey(t *testing.T) {
@@ -68,3 +82,251 @@ func TestGetDecodedKey(t *testing.T) {
 		}
 	}
 }
+
+// atomicUploadRecorder records for every request to the server whether
+// the object `final` exists.
+type atomicUploadRecorder struct {
+	*fakeS3Server
+	final    string
+	requests *[]string
+}
+
+func (h atomicUploadRecorder) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	_, finalExists := h.object(h.final)
+	h.mutex.Lock()
+	*h.requests = append(*h.requests, r.Method+" "+strings.TrimPrefix(r.URL.Path, "/")+" "+strconv.FormatBool(finalExists))
+	h.mutex.Unlock()
+	h.fakeS3Server.ServeHTTP(w, r)
+}
+
+// failingReader returns an error instead of EOF.
+type failingReader struct{}
+
+func (failingReader) Read(p []byte) (int, error) {
+	return 0, errors.New("source went away")
+}
+
+// Tests --atomic uploads only create the target once complete.
+func TestPutTargetStreamAtomic(t *testing.T) {
+	var requests []string
+	store := newFakeS3Server(nil)
+	server := httptest.NewServer(atomicUploadRecorder{fakeS3Server: store, final: "bucket/object", requests: &requests})
+	defer server.Close()
+
+	defer withTestConfig(map[string]hostConfigV9{"atomic": testHost(server.URL)})()
+
+	data := "hello world"
+	n, err := putTargetStreamAtomic(context.Background(), "atomic", server.URL+"/bucket/object",
+		strings.NewReader(data), int64(len(data)), map[string]string{"Content-Type": "text/plain"}, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != int64(len(data)) {
+		t.Fatalf("Expected %d bytes, got %d", len(data), n)
+	}
+	if len(store.objects) != 1 || string(store.objects["bucket/object"]) != data {
+		t.Fatalf("Expected only the final object to be left, got %v", store.objects)
+	}
+	// The upload of the temporary object precedes the final object.
+	var sawUpload bool
+	for _, request := range requests {
+		fields := strings.Fields(request)
+		if fields[0] == http.MethodPut && strings.HasPrefix(fields[1], "bucket/object"+atomicTempSuffix) {
+			sawUpload = true
+			if fields[2] != "false" {
+				t.Fatalf("Expected the final object to appear after the upload, got requests %v", requests)
+			}
+		}
+	}
+	if !sawUpload {
+		t.Fatalf("Expected an upload to a temporary object, got requests %v", requests)
+	}
+
+	// Failed local uploads leave neither the file nor its part file.
+	dir, e := ioutil.TempDir("", "mc-atomic-")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "object")
+	reader := io.MultiReader(bytes.NewReader([]byte("hello")), failingReader{})
+	if _, err = putTargetStreamAtomic(context.Background(), "", filename, reader, int64(len(data)), map[string]string{}, nil, nil); err == nil {
+		t.Fatal("Expected a failing source to fail the upload")
+	}
+	for _, name := range []string{filename, filename + partSuffix} {
+		if _, e = os.Stat(name); !os.IsNotExist(e) {
+			t.Fatalf("Expected %s to be removed, got %v", name, e)
+		}
+	}
+}
+
+// Tests the content type of uploads of unknown extension is detected
+// from their first bytes, unless disabled with --no-sniff.
+func TestSniffContentType(t *testing.T) {
+	handler := newFakeS3Server(nil)
+	server := httptest.NewServer(handler)
+	defer server.Close()
+	defer withTestConfig(map[string]hostConfigV9{"myminio": testHost(server.URL)})()
+	defer func(noSniff bool) { globalNoSniff = noSniff }(globalNoSniff)
+
+	png := append([]byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR"), bytes.Repeat([]byte{0}, 1024)...)
+	binary := []byte{0x00, 0x17, 0xfe, 0x42, 0x01, 0x9c, 0x00, 0x03}
+	testCases := []struct {
+		key         string
+		data        []byte
+		noSniff     bool
+		contentType string
+	}{
+		{"image", png, false, "image/png"},
+		{"image-nosniff", png, true, "application/octet-stream"},
+		{"blob", binary, false, "application/octet-stream"},
+		{"notes", []byte("meeting at noon"), false, "text/plain; charset=utf-8"},
+		{"empty", nil, false, "application/octet-stream"},
+		// Known extensions are not sniffed.
+		{"page.html", png, false, "text/html"},
+	}
+	for i, testCase := range testCases {
+		globalNoSniff = testCase.noSniff
+		n, err := putTargetStreamWithURL("myminio/bucket/"+testCase.key, bytes.NewReader(testCase.data), int64(len(testCase.data)), nil, nil)
+		if err != nil {
+			t.Fatalf("Test %d: %s", i+1, err)
+		}
+		if n != int64(len(testCase.data)) {
+			t.Fatalf("Test %d: Expected the %d bytes of the source to be uploaded, got %d", i+1, len(testCase.data), n)
+		}
+		if ctype := handler.headers["bucket/"+testCase.key].Get("Content-Type"); ctype != testCase.contentType {
+			t.Fatalf("Test %d: Expected content type %s, got %s", i+1, testCase.contentType, ctype)
+		}
+	}
+
+	// The sniffed bytes are read again from the returned reader.
+	ctype, reader, err := sniffReader(bytes.NewReader(png))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data, e := ioutil.ReadAll(reader); e != nil || ctype != "image/png" || !bytes.Equal(data, png) {
+		t.Fatalf("Expected the whole image/png source, got %d bytes of %s: %v", len(data), ctype, e)
+	}
+}
+
+// copyProgress counts the bytes reported copied and whether a spinner
+// is shown.
+type copyProgress struct {
+	mutex    sync.Mutex
+	copied   int64
+	spinning bool
+	spins    int
+}
+
+func (p *copyProgress) Read(b []byte) (int, error) {
+	p.mutex.Lock()
+	p.copied += int64(len(b))
+	p.mutex.Unlock()
+	return len(b), nil
+}
+
+func (p *copyProgress) spin() func() {
+	p.mutex.Lock()
+	p.spinning = true
+	p.spins++
+	p.mutex.Unlock()
+	return func() {
+		p.mutex.Lock()
+		p.spinning = false
+		p.mutex.Unlock()
+	}
+}
+
+// serverSideCopyHandler copies the object /bucket/source of the given
+// size to /bucket/target, in parts or at once, recording the progress
+// reported when every copy request arrives.
+type serverSideCopyHandler struct {
+	size     int64
+	progress *copyProgress
+	// Start of the range of every part copied, and the progress
+	// reported when it was requested.
+	starts, copied []int64
+	// Whether the spinner was shown while copying at once.
+	spinning []bool
+}
+
+func (h *serverSideCopyHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	_, location := query["location"]
+	_, uploads := query["uploads"]
+	switch {
+	case location:
+		w.Write([]byte(`<LocationConstraint xmlns="http://s3.amazonaws.com/doc/2006-03-01/"></LocationConstraint>`))
+	case r.Method == http.MethodHead && r.URL.Path == "/bucket/source":
+		w.Header().Set("Content-Length", strconv.FormatInt(h.size, 10))
+		w.Header().Set("ETag", `"f8a1e5f7d2b76e2c3d7b29d4c5a4e0b1"`)
+		w.Header().Set("Last-Modified", "Tue, 21 May 2019 18:24:21 GMT")
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.WriteHeader(http.StatusOK)
+	case r.Method == http.MethodPost && uploads:
+		w.Write([]byte("<InitiateMultipartUploadResult><Bucket>bucket</Bucket><Key>target</Key><UploadId>upload1</UploadId></InitiateMultipartUploadResult>"))
+	case r.Method == http.MethodPut && query.Get("partNumber") != "":
+		var start, end int64
+		fmt.Sscanf(r.Header.Get("X-Amz-Copy-Source-Range"), "bytes=%d-%d", &start, &end)
+		h.progress.mutex.Lock()
+		h.starts, h.copied = append(h.starts, start), append(h.copied, h.progress.copied)
+		h.progress.mutex.Unlock()
+		w.Write([]byte(`<CopyPartResult><LastModified>2019-05-21T18:24:21.097Z</LastModified><ETag>"part` + query.Get("partNumber") + `"</ETag></CopyPartResult>`))
+	case r.Method == http.MethodPost && query.Get("uploadId") == "upload1":
+		w.Write([]byte(`<CompleteMultipartUploadResult><Bucket>bucket</Bucket><Key>target</Key><ETag>"multipart-2"</ETag></CompleteMultipartUploadResult>`))
+	case r.Method == http.MethodPut && r.Header.Get("X-Amz-Copy-Source") != "":
+		h.progress.mutex.Lock()
+		h.spinning = append(h.spinning, h.progress.spinning)
+		h.progress.mutex.Unlock()
+		w.Write([]byte(`<CopyObjectResult><LastModified>2019-05-21T18:24:21.097Z</LastModified><ETag>"f8a1e5f7d2b76e2c3d7b29d4c5a4e0b1"</ETag></CopyObjectResult>`))
+	default:
+		w.WriteHeader(http.StatusNotImplemented)
+	}
+}
+
+func TestServerSideCopyProgress(t *testing.T) {
+	copyObject := func(size int64) (*serverSideCopyHandler, *probe.Error) {
+		handler := &serverSideCopyHandler{size: size, progress: &copyProgress{}}
+		server := httptest.NewServer(handler)
+		defer server.Close()
+		defer withTestConfig(map[string]hostConfigV9{"myminio": testHost(server.URL)})()
+		alias, urlStr, _ := mustExpandAlias("myminio/bucket/target")
+		return handler, copySourceToTargetURL(alias, urlStr, "/bucket/source", size, handler.progress, nil, nil, map[string]string{})
+	}
+
+	// Large objects are copied in parts, the progress advances after every part.
+	size := int64(maxPartSize + 1)
+	handler, err := copyObject(size)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(handler.starts) < 2 {
+		t.Fatalf("Expected a multipart copy, got %d part(s)", len(handler.starts))
+	}
+	for i, start := range handler.starts {
+		if handler.copied[i] != start {
+			t.Fatalf("Part %d: expected %d bytes reported copied, got %d", i+1, start, handler.copied[i])
+		}
+	}
+	if handler.progress.copied != size {
+		t.Fatalf("Expected %d bytes reported copied, got %d", size, handler.progress.copied)
+	}
+	if handler.progress.spins != 0 {
+		t.Fatal("Expected no spinner for a multipart copy")
+	}
+
+	// Smaller objects are copied at once, with a spinner meanwhile.
+	handler, err = copyObject(64 * 1024 * 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(handler.spinning) != 1 || !handler.spinning[0] {
+		t.Fatalf("Expected a spinner while copying at once, got %v", handler.spinning)
+	}
+	if handler.progress.spinning {
+		t.Fatal("Expected the spinner to stop once copied")
+	}
+	if handler.progress.copied != 64*1024*1024 {
+		t.Fatalf("Expected %d bytes reported copied, got %d", 64*1024*1024, handler.progress.copied)
+	}
+}
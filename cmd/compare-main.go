@@ -0,0 +1,249 @@
+/*
+ * MinIO Client (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	json "github.com/minio/mc/pkg/colorjson"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/minio/pkg/console"
+)
+
+// Statuses of a path compared between a local folder and a remote prefix.
+const (
+	compareInSync      = "in-sync"
+	compareLocalNewer  = "local-newer"
+	compareRemoteNewer = "remote-newer"
+	compareSizeDiffers = "size-differs"
+	compareLocalOnly   = "local-only"
+	compareRemoteOnly  = "remote-only"
+)
+
+// Modification times closer than this are considered equal, object
+// storage keeps times with a precision of a second only.
+const compareTimeTolerance = time.Second
+
+var compareCmd = cli.Command{
+	Name:   "compare",
+	Usage:  "compare a local folder with a remote prefix by size and modification time",
+	Action: mainCompare,
+	Before: setGlobalsFromContext,
+	Flags:  globalFlags,
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [FLAGS] LOCAL REMOTE
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+DESCRIPTION:
+  Compare lists every path below LOCAL and REMOTE recursively with its status,
+  telling which side to mirror from. Nothing is modified. Contents are not
+  compared, only sizes and modification times.
+
+STATUS:
+  in-sync      - same size, the remote copy is not older than the local one.
+  local-newer  - the local copy was modified after the remote one.
+  remote-newer - the remote copy was modified after the local one and differs in size.
+  size-differs - both copies were modified at the same time but differ in size.
+  local-only   - the path exists only locally.
+  remote-only  - the path exists only remotely.
+
+EXAMPLES:
+  1. Check whether a local folder or its copy on Amazon S3 cloud storage holds the latest changes.
+     {{.Prompt}} {{.HelpName}} ~/Photos s3/mybucket/Photos
+
+  2. List only the paths which would be uploaded by mirroring a local folder, using jq.
+     {{.Prompt}} {{.HelpName}} --json ~/Photos s3/mybucket/Photos | jq -r 'select(.compare == "local-newer" or .compare == "local-only") | .path'
+`,
+}
+
+// compareSide holds one side of a compared path.
+type compareSide struct {
+	Size int64     `json:"size"`
+	Time time.Time `json:"lastModified"`
+}
+
+// compareMessage container for the status of a compared path, a side
+// is nil if the path is missing there.
+type compareMessage struct {
+	Status  string       `json:"status"`
+	Path    string       `json:"path"`
+	Compare string       `json:"compare"`
+	Local   *compareSide `json:"local,omitempty"`
+	Remote  *compareSide `json:"remote,omitempty"`
+}
+
+// Column widths of the compare table.
+const (
+	compareStatusMaxLen = 12
+	compareSizeMaxLen   = 10
+)
+
+// compareTable - returns the table compare messages are rendered with,
+// coloring the status column with statusTheme and the others with theme.
+func compareTable(statusTheme, theme string) PrettyTable {
+	return newPrettyTable("  ",
+		Field{statusTheme, compareStatusMaxLen},
+		Field{theme, compareSizeMaxLen},
+		Field{theme, compareSizeMaxLen},
+		Field{theme, -1},
+	)
+}
+
+// compareSize - returns the human readable size of one side of a
+// compared path, or a dash if the path is missing on that side.
+func compareSize(side *compareSide) string {
+	if side == nil {
+		return "-"
+	}
+	return humanizeSizeColumn(side.Size)
+}
+
+// String colorized compare message.
+func (c compareMessage) String() string {
+	return compareTable("Compare"+c.Compare, "CompareEntry").buildRow(c.Compare,
+		compareSize(c.Local), compareSize(c.Remote), c.Path)
+}
+
+// JSON jsonified compare message.
+func (c compareMessage) JSON() string {
+	c.Status = "success"
+	compareJSONBytes, e := json.MarshalIndent(c, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(compareJSONBytes)
+}
+
+// compareStatus - returns the status of a path present on both sides.
+// A remote copy of the same size modified after the local one is taken
+// for an upload of the current local copy.
+func compareStatus(local, remote *clientContent) string {
+	switch {
+	case local.Time.Sub(remote.Time) > compareTimeTolerance:
+		return compareLocalNewer
+	case local.Size == remote.Size:
+		return compareInSync
+	case remote.Time.Sub(local.Time) > compareTimeTolerance:
+		return compareRemoteNewer
+	}
+	return compareSizeDiffers
+}
+
+// compareFolders - compares the local folder localURL with the remote
+// prefix remoteURL, sending the status of every path in sorted order.
+func compareFolders(localURL, remoteURL string) (<-chan compareMessage, *probe.Error) {
+	// Both sides are always folders.
+	if separator := string(newClientURL(localURL).Separator); !strings.HasSuffix(localURL, separator) {
+		localURL += separator
+	}
+	if separator := string(newClientURL(remoteURL).Separator); !strings.HasSuffix(remoteURL, separator) {
+		remoteURL += separator
+	}
+	localAlias, localURL, _ := mustExpandAlias(localURL)
+	remoteAlias, remoteURL, _ := mustExpandAlias(remoteURL)
+	localClnt, err := newClientFromAlias(localAlias, localURL)
+	if err != nil {
+		return nil, err.Trace(localURL)
+	}
+	remoteClnt, err := newClientFromAlias(remoteAlias, remoteURL)
+	if err != nil {
+		return nil, err.Trace(remoteURL)
+	}
+
+	compareCh := make(chan compareMessage)
+	go func() {
+		defer close(compareCh)
+		for diffMsg := range objectDifference(localClnt, remoteClnt, localURL, remoteURL, false, true) {
+			if diffMsg.Error != nil {
+				errorIf(diffMsg.Error, "Unable to compare `"+localURL+"` with `"+remoteURL+"`.")
+				continue
+			}
+			local, remote := diffMsg.firstContent, diffMsg.secondContent
+			var msg compareMessage
+			switch diffMsg.Diff {
+			case differInFirst:
+				msg.Path, msg.Compare = strings.TrimPrefix(diffMsg.FirstURL, localURL), compareLocalOnly
+			case differInSecond:
+				msg.Path, msg.Compare = strings.TrimPrefix(diffMsg.SecondURL, remoteURL), compareRemoteOnly
+			default:
+				msg.Path, msg.Compare = strings.TrimPrefix(diffMsg.SecondURL, remoteURL), compareStatus(local, remote)
+			}
+			if local != nil {
+				msg.Local = &compareSide{Size: local.Size, Time: local.Time}
+			}
+			if remote != nil {
+				msg.Remote = &compareSide{Size: remote.Size, Time: remote.Time}
+			}
+			compareCh <- msg
+		}
+	}()
+	return compareCh, nil
+}
+
+// checkCompareSyntax - validates the arguments of compare, both of
+// which must be folders.
+func checkCompareSyntax(ctx *cli.Context, encKeyDB map[string][]prefixSSEPair) {
+	if len(ctx.Args()) != 2 {
+		cli.ShowCommandHelpAndExit(ctx, "compare", 1) // last argument is exit code
+	}
+	for _, arg := range ctx.Args() {
+		if strings.TrimSpace(arg) == "" {
+			fatalIf(errInvalidArgument().Trace(ctx.Args()...), "Unable to validate empty argument.")
+		}
+		_, content, err := url2Stat(arg, false, false, encKeyDB)
+		fatalIf(err.Trace(arg), "Unable to stat `"+arg+"`.")
+		if !content.Type.IsDir() {
+			fatalIf(errInvalidArgument().Trace(arg), "`"+arg+"` is not a folder.")
+		}
+	}
+}
+
+// mainCompare is the entry point for compare command.
+func mainCompare(ctx *cli.Context) error {
+	// Parse encryption keys per command.
+	encKeyDB, err := getEncKeys(ctx)
+	fatalIf(err, "Unable to parse encryption keys.")
+
+	checkCompareSyntax(ctx, encKeyDB)
+
+	console.SetColor("CompareHeader", color.New(color.Bold))
+	console.SetColor("Compare"+compareInSync, color.New(color.FgGreen))
+	console.SetColor("Compare"+compareLocalNewer, color.New(color.FgYellow, color.Bold))
+	console.SetColor("Compare"+compareRemoteNewer, color.New(color.FgMagenta, color.Bold))
+	console.SetColor("Compare"+compareSizeDiffers, color.New(color.FgRed, color.Bold))
+	console.SetColor("Compare"+compareLocalOnly, color.New(color.FgRed))
+	console.SetColor("Compare"+compareRemoteOnly, color.New(color.FgCyan))
+	console.SetColor("CompareEntry", color.New())
+
+	localURL, remoteURL := ctx.Args().Get(0), ctx.Args().Get(1)
+	compareCh, err := compareFolders(localURL, remoteURL)
+	fatalIf(err.Trace(localURL, remoteURL), "Unable to compare `"+localURL+"` with `"+remoteURL+"`.")
+
+	if !globalJSON {
+		console.Println(compareTable("CompareHeader", "CompareHeader").buildRow("STATUS", "LOCAL", "REMOTE", "PATH"))
+	}
+	for msg := range compareCh {
+		printMsg(msg)
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+/*
+ * MinIO Client (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"io/ioutil"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCompareFolders(t *testing.T) {
+	dir, e := ioutil.TempDir("", "mc-compare-")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.RemoveAll(dir)
+	writeFiles(t, dir, map[string]string{"synced.txt": "same", "uploaded.txt": "same", "edited.txt": "edited locally", "stale.txt": "old", "resized.txt": "short", "new.txt": "new"})
+	writeFiles(t, filepath.Join(dir, "sub"), map[string]string{"synced.txt": "same"})
+
+	// Objects of the mock server were all modified at the same time.
+	remoteTime := time.Date(2019, 5, 21, 18, 24, 21, 0, time.UTC)
+	modTimes := map[string]time.Time{
+		"synced.txt":     remoteTime.Add(500 * time.Millisecond),
+		"sub/synced.txt": remoteTime,
+		"uploaded.txt":   remoteTime.Add(-time.Hour),
+		"edited.txt":     remoteTime.Add(time.Hour),
+		"stale.txt":      remoteTime.Add(-time.Hour),
+		"resized.txt":    remoteTime.Add(-500 * time.Millisecond),
+	}
+	for name, modTime := range modTimes {
+		if e = os.Chtimes(filepath.Join(dir, filepath.FromSlash(name)), modTime, modTime); e != nil {
+			t.Fatal(e)
+		}
+	}
+
+	handler := newFakeS3Server(map[string][]byte{
+		"bucket/prefix/synced.txt":     []byte("same"),
+		"bucket/prefix/sub/synced.txt": []byte("same"),
+		"bucket/prefix/uploaded.txt":   []byte("same"),
+		"bucket/prefix/edited.txt":     []byte("edited"),
+		"bucket/prefix/stale.txt":      []byte("newer remotely"),
+		"bucket/prefix/resized.txt":    []byte("longer"),
+		"bucket/prefix/remote.txt":     []byte("remote"),
+	})
+	server := httptest.NewServer(handler)
+	defer server.Close()
+	defer withTestConfig(map[string]hostConfigV9{"myminio": testHost(server.URL)})()
+
+	compareCh, err := compareFolders(dir, "myminio/bucket/prefix")
+	if err != nil {
+		t.Fatal(err)
+	}
+	statuses := make(map[string]string)
+	var paths []string
+	for msg := range compareCh {
+		statuses[filepath.ToSlash(msg.Path)] = msg.Compare
+		paths = append(paths, filepath.ToSlash(msg.Path))
+	}
+	expected := map[string]string{
+		"synced.txt":     compareInSync,
+		"sub/synced.txt": compareInSync,
+		"uploaded.txt":   compareInSync,
+		"edited.txt":     compareLocalNewer,
+		"stale.txt":      compareRemoteNewer,
+		"resized.txt":    compareSizeDiffers,
+		"new.txt":        compareLocalOnly,
+		"remote.txt":     compareRemoteOnly,
+	}
+	if !reflect.DeepEqual(statuses, expected) {
+		t.Fatalf("Expected statuses %v, got %v", expected, statuses)
+	}
+	if len(paths) != len(expected) {
+		t.Fatalf("Expected every path once, got %v", paths)
+	}
+}
@@ -0,0 +1,174 @@
+/*
+ * MinIO Client (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"compress/gzip"
+	"io"
+	"strings"
+
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/probe"
+)
+
+// Flags of cp and pipe compressing uploads, see compressUpload.
+var compressFlags = []cli.Flag{
+	cli.BoolFlag{
+		Name:  "compress",
+		Usage: "gzip objects while uploading them, stored with Content-Encoding: gzip",
+	},
+	cli.StringFlag{
+		Name:  "compress-types",
+		Usage: "comma separated content types compressed by --compress, e.g. \"text/*,application/json\" (default: all but already compressed types)",
+	},
+}
+
+// Flag of cp reversing --compress on downloads, see decompressDownload.
+var decompressFlag = cli.BoolFlag{
+	Name:  "decompress",
+	Usage: "gunzip objects stored with Content-Encoding: gzip while downloading them",
+}
+
+// Content types already compressed, which --compress would only make
+// larger, unless --compress-types lists them.
+var incompressibleTypes = []string{
+	"application/gzip",
+	"application/x-gzip",
+	"application/zip",
+	"application/x-bzip2",
+	"application/x-xz",
+	"application/x-7z-compressed",
+	"application/x-rar-compressed",
+	"application/vnd.rar",
+	"application/zstd",
+	"application/x-zstd",
+	"image/jpeg",
+	"image/png",
+	"image/gif",
+	"image/webp",
+	"video/*",
+	"audio/*",
+	"font/woff",
+	"font/woff2",
+}
+
+// matchContentType - returns if ctype, parameters ignored, is one of
+// types, which may end with `/*` to match a whole family of types.
+func matchContentType(ctype string, types []string) bool {
+	ctype = strings.ToLower(strings.TrimSpace(strings.SplitN(ctype, ";", 2)[0]))
+	for _, t := range types {
+		if strings.HasSuffix(t, "/*") && strings.HasPrefix(ctype, strings.TrimSuffix(t, "*")) {
+			return true
+		}
+		if ctype == t {
+			return true
+		}
+	}
+	return false
+}
+
+// setGlobalCompression validates --compress, --compress-types and
+// --decompress and applies them to all transfers.
+func setGlobalCompression(compress, decompress bool, compressTypes string) {
+	if compress && decompress {
+		fatalIf(errInvalidArgument().Trace(), "`--compress` and `--decompress` cannot be used together.")
+	}
+	if compressTypes != "" && !compress {
+		fatalIf(errInvalidArgument().Trace(compressTypes), "`--compress-types` requires `--compress`.")
+	}
+	globalCompress = compress
+	globalDecompress = decompress
+	globalCompressTypes = nil
+	for _, t := range strings.Split(compressTypes, ",") {
+		if t = strings.ToLower(strings.TrimSpace(t)); t != "" {
+			globalCompressTypes = append(globalCompressTypes, t)
+		}
+	}
+}
+
+// shouldCompress - returns if uploads of ctype are compressed, those of
+// --compress-types if set or else all but the incompressible types.
+func shouldCompress(ctype string) bool {
+	if len(globalCompressTypes) > 0 {
+		return matchContentType(ctype, globalCompressTypes)
+	}
+	return !matchContentType(ctype, incompressibleTypes)
+}
+
+// contentEncoding - returns the Content-Encoding of metadata, whatever
+// the case of its key.
+func contentEncoding(metadata map[string]string) string {
+	for k, v := range metadata {
+		if strings.EqualFold(k, "Content-Encoding") {
+			return strings.TrimSpace(v)
+		}
+	}
+	return ""
+}
+
+// isGzipEncoded - returns if metadata has Content-Encoding: gzip.
+func isGzipEncoded(metadata map[string]string) bool {
+	return strings.EqualFold(contentEncoding(metadata), "gzip")
+}
+
+// gzipReader - returns the gzip compression of reader, compressed as it
+// is read. Closing it stops the compression.
+func gzipReader(reader io.Reader) io.ReadCloser {
+	pr, pw := io.Pipe()
+	go func() {
+		gw := gzip.NewWriter(pw)
+		_, e := io.Copy(gw, reader)
+		if e == nil {
+			e = gw.Close()
+		}
+		pw.CloseWithError(e)
+	}()
+	return pr
+}
+
+// compressUpload - returns the compression of reader if --compress is
+// set and uploads of ctype are compressed, nil otherwise. Content-Encoding:
+// gzip is added to metadata. The compressed size is unknown, which makes
+// every compressed upload a multipart upload. Uploads already encoded are
+// not compressed.
+func compressUpload(reader io.Reader, ctype string, metadata map[string]string) io.ReadCloser {
+	if !globalCompress || contentEncoding(metadata) != "" || !shouldCompress(ctype) {
+		return nil
+	}
+	metadata["Content-Encoding"] = "gzip"
+	return gzipReader(reader)
+}
+
+// decompressDownload - returns the decompression of reader if --decompress
+// is set and metadata has Content-Encoding: gzip, nil otherwise.
+// Content-Encoding is removed from metadata. The decompressed size is
+// unknown.
+func decompressDownload(reader io.Reader, metadata map[string]string) (io.ReadCloser, *probe.Error) {
+	if !globalDecompress || !isGzipEncoded(metadata) {
+		return nil, nil
+	}
+	gr, e := gzip.NewReader(reader)
+	if e != nil {
+		return nil, probe.NewError(e)
+	}
+	for k := range metadata {
+		if strings.EqualFold(k, "Content-Encoding") {
+			delete(metadata, k)
+		}
+	}
+	return gr, nil
+}
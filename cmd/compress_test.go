@@ -0,0 +1,165 @@
+/*
+ * MinIO Client (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io/ioutil"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dustin/go-humanize"
+	"github.com/fatih/color"
+)
+
+func TestShouldCompress(t *testing.T) {
+	defer func(types []string) { globalCompressTypes = types }(globalCompressTypes)
+
+	testCases := []struct {
+		types    []string
+		ctype    string
+		compress bool
+	}{
+		{nil, "text/plain; charset=utf-8", true},
+		{nil, "application/json", true},
+		{nil, "application/gzip", false},
+		{nil, "image/JPEG", false},
+		{nil, "video/mp4", false},
+		{[]string{"text/*"}, "text/csv", true},
+		{[]string{"text/*"}, "application/json", false},
+		// Listed types are compressed even if already compressed.
+		{[]string{"image/png"}, "image/png", true},
+	}
+	for i, testCase := range testCases {
+		globalCompressTypes = testCase.types
+		if compress := shouldCompress(testCase.ctype); compress != testCase.compress {
+			t.Errorf("Test %d: expected %t for %q, got %t", i+1, testCase.compress, testCase.ctype, compress)
+		}
+	}
+}
+
+// Tests that uploads already encoded are not compressed, whatever the
+// case of their Content-Encoding key.
+func TestCompressUploadEncoded(t *testing.T) {
+	defer setGlobalCompression(false, false, "")
+	setGlobalCompression(true, false, "")
+
+	for i, metadata := range []map[string]string{
+		{"Content-Encoding": "br"},
+		{"content-encoding": "gzip"},
+		{"CONTENT-ENCODING": "identity"},
+	} {
+		if reader := compressUpload(strings.NewReader("data"), "text/plain", metadata); reader != nil {
+			reader.Close()
+			t.Fatalf("Test %d: Expected %v not to be compressed", i+1, metadata)
+		}
+		if len(metadata) != 1 {
+			t.Fatalf("Test %d: Expected no Content-Encoding to be added, got %v", i+1, metadata)
+		}
+	}
+	metadata := map[string]string{}
+	reader := compressUpload(strings.NewReader("data"), "text/plain", metadata)
+	if reader == nil {
+		t.Fatal("Expected an upload without Content-Encoding to be compressed")
+	}
+	reader.Close()
+	if metadata["Content-Encoding"] != "gzip" {
+		t.Fatalf("Expected Content-Encoding: gzip, got %v", metadata)
+	}
+}
+
+func TestCopyCompress(t *testing.T) {
+	savedQuiet, savedOutput, savedPartSize := globalQuiet, color.Output, globalPartSize
+	defer func() {
+		globalQuiet, color.Output, globalPartSize = savedQuiet, savedOutput, savedPartSize
+		setGlobalCompression(false, false, "")
+	}()
+	globalQuiet, color.Output = true, ioutil.Discard
+	// Compressed uploads are multipart uploads of unknown size.
+	globalPartSize = 5 * humanize.MiByte
+
+	objects := newFakeS3Server(nil)
+	server := httptest.NewServer(objects)
+	defer server.Close()
+	host := testHost(server.URL)
+	// Signature V2 uploads the data unchunked.
+	host.API = "S3v2"
+	defer withTestConfig(map[string]hostConfigV9{"myminio": host})()
+
+	dir, e := ioutil.TempDir("", "mc-compress-")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.RemoveAll(dir)
+	text := bytes.Repeat([]byte("The quick brown fox jumps over the lazy dog.\n"), 10000)
+	for name, data := range map[string][]byte{"notes.txt": text, "photo.jpg": []byte("not really a photo")} {
+		if e = ioutil.WriteFile(filepath.Join(dir, name), data, 0600); e != nil {
+			t.Fatal(e)
+		}
+	}
+
+	copyObject := func(sourceURL, targetURL string) {
+		for cpURLs := range prepareCopyURLs([]string{sourceURL}, targetURL, false, false, nil, "", "") {
+			if cpURLs.Error != nil {
+				t.Fatal(cpURLs.Error)
+			}
+			if cpURLs = doCopy(context.Background(), cpURLs, nil, nil); cpURLs.Error != nil {
+				t.Fatal(cpURLs.Error)
+			}
+		}
+	}
+
+	setGlobalCompression(true, false, "")
+	copyObject(filepath.Join(dir, "notes.txt"), "myminio/bucket/notes.txt")
+	stored := objects.objects["bucket/notes.txt"]
+	if objects.multiparts != 1 || len(stored) >= len(text) {
+		t.Fatalf("Expected a multipart upload of less than %d bytes, got %d multipart uploads of %d bytes", len(text), objects.multiparts, len(stored))
+	}
+	if encoding := objects.headers["bucket/notes.txt"].Get("Content-Encoding"); encoding != "gzip" {
+		t.Fatalf("Expected Content-Encoding: gzip, got %q", encoding)
+	}
+	gr, e := gzip.NewReader(bytes.NewReader(stored))
+	if e != nil {
+		t.Fatalf("Expected the object to be gzip, got %v", e)
+	}
+	if data, e := ioutil.ReadAll(gr); e != nil || !bytes.Equal(data, text) {
+		t.Fatalf("Expected the object to decompress to the original %d bytes, got %d bytes, %v", len(text), len(data), e)
+	}
+
+	// Already compressed types are uploaded as they are.
+	copyObject(filepath.Join(dir, "photo.jpg"), "myminio/bucket/photo.jpg")
+	if string(objects.objects["bucket/photo.jpg"]) != "not really a photo" || objects.headers["bucket/photo.jpg"].Get("Content-Encoding") != "" {
+		t.Fatalf("Expected the photo to be uploaded uncompressed, got %q with headers %v", objects.objects["bucket/photo.jpg"], objects.headers["bucket/photo.jpg"])
+	}
+
+	// Downloads keep the encoding of objects without --decompress.
+	setGlobalCompression(false, false, "")
+	copyObject("myminio/bucket/notes.txt", filepath.Join(dir, "notes.txt.gz"))
+	if data, e := ioutil.ReadFile(filepath.Join(dir, "notes.txt.gz")); e != nil || !bytes.Equal(data, stored) {
+		t.Fatalf("Expected the gzip object to be downloaded as it is stored, got %d bytes, %v", len(data), e)
+	}
+	setGlobalCompression(false, true, "")
+	copyObject("myminio/bucket/notes.txt", filepath.Join(dir, "notes-copy.txt"))
+	if data, e := ioutil.ReadFile(filepath.Join(dir, "notes-copy.txt")); e != nil || !bytes.Equal(data, text) {
+		t.Fatalf("Expected the object to be downloaded decompressed, got %d bytes, %v", len(data), e)
+	}
+}
@@ -0,0 +1,129 @@
+/*
+ * MinIO Client (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package cmd
+
+import (
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	json "github.com/minio/mc/pkg/colorjson"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/minio/pkg/console"
+)
+
+var configBackendsCmd = cli.Command{
+	Name:            "backends",
+	Usage:           "list the storage backends and the URL schemes they serve",
+	Action:          mainConfigBackends,
+	Before:          setGlobalsFromContext,
+	Flags:           globalFlags,
+	HideHelpCommand: true,
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}}
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+EXAMPLES:
+  1. List the backends supported by this build of mc.
+     {{.Prompt}} {{.HelpName}}
+
+  2. List the backends in JSON format.
+     {{.Prompt}} {{.HelpName}} --json
+`,
+}
+
+// clientBackend - a backend of the Client interface, chosen by
+// newClientFromAlias from the URL of a path.
+type clientBackend struct {
+	Backend     string
+	Schemes     []string
+	APIs        []string
+	Description string
+}
+
+// clientBackends - the backends compiled in mc. Paths whose alias is
+// found in the config are served by s3, every other path by fs.
+var clientBackends = []clientBackend{
+	{
+		Backend:     "s3",
+		Schemes:     []string{"http", "https"},
+		APIs:        validAPIs,
+		Description: "Amazon S3 compatible object storage of the aliases in the config",
+	},
+	{
+		Backend:     "fs",
+		Description: "local files and folders, any path which is not an alias",
+	},
+}
+
+// backendMessage container for the backends of "mc config backends".
+type backendMessage struct {
+	Status      string   `json:"status"`
+	Backend     string   `json:"backend"`
+	Schemes     []string `json:"schemes"`
+	APIs        []string `json:"apis,omitempty"`
+	Description string   `json:"description"`
+}
+
+// String colorized backend message.
+func (b backendMessage) String() string {
+	schemes := "local paths"
+	if len(b.Schemes) > 0 {
+		schemes = strings.Join(b.Schemes, ", ")
+	}
+	msg := console.Colorize("Backend", b.Backend) + "  " + console.Colorize("Schemes", schemes)
+	if len(b.APIs) > 0 {
+		msg += "  " + console.Colorize("APIs", "API "+strings.Join(b.APIs, ", "))
+	}
+	return msg + "  " + b.Description
+}
+
+// JSON jsonified backend message.
+func (b backendMessage) JSON() string {
+	b.Status = "success"
+	if b.Schemes == nil {
+		b.Schemes = []string{}
+	}
+	backendJSONBytes, e := json.MarshalIndent(b, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(backendJSONBytes)
+}
+
+// mainConfigBackends is the handle for "mc config backends" command.
+func mainConfigBackends(ctx *cli.Context) error {
+	if len(ctx.Args()) != 0 {
+		cli.ShowCommandHelpAndExit(ctx, "backends", 1) // last argument is exit code
+	}
+	console.SetColor("Backend", color.New(color.FgCyan, color.Bold))
+	console.SetColor("Schemes", color.New(color.FgYellow))
+	console.SetColor("APIs", color.New(color.FgGreen))
+
+	for _, backend := range clientBackends {
+		printMsg(backendMessage{
+			Backend:     backend.Backend,
+			Schemes:     backend.Schemes,
+			APIs:        backend.APIs,
+			Description: backend.Description,
+		})
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+/*
+ * MinIO Client (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+)
+
+func TestConfigBackends(t *testing.T) {
+	savedJSON, savedOutput := globalJSON, color.Output
+	defer func() {
+		globalJSON, color.Output = savedJSON, savedOutput
+	}()
+	globalJSON = true
+	var buf bytes.Buffer
+	color.Output = &buf
+
+	if e := mainConfigBackends(cli.NewContext(nil, flag.NewFlagSet(configBackendsCmd.Name, flag.ContinueOnError), nil)); e != nil {
+		t.Fatal(e)
+	}
+	backends := make(map[string]backendMessage)
+	decoder := json.NewDecoder(&buf)
+	for {
+		var msg backendMessage
+		if e := decoder.Decode(&msg); e == io.EOF {
+			break
+		} else if e != nil {
+			t.Fatal(e)
+		}
+		backends[msg.Backend] = msg
+	}
+	if len(backends) != len(clientBackends) {
+		t.Fatalf("Expected %d backends, got %v", len(clientBackends), backends)
+	}
+	if s3 := backends["s3"]; !reflect.DeepEqual(s3.Schemes, []string{"http", "https"}) || !reflect.DeepEqual(s3.APIs, validAPIs) {
+		t.Fatalf("Expected the s3 backend to serve http and https with APIs %v, got %+v", validAPIs, s3)
+	}
+	if fs, ok := backends["fs"]; !ok || len(fs.Schemes) != 0 {
+		t.Fatalf("Expected the fs backend of local paths, got %+v", fs)
+	}
+}
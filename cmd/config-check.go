@@ -0,0 +1,331 @@
+/*
+ * MinIO Client (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/minio/pkg/console"
+)
+
+var configCheckCmd = cli.Command{
+	Name:            "check",
+	Usage:           "report problems of the configuration file without modifying it",
+	Action:          mainConfigCheck,
+	Before:          setGlobalsFromContext,
+	Flags:           globalFlags,
+	HideHelpCommand: true,
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}}
+
+  Problems marked as repairable are fixed by "mc config repair".
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+EXAMPLES:
+  1. Check a configuration file edited by hand.
+     {{.Prompt}} {{.HelpName}}
+
+  2. Check the configuration file of another folder.
+     {{.Prompt}} {{.HelpName}} --config-dir /opt/mc
+`,
+}
+
+// configIssue is a problem found in the config file.
+type configIssue struct {
+	// Line of the problem, zero if unknown.
+	Line int `json:"line,omitempty"`
+	// Path of the field, as in `hosts.play.url`.
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+	// Fixed by `mc config repair`.
+	Repairable bool `json:"repairable"`
+}
+
+func (i configIssue) String() string {
+	var msg string
+	if i.Line > 0 {
+		msg = fmt.Sprintf("line %d: ", i.Line)
+	}
+	if i.Field != "" {
+		msg += i.Field + ": "
+	}
+	msg += i.Message
+	if i.Repairable {
+		msg += " (repairable)"
+	}
+	return msg
+}
+
+// configCheckMessage container for the problems of a config file.
+type configCheckMessage struct {
+	Status string        `json:"status"`
+	Path   string        `json:"path"`
+	Issues []configIssue `json:"issues"`
+}
+
+func (c configCheckMessage) String() string {
+	if len(c.Issues) == 0 {
+		return console.Colorize("ConfigCheck", "No problems found in `"+c.Path+"`.")
+	}
+	msg := console.Colorize("ConfigCheck", fmt.Sprintf("Found %d problem(s) in `%s`:", len(c.Issues), c.Path))
+	for _, issue := range c.Issues {
+		msg += "\n  " + console.Colorize("ConfigIssue", issue.String())
+	}
+	return msg
+}
+
+func (c configCheckMessage) JSON() string {
+	c.Status = "success"
+	if len(c.Issues) != 0 {
+		c.Status = "error"
+	}
+	jsonMessageBytes, e := json.MarshalIndent(c, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(jsonMessageBytes)
+}
+
+// lineOf - returns the line of offset in data, counting from 1.
+func lineOf(data []byte, offset int) int {
+	return bytes.Count(data[:offset], []byte("\n")) + 1
+}
+
+// keyOffsets - returns the offsets of every `"key":` in data after from.
+func keyOffsets(data []byte, from int, key string) (offsets []int) {
+	keyRegex := regexp.MustCompile(regexp.QuoteMeta(fmt.Sprintf("%q", key)) + `\s*:`)
+	for _, match := range keyRegex.FindAllIndex(data[from:], -1) {
+		offsets = append(offsets, from+match[0])
+	}
+	return offsets
+}
+
+// hostAliases - returns the aliases of the hosts object in the order
+// they are written, aliases written more than once included each time.
+func hostAliases(hosts json.RawMessage) ([]string, error) {
+	dec := json.NewDecoder(bytes.NewReader(hosts))
+	if t, e := dec.Token(); e != nil || t != json.Delim('{') {
+		return nil, fmt.Errorf("expected an object of hosts")
+	}
+	var aliases []string
+	for dec.More() {
+		t, e := dec.Token()
+		if e != nil {
+			return nil, e
+		}
+		aliases = append(aliases, t.(string))
+		var host json.RawMessage
+		if e = dec.Decode(&host); e != nil {
+			return nil, e
+		}
+	}
+	return aliases, nil
+}
+
+// normalizeHostURL - returns the host URL as written by `mc config host
+// add`, without spaces, trailing separator nor upper case scheme and host.
+func normalizeHostURL(hostURL string) string {
+	hostURL = trimTrailingSeparator(strings.TrimSpace(hostURL))
+	if i := strings.Index(hostURL, "://"); i >= 0 {
+		rest := hostURL[i+len("://"):]
+		host := rest
+		if j := strings.Index(rest, "/"); j >= 0 {
+			host = rest[:j]
+		}
+		hostURL = strings.ToLower(hostURL[:i+len("://")+len(host)]) + rest[len(host):]
+	}
+	return hostURL
+}
+
+// checkConfigData - returns the problems of the config file data, sorted
+// by line. The config is returned if data could be parsed.
+func checkConfigData(data []byte) ([]configIssue, *configV9) {
+	var issues []configIssue
+	// Errors of the JSON syntax and types are reported where they stop
+	// the parsing of the config, nothing more can be told past them.
+	cfg := new(configV9)
+	if e := json.Unmarshal(data, cfg); e != nil {
+		issue := configIssue{Message: e.Error()}
+		switch e := e.(type) {
+		case *json.SyntaxError:
+			issue.Line = lineOf(data, int(e.Offset))
+		case *json.UnmarshalTypeError:
+			issue.Line = lineOf(data, int(e.Offset))
+		}
+		return []configIssue{issue}, nil
+	}
+	var fields map[string]json.RawMessage
+	if e := json.Unmarshal(data, &fields); e != nil {
+		// The config is an object, as parsed above.
+		return []configIssue{{Message: e.Error()}}, nil
+	}
+	line := func(offsets []int) int {
+		if len(offsets) == 0 {
+			return 0
+		}
+		return lineOf(data, offsets[0])
+	}
+
+	switch {
+	case cfg.Version == "":
+		issues = append(issues, configIssue{Field: "version", Message: "missing version, `" + globalMCConfigVersion + "` is expected", Repairable: true})
+	case cfg.Version != globalMCConfigVersion:
+		issues = append(issues, configIssue{
+			Line:    line(keyOffsets(data, 0, "version")),
+			Field:   "version",
+			Message: fmt.Sprintf("version `%s` does not match the version `%s` of this mc", cfg.Version, globalMCConfigVersion),
+		})
+	}
+	if _, ok := fields["hosts"]; !ok || cfg.Hosts == nil {
+		issues = append(issues, configIssue{Field: "hosts", Message: "missing hosts", Repairable: true})
+		return issues, cfg
+	}
+
+	aliases, e := hostAliases(fields["hosts"])
+	if e != nil {
+		issues = append(issues, configIssue{Line: line(keyOffsets(data, 0, "hosts")), Field: "hosts", Message: e.Error()})
+		return issues, cfg
+	}
+	// Aliases are searched after the key of the hosts, for their line.
+	hostsOffset := 0
+	if offsets := keyOffsets(data, 0, "hosts"); len(offsets) > 0 {
+		hostsOffset = offsets[0]
+	}
+	count := make(map[string]int)
+	for _, alias := range aliases {
+		count[alias]++
+	}
+	seen := make(map[string]bool)
+	for _, alias := range aliases {
+		if seen[alias] {
+			continue
+		}
+		seen[alias] = true
+		field := "hosts." + alias
+		offsets := keyOffsets(data, hostsOffset, alias)
+		if len(offsets) != count[alias] {
+			// Written with escapes, or a field of another host.
+			offsets = nil
+		}
+		aliasOffset, aliasLine := hostsOffset, 0
+		if len(offsets) > 0 {
+			aliasOffset = offsets[len(offsets)-1]
+			aliasLine = lineOf(data, aliasOffset)
+		}
+		// The last definition of an alias is the one used by mc.
+		for i := 1; i < count[alias]; i++ {
+			issue := configIssue{Field: field, Message: "duplicate alias, only its last definition is used", Repairable: true}
+			if offsets != nil {
+				issue.Line = lineOf(data, offsets[i-1])
+			}
+			issues = append(issues, issue)
+		}
+		fieldLine := func(key string) int {
+			return line(keyOffsets(data, aliasOffset, key))
+		}
+
+		host := cfg.Hosts[alias]
+		if !isValidAlias(alias) && !isHostURLKey(alias, host) {
+			issues = append(issues, configIssue{Line: aliasLine, Field: field, Message: "invalid alias, aliases start with a letter followed by letters, digits, `-` or `_`"})
+		}
+		switch normalized := normalizeHostURL(host.URL); {
+		case host.URL == "":
+			issues = append(issues, configIssue{Line: aliasLine, Field: field + ".url", Message: "missing URL"})
+		case envRefRegex.MatchString(host.URL):
+			// Checked once expanded, variables which are not set may be
+			// set where the alias is used.
+			if hostURL, ok := expandedHostURL(host); ok && !isValidHostURLPattern(normalizeHostURL(hostURL)) {
+				issues = append(issues, configIssue{Line: fieldLine("url"), Field: field + ".url", Message: "invalid URL `" + hostURL + "`, expanded from `" + host.URL + "`"})
+			}
+		case !isValidHostURLPattern(normalized):
+			issues = append(issues, configIssue{Line: fieldLine("url"), Field: field + ".url", Message: "invalid URL `" + host.URL + "`"})
+		case normalized != host.URL:
+			issues = append(issues, configIssue{Line: fieldLine("url"), Field: field + ".url", Message: "URL `" + host.URL + "` is not normalized, `" + normalized + "` is expected", Repairable: true})
+		}
+		if host.AccessKey == "" && host.SecretKey != "" {
+			issues = append(issues, configIssue{Line: fieldLine("secretKey"), Field: field + ".accessKey", Message: "missing access key for the secret key"})
+		}
+		if host.AccessKey != "" && host.SecretKey == "" {
+			issues = append(issues, configIssue{Line: fieldLine("accessKey"), Field: field + ".secretKey", Message: "missing secret key for the access key"})
+		}
+		if !isValidAPI(host.API) {
+			issues = append(issues, configIssue{Line: fieldLine("api"), Field: field + ".api", Message: "invalid API `" + host.API + "`, S3v4 or S3v2 is expected"})
+		}
+		if host.Lookup != "" && !isValidLookup(host.Lookup) {
+			issues = append(issues, configIssue{Line: fieldLine("lookup"), Field: field + ".lookup", Message: "invalid lookup `" + host.Lookup + "`, dns, path or auto is expected"})
+		}
+		if host.PartSize != "" {
+			if _, err := parsePartSize(host.PartSize); err != nil {
+				issues = append(issues, configIssue{Line: fieldLine("partSize"), Field: field + ".partSize", Message: "invalid part size `" + host.PartSize + "`"})
+			}
+		}
+		if (host.ClientCert == "") != (host.ClientKey == "") {
+			issues = append(issues, configIssue{Line: aliasLine, Field: field, Message: "a client certificate requires both clientCert and clientKey"})
+		}
+	}
+	if _, ok := cfg.Hosts[cfg.Current]; cfg.Current != "" && !ok {
+		issues = append(issues, configIssue{
+			Line:       line(keyOffsets(data, 0, "current")),
+			Field:      "current",
+			Message:    "current alias `" + cfg.Current + "` is not a host",
+			Repairable: true,
+		})
+	}
+	sort.SliceStable(issues, func(i, j int) bool { return issues[i].Line < issues[j].Line })
+	return issues, cfg
+}
+
+// readConfigFile - reads the config file, unparsed for its problems to
+// be reported.
+func readConfigFile() ([]byte, *probe.Error) {
+	data, e := ioutil.ReadFile(mustGetMcConfigPath())
+	if e != nil {
+		return nil, probe.NewError(e).Trace(mustGetMcConfigPath())
+	}
+	return data, nil
+}
+
+func mainConfigCheck(ctx *cli.Context) error {
+	if len(ctx.Args()) != 0 {
+		cli.ShowCommandHelpAndExit(ctx, "check", 1) // last argument is exit code
+	}
+	console.SetColor("ConfigCheck", color.New(color.FgGreen, color.Bold))
+	console.SetColor("ConfigIssue", color.New(color.FgYellow))
+
+	data, err := readConfigFile()
+	fatalIf(err, "Unable to read config.")
+
+	issues, _ := checkConfigData(data)
+	printMsg(configCheckMessage{Path: mustGetMcConfigPath(), Issues: issues})
+	if len(issues) != 0 {
+		return exitStatus(globalErrorExitStatus)
+	}
+	return nil
+}
@@ -0,0 +1,203 @@
+/*
+ * MinIO Client (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"flag"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/probe"
+)
+
+func TestCheckConfigData(t *testing.T) {
+	os.Unsetenv("MC_TEST_UNSET_URL")
+	testCases := []struct {
+		config string
+		// Line, field and whether repairable of each issue.
+		issues []configIssue
+	}{
+		{`{
+	"version": "9",
+	"hosts": {
+		"play": {
+			"url": "https://play.min.io",
+			"accessKey": "Q3AM3UQ867SPQQA43P2F",
+			"secretKey": "zuf+tfteSlswRu7BJ86wekitnifILbZam1KYY3TG",
+			"api": "S3v4",
+			"lookup": "auto"
+		},
+		"anonymous": {"url": "http://localhost:9000", "accessKey": "", "secretKey": "", "api": "s3v2", "lookup": "path"}
+	}
+}`, nil},
+		// Syntax errors.
+		{`{
+	"version": "9",
+	"hosts": {
+		"play": {"url": "https://play.min.io",}
+	}
+}`, []configIssue{{Line: 4}}},
+		{`{"version": "9", "hosts": {`, []configIssue{{Line: 1}}},
+		// Fields of the wrong type.
+		{`{
+	"version": "9",
+	"hosts": {
+		"play": {"url": "https://play.min.io", "api": "S3v4", "anonymous": "yes"}
+	}
+}`, []configIssue{{Line: 4}}},
+		// Missing version and duplicate aliases.
+		{`{
+	"hosts": {
+		"play": {"url": "https://play.min.io", "api": "S3v4"},
+		"local": {"url": "http://localhost:9000", "api": "S3v4"},
+		"play": {"url": "https://play.min.io", "api": "S3v2"}
+	}
+}`, []configIssue{{Field: "version", Repairable: true}, {Line: 3, Field: "hosts.play", Repairable: true}}},
+		{`{"version": "8", "hosts": {}}`, []configIssue{{Line: 1, Field: "version"}}},
+		{`{"version": "9"}`, []configIssue{{Field: "hosts", Repairable: true}}},
+		// Current alias which is not a host.
+		{`{"version": "9", "hosts": {}, "current": "gone"}`, []configIssue{{Line: 1, Field: "current", Repairable: true}}},
+		// URLs which are normalized and URLs which are invalid.
+		{`{
+	"version": "9",
+	"hosts": {
+		"upper": {"url": "HTTPS://Play.MIN.io/", "api": "S3v4"},
+		"spaces": {"url": " http://localhost:9000 ", "api": "S3v4"},
+		"noscheme": {"url": "localhost:9000", "api": "S3v4"},
+		"nourl": {"api": "S3v4"}
+	}
+}`, []configIssue{
+			{Line: 4, Field: "hosts.upper.url", Repairable: true},
+			{Line: 5, Field: "hosts.spaces.url", Repairable: true},
+			{Line: 6, Field: "hosts.noscheme.url"},
+			{Line: 7, Field: "hosts.nourl.url"},
+		}},
+		// URLs of environment variables are checked once expanded.
+		{`{
+	"version": "9",
+	"hosts": {
+		"fromenv": {"url": "${MC_TEST_UNSET_URL}", "api": "S3v4"},
+		"default": {"url": "${MC_TEST_UNSET_URL:-https://play.min.io}", "api": "S3v4"},
+		"invalid": {"url": "${MC_TEST_UNSET_URL:-play.min.io}", "api": "S3v4"}
+	}
+}`, []configIssue{{Line: 6, Field: "hosts.invalid.url"}}},
+		// Credentials and other fields of hosts.
+		{`{
+	"version": "9",
+	"hosts": {
+		"nosecret": {
+			"url": "http://localhost:9000",
+			"accessKey": "minio",
+			"api": "S3v4"
+		},
+		"noaccess": {"url": "http://localhost:9000", "secretKey": "minio123", "api": "S3v4"},
+		"badapi": {"url": "http://localhost:9000", "api": "S3v9", "lookup": "host", "partSize": "tiny"},
+		"1alias": {"url": "http://localhost:9000", "api": "S3v4"}
+	}
+}`, []configIssue{
+			{Line: 6, Field: "hosts.nosecret.secretKey"},
+			{Line: 9, Field: "hosts.noaccess.accessKey"},
+			{Line: 10, Field: "hosts.badapi.api"},
+			{Line: 10, Field: "hosts.badapi.lookup"},
+			{Line: 10, Field: "hosts.badapi.partSize"},
+			{Line: 11, Field: "hosts.1alias"},
+		}},
+	}
+	for i, testCase := range testCases {
+		issues, cfg := checkConfigData([]byte(testCase.config))
+		var got []configIssue
+		for _, issue := range issues {
+			if issue.Message == "" {
+				t.Errorf("Test %d: expected a message for %v", i+1, issue)
+			}
+			got = append(got, configIssue{Line: issue.Line, Field: issue.Field, Repairable: issue.Repairable})
+		}
+		if !reflect.DeepEqual(got, testCase.issues) {
+			t.Errorf("Test %d: expected issues %v, got %v", i+1, testCase.issues, issues)
+		}
+		if cfg == nil && (len(issues) != 1 || issues[0].Repairable) {
+			t.Errorf("Test %d: expected a config to be returned with %v", i+1, issues)
+		}
+	}
+}
+
+func TestConfigRepair(t *testing.T) {
+	dir, e := ioutil.TempDir("", "mc-config-")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.RemoveAll(dir)
+	defer setMcConfigDir(mcCustomConfigDir)
+	setMcConfigDir(dir)
+	defer func(load func() (*configV9, *probe.Error)) { loadMcConfig = load }(loadMcConfig)
+	defer func() { cacheCfgV9 = nil }()
+
+	broken := `{
+	"hosts": {
+		"play": {"url": "https://play.min.io", "accessKey": "old", "secretKey": "oldsecret", "api": "S3v4", "lookup": "auto"},
+		"local": {"url": "HTTP://LOCALHOST:9000/", "accessKey": "minio", "api": "S3v4", "lookup": "auto"},
+		"play": {"url": "https://play.min.io", "accessKey": "new", "secretKey": "newsecret", "api": "S3v4", "lookup": "auto"}
+	}
+}`
+	if e = ioutil.WriteFile(filepath.Join(dir, globalMCConfigFile), []byte(broken), 0600); e != nil {
+		t.Fatal(e)
+	}
+	data, err := readConfigFile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	issues, _ := checkConfigData(data)
+	if len(issues) != 4 {
+		t.Fatalf("Expected 4 issues, got %v", issues)
+	}
+	defer func(output io.Writer) { color.Output = output }(color.Output)
+	color.Output = ioutil.Discard
+	// The missing secret key cannot be repaired.
+	if e = mainConfigRepair(cli.NewContext(nil, flag.NewFlagSet("repair", flag.ContinueOnError), nil)); e == nil {
+		t.Fatal("Expected the problem left to fail the repair")
+	}
+	if backup, e := ioutil.ReadFile(filepath.Join(dir, globalMCConfigFile+".bak")); e != nil || string(backup) != broken {
+		t.Fatalf("Expected the original config to be kept, got %q, %v", backup, e)
+	}
+
+	if data, err = readConfigFile(); err != nil {
+		t.Fatal(err)
+	}
+	issues, _ = checkConfigData(data)
+	expected := []configIssue{{Line: 6, Field: "hosts.local.secretKey", Message: "missing secret key for the access key"}}
+	if !reflect.DeepEqual(issues, expected) {
+		t.Fatalf("Expected %v after the repair, got %v", expected, issues)
+	}
+	// The repaired config is loaded by mc.
+	cacheCfgV9 = nil
+	repaired, err := loadConfigV9()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if repaired.Version != globalMCConfigVersion || len(repaired.Hosts) != 2 {
+		t.Fatalf("Expected version %s with 2 hosts, got %v", globalMCConfigVersion, repaired)
+	}
+	if repaired.Hosts["play"].AccessKey != "new" || repaired.Hosts["local"].URL != "http://localhost:9000" {
+		t.Fatalf("Expected the last play alias and a normalized URL, got %v", repaired.Hosts)
+	}
+}
@@ -0,0 +1,169 @@
+/*
+ * MinIO Client (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/minio/minio-go/v6/pkg/credentials"
+)
+
+// credentialProvider - a source of the credentials of hosts.
+type credentialProvider interface {
+	// retrieve returns the credentials of the host of alias, ok is
+	// false if the source has none for it.
+	retrieve(alias string, hostCfg hostConfigV9) (creds credentials.Value, ok bool)
+}
+
+// credentialRefresher - a credentialProvider of credentials which expire,
+// clients sign with credentials retrieved again once expired.
+type credentialRefresher interface {
+	credentials() *credentials.Credentials
+}
+
+// credentialProviderFunc - a function usable as a credentialProvider.
+type credentialProviderFunc func(alias string, hostCfg hostConfigV9) (credentials.Value, bool)
+
+func (f credentialProviderFunc) retrieve(alias string, hostCfg hostConfigV9) (credentials.Value, bool) {
+	return f(alias, hostCfg)
+}
+
+// credentialChain - sources of the credentials of the hosts of the
+// config file, in order. The credentials of the first source which has
+// some for a host are used:
+//
+//  1. --access and --secret, for the alias of the command they are
+//     given with, see credentialsAlias
+//  2. AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and AWS_SESSION_TOKEN
+//  3. the profile $AWS_PROFILE, or default, of the AWS shared
+//     credentials file $AWS_SHARED_CREDENTIALS_FILE or ~/.aws/credentials
+//  4. the keys of the host in the config file
+//  5. the IAM role of the EC2 instance or ECS task, from the metadata
+//     service unless AWS_EC2_METADATA_DISABLED is true
+//
+// The metadata service comes last as it only fails after a timeout off
+// AWS. AWS sources only apply to AWS hosts, their credentials are never
+// sent to other hosts, and are refreshed by their source once expired.
+var credentialChain = []credentialProvider{
+	credentialProviderFunc(flagCredentials),
+	&awsCredentials{provider: &credentials.EnvAWS{}},
+	&awsCredentials{provider: &credentials.FileAWSCredentials{}},
+	credentialProviderFunc(configCredentials),
+	&awsCredentials{provider: &credentials.IAM{Client: &http.Client{Timeout: time.Second}}, isMetadata: true},
+}
+
+// credentialsAlias - returns the alias --access and --secret are given
+// for: the first host of the config named by an argument of the command.
+func credentialsAlias() string {
+	mcCfg, err := loadMcConfig()
+	if err != nil {
+		return ""
+	}
+	for _, arg := range globalCommandArgs {
+		if strings.HasPrefix(arg, s3SchemePrefix) {
+			return s3SchemePrefix
+		}
+		if strings.HasPrefix(arg, "/") && mcCfg.Current != "" {
+			return mcCfg.Current
+		}
+		if alias, _ := url2Alias(arg); alias != "" {
+			if _, ok := mcCfg.Hosts[alias]; ok {
+				return alias
+			}
+		}
+	}
+	return ""
+}
+
+// flagCredentials - returns the keys given with --access and --secret
+// if given for alias.
+func flagCredentials(alias string, hostCfg hostConfigV9) (credentials.Value, bool) {
+	if globalAccessKey == "" || globalSecretKey == "" || alias != credentialsAlias() {
+		return credentials.Value{}, false
+	}
+	return credentials.Value{AccessKeyID: globalAccessKey, SecretAccessKey: globalSecretKey}, true
+}
+
+// configCredentials - returns the keys of the host in the config file.
+func configCredentials(alias string, hostCfg hostConfigV9) (credentials.Value, bool) {
+	if hostCfg.AccessKey == "" && hostCfg.SecretKey == "" {
+		return credentials.Value{}, false
+	}
+	return credentials.Value{
+		AccessKeyID:     hostCfg.AccessKey,
+		SecretAccessKey: hostCfg.SecretKey,
+		SessionToken:    hostCfg.SessionToken,
+	}, true
+}
+
+// awsCredentials - credentials of AWS hosts retrieved by a minio-go
+// provider. Whether the provider has any is only found out once per
+// process: the metadata service only fails after a timeout off AWS.
+type awsCredentials struct {
+	provider   credentials.Provider
+	isMetadata bool
+
+	once  sync.Once
+	creds *credentials.Credentials
+}
+
+func (a *awsCredentials) retrieve(alias string, hostCfg hostConfigV9) (credentials.Value, bool) {
+	if !isAmazon(newClientURL(hostCfg.URL).Host) {
+		return credentials.Value{}, false
+	}
+	a.once.Do(func() {
+		if a.isMetadata && strings.EqualFold(os.Getenv("AWS_EC2_METADATA_DISABLED"), "true") {
+			return
+		}
+		// The provider is asked again through creds once they expire.
+		creds := credentials.New(a.provider)
+		if value, e := creds.Get(); e == nil && value.AccessKeyID != "" {
+			a.creds = creds
+		}
+	})
+	if a.creds == nil {
+		return credentials.Value{}, false
+	}
+	value, e := a.creds.Get()
+	return value, e == nil
+}
+
+func (a *awsCredentials) credentials() *credentials.Credentials {
+	return a.creds
+}
+
+// resolveHostCredentials - sets the credentials of the host of alias to
+// those of the first source of credentialChain which has some, the host
+// is left without credentials if none does.
+func resolveHostCredentials(alias string, hostCfg *hostConfigV9) {
+	for _, provider := range credentialChain {
+		if creds, ok := provider.retrieve(alias, *hostCfg); ok {
+			hostCfg.AccessKey, hostCfg.SecretKey, hostCfg.SessionToken = creds.AccessKeyID, creds.SecretAccessKey, creds.SessionToken
+			hostCfg.creds = nil
+			if refresher, ok := provider.(credentialRefresher); ok {
+				hostCfg.creds = refresher.credentials()
+			}
+			return
+		}
+	}
+	hostCfg.AccessKey, hostCfg.SecretKey, hostCfg.SessionToken = "", "", ""
+	hostCfg.creds = nil
+}
@@ -0,0 +1,163 @@
+/*
+ * MinIO Client (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/minio/minio-go/v6/pkg/credentials"
+)
+
+func TestCredentialChain(t *testing.T) {
+	cfg := newConfigV9()
+	cfg.Hosts["myminio"] = hostConfigV9{URL: "http://localhost:9000", AccessKey: "config-access", SecretKey: "config-secret", API: "S3v4", Lookup: "auto"}
+	cfg.Hosts["s3"] = hostConfigV9{URL: "https://s3.amazonaws.com", AccessKey: "config-access", SecretKey: "config-secret", API: "S3v4", Lookup: "dns"}
+	cfg.Hosts["empty"] = hostConfigV9{URL: "http://localhost:9000", API: "S3v4", Lookup: "auto"}
+	defer withTestConfig(cfg.Hosts)()
+	defer func(chain []credentialProvider) { credentialChain = chain }(credentialChain)
+	defer func(accessKey, secretKey string) { globalAccessKey, globalSecretKey = accessKey, secretKey }(globalAccessKey, globalSecretKey)
+	defer func(args []string) { globalCommandArgs = args }(globalCommandArgs)
+
+	// Fake sources standing for the environment, the shared AWS file and
+	// the metadata service.
+	sources := map[string]string{}
+	fake := func(name string) credentialProvider {
+		return credentialProviderFunc(func(alias string, hostCfg hostConfigV9) (credentials.Value, bool) {
+			if sources[name] == "" {
+				return credentials.Value{}, false
+			}
+			return credentials.Value{AccessKeyID: sources[name], SecretAccessKey: name + "-secret", SessionToken: name + "-token"}, true
+		})
+	}
+	credentialChain = []credentialProvider{
+		credentialProviderFunc(flagCredentials),
+		fake("env"),
+		fake("file"),
+		credentialProviderFunc(configCredentials),
+		fake("metadata"),
+	}
+
+	testCases := []struct {
+		alias                      string
+		args                       []string
+		flags, env, file, metadata bool
+		accessKey                  string
+		sessionToken               string
+	}{
+		{"myminio", []string{"myminio/bucket"}, true, true, true, true, "flag-access", ""},
+		{"myminio", []string{"myminio/bucket"}, false, true, true, true, "env-access", "env-token"},
+		{"myminio", []string{"myminio/bucket"}, false, false, true, true, "file-access", "file-token"},
+		{"myminio", []string{"myminio/bucket"}, false, false, false, true, "config-access", ""},
+		{"empty", []string{"empty/bucket"}, false, false, false, true, "metadata-access", "metadata-token"},
+		{"empty", []string{"empty/bucket"}, false, false, false, false, "", ""},
+		// --access and --secret only apply to the alias of the command.
+		{"myminio", []string{"dir/file", "empty/bucket"}, true, false, false, false, "config-access", ""},
+		{"empty", []string{"dir/file", "empty/bucket", "myminio/bucket"}, true, false, false, false, "flag-access", ""},
+	}
+	for i, testCase := range testCases {
+		globalAccessKey, globalSecretKey = "", ""
+		if testCase.flags {
+			globalAccessKey, globalSecretKey = "flag-access", "flag-secret"
+		}
+		globalCommandArgs = testCase.args
+		sources["env"], sources["file"], sources["metadata"] = "", "", ""
+		if testCase.env {
+			sources["env"] = "env-access"
+		}
+		if testCase.file {
+			sources["file"] = "file-access"
+		}
+		if testCase.metadata {
+			sources["metadata"] = "metadata-access"
+		}
+		hostCfg, err := getHostConfig(testCase.alias)
+		if err != nil {
+			t.Fatalf("Test %d: %v", i+1, err)
+		}
+		if hostCfg.AccessKey != testCase.accessKey || hostCfg.SessionToken != testCase.sessionToken {
+			t.Fatalf("Test %d: expected access key %q and session token %q, got %q and %q",
+				i+1, testCase.accessKey, testCase.sessionToken, hostCfg.AccessKey, hostCfg.SessionToken)
+		}
+	}
+	globalAccessKey, globalSecretKey = "", ""
+
+	// AWS sources only apply to AWS hosts.
+	defer func(accessKey, secretKey string) {
+		os.Setenv("AWS_ACCESS_KEY_ID", accessKey)
+		os.Setenv("AWS_SECRET_ACCESS_KEY", secretKey)
+	}(os.Getenv("AWS_ACCESS_KEY_ID"), os.Getenv("AWS_SECRET_ACCESS_KEY"))
+	os.Setenv("AWS_ACCESS_KEY_ID", "aws-access")
+	os.Setenv("AWS_SECRET_ACCESS_KEY", "aws-secret")
+	credentialChain = []credentialProvider{
+		&awsCredentials{provider: &credentials.EnvAWS{}},
+		credentialProviderFunc(configCredentials),
+	}
+	for alias, accessKey := range map[string]string{"s3": "aws-access", "myminio": "config-access"} {
+		hostCfg, err := getHostConfig(alias)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hostCfg.AccessKey != accessKey {
+			t.Fatalf("Expected the access key of %s to be %q, got %q", alias, accessKey, hostCfg.AccessKey)
+		}
+	}
+}
+
+// expiringProvider - a credentials.Provider whose credentials expire
+// after every retrieval.
+type expiringProvider struct {
+	retrieved int
+}
+
+func (p *expiringProvider) Retrieve() (credentials.Value, error) {
+	p.retrieved++
+	return credentials.Value{AccessKeyID: fmt.Sprintf("access-%d", p.retrieved), SecretAccessKey: "secret"}, nil
+}
+
+func (p *expiringProvider) IsExpired() bool {
+	return true
+}
+
+func TestCredentialChainRefresh(t *testing.T) {
+	cfg := newConfigV9()
+	cfg.Hosts["s3"] = hostConfigV9{URL: "https://s3.amazonaws.com", API: "S3v4", Lookup: "dns"}
+	defer withTestConfig(cfg.Hosts)()
+	defer func(chain []credentialProvider) { credentialChain = chain }(credentialChain)
+
+	provider := &expiringProvider{}
+	credentialChain = []credentialProvider{&awsCredentials{provider: provider}}
+	hostCfg, err := getHostConfig("s3")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The client signs with the credentials of the source, which are
+	// retrieved again once expired instead of the keys first resolved.
+	s3Config := newS3Config(hostCfg.URL, hostCfg)
+	if s3Config.Creds == nil {
+		t.Fatal("Expected the credentials of the source to be passed to the client")
+	}
+	value, e := s3Config.Creds.Get()
+	if e != nil {
+		t.Fatal(e)
+	}
+	if value.AccessKeyID == hostCfg.AccessKey {
+		t.Fatalf("Expected expired credentials to be retrieved again, got %q again", value.AccessKeyID)
+	}
+}
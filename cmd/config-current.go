@@ -0,0 +1,65 @@
+/*
+ * MinIO Client (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	"github.com/minio/minio/pkg/console"
+)
+
+var configCurrentCmd = cli.Command{
+	Name:            "current",
+	Usage:           "print the alias of paths with a leading slash",
+	Action:          mainConfigCurrent,
+	Before:          setGlobalsFromContext,
+	Flags:           globalFlags,
+	HideHelpCommand: true,
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}}
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+EXAMPLES:
+  1. Print the current alias set by "mc config use" and its URL.
+     {{.Prompt}} {{.HelpName}}
+`,
+}
+
+// mainConfigCurrent is the handle for "mc config current" command.
+func mainConfigCurrent(ctx *cli.Context) error {
+	if len(ctx.Args()) != 0 {
+		cli.ShowCommandHelpAndExit(ctx, "current", 1) // last argument is exit code
+	}
+	console.SetColor("HostMessage", color.New(color.FgGreen))
+	console.SetColor("Alias", color.New(color.FgCyan, color.Bold))
+	console.SetColor("URL", color.New(color.FgYellow))
+
+	conf, err := loadMcConfig()
+	fatalIf(err.Trace(globalMCConfigVersion), "Unable to load config version `"+globalMCConfigVersion+"`.")
+
+	msg := hostMessage{op: "current", Alias: conf.Current}
+	if host, ok := conf.Hosts[conf.Current]; ok {
+		msg.URL = host.URL
+	}
+	printMsg(msg)
+	return nil
+}
@@ -0,0 +1,85 @@
+/*
+ * MinIO Client (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"strings"
+
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/probe"
+)
+
+// Per alias flag defaults are stored in the `defaults` map of a host
+// entry, keyed by the long flag name, for example
+//
+//   "defaults": {"storage-class": "GLACIER", "part-size": "64MiB"}
+//
+// The value of a flag is resolved in the following order:
+//   1. the flag given on the command line,
+//   2. the default of the alias the command operates on,
+//   3. the built-in default of the flag.
+// Global flags such as --json or --quiet are never taken from the config.
+
+// aliasDefaults - returns the alias and flag defaults of the last argument
+// referring to a configured alias, which is the target for commands taking
+// both sources and a target.
+func aliasDefaults(args []string) (string, map[string]string) {
+	for i := len(args) - 1; i >= 0; i-- {
+		alias, _ := url2Alias(args[i])
+		if alias == "" {
+			continue
+		}
+		if hostCfg := mustGetHostConfig(alias); hostCfg != nil && len(hostCfg.Defaults) > 0 {
+			return alias, hostCfg.Defaults
+		}
+	}
+	return "", nil
+}
+
+// applyFlagDefaults - sets every flag of the command which is present in
+// defaults and was not given on the command line. Unknown names are
+// ignored since one alias is shared by commands with different flags.
+func applyFlagDefaults(ctx *cli.Context, defaults map[string]string) *probe.Error {
+	globals := make(map[string]bool)
+	for _, flag := range globalFlags {
+		globals[strings.Split(flag.GetName(), ",")[0]] = true
+	}
+	for _, name := range ctx.FlagNames() {
+		value, ok := defaults[name]
+		if !ok || globals[name] || ctx.IsSet(name) {
+			continue
+		}
+		if e := ctx.Set(name, value); e != nil {
+			return probe.NewError(e).Trace(name, value)
+		}
+	}
+	return nil
+}
+
+// applyAliasDefaults - merges the config defaults of the alias the command
+// operates on beneath the flags given on the command line.
+func applyAliasDefaults(ctx *cli.Context) {
+	// Nothing to do for the application flags, parsed before any config is loaded.
+	if ctx.Command.Name == "" {
+		return
+	}
+	alias, defaults := aliasDefaults(ctx.Args())
+	if len(defaults) == 0 {
+		return
+	}
+	fatalIf(applyFlagDefaults(ctx, defaults).Trace(alias), "Unable to apply the defaults of alias `"+alias+"`.")
+}
@@ -0,0 +1,96 @@
+/*
+ * MinIO Client (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/json"
+
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/probe"
+)
+
+// redactedSecretKey replaces secret keys in exported configs.
+const redactedSecretKey = "REDACTED"
+
+var configExportCmd = cli.Command{
+	Name:            "export",
+	Usage:           "export hosts in configuration file with secrets redacted",
+	Action:          mainConfigExport,
+	Before:          setGlobalsFromContext,
+	Flags:           globalFlags,
+	HideHelpCommand: true,
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}}
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+EXAMPLES:
+  1. Export all hosts to a file which can be shared with your team.
+     {{.Prompt}} {{.HelpName}} > team-config.json
+`,
+}
+
+// hostsExportMessage container for the hosts of an exported config.
+type hostsExportMessage struct {
+	Status string    `json:"status"`
+	Config *configV9 `json:"config"`
+}
+
+// String prints the config as a file which can be imported.
+func (h hostsExportMessage) String() string {
+	buf, e := json.MarshalIndent(h.Config, "", "\t")
+	fatalIf(probe.NewError(e), "Unable to marshal config.")
+
+	return string(buf)
+}
+
+// JSON jsonified hosts export message.
+func (h hostsExportMessage) JSON() string {
+	h.Status = "success"
+	buf, e := json.MarshalIndent(h, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(buf)
+}
+
+// redactConfig - returns a copy of the config with all secret keys redacted.
+func redactConfig(cfg *configV9) *configV9 {
+	redacted := newConfigV9()
+	for alias, host := range cfg.Hosts {
+		if host.SecretKey != "" {
+			host.SecretKey = redactedSecretKey
+		}
+		redacted.Hosts[alias] = host
+	}
+	return redacted
+}
+
+func mainConfigExport(ctx *cli.Context) error {
+	if len(ctx.Args()) != 0 {
+		cli.ShowCommandHelpAndExit(ctx, "export", 1) // last argument is exit code
+	}
+
+	mcCfg, err := loadMcConfig()
+	fatalIf(err.Trace(globalMCConfigVersion), "Unable to load config `"+mustGetMcConfigPath()+"`.")
+
+	printMsg(hostsExportMessage{Config: redactConfig(mcCfg)})
+	return nil
+}
@@ -18,6 +18,8 @@ package cmd
 
 import (
 	"math/rand"
+	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/fatih/color"
@@ -36,6 +38,14 @@ var hostAddFlags = []cli.Flag{
 		Name:  "api",
 		Usage: "API signature. Valid options are '[S3v4, S3v2]'",
 	},
+	cli.BoolFlag{
+		Name:  "s3-scheme",
+		Usage: "expand s3://bucket/key URLs of the AWS CLI to this host instead of the first AWS host",
+	},
+	cli.StringFlag{
+		Name:  "region",
+		Usage: "region signing the requests to this host, instead of looking up the location of buckets",
+	},
 }
 var configHostAddCmd = cli.Command{
 	Name:            "add",
@@ -50,6 +60,7 @@ var configHostAddCmd = cli.Command{
 
 USAGE:
   {{.HelpName}} ALIAS URL ACCESSKEY SECRETKEY
+  {{.HelpName}} URL --access ACCESSKEY --secret SECRETKEY [--region REGION]
 
 FLAGS:
   {{range .VisibleFlags}}{{.}}
@@ -71,29 +82,75 @@ EXAMPLES:
      {{.Prompt}} {{.HelpName}} mys3 https://s3.amazonaws.com \
                  BKIKJAA5BMMU2RHO6IBB V8f1CwQqAcwo80UEIJEjc5gVQUSSx5ohQ9GSrr12
      {{.EnableHistory}}
+
+  4. Share credentials between all subdomain endpoints of example.com, used for URLs given instead of an
+     alias such as "https://eu.example.com/mybucket". For security reasons turn off bash history momentarily.
+     {{.DisableHistory}}
+     {{.Prompt}} {{.HelpName}} example "https://*.example.com" minio minio123
+     {{.EnableHistory}}
+
+  5. Add an S3 gateway requiring a client certificate under "gateway" alias, the certificate is presented to
+     this host only. For security reasons turn off bash history momentarily.
+     {{.DisableHistory}}
+     {{.Prompt}} {{.HelpName}} gateway https://s3.example.com minio minio123 \
+                 --client-cert ~/.mc/certs/client.crt --client-key ~/.mc/certs/client.key
+     {{.EnableHistory}}
+
+  6. Add Amazon S3 under "payer" alias accepting the charges of requester pays buckets in all later commands.
+     For security reasons turn off bash history momentarily.
+     {{.DisableHistory}}
+     {{.Prompt}} {{.HelpName}} payer https://s3.amazonaws.com \
+                 BKIKJAA5BMMU2RHO6IBB V8f1CwQqAcwo80UEIJEjc5gVQUSSx5ohQ9GSrr12 --request-payer
+     {{.EnableHistory}}
+
+  7. Add Amazon S3 in eu-west-1 under "eu" alias, which s3://bucket/key URLs then refer to. For security reasons
+     turn off bash history momentarily.
+     {{.DisableHistory}}
+     {{.Prompt}} {{.HelpName}} eu https://s3.eu-west-1.amazonaws.com \
+                 BKIKJAA5BMMU2RHO6IBB V8f1CwQqAcwo80UEIJEjc5gVQUSSx5ohQ9GSrr12 --s3-scheme
+     {{.EnableHistory}}
+
+  8. Add the credentials of a host by its URL instead of an alias, used for URLs given instead of an alias
+     such as "https://minio.example.com/mybucket". For security reasons turn off bash history momentarily.
+     {{.DisableHistory}}
+     {{.Prompt}} {{.HelpName}} https://minio.example.com --access minio --secret minio123 --region eu-west-1
+     {{.EnableHistory}}
 `,
 }
 
+// hostAddArgs - returns the key, URL and keys of the host added by
+// 'config host add', hosts added by URL are keyed by their URL.
+func hostAddArgs(ctx *cli.Context) (key, url, accessKey, secretKey string) {
+	args := ctx.Args()
+	if len(args) == 1 {
+		url = hostURLKey(args.Get(0))
+		return url, url, globalAccessKey, globalSecretKey
+	}
+	return args.Get(0), trimTrailingSeparator(args.Get(1)), args.Get(2), args.Get(3)
+}
+
 // checkConfigHostAddSyntax - verifies input arguments to 'config host add'.
 func checkConfigHostAddSyntax(ctx *cli.Context) {
 	args := ctx.Args()
 	argsNr := len(args)
-	if argsNr < 4 || argsNr > 5 {
+	if argsNr != 1 && (argsNr < 4 || argsNr > 5) {
 		fatalIf(errInvalidArgument().Trace(ctx.Args().Tail()...),
 			"Incorrect number of arguments for host add command.")
 	}
 
-	alias := args.Get(0)
-	url := args.Get(1)
-	accessKey := args.Get(2)
-	secretKey := args.Get(3)
+	alias, url, accessKey, secretKey := hostAddArgs(ctx)
 	api := ctx.String("api")
 	bucketLookup := ctx.String("lookup")
-	if !isValidAlias(alias) {
+	if argsNr == 1 {
+		if globalAccessKey == "" || globalSecretKey == "" {
+			fatalIf(errInvalidArgument().Trace(url),
+				"Both `--access` and `--secret` are required to add a host by URL.")
+		}
+	} else if !isValidAlias(alias) {
 		fatalIf(errInvalidAlias(alias), "Invalid alias.")
 	}
 
-	if !isValidHostURL(url) {
+	if !isValidHostURLPattern(url) {
 		fatalIf(errInvalidURL(url), "Invalid URL.")
 	}
 
@@ -125,6 +182,15 @@ func addHost(alias string, hostCfgV9 hostConfigV9) {
 
 	// Add new host.
 	mcCfgV9.Hosts[alias] = hostCfgV9
+	// s3:// URLs are expanded to a single host.
+	if hostCfgV9.S3Scheme {
+		for otherAlias, otherHostCfg := range mcCfgV9.Hosts {
+			if otherAlias != alias && otherHostCfg.S3Scheme {
+				otherHostCfg.S3Scheme = false
+				mcCfgV9.Hosts[otherAlias] = otherHostCfg
+			}
+		}
+	}
 
 	err = saveMcConfig(mcCfgV9)
 	fatalIf(err.Trace(alias), "Unable to update hosts in config version `"+mustGetMcConfigPath()+"`.")
@@ -134,9 +200,10 @@ func addHost(alias string, hostCfgV9 hostConfigV9) {
 		Alias:     alias,
 		URL:       hostCfgV9.URL,
 		AccessKey: hostCfgV9.AccessKey,
-		SecretKey: hostCfgV9.SecretKey,
+		SecretKey: redactedSecretKey,
 		API:       hostCfgV9.API,
 		Lookup:    hostCfgV9.Lookup,
+		Region:    hostCfgV9.Region,
 	})
 }
 
@@ -147,11 +214,13 @@ func probeS3Signature(accessKey, secretKey, url string) (string, *probe.Error) {
 	// Test s3 connection for API auto probe
 	s3Config := &Config{
 		// S3 connection parameters
-		Insecure:  globalInsecure,
-		AccessKey: accessKey,
-		SecretKey: secretKey,
-		Signature: "s3v4",
-		HostURL:   urlJoinPath(url, probeBucketName),
+		Insecure:   globalInsecure,
+		ClientCert: globalClientCert,
+		ClientKey:  globalClientKey,
+		AccessKey:  accessKey,
+		SecretKey:  secretKey,
+		Signature:  "s3v4",
+		HostURL:    urlJoinPath(url, probeBucketName),
 	}
 
 	s3Client, err := s3New(s3Config)
@@ -216,24 +285,42 @@ func mainConfigHostAdd(ctx *cli.Context) error {
 	checkConfigHostAddSyntax(ctx)
 
 	console.SetColor("HostMessage", color.New(color.FgGreen))
+	key, url, accessKey, secretKey := hostAddArgs(ctx)
 	var (
-		args      = ctx.Args()
-		url       = trimTrailingSeparator(args.Get(1))
-		accessKey = args.Get(2)
-		secretKey = args.Get(3)
-		api       = ctx.String("api")
-		lookup    = ctx.String("lookup")
+		api    = ctx.String("api")
+		lookup = ctx.String("lookup")
 	)
+	// Wildcard URLs match many endpoints, none of which can be probed.
+	if api == "" && strings.Contains(url, "*") {
+		api = "S3v4"
+	}
 
 	s3Config, err := buildS3Config(url, accessKey, secretKey, api, lookup)
 	fatalIf(err.Trace(ctx.Args()...), "Unable to initialize new config from the provided credentials.")
 
-	addHost(ctx.Args().Get(0), hostConfigV9{
+	// Later commands may run in other folders.
+	var clientCert, clientKey string
+	if globalClientCert != "" {
+		var e error
+		clientCert, e = filepath.Abs(globalClientCert)
+		fatalIf(probe.NewError(e).Trace(globalClientCert), "Unable to resolve the client certificate path.")
+		clientKey, e = filepath.Abs(globalClientKey)
+		fatalIf(probe.NewError(e).Trace(globalClientKey), "Unable to resolve the client key path.")
+	}
+
+	addHost(key, hostConfigV9{
 		URL:       s3Config.HostURL,
 		AccessKey: s3Config.AccessKey,
 		SecretKey: s3Config.SecretKey,
 		API:       s3Config.Signature,
 		Lookup:    lookup,
+		Region:    ctx.String("region"),
+		// Presented to this host only by later commands.
+		ClientCert: clientCert,
+		ClientKey:  clientKey,
+		// Requester pays buckets are charged to this host.
+		RequestPayer: globalRequestPayer,
+		S3Scheme:     ctx.Bool("s3-scheme"),
 	}) // Add a host with specified credentials.
 	return nil
 }
@@ -37,7 +37,7 @@ var configHostListCmd = cli.Command{
   {{.HelpName}} - {{.Usage}}
 
 USAGE:
-  {{.HelpName}} [ALIAS]
+  {{.HelpName}} [ALIAS | URL]
 
 FLAGS:
   {{range .VisibleFlags}}{{.}}
@@ -48,6 +48,9 @@ EXAMPLES:
 
   2. List a specific host.
      {{.Prompt}} {{.HelpName}} s3
+
+  3. List the host added by its URL "https://minio.example.com".
+     {{.Prompt}} {{.HelpName}} https://minio.example.com
 `,
 }
 
@@ -61,7 +64,7 @@ func checkConfigHostListSyntax(ctx *cli.Context) {
 	}
 
 	if args.Get(0) != "" {
-		if !isValidAlias(args.Get(0)) {
+		if !isValidAlias(args.Get(0)) && !isValidHostURLPattern(trimTrailingSeparator(args.Get(0))) {
 			fatalIf(errDummy().Trace(args.Get(0)),
 				"Invalid alias `"+args.Get(0)+"`.")
 		}
@@ -80,11 +83,15 @@ func mainConfigHostList(ctx *cli.Context) error {
 	console.SetColor("Lookup", color.New(color.FgCyan))
 
 	args := ctx.Args()
-	listHosts(args.Get(0)) // List all configured hosts.
+	alias := args.Get(0)
+	if alias != "" {
+		alias = hostKey(alias)
+	}
+	listHosts(alias) // List all configured hosts.
 	return nil
 }
 
-// Prints all the hosts.
+// Prints all the hosts, secret keys are never shown.
 func printHosts(hosts ...hostMessage) {
 	var maxAlias = 0
 	for _, host := range hosts {
@@ -102,6 +109,9 @@ func printHosts(hosts ...hostMessage) {
 			host.SecretKey = ""
 			host.API = ""
 		}
+		if host.SecretKey != "" {
+			host.SecretKey = redactedSecretKey
+		}
 		printMsg(host)
 	}
 }
@@ -130,6 +140,7 @@ func listHosts(alias string) {
 				SecretKey:   v.SecretKey,
 				API:         v.API,
 				Lookup:      v.Lookup,
+				Region:      v.Region,
 			})
 			return
 		}
@@ -147,6 +158,7 @@ func listHosts(alias string) {
 			SecretKey:   v.SecretKey,
 			API:         v.API,
 			Lookup:      v.Lookup,
+			Region:      v.Region,
 		})
 	}
 
@@ -35,6 +35,7 @@ var configHostRemoveCmd = cli.Command{
 
 USAGE:
   {{.HelpName}} ALIAS
+  {{.HelpName}} URL
 
 FLAGS:
   {{range .VisibleFlags}}{{.}}
@@ -43,6 +44,9 @@ EXAMPLES:
   1. Remove "goodisk" from config.
      {{.Prompt}} {{.HelpName}} goodisk
 
+  2. Remove the host added by its URL "https://minio.example.com" from config.
+     {{.Prompt}} {{.HelpName}} https://minio.example.com
+
 `,
 }
 
@@ -55,12 +59,21 @@ func checkConfigHostRemoveSyntax(ctx *cli.Context) {
 			"Incorrect number of arguments for remove host command.")
 	}
 
-	if !isValidAlias(args.Get(0)) {
+	if !isValidAlias(args.Get(0)) && !isValidHostURLPattern(trimTrailingSeparator(args.Get(0))) {
 		fatalIf(errDummy().Trace(args.Get(0)),
 			"Invalid alias `"+args.Get(0)+"`.")
 	}
 }
 
+// hostKey - returns the key of the host named by an alias, or by its
+// URL for the hosts added by URL.
+func hostKey(aliasOrURL string) string {
+	if isValidAlias(aliasOrURL) {
+		return aliasOrURL
+	}
+	return hostURLKey(trimTrailingSeparator(aliasOrURL))
+}
+
 // mainConfigHost is the handle for "mc config host rm" command.
 func mainConfigHostRemove(ctx *cli.Context) error {
 	checkConfigHostRemoveSyntax(ctx)
@@ -68,7 +81,7 @@ func mainConfigHostRemove(ctx *cli.Context) error {
 	console.SetColor("HostMessage", color.New(color.FgGreen))
 
 	args := ctx.Args()
-	alias := args.Get(0)
+	alias := hostKey(args.Get(0))
 	removeHost(alias) // Remove a host.
 	return nil
 }
@@ -80,6 +93,9 @@ func removeHost(alias string) {
 
 	// Remove host.
 	delete(conf.Hosts, alias)
+	if conf.Current == alias {
+		conf.Current = ""
+	}
 
 	err = saveMcConfig(conf)
 	fatalIf(err.Trace(alias), "Unable to save deleted hosts in config version `"+globalMCConfigVersion+"`.")
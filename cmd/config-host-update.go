@@ -0,0 +1,160 @@
+/*
+ * MinIO Client (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/minio/pkg/console"
+)
+
+var hostUpdateFlags = []cli.Flag{
+	cli.BoolFlag{
+		Name:  "verify",
+		Usage: "ping the host with the new credentials, the old ones are kept if it fails",
+	},
+}
+
+var configHostUpdateCmd = cli.Command{
+	Name:            "update",
+	ShortName:       "u",
+	Usage:           "update the credentials of a host in configuration file",
+	Action:          mainConfigHostUpdate,
+	Before:          setGlobalsFromContext,
+	Flags:           append(hostUpdateFlags, globalFlags...),
+	HideHelpCommand: true,
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} ALIAS --access ACCESSKEY --secret SECRETKEY
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+EXAMPLES:
+  1. Rotate the keys of "myminio", keeping its URL, API and lookup. For security reasons turn off bash
+     history momentarily.
+     {{.DisableHistory}}
+     {{.Prompt}} {{.HelpName}} myminio --access minio2 --secret minio456
+     {{.EnableHistory}}
+
+  2. Rotate the keys of "myminio" only if the server accepts them. For security reasons turn off bash
+     history momentarily.
+     {{.DisableHistory}}
+     {{.Prompt}} {{.HelpName}} myminio --access minio2 --secret minio456 --verify
+     {{.EnableHistory}}
+`,
+}
+
+// checkConfigHostUpdateSyntax - verifies input arguments to 'config host update'.
+func checkConfigHostUpdateSyntax(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) != 1 {
+		fatalIf(errInvalidArgument().Trace(args...),
+			"Incorrect number of arguments for host update command.")
+	}
+
+	alias := args.Get(0)
+	if !isValidAlias(alias) {
+		fatalIf(errInvalidAlias(alias), "Invalid alias.")
+	}
+
+	if globalAccessKey == "" || globalSecretKey == "" {
+		fatalIf(errInvalidArgument().Trace(alias),
+			"Both `--access` and `--secret` are required to update a host.")
+	}
+
+	if !isValidAccessKey(globalAccessKey) {
+		fatalIf(errInvalidArgument().Trace(globalAccessKey),
+			"Invalid access key `"+globalAccessKey+"`.")
+	}
+
+	if !isValidSecretKey(globalSecretKey) {
+		fatalIf(errInvalidArgument().Trace(globalSecretKey),
+			"Invalid secret key `"+globalSecretKey+"`.")
+	}
+}
+
+// updateHostCredentials - replaces the keys of the host of alias, the
+// other fields of the host are kept. With verify the host is pinged with
+// the new keys first, and the config is left untouched if that fails.
+func updateHostCredentials(alias, accessKey, secretKey string, verify bool) *probe.Error {
+	mcCfgV9, err := loadMcConfig()
+	if err != nil {
+		return err.Trace(globalMCConfigVersion)
+	}
+	hostCfg, ok := mcCfgV9.Hosts[alias]
+	if !ok {
+		return errNoMatchingHost(alias).Trace(alias)
+	}
+	hostCfg.AccessKey, hostCfg.SecretKey = accessKey, secretKey
+	// Session tokens belong to the keys being replaced.
+	hostCfg.SessionToken = ""
+
+	if verify {
+		clnt, err := s3New(newS3Config(hostCfg.URL, &hostCfg))
+		if err != nil {
+			return err.Trace(alias)
+		}
+		if _, err = pingClient(alias, clnt); err != nil {
+			return err.Trace(alias)
+		}
+	}
+
+	mcCfgV9.Hosts[alias] = hostCfg
+	if err = saveMcConfig(mcCfgV9); err != nil {
+		return err.Trace(alias)
+	}
+
+	printMsg(hostMessage{
+		op:        "update",
+		Alias:     alias,
+		URL:       hostCfg.URL,
+		AccessKey: hostCfg.AccessKey,
+		SecretKey: redactedSecretKey,
+		API:       hostCfg.API,
+		Lookup:    hostCfg.Lookup,
+	})
+	return nil
+}
+
+// mainConfigHostUpdate is the handle for "mc config host update" command.
+func mainConfigHostUpdate(ctx *cli.Context) error {
+	checkConfigHostUpdateSyntax(ctx)
+
+	console.SetColor("HostMessage", color.New(color.FgGreen))
+
+	alias := ctx.Args().Get(0)
+	mcCfgV9, err := loadMcConfig()
+	fatalIf(err.Trace(globalMCConfigVersion), "Unable to load config `"+mustGetMcConfigPath()+"`.")
+	if _, ok := mcCfgV9.Hosts[alias]; !ok {
+		fatalIf(errNoMatchingHost(alias).Trace(alias), "Unable to update the credentials of `"+alias+"`.")
+	}
+
+	err = updateHostCredentials(alias, globalAccessKey, globalSecretKey, ctx.Bool("verify"))
+	if err != nil {
+		if ctx.Bool("verify") {
+			reason, status := classifyPingError(err.ToGoError())
+			errorIf(err, reason+" for `"+alias+"` with the new credentials, the old ones are kept.")
+			return exitStatus(status)
+		}
+		fatalIf(err, "Unable to update the credentials of `"+alias+"`.")
+	}
+	return nil
+}
@@ -33,6 +33,7 @@ var configHostCmd = cli.Command{
 		configHostAddCmd,
 		configHostRemoveCmd,
 		configHostListCmd,
+		configHostUpdateCmd,
 	},
 	HideHelpCommand: true,
 }
@@ -55,6 +56,7 @@ type hostMessage struct {
 	SecretKey   string `json:"secretKey,omitempty"`
 	API         string `json:"api,omitempty"`
 	Lookup      string `json:"lookup,omitempty"`
+	Region      string `json:"region,omitempty"`
 }
 
 // Print the config information of one alias, when prettyPrint flag
@@ -77,6 +79,20 @@ func (h hostMessage) String() string {
 		return console.Colorize("HostMessage", "Removed `"+h.Alias+"` successfully.")
 	case "add":
 		return console.Colorize("HostMessage", "Added `"+h.Alias+"` successfully.")
+	case "update":
+		return console.Colorize("HostMessage", "Updated the credentials of `"+h.Alias+"` successfully.")
+	case "import":
+		return console.Colorize("HostMessage", "Imported `"+h.Alias+"` successfully.")
+	case "use":
+		if h.Alias == "" {
+			return console.Colorize("HostMessage", "Cleared the current alias successfully.")
+		}
+		return console.Colorize("HostMessage", "Using `"+h.Alias+"` as the current alias.")
+	case "current":
+		if h.Alias == "" {
+			return console.Colorize("HostMessage", "No current alias is set.")
+		}
+		return console.Colorize("Alias", h.Alias) + "  " + console.Colorize("URL", h.URL)
 	default:
 		return ""
 	}
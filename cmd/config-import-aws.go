@@ -0,0 +1,293 @@
+/*
+ * MinIO Client (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/minio/pkg/console"
+	"github.com/mitchellh/go-homedir"
+)
+
+var configImportAWSFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:  "credentials-file",
+		Usage: "AWS shared credentials file, defaults to $AWS_SHARED_CREDENTIALS_FILE or ~/.aws/credentials",
+	},
+	cli.StringFlag{
+		Name:  "config-file",
+		Usage: "AWS config file, defaults to $AWS_CONFIG_FILE or ~/.aws/config",
+	},
+}
+
+var configImportAWSCmd = cli.Command{
+	Name:            "import-aws",
+	Usage:           "add an alias for every profile of the AWS CLI",
+	Action:          mainConfigImportAWS,
+	Before:          setGlobalsFromContext,
+	Flags:           append(configImportAWSFlags, globalFlags...),
+	HideHelpCommand: true,
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [FLAGS]
+
+  Every profile with access keys becomes an alias of the same name, pointing
+  at the S3 endpoint of the region of the profile. Existing aliases of the same
+  name are replaced. Profiles signing in with SSO, assuming a role, running a
+  credential process or holding temporary credentials are skipped.
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+ENVIRONMENT VARIABLES:
+  AWS_SHARED_CREDENTIALS_FILE: location of the AWS shared credentials file.
+  AWS_CONFIG_FILE:             location of the AWS config file.
+
+EXAMPLES:
+  1. Add an alias for every profile configured for the AWS CLI.
+     {{.Prompt}} {{.HelpName}}
+
+  2. Import the profiles of credential files kept in a project folder.
+     {{.Prompt}} {{.HelpName}} --credentials-file project/.aws/credentials --config-file project/.aws/config
+`,
+}
+
+// awsIni holds the sections of an AWS credentials or config file, keyed
+// by the section name and the lower cased key. Keys of nested sections
+// such as `s3 =` are prefixed with the parent key, e.g. s3.endpoint_url.
+type awsIni map[string]map[string]string
+
+// parseAWSIni - parses an AWS credentials or config file.
+func parseAWSIni(r io.Reader) (awsIni, *probe.Error) {
+	ini := make(awsIni)
+	var section map[string]string
+	var parent string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), " \t\r")
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") || strings.HasPrefix(trimmed, ";") {
+			continue
+		}
+		if strings.HasPrefix(trimmed, "[") && strings.HasSuffix(trimmed, "]") {
+			name := strings.TrimSpace(trimmed[1 : len(trimmed)-1])
+			if ini[name] == nil {
+				ini[name] = make(map[string]string)
+			}
+			section, parent = ini[name], ""
+			continue
+		}
+		tokens := strings.SplitN(trimmed, "=", 2)
+		if section == nil || len(tokens) != 2 {
+			return nil, probe.NewError(errors.New("malformed line `" + trimmed + "`"))
+		}
+		key, value := strings.ToLower(strings.TrimSpace(tokens[0])), strings.TrimSpace(tokens[1])
+		if trimmed != line && parent != "" {
+			section[parent+"."+key] = value
+			continue
+		}
+		parent = ""
+		if value == "" {
+			parent = key
+		}
+		section[key] = value
+	}
+	if e := scanner.Err(); e != nil {
+		return nil, probe.NewError(e)
+	}
+	return ini, nil
+}
+
+// readAWSIni - parses the AWS file filename, a missing file is empty.
+func readAWSIni(filename string) (awsIni, *probe.Error) {
+	f, e := os.Open(filename)
+	if os.IsNotExist(e) {
+		return awsIni{}, nil
+	}
+	if e != nil {
+		return nil, probe.NewError(e)
+	}
+	defer f.Close()
+	ini, err := parseAWSIni(f)
+	if err != nil {
+		return nil, err.Trace(filename)
+	}
+	return ini, nil
+}
+
+// awsS3Endpoint - returns the S3 endpoint of an AWS region.
+func awsS3Endpoint(region string) string {
+	switch {
+	case region == "" || region == "us-east-1":
+		return "https://s3.amazonaws.com"
+	case strings.HasPrefix(region, "cn-"):
+		return "https://s3." + region + ".amazonaws.com.cn"
+	}
+	return "https://s3." + region + ".amazonaws.com"
+}
+
+// awsProfileHosts - returns the host of every profile of the AWS
+// credentials and config files with access keys, and why the other
+// profiles are skipped. Keys of the credentials file take precedence.
+func awsProfileHosts(credentials, config awsIni) (map[string]hostConfigV9, map[string]string) {
+	profiles := make(map[string]map[string]string)
+	for name, section := range config {
+		// Sections of the config file are named `profile NAME`, except for the default.
+		if name != "default" {
+			if !strings.HasPrefix(name, "profile ") {
+				continue
+			}
+			name = strings.TrimSpace(strings.TrimPrefix(name, "profile "))
+		}
+		profiles[name] = make(map[string]string)
+		for k, v := range section {
+			profiles[name][k] = v
+		}
+	}
+	for name, section := range credentials {
+		if profiles[name] == nil {
+			profiles[name] = make(map[string]string)
+		}
+		for k, v := range section {
+			profiles[name][k] = v
+		}
+	}
+
+	hosts := make(map[string]hostConfigV9)
+	skipped := make(map[string]string)
+	for name, profile := range profiles {
+		switch {
+		case profile["sso_start_url"] != "" || profile["sso_session"] != "":
+			skipped[name] = "signs in with SSO"
+		case profile["role_arn"] != "":
+			skipped[name] = "assumes a role"
+		case profile["credential_process"] != "":
+			skipped[name] = "runs a credential process"
+		case profile["aws_session_token"] != "":
+			skipped[name] = "holds temporary credentials"
+		case profile["aws_access_key_id"] == "" || profile["aws_secret_access_key"] == "":
+			skipped[name] = "has no access keys"
+		case !isValidAlias(name):
+			skipped[name] = "is not a valid alias name"
+		default:
+			host := hostConfigV9{
+				URL:       awsS3Endpoint(profile["region"]),
+				AccessKey: profile["aws_access_key_id"],
+				SecretKey: profile["aws_secret_access_key"],
+				API:       "S3v4",
+				Lookup:    "dns",
+			}
+			// Custom endpoints are rarely reachable with virtual host style requests.
+			for _, key := range []string{"s3.endpoint_url", "endpoint_url"} {
+				if endpoint := profile[key]; endpoint != "" {
+					host.URL, host.Lookup = strings.TrimSuffix(endpoint, "/"), "auto"
+					break
+				}
+			}
+			hosts[name] = host
+		}
+	}
+	return hosts, skipped
+}
+
+// awsFilePath - returns the AWS file given with flag, set with the
+// environment variable env or in the .aws folder of the home folder.
+func awsFilePath(ctx *cli.Context, flag, env, name string) string {
+	if filename := ctx.String(flag); filename != "" {
+		return filename
+	}
+	if filename := os.Getenv(env); filename != "" {
+		return filename
+	}
+	homeDir, e := homedir.Dir()
+	fatalIf(probe.NewError(e), "Unable to get the home folder.")
+	return filepath.Join(homeDir, ".aws", name)
+}
+
+// importAWSProfiles - adds the hosts to the config, returning the
+// sorted list of imported aliases.
+func importAWSProfiles(hosts map[string]hostConfigV9) ([]string, *probe.Error) {
+	mcCfg, err := loadMcConfig()
+	if err != nil {
+		return nil, err.Trace(globalMCConfigVersion)
+	}
+	var aliases []string
+	for alias, host := range hosts {
+		mcCfg.Hosts[alias] = host
+		aliases = append(aliases, alias)
+	}
+	sort.Strings(aliases)
+	if err = saveMcConfig(mcCfg); err != nil {
+		return nil, err.Trace(aliases...)
+	}
+	return aliases, nil
+}
+
+func mainConfigImportAWS(ctx *cli.Context) error {
+	if len(ctx.Args()) != 0 {
+		cli.ShowCommandHelpAndExit(ctx, "import-aws", 1) // last argument is exit code
+	}
+
+	console.SetColor("HostMessage", color.New(color.FgGreen))
+
+	credentialsFile := awsFilePath(ctx, "credentials-file", "AWS_SHARED_CREDENTIALS_FILE", "credentials")
+	configFile := awsFilePath(ctx, "config-file", "AWS_CONFIG_FILE", "config")
+	credentials, err := readAWSIni(credentialsFile)
+	fatalIf(err.Trace(credentialsFile), "Unable to read AWS credentials file `"+credentialsFile+"`.")
+	config, err := readAWSIni(configFile)
+	fatalIf(err.Trace(configFile), "Unable to read AWS config file `"+configFile+"`.")
+
+	hosts, skipped := awsProfileHosts(credentials, config)
+	var names []string
+	for name := range skipped {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		errorIf(probe.NewError(errors.New("Profile "+skipped[name]+".")).Trace(name), "Skipping AWS profile `"+name+"`.")
+	}
+	if len(hosts) == 0 {
+		fatalIf(errDummy().Trace(credentialsFile, configFile), "No AWS profile with access keys found.")
+	}
+
+	aliases, err := importAWSProfiles(hosts)
+	fatalIf(err, "Unable to update hosts in config version `"+mustGetMcConfigPath()+"`.")
+
+	for _, alias := range aliases {
+		host := hosts[alias]
+		printMsg(hostMessage{
+			op:        "import",
+			Alias:     alias,
+			URL:       host.URL,
+			AccessKey: host.AccessKey,
+			API:       host.API,
+			Lookup:    host.Lookup,
+		})
+	}
+	return nil
+}
@@ -0,0 +1,190 @@
+/*
+ * MinIO Client (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/mattn/go-isatty"
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/minio/pkg/console"
+)
+
+// mcEnvSecretKeyPrefix - environment variable carrying the secret key
+// of an imported alias, e.g. MC_SECRET_KEY_myminio.
+const mcEnvSecretKeyPrefix = "MC_SECRET_KEY_"
+
+var configImportCmd = cli.Command{
+	Name:            "import",
+	Usage:           "merge hosts from an exported configuration file",
+	Action:          mainConfigImport,
+	Before:          setGlobalsFromContext,
+	Flags:           globalFlags,
+	HideHelpCommand: true,
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [FILE]
+
+  Secret keys redacted in FILE are kept from the local configuration when the
+  alias and access key match, otherwise they are read from the environment
+  variable MC_SECRET_KEY_<alias> or prompted for.
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+ENVIRONMENT VARIABLES:
+  MC_SECRET_KEY_<alias>: secret key for a redacted alias.
+
+EXAMPLES:
+  1. Import hosts shared by your team, prompting for missing secret keys.
+     {{.Prompt}} {{.HelpName}} team-config.json
+
+  2. Import hosts from standard input, reading the secret key of "myminio" from the environment.
+     {{.DisableHistory}}
+     {{.Prompt}} export MC_SECRET_KEY_myminio=minio123
+     {{.EnableHistory}}
+     {{.Prompt}} cat team-config.json | {{.HelpName}}
+`,
+}
+
+// readImportConfig - decodes and validates an exported config.
+func readImportConfig(r io.Reader) (*configV9, *probe.Error) {
+	cfg := newConfigV9()
+	cfg.Version = ""
+	if e := json.NewDecoder(r).Decode(cfg); e != nil {
+		return nil, probe.NewError(e)
+	}
+	if ok, msg := validateConfigVersion(cfg); !ok {
+		return nil, probe.NewError(errors.New(strings.TrimSpace(msg)))
+	}
+	for alias, host := range cfg.Hosts {
+		if !isValidAlias(alias) && !isHostURLKey(alias, host) {
+			return nil, errInvalidAlias(alias).Trace(alias)
+		}
+		if ok, msgs := validateConfigHost(host); !ok {
+			return nil, probe.NewError(fmt.Errorf("Invalid host `%s`: %s", alias, strings.Join(msgs, ", ")))
+		}
+	}
+	return cfg, nil
+}
+
+// mergeImportedHosts - merges imported hosts into the local config and
+// returns the list of imported aliases. A redacted secret key keeps the
+// local one if the alias exists with the same access key, otherwise it
+// is obtained from lookupSecret.
+func mergeImportedHosts(local, imported *configV9, lookupSecret func(alias string) (string, *probe.Error)) ([]string, *probe.Error) {
+	var aliases []string
+	for alias := range imported.Hosts {
+		aliases = append(aliases, alias)
+	}
+	sort.Strings(aliases)
+
+	for _, alias := range aliases {
+		host := imported.Hosts[alias]
+		if host.SecretKey == redactedSecretKey {
+			host.SecretKey = ""
+			if current, ok := local.Hosts[alias]; ok && current.AccessKey == host.AccessKey {
+				host.SecretKey = current.SecretKey
+			}
+			if host.SecretKey == "" && host.AccessKey != "" {
+				secretKey, err := lookupSecret(alias)
+				if err != nil {
+					return nil, err.Trace(alias)
+				}
+				if !isValidSecretKey(secretKey) {
+					return nil, errInvalidArgument().Trace(alias)
+				}
+				host.SecretKey = secretKey
+			}
+		}
+		local.Hosts[alias] = host
+	}
+	return aliases, nil
+}
+
+// importSecretFromEnvOrPrompt - reads the secret key of alias from the
+// environment, falling back to a prompt when stdin is a terminal.
+func importSecretFromEnvOrPrompt(canPrompt bool) func(alias string) (string, *probe.Error) {
+	return func(alias string) (string, *probe.Error) {
+		if secretKey, ok := os.LookupEnv(mcEnvSecretKeyPrefix + alias); ok {
+			return secretKey, nil
+		}
+		if !canPrompt {
+			return "", probe.NewError(errors.New("Secret key for alias `" + alias + "` is redacted, please set " + mcEnvSecretKeyPrefix + alias))
+		}
+		fmt.Fprintf(os.Stderr, "Enter secret key for `%s`: ", alias)
+		line, e := bufio.NewReader(os.Stdin).ReadString('\n')
+		if e != nil && e != io.EOF {
+			return "", probe.NewError(e)
+		}
+		return strings.TrimSpace(line), nil
+	}
+}
+
+func mainConfigImport(ctx *cli.Context) error {
+	if len(ctx.Args()) > 1 {
+		cli.ShowCommandHelpAndExit(ctx, "import", 1) // last argument is exit code
+	}
+
+	console.SetColor("HostMessage", color.New(color.FgGreen))
+
+	var reader io.Reader = os.Stdin
+	canPrompt := false
+	if filename := ctx.Args().Get(0); filename != "" && filename != "-" {
+		f, e := os.Open(filename)
+		fatalIf(probe.NewError(e), "Unable to open `"+filename+"`.")
+		defer f.Close()
+		reader = f
+		canPrompt = isatty.IsTerminal(os.Stdin.Fd())
+	}
+
+	imported, err := readImportConfig(reader)
+	fatalIf(err.Trace(ctx.Args()...), "Unable to read imported config.")
+
+	mcCfg, err := loadMcConfig()
+	fatalIf(err.Trace(globalMCConfigVersion), "Unable to load config `"+mustGetMcConfigPath()+"`.")
+
+	aliases, err := mergeImportedHosts(mcCfg, imported, importSecretFromEnvOrPrompt(canPrompt))
+	fatalIf(err.Trace(ctx.Args()...), "Unable to import hosts.")
+
+	err = saveMcConfig(mcCfg)
+	fatalIf(err.Trace(aliases...), "Unable to update hosts in config version `"+mustGetMcConfigPath()+"`.")
+
+	for _, alias := range aliases {
+		host := mcCfg.Hosts[alias]
+		printMsg(hostMessage{
+			op:        "import",
+			Alias:     alias,
+			URL:       host.URL,
+			AccessKey: host.AccessKey,
+			API:       host.API,
+			Lookup:    host.Lookup,
+		})
+	}
+	return nil
+}
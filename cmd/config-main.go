@@ -42,6 +42,14 @@ var configCmd = cli.Command{
 	Flags:           append(configFlags, globalFlags...),
 	Subcommands: []cli.Command{
 		configHostCmd,
+		configExportCmd,
+		configImportCmd,
+		configImportAWSCmd,
+		configCheckCmd,
+		configRepairCmd,
+		configUseCmd,
+		configCurrentCmd,
+		configBackendsCmd,
 	},
 }
 
@@ -0,0 +1,159 @@
+/*
+ * MinIO Client (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/minio/pkg/console"
+)
+
+var configRepairCmd = cli.Command{
+	Name:            "repair",
+	Usage:           "fix trivial problems of the configuration file",
+	Action:          mainConfigRepair,
+	Before:          setGlobalsFromContext,
+	Flags:           globalFlags,
+	HideHelpCommand: true,
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}}
+
+  A missing version is set, host URLs are normalized and duplicate aliases
+  are removed, keeping their last definition which is the one used by mc.
+  The configuration file is rewritten at once, fields unknown to mc are not
+  kept. The original file is saved next to it with a ".bak" suffix first.
+  Problems which cannot be repaired are reported as by "mc config check".
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+EXAMPLES:
+  1. Repair a configuration file edited by hand.
+     {{.Prompt}} {{.HelpName}}
+`,
+}
+
+// configRepairMessage container for the problems repaired in a config file.
+type configRepairMessage struct {
+	Status   string        `json:"status"`
+	Path     string        `json:"path"`
+	Repaired []configIssue `json:"repaired"`
+	// Copy of the config file before it was repaired.
+	Backup string `json:"backup,omitempty"`
+	// Problems left, which cannot be repaired.
+	Issues []configIssue `json:"issues"`
+}
+
+func (c configRepairMessage) String() string {
+	var msg string
+	if len(c.Repaired) == 0 {
+		msg = console.Colorize("ConfigCheck", "Nothing to repair in `"+c.Path+"`.")
+	} else {
+		msg = console.Colorize("ConfigCheck", fmt.Sprintf("Repaired %d problem(s) in `%s`:", len(c.Repaired), c.Path))
+		for _, issue := range c.Repaired {
+			issue.Repairable = false
+			msg += "\n  " + issue.String()
+		}
+		msg += "\n" + console.Colorize("ConfigCheck", "The original file is saved in `"+c.Backup+"`.")
+	}
+	if len(c.Issues) != 0 {
+		msg += "\n" + console.Colorize("ConfigCheck", fmt.Sprintf("Found %d problem(s) to fix by hand:", len(c.Issues)))
+		for _, issue := range c.Issues {
+			msg += "\n  " + console.Colorize("ConfigIssue", issue.String())
+		}
+	}
+	return msg
+}
+
+func (c configRepairMessage) JSON() string {
+	c.Status = "success"
+	if len(c.Issues) != 0 {
+		c.Status = "error"
+	}
+	jsonMessageBytes, e := json.MarshalIndent(c, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(jsonMessageBytes)
+}
+
+// repairConfig - fixes the repairable problems of cfg, as reported by
+// checkConfigData. Duplicate aliases are gone once parsed.
+func repairConfig(cfg *configV9) {
+	if cfg.Version == "" {
+		cfg.Version = globalMCConfigVersion
+	}
+	if cfg.Hosts == nil {
+		cfg.Hosts = make(map[string]hostConfigV9)
+	}
+	for alias, host := range cfg.Hosts {
+		if envRefRegex.MatchString(host.URL) {
+			// Variable names are case sensitive.
+			continue
+		}
+		if normalized := normalizeHostURL(host.URL); isValidHostURLPattern(normalized) {
+			host.URL = normalized
+			cfg.Hosts[alias] = host
+		}
+	}
+	if _, ok := cfg.Hosts[cfg.Current]; !ok {
+		cfg.Current = ""
+	}
+}
+
+func mainConfigRepair(ctx *cli.Context) error {
+	if len(ctx.Args()) != 0 {
+		cli.ShowCommandHelpAndExit(ctx, "repair", 1) // last argument is exit code
+	}
+	console.SetColor("ConfigCheck", color.New(color.FgGreen, color.Bold))
+	console.SetColor("ConfigIssue", color.New(color.FgYellow))
+
+	data, err := readConfigFile()
+	fatalIf(err, "Unable to read config.")
+
+	issues, cfg := checkConfigData(data)
+	msg := configRepairMessage{Path: mustGetMcConfigPath()}
+	for _, issue := range issues {
+		if issue.Repairable {
+			msg.Repaired = append(msg.Repaired, issue)
+		} else {
+			msg.Issues = append(msg.Issues, issue)
+		}
+	}
+	if len(msg.Repaired) != 0 {
+		msg.Backup = mustGetMcConfigPath() + ".bak"
+		fatalIf(writeFileAtomic(msg.Backup, data).Trace(msg.Backup), "Unable to save a copy of the config.")
+		repairConfig(cfg)
+		fatalIf(saveMcConfig(cfg).Trace(), "Unable to save config.")
+
+		// Lines changed with the rewrite.
+		data, err = readConfigFile()
+		fatalIf(err, "Unable to read config.")
+		msg.Issues, _ = checkConfigData(data)
+	}
+	printMsg(msg)
+	if len(msg.Issues) != 0 {
+		return exitStatus(globalErrorExitStatus)
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+/*
+ * MinIO Client (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	"github.com/minio/minio/pkg/console"
+)
+
+var configUseFlags = []cli.Flag{
+	cli.BoolFlag{
+		Name:  "clear",
+		Usage: "clear the current alias",
+	},
+}
+
+var configUseCmd = cli.Command{
+	Name:            "use",
+	Usage:           "set the alias of paths with a leading slash",
+	Action:          mainConfigUse,
+	Before:          setGlobalsFromContext,
+	Flags:           append(configUseFlags, globalFlags...),
+	HideHelpCommand: true,
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} ALIAS
+  {{.HelpName}} --clear
+
+  Once set, paths starting with a slash are on the current alias: "/mybucket"
+  stands for "ALIAS/mybucket". Local absolute paths must then be given relative
+  to the current folder, until the current alias is cleared.
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+EXAMPLES:
+  1. Work against "myminio" without repeating its alias.
+     {{.Prompt}} {{.HelpName}} myminio
+     {{.Prompt}} mc ls /mybucket
+
+  2. Go back to local absolute paths.
+     {{.Prompt}} {{.HelpName}} --clear
+`,
+}
+
+// checkConfigUseSyntax - verifies input arguments to 'config use'.
+func checkConfigUseSyntax(ctx *cli.Context) {
+	args := ctx.Args()
+	if ctx.Bool("clear") {
+		if len(args) != 0 {
+			fatalIf(errInvalidArgument().Trace(args...), "--clear takes no alias.")
+		}
+		return
+	}
+	if len(args) != 1 {
+		cli.ShowCommandHelpAndExit(ctx, "use", 1) // last argument is exit code
+	}
+	if !isValidAlias(args.Get(0)) {
+		fatalIf(errInvalidAlias(args.Get(0)), "Invalid alias `"+args.Get(0)+"`.")
+	}
+}
+
+// mainConfigUse is the handle for "mc config use" command.
+func mainConfigUse(ctx *cli.Context) error {
+	checkConfigUseSyntax(ctx)
+
+	console.SetColor("HostMessage", color.New(color.FgGreen))
+
+	alias := ctx.Args().Get(0)
+	useAlias(alias)
+	return nil
+}
+
+// useAlias - makes alias the current alias, none if empty.
+func useAlias(alias string) {
+	conf, err := loadMcConfig()
+	fatalIf(err.Trace(globalMCConfigVersion), "Unable to load config version `"+globalMCConfigVersion+"`.")
+
+	if _, ok := conf.Hosts[alias]; alias != "" && !ok {
+		fatalIf(errNoMatchingHost(alias), "No host is configured for `"+alias+"`.")
+	}
+	conf.Current = alias
+
+	err = saveMcConfig(conf)
+	fatalIf(err.Trace(alias), "Unable to save the current alias in config version `"+globalMCConfigVersion+"`.")
+
+	printMsg(hostMessage{op: "use", Alias: alias})
+}
@@ -60,6 +60,24 @@ func isValidHostURL(hostURL string) (ok bool) {
 	return ok
 }
 
+// isValidHostURLPattern - validate input host url, which may match many
+// endpoints with a wildcard as in `https://*.example.com` or `*`.
+func isValidHostURLPattern(hostURL string) bool {
+	if hostURL == "*" {
+		return true
+	}
+	switch strings.Count(hostURL, "*") {
+	case 0:
+	case 1:
+		if !strings.Contains(hostURL, "://*.") {
+			return false
+		}
+	default:
+		return false
+	}
+	return isValidHostURL(hostURL)
+}
+
 // isValidAPI - Validates if API signature string of supported type.
 func isValidAPI(api string) (ok bool) {
 	switch strings.ToLower(api) {
@@ -42,6 +42,28 @@ func TestValidHostURL(t *testing.T) {
 	}
 }
 
+// Tests host URLs with wildcards.
+func TestValidHostURLPattern(t *testing.T) {
+	testCases := []struct {
+		hostURL string
+		isHost  bool
+	}{
+		{"*", true},
+		{"https://*.example.com", true},
+		{"https://*.example.com:9000", true},
+		{"https://localhost:9000", true},
+		{"https://eu.*.com", false},
+		{"https://*.*.example.com", false},
+		{"*.example.com", false},
+	}
+
+	for _, testCase := range testCases {
+		if isHost := isValidHostURLPattern(testCase.hostURL); testCase.isHost != isHost {
+			t.Fatalf("%s: Expected %t, got %t", testCase.hostURL, testCase.isHost, isHost)
+		}
+	}
+}
+
 func TestIsValidAPI(t *testing.T) {
 	equalAssert(isValidAPI("s3V2"), true, t)
 	equalAssert(isValidAPI("S3v2"), true, t)
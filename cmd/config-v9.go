@@ -20,6 +20,7 @@ import (
 	"sync"
 
 	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/minio-go/v6/pkg/credentials"
 	"github.com/minio/minio/pkg/quick"
 )
 
@@ -40,14 +41,45 @@ type hostConfigV9 struct {
 	URL       string `json:"url"`
 	AccessKey string `json:"accessKey"`
 	SecretKey string `json:"secretKey"`
-	API       string `json:"api"`
-	Lookup    string `json:"lookup"`
+	// Session token of temporary credentials, e.g. of an IAM role.
+	SessionToken string `json:"sessionToken,omitempty"`
+	API          string `json:"api"`
+	Lookup       string `json:"lookup"`
+	PartSize     string `json:"partSize,omitempty"`
+	// Region signing the requests to this host, the location of
+	// buckets is looked up if not set.
+	Region string `json:"region,omitempty"`
+	// Flag defaults of this alias, see applyAliasDefaults.
+	Defaults map[string]string `json:"defaults,omitempty"`
+	// Send unsigned requests even if credentials are configured.
+	Anonymous bool `json:"anonymous,omitempty"`
+	// Upload and download objects through the S3 transfer acceleration endpoint.
+	Accelerate bool `json:"accelerate,omitempty"`
+	// Accept the charges of requester pays buckets of this host.
+	RequestPayer bool `json:"requestPayer,omitempty"`
+	// Expand `s3://bucket/key` URLs of the AWS CLI to this host, see
+	// s3SchemeHost.
+	S3Scheme bool `json:"s3Scheme,omitempty"`
+	// User-Agent sent instead of the one of mc, --user-agent takes precedence.
+	UserAgent string `json:"userAgent,omitempty"`
+	// PEM files of the certificate presented to this host only, for
+	// mutual TLS. --client-cert and --client-key take precedence.
+	ClientCert string `json:"clientCert,omitempty"`
+	ClientKey  string `json:"clientKey,omitempty"`
+	// Credentials set by resolveHostCredentials, refreshed by their
+	// source once expired.
+	creds *credentials.Credentials
 }
 
 // configV8 config version.
 type configV9 struct {
 	Version string                  `json:"version"`
 	Hosts   map[string]hostConfigV9 `json:"hosts"`
+	// Alias of paths with a leading slash, set by "mc config use".
+	Current string `json:"current,omitempty"`
+	// Complete the buckets and objects of aliases listing them, set by
+	// "mc --autocompletion --remote-completion".
+	RemoteCompletion bool `json:"remoteCompletion,omitempty"`
 }
 
 // newConfigV9 - new config version.
@@ -139,7 +171,8 @@ func loadConfigV9() (*configV9, *probe.Error) {
 	return cfgV9, nil
 }
 
-// saveConfigV8 - saves an updated config.
+// saveConfigV9 - writes the config atomically: quick writes a temporary
+// file renamed over the config, which is complete or left untouched.
 func saveConfigV9(cfgV9 *configV9) *probe.Error {
 	cfgMutex.Lock()
 	defer cfgMutex.Unlock()
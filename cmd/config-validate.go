@@ -57,9 +57,28 @@ func validateConfigHost(host hostConfigV9) (bool, []string) {
 		validationSuccessful = false
 		hostErrors = append(hostErrors, errInvalidAPISignature(host.API, host.URL).ToGoError().Error())
 	}
-	if !isValidHostURL(host.URL) {
+	if hostURL, ok := expandedHostURL(host); ok && !isValidHostURLPattern(hostURL) {
 		validationSuccessful = false
 		hostErrors = append(hostErrors, errInvalidURL(host.URL).ToGoError().Error())
 	}
+	if host.PartSize != "" {
+		if _, err := parsePartSize(host.PartSize); err != nil {
+			validationSuccessful = false
+			hostErrors = append(hostErrors, fmt.Sprintf("Invalid part size `%s` for host %s: %s", host.PartSize, host.URL, err.ToGoError()))
+		}
+	}
+	if (host.ClientCert == "") != (host.ClientKey == "") {
+		validationSuccessful = false
+		hostErrors = append(hostErrors, fmt.Sprintf("Client certificate for host %s requires both clientCert and clientKey", host.URL))
+	}
 	return validationSuccessful, hostErrors
 }
+
+// expandedHostURL - returns the URL of host with its environment variable
+// references expanded, ok is false if a variable is not set. Such hosts
+// are only reported once they are used, for the config to remain usable
+// by the other commands.
+func expandedHostURL(host hostConfigV9) (hostURL string, ok bool) {
+	hostURL, err := expandEnvRefs("", host.URL)
+	return hostURL, err == nil
+}
@@ -17,12 +17,15 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"math"
 	"net/url"
 	"os"
 	"path/filepath"
 	"regexp"
 	"runtime"
+	"sort"
 	"strings"
 
 	"github.com/minio/mc/pkg/probe"
@@ -131,6 +134,9 @@ func saveMcConfig(config *configV9) *probe.Error {
 	if config == nil {
 		return errInvalidArgument().Trace()
 	}
+	if globalNoConfig {
+		return errConfigDisabled().Trace()
+	}
 
 	err := createMcConfigDir()
 	if err != nil {
@@ -147,6 +153,34 @@ func saveMcConfig(config *configV9) *probe.Error {
 	return nil
 }
 
+// setNoConfig - replaces the config by one holding only the host given
+// with --endpoint-url, --access and --secret, aliased by the host name
+// of the endpoint. Nothing is read from the config folder.
+func setNoConfig() *probe.Error {
+	if globalEndpointURL == "" {
+		return probe.NewError(errors.New("`--endpoint-url` is required"))
+	}
+	if (globalAccessKey == "") != (globalSecretKey == "") {
+		return probe.NewError(errors.New("`--access` and `--secret` must be given together"))
+	}
+	endpoint, e := url.Parse(globalEndpointURL)
+	if e != nil {
+		return probe.NewError(e).Trace(globalEndpointURL)
+	}
+	cfg := newConfigV9()
+	cfg.Hosts[endpoint.Hostname()] = hostConfigV9{
+		URL:       globalEndpointURL,
+		AccessKey: globalAccessKey,
+		SecretKey: globalSecretKey,
+		API:       "S3v4",
+		Lookup:    "auto",
+	}
+	loadMcConfig = func() (*configV9, *probe.Error) {
+		return cfg, nil
+	}
+	return nil
+}
+
 // isMcConfigExists returns err if config doesn't exist.
 func isMcConfigExists() bool {
 	configFile, err := getMcConfigPath()
@@ -159,6 +193,17 @@ func isMcConfigExists() bool {
 	return true
 }
 
+// hostURLKey - returns the key of the host added by URL instead of an
+// alias, see `config host add URL`.
+func hostURLKey(hostURL string) string {
+	return normalizeHostURL(hostURL)
+}
+
+// isHostURLKey - reports whether the host of key was added by URL.
+func isHostURLKey(key string, hostCfg hostConfigV9) bool {
+	return isValidHostURLPattern(key) && key == hostURLKey(hostCfg.URL)
+}
+
 // isValidAlias - Check if alias valid.
 func isValidAlias(alias string) bool {
 	return regexp.MustCompile("^[a-zA-Z][a-zA-Z0-9-_]+$").MatchString(alias)
@@ -174,6 +219,10 @@ func getHostConfig(alias string) (*hostConfigV9, *probe.Error) {
 	// if host is exact return quickly.
 	if _, ok := mcCfg.Hosts[alias]; ok {
 		hostCfg := mcCfg.Hosts[alias]
+		if err = expandHostConfigEnv(alias, &hostCfg); err != nil {
+			return nil, err.Trace(alias)
+		}
+		resolveHostCredentials(alias, &hostCfg)
 		return &hostCfg, nil
 	}
 
@@ -181,9 +230,116 @@ func getHostConfig(alias string) (*hostConfigV9, *probe.Error) {
 	return nil, errNoMatchingHost(alias).Trace(alias)
 }
 
+// hostPatternScore - returns how specifically the URL pattern of a host
+// matches the endpoint of target, -1 if it does not match. Exact hosts
+// win over `*.example.com` patterns, which win over the catch-all `*`.
+func hostPatternScore(pattern string, target *clientURL) int {
+	if pattern == "*" {
+		return 0
+	}
+	patternURL := newClientURL(pattern)
+	if patternURL.Type != objectStorage || patternURL.Scheme != target.Scheme {
+		return -1
+	}
+	patternHost, targetHost := strings.ToLower(patternURL.Host), strings.ToLower(target.Host)
+	if !strings.Contains(patternHost, "*") {
+		if patternHost == targetHost {
+			return math.MaxInt32
+		}
+		return -1
+	}
+	if strings.HasPrefix(patternHost, "*.") && strings.HasSuffix(targetHost, patternHost[1:]) {
+		return len(patternHost)
+	}
+	return -1
+}
+
+// matchHostAlias - returns the alias of the host whose URL pattern
+// matches the endpoint of target most specifically, empty if none does.
+func matchHostAlias(mcCfg *configV9, target *clientURL) string {
+	var aliases []string
+	for alias := range mcCfg.Hosts {
+		aliases = append(aliases, alias)
+	}
+	// Ties go to the first alias, for matching to not depend on map order.
+	sort.Strings(aliases)
+
+	matched, bestScore := "", -1
+	for _, alias := range aliases {
+		if score := hostPatternScore(mcCfg.Hosts[alias].URL, target); score > bestScore {
+			matched, bestScore = alias, score
+		}
+	}
+	return matched
+}
+
+// getHostConfigByURL retrieves the configuration of the host matching the
+// endpoint of a URL given instead of an alias, hosts may be configured with
+// wildcard URLs to share credentials between many endpoints.
+func getHostConfigByURL(urlStr string) (*hostConfigV9, *probe.Error) {
+	mcCfg, err := loadMcConfig()
+	if err != nil {
+		return nil, err.Trace(urlStr)
+	}
+
+	target := newClientURL(urlStr)
+	if target.Type != objectStorage {
+		return nil, errNoMatchingHost(urlStr).Trace(urlStr)
+	}
+	matched := matchHostAlias(mcCfg, target)
+	if matched == "" {
+		return nil, errNoMatchingHost(urlStr).Trace(urlStr)
+	}
+
+	hostCfg := mcCfg.Hosts[matched]
+	if err = expandHostConfigEnv(matched, &hostCfg); err != nil {
+		return nil, err.Trace(urlStr)
+	}
+	hostCfg.URL = target.Scheme + target.SchemeSeparator + target.Host
+	resolveHostCredentials(matched, &hostCfg)
+	return &hostCfg, nil
+}
+
+// envRefRegex matches `${VAR}` and `${VAR:-default}` references.
+var envRefRegex = regexp.MustCompile(`\$\{([a-zA-Z_][a-zA-Z0-9_]*)(:-([^}]*))?\}`)
+
+// expandEnvRefs - replaces environment variable references in value,
+// variables which are not set fail unless a default is given.
+func expandEnvRefs(alias, value string) (string, *probe.Error) {
+	var err *probe.Error
+	expanded := envRefRegex.ReplaceAllStringFunc(value, func(ref string) string {
+		match := envRefRegex.FindStringSubmatch(ref)
+		if v, ok := os.LookupEnv(match[1]); ok {
+			return v
+		}
+		if match[2] == "" && err == nil {
+			err = errEnvVarNotSet(match[1], alias)
+		}
+		return match[3]
+	})
+	return expanded, err
+}
+
+// expandHostConfigEnv - expands environment variable references in the
+// URL and credentials of a host, so that secrets need not be stored in
+// the config file.
+func expandHostConfigEnv(alias string, hostCfg *hostConfigV9) *probe.Error {
+	for _, field := range []*string{&hostCfg.URL, &hostCfg.AccessKey, &hostCfg.SecretKey} {
+		expanded, err := expandEnvRefs(alias, *field)
+		if err != nil {
+			return err.Trace()
+		}
+		*field = expanded
+	}
+	return nil
+}
+
 // mustGetHostConfig retrieves host specific configuration such as access keys, signature type.
 func mustGetHostConfig(alias string) *hostConfigV9 {
-	hostCfg, _ := getHostConfig(alias)
+	hostCfg, err := getHostConfig(alias)
+	if _, ok := err.ToGoError().(envVarNotSetErr); ok {
+		fatalIf(err, "Unable to load the config of alias `"+alias+"`.")
+	}
 	// If alias is not found,
 	// look for it in the environment variable.
 	if hostCfg == nil {
@@ -288,8 +444,90 @@ func expandAliasFromEnv(envURL string) (*hostConfigV9, *probe.Error) {
 	}, nil
 }
 
+// overrideEndpoint - points the host at --endpoint-url if given, keeping
+// the credentials, signature and lookup style of the alias.
+func overrideEndpoint(hostCfg *hostConfigV9) *hostConfigV9 {
+	if globalEndpointURL == "" {
+		return hostCfg
+	}
+	overridden := *hostCfg
+	overridden.URL = globalEndpointURL
+	return &overridden
+}
+
+// `s3://bucket/key` URLs of the AWS CLI, expanded to an AWS host of the
+// config.
+const (
+	s3SchemePrefix     = "s3://"
+	s3SchemeDefaultURL = "https://s3.amazonaws.com"
+)
+
+// s3SchemeHost - returns the host of `s3://` URLs: the host added with
+// `--s3-scheme`, otherwise the first AWS host of the config by alias,
+// otherwise Amazon S3 in us-east-1 with the credentials of the AWS
+// sources of credentialChain.
+func s3SchemeHost() (*hostConfigV9, *probe.Error) {
+	mcCfg, err := loadMcConfig()
+	if err != nil {
+		return nil, err.Trace(s3SchemePrefix)
+	}
+	var aliases []string
+	for alias := range mcCfg.Hosts {
+		aliases = append(aliases, alias)
+	}
+	sort.Strings(aliases)
+
+	matched := ""
+	for _, alias := range aliases {
+		if mcCfg.Hosts[alias].S3Scheme {
+			matched = alias
+			break
+		}
+	}
+	for _, alias := range aliases {
+		if matched != "" {
+			break
+		}
+		if hostURL := mcCfg.Hosts[alias].URL; !strings.Contains(hostURL, "*") && isAmazon(newClientURL(hostURL).Host) {
+			matched = alias
+		}
+	}
+	if matched != "" {
+		return getHostConfig(matched)
+	}
+
+	hostCfg := &hostConfigV9{URL: s3SchemeDefaultURL, API: "S3v4", Lookup: "dns"}
+	resolveHostCredentials(s3SchemePrefix, hostCfg)
+	return hostCfg, nil
+}
+
+// currentAlias - returns the alias set by "mc config use", empty if none
+// is set or the config cannot be loaded.
+func currentAlias() string {
+	cfg, err := loadMcConfig()
+	if err != nil {
+		return ""
+	}
+	return cfg.Current
+}
+
 // expandAlias expands aliased URL if any match is found, returns as is otherwise.
 func expandAlias(aliasedURL string) (alias string, urlStr string, hostCfg *hostConfigV9, err *probe.Error) {
+	// `s3://` stands for an alias, which is `s3://` itself for the
+	// expanded URLs of objects to be aliased again.
+	if strings.HasPrefix(aliasedURL, s3SchemePrefix) {
+		if hostCfg, err = s3SchemeHost(); err != nil {
+			return "", "", nil, err.Trace(aliasedURL)
+		}
+		hostCfg = overrideEndpoint(hostCfg)
+		return s3SchemePrefix, urlJoinPath(hostCfg.URL, strings.TrimPrefix(aliasedURL, s3SchemePrefix)), hostCfg, nil
+	}
+
+	// Paths with a leading slash are on the current alias if one is set.
+	if current := currentAlias(); current != "" && strings.HasPrefix(aliasedURL, "/") {
+		aliasedURL = current + aliasedURL
+	}
+
 	// Extract alias from the URL.
 	alias, path := url2Alias(aliasedURL)
 
@@ -308,13 +546,25 @@ func expandAlias(aliasedURL string) (alias string, urlStr string, hostCfg *hostC
 		if err != nil {
 			return "", "", nil, err.Trace(aliasedURL)
 		}
+		hostCfg = overrideEndpoint(hostCfg)
 		return alias, urlJoinPath(hostCfg.URL, path), hostCfg, nil
 	}
 
 	// Find the matching alias entry and expand the URL.
 	if hostCfg = mustGetHostConfig(alias); hostCfg != nil {
+		hostCfg = overrideEndpoint(hostCfg)
 		return alias, urlJoinPath(hostCfg.URL, path), hostCfg, nil
 	}
+
+	// URLs given instead of an alias may match a host by its endpoint.
+	if urlRgx.MatchString(aliasedURL) {
+		if hostCfg, err = getHostConfigByURL(aliasedURL); err == nil {
+			return hostCfg.URL, aliasedURL, hostCfg, nil
+		}
+		if _, ok := err.ToGoError().(envVarNotSetErr); ok {
+			return "", "", nil, err.Trace(aliasedURL)
+		}
+	}
 	return "", aliasedURL, nil, nil // No matching entry found. Return original URL as is.
 }
 
@@ -0,0 +1,92 @@
+/*
+ * MinIO Client (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"io"
+	"sync/atomic"
+
+	"github.com/minio/minio/pkg/console"
+)
+
+// attemptProgress counts the bytes an attempt to copy an object adds to
+// progress, to take them back when the attempt fails.
+type attemptProgress struct {
+	io.Reader
+	n int64
+}
+
+func (p *attemptProgress) Read(b []byte) (int, error) {
+	n, e := p.Reader.Read(b)
+	atomic.AddInt64(&p.n, int64(n))
+	return n, e
+}
+
+// spin - animates the progress wrapped, if it is able to.
+func (p *attemptProgress) spin() func() {
+	if spinner, ok := p.Reader.(progressSpinner); ok {
+		return spinner.spin()
+	}
+	return func() {}
+}
+
+// rewind - takes the bytes of the attempt back from the progress.
+func (p *attemptProgress) rewind() {
+	n := -atomic.SwapInt64(&p.n, 0)
+	switch progress := p.Reader.(type) {
+	case *progressBar:
+		progress.ProgressBar.Add64(n)
+	case *accounter:
+		progress.Add(n)
+	case Status:
+		progress.Add(n)
+	}
+}
+
+// uploadSourceToTargetURLAttempts - copies like uploadSourceToTargetURL,
+// copying the whole object again when a copy fails, up to --attempts
+// copies in all. Unlike the retries of requests, which cannot replay a
+// body already consumed, this recovers from connections reset in the
+// middle of objects. Copies failing with errors which another copy
+// would fail with again, such as missing sources, are not retried.
+func uploadSourceToTargetURLAttempts(ctx context.Context, urls URLs, progress io.Reader, encKeyDB map[string][]prefixSSEPair) URLs {
+	if globalCopyAttempts <= 1 {
+		return uploadSourceToTargetURL(ctx, urls, progress, encKeyDB)
+	}
+	var attempt *attemptProgress
+	if progress != nil {
+		attempt = &attemptProgress{Reader: progress}
+		progress = attempt
+	}
+	for i := 1; ; i++ {
+		result := uploadSourceToTargetURL(ctx, urls, progress, encKeyDB)
+		if result.Error == nil || i == globalCopyAttempts || isErrIgnored(result.Error) || ctx.Err() != nil {
+			return result
+		}
+		if attempt != nil {
+			attempt.rewind()
+		}
+		// Print in new line and adjust to top so that we don't print over
+		// the ongoing progress bar.
+		if !globalQuiet && !globalJSON {
+			console.Eraseline()
+		}
+		errorIf(result.Error.Trace(urls.SourceContent.URL.String()),
+			"Attempt %d of %d to copy `%s` failed, copying it again.", i, globalCopyAttempts, urls.SourceContent.URL.String())
+	}
+}
@@ -0,0 +1,130 @@
+/*
+ * MinIO Client (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+// Requests of a download, objects are requested once for their headers
+// before their content is read.
+const downloadRequests = 2
+
+// resetObjectHandler resets the connections of the first resets downloads
+// of objects in the middle of their content.
+type resetObjectHandler struct {
+	handler *fakeS3Server
+
+	mutex    *sync.Mutex
+	resets   int
+	requests int
+}
+
+func (h *resetObjectHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet || r.URL.Path == "/bucket/" {
+		h.handler.ServeHTTP(w, r)
+		return
+	}
+	h.mutex.Lock()
+	h.requests++
+	reset := h.requests <= h.resets*downloadRequests
+	h.mutex.Unlock()
+	if !reset {
+		h.handler.ServeHTTP(w, r)
+		return
+	}
+	recorder := httptest.NewRecorder()
+	h.handler.ServeHTTP(recorder, r)
+	for name, values := range recorder.Header() {
+		w.Header()[name] = values
+	}
+	w.WriteHeader(recorder.Code)
+	w.Write(recorder.Body.Bytes()[:recorder.Body.Len()/2])
+	w.(http.Flusher).Flush()
+	conn, _, _ := w.(http.Hijacker).Hijack()
+	conn.Close()
+}
+
+func TestCopyAttempts(t *testing.T) {
+	data := bytes.Repeat([]byte("0123456789"), 10000)
+	handler := &resetObjectHandler{handler: newFakeS3Server(map[string][]byte{"bucket/object": data}), mutex: &sync.Mutex{}}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+	defer withTestConfig(map[string]hostConfigV9{"myminio": testHost(server.URL)})()
+	defer func(attempts int) { globalCopyAttempts = attempts }(globalCopyAttempts)
+	// Sniffing the content type seeks back, requesting the object again.
+	defer func(noSniff bool) { globalNoSniff = noSniff }(globalNoSniff)
+	globalNoSniff = true
+
+	dir, e := ioutil.TempDir("", "mc-attempts-")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.RemoveAll(dir)
+
+	testCases := []struct {
+		attempts  int
+		resets    int
+		downloads int
+		success   bool
+	}{
+		{1, 0, 1, true},
+		// Without attempts the reset fails the copy.
+		{1, 1, 1, false},
+		// The object is downloaded again after the reset.
+		{2, 1, 2, true},
+		{3, 3, 3, false},
+	}
+	for i, testCase := range testCases {
+		globalCopyAttempts = testCase.attempts
+		handler.resets, handler.requests = testCase.resets, 0
+		target := filepath.Join(dir, strconv.Itoa(i))
+
+		progress := newAccounter(int64(len(data)))
+		urls := URLs{
+			SourceAlias:   "myminio",
+			SourceContent: &clientContent{URL: *newClientURL(server.URL + "/bucket/object"), Size: int64(len(data))},
+			TargetContent: &clientContent{URL: *newClientURL(target)},
+		}
+		urls = uploadSourceToTargetURLAttempts(context.Background(), urls, progress, nil)
+		if testCase.success != (urls.Error == nil) {
+			t.Fatalf("Test %d: expected success %t, got %v", i+1, testCase.success, urls.Error)
+		}
+		if downloads := handler.requests / downloadRequests; downloads != testCase.downloads {
+			t.Fatalf("Test %d: expected %d downloads, got %d", i+1, testCase.downloads, downloads)
+		}
+		if !testCase.success {
+			continue
+		}
+		if got, e := ioutil.ReadFile(target); e != nil || !bytes.Equal(got, data) {
+			t.Fatalf("Test %d: expected the object to be downloaded whole, got %d bytes, %v", i+1, len(got), e)
+		}
+		// Bytes of failed attempts are not counted.
+		if progress.Get() != int64(len(data)) {
+			t.Fatalf("Test %d: expected a progress of %d, got %d", i+1, len(data), progress.Get())
+		}
+	}
+}
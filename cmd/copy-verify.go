@@ -0,0 +1,106 @@
+/*
+ * MinIO Client (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package cmd
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"hash"
+	"io"
+	"os"
+
+	"github.com/minio/mc/pkg/probe"
+)
+
+// verifyReader - computes the MD5 of a source while it is copied to a
+// local target with --verify, the file written is read back and
+// compared with it once the copy is done.
+type verifyReader struct {
+	io.ReadCloser
+	hash   hash.Hash
+	offset int64
+}
+
+func (v *verifyReader) Read(p []byte) (int, error) {
+	n, e := v.ReadCloser.Read(p)
+	v.hash.Write(p[:n])
+	v.offset += int64(n)
+	return n, e
+}
+
+// sum - the MD5 of the data read so far, hex encoded.
+func (v *verifyReader) sum() string {
+	return hex.EncodeToString(v.hash.Sum(nil))
+}
+
+// verifyReadSeeker - a verifyReader of a seekable source. Copies to
+// local targets resume from a partial file by seeking past the data
+// already written, it is read instead so that the MD5 covers the whole
+// file.
+type verifyReadSeeker struct {
+	*verifyReader
+}
+
+func (v verifyReadSeeker) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekCurrent:
+		offset += v.offset
+	case io.SeekEnd:
+		return v.offset, os.ErrInvalid
+	}
+	if offset < v.offset {
+		if _, e := v.ReadCloser.(io.Seeker).Seek(0, io.SeekStart); e != nil {
+			return v.offset, e
+		}
+		v.hash.Reset()
+		v.offset = 0
+	}
+	if _, e := io.CopyN(v.hash, v.ReadCloser, offset-v.offset); e != nil {
+		return v.offset, e
+	}
+	v.offset = offset
+	return offset, nil
+}
+
+// newVerifyReader - returns reader computing the MD5 of the data read,
+// seekable if reader is.
+func newVerifyReader(reader io.ReadCloser) (io.ReadCloser, *verifyReader) {
+	v := &verifyReader{ReadCloser: reader, hash: md5.New()}
+	if _, ok := reader.(io.Seeker); ok {
+		return verifyReadSeeker{v}, v
+	}
+	return v, v
+}
+
+// verifyLocalCopy - reads back the file written by a copy, it is removed
+// unless its MD5 is the expected one.
+func verifyLocalCopy(path, expected string) *probe.Error {
+	file, e := os.Open(path)
+	if e != nil {
+		return probe.NewError(e)
+	}
+	hash := md5.New()
+	_, e = io.Copy(hash, file)
+	file.Close()
+	if e != nil {
+		return probe.NewError(e)
+	}
+	if got := hex.EncodeToString(hash.Sum(nil)); got != expected {
+		os.Remove(path)
+		return probe.NewError(ChecksumMismatch{Checksum: "MD5", Expected: expected, Got: got, Local: true})
+	}
+	return nil
+}
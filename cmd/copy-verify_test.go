@@ -0,0 +1,113 @@
+/*
+ * MinIO Client (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+)
+
+func TestCopyVerifyLocal(t *testing.T) {
+	defer withTestConfig(nil)()
+	defer func(verify bool) { globalVerify = verify }(globalVerify)
+	globalVerify = true
+
+	dir, e := ioutil.TempDir("", "mc-verify-")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.RemoveAll(dir)
+
+	data := bytes.Repeat([]byte("0123456789abcdef"), 64*1024)
+	source := filepath.Join(dir, "source")
+	if e = ioutil.WriteFile(source, data, 0600); e != nil {
+		t.Fatal(e)
+	}
+	corrupted := append([]byte{}, data[:len(data)/2]...)
+	corrupted[100] ^= 0xff
+
+	// Copies resuming from a corrupted partial file write a corrupted
+	// destination, it must be detected and removed.
+	testCases := []struct {
+		partial []byte
+		success bool
+	}{
+		{nil, true},
+		{data[:len(data)/2], true},
+		{corrupted, false},
+	}
+	for i, testCase := range testCases {
+		target := filepath.Join(dir, "target"+strconv.Itoa(i))
+		if testCase.partial != nil {
+			if e = ioutil.WriteFile(target+partSuffix, testCase.partial, 0600); e != nil {
+				t.Fatal(e)
+			}
+		}
+		urls := URLs{
+			SourceContent: &clientContent{URL: *newClientURL(source), Size: int64(len(data))},
+			TargetContent: &clientContent{URL: *newClientURL(target)},
+		}
+		urls = uploadSourceToTargetURL(context.Background(), urls, newAccounter(int64(len(data))), nil)
+		if testCase.success {
+			if urls.Error != nil {
+				t.Fatalf("Test %d: %v", i+1, urls.Error)
+			}
+			if got, e := ioutil.ReadFile(target); e != nil || !bytes.Equal(got, data) {
+				t.Fatalf("Test %d: expected the file to be copied whole, got %d bytes, %v", i+1, len(got), e)
+			}
+			continue
+		}
+		if _, ok := urls.Error.ToGoError().(ChecksumMismatch); !ok {
+			t.Fatalf("Test %d: expected a checksum mismatch, got %v", i+1, urls.Error)
+		}
+		if _, e = os.Stat(target); !os.IsNotExist(e) {
+			t.Fatalf("Test %d: expected the corrupted destination to be removed, got %v", i+1, e)
+		}
+	}
+}
+
+func TestVerifyLocalCopy(t *testing.T) {
+	file, e := ioutil.TempFile("", "mc-verify-")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.Remove(file.Name())
+	file.WriteString("hello world")
+	file.Close()
+
+	sum := md5.Sum([]byte("hello world"))
+	expected := hex.EncodeToString(sum[:])
+	if err := verifyLocalCopy(file.Name(), expected); err != nil {
+		t.Fatal(err)
+	}
+	if e = ioutil.WriteFile(file.Name(), []byte("hello w0rld"), 0600); e != nil {
+		t.Fatal(e)
+	}
+	err := verifyLocalCopy(file.Name(), expected)
+	if _, ok := err.ToGoError().(ChecksumMismatch); !ok {
+		t.Fatalf("expected a checksum mismatch, got %v", err)
+	}
+	if _, e = os.Stat(file.Name()); !os.IsNotExist(e) {
+		t.Fatalf("expected the corrupted file to be removed, got %v", e)
+	}
+}
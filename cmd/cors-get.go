@@ -0,0 +1,91 @@
+/*
+ * MinIO Client (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"github.com/minio/cli"
+	json "github.com/minio/mc/pkg/colorjson"
+	"github.com/minio/mc/pkg/probe"
+)
+
+var (
+	corsGetFlags = []cli.Flag{}
+)
+
+var corsGetCmd = cli.Command{
+	Name:   "get",
+	Usage:  "show the CORS rules of a bucket",
+	Action: mainCORSGet,
+	Before: setGlobalsFromContext,
+	Flags:  append(corsGetFlags, globalFlags...),
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} TARGET [FLAGS]
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+EXAMPLES:
+  1. Show the CORS rules of a bucket.
+    {{.Prompt}} {{.HelpName}} s3/mybucket
+
+  2. Save the CORS rules of a bucket to a file, to be edited and set again.
+    {{.Prompt}} {{.HelpName}} s3/mybucket > cors.json
+`,
+}
+
+// checkCORSGetSyntax - validate all the passed arguments
+func checkCORSGetSyntax(ctx *cli.Context) {
+	if len(ctx.Args()) != 1 {
+		cli.ShowCommandHelpAndExit(ctx, "get", 1) // last argument is exit code
+	}
+}
+
+// corsGetMessage container
+type corsGetMessage struct {
+	Status string             `json:"status"`
+	URL    string             `json:"url"`
+	CORS   *corsConfiguration `json:"cors"`
+}
+
+// String the rules in the JSON format accepted by "mc cors set".
+func (c corsGetMessage) String() string {
+	data, err := c.CORS.toJSON()
+	fatalIf(err, "Unable to marshal into JSON.")
+	return string(data)
+}
+
+// JSON jsonified CORS rules message.
+func (c corsGetMessage) JSON() string {
+	c.Status = "success"
+	corsJSONBytes, e := json.MarshalIndent(c, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(corsJSONBytes)
+}
+
+func mainCORSGet(ctx *cli.Context) error {
+	checkCORSGetSyntax(ctx)
+
+	targetURL := ctx.Args().Get(0)
+	cfg, err := newCORSClient(targetURL).GetBucketCORS()
+	fatalIf(err.Trace(targetURL), "Unable to get the CORS rules of `"+targetURL+"`.")
+
+	printMsg(corsGetMessage{URL: targetURL, CORS: cfg})
+	return nil
+}
@@ -0,0 +1,57 @@
+/*
+ * MinIO Client (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import "github.com/minio/cli"
+
+var (
+	corsFlags = []cli.Flag{}
+)
+
+var corsCmd = cli.Command{
+	Name:            "cors",
+	Usage:           "manage the cross-origin access to buckets",
+	HideHelpCommand: true,
+	Action:          mainCORS,
+	Before:          setGlobalsFromContext,
+	Flags:           append(corsFlags, globalFlags...),
+	Subcommands: []cli.Command{
+		corsGetCmd,
+		corsSetCmd,
+		corsRemoveCmd,
+	},
+}
+
+// mainCORS is the handle for "mc cors" command.
+func mainCORS(ctx *cli.Context) error {
+	cli.ShowCommandHelp(ctx, ctx.Args().First())
+	return nil
+	// Sub-commands like "get", "set", "remove" have their own main.
+}
+
+// newCORSClient - the S3 client of the bucket whose CORS configuration
+// is managed.
+func newCORSClient(urlStr string) *s3Client {
+	client, err := newClient(urlStr)
+	fatalIf(err.Trace(urlStr), "Cannot parse the provided url.")
+
+	s3Client, ok := client.(*s3Client)
+	if !ok {
+		fatalIf(errDummy().Trace(urlStr), "The provided url doesn't point to a S3 server.")
+	}
+	return s3Client
+}
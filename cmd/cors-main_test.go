@@ -0,0 +1,181 @@
+/*
+ * MinIO Client (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package cmd
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+const corsRulesJSON = `{
+  "CORSRules": [
+    {
+      "AllowedHeaders": ["*"],
+      "AllowedMethods": ["get", "HEAD"],
+      "AllowedOrigins": ["https://www.example.com", "https://cdn.example.com"],
+      "ExposeHeaders": ["ETag"],
+      "ID": "site",
+      "MaxAgeSeconds": 3000
+    },
+    {
+      "AllowedMethods": ["PUT", "POST", "DELETE"],
+      "AllowedOrigins": ["*"]
+    }
+  ]
+}`
+
+func TestCORSRoundTrip(t *testing.T) {
+	cfg, err := parseCORSJSON([]byte(corsRulesJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []corsRule{
+		{
+			AllowedHeaders: []string{"*"},
+			AllowedMethods: []string{"GET", "HEAD"},
+			AllowedOrigins: []string{"https://www.example.com", "https://cdn.example.com"},
+			ExposeHeaders:  []string{"ETag"},
+			ID:             "site",
+			MaxAgeSeconds:  3000,
+		},
+		{
+			AllowedMethods: []string{"PUT", "POST", "DELETE"},
+			AllowedOrigins: []string{"*"},
+		},
+	}
+	if !reflect.DeepEqual(cfg.Rules, expected) {
+		t.Fatalf("Expected %+v, got %+v", expected, cfg.Rules)
+	}
+
+	xmlData, err := cfg.toXML()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedXML := `<CORSConfiguration xmlns="http://s3.amazonaws.com/doc/2006-03-01/">` +
+		`<CORSRule><AllowedHeader>*</AllowedHeader><AllowedMethod>GET</AllowedMethod><AllowedMethod>HEAD</AllowedMethod>` +
+		`<AllowedOrigin>https://www.example.com</AllowedOrigin><AllowedOrigin>https://cdn.example.com</AllowedOrigin>` +
+		`<ExposeHeader>ETag</ExposeHeader><ID>site</ID><MaxAgeSeconds>3000</MaxAgeSeconds></CORSRule>` +
+		`<CORSRule><AllowedMethod>PUT</AllowedMethod><AllowedMethod>POST</AllowedMethod><AllowedMethod>DELETE</AllowedMethod>` +
+		`<AllowedOrigin>*</AllowedOrigin></CORSRule></CORSConfiguration>`
+	if string(xmlData) != expectedXML {
+		t.Fatalf("Expected %s, got %s", expectedXML, xmlData)
+	}
+
+	// The XML returned by the server reads back the same rules, and so
+	// does the JSON rendered from them.
+	server := httptest.NewServer(&corsHandler{})
+	defer server.Close()
+	clnt := newCORSTestClient(t, server.URL+"/bucket")
+	if err = clnt.SetBucketCORS(cfg); err != nil {
+		t.Fatal(err)
+	}
+	got, err := clnt.GetBucketCORS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got.Rules, expected) {
+		t.Fatalf("Expected %+v, got %+v", expected, got.Rules)
+	}
+	jsonData, err := got.toJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg, err = parseCORSJSON(jsonData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(cfg.Rules, expected) {
+		t.Fatalf("Expected %+v, got %+v", expected, cfg.Rules)
+	}
+
+	if err = clnt.RemoveBucketCORS(); err != nil {
+		t.Fatal(err)
+	}
+	_, err = clnt.GetBucketCORS()
+	if _, ok := err.ToGoError().(BucketCORSNotFound); !ok {
+		t.Fatalf("Expected a missing CORS configuration error, got %v", err)
+	}
+}
+
+func TestCORSValidate(t *testing.T) {
+	testCases := []string{
+		`{"CORSRules": []}`,
+		`{"CORSRules": [{"AllowedMethods": ["GET"]}]}`,
+		`{"CORSRules": [{"AllowedMethods": ["GET"], "AllowedOrigins": [""]}]}`,
+		`{"CORSRules": [{"AllowedOrigins": ["*"]}]}`,
+		`{"CORSRules": [{"AllowedMethods": ["PATCH"], "AllowedOrigins": ["*"]}]}`,
+		`{"CORSRules": [{"AllowedMethods": ["GET"], "AllowedOrigins": ["*"], "MaxAgeSeconds": -1}]}`,
+		`{"CORSRules": [{"AllowedMethod": ["GET"], "AllowedOrigins": ["*"]}]}`,
+		`<CORSConfiguration/>`,
+	}
+	for i, testCase := range testCases {
+		if _, err := parseCORSJSON([]byte(testCase)); err == nil {
+			t.Fatalf("Test %d: Expected %s to be refused", i+1, testCase)
+		}
+	}
+}
+
+// corsHandler stores the CORS configuration of "bucket".
+type corsHandler struct {
+	mutex sync.Mutex
+	cors  []byte
+}
+
+func (h *corsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	query := r.URL.Query()
+	if _, ok := query["location"]; ok {
+		w.Write([]byte(`<LocationConstraint xmlns="http://s3.amazonaws.com/doc/2006-03-01/"></LocationConstraint>`))
+		return
+	}
+	if _, ok := query["cors"]; !ok || r.URL.Path != "/bucket/" && r.URL.Path != "/bucket" {
+		w.WriteHeader(http.StatusNotImplemented)
+		w.Write([]byte("<Error><Code>NotImplemented</Code><Message>Not implemented.</Message></Error>"))
+		return
+	}
+	switch r.Method {
+	case http.MethodPut:
+		if r.Header.Get("Content-Md5") == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		h.cors, _ = ioutil.ReadAll(r.Body)
+	case http.MethodGet:
+		if h.cors == nil {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte("<Error><Code>NoSuchCORSConfiguration</Code><Message>The CORS configuration does not exist</Message></Error>"))
+			return
+		}
+		w.Write(h.cors)
+	case http.MethodDelete:
+		h.cors = nil
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func newCORSTestClient(t *testing.T, urlStr string) *s3Client {
+	conf := newTestS3Config(urlStr)
+	clnt, err := s3New(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return clnt.(*s3Client)
+}
@@ -0,0 +1,89 @@
+/*
+ * MinIO Client (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	json "github.com/minio/mc/pkg/colorjson"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/minio/pkg/console"
+)
+
+var (
+	corsRemoveFlags = []cli.Flag{}
+)
+
+var corsRemoveCmd = cli.Command{
+	Name:   "remove",
+	Usage:  "remove the CORS rules of a bucket",
+	Action: mainCORSRemove,
+	Before: setGlobalsFromContext,
+	Flags:  append(corsRemoveFlags, globalFlags...),
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} TARGET [FLAGS]
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+EXAMPLES:
+  1. Remove the CORS rules of a bucket, cross-origin requests are refused from then on.
+    {{.Prompt}} {{.HelpName}} s3/mybucket
+`,
+}
+
+// checkCORSRemoveSyntax - validate all the passed arguments
+func checkCORSRemoveSyntax(ctx *cli.Context) {
+	if len(ctx.Args()) != 1 {
+		cli.ShowCommandHelpAndExit(ctx, "remove", 1) // last argument is exit code
+	}
+}
+
+// corsRemoveMessage container
+type corsRemoveMessage struct {
+	Status string `json:"status"`
+	URL    string `json:"url"`
+}
+
+// String colorized CORS remove message.
+func (c corsRemoveMessage) String() string {
+	return console.Colorize("CORS", "CORS rules removed from `"+c.URL+"`.")
+}
+
+// JSON jsonified CORS remove message.
+func (c corsRemoveMessage) JSON() string {
+	c.Status = "success"
+	corsJSONBytes, e := json.MarshalIndent(c, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(corsJSONBytes)
+}
+
+func mainCORSRemove(ctx *cli.Context) error {
+	checkCORSRemoveSyntax(ctx)
+
+	console.SetColor("CORS", color.New(color.FgGreen, color.Bold))
+
+	targetURL := ctx.Args().Get(0)
+	err := newCORSClient(targetURL).RemoveBucketCORS()
+	fatalIf(err.Trace(targetURL), "Unable to remove the CORS rules of `"+targetURL+"`.")
+
+	printMsg(corsRemoveMessage{URL: targetURL})
+	return nil
+}
@@ -0,0 +1,114 @@
+/*
+ * MinIO Client (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"io/ioutil"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	json "github.com/minio/mc/pkg/colorjson"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/minio/pkg/console"
+)
+
+var (
+	corsSetFlags = []cli.Flag{}
+)
+
+var corsSetCmd = cli.Command{
+	Name:   "set",
+	Usage:  "set the CORS rules of a bucket from a JSON file",
+	Action: mainCORSSet,
+	Before: setGlobalsFromContext,
+	Flags:  append(corsSetFlags, globalFlags...),
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} TARGET FILE [FLAGS]
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+FILE:
+  The rules use the JSON format of the AWS CLI, they replace the current rules of the bucket.
+  AllowedOrigins and AllowedMethods are required, the methods are GET, PUT, POST, DELETE and HEAD.
+
+  {
+    "CORSRules": [
+      {
+        "AllowedOrigins": ["https://www.example.com"],
+        "AllowedMethods": ["GET", "HEAD"],
+        "AllowedHeaders": ["*"],
+        "ExposeHeaders": ["ETag"],
+        "MaxAgeSeconds": 3000
+      }
+    ]
+  }
+
+EXAMPLES:
+  1. Allow the pages of a web site to download the objects of a bucket.
+    {{.Prompt}} {{.HelpName}} s3/mybucket cors.json
+`,
+}
+
+// checkCORSSetSyntax - validate all the passed arguments
+func checkCORSSetSyntax(ctx *cli.Context) {
+	if len(ctx.Args()) != 2 {
+		cli.ShowCommandHelpAndExit(ctx, "set", 1) // last argument is exit code
+	}
+}
+
+// corsSetMessage container
+type corsSetMessage struct {
+	Status string `json:"status"`
+	URL    string `json:"url"`
+	Rules  int    `json:"rules"`
+}
+
+// String colorized CORS set message.
+func (c corsSetMessage) String() string {
+	return console.Colorize("CORS", "CORS rules set on `"+c.URL+"`.")
+}
+
+// JSON jsonified CORS set message.
+func (c corsSetMessage) JSON() string {
+	c.Status = "success"
+	corsJSONBytes, e := json.MarshalIndent(c, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(corsJSONBytes)
+}
+
+func mainCORSSet(ctx *cli.Context) error {
+	checkCORSSetSyntax(ctx)
+
+	console.SetColor("CORS", color.New(color.FgGreen, color.Bold))
+
+	targetURL := ctx.Args().Get(0)
+	rulesFile := ctx.Args().Get(1)
+	data, e := ioutil.ReadFile(rulesFile)
+	fatalIf(probe.NewError(e).Trace(rulesFile), "Unable to read the CORS rules file.")
+	cfg, err := parseCORSJSON(data)
+	fatalIf(err.Trace(rulesFile), "Invalid CORS rules in `"+rulesFile+"`.")
+
+	err = newCORSClient(targetURL).SetBucketCORS(cfg)
+	fatalIf(err.Trace(targetURL), "Unable to set the CORS rules of `"+targetURL+"`.")
+
+	printMsg(corsSetMessage{URL: targetURL, Rules: len(cfg.Rules)})
+	return nil
+}
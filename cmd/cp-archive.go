@@ -0,0 +1,155 @@
+/*
+ * MinIO Client (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"archive/tar"
+	"context"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/minio-go/v6/pkg/encrypt"
+)
+
+// checkCopyArchiveSyntax - verifies the arguments of cp --archive and
+// cp --extract, which copy a single source to a single target.
+func checkCopyArchiveSyntax(ctx *cli.Context) {
+	if len(ctx.Args()) != 2 {
+		fatalIf(errInvalidArgument().Trace(ctx.Args()...), "--archive and --extract copy exactly one source to one target.")
+	}
+	if ctx.Bool("archive") && ctx.Bool("extract") {
+		fatalIf(errInvalidArgument(), "--archive cannot be used with --extract.")
+	}
+	if ctx.Bool("continue") {
+		fatalIf(errInvalidArgument(), "--archive and --extract cannot be used with --continue.")
+	}
+}
+
+// archiveCopy - streams the objects under sourceURL into a tar archive
+// uploaded to targetURL, with entries named by the keys of the objects
+// relative to the prefix. Nothing is staged on disk, objects are read
+// one after another while the archive is uploaded.
+func archiveCopy(sourceURL, targetURL string, encKeyDB map[string][]prefixSSEPair) *probe.Error {
+	srcAlias, _, _, err := expandAlias(sourceURL)
+	if err != nil {
+		return err.Trace(sourceURL)
+	}
+	srcClnt, err := newClient(sourceURL)
+	if err != nil {
+		return err.Trace(sourceURL)
+	}
+	tgtAlias, _ := url2Alias(targetURL)
+
+	pr, pw := io.Pipe()
+	writeErrCh := make(chan *probe.Error, 1)
+	go func() {
+		err := writeArchive(srcClnt, srcAlias, getSSE(sourceURL, encKeyDB[srcAlias]), targetURL, pw)
+		if err != nil {
+			pw.CloseWithError(err.ToGoError())
+		} else {
+			pw.Close()
+		}
+		writeErrCh <- err
+	}()
+	_, err = putTargetStreamWithURL(targetURL, pr, -1, nil, getSSE(targetURL, encKeyDB[tgtAlias]))
+	// Unblocks the archive writer if the upload failed.
+	pr.Close()
+	if writeErr := <-writeErrCh; writeErr != nil {
+		return writeErr.Trace(sourceURL)
+	}
+	if err != nil {
+		return err.Trace(targetURL)
+	}
+	return nil
+}
+
+// writeArchive - writes the objects listed by clnt to w as a tar archive.
+func writeArchive(clnt Client, alias string, sse encrypt.ServerSide, targetURL string, w io.Writer) *probe.Error {
+	tw := tar.NewWriter(w)
+	trimPrefix := trimListPrefix(clnt)
+	for content := range clnt.List(true, false, false, DirNone) {
+		if content.Err != nil {
+			return content.Err.Trace(clnt.GetURL().String())
+		}
+		if content.Type.IsDir() {
+			continue
+		}
+		urlStr := content.URL.String()
+		trimPrefix(content)
+		name := strings.TrimPrefix(content.URL.Path, "/")
+		reader, _, err := getSourceStream(context.Background(), alias, urlStr, false, sse, 0, 0)
+		if err != nil {
+			return err.Trace(urlStr)
+		}
+		e := tw.WriteHeader(&tar.Header{
+			Typeflag: tar.TypeReg,
+			Name:     name,
+			Size:     content.Size,
+			Mode:     0644,
+			ModTime:  content.Time,
+		})
+		if e == nil {
+			_, e = io.Copy(tw, reader)
+		}
+		reader.Close()
+		if e != nil {
+			return probe.NewError(e).Trace(urlStr)
+		}
+		printMsg(copyMessage{Source: urlStr, Target: targetURL + ":" + name, Size: content.Size})
+	}
+	return probe.NewError(tw.Close())
+}
+
+// extractCopy - uploads each file of the tar archive sourceURL as an
+// object under targetURL, named by its path in the archive. Folders and
+// other entries which are not files are skipped.
+func extractCopy(sourceURL, targetURL string, encKeyDB map[string][]prefixSSEPair) *probe.Error {
+	reader, err := getSourceStreamFromURL(sourceURL, encKeyDB)
+	if err != nil {
+		return err.Trace(sourceURL)
+	}
+	defer reader.Close()
+	tgtAlias, _ := url2Alias(targetURL)
+	if !strings.HasSuffix(targetURL, "/") {
+		targetURL += "/"
+	}
+
+	tr := tar.NewReader(reader)
+	for {
+		header, e := tr.Next()
+		if e == io.EOF {
+			return nil
+		}
+		if e != nil {
+			return probe.NewError(e).Trace(sourceURL)
+		}
+		if header.Typeflag != tar.TypeReg && header.Typeflag != tar.TypeRegA {
+			continue
+		}
+		// Cleaned as an absolute path, names with `..` segments stay
+		// under the target.
+		name := strings.TrimPrefix(path.Clean("/"+header.Name), "/")
+		objectURL := targetURL + name
+		if _, err = putTargetStreamWithURL(objectURL, tr, header.Size, nil, getSSE(objectURL, encKeyDB[tgtAlias])); err != nil {
+			return err.Trace(sourceURL, objectURL)
+		}
+		printMsg(copyMessage{Source: sourceURL + ":" + header.Name, Target: objectURL, Size: header.Size})
+	}
+}
@@ -0,0 +1,107 @@
+/*
+ * MinIO Client (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"archive/tar"
+	"bytes"
+	"io/ioutil"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/fatih/color"
+)
+
+func TestArchiveCopy(t *testing.T) {
+	handler := newFakeS3Server(map[string][]byte{
+		"bucket/photos/a.jpg":      []byte("jpeg a"),
+		"bucket/photos/2020/b.jpg": []byte("jpeg b"),
+		"bucket/photos/2020/c.txt": []byte(""),
+		"bucket/other/d.jpg":       []byte("not archived"),
+	})
+	server := httptest.NewServer(handler)
+	defer server.Close()
+	defer withTestConfig(map[string]hostConfigV9{"myminio": testHost(server.URL)})()
+	savedOutput := color.Output
+	defer func() { color.Output = savedOutput }()
+	color.Output = ioutil.Discard
+
+	dir, e := ioutil.TempDir("", "mc-archive-")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.RemoveAll(dir)
+	archive := filepath.Join(dir, "photos.tar")
+
+	if err := archiveCopy("myminio/bucket/photos/", archive, nil); err != nil {
+		t.Fatal(err)
+	}
+	f, e := os.Open(archive)
+	if e != nil {
+		t.Fatal(e)
+	}
+	entries := map[string]string{}
+	tr := tar.NewReader(f)
+	for header, e := tr.Next(); e == nil; header, e = tr.Next() {
+		data, _ := ioutil.ReadAll(tr)
+		entries[header.Name] = string(data)
+	}
+	f.Close()
+	expected := map[string]string{"a.jpg": "jpeg a", "2020/b.jpg": "jpeg b", "2020/c.txt": ""}
+	if len(entries) != len(expected) {
+		t.Fatalf("Expected the entries %v, got %v", expected, entries)
+	}
+	for name, data := range expected {
+		if entries[name] != data {
+			t.Fatalf("Expected entry %s to hold %q, got %q", name, data, entries[name])
+		}
+	}
+
+	// Extracted back under another prefix.
+	if err := extractCopy(archive, "myminio/bucket/restored", nil); err != nil {
+		t.Fatal(err)
+	}
+	for name, data := range expected {
+		if got, ok := handler.objects["bucket/restored/"+name]; !ok || string(got) != data {
+			t.Fatalf("Expected object restored/%s to hold %q, got %q", name, data, got)
+		}
+	}
+
+	// Entries escaping the target folder are kept inside it.
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	tw.WriteHeader(&tar.Header{Typeflag: tar.TypeReg, Name: "../../escaped.txt", Size: 4, Mode: 0644})
+	tw.Write([]byte("data"))
+	tw.WriteHeader(&tar.Header{Typeflag: tar.TypeDir, Name: "folder/", Mode: 0755})
+	tw.Close()
+	evil := filepath.Join(dir, "evil.tar")
+	if e = ioutil.WriteFile(evil, buf.Bytes(), 0644); e != nil {
+		t.Fatal(e)
+	}
+	target := filepath.Join(dir, "target")
+	if err := extractCopy(evil, target, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, e = os.Stat(filepath.Join(target, "escaped.txt")); e != nil {
+		t.Fatalf("Expected the entry to be extracted inside the target: %v", e)
+	}
+	if _, e = os.Stat(filepath.Join(dir, "escaped.txt")); !os.IsNotExist(e) {
+		t.Fatal("Expected the entry not to be extracted outside of the target")
+	}
+}
@@ -0,0 +1,135 @@
+/*
+ * MinIO Client (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package cmd
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/minio/mc/pkg/probe"
+)
+
+// downloadDedup hardlinks the objects downloaded by cp --dedup to the
+// file of the first object downloaded with the same ETag, instead of
+// downloading the same content again. Files are copied where they
+// cannot be linked, e.g. across filesystems.
+type downloadDedup struct {
+	mutex *sync.Mutex
+	// Local path of the first download of each ETag.
+	paths map[string]string
+}
+
+// newDownloadDedup - returns the deduplication of downloads, nil if not
+// enabled.
+func newDownloadDedup(enabled bool) *downloadDedup {
+	if !enabled {
+		return nil
+	}
+	return &downloadDedup{mutex: &sync.Mutex{}, paths: make(map[string]string)}
+}
+
+// dedupETag - the ETag identifying the content of cpURLs, empty unless
+// it is a whole object downloaded to a local file.
+func dedupETag(cpURLs URLs) string {
+	if cpURLs.Error != nil || cpURLs.SourceContent.Type.IsDir() || isByteRange(cpURLs.sourceOffset, cpURLs.sourceLength) {
+		return ""
+	}
+	if cpURLs.SourceContent.URL.Type != objectStorage || cpURLs.TargetContent.URL.Type != fileSystem {
+		return ""
+	}
+	return strings.Trim(cpURLs.SourceContent.ETag, "\"")
+}
+
+// copy - copies cpURLs as doCopy does, unless an object with the same
+// ETag was downloaded already, its file is linked then.
+func (d *downloadDedup) copy(ctx context.Context, cpURLs URLs, pg ProgressReader, encKeyDB map[string][]prefixSSEPair) URLs {
+	etag := ""
+	if d != nil {
+		etag = dedupETag(cpURLs)
+	}
+	if etag == "" {
+		return doCopy(ctx, cpURLs, pg, encKeyDB)
+	}
+	targetPath := cpURLs.TargetContent.URL.Path
+
+	d.mutex.Lock()
+	firstPath, ok := d.paths[etag]
+	d.mutex.Unlock()
+	// The first download is linked as long as it is there.
+	if ok && firstPath != targetPath {
+		if st, e := os.Stat(firstPath); e == nil && st.Mode().IsRegular() {
+			printCopyMessage(cpURLs, pg)
+			if err := linkDownload(firstPath, targetPath); err != nil {
+				return cpURLs.WithError(err.Trace(firstPath, targetPath))
+			}
+			switch p := pg.(type) {
+			case *progressBar:
+				p.ProgressBar.Add64(cpURLs.SourceContent.Size)
+			case *accounter:
+				p.Add(cpURLs.SourceContent.Size)
+			}
+			return cpURLs.WithError(nil)
+		}
+	}
+
+	cpURLs = doCopy(ctx, cpURLs, pg, encKeyDB)
+	if cpURLs.Error == nil {
+		d.mutex.Lock()
+		if _, ok = d.paths[etag]; !ok {
+			d.paths[etag] = targetPath
+		}
+		d.mutex.Unlock()
+	}
+	return cpURLs
+}
+
+// linkDownload - makes targetPath a hardlink to firstPath, or a copy
+// of it if it cannot be linked. An existing target is replaced.
+func linkDownload(firstPath, targetPath string) *probe.Error {
+	if e := os.MkdirAll(filepath.Dir(targetPath), 0777); e != nil {
+		return probe.NewError(e)
+	}
+	if e := os.Remove(targetPath); e != nil && !os.IsNotExist(e) {
+		return probe.NewError(e)
+	}
+	if os.Link(firstPath, targetPath) == nil {
+		return nil
+	}
+
+	src, e := os.Open(firstPath)
+	if e != nil {
+		return probe.NewError(e)
+	}
+	defer src.Close()
+	dst, e := os.OpenFile(targetPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0666)
+	if e != nil {
+		return probe.NewError(e)
+	}
+	if _, e = io.Copy(dst, src); e != nil {
+		dst.Close()
+		os.Remove(targetPath)
+		return probe.NewError(e)
+	}
+	if e = dst.Close(); e != nil {
+		os.Remove(targetPath)
+		return probe.NewError(e)
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+/*
+ * MinIO Client (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package cmd
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/fatih/color"
+)
+
+func TestCopyDedup(t *testing.T) {
+	savedQuiet, savedOutput := globalQuiet, color.Output
+	defer func() { globalQuiet, color.Output = savedQuiet, savedOutput }()
+	globalQuiet, color.Output = true, ioutil.Discard
+
+	handler := newFakeS3Server(map[string][]byte{
+		"bucket/daily/monday/report.pdf":  []byte("the same report"),
+		"bucket/daily/tuesday/report.pdf": []byte("the same report"),
+		"bucket/daily/tuesday/notes.txt":  []byte("other notes"),
+	})
+	handler.md5ETags = true
+	// Paths of the objects downloaded.
+	gets := map[string]bool{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet && r.URL.Path != "/bucket/" {
+			gets[r.URL.Path] = true
+		}
+		handler.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+	defer withTestConfig(map[string]hostConfigV9{"myminio": testHost(server.URL)})()
+
+	dir, e := ioutil.TempDir("", "mc-dedup-")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.RemoveAll(dir)
+
+	dedup := newDownloadDedup(true)
+	var copies int
+	for cpURLs := range prepareCopyURLs([]string{"myminio/bucket/daily/"}, dir, true, false, nil, "", "") {
+		if cpURLs.Error != nil {
+			t.Fatal(cpURLs.Error)
+		}
+		if cpURLs = dedup.copy(context.Background(), cpURLs, newAccounter(0), nil); cpURLs.Error != nil {
+			t.Fatal(cpURLs.Error)
+		}
+		copies++
+	}
+	if expected := map[string]bool{"/bucket/daily/monday/report.pdf": true, "/bucket/daily/tuesday/notes.txt": true}; copies != 3 || !reflect.DeepEqual(gets, expected) {
+		t.Fatalf("Expected 3 objects to be copied downloading %v, got %d downloading %v", expected, copies, gets)
+	}
+
+	stat := func(name string) os.FileInfo {
+		st, e := os.Stat(filepath.Join(dir, name))
+		if e != nil {
+			t.Fatal(e)
+		}
+		return st
+	}
+	monday, tuesday := stat("monday/report.pdf"), stat("tuesday/report.pdf")
+	if !os.SameFile(monday, tuesday) {
+		t.Fatal("Expected the report of the same ETag to be a hardlink to the first one")
+	}
+	if data, e := ioutil.ReadFile(filepath.Join(dir, "tuesday/report.pdf")); e != nil || string(data) != "the same report" {
+		t.Fatalf("Expected the content of the report, got %q, %v", data, e)
+	}
+	if os.SameFile(monday, stat("tuesday/notes.txt")) {
+		t.Fatal("Expected objects of other ETags to be downloaded")
+	}
+}
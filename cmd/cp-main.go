@@ -27,10 +27,12 @@ import (
 	"path/filepath"
 	"strings"
 	"syscall"
+	"time"
 
 	"github.com/fatih/color"
 	"github.com/minio/cli"
 	json "github.com/minio/mc/pkg/colorjson"
+	"github.com/minio/mc/pkg/ioutils"
 	"github.com/minio/mc/pkg/probe"
 	"github.com/minio/minio/pkg/console"
 )
@@ -62,6 +64,25 @@ var (
 			Name:  "attr",
 			Usage: "add custom metadata for the object",
 		},
+		cli.StringFlag{
+			Name:  "attr-from-file",
+			Usage: "add content headers and custom metadata for the object from a JSON file",
+		},
+		cacheControlFlag,
+		expiresFlag,
+		cli.StringFlag{
+			Name:  "content-type",
+			Usage: "set Content-Type for the object",
+		},
+		cli.StringFlag{
+			Name:  "metadata-directive",
+			Usage: "preserve the metadata of the source with COPY, or set only the given metadata with REPLACE",
+			Value: "COPY",
+		},
+		cli.BoolFlag{
+			Name:  "no-preserve-metadata",
+			Usage: "do not copy the content type, caching headers and user metadata of the source, like --metadata-directive REPLACE",
+		},
 		cli.BoolFlag{
 			Name:  "continue, c",
 			Usage: "create or resume copy session",
@@ -70,6 +91,71 @@ var (
 			Name:  "preserve, a",
 			Usage: "preserve filesystem attributes (mode, ownership, timestamps)",
 		},
+		cli.BoolFlag{
+			Name:  "preserve-empty-dirs",
+			Usage: "keep empty directories of recursive copies as zero-byte 'dir/' objects and recreate them on download",
+		},
+		cli.BoolFlag{
+			Name:  "flatten",
+			Usage: "download the objects of recursive copies into the target folder itself, ignoring the folders of their keys",
+		},
+		cli.StringFlag{
+			Name:  "links",
+			Usage: "handling of symbolic links in local source folders, one of [skip, follow, copy-as-file]",
+			Value: "skip",
+		},
+		cli.StringFlag{
+			Name:  "part-size",
+			Usage: "multipart upload part size, e.g. 64MiB (minimum 5MiB, raised automatically for large objects)",
+		},
+		cli.StringFlag{
+			Name:  "on-complete",
+			Usage: "run a shell command after each object copied, described by $MC_SRC, $MC_DST and $MC_SIZE",
+		},
+		cli.BoolFlag{
+			Name:  "hook-strict",
+			Usage: "fail the copy of an object if the command of --on-complete fails",
+		},
+		checksumFlag,
+		atomicFlag,
+		attemptsFlag,
+		idleTimeoutFlag,
+		maxObjectSizeFlag,
+		orderFlag,
+		manifestFlag,
+		reportFlag,
+		excludeHiddenFlag,
+		ifNotExistsFlag,
+		cli.BoolFlag{
+			Name:  "verify",
+			Usage: "verify the ETag returned by uploads against the MD5 of their data, composite ETags of multipart uploads included, and local copies reading back the files written",
+		},
+		noSniffFlag,
+		decompressFlag,
+		cli.BoolFlag{
+			Name:  "archive",
+			Usage: "stream all objects under the source prefix into a single tar archive",
+		},
+		cli.BoolFlag{
+			Name:  "extract",
+			Usage: "upload each file of the source tar archive as an object under the target",
+		},
+		cli.BoolFlag{
+			Name:  "dedup",
+			Usage: "hardlink the downloads of objects with the ETag of an object already downloaded, instead of downloading them again",
+		},
+		cli.BoolFlag{
+			Name:  "from-url",
+			Usage: "copy from the HTTP(S) URLs of files on any web server, streamed to the target",
+		},
+		cli.StringFlag{
+			Name:  "retention-mode",
+			Usage: "set object retention mode on upload, one of [GOVERNANCE, COMPLIANCE]",
+		},
+		cli.StringFlag{
+			Name:  "retention-until",
+			Usage: "set object retention until this date on upload, e.g. 2025-01-01",
+		},
 	}
 )
 
@@ -82,7 +168,7 @@ var cpCmd = cli.Command{
 	Usage:  "copy objects",
 	Action: mainCopy,
 	Before: setGlobalsFromContext,
-	Flags:  append(append(cpFlags, ioFlags...), globalFlags...),
+	Flags:  append(append(append(append(append(cpFlags, byteRangeFlags...), compressFlags...), bandwidthLimitFlags...), ioFlags...), globalFlags...),
 	CustomHelpTemplate: `NAME:
   {{.HelpName}} - {{.Usage}}
 
@@ -145,6 +231,111 @@ EXAMPLES:
 
   16. Copy a text file to an object storage with object lock mode set to 'GOVERNANCE' with retention date.
       {{.Prompt}} {{.HelpName}} --attr "x-amz-object-lock-mode=GOVERNANCE;x-amz-object-lock-retain-until-date=2020-01-11T01:57:02Z" locked.txt play/locked-bucket/
+
+  17. Copy a large file to an object storage using 64MiB parts for the multipart upload.
+      {{.Prompt}} {{.HelpName}} --part-size 64MiB backup.tar play/mybucket/
+
+  18. Copy a web page to an object storage with headers and metadata from a JSON file, e.g.
+      {"contentType": "text/html", "cacheControl": "max-age=300", "metadata": {"author": "jane"}}
+      {{.Prompt}} {{.HelpName}} --attr-from-file meta.json index.html play/website/
+
+  19. Copy a folder recursively to an object storage and set Cache-Control on all objects.
+      {{.Prompt}} {{.HelpName}} --recursive --cache-control "max-age=86400" assets/ play/website/assets/
+
+  20. Copy a local folder recursively to an object storage, including the content of symbolic links.
+      {{.Prompt}} {{.HelpName}} --recursive --links follow dir/ play/mybucket/
+
+  21. Copy a file to a locked bucket and retain it in compliance mode until the first day of 2025.
+      {{.Prompt}} {{.HelpName}} --retention-mode COMPLIANCE --retention-until 2025-01-01 report.pdf play/locked-bucket/
+
+  22. Copy 1MiB starting at offset 10MiB of a large object to a local file.
+      {{.Prompt}} {{.HelpName}} --offset 10MiB --length 1MiB play/mybucket/large.bin slice.bin
+
+  23. Copy a file to an object storage letting the server verify its CRC32C checksum.
+      {{.Prompt}} {{.HelpName}} --checksum-algorithm CRC32C backup.tar play/mybucket/
+
+  24. Copy a file such that readers of the object never see a partial upload.
+      {{.Prompt}} {{.HelpName}} --atomic backup.tar play/mybucket/
+
+  25. Claim a key unless another client created it first, exits with status 2 if the object exists.
+      {{.Prompt}} {{.HelpName}} --if-not-exists owner.txt play/mybucket/locks/job1
+
+  26. Download a prefix recursively, resuming an interrupted download without copying completed objects again.
+      Uploads are resumed alike.
+      {{.Prompt}} {{.HelpName}} --recursive --continue play/mybucket/photos/ photos/
+
+  27. Fix the content type of an object in place with a server side copy, dropping its other metadata.
+      {{.Prompt}} {{.HelpName}} --metadata-directive REPLACE --content-type "text/html" play/mybucket/index.html play/mybucket/index.html
+
+  28. Find out whether a slow copy is spent setting up connections or transferring data.
+      {{.Prompt}} {{.HelpName}} --profile s3/mybucket/backup.tar.gz /tmp/
+
+  29. Copy a local folder to Amazon S3 and back keeping its empty directories.
+      {{.Prompt}} {{.HelpName}} --recursive --preserve-empty-dirs /home/user/project s3/mybucket/
+      {{.Prompt}} {{.HelpName}} --recursive --preserve-empty-dirs s3/mybucket/project /home/user/
+
+  30. Upload a static site letting CDNs cache its assets for a week.
+      {{.Prompt}} {{.HelpName}} --recursive --cache-control "public, max-age=604800" --expires 7d site/ s3/website/
+
+  31. Copy files without extension detecting their content type from their first bytes, the default, or not.
+      {{.Prompt}} {{.HelpName}} --recursive build/bin/ s3/releases/
+      {{.Prompt}} {{.HelpName}} --recursive --no-sniff build/bin/ s3/releases/
+
+  32. Download all the photos of a bucket into a single folder, renaming photos of the same name.
+      {{.Prompt}} {{.HelpName}} --recursive --flatten s3/photos/ /home/user/photos/
+
+  33. Download a prefix recursively, making each downloaded file read-only.
+      {{.Prompt}} {{.HelpName}} --recursive --on-complete 'chmod 0444 "$MC_DST"' s3/mybucket/reports/ reports/
+
+  34. Copy objects to another cloud without their content type, caching headers and user metadata.
+      {{.Prompt}} {{.HelpName}} --recursive --no-preserve-metadata s3/mybucket/ gcs/mybucket/
+
+  35. Upload a folder without using more than 10MiB/s of the uplink, however many files are copied at once.
+      {{.Prompt}} {{.HelpName}} --recursive --limit-upload 10MiB backup/ s3/mybucket/
+
+  36. Download large videos over a flaky connection, copying each video up to 5 times before giving up.
+      {{.Prompt}} {{.HelpName}} --recursive --attempts 5 s3/videos/ videos/
+
+  37. Upload logs gzipped on the fly, served with Content-Encoding: gzip, and download them uncompressed.
+      {{.Prompt}} {{.HelpName}} --recursive --compress --compress-types "text/*" logs/ s3/logs/
+      {{.Prompt}} {{.HelpName}} --recursive --decompress s3/logs/ logs/
+
+  38. Upload a large backup verifying the multipart ETag returned by the server against the uploaded data.
+      {{.Prompt}} {{.HelpName}} --verify backup.tar s3/mybucket/
+
+  39. Bundle the many small objects of a prefix into one tar archive, and upload them back from it.
+      {{.Prompt}} {{.HelpName}} --archive s3/mybucket/thumbnails/ thumbnails.tar
+      {{.Prompt}} {{.HelpName}} --extract thumbnails.tar s3/mybucket/thumbnails/
+
+  40. Copy a bucket recursively without transferring any object larger than 1GiB.
+      {{.Prompt}} {{.HelpName}} --recursive --max-object-size 1GiB s3/mybucket/ backup/
+
+  41. Upload a folder recursively copying the largest files first. The whole listing is held in memory
+      before the first copy starts.
+      {{.Prompt}} {{.HelpName}} --recursive --order size-desc backup/ s3/mybucket/
+
+  42. Upload a release folder recursively and record the key, size and ETag of every object uploaded to
+      'release.json', written only if all the objects are uploaded.
+      {{.Prompt}} {{.HelpName}} --recursive --manifest release.json release/ s3/mybucket/release/
+
+  43. Upload a project folder recursively without its hidden files and folders, e.g. '.git' and '.DS_Store'.
+      {{.Prompt}} {{.HelpName}} --recursive --exclude-hidden project/ s3/mybucket/project/
+
+  44. Download a bucket over an unreliable link, copying again the objects whose transfer stalls for 30 seconds.
+      {{.Prompt}} {{.HelpName}} --recursive --idle-timeout 30s --attempts 3 s3/mybucket/ backup/
+
+  45. Copy a folder to an external disk, reading back every file written to verify its MD5.
+      {{.Prompt}} {{.HelpName}} --recursive --verify photos/ /mnt/disk/photos/
+
+  46. Upload an ISO image straight from a web server, without downloading it first.
+      {{.Prompt}} {{.HelpName}} --from-url https://example.com/releases/file.iso s3/mybucket/
+
+  47. Download a backup bucket holding many identical files, hardlinking the duplicates to the first one.
+      Linked files share their content, changing one changes all of them.
+      {{.Prompt}} {{.HelpName}} --recursive --dedup s3/backups/ /mnt/restore/
+
+  48. Upload a build folder in a CI job and write the totals of the upload to a JSON report for its dashboard.
+      {{.Prompt}} {{.HelpName}} --recursive --report upload-report.json build/ s3/mybucket/builds/
 `,
 }
 
@@ -186,6 +377,24 @@ type ProgressReader interface {
 	Progress
 }
 
+// printCopyMessage - shows the copy of cpURLs starting, in the caption
+// of the progress bar or as a message.
+func printCopyMessage(cpURLs URLs, pg ProgressReader) {
+	if progressReader, ok := pg.(*progressBar); ok {
+		progressReader.SetCaption(cpURLs.SourceContent.URL.String() + ": ")
+		return
+	}
+	sourcePath := filepath.ToSlash(filepath.Join(cpURLs.SourceAlias, cpURLs.SourceContent.URL.Path))
+	targetPath := filepath.ToSlash(filepath.Join(cpURLs.TargetAlias, cpURLs.TargetContent.URL.Path))
+	printMsg(copyMessage{
+		Source:     sourcePath,
+		Target:     targetPath,
+		Size:       cpURLs.SourceContent.Size,
+		TotalCount: cpURLs.TotalCount,
+		TotalSize:  cpURLs.TotalSize,
+	})
+}
+
 // doCopy - Copy a singe file from source to destination
 func doCopy(ctx context.Context, cpURLs URLs, pg ProgressReader, encKeyDB map[string][]prefixSSEPair) URLs {
 	if cpURLs.Error != nil {
@@ -193,26 +402,11 @@ func doCopy(ctx context.Context, cpURLs URLs, pg ProgressReader, encKeyDB map[st
 		return cpURLs
 	}
 
-	sourceAlias := cpURLs.SourceAlias
-	sourceURL := cpURLs.SourceContent.URL
-	targetAlias := cpURLs.TargetAlias
-	targetURL := cpURLs.TargetContent.URL
-	length := cpURLs.SourceContent.Size
-
-	if progressReader, ok := pg.(*progressBar); ok {
-		progressReader.SetCaption(cpURLs.SourceContent.URL.String() + ": ")
-	} else {
-		sourcePath := filepath.ToSlash(filepath.Join(sourceAlias, sourceURL.Path))
-		targetPath := filepath.ToSlash(filepath.Join(targetAlias, targetURL.Path))
-		printMsg(copyMessage{
-			Source:     sourcePath,
-			Target:     targetPath,
-			Size:       length,
-			TotalCount: cpURLs.TotalCount,
-			TotalSize:  cpURLs.TotalSize,
-		})
-	}
-	return uploadSourceToTargetURL(ctx, cpURLs, pg, encKeyDB)
+	printCopyMessage(cpURLs, pg)
+	if cpURLs.SourceContent.Type.IsDir() {
+		return createEmptyDir(ctx, cpURLs)
+	}
+	return uploadSourceToTargetURLAttempts(ctx, cpURLs, pg, encKeyDB)
 }
 
 // doCopyFake - Perform a fake copy to update the progress bar appropriately.
@@ -248,11 +442,16 @@ func doPrepareCopyURLs(session *sessionV8, trapCh <-chan bool, cancelCopy contex
 		scanBar = scanBarFactory()
 	}
 
-	URLsCh := prepareCopyURLs(sourceURLs, targetURL, isRecursive, encKeyDB, olderThan, newerThan)
+	preserveEmptyDirs := session.Header.CommandBoolFlags["preserve-empty-dirs"]
+	URLsCh := prepareCopyURLs(sourceURLs, targetURL, isRecursive, preserveEmptyDirs, encKeyDB, olderThan, newerThan)
+	if session.Header.CommandBoolFlags["flatten"] {
+		URLsCh = flattenCopyURLs(URLsCh, targetURL)
+	}
+	orderedURLsCh := orderCopyURLs(URLsCh, session.Header.CommandStringFlags["order"])
 	done := false
 	for !done {
 		select {
-		case cpURLs, ok := <-URLsCh:
+		case cpURLs, ok := <-orderedURLsCh:
 			if !ok { // Done with URL preparation
 				done = true
 				break
@@ -290,7 +489,7 @@ func doPrepareCopyURLs(session *sessionV8, trapCh <-chan bool, cancelCopy contex
 				console.Eraseline()
 			}
 			session.Delete() // If we are interrupted during the URL scanning, we drop the session.
-			os.Exit(0)
+			exit(0)
 		}
 	}
 
@@ -359,13 +558,22 @@ func doCopySession(cli *cli.Context, session *sessionV8, encKeyDB map[string][]p
 
 		// Access recursive flag inside the session header.
 		isRecursive := cli.Bool("recursive")
+		preserveEmptyDirs := cli.Bool("preserve-empty-dirs")
 		olderThan := cli.String("older-than")
 		newerThan := cli.String("newer-than")
 
+		// Range flags are already validated in checkCopySyntax.
+		offset, length, _ := parseByteRange(cli.String("offset"), cli.String("length"))
+
+		URLsCh := prepareCopyURLs(sourceURLs, targetURL, isRecursive, preserveEmptyDirs, encKeyDB, olderThan, newerThan)
+		if cli.Bool("flatten") {
+			URLsCh = flattenCopyURLs(URLsCh, targetURL)
+		}
+		orderedURLsCh := orderCopyURLs(URLsCh, globalCopyOrder)
+
 		go func() {
 			totalBytes := int64(0)
-			for cpURLs := range prepareCopyURLs(sourceURLs, targetURL, isRecursive,
-				encKeyDB, olderThan, newerThan) {
+			for cpURLs := range orderedURLsCh {
 				if cpURLs.Error != nil {
 					// Print in new line and adjust to top so that we
 					// don't print over the ongoing scan bar
@@ -382,6 +590,15 @@ func doCopySession(cli *cli.Context, session *sessionV8, encKeyDB map[string][]p
 					}
 					break
 				} else {
+					if isByteRange(offset, length) {
+						size, err := rangeLength(offset, length, cpURLs.SourceContent.Size)
+						if err != nil {
+							errorIf(err.Trace(cpURLs.SourceContent.URL.String()), "Unable to start copying.")
+							break
+						}
+						cpURLs.sourceOffset, cpURLs.sourceLength = offset, size
+						cpURLs.SourceContent.Size = size
+					}
 					totalBytes += cpURLs.SourceContent.Size
 					pg.SetTotal(totalBytes)
 				}
@@ -391,11 +608,59 @@ func doCopySession(cli *cli.Context, session *sessionV8, encKeyDB map[string][]p
 		}()
 	}
 
+	// Recursive copies track the copied objects in a manifest, which
+	// is exact when the parallel copies do not complete in order unlike
+	// the last copied object of the session.
+	args, isRecursive := cli.Args(), cli.Bool("recursive")
+	if session != nil {
+		args, isRecursive = session.Header.CommandArgs, session.Header.CommandBoolFlags["recursive"]
+	}
+	manifest := newCopyManifest(args, isRecursive, cli.Bool("continue"))
+	if manifest != nil {
+		isCopied = nil
+	}
+	var failures *errorSummary
+	if isRecursive {
+		failures = newErrorSummary()
+	}
+	var result batchResult
+
+	onComplete, hookStrict := cli.String("on-complete"), cli.Bool("hook-strict")
+	if session != nil && onComplete == "" {
+		onComplete = session.Header.CommandStringFlags["on-complete"]
+		hookStrict = hookStrict || session.Header.CommandBoolFlags["hook-strict"]
+	}
+	hook := newTransferHook(onComplete, hookStrict)
+	dedup := newDownloadDedup(cli.Bool("dedup") || session != nil && session.Header.CommandBoolFlags["dedup"])
+
+	manifestPath := cli.String("manifest")
+	if session != nil && manifestPath == "" {
+		manifestPath = session.Header.CommandStringFlags["manifest"]
+	}
+	transferred := newTransferManifest(manifestPath, encKeyDB)
+
+	reportPath := cli.String("report")
+	if session != nil && reportPath == "" {
+		reportPath = session.Header.CommandStringFlags["report"]
+	}
+	report := newTransferReport(reportPath, "cp")
+	saveReport := func() bool {
+		if err := report.save(); err != nil {
+			errorIf(err.Trace(reportPath), "Unable to write the report `"+reportPath+"`.")
+			return false
+		}
+		return true
+	}
+
 	var quitCh = make(chan struct{})
 	var statusCh = make(chan URLs)
 
 	parallel, queueCh := newParallelManager(statusCh)
 
+	// Metadata flags are already validated in mainCopy.
+	userMetaMap, _ := getCopyMetaData(cli)
+	replaceMetadata := strings.EqualFold(cli.String("metadata-directive"), "REPLACE") || cli.Bool("no-preserve-metadata")
+
 	go func() {
 		gracefulStop := func() {
 			close(queueCh)
@@ -431,12 +696,10 @@ func doCopySession(cli *cli.Context, session *sessionV8, encKeyDB map[string][]p
 					cpURLs.TargetContent.Metadata["X-Amz-Storage-Class"] = storageClass
 				}
 
-				if cli.String("attr") != "" {
-					userMetaMap, _ := getMetaDataEntry(cli.String("attr"))
-					for metaDataKey, metaDataVal := range userMetaMap {
-						cpURLs.TargetContent.UserMetadata[metaDataKey] = metaDataVal
-					}
+				for metaDataKey, metaDataVal := range userMetaMap {
+					cpURLs.TargetContent.UserMetadata[metaDataKey] = metaDataVal
 				}
+				cpURLs.replaceMetadata = replaceMetadata
 
 				// If one needs to store the file system information by passing -a flag
 				if preserve := cli.Bool("preserve"); preserve {
@@ -451,13 +714,14 @@ func doCopySession(cli *cli.Context, session *sessionV8, encKeyDB map[string][]p
 					}
 				}
 				// Verify if previously copied, notify progress bar.
-				if isCopied != nil && isCopied(cpURLs.SourceContent.URL.String()) {
+				if isCopied != nil && isCopied(cpURLs.SourceContent.URL.String()) ||
+					manifest != nil && manifest.isDone(cpURLs) {
 					queueCh <- func() URLs {
 						return doCopyFake(cpURLs, pg)
 					}
 				} else {
 					queueCh <- func() URLs {
-						return doCopy(ctx, cpURLs, pg, encKeyDB)
+						return hook.run(dedup.copy(ctx, cpURLs, pg, encKeyDB))
 					}
 				}
 			}
@@ -465,11 +729,14 @@ func doCopySession(cli *cli.Context, session *sessionV8, encKeyDB map[string][]p
 	}()
 
 	var retErr error
+	var preconditionFailed bool
+	var interrupted bool
 
 loop:
 	for {
 		select {
 		case <-trapCh:
+			interrupted = true
 			close(quitCh)
 			cancelCopy()
 			// Receive interrupt notification.
@@ -477,6 +744,7 @@ loop:
 				console.Eraseline()
 			}
 			if session != nil {
+				saveReport()
 				session.CloseAndDie()
 			}
 		case cpURLs, ok := <-statusCh:
@@ -484,15 +752,33 @@ loop:
 			if !ok {
 				break loop
 			}
+			report.add(cpURLs)
 			if cpURLs.Error == nil {
+				result.success()
 				if session != nil {
 					session.Header.LastCopied = cpURLs.SourceContent.URL.String()
 					session.Save()
 				}
+				if manifest != nil {
+					errorIf(manifest.add(cpURLs).Trace(cpURLs.SourceContent.URL.String()), "Unable to update the copy manifest.")
+				}
+				if err := transferred.add(cpURLs); err != nil {
+					errorIf(err, "Unable to record `%s` in the manifest.", cpURLs.TargetContent.URL.String())
+					retErr = exitStatus(globalErrorExitStatus)
+				}
 			} else {
-
-				// Set exit status for any copy error
-				retErr = exitStatus(globalErrorExitStatus)
+				result.failure()
+
+				// Set exit status for any copy error, objects refused by
+				// --if-not-exists have a status of their own unless other
+				// copies failed too.
+				if _, ok := cpURLs.Error.ToGoError().(ObjectPreconditionFailed); ok && (retErr == nil || preconditionFailed) {
+					preconditionFailed = true
+					retErr = exitStatus(globalPreconditionFailedExitStatus)
+				} else {
+					preconditionFailed = false
+					retErr = exitStatus(globalErrorExitStatus)
+				}
 
 				// Print in new line and adjust to top so that we
 				// don't print over the ongoing progress bar.
@@ -501,6 +787,7 @@ loop:
 				}
 				errorIf(cpURLs.Error.Trace(cpURLs.SourceContent.URL.String()),
 					fmt.Sprintf("Failed to copy `%s`.", cpURLs.SourceContent.URL.String()))
+				failures.add(cpURLs.SourceContent.URL.String(), cpURLs.Error)
 				if isErrIgnored(cpURLs.Error) {
 					continue loop
 				}
@@ -509,6 +796,7 @@ loop:
 					// For critical errors we should exit. Session
 					// can be resumed after the user figures out
 					// the  problem.
+					saveReport()
 					session.copyCloseAndDie(session.Header.CommandBoolFlags["session"])
 				}
 			}
@@ -524,6 +812,31 @@ loop:
 			printMsg(accntReader.Stat())
 		}
 	}
+	failures.print()
+	// The exit status tells apart the failures, see above.
+	result.summary()
+	// The report is written however the copy ended.
+	if !saveReport() && retErr == nil {
+		retErr = exitStatus(globalErrorExitStatus)
+	}
+
+	// The manifest of the objects transferred is only written once all
+	// of them are.
+	if retErr == nil && !interrupted {
+		if err := transferred.save(); err != nil {
+			errorIf(err.Trace(manifestPath), "Unable to write the manifest `"+manifestPath+"`.")
+			retErr = exitStatus(globalErrorExitStatus)
+		}
+	}
+
+	if manifest != nil {
+		// Keep the manifest for the copy to be resumed.
+		if retErr == nil && !interrupted {
+			errorIf(manifest.remove().Trace(), "Unable to remove the copy manifest.")
+		} else {
+			errorIf(manifest.close().Trace(), "Unable to close the copy manifest.")
+		}
+	}
 
 	return retErr
 }
@@ -541,6 +854,140 @@ func getMetaDataEntry(metadataString string) (map[string]string, *probe.Error) {
 	return metaDataMap, nil
 }
 
+// sidecarMetaData is the format of the JSON file passed to --attr-from-file.
+type sidecarMetaData struct {
+	ContentType        string            `json:"contentType,omitempty"`
+	CacheControl       string            `json:"cacheControl,omitempty"`
+	ContentEncoding    string            `json:"contentEncoding,omitempty"`
+	ContentDisposition string            `json:"contentDisposition,omitempty"`
+	ContentLanguage    string            `json:"contentLanguage,omitempty"`
+	Metadata           map[string]string `json:"metadata,omitempty"`
+}
+
+// Headers managed by the server which cannot be set as metadata.
+var systemHeaders = map[string]bool{
+	"Authorization":  true,
+	"Content-Length": true,
+	"Content-Md5":    true,
+	"Date":           true,
+	"Etag":           true,
+	"Expect":         true,
+	"Host":           true,
+	"Last-Modified":  true,
+}
+
+// validate the passed metadata file and populate the map
+func getMetaDataFromFile(filename string) (map[string]string, *probe.Error) {
+	f, e := os.Open(filename)
+	if e != nil {
+		return nil, probe.NewError(e)
+	}
+	defer f.Close()
+
+	var sidecar sidecarMetaData
+	decoder := json.NewDecoder(f)
+	decoder.DisallowUnknownFields()
+	if e = decoder.Decode(&sidecar); e != nil {
+		return nil, probe.NewError(e)
+	}
+
+	metaDataMap := make(map[string]string)
+	for key, value := range sidecar.Metadata {
+		key = http.CanonicalHeaderKey(key)
+		switch {
+		case systemHeaders[key]:
+			fallthrough
+		case strings.HasPrefix(key, "X-Amz-") && !strings.HasPrefix(key, "X-Amz-Meta-"):
+			fallthrough
+		case strings.HasPrefix(key, "X-Minio-"):
+			return nil, probe.NewError(fmt.Errorf("system header `%s` cannot be set as metadata", key))
+		case strings.HasPrefix(key, "Content-") || key == "Cache-Control":
+			return nil, probe.NewError(fmt.Errorf("header `%s` must be set with its own field, not as metadata", key))
+		}
+		metaDataMap[key] = value
+	}
+	for key, value := range map[string]string{
+		"Content-Type":        sidecar.ContentType,
+		"Cache-Control":       sidecar.CacheControl,
+		"Content-Encoding":    sidecar.ContentEncoding,
+		"Content-Disposition": sidecar.ContentDisposition,
+		"Content-Language":    sidecar.ContentLanguage,
+	} {
+		if value != "" {
+			metaDataMap[key] = value
+		}
+	}
+	return metaDataMap, nil
+}
+
+// parseExpires - parses the value of --expires, an HTTP date or a
+// duration from now, into the HTTP date of the Expires header.
+func parseExpires(expires string) (string, *probe.Error) {
+	if t, e := http.ParseTime(expires); e == nil {
+		return t.UTC().Format(http.TimeFormat), nil
+	}
+	d, e := ioutils.ParseDurationTime(expires)
+	if e != nil {
+		return "", probe.NewError(fmt.Errorf("expires must be an HTTP date or a duration, e.g. 7d: %v", e)).Trace(expires)
+	}
+	return UTCNow().Add(d).Format(http.TimeFormat), nil
+}
+
+// getCopyMetaData returns the metadata to set on copied objects from
+// --attr-from-file, --attr, --cache-control, --expires and --content-type,
+// later ones take precedence.
+func getCopyMetaData(ctx *cli.Context) (map[string]string, *probe.Error) {
+	userMetaMap := make(map[string]string)
+	if filename := ctx.String("attr-from-file"); filename != "" {
+		fileMetaMap, err := getMetaDataFromFile(filename)
+		if err != nil {
+			return nil, err.Trace(filename)
+		}
+		for k, v := range fileMetaMap {
+			userMetaMap[k] = v
+		}
+	}
+	if attr := ctx.String("attr"); attr != "" {
+		attrMetaMap, err := getMetaDataEntry(attr)
+		if err != nil {
+			return nil, err.Trace(attr)
+		}
+		for k, v := range attrMetaMap {
+			userMetaMap[k] = v
+		}
+	}
+	if cacheControl := ctx.String("cache-control"); cacheControl != "" {
+		userMetaMap["Cache-Control"] = cacheControl
+	}
+	if expires := ctx.String("expires"); expires != "" {
+		expiresDate, err := parseExpires(expires)
+		if err != nil {
+			return nil, err.Trace(expires)
+		}
+		userMetaMap["Expires"] = expiresDate
+	}
+	if contentType := ctx.String("content-type"); contentType != "" {
+		userMetaMap["Content-Type"] = contentType
+	}
+	modeStr, untilStr := ctx.String("retention-mode"), ctx.String("retention-until")
+	if modeStr != "" || untilStr != "" {
+		if modeStr == "" || untilStr == "" {
+			return nil, errInvalidArgument().Trace(modeStr, untilStr)
+		}
+		mode, err := parseRetentionMode(modeStr)
+		if err != nil {
+			return nil, err.Trace(modeStr)
+		}
+		retainUntil, err := parseRetainUntil(untilStr)
+		if err != nil {
+			return nil, err.Trace(untilStr)
+		}
+		userMetaMap[AmzObjectLockMode] = string(mode)
+		userMetaMap[AmzObjectLockRetainUntilDate] = retainUntil.Format(time.RFC3339)
+	}
+	return userMetaMap, nil
+}
+
 // mainCopy is the entry point for cp command.
 func mainCopy(ctx *cli.Context) error {
 	// Parse encryption keys per command.
@@ -548,10 +995,44 @@ func mainCopy(ctx *cli.Context) error {
 	fatalIf(err, "Unable to parse encryption keys.")
 
 	// Parse metadata.
-	userMetaMap := make(map[string]string)
-	if ctx.String("attr") != "" {
-		userMetaMap, err = getMetaDataEntry(ctx.String("attr"))
-		fatalIf(err, "Unable to parse attribute %v", ctx.String("attr"))
+	userMetaMap, err := getCopyMetaData(ctx)
+	fatalIf(err, "Unable to parse object metadata.")
+
+	// Replacing metadata only makes sense with some metadata to set.
+	switch strings.ToUpper(ctx.String("metadata-directive")) {
+	case "COPY":
+	case "REPLACE":
+		if len(userMetaMap) == 0 {
+			fatalIf(errInvalidArgument().Trace(), "--metadata-directive REPLACE requires metadata to set, e.g. with --content-type or --attr.")
+		}
+	default:
+		fatalIf(errInvalidArgument().Trace(ctx.String("metadata-directive")), "--metadata-directive must be one of [COPY, REPLACE].")
+	}
+	if ctx.Bool("no-preserve-metadata") && ctx.IsSet("metadata-directive") && strings.EqualFold(ctx.String("metadata-directive"), "COPY") {
+		fatalIf(errInvalidArgument().Trace(), "--no-preserve-metadata cannot be used with --metadata-directive COPY.")
+	}
+
+	// Files on web servers are streamed by themselves too.
+	if ctx.Bool("from-url") {
+		checkCopyFromURLSyntax(ctx)
+		console.SetColor("Copy", color.New(color.FgGreen, color.Bold))
+		args := ctx.Args()
+		sourceURLs, targetURL := args[:len(args)-1], args[len(args)-1]
+		fatalIf(urlSourceCopy(sourceURLs, targetURL, encKeyDB), "Unable to copy from `"+strings.Join(sourceURLs, "`, `")+"` to `"+targetURL+"`.")
+		return nil
+	}
+
+	// Archives are streamed by themselves, outside of copy sessions.
+	if ctx.Bool("archive") || ctx.Bool("extract") {
+		checkCopyArchiveSyntax(ctx)
+		console.SetColor("Copy", color.New(color.FgGreen, color.Bold))
+		sourceURL, targetURL := ctx.Args().Get(0), ctx.Args().Get(1)
+		if ctx.Bool("archive") {
+			fatalIf(archiveCopy(sourceURL, targetURL, encKeyDB), "Unable to archive `"+sourceURL+"` to `"+targetURL+"`.")
+		} else {
+			fatalIf(extractCopy(sourceURL, targetURL, encKeyDB), "Unable to extract `"+sourceURL+"` to `"+targetURL+"`.")
+		}
+		return nil
 	}
 
 	// check 'copy' cli arguments.
@@ -574,6 +1055,31 @@ func mainCopy(ctx *cli.Context) error {
 		fatalIf(err, "Unable to parse encryption keys.")
 	}
 	sse := ctx.String("encrypt")
+	partSize := ctx.String("part-size")
+	maxObjectSize := ctx.String("max-object-size")
+	order := ctx.String("order")
+	manifestPath := ctx.String("manifest")
+	if manifestPath != "" {
+		// Sessions may be resumed from another folder.
+		if abs, e := filepath.Abs(manifestPath); e == nil {
+			manifestPath = abs
+		}
+	}
+	reportPath := ctx.String("report")
+	if reportPath != "" {
+		if abs, e := filepath.Abs(reportPath); e == nil {
+			reportPath = abs
+		}
+	}
+	checksum := ctx.String("checksum-algorithm")
+	atomic := ctx.Bool("atomic")
+	excludeHidden := ctx.Bool("exclude-hidden")
+	ifNotExists := ctx.Bool("if-not-exists")
+	verify := ctx.Bool("verify")
+	noSniff := ctx.Bool("no-sniff")
+	compress := ctx.Bool("compress")
+	compressTypes := ctx.String("compress-types")
+	decompress := ctx.Bool("decompress")
 
 	var session *sessionV8
 
@@ -582,15 +1088,56 @@ func mainCopy(ctx *cli.Context) error {
 		if isSessionExists(sessionID) {
 			session, err = loadSessionV8(sessionID)
 			fatalIf(err.Trace(sessionID), "Unable to load session.")
+			if partSize == "" {
+				partSize = session.Header.CommandStringFlags["part-size"]
+			}
+			if checksum == "" {
+				checksum = session.Header.CommandStringFlags["checksum-algorithm"]
+			}
+			if maxObjectSize == "" {
+				maxObjectSize = session.Header.CommandStringFlags["max-object-size"]
+			}
+			if !ctx.IsSet("order") && session.Header.CommandStringFlags["order"] != "" {
+				order = session.Header.CommandStringFlags["order"]
+			}
+			atomic = atomic || session.Header.CommandBoolFlags["atomic"]
+			excludeHidden = excludeHidden || session.Header.CommandBoolFlags["exclude-hidden"]
+			ifNotExists = ifNotExists || session.Header.CommandBoolFlags["if-not-exists"]
+			verify = verify || session.Header.CommandBoolFlags["verify"]
+			noSniff = noSniff || session.Header.CommandBoolFlags["no-sniff"]
+			compress = compress || session.Header.CommandBoolFlags["compress"]
+			if compressTypes == "" {
+				compressTypes = session.Header.CommandStringFlags["compress-types"]
+			}
+			decompress = decompress || session.Header.CommandBoolFlags["decompress"]
 		} else {
 			session = newSessionV8(sessionID)
 			session.Header.CommandType = "cp"
 			session.Header.CommandBoolFlags["recursive"] = recursive
+			session.Header.CommandBoolFlags["preserve-empty-dirs"] = ctx.Bool("preserve-empty-dirs")
+			session.Header.CommandBoolFlags["flatten"] = ctx.Bool("flatten")
+			session.Header.CommandStringFlags["on-complete"] = ctx.String("on-complete")
+			session.Header.CommandBoolFlags["hook-strict"] = ctx.Bool("hook-strict")
 			session.Header.CommandStringFlags["older-than"] = olderThan
 			session.Header.CommandStringFlags["newer-than"] = newerThan
 			session.Header.CommandStringFlags["storage-class"] = storageClass
 			session.Header.CommandStringFlags["encrypt-key"] = sseKeys
 			session.Header.CommandStringFlags["encrypt"] = sse
+			session.Header.CommandStringFlags["part-size"] = partSize
+			session.Header.CommandStringFlags["checksum-algorithm"] = checksum
+			session.Header.CommandStringFlags["max-object-size"] = maxObjectSize
+			session.Header.CommandStringFlags["order"] = order
+			session.Header.CommandStringFlags["manifest"] = manifestPath
+			session.Header.CommandStringFlags["report"] = reportPath
+			session.Header.CommandBoolFlags["atomic"] = atomic
+			session.Header.CommandBoolFlags["exclude-hidden"] = excludeHidden
+			session.Header.CommandBoolFlags["if-not-exists"] = ifNotExists
+			session.Header.CommandBoolFlags["verify"] = verify
+			session.Header.CommandBoolFlags["no-sniff"] = noSniff
+			session.Header.CommandBoolFlags["compress"] = compress
+			session.Header.CommandStringFlags["compress-types"] = compressTypes
+			session.Header.CommandBoolFlags["decompress"] = decompress
+			session.Header.CommandBoolFlags["dedup"] = ctx.Bool("dedup")
 			session.Header.CommandBoolFlags["session"] = ctx.Bool("continue")
 
 			if ctx.Bool("preserve") {
@@ -609,6 +1156,21 @@ func mainCopy(ctx *cli.Context) error {
 		}
 	}
 
+	setGlobalPartSize(partSize)
+	setGlobalChecksumAlgorithm(checksum)
+	setGlobalLinks(ctx.String("links"))
+	setGlobalBandwidthLimits(ctx.String("limit-upload"), ctx.String("limit-download"))
+	setGlobalCopyAttempts(ctx)
+	setGlobalIdleTimeout(ctx)
+	setGlobalMaxObjectSize(maxObjectSize)
+	setGlobalCopyOrder(order)
+	globalAtomic = atomic
+	globalExcludeHidden = excludeHidden
+	globalIfNotExists = ifNotExists
+	globalVerify = verify
+	globalNoSniff = noSniff
+	setGlobalCompression(compress, decompress, compressTypes)
+
 	e := doCopySession(ctx, session, encKeyDB)
 	if session != nil {
 		session.Delete()
@@ -17,8 +17,28 @@
 package cmd
 
 import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"path/filepath"
 	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
 )
 
 func TestParseMetaData(t *testing.T) {
@@ -61,3 +81,791 @@ func TestParseMetaData(t *testing.T) {
 		}
 	}
 }
+
+func TestParseMetaDataFile(t *testing.T) {
+	testCases := []struct {
+		content string
+		output  map[string]string
+		status  bool
+	}{
+		{
+			`{"contentType": "text/html", "cacheControl": "max-age=300", "metadata": {"author": "jane", "x-amz-meta-team": "web"}}`,
+			map[string]string{"Content-Type": "text/html", "Cache-Control": "max-age=300", "Author": "jane", "X-Amz-Meta-Team": "web"},
+			true,
+		},
+		{`{"contentLanguage": "en"}`, map[string]string{"Content-Language": "en"}, true},
+		// unknown field
+		{`{"contentType": "text/html", "acl": "public-read"}`, nil, false},
+		// system headers
+		{`{"metadata": {"etag": "abc"}}`, nil, false},
+		{`{"metadata": {"x-amz-storage-class": "GLACIER"}}`, nil, false},
+		{`{"metadata": {"X-Minio-Internal-Foo": "bar"}}`, nil, false},
+		// content headers have their own fields
+		{`{"metadata": {"content-type": "text/html"}}`, nil, false},
+		// only string values
+		{`{"metadata": {"revision": 2}}`, nil, false},
+		{`not json`, nil, false},
+	}
+
+	dir, e := ioutil.TempDir("", "mc-attr-")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.RemoveAll(dir)
+
+	for i, testCase := range testCases {
+		filename := filepath.Join(dir, "meta.json")
+		if e = ioutil.WriteFile(filename, []byte(testCase.content), 0600); e != nil {
+			t.Fatal(e)
+		}
+		metaDataMap, err := getMetaDataFromFile(filename)
+		if testCase.status != (err == nil) {
+			t.Fatalf("Test %d: expected success %t, got %v", i+1, testCase.status, err)
+		}
+		if !reflect.DeepEqual(metaDataMap, testCase.output) {
+			t.Fatalf("Test %d: expected %v, got %v", i+1, testCase.output, metaDataMap)
+		}
+	}
+}
+
+func TestMetaDataFileRoundTrip(t *testing.T) {
+	dir, e := ioutil.TempDir("", "mc-attr-")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "meta.json")
+	content := `{"contentType": "text/html", "cacheControl": "max-age=300", "metadata": {"author": "jane"}}`
+	if e = ioutil.WriteFile(filename, []byte(content), 0600); e != nil {
+		t.Fatal(e)
+	}
+	metaDataMap, err := getMetaDataFromFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := httptest.NewServer(newFakeS3Server(nil))
+	defer server.Close()
+
+	conf := newTestS3Config(server.URL + "/bucket/index.html")
+	clnt, err := s3New(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data := []byte("<html/>\n\n\n")
+	if _, err = clnt.Put(context.Background(), bytes.NewReader(data), int64(len(data)), metaDataMap, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	st, err := clnt.Stat(false, true, false, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]string{
+		"Content-Type":      "text/html",
+		"Cache-Control":     "max-age=300",
+		"X-Amz-Meta-Author": "jane",
+	}
+	for k, v := range expected {
+		if st.Metadata[k] != v {
+			t.Errorf("Expected %s to be `%s`, got `%s`", k, v, st.Metadata[k])
+		}
+	}
+}
+
+// newCopyContext - returns the context of a cp command line.
+func newCopyContext(t *testing.T, args ...string) *cli.Context {
+	set := flag.NewFlagSet(cpCmd.Name, flag.ContinueOnError)
+	for _, f := range cpCmd.Flags {
+		f.Apply(set)
+	}
+	if e := set.Parse(args); e != nil {
+		t.Fatal(e)
+	}
+	ctx := cli.NewContext(nil, set, nil)
+	ctx.Command = cpCmd
+	return ctx
+}
+
+func TestCopyManifestResume(t *testing.T) {
+	savedQuiet, savedOutput := globalQuiet, color.Output
+	defer func() {
+		globalQuiet, color.Output = savedQuiet, savedOutput
+	}()
+	globalQuiet, color.Output = true, ioutil.Discard
+	defer withTestConfig(nil)()
+
+	dir, e := ioutil.TempDir("", "mc-cp-manifest-")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.RemoveAll(dir)
+	defer setMcConfigDir(mcCustomConfigDir)
+	setMcConfigDir(filepath.Join(dir, "mc"))
+	source, target := filepath.Join(dir, "source"), filepath.Join(dir, "target")
+	files := map[string]string{"a": "first", "b": "second", "sub/c": "third"}
+	for name, data := range files {
+		if e = os.MkdirAll(filepath.Dir(filepath.Join(source, name)), 0700); e != nil {
+			t.Fatal(e)
+		}
+		if e = ioutil.WriteFile(filepath.Join(source, name), []byte(data), 0600); e != nil {
+			t.Fatal(e)
+		}
+	}
+	// A folder in place of `sub/c` fails its copy, as if interrupted.
+	if e = os.MkdirAll(filepath.Join(target, "sub", "c", "blocker"), 0700); e != nil {
+		t.Fatal(e)
+	}
+
+	args := []string{"--recursive", "--continue", source + string(os.PathSeparator), target + string(os.PathSeparator)}
+	manifestPath, err := getCopyManifestPath(args[2:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e = doCopySession(newCopyContext(t, args...), nil, nil); e == nil {
+		t.Fatal("Expected the first copy to fail")
+	}
+	if _, e = os.Stat(manifestPath); e != nil {
+		t.Fatalf("Expected the manifest to be kept: %v", e)
+	}
+
+	// Objects recorded in the manifest are not copied again, a change
+	// keeping the size is therefore not noticed.
+	if e = ioutil.WriteFile(filepath.Join(source, "a"), []byte("FIRST"), 0600); e != nil {
+		t.Fatal(e)
+	}
+	if e = os.RemoveAll(filepath.Join(target, "sub", "c")); e != nil {
+		t.Fatal(e)
+	}
+	if e = doCopySession(newCopyContext(t, args...), nil, nil); e != nil {
+		t.Fatal(e)
+	}
+	for name, data := range files {
+		content, e := ioutil.ReadFile(filepath.Join(target, name))
+		if e != nil {
+			t.Fatal(e)
+		}
+		if string(content) != data {
+			t.Fatalf("%s: Expected %q, got %q", name, data, content)
+		}
+	}
+	if _, e = os.Stat(manifestPath); !os.IsNotExist(e) {
+		t.Fatalf("Expected the manifest to be removed on completion: %v", e)
+	}
+}
+
+func TestCopyManifestResumeUpload(t *testing.T) {
+	savedQuiet, savedOutput := globalQuiet, color.Output
+	defer func() {
+		globalQuiet, color.Output = savedQuiet, savedOutput
+	}()
+	globalQuiet, color.Output = true, ioutil.Discard
+
+	handler := newFakeS3Server(nil)
+	var uploadsMutex sync.Mutex
+	var uploads []string
+	failing := "sub/c"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPut {
+			key := strings.TrimPrefix(r.URL.Path, "/bucket/")
+			uploadsMutex.Lock()
+			defer uploadsMutex.Unlock()
+			if key == failing {
+				// Not retried by the client.
+				w.WriteHeader(http.StatusForbidden)
+				w.Write([]byte(`<Error><Code>AccessDenied</Code><Message>Access Denied.</Message></Error>`))
+				return
+			}
+			uploads = append(uploads, key)
+		}
+		handler.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+	defer withTestConfig(map[string]hostConfigV9{"myminio": testHost(server.URL)})()
+
+	dir, e := ioutil.TempDir("", "mc-cp-manifest-")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.RemoveAll(dir)
+	defer setMcConfigDir(mcCustomConfigDir)
+	setMcConfigDir(filepath.Join(dir, "mc"))
+	source := filepath.Join(dir, "source")
+	files := map[string]string{"a": "first", "b": "second", "sub/c": "third"}
+	for name, data := range files {
+		if e = os.MkdirAll(filepath.Dir(filepath.Join(source, name)), 0700); e != nil {
+			t.Fatal(e)
+		}
+		if e = ioutil.WriteFile(filepath.Join(source, name), []byte(data), 0600); e != nil {
+			t.Fatal(e)
+		}
+	}
+
+	args := []string{"--recursive", "--continue", source + string(os.PathSeparator), "myminio/bucket/"}
+	manifestPath, err := getCopyManifestPath(args[2:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e = doCopySession(newCopyContext(t, args...), nil, nil); e == nil {
+		t.Fatal("Expected the first upload to fail")
+	}
+	if _, e = os.Stat(manifestPath); e != nil {
+		t.Fatalf("Expected the manifest to be kept: %v", e)
+	}
+
+	// Completed objects are skipped while they are found with the same
+	// size, `b` removed meanwhile is uploaded again.
+	uploadsMutex.Lock()
+	failing, uploads = "", nil
+	uploadsMutex.Unlock()
+	handler.mutex.Lock()
+	delete(handler.objects, "bucket/b")
+	handler.mutex.Unlock()
+	if e = doCopySession(newCopyContext(t, args...), nil, nil); e != nil {
+		t.Fatal(e)
+	}
+	sort.Strings(uploads)
+	if !reflect.DeepEqual(uploads, []string{"b", "sub/c"}) {
+		t.Fatalf("Expected only b and sub/c to be uploaded, got %v", uploads)
+	}
+	for name, data := range files {
+		if string(handler.objects["bucket/"+name]) != data {
+			t.Fatalf("%s: Expected %q, got %q", name, data, handler.objects["bucket/"+name])
+		}
+	}
+	if _, e = os.Stat(manifestPath); !os.IsNotExist(e) {
+		t.Fatalf("Expected the manifest to be removed on completion: %v", e)
+	}
+}
+
+// copyRequestRecorder records the headers of the server side copies
+// to the objects of the server.
+type copyRequestRecorder struct {
+	*fakeS3Server
+	copies map[string]http.Header
+}
+
+func (h copyRequestRecorder) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodPut && r.Header.Get("X-Amz-Copy-Source") != "" {
+		h.mutex.Lock()
+		h.copies[strings.TrimPrefix(r.URL.Path, "/")] = r.Header
+		h.mutex.Unlock()
+	}
+	h.fakeS3Server.ServeHTTP(w, r)
+}
+
+func TestCopyMetadataDirective(t *testing.T) {
+	handler := copyRequestRecorder{fakeS3Server: newFakeS3Server(map[string][]byte{"bucket/src": []byte("0123456789")}), copies: map[string]http.Header{}}
+	handler.headers["bucket/src"] = http.Header{"Content-Type": {"application/octet-stream"}, "X-Amz-Meta-Owner": {"jane"}}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	defer withTestConfig(map[string]hostConfigV9{"myminio": testHost(server.URL)})()
+
+	testCases := []struct {
+		replaceMetadata bool
+		directive       string
+		owner           string
+	}{
+		// The metadata of the source is preserved, fixing the content type.
+		{false, "", "jane"},
+		// Only the given metadata is set.
+		{true, "REPLACE", ""},
+	}
+	for i, testCase := range testCases {
+		urls := URLs{
+			SourceAlias:   "myminio",
+			SourceContent: &clientContent{URL: *newClientURL(server.URL + "/bucket/src"), Size: 10},
+			TargetAlias:   "myminio",
+			TargetContent: &clientContent{
+				URL:          *newClientURL(server.URL + "/bucket/dst"),
+				Metadata:     map[string]string{},
+				UserMetadata: map[string]string{"Content-Type": "text/html"},
+			},
+			replaceMetadata: testCase.replaceMetadata,
+		}
+		if urls = uploadSourceToTargetURL(context.Background(), urls, nil, nil); urls.Error != nil {
+			t.Fatalf("Test %d: %s", i+1, urls.Error)
+		}
+		header := handler.copies["bucket/dst"]
+		if header.Get("X-Amz-Copy-Source") == "" {
+			t.Fatalf("Test %d: Expected a server side copy", i+1)
+		}
+		if header.Get("Content-Type") != "text/html" {
+			t.Fatalf("Test %d: Expected the content type to be replaced, got %s", i+1, header.Get("Content-Type"))
+		}
+		if testCase.directive != "" && header.Get("X-Amz-Metadata-Directive") != testCase.directive {
+			t.Fatalf("Test %d: Expected metadata directive %s, got %s", i+1, testCase.directive, header.Get("X-Amz-Metadata-Directive"))
+		}
+		if header.Get("X-Amz-Meta-Owner") != testCase.owner {
+			t.Fatalf("Test %d: Expected owner `%s`, got `%s`", i+1, testCase.owner, header.Get("X-Amz-Meta-Owner"))
+		}
+	}
+}
+
+func TestCopyTransferManifest(t *testing.T) {
+	savedQuiet, savedOutput := globalQuiet, color.Output
+	defer func() {
+		globalQuiet, color.Output = savedQuiet, savedOutput
+	}()
+	globalQuiet, color.Output = true, ioutil.Discard
+
+	handler := newFakeS3Server(nil)
+	handler.md5ETags = true
+	var failingMutex sync.Mutex
+	failing := "release/sub/c"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPut {
+			failingMutex.Lock()
+			defer failingMutex.Unlock()
+			if strings.TrimPrefix(r.URL.Path, "/bucket/") == failing {
+				w.WriteHeader(http.StatusForbidden)
+				w.Write([]byte(`<Error><Code>AccessDenied</Code><Message>Access Denied.</Message></Error>`))
+				return
+			}
+		}
+		handler.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+	defer withTestConfig(map[string]hostConfigV9{"myminio": testHost(server.URL)})()
+
+	dir, e := ioutil.TempDir("", "mc-cp-manifest-")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.RemoveAll(dir)
+	source := filepath.Join(dir, "source")
+	files := map[string]string{"a": "first", "b": "second", "sub/c": "third"}
+	for name, data := range files {
+		if e = os.MkdirAll(filepath.Dir(filepath.Join(source, name)), 0700); e != nil {
+			t.Fatal(e)
+		}
+		if e = ioutil.WriteFile(filepath.Join(source, name), []byte(data), 0600); e != nil {
+			t.Fatal(e)
+		}
+	}
+	manifestPath := filepath.Join(dir, "release.json")
+	args := []string{"--recursive", "--manifest", manifestPath, source + string(os.PathSeparator), "myminio/bucket/release/"}
+
+	// Nothing is written unless every object is uploaded.
+	if e = doCopySession(newCopyContext(t, args...), nil, nil); e == nil {
+		t.Fatal("Expected the upload to fail")
+	}
+	if _, e = os.Stat(manifestPath); !os.IsNotExist(e) {
+		t.Fatalf("Expected no manifest, got %v", e)
+	}
+
+	failingMutex.Lock()
+	failing = ""
+	failingMutex.Unlock()
+	if e = doCopySession(newCopyContext(t, args...), nil, nil); e != nil {
+		t.Fatal(e)
+	}
+	data, e := ioutil.ReadFile(manifestPath)
+	if e != nil {
+		t.Fatal(e)
+	}
+	var manifest transferManifestV1
+	if e = json.Unmarshal(data, &manifest); e != nil {
+		t.Fatal(e)
+	}
+	var expected []transferManifestEntry
+	for _, name := range []string{"a", "b", "sub/c"} {
+		sum := md5.Sum([]byte(files[name]))
+		expected = append(expected, transferManifestEntry{
+			Key:  "release/" + name,
+			Size: int64(len(files[name])),
+			ETag: hex.EncodeToString(sum[:]),
+		})
+		if string(handler.objects["bucket/release/"+name]) != files[name] {
+			t.Fatalf("Expected `%s` to be uploaded", name)
+		}
+	}
+	if manifest.Version != transferManifestVersion || !reflect.DeepEqual(manifest.Objects, expected) {
+		t.Fatalf("Expected the manifest %v, got %v", expected, manifest)
+	}
+}
+
+func TestCopyZeroByteObjects(t *testing.T) {
+	savedQuiet, savedOutput := globalQuiet, color.Output
+	defer func() {
+		globalQuiet, color.Output = savedQuiet, savedOutput
+	}()
+	globalQuiet, color.Output = true, ioutil.Discard
+
+	handler := newFakeS3Server(nil)
+	var multipart int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := r.URL.Query()["uploads"]; ok {
+			atomic.AddInt32(&multipart, 1)
+		}
+		handler.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+	defer withTestConfig(map[string]hostConfigV9{"myminio": testHost(server.URL)})()
+
+	dir, e := ioutil.TempDir("", "mc-cp-empty-")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.RemoveAll(dir)
+	source := filepath.Join(dir, "source")
+	if e = os.MkdirAll(filepath.Join(source, "sub"), 0700); e != nil {
+		t.Fatal(e)
+	}
+	for name, data := range map[string]string{"empty": "", "sub/empty": "", "full": "data"} {
+		if e = ioutil.WriteFile(filepath.Join(source, name), []byte(data), 0600); e != nil {
+			t.Fatal(e)
+		}
+	}
+
+	// Zero-byte files are uploaded with a single PUT each.
+	if e = doCopySession(newCopyContext(t, "--recursive", source+string(os.PathSeparator), "myminio/bucket/"), nil, nil); e != nil {
+		t.Fatal(e)
+	}
+	for _, key := range []string{"empty", "sub/empty"} {
+		if data, ok := handler.objects["bucket/"+key]; !ok || len(data) != 0 {
+			t.Fatalf("Expected the zero-byte object `%s`, got %q", key, data)
+		}
+	}
+	if n := atomic.LoadInt32(&multipart); n != 0 {
+		t.Fatalf("Expected no multipart upload, got %d", n)
+	}
+
+	// Zero-byte objects are downloaded as empty files.
+	target := filepath.Join(dir, "target")
+	if e = doCopySession(newCopyContext(t, "--recursive", "myminio/bucket/", target), nil, nil); e != nil {
+		t.Fatal(e)
+	}
+	for _, name := range []string{"empty", "sub/empty"} {
+		st, e := os.Stat(filepath.Join(target, name))
+		if e != nil {
+			t.Fatal(e)
+		}
+		if !st.Mode().IsRegular() || st.Size() != 0 {
+			t.Fatalf("Expected `%s` to be an empty file, got %v of %d bytes", name, st.Mode(), st.Size())
+		}
+	}
+}
+
+func TestCopyPreserveEmptyDirs(t *testing.T) {
+	savedQuiet, savedOutput := globalQuiet, color.Output
+	defer func() {
+		globalQuiet, color.Output = savedQuiet, savedOutput
+	}()
+	globalQuiet, color.Output = true, ioutil.Discard
+
+	handler := newFakeS3Server(nil)
+	server := httptest.NewServer(handler)
+	defer server.Close()
+	host := testHost(server.URL)
+	// Signature V2 uploads the data unchunked.
+	host.API = "S3v2"
+	defer withTestConfig(map[string]hostConfigV9{"myminio": host})()
+
+	dir, e := ioutil.TempDir("", "mc-cp-empty-dirs-")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.RemoveAll(dir)
+	source, target := filepath.Join(dir, "src"), filepath.Join(dir, "target")
+	for _, name := range []string{"empty", "sub"} {
+		if e = os.MkdirAll(filepath.Join(source, name), 0700); e != nil {
+			t.Fatal(e)
+		}
+	}
+	if e = ioutil.WriteFile(filepath.Join(source, "sub", "a"), []byte("data"), 0600); e != nil {
+		t.Fatal(e)
+	}
+	if e = os.MkdirAll(target, 0700); e != nil {
+		t.Fatal(e)
+	}
+
+	copyAll := func(sourceURL, targetURL string, preserveEmptyDirs bool) {
+		for cpURLs := range prepareCopyURLs([]string{sourceURL}, targetURL, true, preserveEmptyDirs, nil, "", "") {
+			if cpURLs.Error != nil {
+				t.Fatal(cpURLs.Error)
+			}
+			if cpURLs = doCopy(context.Background(), cpURLs, nil, nil); cpURLs.Error != nil {
+				t.Fatal(cpURLs.Error)
+			}
+		}
+	}
+
+	// Without the flag empty directories are not copied.
+	copyAll(source, "myminio/bucket/", false)
+	if expected := map[string][]byte{"bucket/src/sub/a": []byte("data")}; !reflect.DeepEqual(handler.objects, expected) {
+		t.Fatalf("Expected %v, got %v", expected, handler.objects)
+	}
+
+	copyAll(source, "myminio/bucket/", true)
+	expected := map[string][]byte{"bucket/src/sub/a": []byte("data"), "bucket/src/empty/": {}}
+	if !reflect.DeepEqual(handler.objects, expected) {
+		t.Fatalf("Expected %v, got %v", expected, handler.objects)
+	}
+
+	copyAll("myminio/bucket/src", target+string(os.PathSeparator), true)
+	if fi, e := os.Stat(filepath.Join(target, "src", "empty")); e != nil || !fi.IsDir() {
+		t.Fatalf("Expected the empty directory to be recreated: %v", e)
+	}
+	if data, e := ioutil.ReadFile(filepath.Join(target, "src", "sub", "a")); e != nil || string(data) != "data" {
+		t.Fatalf("Expected the object to be downloaded, got %q: %v", data, e)
+	}
+}
+
+func TestCopyFlatten(t *testing.T) {
+	savedQuiet, savedJSON, savedOutput := globalQuiet, globalJSON, color.Output
+	defer func() {
+		globalQuiet, globalJSON, color.Output = savedQuiet, savedJSON, savedOutput
+	}()
+	globalQuiet, globalJSON, color.Output = true, true, ioutil.Discard
+
+	handler := newFakeS3Server(map[string][]byte{
+		"bucket/a/x.txt":   []byte("first"),
+		"bucket/b/c/x.txt": []byte("second"),
+		"bucket/b/x-1.txt": []byte("third"),
+		"bucket/b/y":       []byte("fourth"),
+	})
+	server := httptest.NewServer(handler)
+	defer server.Close()
+	defer withTestConfig(map[string]hostConfigV9{"myminio": testHost(server.URL)})()
+
+	target, e := ioutil.TempDir("", "mc-cp-flatten-")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.RemoveAll(target)
+
+	targetURL := target + string(os.PathSeparator)
+	for cpURLs := range flattenCopyURLs(prepareCopyURLs([]string{"myminio/bucket/"}, targetURL, true, false, nil, "", ""), targetURL) {
+		if cpURLs.Error != nil {
+			t.Fatal(cpURLs.Error)
+		}
+		if cpURLs = doCopy(context.Background(), cpURLs, nil, nil); cpURLs.Error != nil {
+			t.Fatal(cpURLs.Error)
+		}
+	}
+
+	// Objects are listed in lexical order, the first one keeps its name.
+	expected := map[string]string{"x.txt": "first", "x-1.txt": "second", "x-1-1.txt": "third", "y": "fourth"}
+	entries, e := ioutil.ReadDir(target)
+	if e != nil {
+		t.Fatal(e)
+	}
+	if len(entries) != len(expected) {
+		t.Fatalf("Expected %d files in the target folder, got %d", len(expected), len(entries))
+	}
+	for name, data := range expected {
+		content, e := ioutil.ReadFile(filepath.Join(target, name))
+		if e != nil {
+			t.Fatal(e)
+		}
+		if string(content) != data {
+			t.Errorf("%s: Expected %q, got %q", name, data, content)
+		}
+	}
+}
+
+func TestCopyPreserveMetadata(t *testing.T) {
+	savedQuiet, savedOutput := globalQuiet, color.Output
+	defer func() {
+		globalQuiet, color.Output = savedQuiet, savedOutput
+	}()
+	globalQuiet, color.Output = true, ioutil.Discard
+
+	header := http.Header{
+		"Content-Type":      {"text/html"},
+		"Cache-Control":     {"max-age=60"},
+		"Content-Encoding":  {"gzip"},
+		"X-Amz-Meta-Author": {"jane"},
+		"Expires":           {"Thu, 01 Jan 2037 00:00:00 GMT"},
+	}
+	source := newFakeS3Server(map[string][]byte{"bucket/index.html": []byte("<html></html>")})
+	source.headers["bucket/index.html"] = header
+	target := newFakeS3Server(nil)
+	sourceServer, targetServer := httptest.NewServer(source), httptest.NewServer(target)
+	defer sourceServer.Close()
+	defer targetServer.Close()
+	host := func(URL string) hostConfigV9 {
+		host := testHost(URL)
+		host.API = "S3v2"
+		return host
+	}
+	defer withTestConfig(map[string]hostConfigV9{"source": host(sourceServer.URL), "target": host(targetServer.URL)})()
+
+	copyObject := func(replaceMetadata bool) http.Header {
+		cpURLs := prepareCopyURLs([]string{"source/bucket/index.html"}, "target/bucket/index.html", false, false, nil, "", "")
+		for urls := range cpURLs {
+			if urls.Error != nil {
+				t.Fatal(urls.Error)
+			}
+			urls.TargetContent.Metadata = map[string]string{}
+			urls.TargetContent.UserMetadata = map[string]string{}
+			urls.replaceMetadata = replaceMetadata
+			if urls = doCopy(context.Background(), urls, nil, nil); urls.Error != nil {
+				t.Fatal(urls.Error)
+			}
+		}
+		return target.header("bucket/index.html")
+	}
+
+	// Headers of the response other than the metadata of the source,
+	// such as its ETag, are not copied.
+	if copied := copyObject(false); !reflect.DeepEqual(copied, header) {
+		t.Fatalf("Expected the metadata %v to be copied, got %v", header, copied)
+	}
+	expected := http.Header{"Content-Type": {"application/octet-stream"}}
+	if copied := copyObject(true); !reflect.DeepEqual(copied, expected) {
+		t.Fatalf("Expected the metadata %v without the metadata of the source, got %v", expected, copied)
+	}
+}
+
+func TestPutCacheControlExpires(t *testing.T) {
+	savedOutput := color.Output
+	defer func() { color.Output = savedOutput }()
+	color.Output = ioutil.Discard
+
+	handler := newFakeS3Server(nil)
+	server := httptest.NewServer(handler)
+	defer server.Close()
+	defer withTestConfig(map[string]hostConfigV9{"myminio": testHost(server.URL)})()
+
+	expires, err := parseExpires("Wed, 21 Oct 2037 07:28:00 GMT")
+	if err != nil {
+		t.Fatal(err)
+	}
+	metadata := map[string]string{"Cache-Control": "public, max-age=604800", "Expires": expires}
+	if _, err = pipeStream("myminio/bucket/index.html", strings.NewReader("<html></html>"), metadata, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	clnt, err := newClient("myminio/bucket/index.html")
+	if err != nil {
+		t.Fatal(err)
+	}
+	isIncomplete, isFetchMeta, isPreserve := false, true, false
+	content, err := clnt.Stat(isIncomplete, isFetchMeta, isPreserve, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stat := parseStat(content)
+	if stat.Metadata["Cache-Control"] != metadata["Cache-Control"] {
+		t.Fatalf("Expected Cache-Control `%s`, got `%s`", metadata["Cache-Control"], stat.Metadata["Cache-Control"])
+	}
+	if expected := time.Date(2037, 10, 21, 7, 28, 0, 0, time.UTC); !stat.Expires.Equal(expected) {
+		t.Fatalf("Expected Expires %s, got %s", expected, stat.Expires)
+	}
+
+	// Durations are converted to absolute dates.
+	before := UTCNow().Truncate(time.Second)
+	expires, err = parseExpires("7d")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d, e := http.ParseTime(expires); e != nil || d.Sub(before) < 7*24*time.Hour || d.Sub(before) > 7*24*time.Hour+time.Minute {
+		t.Fatalf("Expected a date 7 days from now, got %s", expires)
+	}
+	if _, err = parseExpires("next week"); err == nil {
+		t.Fatal("Expected an invalid expires to fail")
+	}
+}
+
+func TestCopyMaxObjectSize(t *testing.T) {
+	savedQuiet, savedJSON, savedOutput := globalQuiet, globalJSON, color.Output
+	defer func() {
+		globalQuiet, globalJSON, color.Output = savedQuiet, savedJSON, savedOutput
+		globalMaxObjectSize, globalSkippedTooLarge = 0, 0
+	}()
+	var buf bytes.Buffer
+	globalQuiet, globalJSON, color.Output = true, true, &buf
+	setGlobalMaxObjectSize("10B")
+
+	handler := newFakeS3Server(map[string][]byte{
+		"bucket/big.bin":   []byte("more than ten bytes"),
+		"bucket/small.txt": []byte("ten bytes!"),
+	})
+	server := httptest.NewServer(handler)
+	defer server.Close()
+	defer withTestConfig(map[string]hostConfigV9{"myminio": testHost(server.URL)})()
+
+	target, e := ioutil.TempDir("", "mc-cp-max-size-")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.RemoveAll(target)
+
+	targetURL := target + string(os.PathSeparator)
+	for cpURLs := range prepareCopyURLs([]string{"myminio/bucket/"}, targetURL, true, false, nil, "", "") {
+		if cpURLs.Error != nil {
+			t.Fatal(cpURLs.Error)
+		}
+		if cpURLs = doCopy(context.Background(), cpURLs, nil, nil); cpURLs.Error != nil {
+			t.Fatal(cpURLs.Error)
+		}
+	}
+	if _, e = os.Stat(filepath.Join(target, "small.txt")); e != nil {
+		t.Fatalf("Expected the small object to be copied: %v", e)
+	}
+	if _, e = os.Stat(filepath.Join(target, "big.bin")); !os.IsNotExist(e) {
+		t.Fatal("Expected the object larger than --max-object-size to be skipped")
+	}
+	if !strings.Contains(buf.String(), `{"status":"skipped","source":"myminio/bucket/big.bin","size":19,"maxSize":10}`) {
+		t.Fatalf("Expected a notice of the skipped object, got %s", buf.String())
+	}
+	// Skipped objects are counted in the summary, apart from errors.
+	if stat := newAccounter(0).Stat(); stat.SkippedTooLarge != 1 {
+		t.Fatalf("Expected 1 object skipped in the summary, got %d", stat.SkippedTooLarge)
+	}
+
+	// Mirroring skips them too.
+	mj := &mirrorJob{}
+	for _, testCase := range []struct {
+		size     int64
+		expected mirrorAction
+	}{{10, mirrorActionCopy}, {11, mirrorActionSkipTooLarge}} {
+		if action, _ := mj.action(URLs{SourceContent: &clientContent{Size: testCase.size}}); action != testCase.expected {
+			t.Fatalf("Expected the action of an object of %d bytes to be %d, got %d", testCase.size, testCase.expected, action)
+		}
+	}
+}
+
+func TestCopyOrder(t *testing.T) {
+	handler := newFakeS3Server(map[string][]byte{
+		"bucket/a": []byte("12345"),
+		"bucket/b": []byte("1"),
+		"bucket/c": []byte("123456789"),
+		"bucket/d": []byte("123"),
+		"bucket/e": []byte("12345"),
+	})
+	server := httptest.NewServer(handler)
+	defer server.Close()
+	defer withTestConfig(map[string]hostConfigV9{"myminio": testHost(server.URL)})()
+
+	target, e := ioutil.TempDir("", "mc-cp-order-")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.RemoveAll(target)
+
+	// Objects of the same size keep the order they are listed in.
+	testCases := []struct {
+		order    string
+		expected string
+	}{
+		{"name", "abcde"},
+		{"size-asc", "bdaec"},
+		{"size-desc", "caedb"},
+	}
+	for _, testCase := range testCases {
+		var dispatched string
+		URLsCh := prepareCopyURLs([]string{"myminio/bucket/"}, target+string(os.PathSeparator), true, false, nil, "", "")
+		for cpURLs := range orderCopyURLs(URLsCh, testCase.order) {
+			if cpURLs.Error != nil {
+				t.Fatal(cpURLs.Error)
+			}
+			dispatched += path.Base(cpURLs.SourceContent.URL.Path)
+		}
+		if dispatched != testCase.expected {
+			t.Errorf("%s: Expected the objects to be dispatched as %s, got %s", testCase.order, testCase.expected, dispatched)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+/*
+ * MinIO Client (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/minio/mc/pkg/probe"
+)
+
+// copyManifestEntry is an object copied completely.
+type copyManifestEntry struct {
+	Source string `json:"source"`
+	Size   int64  `json:"size"`
+	ETag   string `json:"etag,omitempty"`
+}
+
+// copyManifest records the objects of a recursive copy as they complete,
+// keyed by their source, so that an interrupted upload or download
+// resumed with --continue skips them once their target is found with
+// the same size. Unlike the last copied object of a session it does not
+// depend on the order in which the parallel copies complete.
+type copyManifest struct {
+	mutex sync.Mutex
+	path  string
+	file  *os.File
+	done  map[copyManifestEntry]bool
+}
+
+// getCopyManifestPath - returns the path of the manifest of the copy of
+// the sources to the target of args, the same for every run.
+func getCopyManifestPath(args []string) (string, *probe.Error) {
+	configDir, err := getMcConfigDir()
+	if err != nil {
+		return "", err.Trace()
+	}
+	return filepath.Join(configDir, globalManifestDir, getHash("cp", args)+".json"), nil
+}
+
+// openCopyManifest - opens the manifest at path, loading the objects
+// already copied by a previous run.
+func openCopyManifest(path string) (*copyManifest, *probe.Error) {
+	if e := os.MkdirAll(filepath.Dir(path), 0700); e != nil {
+		return nil, probe.NewError(e)
+	}
+	m := &copyManifest{
+		path: path,
+		done: make(map[copyManifestEntry]bool),
+	}
+	file, e := os.OpenFile(m.path, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0600)
+	if e != nil {
+		return nil, probe.NewError(e)
+	}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var entry copyManifestEntry
+		// A line cut short by an interruption is not an error, the
+		// object is copied again.
+		if json.Unmarshal(scanner.Bytes(), &entry) == nil {
+			m.done[entry] = true
+		}
+	}
+	if e = scanner.Err(); e != nil {
+		file.Close()
+		return nil, probe.NewError(e)
+	}
+	m.file = file
+	return m, nil
+}
+
+// manifestEntry - returns the manifest entry of the source of cpURLs.
+func manifestEntry(cpURLs URLs) copyManifestEntry {
+	return copyManifestEntry{
+		Source: cpURLs.SourceContent.URL.String(),
+		Size:   cpURLs.SourceContent.Size,
+		ETag:   cpURLs.SourceContent.ETag,
+	}
+}
+
+// isDone - returns true if the source of cpURLs was copied by a previous
+// run and did not change since, and its target is still there with the
+// same size.
+func (m *copyManifest) isDone(cpURLs URLs) bool {
+	m.mutex.Lock()
+	done := m.done[manifestEntry(cpURLs)]
+	m.mutex.Unlock()
+	if !done {
+		return false
+	}
+	targetURL := cpURLs.TargetContent.URL.String()
+	clnt, err := newClientFromAlias(cpURLs.TargetAlias, targetURL)
+	if err != nil {
+		return false
+	}
+	content, err := clnt.Stat(false, false, false, nil)
+	return err == nil && content.Size == cpURLs.SourceContent.Size
+}
+
+// add - records the source of cpURLs as copied, the entry is written
+// right away to survive the process being interrupted.
+func (m *copyManifest) add(cpURLs URLs) *probe.Error {
+	entry := manifestEntry(cpURLs)
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if m.done[entry] {
+		return nil
+	}
+	line, e := json.Marshal(entry)
+	if e != nil {
+		return probe.NewError(e)
+	}
+	if _, e = m.file.Write(append(line, '\n')); e != nil {
+		return probe.NewError(e)
+	}
+	m.done[entry] = true
+	return nil
+}
+
+// close - closes the manifest, keeping it for a later run.
+func (m *copyManifest) close() *probe.Error {
+	return probe.NewError(m.file.Close())
+}
+
+// remove - closes and removes the manifest of a completed copy.
+func (m *copyManifest) remove() *probe.Error {
+	m.file.Close()
+	return probe.NewError(os.Remove(m.path))
+}
+
+// newCopyManifest - returns the manifest of a recursive copy resumed with
+// --continue, nil for any other copy.
+func newCopyManifest(args []string, isRecursive, isContinue bool) *copyManifest {
+	if !isRecursive || !isContinue || len(args) < 2 {
+		return nil
+	}
+	path, err := getCopyManifestPath(args)
+	fatalIf(err.Trace(args...), "Unable to get the copy manifest of `"+args[len(args)-1]+"`.")
+	manifest, err := openCopyManifest(path)
+	fatalIf(err.Trace(path), "Unable to open the copy manifest `"+path+"`.")
+	return manifest
+}
@@ -0,0 +1,142 @@
+/*
+ * MinIO Client (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package cmd
+
+import (
+	"crypto/tls"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/probe"
+)
+
+// checkCopyFromURLSyntax - verifies the arguments of cp --from-url, the
+// sources are the HTTP(S) URLs of files on any web server.
+func checkCopyFromURLSyntax(ctx *cli.Context) {
+	if len(ctx.Args()) < 2 {
+		cli.ShowCommandHelpAndExit(ctx, "cp", 1) // last argument is exit code.
+	}
+	if ctx.Bool("archive") || ctx.Bool("extract") {
+		fatalIf(errInvalidArgument(), "--from-url cannot be used with --archive or --extract.")
+	}
+	if ctx.Bool("recursive") || ctx.Bool("continue") {
+		fatalIf(errInvalidArgument(), "--from-url cannot be used with --recursive or --continue.")
+	}
+	args := ctx.Args()
+	for _, sourceURL := range args[:len(args)-1] {
+		if !urlRgx.MatchString(sourceURL) {
+			fatalIf(errInvalidArgument().Trace(sourceURL), "--from-url sources must be http:// or https:// URLs.")
+		}
+	}
+}
+
+// urlSourceTransport - the transport of requests to web servers, with
+// the TLS, proxy and user agent settings of requests to hosts.
+func urlSourceTransport(urlStr string) (http.RoundTripper, *probe.Error) {
+	config := newS3Config(urlStr, &hostConfigV9{})
+	dialContext, err := newDialContext(config)
+	if err != nil {
+		return nil, err.Trace(urlStr)
+	}
+	var transport http.RoundTripper = &http.Transport{
+		Proxy:                 transportProxy(config),
+		DialContext:           dialContext,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+		TLSClientConfig:       &tls.Config{RootCAs: globalRootCAs, InsecureSkipVerify: config.Insecure},
+	}
+	if config.UserAgent != "" {
+		transport = userAgentTransport{userAgent: config.UserAgent, transport: transport}
+	}
+	return transport, nil
+}
+
+// getURLSourceStream - returns the body of a GET of urlStr, redirects
+// followed. The size is -1 if the server does not send Content-Length,
+// such sources are uploaded as multipart uploads of unknown size.
+func getURLSourceStream(urlStr string) (reader io.ReadCloser, size int64, contentType string, err *probe.Error) {
+	transport, err := urlSourceTransport(urlStr)
+	if err != nil {
+		return nil, 0, "", err
+	}
+	resp, e := (&http.Client{Transport: transport}).Get(urlStr)
+	if e != nil {
+		return nil, 0, "", probe.NewError(e)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		resp.Body.Close()
+		return nil, 0, "", probe.NewError(URLSourceStatus{URL: urlStr, Status: resp.Status})
+	}
+	return resp.Body, resp.ContentLength, resp.Header.Get("Content-Type"), nil
+}
+
+// urlSourceName - the name of the object copied from urlStr, the last
+// segment of its path.
+func urlSourceName(urlStr string) string {
+	u, e := url.Parse(urlStr)
+	if e != nil {
+		return ""
+	}
+	if name := path.Base(u.Path); name != "/" && name != "." {
+		return name
+	}
+	return ""
+}
+
+// urlSourceCopy - streams the files at sourceURLs to targetURL, nothing
+// is staged on disk. The target is a folder under which the files are
+// named by their URL with several sources, or if it is one already.
+func urlSourceCopy(sourceURLs []string, targetURL string, encKeyDB map[string][]prefixSSEPair) *probe.Error {
+	tgtAlias, _ := url2Alias(targetURL)
+	if len(sourceURLs) > 1 || isAliasURLDir(targetURL, encKeyDB) {
+		if !strings.HasSuffix(targetURL, "/") {
+			targetURL += "/"
+		}
+	}
+	for _, sourceURL := range sourceURLs {
+		objectURL := targetURL
+		if strings.HasSuffix(targetURL, "/") {
+			name := urlSourceName(sourceURL)
+			if name == "" {
+				return errInvalidArgument().Trace(sourceURL, targetURL)
+			}
+			objectURL += name
+		}
+		reader, size, contentType, err := getURLSourceStream(sourceURL)
+		if err != nil {
+			return err.Trace(sourceURL)
+		}
+		// The content type sent by the server is kept, unless it is
+		// unknown and guessed from the name as usual.
+		var metadata map[string]string
+		if contentType != "" && contentType != "application/octet-stream" {
+			metadata = map[string]string{"Content-Type": contentType}
+		}
+		n, err := putTargetStreamWithURL(objectURL, reader, size, metadata, getSSE(objectURL, encKeyDB[tgtAlias]))
+		reader.Close()
+		if err != nil {
+			return err.Trace(sourceURL, objectURL)
+		}
+		printMsg(copyMessage{Source: sourceURL, Target: objectURL, Size: n})
+	}
+	return nil
+}
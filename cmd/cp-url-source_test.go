@@ -0,0 +1,103 @@
+/*
+ * MinIO Client (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package cmd
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/dustin/go-humanize"
+	"github.com/fatih/color"
+)
+
+func TestCopyFromURL(t *testing.T) {
+	savedQuiet, savedOutput, savedPartSize := globalQuiet, color.Output, globalPartSize
+	defer func() {
+		globalQuiet, color.Output, globalPartSize = savedQuiet, savedOutput, savedPartSize
+	}()
+	globalQuiet, color.Output = true, ioutil.Discard
+	// Sources of unknown size are multipart uploads.
+	globalPartSize = 5 * humanize.MiByte
+
+	iso := bytes.Repeat([]byte("iso image "), 100000)
+	notes := bytes.Repeat([]byte("release notes\n"), 1000)
+	web := http.NewServeMux()
+	web.Handle("/latest/file.iso", http.RedirectHandler("/releases/file.iso", http.StatusFound))
+	web.HandleFunc("/releases/file.iso", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.Header().Set("Content-Length", strconv.Itoa(len(iso)))
+		w.Write(iso)
+	})
+	// Sent chunked, without Content-Length.
+	web.HandleFunc("/releases/notes.txt", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		for i := 0; i < len(notes); i += len(notes) / 4 {
+			w.Write(notes[i : i+len(notes)/4])
+			w.(http.Flusher).Flush()
+		}
+	})
+	webServer := httptest.NewServer(web)
+	defer webServer.Close()
+
+	objects := newFakeS3Server(nil)
+	server := httptest.NewServer(objects)
+	defer server.Close()
+	host := testHost(server.URL)
+	host.API = "S3v2"
+	defer withTestConfig(map[string]hostConfigV9{"myminio": host})()
+
+	testCases := []struct {
+		sources  []string
+		target   string
+		expected map[string][]byte
+	}{
+		{[]string{"/latest/file.iso"}, "myminio/bucket/", map[string][]byte{"bucket/file.iso": iso}},
+		{[]string{"/releases/file.iso"}, "myminio/bucket/renamed.iso", map[string][]byte{"bucket/renamed.iso": iso}},
+		{[]string{"/releases/file.iso", "/releases/notes.txt"}, "myminio/bucket/v1", map[string][]byte{"bucket/v1/file.iso": iso, "bucket/v1/notes.txt": notes}},
+	}
+	for i, testCase := range testCases {
+		var sourceURLs []string
+		for _, source := range testCase.sources {
+			sourceURLs = append(sourceURLs, webServer.URL+source)
+		}
+		if err := urlSourceCopy(sourceURLs, testCase.target, nil); err != nil {
+			t.Fatalf("Test %d: %v", i+1, err)
+		}
+		for key, data := range testCase.expected {
+			if !bytes.Equal(objects.objects[key], data) {
+				t.Fatalf("Test %d: expected %d bytes in %s, got %d", i+1, len(data), key, len(objects.objects[key]))
+			}
+		}
+	}
+	if objects.multiparts != 1 {
+		t.Fatalf("Expected the source of unknown size to be a multipart upload, got %d", objects.multiparts)
+	}
+	if contentType := objects.headers["bucket/v1/notes.txt"].Get("Content-Type"); contentType != "text/plain; charset=utf-8" {
+		t.Fatalf("Expected the content type of the server, got %q", contentType)
+	}
+
+	err := urlSourceCopy([]string{webServer.URL + "/releases/missing.iso"}, "myminio/bucket/", nil)
+	if status, ok := err.ToGoError().(URLSourceStatus); !ok || status.Status != "404 Not Found" {
+		t.Fatalf("Expected the status of the server, got %v", err)
+	}
+	if _, ok := objects.objects["bucket/missing.iso"]; ok {
+		t.Fatal("Expected nothing to be uploaded from a missing source")
+	}
+}
@@ -74,6 +74,46 @@ func checkCopySyntax(ctx *cli.Context, encKeyDB map[string][]prefixSSEPair) {
 		fatalIf(errInvalidArgument().Trace(), "Unable to guess the type of copy operation.")
 	}
 
+	// A slice can only be copied out of a single source object.
+	offset, length, err := parseByteRange(ctx.String("offset"), ctx.String("length"))
+	fatalIf(err, "Unable to parse --offset and --length.")
+	if isByteRange(offset, length) {
+		if copyURLsType != copyURLsTypeA && copyURLsType != copyURLsTypeB || len(srcURLs) != 1 {
+			fatalIf(errInvalidArgument().Trace(srcURLs...), "--offset and --length require a single source object.")
+		}
+		if ctx.Bool("continue") {
+			fatalIf(errInvalidArgument().Trace(), "--offset and --length cannot be used with --continue.")
+		}
+	}
+
+	// Objects are flattened into a local folder only, folders of keys
+	// are needed to tell objects apart on object storage.
+	if ctx.Bool("flatten") {
+		if !isRecursive {
+			fatalIf(errInvalidArgument().Trace(), "--flatten requires --recursive.")
+		}
+		if ctx.Bool("preserve-empty-dirs") {
+			fatalIf(errInvalidArgument().Trace(), "--flatten cannot be used with --preserve-empty-dirs.")
+		}
+		if clnt, err := newClient(tgtURL); err != nil || clnt.GetURL().Type != fileSystem {
+			fatalIf(errInvalidArgument().Trace(tgtURL), "--flatten requires a local target folder.")
+		}
+	}
+
+	if ctx.String("manifest") != "" && !isRecursive {
+		fatalIf(errInvalidArgument().Trace(), "--manifest requires --recursive.")
+	}
+
+	if ctx.Bool("hook-strict") && ctx.String("on-complete") == "" {
+		fatalIf(errInvalidArgument().Trace(), "--hook-strict requires --on-complete.")
+	}
+
+	// The object moved into place by --atomic would be copied over
+	// an existing object.
+	if ctx.Bool("atomic") && ctx.Bool("if-not-exists") {
+		fatalIf(errInvalidArgument().Trace(), "--atomic cannot be used with --if-not-exists.")
+	}
+
 	// Preserve functionality not supported for windows
 	if ctx.Bool("preserve") && runtime.GOOS == "windows" {
 		fatalIf(errInvalidArgument().Trace(), "Permissions are not preserved on windows platform.")
@@ -17,10 +17,17 @@
 package cmd
 
 import (
+	"fmt"
+	"io"
+	"os"
 	"path/filepath"
+	"sort"
 	"strings"
+	"sync/atomic"
 
+	json "github.com/minio/mc/pkg/colorjson"
 	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/minio/pkg/console"
 )
 
 type copyURLsType uint8
@@ -144,12 +151,15 @@ func makeCopyContentTypeB(sourceAlias string, sourceContent *clientContent, targ
 	// All OK.. We can proceed. Type B: source is a file, target is a folder and exists.
 	targetURLParse := newClientURL(targetURL)
 	targetURLParse.Path = filepath.ToSlash(filepath.Join(targetURLParse.Path, filepath.Base(sourceContent.URL.Path)))
+	if err := checkTargetPath(targetURL, targetURLParse.String()); err != nil {
+		return URLs{Error: err.Trace(sourceContent.URL.String())}
+	}
 	return makeCopyContentTypeA(sourceAlias, sourceContent, targetAlias, targetURLParse.String(), encKeyDB)
 }
 
 // SINGLE SOURCE - Type C: copy(d1..., d2) -> []copy(d1/f, d1/d2/f) -> []A
 // prepareCopyRecursiveURLTypeC - prepares target and source clientURLs for copying.
-func prepareCopyURLsTypeC(sourceURL, targetURL string, isRecursive bool, encKeyDB map[string][]prefixSSEPair) <-chan URLs {
+func prepareCopyURLsTypeC(sourceURL, targetURL string, isRecursive, preserveEmptyDirs bool, encKeyDB map[string][]prefixSSEPair) <-chan URLs {
 	// Extract alias before fiddling with the clientURL.
 	sourceAlias, _, _ := mustExpandAlias(sourceURL)
 	// Find alias and expanded clientURL.
@@ -172,6 +182,13 @@ func prepareCopyURLsTypeC(sourceURL, targetURL string, isRecursive bool, encKeyD
 				continue
 			}
 
+			if preserveEmptyDirs && isEmptyDirMarker(sourceContent) {
+				// Recreate the directory instead of downloading the marker.
+				sourceContent.Type = os.ModeDir
+				copyURLsCh <- makeCopyContentTypeC(sourceAlias, sourceClient.GetURL(), sourceContent, targetAlias, targetURL, encKeyDB)
+				continue
+			}
+
 			if !sourceContent.Type.IsRegular() {
 				// Source is not a regular file. Skip it for copy.
 				continue
@@ -180,10 +197,43 @@ func prepareCopyURLsTypeC(sourceURL, targetURL string, isRecursive bool, encKeyD
 			// All OK.. We can proceed. Type B: source is a file, target is a folder and exists.
 			copyURLsCh <- makeCopyContentTypeC(sourceAlias, sourceClient.GetURL(), sourceContent, targetAlias, targetURL, encKeyDB)
 		}
+
+		if !preserveEmptyDirs || !isRecursive || sourceClient.GetURL().Type != fileSystem {
+			return
+		}
+		// Listings of files skip directories, list the empty ones on their own.
+		for sourceContent := range sourceClient.List(isRecursive, isIncomplete, false, DirFirst) {
+			if sourceContent.Err != nil || !sourceContent.Type.IsDir() || !isEmptyDir(sourceContent.URL.Path) {
+				continue
+			}
+			sourceContent.URL.Path = sourceContent.URL.Path + string(sourceContent.URL.Separator)
+			copyURLsCh <- makeCopyContentTypeC(sourceAlias, sourceClient.GetURL(), sourceContent, targetAlias, targetURL, encKeyDB)
+		}
 	}(sourceURL, targetURL, copyURLsCh)
 	return copyURLsCh
 }
 
+// isEmptyDirMarker - returns true if content is the marker object of an
+// empty directory. Empty directories copied with --preserve-empty-dirs
+// are kept on object storage as zero-byte objects named after the
+// directory with a trailing "/", e.g. `photos/2020/`, the way the S3
+// console creates folders. Downloading a marker recreates the directory.
+func isEmptyDirMarker(content *clientContent) bool {
+	return content.URL.Type == objectStorage && content.Size == 0 &&
+		strings.HasSuffix(content.URL.Path, string(content.URL.Separator))
+}
+
+// isEmptyDir - returns true if the local directory dirPath has no entries.
+func isEmptyDir(dirPath string) bool {
+	dir, e := os.Open(dirPath)
+	if e != nil {
+		return false
+	}
+	defer dir.Close()
+	_, e = dir.Readdirnames(1)
+	return e == io.EOF
+}
+
 // makeCopyContentTypeC - CopyURLs content for copying.
 func makeCopyContentTypeC(sourceAlias string, sourceURL clientURL, sourceContent *clientContent, targetAlias string, targetURL string, encKeyDB map[string][]prefixSSEPair) URLs {
 	newSourceURL := sourceContent.URL
@@ -194,17 +244,20 @@ func makeCopyContentTypeC(sourceAlias string, sourceURL clientURL, sourceContent
 		newSourceSuffix = strings.TrimPrefix(newSourceSuffix, sourcePrefix)
 	}
 	newTargetURL := urlJoinPath(targetURL, newSourceSuffix)
+	if err := checkTargetPath(targetURL, newTargetURL); err != nil {
+		return URLs{Error: err.Trace(sourceContent.URL.String())}
+	}
 	return makeCopyContentTypeA(sourceAlias, sourceContent, targetAlias, newTargetURL, encKeyDB)
 }
 
 // MULTI-SOURCE - Type D: copy([](f|d...), d) -> []B
 // prepareCopyURLsTypeE - prepares target and source clientURLs for copying.
-func prepareCopyURLsTypeD(sourceURLs []string, targetURL string, isRecursive bool, encKeyDB map[string][]prefixSSEPair) <-chan URLs {
+func prepareCopyURLsTypeD(sourceURLs []string, targetURL string, isRecursive, preserveEmptyDirs bool, encKeyDB map[string][]prefixSSEPair) <-chan URLs {
 	copyURLsCh := make(chan URLs)
 	go func(sourceURLs []string, targetURL string, copyURLsCh chan URLs) {
 		defer close(copyURLsCh)
 		for _, sourceURL := range sourceURLs {
-			for cpURLs := range prepareCopyURLsTypeC(sourceURL, targetURL, isRecursive, encKeyDB) {
+			for cpURLs := range prepareCopyURLsTypeC(sourceURL, targetURL, isRecursive, preserveEmptyDirs, encKeyDB) {
 				copyURLsCh <- cpURLs
 			}
 		}
@@ -212,8 +265,116 @@ func prepareCopyURLsTypeD(sourceURLs []string, targetURL string, isRecursive boo
 	return copyURLsCh
 }
 
+// flattenCopyURLs - copies the objects of cpURLsCh into the local folder
+// targetURL itself instead of the folders of their keys. Objects named
+// like one already copied get a counter appended to their name, e.g.
+// `a/report.pdf` and `b/report.pdf` are copied to `report.pdf` and
+// `report-1.pdf`.
+func flattenCopyURLs(cpURLsCh <-chan URLs, targetURL string) chan URLs {
+	flatURLsCh := make(chan URLs)
+	go func() {
+		defer close(flatURLsCh)
+		used := make(map[string]bool)
+		for cpURLs := range cpURLsCh {
+			if cpURLs.Error != nil {
+				flatURLsCh <- cpURLs
+				continue
+			}
+			sourceURL := cpURLs.SourceContent.URL
+			sourceName := sourceURL.Path[strings.LastIndex(sourceURL.Path, string(sourceURL.Separator))+1:]
+			name := sourceName
+			ext := filepath.Ext(name)
+			for i := 1; used[name]; i++ {
+				name = fmt.Sprintf("%s-%d%s", strings.TrimSuffix(sourceName, ext), i, ext)
+			}
+			used[name] = true
+			if name != sourceName {
+				errorIf(errNameCollision(sourceURL.String(), sourceName).Trace(sourceURL.String()),
+					"Renaming `%s` to `%s`.", sourceName, name)
+			}
+			cpURLs.TargetContent.URL = *newClientURL(urlJoinPath(targetURL, name))
+			flatURLsCh <- cpURLs
+		}
+	}()
+	return flatURLsCh
+}
+
+// orderCopyURLs - dispatches the URLs of cpURLsCh in the order of
+// --order. By name the URLs are streamed as they are listed, size orders
+// have to keep the whole listing in memory before the first copy starts.
+// Errors and URLs without a source, such as removals of mirror, are
+// dispatched first in the order they came in.
+func orderCopyURLs(cpURLsCh <-chan URLs, order string) <-chan URLs {
+	if order != "size-asc" && order != "size-desc" {
+		return cpURLsCh
+	}
+	orderedURLsCh := make(chan URLs)
+	go func() {
+		defer close(orderedURLsCh)
+		var sized []URLs
+		for cpURLs := range cpURLsCh {
+			if cpURLs.Error != nil || cpURLs.SourceContent == nil {
+				orderedURLsCh <- cpURLs
+				continue
+			}
+			sized = append(sized, cpURLs)
+		}
+		sort.SliceStable(sized, func(i, j int) bool {
+			if order == "size-desc" {
+				return sized[i].SourceContent.Size > sized[j].SourceContent.Size
+			}
+			return sized[i].SourceContent.Size < sized[j].SourceContent.Size
+		})
+		for _, cpURLs := range sized {
+			orderedURLsCh <- cpURLs
+		}
+	}()
+	return orderedURLsCh
+}
+
+// tooLargeMessage - notice of an object skipped by --max-object-size.
+type tooLargeMessage struct {
+	Status  string `json:"status"`
+	Source  string `json:"source"`
+	Size    int64  `json:"size"`
+	MaxSize int64  `json:"maxSize"`
+}
+
+func (m tooLargeMessage) String() string {
+	return fmt.Sprintf("Skipping `%s` of %s, larger than --max-object-size %s.", m.Source, humanizeSize(m.Size), humanizeSize(m.MaxSize))
+}
+
+func (m tooLargeMessage) JSON() string {
+	m.Status = "skipped"
+	jsonMessageBytes, e := json.MarshalIndent(m, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(jsonMessageBytes)
+}
+
+// isTooLarge - reports whether content is larger than --max-object-size.
+func isTooLarge(content *clientContent) bool {
+	return globalMaxObjectSize > 0 && content != nil && content.Size > globalMaxObjectSize
+}
+
+// skipTooLarge - reports whether content of alias is larger than
+// --max-object-size, in which case a notice is printed and the object is
+// counted as skipped. The size is the one listed, no request is sent.
+func skipTooLarge(alias string, content *clientContent) bool {
+	if !isTooLarge(content) {
+		return false
+	}
+	atomic.AddInt64(&globalSkippedTooLarge, 1)
+	if !globalQuiet && !globalJSON {
+		console.Eraseline()
+	}
+	source := filepath.ToSlash(filepath.Join(alias, content.URL.Path))
+	printMsg(tooLargeMessage{Source: source, Size: content.Size, MaxSize: globalMaxObjectSize})
+	return true
+}
+
 // prepareCopyURLs - prepares target and source clientURLs for copying.
-func prepareCopyURLs(sourceURLs []string, targetURL string, isRecursive bool, encKeyDB map[string][]prefixSSEPair, olderThan, newerThan string) chan URLs {
+func prepareCopyURLs(sourceURLs []string, targetURL string, isRecursive, preserveEmptyDirs bool, encKeyDB map[string][]prefixSSEPair, olderThan, newerThan string) chan URLs {
 	copyURLsCh := make(chan URLs)
 	go func(sourceURLs []string, targetURL string, copyURLsCh chan URLs, encKeyDB map[string][]prefixSSEPair) {
 		defer close(copyURLsCh)
@@ -226,11 +387,11 @@ func prepareCopyURLs(sourceURLs []string, targetURL string, isRecursive bool, en
 		case copyURLsTypeB:
 			copyURLsCh <- prepareCopyURLsTypeB(sourceURLs[0], targetURL, encKeyDB)
 		case copyURLsTypeC:
-			for cURLs := range prepareCopyURLsTypeC(sourceURLs[0], targetURL, isRecursive, encKeyDB) {
+			for cURLs := range prepareCopyURLsTypeC(sourceURLs[0], targetURL, isRecursive, preserveEmptyDirs, encKeyDB) {
 				copyURLsCh <- cURLs
 			}
 		case copyURLsTypeD:
-			for cURLs := range prepareCopyURLsTypeD(sourceURLs, targetURL, isRecursive, encKeyDB) {
+			for cURLs := range prepareCopyURLsTypeD(sourceURLs, targetURL, isRecursive, preserveEmptyDirs, encKeyDB) {
 				copyURLsCh <- cURLs
 			}
 		default:
@@ -252,6 +413,10 @@ func prepareCopyURLs(sourceURLs []string, targetURL string, isRecursive bool, en
 				continue
 			}
 
+			if isRecursive && cpURLs.Error == nil && skipTooLarge(cpURLs.SourceAlias, cpURLs.SourceContent) {
+				continue
+			}
+
 			finalCopyURLsCh <- cpURLs
 		}
 	}()
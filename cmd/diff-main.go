@@ -18,6 +18,7 @@ package cmd
 
 import (
 	"fmt"
+	"net/http"
 	"strings"
 
 	"github.com/fatih/color"
@@ -29,7 +30,12 @@ import (
 
 // diff specific flags.
 var (
-	diffFlags = []cli.Flag{}
+	diffFlags = []cli.Flag{
+		cli.BoolFlag{
+			Name:  "metadata",
+			Usage: "compare content type and user metadata of objects present on both sides",
+		},
+	}
 )
 
 // Compute differences in object name, size, and date between two buckets.
@@ -50,11 +56,14 @@ FLAGS:
   {{end}}
 DESCRIPTION:
   Diff only calculates differences in object name, size and time. It *DOES NOT* compare objects' contents.
+  With --metadata, the content type and user metadata of objects of the same size on both sides are
+  compared as well, which requires fetching the metadata of each of them.
 
 LEGEND:
   < - object is only in source.
   > - object is only in destination.
   ! - newer object is in source.
+  * - object differs in metadata only.
 
 EXAMPLES:
   1. Compare a local folder with a folder on Amazon S3 cloud storage.
@@ -62,6 +71,9 @@ EXAMPLES:
 
   2. Compare two folders on a local filesystem.
      {{.Prompt}} {{.HelpName}} ~/Photos /Media/Backup/Photos
+
+  3. Compare two buckets, including the content type and user metadata of their objects.
+     {{.Prompt}} {{.HelpName}} --metadata s3/mybucket play/mybucket
 `,
 }
 
@@ -89,7 +101,7 @@ func (d diffMessage) String() string {
 	case differInSize:
 		msg = console.Colorize("DiffSize", "! "+d.SecondURL)
 	case differInMetadata:
-		msg = console.Colorize("DiffMetadata", "! "+d.SecondURL)
+		msg = console.Colorize("DiffMetadata", "* "+d.SecondURL)
 	default:
 		fatalIf(errDummy().Trace(d.FirstURL, d.SecondURL),
 			"Unhandled difference between `"+d.FirstURL+"` and `"+d.SecondURL+"`.")
@@ -103,7 +115,7 @@ func (d diffMessage) JSON() string {
 	d.Status = "success"
 	diffJSONBytes, e := json.MarshalIndent(d, "", " ")
 	fatalIf(probe.NewError(e),
-		"Unable to marshal diff message `"+d.FirstURL+"`, `"+d.SecondURL+"` and `"+string(d.Diff)+"`.")
+		"Unable to marshal diff message `"+d.FirstURL+"`, `"+d.SecondURL+"` and `"+d.Diff.String()+"`.")
 	return string(diffJSONBytes)
 }
 
@@ -146,7 +158,7 @@ func checkDiffSyntax(ctx *cli.Context, encKeyDB map[string][]prefixSSEPair) {
 }
 
 // doDiffMain runs the diff.
-func doDiffMain(firstURL, secondURL string) error {
+func doDiffMain(firstURL, secondURL string, isMetadata bool) error {
 	// Source and targets are always directories
 	sourceSeparator := string(newClientURL(firstURL).Separator)
 	if !strings.HasSuffix(firstURL, sourceSeparator) {
@@ -173,8 +185,13 @@ func doDiffMain(firstURL, secondURL string) error {
 			fmt.Sprintf("Failed to diff '%s' and '%s'", firstURL, secondURL))
 	}
 
-	// Diff first and second urls.
-	for diffMsg := range objectDifference(firstClient, secondClient, firstURL, secondURL, false) {
+	// Diff first and second urls. Objects similar in their listing are
+	// needed to compare their metadata.
+	diffCh := objectDifference(firstClient, secondClient, firstURL, secondURL, false, isMetadata)
+	if isMetadata {
+		diffCh = metadataDifference(firstAlias, secondAlias, diffCh)
+	}
+	for diffMsg := range diffCh {
 		if diffMsg.Error != nil {
 			errorIf(diffMsg.Error, "Unable to calculate objects difference.")
 			// Ignore error and proceed to next object.
@@ -202,10 +219,97 @@ func mainDiff(ctx *cli.Context) error {
 	console.SetColor("DiffType", color.New(color.FgMagenta))
 	console.SetColor("DiffSize", color.New(color.FgYellow, color.Bold))
 	console.SetColor("DiffTime", color.New(color.FgYellow, color.Bold))
+	console.SetColor("DiffMetadata", color.New(color.FgBlue, color.Bold))
 
 	URLs := ctx.Args()
 	firstURL := URLs.Get(0)
 	secondURL := URLs.Get(1)
 
-	return doDiffMain(firstURL, secondURL)
+	return doDiffMain(firstURL, secondURL, ctx.Bool("metadata"))
+}
+
+// Number of concurrent HEAD requests issued while comparing the
+// metadata of objects.
+const diffMetadataWorkers = 16
+
+// userMetadataEqual returns true if the content type and user metadata,
+// as returned by Stat(), are the same. Other headers, such as the
+// dates or the ETag, are expected to differ between copies.
+func userMetadataEqual(m1, m2 map[string]string) bool {
+	userMetadata := func(m map[string]string) map[string]string {
+		user := map[string]string{"Content-Type": m["Content-Type"]}
+		for k, v := range m {
+			k = http.CanonicalHeaderKey(k)
+			if strings.HasPrefix(k, "X-Amz-Meta-") {
+				user[k] = v
+			}
+		}
+		return user
+	}
+	return metadataEqual(userMetadata(m1), userMetadata(m2))
+}
+
+// statMetadataDifference fetches the metadata of an object present on
+// both sides and reports it as differInMetadata if it differs.
+func statMetadataDifference(firstAlias, secondAlias string, diffMsg diffMessage) diffMessage {
+	stat := func(alias, objectURL string) (*clientContent, *probe.Error) {
+		clnt, err := newClientFromAlias(alias, objectURL)
+		if err != nil {
+			return nil, err.Trace(objectURL)
+		}
+		content, err := clnt.Stat(false, true, false, nil)
+		if err != nil {
+			return nil, err.Trace(objectURL)
+		}
+		return content, nil
+	}
+	firstContent, err := stat(firstAlias, diffMsg.FirstURL)
+	if err != nil {
+		return diffMessage{Error: err}
+	}
+	secondContent, err := stat(secondAlias, diffMsg.SecondURL)
+	if err != nil {
+		return diffMessage{Error: err}
+	}
+	if !userMetadataEqual(firstContent.Metadata, secondContent.Metadata) {
+		diffMsg.Diff = differInMetadata
+		diffMsg.firstContent, diffMsg.secondContent = firstContent, secondContent
+	}
+	return diffMsg
+}
+
+// metadataDifference compares the metadata of the similar objects of
+// diffCh, up to diffMetadataWorkers at once, and only passes on their
+// differences. Messages are passed on in the order of diffCh.
+func metadataDifference(firstAlias, secondAlias string, diffCh <-chan diffMessage) chan diffMessage {
+	resultCh := make(chan diffMessage, 10000)
+	pendingCh := make(chan chan diffMessage, diffMetadataWorkers)
+
+	go func() {
+		defer close(pendingCh)
+		for diffMsg := range diffCh {
+			doneCh := make(chan diffMessage, 1)
+			pendingCh <- doneCh
+			if diffMsg.Error != nil || diffMsg.Diff != differInNone {
+				doneCh <- diffMsg
+				continue
+			}
+			go func(diffMsg diffMessage) {
+				doneCh <- statMetadataDifference(firstAlias, secondAlias, diffMsg)
+			}(diffMsg)
+		}
+	}()
+
+	go func() {
+		defer close(resultCh)
+		for doneCh := range pendingCh {
+			diffMsg := <-doneCh
+			if diffMsg.Error == nil && diffMsg.Diff == differInNone {
+				continue
+			}
+			resultCh <- diffMsg
+		}
+	}()
+
+	return resultCh
 }
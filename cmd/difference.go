@@ -45,6 +45,8 @@ func (d differType) String() string {
 	switch d {
 	case differInNone:
 		return ""
+	case differInETag:
+		return "etag"
 	case differInSize:
 		return "size"
 	case differInMetadata:
@@ -96,8 +98,8 @@ func metadataEqual(m1, m2 map[string]string) bool {
 	return true
 }
 
-func objectDifference(sourceClnt, targetClnt Client, sourceURL, targetURL string, isMetadata bool) (diffCh chan diffMessage) {
-	return difference(sourceClnt, targetClnt, sourceURL, targetURL, isMetadata, true, false, DirNone)
+func objectDifference(sourceClnt, targetClnt Client, sourceURL, targetURL string, isMetadata, returnSimilar bool) (diffCh chan diffMessage) {
+	return difference(sourceClnt, targetClnt, sourceURL, targetURL, isMetadata, true, returnSimilar, DirNone)
 }
 
 func dirDifference(sourceClnt, targetClnt Client, sourceURL, targetURL string) (diffCh chan diffMessage) {
@@ -205,49 +207,31 @@ func differenceInternal(sourceClnt, targetClnt Client, sourceURL, targetURL stri
 				}
 				continue
 			}
+			diff := differInNone
 			if eTagMatch(srcCtnt, tgtCtnt) {
 				// If ETag matches, only thing that can differ is metadata.
 				if isMetadata &&
 					!metadataEqual(srcCtnt.UserMetadata, tgtCtnt.UserMetadata) &&
 					!metadataEqual(srcCtnt.Metadata, tgtCtnt.Metadata) {
 					// Regular files user requesting additional metadata to same file.
-					diffCh <- diffMessage{
-						FirstURL:      srcCtnt.URL.String(),
-						SecondURL:     tgtCtnt.URL.String(),
-						Diff:          differInMetadata,
-						firstContent:  srcCtnt,
-						secondContent: tgtCtnt,
-					}
+					diff = differInMetadata
 				}
 			} else if (srcType.IsRegular() && tgtType.IsRegular()) && srcSize != tgtSize {
 				// Regular files differing in size.
-				diffCh <- diffMessage{
-					FirstURL:      srcCtnt.URL.String(),
-					SecondURL:     tgtCtnt.URL.String(),
-					Diff:          differInSize,
-					firstContent:  srcCtnt,
-					secondContent: tgtCtnt,
-				}
+				diff = differInSize
 			} else if isMetadata &&
 				!metadataEqual(srcCtnt.UserMetadata, tgtCtnt.UserMetadata) &&
 				!metadataEqual(srcCtnt.Metadata, tgtCtnt.Metadata) {
-
 				// Regular files user requesting additional metadata to same file.
-				diffCh <- diffMessage{
-					FirstURL:      srcCtnt.URL.String(),
-					SecondURL:     tgtCtnt.URL.String(),
-					Diff:          differInMetadata,
-					firstContent:  srcCtnt,
-					secondContent: tgtCtnt,
-				}
+				diff = differInMetadata
 			}
 
-			// No differ
-			if returnSimilar {
+			// Similar objects are only sent when requested.
+			if diff != differInNone || returnSimilar {
 				diffCh <- diffMessage{
 					FirstURL:      srcCtnt.URL.String(),
 					SecondURL:     tgtCtnt.URL.String(),
-					Diff:          differInNone,
+					Diff:          diff,
 					firstContent:  srcCtnt,
 					secondContent: tgtCtnt,
 				}
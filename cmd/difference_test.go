@@ -17,6 +17,11 @@
 package cmd
 
 import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"strings"
 	"testing"
 )
 
@@ -46,3 +51,75 @@ func TestExcludeOptions(t *testing.T) {
 		}
 	}
 }
+
+func TestMetadataDifference(t *testing.T) {
+	newServer := func(headers map[string]http.Header) *httptest.Server {
+		server := newFakeS3Server(nil)
+		for key, header := range headers {
+			server.objects["bucket/"+key] = []byte("same size")
+			server.headers["bucket/"+key] = header
+		}
+		return httptest.NewServer(server)
+	}
+	first := newServer(map[string]http.Header{
+		"same.txt":     {"Content-Type": {"text/plain"}, "X-Amz-Meta-Owner": {"jane"}},
+		"type.dat":     {"Content-Type": {"application/octet-stream"}},
+		"metadata.txt": {"Content-Type": {"text/plain"}, "X-Amz-Meta-Owner": {"jane"}},
+		"only.txt":     {"Content-Type": {"text/plain"}},
+	})
+	defer first.Close()
+	second := newServer(map[string]http.Header{
+		"same.txt":     {"Content-Type": {"text/plain"}, "X-Amz-Meta-Owner": {"jane"}},
+		"type.dat":     {"Content-Type": {"image/png"}},
+		"metadata.txt": {"Content-Type": {"text/plain"}, "X-Amz-Meta-Owner": {"john"}},
+	})
+	defer second.Close()
+	defer withTestConfig(map[string]hostConfigV9{"first": testHost(first.URL), "second": testHost(second.URL)})()
+
+	diff := func(isMetadata bool) map[string]differType {
+		firstAlias, firstURL, _ := mustExpandAlias("first/bucket/")
+		secondAlias, secondURL, _ := mustExpandAlias("second/bucket/")
+		firstClient, err := newClientFromAlias(firstAlias, firstURL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		secondClient, err := newClientFromAlias(secondAlias, secondURL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		diffCh := objectDifference(firstClient, secondClient, firstURL, secondURL, false, isMetadata)
+		if isMetadata {
+			diffCh = metadataDifference(firstAlias, secondAlias, diffCh)
+		}
+		diffs := make(map[string]differType)
+		var keys []string
+		for diffMsg := range diffCh {
+			if diffMsg.Error != nil {
+				t.Fatal(diffMsg.Error)
+			}
+			key := strings.TrimPrefix(diffMsg.FirstURL, firstURL)
+			diffs[key] = diffMsg.Diff
+			keys = append(keys, key)
+		}
+		if !sort.StringsAreSorted(keys) {
+			t.Fatalf("Expected the differences in order, got %v", keys)
+		}
+		return diffs
+	}
+
+	// Objects of the same size are not compared any further by default.
+	if diffs, expected := diff(false), map[string]differType{"only.txt": differInFirst}; !reflect.DeepEqual(diffs, expected) {
+		t.Fatalf("Expected %v, got %v", expected, diffs)
+	}
+	expected := map[string]differType{
+		"metadata.txt": differInMetadata,
+		"only.txt":     differInFirst,
+		"type.dat":     differInMetadata,
+	}
+	if diffs := diff(true); !reflect.DeepEqual(diffs, expected) {
+		t.Fatalf("Expected %v, got %v", expected, diffs)
+	}
+	if msg := (diffMessage{SecondURL: "second/bucket/type.dat", Diff: differInMetadata}).String(); !strings.HasPrefix(msg, "* ") {
+		t.Fatalf("Expected metadata differences to be marked with *, got %q", msg)
+	}
+}
@@ -21,7 +21,6 @@ import (
 	"net/url"
 	"strings"
 
-	humanize "github.com/dustin/go-humanize"
 	"github.com/fatih/color"
 	"github.com/minio/cli"
 	json "github.com/minio/mc/pkg/colorjson"
@@ -80,7 +79,7 @@ type duMessage struct {
 
 // Colorized message for console printing.
 func (r duMessage) String() string {
-	humanSize := strings.Join(strings.Fields(humanize.IBytes(uint64(r.Size))), "")
+	humanSize := humanizeSizeColumn(r.Size)
 
 	return fmt.Sprintf("%s\t%s", console.Colorize("Size", humanSize),
 		console.Colorize("Prefix", r.Prefix))
@@ -190,12 +189,11 @@ func mainDu(ctx *cli.Context) error {
 	// Set color.
 	console.SetColor("Remove", color.New(color.FgGreen, color.Bold))
 
-	var duErr error
+	var result batchResult
 	for _, urlStr := range ctx.Args() {
-		if _, err := du(urlStr, depth, encKeyDB); duErr == nil {
-			duErr = err
-		}
+		_, e := du(urlStr, depth, encKeyDB)
+		result.record(e)
 	}
 
-	return duErr
+	return result.exit()
 }
@@ -0,0 +1,191 @@
+/*
+ * MinIO Client (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"syscall"
+
+	json "github.com/minio/mc/pkg/colorjson"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/minio-go/v6"
+	"github.com/minio/minio/pkg/console"
+)
+
+// Categories of the objects failed by recursive operations.
+const (
+	errorCategoryAccessDenied = "access-denied"
+	errorCategoryNotFound     = "not-found"
+	errorCategoryNetwork      = "network"
+	errorCategoryChecksum     = "checksum"
+	errorCategoryOther        = "other"
+)
+
+// Order of the categories in the summary.
+var errorCategories = []string{
+	errorCategoryAccessDenied,
+	errorCategoryNotFound,
+	errorCategoryNetwork,
+	errorCategoryChecksum,
+	errorCategoryOther,
+}
+
+// Keys listed per category in the summary.
+const errorSummaryExamples = 3
+
+// errorCategory - returns the category of the error of an object.
+func errorCategory(err *probe.Error) string {
+	e := err.ToGoError()
+	switch e.(type) {
+	case PathInsufficientPermission:
+		return errorCategoryAccessDenied
+	case PathNotFound, ObjectMissing, BucketDoesNotExist, BrokenSymlink:
+		return errorCategoryNotFound
+	case ChecksumMismatch:
+		return errorCategoryChecksum
+	case UnexpectedEOF, TransferIdleTimeout:
+		return errorCategoryNetwork
+	case minio.ErrorResponse:
+		switch minio.ToErrorResponse(e).Code {
+		case "AccessDenied", "AllAccessDisabled", "InvalidAccessKeyId", "SignatureDoesNotMatch", "AccountProblem":
+			return errorCategoryAccessDenied
+		case "NoSuchKey", "NoSuchBucket", "NoSuchUpload", "NoSuchVersion":
+			return errorCategoryNotFound
+		case "BadDigest", "InvalidDigest", "XAmzContentSHA256Mismatch":
+			return errorCategoryChecksum
+		case "RequestTimeout", "IncompleteBody":
+			return errorCategoryNetwork
+		}
+		return errorCategoryOther
+	}
+	var netErr net.Error
+	switch {
+	case os.IsPermission(e):
+		return errorCategoryAccessDenied
+	case os.IsNotExist(e):
+		return errorCategoryNotFound
+	case errors.As(e, &netErr), errors.Is(e, io.ErrUnexpectedEOF),
+		errors.Is(e, syscall.ECONNRESET), errors.Is(e, syscall.ECONNREFUSED), errors.Is(e, syscall.EPIPE):
+		return errorCategoryNetwork
+	}
+	return errorCategoryOther
+}
+
+// errorSummaryCategory - the objects failed for errors of a category.
+type errorSummaryCategory struct {
+	Category string   `json:"category"`
+	Count    int      `json:"count"`
+	Examples []string `json:"examples"`
+}
+
+// errorSummary groups the objects failed by a recursive cp, rm or
+// mirror by the category of their error, so that the cause of large
+// failed runs can be told at a glance. The errors of the objects are
+// reported as they happen, the summary is printed at the end. A nil
+// summary records nothing.
+type errorSummary struct {
+	mutex      sync.Mutex
+	categories map[string]*errorSummaryCategory
+}
+
+// newErrorSummary - returns an empty summary.
+func newErrorSummary() *errorSummary {
+	return &errorSummary{categories: make(map[string]*errorSummaryCategory)}
+}
+
+// add - records the object key failed by err.
+func (s *errorSummary) add(key string, err *probe.Error) {
+	if s == nil || err == nil {
+		return
+	}
+	category := errorCategory(err)
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	c, ok := s.categories[category]
+	if !ok {
+		c = &errorSummaryCategory{Category: category}
+		s.categories[category] = c
+	}
+	c.Count++
+	if len(c.Examples) < errorSummaryExamples {
+		c.Examples = append(c.Examples, key)
+	}
+}
+
+// message - the summary of the failed objects, by category.
+func (s *errorSummary) message() errorSummaryMessage {
+	msg := errorSummaryMessage{Status: "error"}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for _, category := range errorCategories {
+		if c, ok := s.categories[category]; ok {
+			msg.Failed += c.Count
+			msg.Errors = append(msg.Errors, *c)
+		}
+	}
+	return msg
+}
+
+// print - prints the summary, if any object failed.
+func (s *errorSummary) print() {
+	if s == nil {
+		return
+	}
+	msg := s.message()
+	if msg.Failed == 0 {
+		return
+	}
+	if globalJSON {
+		console.Println(msg.JSON())
+	} else {
+		console.Errorln(msg.String())
+	}
+}
+
+// errorSummaryMessage container for the summary of failed objects.
+type errorSummaryMessage struct {
+	Status string                 `json:"status"`
+	Failed int                    `json:"failed"`
+	Errors []errorSummaryCategory `json:"errors"`
+}
+
+// String message of the failed objects, one line per category.
+func (m errorSummaryMessage) String() string {
+	lines := []string{fmt.Sprintf("Objects failed: %d", m.Failed)}
+	for _, c := range m.Errors {
+		examples := "`" + strings.Join(c.Examples, "`, `") + "`"
+		if c.Count > len(c.Examples) {
+			examples += ", ..."
+		}
+		lines = append(lines, fmt.Sprintf("  %s: %d (%s)", c.Category, c.Count, examples))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// JSON jsonified message of the failed objects.
+func (m errorSummaryMessage) JSON() string {
+	summaryJSONBytes, e := json.MarshalIndent(m, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(summaryJSONBytes)
+}
@@ -0,0 +1,85 @@
+/*
+ * MinIO Client (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"reflect"
+	"syscall"
+	"testing"
+
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/minio-go/v6"
+)
+
+func TestErrorSummary(t *testing.T) {
+	failures := []struct {
+		key string
+		e   error
+	}{
+		{"s3/bucket/private/1", minio.ErrorResponse{Code: "AccessDenied", StatusCode: 403}},
+		{"/data/secret", PathInsufficientPermission{Path: "/data/secret"}},
+		{"s3/bucket/private/2", minio.ErrorResponse{Code: "AccessDenied", StatusCode: 403}},
+		{"s3/bucket/private/3", minio.ErrorResponse{Code: "SignatureDoesNotMatch", StatusCode: 403}},
+		{"s3/bucket/private/4", minio.ErrorResponse{Code: "AccessDenied", StatusCode: 403}},
+		{"/data/gone", PathNotFound{Path: "/data/gone"}},
+		{"s3/bucket/gone", minio.ErrorResponse{Code: "NoSuchKey", StatusCode: 404}},
+		{"s3/bucket/reset", &url.Error{Op: "Put", URL: "https://s3", Err: &net.OpError{Op: "read", Err: syscall.ECONNRESET}}},
+		{"s3/bucket/refused", fmt.Errorf("dial: %w", syscall.ECONNREFUSED)},
+		{"s3/bucket/etag", ChecksumMismatch{Checksum: "ETag", Expected: "a", Got: "b"}},
+		{"s3/bucket/digest", minio.ErrorResponse{Code: "BadDigest", StatusCode: 400}},
+		{"s3/bucket/locked", ObjectLocked{Object: "locked"}},
+		{"s3/bucket/unknown", errors.New("unknown")},
+	}
+	summary := newErrorSummary()
+	for _, failure := range failures {
+		summary.add(failure.key, probe.NewError(failure.e).Trace(failure.key))
+	}
+	// Nil errors and nil summaries record nothing.
+	summary.add("s3/bucket/ok", nil)
+	var noSummary *errorSummary
+	noSummary.add("s3/bucket/none", probe.NewError(errors.New("none")))
+	noSummary.print()
+
+	expected := errorSummaryMessage{
+		Status: "error",
+		Failed: len(failures),
+		Errors: []errorSummaryCategory{
+			{errorCategoryAccessDenied, 5, []string{"s3/bucket/private/1", "/data/secret", "s3/bucket/private/2"}},
+			{errorCategoryNotFound, 2, []string{"/data/gone", "s3/bucket/gone"}},
+			{errorCategoryNetwork, 2, []string{"s3/bucket/reset", "s3/bucket/refused"}},
+			{errorCategoryChecksum, 2, []string{"s3/bucket/etag", "s3/bucket/digest"}},
+			{errorCategoryOther, 2, []string{"s3/bucket/locked", "s3/bucket/unknown"}},
+		},
+	}
+	msg := summary.message()
+	if !reflect.DeepEqual(msg, expected) {
+		t.Fatalf("Expected %+v, got %+v", expected, msg)
+	}
+
+	expectedString := "Objects failed: 13\n" +
+		"  access-denied: 5 (`s3/bucket/private/1`, `/data/secret`, `s3/bucket/private/2`, ...)\n" +
+		"  not-found: 2 (`/data/gone`, `s3/bucket/gone`)\n" +
+		"  network: 2 (`s3/bucket/reset`, `s3/bucket/refused`)\n" +
+		"  checksum: 2 (`s3/bucket/etag`, `s3/bucket/digest`)\n" +
+		"  other: 2 (`s3/bucket/locked`, `s3/bucket/unknown`)"
+	if msg.String() != expectedString {
+		t.Fatalf("Expected %q, got %q", expectedString, msg.String())
+	}
+}
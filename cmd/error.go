@@ -117,7 +117,9 @@ func exitStatus(status int) error {
 	return cli.NewExitError("", status)
 }
 
-// errorIf synonymous with fatalIf but doesn't exit on error != nil
+// errorIf synonymous with fatalIf but doesn't exit on error != nil,
+// JSON errors are printed on a single line so that they do not break
+// streams of JSON lines like listings.
 func errorIf(err *probe.Error, msg string, data ...interface{}) {
 	if err == nil {
 		return
@@ -135,13 +137,13 @@ func errorIf(err *probe.Error, msg string, data ...interface{}) {
 		if globalDebug {
 			errorMsg.CallTrace = err.CallTrace
 		}
-		json, e := json.MarshalIndent(struct {
+		json, e := json.Marshal(struct {
 			Status string       `json:"status"`
 			Error  errorMessage `json:"error"`
 		}{
 			Status: "error",
 			Error:  errorMsg,
-		}, "", " ")
+		})
 		if e != nil {
 			console.Fatalln(probe.NewError(e))
 		}
@@ -0,0 +1,381 @@
+/*
+ * MinIO Client (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"crypto/md5"
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// fakeS3Server serves buckets from memory to the tests, the objects
+// keyed by their path "bucket/key". The buckets made and those holding
+// objects exist, "bucket" is made by newFakeS3Server. Listings with
+// a prefix and delimiter, server side copies, deletes, byte ranges
+// and multipart uploads are supported, completed multipart uploads are
+// counted. The content type, content encoding, caching headers, storage
+// class and user metadata of uploads are kept in headers if set, copies
+// keep those of their source unless replaced. The ETag of every object
+// is "etag", or the MD5 of its data with md5ETags. HEAD requests of the
+// objects in delays are answered after their delay.
+type fakeS3Server struct {
+	mutex      sync.Mutex
+	buckets    map[string]bool
+	objects    map[string][]byte
+	headers    map[string]http.Header
+	delays     map[string]time.Duration
+	md5ETags   bool
+	uploads    map[string]*fakeUpload
+	multiparts int
+}
+
+// fakeUpload is a multipart upload in progress.
+type fakeUpload struct {
+	objectPath string
+	header     http.Header
+	parts      map[int][]byte
+}
+
+// newFakeS3Server - returns a server holding objects, keeping the
+// headers of uploads.
+func newFakeS3Server(objects map[string][]byte) *fakeS3Server {
+	if objects == nil {
+		objects = map[string][]byte{}
+	}
+	return &fakeS3Server{buckets: map[string]bool{"bucket": true}, objects: objects, headers: map[string]http.Header{}}
+}
+
+// bucketExists - returns whether bucket was made or holds objects.
+func (s *fakeS3Server) bucketExists(bucket string) bool {
+	if s.buckets[bucket] {
+		return true
+	}
+	for objectPath := range s.objects {
+		if strings.HasPrefix(objectPath, bucket+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// object - returns the data of the object at objectPath.
+func (s *fakeS3Server) object(objectPath string) ([]byte, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	data, ok := s.objects[objectPath]
+	return data, ok
+}
+
+// header - returns the headers kept for the object at objectPath.
+func (s *fakeS3Server) header(objectPath string) http.Header {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.headers[objectPath]
+}
+
+func (s *fakeS3Server) etag(data []byte) string {
+	if s.md5ETags {
+		return fmt.Sprintf(`"%x"`, md5.Sum(data))
+	}
+	return `"etag"`
+}
+
+// uploadedHeaders - returns the headers kept of an upload.
+func uploadedHeaders(header http.Header) http.Header {
+	kept := http.Header{}
+	for name, values := range header {
+		switch {
+		case name == "Content-Type", name == "Cache-Control", name == "Expires", name == "Content-Encoding",
+			name == "X-Amz-Storage-Class", strings.HasPrefix(name, "X-Amz-Meta-"):
+			kept[name] = values
+		}
+	}
+	return kept
+}
+
+func (s *fakeS3Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	if _, ok := query["location"]; ok {
+		w.Write([]byte(`<LocationConstraint xmlns="http://s3.amazonaws.com/doc/2006-03-01/"></LocationConstraint>`))
+		return
+	}
+	if _, ok := query["policy"]; ok {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`<Error><Code>NoSuchBucketPolicy</Code><Message>The bucket policy does not exist</Message></Error>`))
+		return
+	}
+	objectPath := strings.TrimPrefix(r.URL.Path, "/")
+	bucket := splitStr(objectPath, "/", 2)[0]
+	if r.Method == http.MethodHead {
+		time.Sleep(s.delays[objectPath])
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	isBucket := strings.TrimSuffix(objectPath, "/") == bucket
+	if !s.bucketExists(bucket) && !(isBucket && r.Method == http.MethodPut) {
+		w.WriteHeader(http.StatusNotFound)
+		if r.Method != http.MethodHead {
+			w.Write([]byte("<Error><Code>NoSuchBucket</Code><Message>The specified bucket does not exist</Message></Error>"))
+		}
+		return
+	}
+	_, isDelete := query["delete"]
+	switch {
+	case isBucket && r.Method == http.MethodGet:
+		s.list(w, bucket, query.Get("prefix"), query.Get("delimiter"))
+	case isBucket && r.Method == http.MethodPost && isDelete:
+		var request struct {
+			Objects []struct {
+				Key string
+			} `xml:"Object"`
+		}
+		xml.NewDecoder(r.Body).Decode(&request)
+		result := "<DeleteResult>"
+		for _, object := range request.Objects {
+			delete(s.objects, bucket+"/"+object.Key)
+			delete(s.headers, bucket+"/"+object.Key)
+			result += "<Deleted><Key>" + object.Key + "</Key></Deleted>"
+		}
+		w.Write([]byte(result + "</DeleteResult>"))
+	case isBucket && r.Method == http.MethodPut:
+		if s.buckets == nil {
+			s.buckets = map[string]bool{}
+		}
+		s.buckets[bucket] = true
+	case isBucket && r.Method == http.MethodDelete:
+		for objectPath := range s.objects {
+			if strings.HasPrefix(objectPath, bucket+"/") {
+				w.WriteHeader(http.StatusConflict)
+				w.Write([]byte("<Error><Code>BucketNotEmpty</Code><Message>The bucket you tried to delete is not empty</Message></Error>"))
+				return
+			}
+		}
+		delete(s.buckets, bucket)
+		w.WriteHeader(http.StatusNoContent)
+	case isBucket:
+		// Found.
+	case r.Method == http.MethodPost && func() bool { _, ok := query["uploads"]; return ok }():
+		if s.uploads == nil {
+			s.uploads = map[string]*fakeUpload{}
+		}
+		uploadID := "upload" + strconv.Itoa(len(s.uploads)+1)
+		s.uploads[uploadID] = &fakeUpload{objectPath: objectPath, header: uploadedHeaders(r.Header), parts: map[int][]byte{}}
+		w.Write([]byte("<InitiateMultipartUploadResult><UploadId>" + uploadID + "</UploadId></InitiateMultipartUploadResult>"))
+	case query.Get("uploadId") != "":
+		s.multipart(w, r, query)
+	case r.Method == http.MethodPut && r.Header.Get("X-Amz-Copy-Source") != "":
+		source, _ := url.PathUnescape(strings.TrimPrefix(r.Header.Get("X-Amz-Copy-Source"), "/"))
+		data, ok := s.objects[source]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte("<Error><Code>NoSuchKey</Code><Message>The specified key does not exist.</Message></Error>"))
+			return
+		}
+		s.objects[objectPath] = append([]byte{}, data...)
+		if s.headers != nil {
+			header := uploadedHeaders(r.Header)
+			if r.Header.Get("X-Amz-Metadata-Directive") != "REPLACE" {
+				header = uploadedHeaders(s.headers[source])
+				delete(header, "X-Amz-Storage-Class")
+				if storageClass := r.Header.Get("X-Amz-Storage-Class"); storageClass != "" {
+					header.Set("X-Amz-Storage-Class", storageClass)
+				}
+			}
+			s.headers[objectPath] = header
+		}
+		w.Write([]byte(`<CopyObjectResult><ETag>` + s.etag(data) + `</ETag><LastModified>2019-05-21T18:24:21.000Z</LastModified></CopyObjectResult>`))
+	case r.Method == http.MethodPut:
+		data, _ := ioutil.ReadAll(r.Body)
+		if strings.HasPrefix(r.Header.Get("X-Amz-Content-Sha256"), "STREAMING-") {
+			data = decodeAWSChunked(data)
+		}
+		s.objects[objectPath] = data
+		if s.headers != nil {
+			s.headers[objectPath] = uploadedHeaders(r.Header)
+		}
+		w.Header().Set("ETag", s.etag(data))
+	case r.Method == http.MethodDelete:
+		delete(s.objects, objectPath)
+		delete(s.headers, objectPath)
+		w.WriteHeader(http.StatusNoContent)
+	case r.Method == http.MethodGet || r.Method == http.MethodHead:
+		data, ok := s.objects[objectPath]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		for name, values := range s.headers[objectPath] {
+			w.Header()[name] = values
+		}
+		w.Header().Set("ETag", s.etag(data))
+		w.Header().Set("Last-Modified", "Tue, 21 May 2019 18:24:21 GMT")
+		status := http.StatusOK
+		if rng := r.Header.Get("Range"); strings.HasPrefix(rng, "bytes=") {
+			start, end, ok := parseFakeRange(strings.TrimPrefix(rng, "bytes="), len(data))
+			if !ok {
+				w.WriteHeader(http.StatusRequestedRangeNotSatisfiable)
+				w.Write([]byte("<Error><Code>InvalidRange</Code><Message>The requested range is not satisfiable</Message></Error>"))
+				return
+			}
+			w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, end-1, len(data)))
+			data, status = data[start:end], http.StatusPartialContent
+		}
+		w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+		w.WriteHeader(status)
+		if r.Method == http.MethodGet {
+			w.Write(data)
+		}
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// multipart - answers the part uploads, completion and abort of the
+// multipart upload of query.
+func (s *fakeS3Server) multipart(w http.ResponseWriter, r *http.Request, query url.Values) {
+	upload, ok := s.uploads[query.Get("uploadId")]
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("<Error><Code>NoSuchUpload</Code><Message>The specified upload does not exist.</Message></Error>"))
+		return
+	}
+	switch r.Method {
+	case http.MethodPut:
+		partNumber, _ := strconv.Atoi(query.Get("partNumber"))
+		data, _ := ioutil.ReadAll(r.Body)
+		if strings.HasPrefix(r.Header.Get("X-Amz-Content-Sha256"), "STREAMING-") {
+			data = decodeAWSChunked(data)
+		}
+		upload.parts[partNumber] = data
+		w.Header().Set("ETag", `"etag`+query.Get("partNumber")+`"`)
+	case http.MethodPost:
+		var partNumbers []int
+		for partNumber := range upload.parts {
+			partNumbers = append(partNumbers, partNumber)
+		}
+		sort.Ints(partNumbers)
+		var data []byte
+		for _, partNumber := range partNumbers {
+			data = append(data, upload.parts[partNumber]...)
+		}
+		s.objects[upload.objectPath] = data
+		if s.headers != nil {
+			s.headers[upload.objectPath] = upload.header
+		}
+		delete(s.uploads, query.Get("uploadId"))
+		s.multiparts++
+		bucket := splitStr(upload.objectPath, "/", 2)[0]
+		w.Write([]byte("<CompleteMultipartUploadResult><Bucket>" + bucket + "</Bucket><Key>" + strings.TrimPrefix(upload.objectPath, bucket+"/") +
+			"</Key><ETag>" + s.etag(data) + "</ETag></CompleteMultipartUploadResult>"))
+	case http.MethodDelete:
+		delete(s.uploads, query.Get("uploadId"))
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// list - writes the listing of the objects of bucket with prefix,
+// grouped by delimiter if any.
+func (s *fakeS3Server) list(w http.ResponseWriter, bucket, prefix, delimiter string) {
+	var keys []string
+	for objectPath := range s.objects {
+		if key := strings.TrimPrefix(objectPath, bucket+"/"); key != objectPath && strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	var contents, prefixes string
+	seen := map[string]bool{}
+	for _, key := range keys {
+		if i := strings.Index(key[len(prefix):], delimiter); delimiter != "" && i >= 0 {
+			commonPrefix := key[:len(prefix)+i+1]
+			if !seen[commonPrefix] {
+				seen[commonPrefix] = true
+				prefixes += "<CommonPrefixes><Prefix>" + commonPrefix + "</Prefix></CommonPrefixes>"
+			}
+			continue
+		}
+		data := s.objects[bucket+"/"+key]
+		storageClass := s.headers[bucket+"/"+key].Get("X-Amz-Storage-Class")
+		if storageClass == "" {
+			storageClass = "STANDARD"
+		}
+		contents += fmt.Sprintf(`<Contents><Key>%s</Key><LastModified>2019-05-21T18:24:21.097Z</LastModified><ETag>%s</ETag><Size>%d</Size><StorageClass>%s</StorageClass></Contents>`,
+			key, s.etag(data), len(data), storageClass)
+	}
+	w.Write([]byte(`<ListBucketResult xmlns="http://s3.amazonaws.com/doc/2006-03-01/"><Name>` + bucket + `</Name><Prefix>` + prefix +
+		`</Prefix><MaxKeys>1000</MaxKeys><IsTruncated>false</IsTruncated>` + contents + prefixes + `</ListBucketResult>`))
+}
+
+// parseFakeRange - returns the bounds of the byte range rng of an object
+// of size, false if it starts past its end as S3 refuses.
+func parseFakeRange(rng string, size int) (start, end int, ok bool) {
+	bounds := strings.SplitN(rng, "-", 2)
+	if len(bounds) != 2 {
+		return 0, 0, false
+	}
+	if bounds[0] == "" {
+		suffix, e := strconv.Atoi(bounds[1])
+		if e != nil || size == 0 {
+			return 0, 0, false
+		}
+		if suffix > size {
+			suffix = size
+		}
+		return size - suffix, size, true
+	}
+	start, e := strconv.Atoi(bounds[0])
+	if e != nil || start >= size {
+		return 0, 0, false
+	}
+	end = size
+	if bounds[1] != "" {
+		if last, e := strconv.Atoi(bounds[1]); e == nil && last+1 < size {
+			end = last + 1
+		}
+	}
+	return start, end, true
+}
+
+// decodeAWSChunked - returns the payload of a body signed in chunks.
+func decodeAWSChunked(body []byte) []byte {
+	var payload []byte
+	for len(body) > 0 {
+		header := bytes.SplitN(body, []byte("\r\n"), 2)
+		if len(header) != 2 {
+			break
+		}
+		size, e := strconv.ParseInt(string(bytes.SplitN(header[0], []byte(";"), 2)[0]), 16, 64)
+		if e != nil || size == 0 || int64(len(header[1])) < size {
+			break
+		}
+		payload = append(payload, header[1][:size]...)
+		body = bytes.TrimPrefix(header[1][size:], []byte("\r\n"))
+	}
+	return payload
+}
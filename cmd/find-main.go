@@ -18,11 +18,10 @@ package cmd
 
 import (
 	"strings"
+	"text/template"
 
-	humanize "github.com/dustin/go-humanize"
 	"github.com/fatih/color"
 	"github.com/minio/cli"
-	"github.com/minio/mc/pkg/probe"
 	"github.com/minio/minio/pkg/console"
 )
 
@@ -77,6 +76,21 @@ var (
 			Name:  "watch",
 			Usage: "monitor a specified path for newly created object(s)",
 		},
+		cli.StringSliceFlag{
+			Name:  "metadata",
+			Usage: "match all objects with metadata key=value, can be repeated",
+		},
+		cli.StringFlag{
+			Name:  "storage-class",
+			Usage: "match all objects stored with the specified storage class",
+		},
+		cli.StringFlag{
+			Name:  "content-type",
+			Usage: "match all objects with content type matching wildcard pattern",
+		},
+		listParallelFlag,
+		templateFlag,
+		nullFlag,
 	}
 )
 
@@ -105,6 +119,12 @@ UNITS
   --older-than, --newer-than flags accept the string for days, hours and minutes 
   i.e. 1d2h30m states 1 day, 2 hours and 30 minutes.
 
+METADATA
+  --metadata, --storage-class, --content-type flags require the metadata of
+  each listed object, which is fetched with an additional HEAD request per
+  object. The --metadata key is matched against the "x-amz-meta-" prefixed
+  user metadata of the object.
+
 FORMAT
   Support string substitutions with special interpretations for following keywords.
   Keywords supported if target is filesystem or object storage:
@@ -150,6 +170,21 @@ EXAMPLES:
 
   10. List all objects up to 3 levels sub-directory deep under "s3/bucket".
       {{.Prompt}} {{.HelpName}} s3/bucket --maxdepth 3
+
+  11. Find all objects under "s3/bucket" with user metadata "project=apollo" stored in the GLACIER storage class.
+      {{.Prompt}} {{.HelpName}} s3/bucket --metadata "project=apollo" --storage-class GLACIER
+
+  12. Find all images under "s3/photos" by their content type.
+      {{.Prompt}} {{.HelpName}} s3/photos --content-type "image/*"
+
+  13. Find all logs under "s3/logs" listing 16 of its top-level prefixes at once.
+      {{.Prompt}} {{.HelpName}} s3/logs --name "*.log" --parallel 16
+
+  14. Find all objects larger than 1 GB under "s3/bucket" and print their size, modification date and name.
+      {{.Prompt}} {{.HelpName}} s3/bucket --larger 1GB --template '{{"{{"}}.Size{{"}}"}} {{"{{"}}.LastModified.Format "2006-01-02"{{"}}"}} {{"{{"}}.Key{{"}}"}}'
+
+  15. Remove all objects older than 30 days under "s3/bucket", whatever characters their names contain.
+      {{.Prompt}} {{.HelpName}} s3/bucket --older-than 30d --null | xargs -0 -n 1 mc rm
 `,
 }
 
@@ -193,11 +228,16 @@ type findContext struct {
 	regexPattern  string
 	maxDepth      uint
 	printFmt      string
+	tmpl          *template.Template
+	null          bool
 	olderThan     string
 	newerThan     string
-	largerSize    uint64
-	smallerSize   uint64
+	largerSize    int64
+	smallerSize   int64
 	watch         bool
+	metadata      map[string]string
+	storageClass  string
+	contentType   string
 
 	// Internal values
 	targetAlias   string
@@ -216,7 +256,18 @@ func mainFind(ctx *cli.Context) error {
 	encKeyDB, err := getEncKeys(ctx)
 	fatalIf(err, "Unable to parse encryption keys.")
 
+	// Templates are verified before anything is listed.
+	tmpl := parseTemplateFlag(ctx)
+	if tmpl != nil && (ctx.String("print") != "" || ctx.String("exec") != "") {
+		fatalIf(errInvalidArgument(), "--template cannot be used with --print or --exec.")
+	}
+
+	if ctx.Bool("null") && (globalJSON || tmpl != nil || ctx.String("exec") != "") {
+		fatalIf(errInvalidArgument(), "--null cannot be used with --json, --template or --exec.")
+	}
+
 	checkFindSyntax(ctx, encKeyDB)
+	setGlobalListParallel(ctx)
 
 	args := ctx.Args()
 	if !args.Present() {
@@ -237,19 +288,27 @@ func mainFind(ctx *cli.Context) error {
 		newerThan = ctx.String("newer-than")
 	}
 
-	// Use 'e' to indicate Go error, this is a convention followed in `mc`. For probe.Error we call it
-	// 'err' and regular Go error is called as 'e'.
-	var e error
-	var largerSize, smallerSize uint64
-
+	var largerSize, smallerSize int64
 	if ctx.String("larger") != "" {
-		largerSize, e = humanize.ParseBytes(ctx.String("larger"))
-		fatalIf(probe.NewError(e).Trace(ctx.String("larger")), "Unable to parse input bytes.")
+		largerSize, err = parseSize(ctx.String("larger"))
+		fatalIf(err.Trace(ctx.String("larger")), "Unable to parse --larger.")
 	}
-
 	if ctx.String("smaller") != "" {
-		smallerSize, e = humanize.ParseBytes(ctx.String("smaller"))
-		fatalIf(probe.NewError(e).Trace(ctx.String("smaller")), "Unable to parse input bytes.")
+		smallerSize, err = parseSize(ctx.String("smaller"))
+		fatalIf(err.Trace(ctx.String("smaller")), "Unable to parse --smaller.")
+	}
+
+	metadata := make(map[string]string)
+	for _, kv := range ctx.StringSlice("metadata") {
+		entry := strings.SplitN(kv, "=", 2)
+		if len(entry) != 2 || entry[0] == "" {
+			fatalIf(errInvalidArgument().Trace(kv), "Metadata should be of the form key=value.")
+		}
+		key := entry[0]
+		if len(key) > len("x-amz-meta-") && strings.EqualFold(key[:len("x-amz-meta-")], "x-amz-meta-") {
+			key = key[len("x-amz-meta-"):]
+		}
+		metadata[key] = entry[1]
 	}
 
 	targetAlias, _, hostCfg, err := expandAlias(args[0])
@@ -265,6 +324,8 @@ func mainFind(ctx *cli.Context) error {
 		maxDepth:      ctx.Uint("maxdepth"),
 		execCmd:       ctx.String("exec"),
 		printFmt:      ctx.String("print"),
+		tmpl:          tmpl,
+		null:          ctx.Bool("null"),
 		namePattern:   ctx.String("name"),
 		pathPattern:   ctx.String("path"),
 		regexPattern:  ctx.String("regex"),
@@ -274,6 +335,9 @@ func mainFind(ctx *cli.Context) error {
 		largerSize:    largerSize,
 		smallerSize:   smallerSize,
 		watch:         ctx.Bool("watch"),
+		metadata:      metadata,
+		storageClass:  ctx.String("storage-class"),
+		contentType:   ctx.String("content-type"),
 		targetAlias:   targetAlias,
 		targetURL:     args[0],
 		targetFullURL: targetFullURL,
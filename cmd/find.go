@@ -18,16 +18,17 @@ package cmd
 
 import (
 	"bytes"
+	"net/http"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"regexp"
 	"strconv"
 	"strings"
+	"sync"
 	"syscall"
 	"time"
 
-	"github.com/dustin/go-humanize"
 	"github.com/minio/mc/pkg/probe"
 	"github.com/minio/minio/pkg/console"
 
@@ -38,11 +39,16 @@ import (
 // findMessage holds JSON and string values for printing find command output.
 type findMessage struct {
 	contentMessage
+	// Keys formatted with --print are printed unquoted.
+	raw bool
 }
 
 // String calls tells the console what to print and how to print it.
 func (f findMessage) String() string {
-	return console.Colorize("Find", f.contentMessage.Key)
+	if f.raw {
+		return console.Colorize("Find", f.contentMessage.Key)
+	}
+	return console.Colorize("Find", quoteKey(f.contentMessage.Key))
 }
 
 // JSON formats output to be JSON output.
@@ -123,7 +129,7 @@ func execFind(command string) {
 	if err := cmd.Run(); err != nil {
 		console.Print(console.Colorize("FindExecErr", stderr.String()))
 		// Return exit status of the command run
-		os.Exit(getExitStatus(err))
+		exit(getExitStatus(err))
 	}
 	console.PrintC(out.String())
 }
@@ -164,6 +170,17 @@ func watchFind(ctx *findContext) {
 				continue
 			}
 
+			if hasMetadataPredicates(ctx) {
+				st, err := statFind(ctx, event.Path)
+				if err != nil {
+					errorIf(err.Trace(event.Path), "Unable to fetch metadata.")
+					continue
+				}
+				if !matchMetadata(ctx, st) {
+					continue
+				}
+			}
+
 			find(ctx, contentMessage{
 				Key:  getAliasedPath(ctx, event.Path),
 				Time: time,
@@ -229,14 +246,88 @@ func find(ctx *findContext, fileContent contentMessage) {
 	} // For all matching content
 
 	// proceed to either exec, format the output string.
+	findOutput(ctx, fileContent)
+}
+
+// Number of concurrent HEAD requests issued while matching
+// metadata predicates.
+const findMetadataWorkers = 16
+
+// hasMetadataPredicates returns true if any of the requested
+// predicates can only be evaluated with a HEAD on the object.
+func hasMetadataPredicates(ctx *findContext) bool {
+	return len(ctx.metadata) > 0 || ctx.storageClass != "" || ctx.contentType != ""
+}
+
+// matchMetadata matches the metadata of an object, as returned by
+// Stat(), with the metadata, storage class and content type predicates.
+func matchMetadata(ctx *findContext, st *clientContent) bool {
+	for k, v := range ctx.metadata {
+		if st.Metadata[http.CanonicalHeaderKey("X-Amz-Meta-"+k)] != v {
+			return false
+		}
+	}
+	if ctx.storageClass != "" {
+		storageClass := st.Metadata["X-Amz-Storage-Class"]
+		if storageClass == "" {
+			// Amazon S3 does not return the storage class
+			// header for objects in the standard class.
+			storageClass = "STANDARD"
+		}
+		if !strings.EqualFold(storageClass, ctx.storageClass) {
+			return false
+		}
+	}
+	if ctx.contentType != "" {
+		if !pathMatch(strings.ToLower(ctx.contentType), strings.ToLower(st.Metadata["Content-Type"])) {
+			return false
+		}
+	}
+	return true
+}
+
+// statFind fetches the metadata of an object found while listing.
+func statFind(ctx *findContext, objectURL string) (*clientContent, *probe.Error) {
+	clnt, err := newClientFromAlias(ctx.targetAlias, objectURL)
+	if err != nil {
+		return nil, err.Trace(objectURL)
+	}
+	return clnt.Stat(false, true, false, nil)
+}
+
+// findOutput either executes the command or prints the content
+// which matched all the input predicates.
+func findOutput(ctx *findContext, fileContent contentMessage) {
 	if ctx.execCmd != "" {
 		execFind(stringsReplace(ctx.execCmd, fileContent))
 		return
 	}
+	if ctx.tmpl != nil {
+		printTemplate(ctx.tmpl, templateItem{
+			Key:          fileContent.Key,
+			Size:         fileContent.Size,
+			LastModified: fileContent.Time,
+			ETag:         fileContent.ETag,
+			StorageClass: fileContent.storageClass,
+		})
+		return
+	}
 	if ctx.printFmt != "" {
 		fileContent.Key = stringsReplace(ctx.printFmt, fileContent)
 	}
-	printMsg(findMessage{fileContent})
+	if ctx.null {
+		printNull(fileContent.Key)
+		return
+	}
+	printMsg(findMessage{contentMessage: fileContent, raw: ctx.printFmt != ""})
+}
+
+// findMetadataItem is a listed object pending a metadata match, the
+// match is sent on matched once its metadata is fetched.
+type findMetadataItem struct {
+	objectURL   string
+	fileContent contentMessage
+	matched     chan bool
 }
 
 // doFind - find is main function body which interprets and executes
@@ -247,6 +338,41 @@ func doFind(ctx *findContext) error {
 	// following defer is a no-op.
 	defer watchFind(ctx)
 
+	// Metadata predicates require a HEAD per object, they are only
+	// issued when such a predicate is present and are spread across
+	// a pool of workers. The matches are output by a single goroutine
+	// in the order of the listing, as without the workers.
+	var metadataCh, pendingCh chan findMetadataItem
+	var wg sync.WaitGroup
+	outputDone := make(chan struct{})
+	if hasMetadataPredicates(ctx) {
+		metadataCh = make(chan findMetadataItem)
+		pendingCh = make(chan findMetadataItem, findMetadataWorkers)
+		for i := 0; i < findMetadataWorkers; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for item := range metadataCh {
+					st, err := statFind(ctx, item.objectURL)
+					if err != nil {
+						errorIf(err.Trace(item.objectURL), "Unable to fetch metadata.")
+						item.matched <- false
+						continue
+					}
+					item.matched <- matchMetadata(ctx, st)
+				}
+			}()
+		}
+		go func() {
+			defer close(outputDone)
+			for item := range pendingCh {
+				if <-item.matched {
+					findOutput(ctx, item.fileContent)
+				}
+			}
+		}()
+	}
+
 	var prevKeyName string
 
 	// iterate over all content which is within the given directory
@@ -276,9 +402,11 @@ func doFind(ctx *findContext) error {
 
 		fileKeyName := getAliasedPath(ctx, content.URL.String())
 		fileContent := contentMessage{
-			Key:  fileKeyName,
-			Time: content.Time.Local(),
-			Size: content.Size,
+			Key:          fileKeyName,
+			Time:         content.Time.Local(),
+			Size:         content.Size,
+			ETag:         strings.Trim(content.ETag, "\""),
+			storageClass: content.StorageClass,
 		}
 
 		// Match the incoming content, didn't match return.
@@ -288,16 +416,26 @@ func doFind(ctx *findContext) error {
 
 		prevKeyName = fileKeyName
 
-		// proceed to either exec, format the output string.
-		if ctx.execCmd != "" {
-			execFind(stringsReplace(ctx.execCmd, fileContent))
+		if metadataCh != nil {
+			item := findMetadataItem{
+				objectURL:   content.URL.String(),
+				fileContent: fileContent,
+				matched:     make(chan bool, 1),
+			}
+			pendingCh <- item
+			metadataCh <- item
 			continue
 		}
-		if ctx.printFmt != "" {
-			fileContent.Key = stringsReplace(ctx.printFmt, fileContent)
-		}
 
-		printMsg(findMessage{fileContent})
+		// proceed to either exec, format the output string.
+		findOutput(ctx, fileContent)
+	}
+
+	if metadataCh != nil {
+		close(metadataCh)
+		close(pendingCh)
+		wg.Wait()
+		<-outputDone
 	}
 
 	// Success, notice watch will execute in defer only if enabled and this call
@@ -341,12 +479,12 @@ func stringsReplace(args string, fileContent contentMessage) string {
 
 	// replace all instances of {size}
 	if strings.Contains(str, "{size}") {
-		str = strings.Replace(str, "{size}", humanize.IBytes(uint64(fileContent.Size)), -1)
+		str = strings.Replace(str, "{size}", humanizeSize(fileContent.Size), -1)
 	}
 
 	// replace all instances of {"size"}
 	if strings.Contains(str, `{"size"}`) {
-		str = strings.Replace(str, `{"size"}`, strconv.Quote(humanize.IBytes(uint64(fileContent.Size))), -1)
+		str = strings.Replace(str, `{"size"}`, strconv.Quote(humanizeSize(fileContent.Size)), -1)
 	}
 
 	// replace all instances of {time}
@@ -403,10 +541,10 @@ func matchFind(ctx *findContext, fileContent contentMessage) (match bool) {
 		match = !isNewer(fileContent.Time, ctx.newerThan)
 	}
 	if match && ctx.largerSize > 0 {
-		match = int64(ctx.largerSize) < fileContent.Size
+		match = ctx.largerSize < fileContent.Size
 	}
 	if match && ctx.smallerSize > 0 {
-		match = int64(ctx.smallerSize) > fileContent.Size
+		match = ctx.smallerSize > fileContent.Size
 	}
 	return match
 }
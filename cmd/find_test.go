@@ -17,10 +17,16 @@
 package cmd
 
 import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"os/exec"
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/fatih/color"
 )
 
 // Tests match find function with all supported inputs on
@@ -452,3 +458,89 @@ func TestGetExitStatus(t *testing.T) {
 		}
 	}
 }
+
+// Tests that metadata predicates only match the intended subset of objects.
+func TestFindMetadataPredicates(t *testing.T) {
+	handler := newFakeS3Server(nil)
+	for key, header := range map[string]http.Header{
+		"apollo.csv": {
+			"Content-Type":       {"text/csv"},
+			"X-Amz-Meta-Project": {"apollo"},
+		},
+		"apollo.png": {
+			"Content-Type":        {"image/png"},
+			"X-Amz-Meta-Project":  {"apollo"},
+			"X-Amz-Storage-Class": {"REDUCED_REDUNDANCY"},
+		},
+		"gemini.png": {
+			"Content-Type":       {"image/png"},
+			"X-Amz-Meta-Project": {"gemini"},
+		},
+		"untagged.txt": {
+			"Content-Type": {"text/plain"},
+		},
+	} {
+		handler.objects["bucket/"+key] = []byte("0123456789")
+		handler.headers["bucket/"+key] = header
+	}
+	// Matches are output in the order of the listing, whichever
+	// metadata is fetched first.
+	handler.delays = map[string]time.Duration{"bucket/apollo.csv": 50 * time.Millisecond}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	defer withTestConfig(map[string]hostConfigV9{"findmeta": testHost(server.URL)})()
+
+	savedJSON, savedOutput := globalJSON, color.Output
+	defer func() {
+		globalJSON, color.Output = savedJSON, savedOutput
+	}()
+	globalJSON = true
+
+	testCases := []struct {
+		metadata     map[string]string
+		storageClass string
+		contentType  string
+		expected     []string
+	}{
+		{metadata: map[string]string{"project": "apollo"}, expected: []string{"apollo.csv", "apollo.png"}},
+		{metadata: map[string]string{"Project": "gemini"}, expected: []string{"gemini.png"}},
+		{contentType: "image/*", expected: []string{"apollo.png", "gemini.png"}},
+		{storageClass: "standard", expected: []string{"apollo.csv", "gemini.png", "untagged.txt"}},
+		{metadata: map[string]string{"project": "apollo"}, storageClass: "REDUCED_REDUNDANCY", expected: []string{"apollo.png"}},
+		{metadata: map[string]string{"project": "mercury"}, expected: nil},
+	}
+
+	for i, testCase := range testCases {
+		clnt, err := newClient("findmeta/bucket/")
+		if err != nil {
+			t.Fatalf("Test %d: %v", i+1, err)
+		}
+		var out bytes.Buffer
+		color.Output = &out
+		doFind(&findContext{
+			metadata:      testCase.metadata,
+			storageClass:  testCase.storageClass,
+			contentType:   testCase.contentType,
+			targetAlias:   "findmeta",
+			targetURL:     "findmeta/bucket/",
+			targetFullURL: server.URL,
+			clnt:          clnt,
+		})
+
+		var found []string
+		for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+			if line == "" {
+				continue
+			}
+			var msg contentMessage
+			if e := json.Unmarshal([]byte(line), &msg); e != nil {
+				t.Fatalf("Test %d: unexpected output `%s`: %v", i+1, line, e)
+			}
+			found = append(found, strings.TrimPrefix(msg.Key, "findmeta/bucket/"))
+		}
+		if strings.Join(found, ",") != strings.Join(testCase.expected, ",") {
+			t.Errorf("Test %d: expected %v, got %v", i+1, testCase.expected, found)
+		}
+	}
+}
@@ -19,6 +19,9 @@ package cmd
 
 import (
 	"crypto/x509"
+	"net/url"
+	"strings"
+	"time"
 
 	"github.com/minio/cli"
 	"github.com/minio/minio/pkg/console"
@@ -39,8 +42,17 @@ const (
 	// Profile directory for dumping profiler outputs.
 	globalProfileDir = "profile"
 
+	// Trash directory for local files removed with --trash.
+	globalTrashDir = "trash"
+
+	// Manifests of the recursive copies resumed with --continue.
+	globalManifestDir = "manifest"
+
 	// Global error exit status.
 	globalErrorExitStatus = 1
+
+	// Exit status of an upload refused by --if-not-exists.
+	globalPreconditionFailedExitStatus = 2
 )
 
 var (
@@ -49,6 +61,7 @@ var (
 	globalDebug    = false // Debug flag set via command line
 	globalNoColor  = false // No Color flag set via command line
 	globalInsecure = false // Insecure flag set via command line
+	globalStats    = false // Stats flag set via command line
 
 	// WHEN YOU ADD NEXT GLOBAL FLAG, MAKE SURE TO ALSO UPDATE SESSION CODE AND CODE BELOW.
 )
@@ -59,15 +72,119 @@ var (
 
 	// CA root certificates, a nil value means system certs pool will be used
 	globalRootCAs *x509.CertPool
+
+	// Multipart part size requested with --part-size, zero means the
+	// host default or the automatically computed part size is used.
+	globalPartSize uint64
+
+	// Handling of symbolic links when walking local trees, set by
+	// commands with a --links flag. Empty keeps the historic behavior
+	// of copying links to files as regular files.
+	globalLinks linksPolicy
+
+	// Skip hidden files and folders, named with a leading dot, when
+	// walking local trees, set by --exclude-hidden.
+	globalExcludeHidden bool
+
+	// Checksum requested with --checksum-algorithm, computed while
+	// uploading and verified by the server.
+	globalChecksumAlgorithm checksumAlgorithm
+
+	// Endpoint requested with --endpoint-url, used in place of the URL
+	// of every alias. Empty means the configured URLs are used.
+	globalEndpointURL string
+
+	// Send unsigned requests, set via --no-sign-request.
+	globalAnonymous bool
+
+	// Abort transfers when no bytes flow for this long, set via
+	// --idle-timeout. Zero disables it.
+	globalIdleTimeout time.Duration
+
+	// Upload to temporary keys moved into place, set via --atomic.
+	globalAtomic bool
+
+	// Refuse to overwrite existing objects, set via --if-not-exists.
+	globalIfNotExists bool
+
+	// Verify the ETags of uploads, set via --verify.
+	globalVerify bool
+
+	// Remove objects retained in GOVERNANCE mode, set via --bypass-governance.
+	globalBypassGovernance bool
+
+	// Keep application/octet-stream for uploads of unknown extensions
+	// instead of sniffing their content, set via --no-sniff.
+	globalNoSniff bool
+
+	// Gzip uploads and gunzip downloads on the fly, set via --compress,
+	// --compress-types and --decompress.
+	globalCompress      bool
+	globalCompressTypes []string
+	globalDecompress    bool
+
+	// Use S3 transfer acceleration for object data, set via --accelerate.
+	globalAccelerate bool
+
+	// Accept the charges of requester pays buckets, set via --request-payer.
+	globalRequestPayer bool
+
+	// Break requests down into their phases, set via --profile.
+	globalProfile bool
+
+	// Connection pool limits set via --max-idle-conns and
+	// --max-conns-per-host, zero keeps the defaults.
+	globalMaxIdleConns    int
+	globalMaxConnsPerHost int
+
+	// Prefixes listed at once, set via --parallel of find and mirror.
+	globalListParallel int
+
+	// Times a whole object is copied before it is marked failed, set via
+	// --attempts of cp and mirror.
+	globalCopyAttempts int
+
+	// Objects larger than this are not copied, set via --max-object-size
+	// of cp and mirror. Zero copies objects of any size.
+	globalMaxObjectSize int64
+
+	// Objects skipped by --max-object-size, counted in the summary.
+	globalSkippedTooLarge int64
+
+	// Order the copies of a listing are dispatched in, set via --order
+	// of cp and mirror. One of name, size-asc and size-desc, empty is name.
+	globalCopyOrder string
+
+	// Proxy all connections are dialed through, set via --socks5 or
+	// a socks5 URL in ALL_PROXY.
+	globalSOCKS5Proxy *url.URL
+
+	// User-Agent replacing the one of mc, set via --user-agent.
+	globalUserAgent string
+
+	// Files of the client certificate of every host, set via
+	// --client-cert and --client-key.
+	globalClientCert string
+	globalClientKey  string
+
+	// Run without a config file, set via --no-config. The only host is
+	// built from --endpoint-url, --access and --secret.
+	globalNoConfig  bool
+	globalAccessKey string
+	globalSecretKey string
+	// Arguments of the command, whose alias --access and --secret
+	// are given for, see credentialsAlias.
+	globalCommandArgs []string
 )
 
 // Set global states. NOTE: It is deliberately kept monolithic to ensure we dont miss out any flags.
-func setGlobals(quiet, debug, json, noColor, insecure bool) {
+func setGlobals(quiet, debug, json, noColor, insecure, stats bool) {
 	globalQuiet = globalQuiet || quiet
 	globalDebug = globalDebug || debug
 	globalJSON = globalJSON || json
 	globalNoColor = globalNoColor || noColor
 	globalInsecure = globalInsecure || insecure
+	globalStats = globalStats || stats
 
 	// Enable debug messages if requested.
 	if globalDebug {
@@ -82,11 +199,70 @@ func setGlobals(quiet, debug, json, noColor, insecure bool) {
 
 // Set global states. NOTE: It is deliberately kept monolithic to ensure we dont miss out any flags.
 func setGlobalsFromContext(ctx *cli.Context) error {
+	applyAliasDefaults(ctx)
+
 	quiet := ctx.IsSet("quiet")
 	debug := ctx.IsSet("debug")
 	json := ctx.IsSet("json")
 	noColor := ctx.IsSet("no-color")
 	insecure := ctx.IsSet("insecure")
-	setGlobals(quiet, debug, json, noColor, insecure)
+	stats := ctx.IsSet("stats")
+	setGlobals(quiet, debug, json, noColor, insecure, stats)
+
+	if endpointURL := ctx.String("endpoint-url"); endpointURL != "" {
+		if !isValidHostURL(endpointURL) {
+			fatalIf(errInvalidURL(endpointURL), "Invalid `--endpoint-url`, an http or https URL without a path is expected.")
+		}
+		globalEndpointURL = strings.TrimSuffix(endpointURL, "/")
+	}
+	globalAnonymous = globalAnonymous || ctx.IsSet("no-sign-request")
+	globalAccelerate = globalAccelerate || ctx.IsSet("accelerate")
+	globalRequestPayer = globalRequestPayer || ctx.IsSet("request-payer")
+	globalProfile = globalProfile || ctx.IsSet("profile")
+	if ctx.Int("max-idle-conns") < 0 || ctx.Int("max-conns-per-host") < 0 {
+		fatalIf(errInvalidArgument(), "`--max-idle-conns` and `--max-conns-per-host` cannot be negative.")
+	}
+	if ctx.IsSet("max-idle-conns") {
+		globalMaxIdleConns = ctx.Int("max-idle-conns")
+	}
+	if ctx.IsSet("max-conns-per-host") {
+		globalMaxConnsPerHost = ctx.Int("max-conns-per-host")
+	}
+	if userAgent := ctx.String("user-agent"); userAgent != "" {
+		globalUserAgent = userAgent
+	}
+	socks5Proxy := ctx.String("socks5")
+	if socks5Proxy == "" {
+		socks5Proxy = socks5ProxyFromEnvironment()
+	}
+	if socks5Proxy != "" {
+		proxyURL, err := parseSOCKS5Proxy(socks5Proxy)
+		fatalIf(err.Trace(socks5Proxy), "Invalid SOCKS5 proxy `"+socks5Proxy+"`.")
+		globalSOCKS5Proxy = proxyURL
+	}
+
+	if certFile, keyFile := ctx.String("client-cert"), ctx.String("client-key"); certFile != "" || keyFile != "" {
+		_, err := loadClientCertificate(certFile, keyFile)
+		fatalIf(err.Trace(certFile, keyFile), "Unable to load the client certificate.")
+		globalClientCert, globalClientKey = certFile, keyFile
+	}
+
+	globalNoConfig = globalNoConfig || ctx.IsSet("no-config")
+	if accessKey := ctx.String("access"); accessKey != "" {
+		globalAccessKey = accessKey
+	}
+	if secretKey := ctx.String("secret"); secretKey != "" {
+		globalSecretKey = secretKey
+	}
+	// Flags may follow the command name, they are all known once the command is reached.
+	if ctx.Command.Name != "" {
+		if (globalAccessKey == "") != (globalSecretKey == "") {
+			fatalIf(errInvalidArgument(), "`--access` and `--secret` must be given together.")
+		}
+		globalCommandArgs = ctx.Args()
+		if globalNoConfig {
+			fatalIf(setNoConfig(), "Unable to run without a config file.")
+		}
+	}
 	return nil
 }
@@ -168,9 +168,15 @@ func mainHead(ctx *cli.Context) error {
 	}
 
 	// Convert arguments to URLs: expand alias, fix format.
+	var result batchResult
 	for _, url := range ctx.Args() {
-		fatalIf(headURL(url, encKeyDB, ctx.Int64("lines")).Trace(url), "Unable to read from `"+url+"`.")
+		if err := headURL(url, encKeyDB, ctx.Int64("lines")); err != nil {
+			errorIf(err.Trace(url), "Unable to read from `"+url+"`.")
+			result.failure()
+			continue
+		}
+		result.success()
 	}
 
-	return nil
+	return result.exit()
 }
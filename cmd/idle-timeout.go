@@ -0,0 +1,152 @@
+/*
+ * MinIO Client (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package cmd
+
+import (
+	"context"
+	"io"
+	"sync/atomic"
+	"time"
+
+	"github.com/minio/mc/pkg/probe"
+)
+
+// idleTimeout aborts a transfer once no bytes flowed for its timeout,
+// which a deadline on the whole request cannot tell from a large
+// object. The timer is reset by every read of the source and by the
+// progress of the upload, and a stalled transfer is aborted by
+// cancelling its context, which releases a read blocked on a half-open
+// connection. A nil idleTimeout never expires.
+type idleTimeout struct {
+	timeout time.Duration
+	timer   idleTimer
+	cancel  context.CancelFunc
+	expired int32
+}
+
+// idleTimer - the timer of an idleTimeout, a *time.Timer.
+type idleTimer interface {
+	Reset(d time.Duration) bool
+	Stop() bool
+}
+
+// startIdleTimer - starts the timer calling f once d elapsed, replaced
+// by tests to expire transfers without waiting.
+var startIdleTimer = func(d time.Duration, f func()) idleTimer {
+	return time.AfterFunc(d, f)
+}
+
+// newIdleTimeout - returns the context of a transfer, cancelled once
+// the transfer is idle for timeout. A timeout of zero returns ctx and
+// a nil idleTimeout.
+func newIdleTimeout(ctx context.Context, timeout time.Duration) (context.Context, *idleTimeout) {
+	if timeout <= 0 {
+		return ctx, nil
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	t := &idleTimeout{timeout: timeout, cancel: cancel}
+	t.timer = startIdleTimer(timeout, t.expire)
+	return ctx, t
+}
+
+func (t *idleTimeout) expire() {
+	atomic.StoreInt32(&t.expired, 1)
+	t.cancel()
+}
+
+// touch - restarts the timer when bytes flowed.
+func (t *idleTimeout) touch(n int) {
+	if n > 0 && atomic.LoadInt32(&t.expired) == 0 {
+		t.timer.Reset(t.timeout)
+	}
+}
+
+// stop - stops the timer once the transfer is over.
+func (t *idleTimeout) stop() {
+	if t == nil {
+		return
+	}
+	t.timer.Stop()
+	t.cancel()
+}
+
+// errOr - returns the error of an expired transfer in place of err,
+// which is only the error of its cancelled context.
+func (t *idleTimeout) errOr(err *probe.Error) *probe.Error {
+	if t == nil || atomic.LoadInt32(&t.expired) == 0 {
+		return err
+	}
+	return probe.NewError(TransferIdleTimeout{Timeout: t.timeout})
+}
+
+// reader - returns r restarting the timer on every read.
+func (t *idleTimeout) reader(r io.ReadCloser) io.ReadCloser {
+	if t == nil {
+		return r
+	}
+	if _, ok := r.(io.Seeker); ok {
+		// Downloads resumed into a partial file seek the source.
+		return &idleTimeoutReadSeeker{idleTimeoutReader{ReadCloser: r, idle: t}}
+	}
+	return &idleTimeoutReader{ReadCloser: r, idle: t}
+}
+
+// progress - returns the progress of the transfer restarting the timer,
+// reporting to progress if not nil.
+func (t *idleTimeout) progress(progress io.Reader) io.Reader {
+	if t == nil {
+		return progress
+	}
+	return &idleTimeoutProgress{progress: progress, idle: t}
+}
+
+// idleTimeoutReader - a reader restarting an idle timer.
+type idleTimeoutReader struct {
+	io.ReadCloser
+	idle *idleTimeout
+}
+
+func (r *idleTimeoutReader) Read(p []byte) (int, error) {
+	n, e := r.ReadCloser.Read(p)
+	if e != nil && e != io.EOF {
+		return n, r.idle.errOr(probe.NewError(e)).ToGoError()
+	}
+	r.idle.touch(n)
+	return n, e
+}
+
+// idleTimeoutReadSeeker - an idleTimeoutReader of a seekable source.
+type idleTimeoutReadSeeker struct {
+	idleTimeoutReader
+}
+
+func (r *idleTimeoutReadSeeker) Seek(offset int64, whence int) (int64, error) {
+	return r.ReadCloser.(io.Seeker).Seek(offset, whence)
+}
+
+// idleTimeoutProgress - a progress hook restarting an idle timer.
+type idleTimeoutProgress struct {
+	progress io.Reader
+	idle     *idleTimeout
+}
+
+func (p *idleTimeoutProgress) Read(b []byte) (int, error) {
+	p.idle.touch(len(b))
+	if p.progress == nil {
+		return len(b), nil
+	}
+	return p.progress.Read(b)
+}
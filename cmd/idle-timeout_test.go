@@ -0,0 +1,176 @@
+/*
+ * MinIO Client (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// stallObjectHandler sends the content of objects in chunks, the
+// downloads of the object "stalled" stop sending after the first
+// chunk and signal stalled.
+type stallObjectHandler struct {
+	handler *fakeS3Server
+	chunks  int
+	stalled chan struct{}
+}
+
+func (h *stallObjectHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet || r.URL.Path == "/bucket/" {
+		h.handler.ServeHTTP(w, r)
+		return
+	}
+	recorder := httptest.NewRecorder()
+	h.handler.ServeHTTP(recorder, r)
+	for name, values := range recorder.Header() {
+		w.Header()[name] = values
+	}
+	w.WriteHeader(recorder.Code)
+	body := recorder.Body.Bytes()
+	chunkSize := len(body)/h.chunks + 1
+	for len(body) > 0 {
+		n := chunkSize
+		if n > len(body) {
+			n = len(body)
+		}
+		w.Write(body[:n])
+		w.(http.Flusher).Flush()
+		body = body[n:]
+		if r.URL.Path == "/bucket/stalled" {
+			// A half-open connection, nothing is sent anymore.
+			h.stalled <- struct{}{}
+			<-r.Context().Done()
+			return
+		}
+	}
+}
+
+// manualIdleTimer - an idleTimer expired by the test only, touched is
+// signalled once it is reset.
+type manualIdleTimer struct {
+	expire  func()
+	touched chan struct{}
+}
+
+func (t *manualIdleTimer) Reset(time.Duration) bool {
+	select {
+	case t.touched <- struct{}{}:
+	default:
+	}
+	return true
+}
+
+func (t *manualIdleTimer) Stop() bool { return true }
+
+func TestCopyIdleTimeout(t *testing.T) {
+	data := bytes.Repeat([]byte("0123456789"), 100000)
+	handler := &stallObjectHandler{
+		handler: newFakeS3Server(map[string][]byte{"bucket/object": data, "bucket/stalled": data}),
+		chunks:  4,
+		stalled: make(chan struct{}, 1),
+	}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+	defer withTestConfig(map[string]hostConfigV9{"myminio": testHost(server.URL)})()
+	defer func(timeout time.Duration) { globalIdleTimeout = timeout }(globalIdleTimeout)
+	globalIdleTimeout = time.Minute
+	defer func(noSniff bool) { globalNoSniff = noSniff }(globalNoSniff)
+	globalNoSniff = true
+
+	timers := make(chan *manualIdleTimer, 1)
+	defer func(start func(time.Duration, func()) idleTimer) { startIdleTimer = start }(startIdleTimer)
+	startIdleTimer = func(_ time.Duration, expire func()) idleTimer {
+		timer := &manualIdleTimer{expire: expire, touched: make(chan struct{}, 1)}
+		timers <- timer
+		return timer
+	}
+
+	dir, e := ioutil.TempDir("", "mc-idle-")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.RemoveAll(dir)
+
+	testCases := []struct {
+		stall   bool
+		partial bool
+		success bool
+	}{
+		// Downloads restart the timer as long as bytes flow.
+		{false, false, true},
+		// Downloads resumed into a partial file still seek the source.
+		{false, true, true},
+		// Stalled downloads are aborted once the timer expires.
+		{true, false, false},
+	}
+	for i, testCase := range testCases {
+		target := filepath.Join(dir, strconv.Itoa(i))
+		if testCase.partial {
+			if e = ioutil.WriteFile(target+partSuffix, data[:len(data)/2], 0600); e != nil {
+				t.Fatal(e)
+			}
+		}
+		object := "object"
+		if testCase.stall {
+			object = "stalled"
+		}
+		urls := URLs{
+			SourceAlias:   "myminio",
+			SourceContent: &clientContent{URL: *newClientURL(server.URL + "/bucket/" + object), Size: int64(len(data))},
+			TargetContent: &clientContent{URL: *newClientURL(target)},
+		}
+
+		done := make(chan URLs)
+		go func() {
+			done <- uploadSourceToTargetURL(context.Background(), urls, newAccounter(int64(len(data))), nil)
+		}()
+		timer := <-timers
+		if testCase.stall {
+			// Expired once the first chunk is read, a request aborted
+			// before its response would be retried.
+			<-handler.stalled
+			<-timer.touched
+			timer.expire()
+		}
+		urls = <-done
+		if testCase.success {
+			if urls.Error != nil {
+				t.Fatalf("Test %d: %v", i+1, urls.Error)
+			}
+			if got, e := ioutil.ReadFile(target); e != nil || !bytes.Equal(got, data) {
+				t.Fatalf("Test %d: expected the object to be downloaded whole, got %d bytes, %v", i+1, len(got), e)
+			}
+			select {
+			case <-timer.touched:
+			default:
+				t.Fatalf("Test %d: expected the reads to restart the timer", i+1)
+			}
+			continue
+		}
+		if _, ok := urls.Error.ToGoError().(TransferIdleTimeout); !ok {
+			t.Fatalf("Test %d: expected an idle timeout error, got %v", i+1, urls.Error)
+		}
+	}
+}
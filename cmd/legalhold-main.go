@@ -0,0 +1,143 @@
+/*
+ * MinIO Client (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	json "github.com/minio/mc/pkg/colorjson"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/minio/pkg/console"
+)
+
+var (
+	legalHoldFlags = []cli.Flag{
+		cli.BoolFlag{
+			Name:  "recursive, r",
+			Usage: "apply legal hold to all objects with the given prefix",
+		},
+	}
+)
+
+var legalHoldCmd = cli.Command{
+	Name:   "legalhold",
+	Usage:  "set or clear legal hold on objects",
+	Action: mainLegalHold,
+	Before: setGlobalsFromContext,
+	Flags:  append(legalHoldFlags, globalFlags...),
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [FLAGS] [on | off] TARGET
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+EXAMPLES:
+   1. Place an object under legal hold
+     $ {{.HelpName}} on myminio/mybucket/myobject
+
+   2. Release the legal hold of all objects with a given prefix
+     $ {{.HelpName}} --recursive off myminio/mybucket/prefix
+`,
+}
+
+// Structured message depending on the type of console.
+type legalHoldMessage struct {
+	LegalHold string `json:"legalhold"`
+	URLPath   string `json:"urlpath"`
+	Status    string `json:"status"`
+}
+
+// Colorized message for console printing.
+func (m legalHoldMessage) String() string {
+	return console.Colorize("LegalHoldSuccess", fmt.Sprintf("Legal hold `%s` set on `%s`.", m.LegalHold, m.URLPath))
+}
+
+// JSON'ified message for scripting.
+func (m legalHoldMessage) JSON() string {
+	m.Status = "success"
+	msgBytes, e := json.MarshalIndent(m, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(msgBytes)
+}
+
+// setLegalHold - sets the legal hold status of one object, or of all
+// objects within a given prefix when recursive.
+func setLegalHold(urlStr, status string, recursive bool) error {
+	clnt, err := newClient(urlStr)
+	fatalIf(err.Trace(urlStr), "Cannot parse the provided url.")
+
+	// Quit early if urlStr does not point to an S3 server
+	switch clnt.(type) {
+	case *fsClient:
+		fatal(errDummy().Trace(), "Legal hold for filesystem not supported.")
+	}
+
+	if !recursive {
+		if err = clnt.PutObjectLegalHold(status); err != nil {
+			errorIf(err.Trace(urlStr), "Unable to set legal hold on `"+urlStr+"`.")
+			return exitStatus(globalErrorExitStatus)
+		}
+		printMsg(legalHoldMessage{LegalHold: status, URLPath: clnt.GetURL().Path})
+		return nil
+	}
+
+	alias, _, _ := mustExpandAlias(urlStr)
+	var cErr error
+	for content := range clnt.List(true, false, false, DirNone) {
+		if content.Err != nil {
+			errorIf(content.Err.Trace(clnt.GetURL().String()), "Unable to list folder.")
+			cErr = exitStatus(globalErrorExitStatus) // Set the exit status.
+			continue
+		}
+		newClnt, perr := newClientFromAlias(alias, content.URL.String())
+		if perr != nil {
+			errorIf(perr.Trace(content.URL.String()), "Invalid URL")
+			cErr = exitStatus(globalErrorExitStatus)
+			continue
+		}
+		if perr = newClnt.PutObjectLegalHold(status); perr != nil {
+			errorIf(perr.Trace(content.URL.String()), "Unable to set legal hold on `"+content.URL.Path+"`.")
+			cErr = exitStatus(globalErrorExitStatus)
+			continue
+		}
+		printMsg(legalHoldMessage{LegalHold: status, URLPath: content.URL.Path})
+	}
+	return cErr
+}
+
+// main for legalhold command.
+func mainLegalHold(ctx *cli.Context) error {
+	console.SetColor("LegalHoldSuccess", color.New(color.FgGreen, color.Bold))
+
+	args := ctx.Args()
+	if len(args) != 2 {
+		cli.ShowCommandHelpAndExit(ctx, "legalhold", 1)
+	}
+
+	status := strings.ToUpper(args.Get(0))
+	if status != "ON" && status != "OFF" {
+		fatalIf(errInvalidArgument().Trace(args.Get(0)), "Invalid legal hold status. Valid options are `[on, off]`.")
+	}
+
+	return setLegalHold(args.Get(1), status, ctx.Bool("recursive"))
+}
@@ -35,6 +35,28 @@ var (
 			Name:  "incomplete, I",
 			Usage: "list incomplete uploads",
 		},
+		cli.BoolFlag{
+			Name:  "summarize",
+			Usage: "print only the number and total size of the objects",
+		},
+		cli.StringFlag{
+			Name:  "format",
+			Usage: "print objects in the given format, only 'csv' is supported",
+		},
+		cli.BoolFlag{
+			Name:  "owner",
+			Usage: "print the owner of objects, blank if not reported by the server",
+		},
+		cli.BoolFlag{
+			Name:  "dirs-only",
+			Usage: "list only the folders (common prefixes) of a level",
+		},
+		cli.BoolFlag{
+			Name:  "files-only",
+			Usage: "list only the files of a level, not its folders",
+		},
+		templateFlag,
+		nullFlag,
 	}
 )
 
@@ -72,6 +94,25 @@ EXAMPLES:
 
   6. List incomplete (previously failed) uploads of objects on Amazon S3.
      {{.Prompt}} {{.HelpName}} --incomplete s3/mybucket
+
+  7. Print the number and total size of all objects under a prefix.
+     {{.Prompt}} {{.HelpName}} --summarize --recursive s3/mybucket/photos/
+
+  8. Export an inventory of all objects in mybucket as CSV.
+     {{.Prompt}} {{.HelpName}} --recursive --format csv s3/mybucket > inventory.csv
+
+  9. List the objects of a shared bucket along with their owners.
+     {{.Prompt}} {{.HelpName}} --owner s3/sharedbucket
+
+  10. List the size and name of all objects in mybucket, one per line, for scripts.
+      {{.Prompt}} {{.HelpName}} --recursive --template '{{"{{"}}.Size{{"}}"}} {{"{{"}}.Key{{"}}"}}' s3/mybucket
+
+  11. List the folders of a prefix without its files, then its files without its folders.
+      {{.Prompt}} {{.HelpName}} --dirs-only s3/mybucket/photos/
+      {{.Prompt}} {{.HelpName}} --files-only s3/mybucket/photos/
+
+  12. Print the keys of all objects in mybucket terminated by NUL, for keys with spaces or newlines.
+      {{.Prompt}} {{.HelpName}} --recursive --null s3/mybucket | xargs -0 -n 1 echo
 `,
 }
 
@@ -90,6 +131,31 @@ func checkListSyntax(ctx *cli.Context) {
 	URLs := ctx.Args()
 	isIncomplete := ctx.Bool("incomplete")
 
+	if format := ctx.String("format"); format != "" {
+		if format != "csv" {
+			fatalIf(errInvalidArgument().Trace(format), "Unsupported format `"+format+"`, only `csv` is supported.")
+		}
+		if ctx.Bool("summarize") {
+			fatalIf(errInvalidArgument().Trace(format), "--format cannot be used with --summarize.")
+		}
+	}
+	if ctx.Bool("owner") && ctx.Bool("summarize") {
+		fatalIf(errInvalidArgument(), "--owner cannot be used with --summarize.")
+	}
+	if ctx.String("template") != "" && (ctx.String("format") != "" || ctx.Bool("summarize") || ctx.Bool("owner")) {
+		fatalIf(errInvalidArgument(), "--template cannot be used with --format, --summarize or --owner.")
+	}
+	if ctx.Bool("dirs-only") && ctx.Bool("files-only") {
+		fatalIf(errInvalidArgument(), "--dirs-only cannot be used with --files-only.")
+	}
+	if ctx.Bool("null") && (globalJSON || ctx.String("format") != "" || ctx.Bool("summarize") || ctx.String("template") != "" || ctx.Bool("owner")) {
+		fatalIf(errInvalidArgument(), "--null cannot be used with --json, --format, --summarize, --template or --owner.")
+	}
+	// Recursive listings have no folders.
+	if ctx.Bool("dirs-only") && ctx.Bool("recursive") {
+		fatalIf(errInvalidArgument(), "--dirs-only cannot be used with --recursive.")
+	}
+
 	for _, url := range URLs {
 		_, _, err := url2Stat(url, false, false, nil)
 		if err != nil && !isURLPrefixExists(url, isIncomplete) {
@@ -110,6 +176,10 @@ func mainList(ctx *cli.Context) error {
 	console.SetColor("Dir", color.New(color.FgCyan, color.Bold))
 	console.SetColor("Size", color.New(color.FgYellow))
 	console.SetColor("Time", color.New(color.FgGreen))
+	console.SetColor("Owner", color.New(color.FgMagenta))
+
+	// Templates are verified before anything is listed.
+	tmpl := parseTemplateFlag(ctx)
 
 	// check 'ls' cli arguments.
 	checkListSyntax(ctx)
@@ -117,6 +187,13 @@ func mainList(ctx *cli.Context) error {
 	// Set command flags from context.
 	isRecursive := ctx.Bool("recursive")
 	isIncomplete := ctx.Bool("incomplete")
+	isSummarize := ctx.Bool("summarize")
+	withOwner := ctx.Bool("owner")
+	dirsOnly, filesOnly := ctx.Bool("dirs-only"), ctx.Bool("files-only")
+	var csvList *csvLister
+	if ctx.String("format") == "csv" {
+		csvList = newCSVLister(color.Output, withOwner)
+	}
 
 	args := ctx.Args()
 	// mimic operating system tool behavior.
@@ -124,7 +201,7 @@ func mainList(ctx *cli.Context) error {
 		args = []string{"."}
 	}
 
-	var cErr error
+	var result batchResult
 	for _, targetURL := range args {
 		clnt, err := newClient(targetURL)
 		fatalIf(err.Trace(targetURL), "Unable to initialize target `"+targetURL+"`.")
@@ -139,9 +216,26 @@ func mainList(ctx *cli.Context) error {
 			}
 		}
 
-		if e := doList(clnt, isRecursive, isIncomplete); e != nil {
-			cErr = e
+		if dirsOnly || filesOnly {
+			clnt = listTypeClient{Client: clnt, dirsOnly: dirsOnly}
+		}
+
+		list := func(clnt Client, isRecursive, isIncomplete bool) error {
+			return doList(clnt, isRecursive, isIncomplete, withOwner)
+		}
+		if isSummarize {
+			list = doSummarize
+		}
+		if csvList != nil {
+			list = csvList.list
+		}
+		if tmpl != nil {
+			list = templateLister{tmpl: tmpl}.list
+		}
+		if ctx.Bool("null") {
+			list = nullList
 		}
+		result.record(list(clnt, isRecursive, isIncomplete))
 	}
-	return cErr
+	return result.exit()
 }
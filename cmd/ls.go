@@ -17,13 +17,15 @@
 package cmd
 
 import (
+	"encoding/csv"
 	"fmt"
+	"io"
 	"path/filepath"
 	"runtime"
+	"strconv"
 	"strings"
 	"time"
 
-	humanize "github.com/dustin/go-humanize"
 	json "github.com/minio/mc/pkg/colorjson"
 	"github.com/minio/mc/pkg/probe"
 	"github.com/minio/minio/pkg/console"
@@ -42,25 +44,37 @@ type contentMessage struct {
 	Size     int64     `json:"size"`
 	Key      string    `json:"key"`
 	ETag     string    `json:"etag"`
+	Owner    string    `json:"owner,omitempty"`
+
+	showOwner bool
+	// Printed by find --template only.
+	storageClass string
 }
 
+// Width of the owner column of listings, owners are padded to it.
+const listOwnerMaxLen = 16
+
 // String colorized string message.
 func (c contentMessage) String() string {
 	message := console.Colorize("Time", fmt.Sprintf("[%s] ", c.Time.Format(printDate)))
-	message = message + console.Colorize("Size", fmt.Sprintf("%7s ", strings.Join(strings.Fields(humanize.IBytes(uint64(c.Size))), "")))
+	message = message + console.Colorize("Size", fmt.Sprintf("%7s ", humanizeSizeColumn(c.Size)))
+	if c.showOwner {
+		message = message + console.Colorize("Owner", fmt.Sprintf("%-*s ", listOwnerMaxLen, c.Owner))
+	}
 	message = func() string {
 		if c.Filetype == "folder" {
-			return message + console.Colorize("Dir", c.Key)
+			return message + console.Colorize("Dir", quoteKey(c.Key))
 		}
-		return message + console.Colorize("File", c.Key)
+		return message + console.Colorize("File", quoteKey(c.Key))
 	}()
 	return message
 }
 
-// JSON jsonified content message.
+// JSON jsonified content message, emitted as a single line so
+// that a listing is streamed as valid NDJSON.
 func (c contentMessage) JSON() string {
 	c.Status = "success"
-	jsonMessageBytes, e := json.MarshalIndent(c, "", " ")
+	jsonMessageBytes, e := json.Marshal(c)
 	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
 
 	return string(jsonMessageBytes)
@@ -104,13 +118,10 @@ func getKey(c *clientContent) string {
 	return c.URL.Path
 }
 
-// doList - list all entities inside a folder.
-func doList(clnt Client, isRecursive, isIncomplete bool) error {
-	prefixPath := clnt.GetURL().Path
-	separator := string(clnt.GetURL().Separator)
-	if !strings.HasSuffix(prefixPath, separator) {
-		prefixPath = prefixPath[:strings.LastIndex(prefixPath, separator)+1]
-	}
+// listContents - streams the listing of clnt to fn, reporting listing
+// errors as they arrive. Objects in Glacier are skipped unless
+// withGlacier is set.
+func listContents(clnt Client, isRecursive, isIncomplete, withGlacier bool, fn func(content *clientContent)) error {
 	var cErr error
 	for content := range clnt.List(isRecursive, isIncomplete, false, DirNone) {
 		if content.Err != nil {
@@ -134,10 +145,23 @@ func doList(clnt Client, isRecursive, isIncomplete bool) error {
 			continue
 		}
 
-		if content.StorageClass == s3StorageClassGlacier {
+		if content.StorageClass == s3StorageClassGlacier && !withGlacier {
 			continue
 		}
+		fn(content)
+	}
+	return cErr
+}
 
+// trimListPrefix - returns a function making the paths of listed
+// contents relative to the folder listed by clnt.
+func trimListPrefix(clnt Client) func(content *clientContent) {
+	prefixPath := clnt.GetURL().Path
+	separator := string(clnt.GetURL().Separator)
+	if !strings.HasSuffix(prefixPath, separator) {
+		prefixPath = prefixPath[:strings.LastIndex(prefixPath, separator)+1]
+	}
+	return func(content *clientContent) {
 		// Convert any os specific delimiters to "/".
 		contentURL := filepath.ToSlash(content.URL.Path)
 		prefixPath = filepath.ToSlash(prefixPath)
@@ -147,9 +171,152 @@ func doList(clnt Client, isRecursive, isIncomplete bool) error {
 		// Trim prefix path from the content path.
 		contentURL = strings.TrimPrefix(contentURL, prefixPath)
 		content.URL.Path = contentURL
+	}
+}
+
+// listTypeClient lists either only the folders or only the files of a
+// Client, for ls --dirs-only and --files-only. Folders of listings with
+// a delimiter are the common prefixes, files are the other keys.
+type listTypeClient struct {
+	Client
+	dirsOnly bool
+}
+
+// List - lists the contents of the wrapped client of the requested
+// type, errors are always passed on.
+func (c listTypeClient) List(isRecursive, isIncomplete, isFetchMeta bool, showDir DirOpt) <-chan *clientContent {
+	contentCh := make(chan *clientContent)
+	go func() {
+		defer close(contentCh)
+		for content := range c.Client.List(isRecursive, isIncomplete, isFetchMeta, showDir) {
+			if content.Err == nil && content.Type.IsDir() != c.dirsOnly {
+				continue
+			}
+			contentCh <- content
+		}
+	}()
+	return contentCh
+}
+
+// doList - list all entities inside a folder, entries are printed
+// as they arrive from the listing instead of being accumulated. The
+// owner of every entry is printed too with withOwner, blank if the
+// backend does not report it.
+func doList(clnt Client, isRecursive, isIncomplete, withOwner bool) error {
+	trimPrefix := trimListPrefix(clnt)
+	return listContents(clnt, isRecursive, isIncomplete, false, func(content *clientContent) {
+		trimPrefix(content)
 		parsedContent := parseContent(content)
+		if withOwner {
+			parsedContent.Owner, parsedContent.showOwner = content.Owner, true
+		}
 		// Print colorized or jsonized content info.
 		printMsg(parsedContent)
+	})
+}
+
+// nullList - prints the key of every entry inside a folder terminated
+// by NUL, for ls --null.
+func nullList(clnt Client, isRecursive, isIncomplete bool) error {
+	trimPrefix := trimListPrefix(clnt)
+	return listContents(clnt, isRecursive, isIncomplete, false, func(content *clientContent) {
+		trimPrefix(content)
+		printNull(getKey(content))
+	})
+}
+
+// Columns of listings printed with --format csv, an owner column is
+// appended with --owner.
+var listCSVHeader = []string{"key", "size", "last-modified", "etag", "storage-class"}
+
+// csvLister prints listings as CSV rows as defined in RFC 4180, with a
+// single header row for all listed targets.
+type csvLister struct {
+	writer        *csv.Writer
+	withOwner     bool
+	headerWritten bool
+}
+
+func newCSVLister(w io.Writer, withOwner bool) *csvLister {
+	return &csvLister{writer: csv.NewWriter(w), withOwner: withOwner}
+}
+
+// list - prints a row per object inside a folder as they arrive from
+// the listing, folders are not printed. Objects in Glacier are listed
+// too, the listing is an inventory.
+func (l *csvLister) list(clnt Client, isRecursive, isIncomplete bool) error {
+	if !l.headerWritten {
+		header := listCSVHeader
+		if l.withOwner {
+			header = append(header[:len(header):len(header)], "owner")
+		}
+		l.writer.Write(header)
+		l.headerWritten = true
+	}
+	trimPrefix := trimListPrefix(clnt)
+	cErr := listContents(clnt, isRecursive, isIncomplete, true, func(content *clientContent) {
+		if content.Type.IsDir() {
+			return
+		}
+		trimPrefix(content)
+		row := []string{
+			getKey(content),
+			strconv.FormatInt(content.Size, 10),
+			content.Time.UTC().Format(time.RFC3339),
+			strings.Trim(content.ETag, "\""),
+			content.StorageClass,
+		}
+		if l.withOwner {
+			row = append(row, content.Owner)
+		}
+		l.writer.Write(row)
+	})
+	l.writer.Flush()
+	if e := l.writer.Error(); e != nil {
+		return e
 	}
 	return cErr
 }
+
+// listSummaryMessage container for the summary of a listing.
+type listSummaryMessage struct {
+	Status  string `json:"status"`
+	Target  string `json:"target"`
+	Objects int64  `json:"objects"`
+	Size    int64  `json:"size"`
+}
+
+// String colorized listing summary message.
+func (s listSummaryMessage) String() string {
+	objects := "objects"
+	if s.Objects == 1 {
+		objects = "object"
+	}
+	return console.Colorize("Size", fmt.Sprintf("%7s ", humanizeSizeColumn(s.Size))) +
+		console.Colorize("File", fmt.Sprintf("%d %s", s.Objects, objects)) + " in " + console.Colorize("Dir", s.Target)
+}
+
+// JSON jsonified listing summary message.
+func (s listSummaryMessage) JSON() string {
+	s.Status = "success"
+	jsonMessageBytes, e := json.Marshal(s)
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(jsonMessageBytes)
+}
+
+// doSummarize - prints the number and the total size of the objects
+// inside a folder, counted while the listing is streamed. Folders are
+// not counted.
+func doSummarize(clnt Client, isRecursive, isIncomplete bool) error {
+	summary := listSummaryMessage{Target: clnt.GetURL().String()}
+	cErr := listContents(clnt, isRecursive, isIncomplete, false, func(content *clientContent) {
+		if content.Type.IsDir() {
+			return
+		}
+		summary.Objects++
+		summary.Size += content.Size
+	})
+	printMsg(summary)
+	return cErr
+}
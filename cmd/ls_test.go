@@ -15,3 +15,402 @@
  */
 
 package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/minio/mc/pkg/probe"
+)
+
+// slowListClient is a Client whose listing is paced by the test, it
+// sends the first entry right away and waits for release before
+// sending the remaining entries followed by an error.
+type slowListClient struct {
+	Client
+	targetURL *clientURL
+	keys      []string
+	release   chan struct{}
+}
+
+func (c *slowListClient) GetURL() clientURL {
+	return *c.targetURL
+}
+
+func (c *slowListClient) List(isRecursive, isIncomplete, isFetchMeta bool, showDir DirOpt) <-chan *clientContent {
+	contentCh := make(chan *clientContent)
+	go func() {
+		defer close(contentCh)
+		for i, key := range c.keys {
+			if i == 1 {
+				<-c.release
+			}
+			u := *c.targetURL
+			u.Path = u.Path + key
+			contentCh <- &clientContent{
+				URL:  u,
+				Time: time.Unix(0, 0),
+				Size: int64(i),
+				Type: os.FileMode(0664),
+			}
+		}
+		contentCh <- &clientContent{Err: probe.NewError(errors.New("listing interrupted"))}
+	}()
+	return contentCh
+}
+
+func TestListJSONStreaming(t *testing.T) {
+	savedJSON, savedOutput := globalJSON, color.Output
+	defer func() {
+		globalJSON, color.Output = savedJSON, savedOutput
+	}()
+	globalJSON = true
+
+	pr, pw := io.Pipe()
+	color.Output = pw
+
+	clnt := &slowListClient{
+		targetURL: newClientURL("http://localhost:9000/bucket/"),
+		keys:      []string{"object1", "object2", "object3"},
+		release:   make(chan struct{}),
+	}
+
+	doneCh := make(chan error)
+	go func() {
+		e := doList(clnt, true, false, false)
+		pw.Close()
+		doneCh <- e
+	}()
+
+	scanner := bufio.NewScanner(pr)
+	lines := make(chan string)
+	go func() {
+		defer close(lines)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+	}()
+
+	// The first object must be emitted while the listing is still blocked.
+	select {
+	case line := <-lines:
+		var msg contentMessage
+		if e := json.Unmarshal([]byte(line), &msg); e != nil {
+			t.Fatalf("expected a JSON object per line, got `%s`: %v", line, e)
+		}
+		if msg.Key != "object1" {
+			t.Fatalf("expected `object1`, got `%s`", msg.Key)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("first object was not emitted before the listing completed")
+	}
+
+	close(clnt.release)
+
+	var rest []string
+	for line := range lines {
+		rest = append(rest, line)
+	}
+	if e := <-doneCh; e == nil {
+		t.Fatal("expected a non-nil exit status for a failed listing")
+	}
+	if len(rest) != 3 {
+		t.Fatalf("expected 3 more lines, got %d: %v", len(rest), rest)
+	}
+	for _, line := range rest {
+		var v map[string]interface{}
+		if e := json.Unmarshal([]byte(line), &v); e != nil {
+			t.Fatalf("invalid NDJSON line `%s`: %v", line, e)
+		}
+	}
+	last := rest[len(rest)-1]
+	if !strings.Contains(last, `"status":"error"`) {
+		t.Fatalf("expected a final error object, got `%s`", last)
+	}
+}
+
+// staticListClient is a Client listing a fixed set of contents.
+type staticListClient struct {
+	Client
+	targetURL *clientURL
+	contents  []*clientContent
+}
+
+func (c *staticListClient) GetURL() clientURL {
+	return *c.targetURL
+}
+
+func (c *staticListClient) List(isRecursive, isIncomplete, isFetchMeta bool, showDir DirOpt) <-chan *clientContent {
+	contentCh := make(chan *clientContent, len(c.contents))
+	for _, content := range c.contents {
+		contentCh <- content
+	}
+	close(contentCh)
+	return contentCh
+}
+
+func TestListSummarize(t *testing.T) {
+	savedJSON, savedOutput := globalJSON, color.Output
+	defer func() {
+		globalJSON, color.Output = savedJSON, savedOutput
+	}()
+	globalJSON = true
+
+	targetURL := newClientURL("http://localhost:9000/bucket/")
+	content := func(key string, size int64, typ os.FileMode, storageClass string) *clientContent {
+		u := *targetURL
+		u.Path = u.Path + key
+		return &clientContent{URL: u, Size: size, Type: typ, StorageClass: storageClass}
+	}
+	clnt := &staticListClient{
+		targetURL: targetURL,
+		contents: []*clientContent{
+			content("dir/", 0, os.ModeDir, ""),
+			content("object1", 100, 0664, ""),
+			content("dir/object2", 1000, 0664, ""),
+			content("archived", 5000, 0664, s3StorageClassGlacier),
+			content("object3", 24, 0664, ""),
+		},
+	}
+
+	var buf strings.Builder
+	color.Output = &buf
+	if e := doSummarize(clnt, true, false); e != nil {
+		t.Fatal(e)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("expected a single summary line, got %d: %v", len(lines), lines)
+	}
+	var msg listSummaryMessage
+	if e := json.Unmarshal([]byte(lines[0]), &msg); e != nil {
+		t.Fatal(e)
+	}
+	if msg.Status != "success" || msg.Objects != 3 || msg.Size != 1124 {
+		t.Fatalf("unexpected summary %+v", msg)
+	}
+}
+
+func TestListCSV(t *testing.T) {
+	savedOutput := color.Output
+	defer func() { color.Output = savedOutput }()
+
+	modTime := time.Date(2020, 3, 1, 10, 30, 0, 0, time.FixedZone("CET", 3600))
+	targetURL := newClientURL("http://localhost:9000/bucket/")
+	content := func(key string, size int64, typ os.FileMode, etag, storageClass string) *clientContent {
+		u := *targetURL
+		u.Path = u.Path + key
+		return &clientContent{URL: u, Size: size, Type: typ, Time: modTime, ETag: etag, StorageClass: storageClass}
+	}
+	clnt := &staticListClient{
+		targetURL: targetURL,
+		contents: []*clientContent{
+			content("dir/", 0, os.ModeDir, "", ""),
+			content("dir/a,b.txt", 100, 0664, `"d41d8cd98f00b204e9800998ecf8427e"`, "STANDARD"),
+			content(`say "hi"`, 5000, 0664, `"9bb58f26192e4ba00f01e2e7b136bbd8-2"`, s3StorageClassGlacier),
+		},
+	}
+
+	var buf strings.Builder
+	color.Output = &buf
+	csvList := newCSVLister(color.Output, false)
+	// The header is printed once for all targets.
+	for i := 0; i < 2; i++ {
+		if e := csvList.list(clnt, true, false); e != nil {
+			t.Fatal(e)
+		}
+	}
+	row1 := `"dir/a,b.txt",100,2020-03-01T09:30:00Z,d41d8cd98f00b204e9800998ecf8427e,STANDARD`
+	row2 := `"say ""hi""",5000,2020-03-01T09:30:00Z,9bb58f26192e4ba00f01e2e7b136bbd8-2,GLACIER`
+	expected := strings.Join([]string{"key,size,last-modified,etag,storage-class", row1, row2, row1, row2, ""}, "\n")
+	if buf.String() != expected {
+		t.Fatalf("expected\n%s\ngot\n%s", expected, buf.String())
+	}
+}
+
+// listOwnerResponse is a ListObjectsV2 response of a shared bucket, the
+// owner of the second object is known by its ID only and the owner of
+// the third one is omitted.
+const listOwnerResponse = `<?xml version="1.0" encoding="UTF-8"?>
+<ListBucketResult xmlns="http://s3.amazonaws.com/doc/2006-03-01/">
+<Name>bucket</Name><Prefix></Prefix><KeyCount>3</KeyCount><MaxKeys>1000</MaxKeys><IsTruncated>false</IsTruncated>
+<Contents><Key>alice.txt</Key><LastModified>2020-03-01T09:30:00.000Z</LastModified><ETag>&quot;d41d8cd98f00b204e9800998ecf8427e&quot;</ETag><Size>100</Size><Owner><ID>75aa57f09aa0c8caeab4f8c24e99d10f8e7faeebf76c078efc7c6caea54ba06a</ID><DisplayName>alice</DisplayName></Owner><StorageClass>STANDARD</StorageClass></Contents>
+<Contents><Key>bob.txt</Key><LastModified>2020-03-01T09:30:00.000Z</LastModified><ETag>&quot;9bb58f26192e4ba00f01e2e7b136bbd8&quot;</ETag><Size>200</Size><Owner><ID>bob-id</ID></Owner><StorageClass>STANDARD</StorageClass></Contents>
+<Contents><Key>nobody.txt</Key><LastModified>2020-03-01T09:30:00.000Z</LastModified><ETag>&quot;5d41402abc4b2a76b9719d911017c592&quot;</ETag><Size>300</Size><StorageClass>STANDARD</StorageClass></Contents>
+</ListBucketResult>`
+
+func TestListOwner(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := r.URL.Query()["location"]; ok {
+			w.Write([]byte(`<LocationConstraint xmlns="http://s3.amazonaws.com/doc/2006-03-01/"></LocationConstraint>`))
+			return
+		}
+		w.Write([]byte(listOwnerResponse))
+	}))
+	defer server.Close()
+	defer withTestConfig(map[string]hostConfigV9{"myminio": testHost(server.URL)})()
+	savedJSON, savedOutput := globalJSON, color.Output
+	defer func() { globalJSON, color.Output = savedJSON, savedOutput }()
+
+	clnt, err := newClient("myminio/bucket/")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf strings.Builder
+	color.Output = &buf
+	if e := newCSVLister(color.Output, true).list(clnt, true, false); e != nil {
+		t.Fatal(e)
+	}
+	expected := strings.Join([]string{
+		"key,size,last-modified,etag,storage-class,owner",
+		"alice.txt,100,2020-03-01T09:30:00Z,d41d8cd98f00b204e9800998ecf8427e,STANDARD,alice",
+		"bob.txt,200,2020-03-01T09:30:00Z,9bb58f26192e4ba00f01e2e7b136bbd8,STANDARD,bob-id",
+		"nobody.txt,300,2020-03-01T09:30:00Z,5d41402abc4b2a76b9719d911017c592,STANDARD,",
+		"",
+	}, "\n")
+	if buf.String() != expected {
+		t.Fatalf("expected\n%s\ngot\n%s", expected, buf.String())
+	}
+
+	// The owner is only printed when asked for.
+	globalJSON = true
+	for _, withOwner := range []bool{false, true} {
+		buf.Reset()
+		if e := doList(clnt, true, false, withOwner); e != nil {
+			t.Fatal(e)
+		}
+		var owners []string
+		for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+			var msg contentMessage
+			if e := json.Unmarshal([]byte(line), &msg); e != nil {
+				t.Fatalf("expected a JSON object per line, got `%s`: %v", line, e)
+			}
+			if msg.ETag == "" {
+				t.Fatalf("expected the etag of `%s`", msg.Key)
+			}
+			owners = append(owners, msg.Owner)
+		}
+		expectedOwners := []string{"", "", ""}
+		if withOwner {
+			expectedOwners = []string{"alice", "bob-id", ""}
+		}
+		if strings.Join(owners, ",") != strings.Join(expectedOwners, ",") {
+			t.Fatalf("expected owners %v with owner %t, got %v", expectedOwners, withOwner, owners)
+		}
+	}
+
+	globalJSON = false
+	buf.Reset()
+	if e := doList(clnt, true, false, true); e != nil {
+		t.Fatal(e)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 || !strings.Contains(lines[0], "alice            alice.txt") ||
+		!strings.HasSuffix(lines[2], strings.Repeat(" ", listOwnerMaxLen+1)+"nobody.txt") {
+		t.Fatalf("expected an owner column left blank for unknown owners, got\n%s", buf.String())
+	}
+}
+
+func TestListType(t *testing.T) {
+	handler := newFakeS3Server(map[string][]byte{
+		"bucket/photos/2019/a.jpg": []byte("a"),
+		"bucket/photos/2020/b.jpg": []byte("b"),
+		"bucket/photos/c.jpg":      []byte("c"),
+		"bucket/photos/d.jpg":      []byte("d"),
+	})
+	server := httptest.NewServer(handler)
+	defer server.Close()
+	defer withTestConfig(map[string]hostConfigV9{"myminio": testHost(server.URL)})()
+
+	clnt, err := newClient("myminio/bucket/photos/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	list := func(clnt Client) (keys []string) {
+		for content := range clnt.List(false, false, false, DirNone) {
+			if content.Err != nil {
+				t.Fatal(content.Err)
+			}
+			keys = append(keys, content.URL.Path)
+		}
+		return keys
+	}
+	all := list(clnt)
+	if len(all) != 4 {
+		t.Fatalf("expected 2 folders and 2 files, got %v", all)
+	}
+	testCases := []struct {
+		dirsOnly bool
+		expected []string
+	}{
+		{true, []string{"/bucket/photos/2019/", "/bucket/photos/2020/"}},
+		{false, []string{"/bucket/photos/c.jpg", "/bucket/photos/d.jpg"}},
+	}
+	for i, testCase := range testCases {
+		keys := list(listTypeClient{Client: clnt, dirsOnly: testCase.dirsOnly})
+		if strings.Join(keys, ",") != strings.Join(testCase.expected, ",") {
+			t.Fatalf("Test %d: expected %v, got %v", i+1, testCase.expected, keys)
+		}
+	}
+}
+
+func TestListQuoting(t *testing.T) {
+	testCases := []struct {
+		key, quoted string
+	}{
+		{"photos/a.jpg", "photos/a.jpg"},
+		{"my photo.jpg", "'my photo.jpg'"},
+		{"it's.txt", `'it'\''s.txt'`},
+		{"line\nbreak it's", `$'line\nbreak it\'s'`},
+		{"bell\a", `$'bell\x07'`},
+		{"été.txt", "été.txt"},
+		{"", ""},
+	}
+	for i, testCase := range testCases {
+		if quoted := quoteKey(testCase.key); quoted != testCase.quoted {
+			t.Fatalf("Test %d: expected %s, got %s", i+1, testCase.quoted, quoted)
+		}
+	}
+
+	handler := newFakeS3Server(map[string][]byte{
+		"bucket/a b\nc.txt": []byte("odd"),
+		"bucket/plain.txt":  []byte("plain"),
+	})
+	server := httptest.NewServer(handler)
+	defer server.Close()
+	defer withTestConfig(map[string]hostConfigV9{"myminio": testHost(server.URL)})()
+	savedOutput, savedNoColor := color.Output, color.NoColor
+	defer func() { color.Output, color.NoColor = savedOutput, savedNoColor }()
+	var buf strings.Builder
+	color.Output, color.NoColor = &buf, true
+
+	clnt, err := newClient("myminio/bucket/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e := doList(clnt, true, false, false); e != nil {
+		t.Fatal(e)
+	}
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 2 || !strings.HasSuffix(lines[0], ` $'a b\nc.txt'`) || !strings.HasSuffix(lines[1], " plain.txt") {
+		t.Fatalf("Expected one quoted line per object, got %q", buf.String())
+	}
+
+	buf.Reset()
+	if e := nullList(clnt, true, false); e != nil {
+		t.Fatal(e)
+	}
+	if buf.String() != "a b\nc.txt\x00plain.txt\x00" {
+		t.Fatalf("Expected NUL terminated keys, got %q", buf.String())
+	}
+}
@@ -44,6 +44,10 @@ var (
 			Name:  "autocompletion",
 			Usage: "install auto-completion for your shell",
 		},
+		cli.BoolFlag{
+			Name:  "remote-completion",
+			Usage: "with --autocompletion, complete the buckets and objects of aliases listing them",
+		},
 	}
 )
 
@@ -61,7 +65,8 @@ GLOBAL FLAGS:
   {{range .VisibleFlags}}{{.}}
   {{end}}{{end}}
 TIP:
-  Use '{{.Name}} --autocompletion' to enable shell autocompletion
+  Use '{{.Name}} --autocompletion' to enable shell autocompletion, '{{.Name}} --autocompletion --remote-completion'
+  to complete the buckets and objects of aliases as well
 
 VERSION:
   ` + ReleaseTag +
@@ -103,6 +108,10 @@ func Main(args []string) {
 		globalTermWidth = w
 	}
 
+	// Output piped to other programs is written in whole lines.
+	bufferPipedOutput()
+	defer flushOutput()
+
 	// Set the mc app name.
 	appName := filepath.Base(args[0])
 	if runtime.GOOS == "windows" && strings.HasSuffix(strings.ToLower(appName), ".exe") {
@@ -111,7 +120,7 @@ func Main(args []string) {
 	}
 	// Run the app - exit on error.
 	if err := registerApp(appName).Run(args); err != nil {
-		os.Exit(1)
+		exit(1)
 	}
 }
 
@@ -230,29 +239,84 @@ func initMC() {
 
 }
 
-func installAutoCompletion() {
+func installAutoCompletion(remote bool) {
 	if runtime.GOOS == "windows" {
 		console.Infoln("autocompletion feature is not available for this operating system")
 		return
 	}
 
+	// Completing buckets and objects sends requests to the hosts at
+	// every tab, it is only done if asked for.
+	conf, err := loadMcConfig()
+	fatalIf(err.Trace(globalMCConfigVersion), "Unable to load config version `"+globalMCConfigVersion+"`.")
+	if conf.RemoteCompletion != remote {
+		conf.RemoteCompletion = remote
+		fatalIf(saveMcConfig(conf).Trace(), "Unable to save remote completion in config version `"+globalMCConfigVersion+"`.")
+	}
+	if remote {
+		console.Infoln("buckets and objects of aliases are completed, listed for at most " + remoteCompletionTimeout.String() + ".")
+	}
+
 	if completeinstall.IsInstalled(filepath.Base(os.Args[0])) || completeinstall.IsInstalled("mc") {
 		console.Infoln("autocompletion is already enabled in your '$SHELLRC'")
 		return
 	}
 
-	err := completeinstall.Install(filepath.Base(os.Args[0]))
-	if err != nil {
-		fatalIf(probe.NewError(err), "Unable to install auto-completion.")
+	if e := completeinstall.Install(filepath.Base(os.Args[0])); e != nil {
+		fatalIf(probe.NewError(e), "Unable to install auto-completion.")
 	} else {
 		console.Infoln("enabled autocompletion in '$SHELLRC'. Please restart your shell.")
 	}
 }
 
+// isNoConfigSet - returns whether --no-config is given, before or after
+// the command name.
+func isNoConfigSet(ctx *cli.Context) bool {
+	if ctx.Bool("no-config") {
+		return true
+	}
+	for _, arg := range ctx.Args() {
+		if arg == "--" {
+			break
+		}
+		if arg == "--no-config" || arg == "-no-config" {
+			return true
+		}
+	}
+	return false
+}
+
+// isConfigCheckSet - returns whether the command is `config check` or
+// `config repair`.
+func isConfigCheckSet(ctx *cli.Context) bool {
+	args := ctx.Args()
+	return len(args) >= 2 && args[0] == "config" && (args[1] == "check" || args[1] == "repair")
+}
+
 func registerBefore(ctx *cli.Context) error {
 	// Set the config directory.
 	setMcConfigDir(ctx.GlobalString("config-dir"))
 
+	// Nothing is migrated or created without a config file, the
+	// config is built from flags once the command is reached.
+	if isNoConfigSet(ctx) {
+		globalNoConfig = true
+		loadMcConfig = func() (*configV9, *probe.Error) {
+			return newConfigV9(), nil
+		}
+		setGlobalsFromContext(ctx)
+		loadRootCAs()
+		return nil
+	}
+
+	// Broken configs would fail migrating and checking the config, they
+	// are read as they are by the commands repairing them.
+	if isConfigCheckSet(ctx) {
+		loadMcConfig = loadMcConfigFactory()
+		setGlobalsFromContext(ctx)
+		return nil
+	}
+
 	// Migrate any old version of config / state files to newer format.
 	migrate()
 
@@ -324,9 +388,17 @@ var appCmds = []cli.Command{
 	duCmd,
 	lockCmd,
 	retentionCmd,
+	legalHoldCmd,
+	restoreCmd,
 	diffCmd,
+	compareCmd,
 	rmCmd,
+	trashCmd,
+	cleanCmd,
+	pingCmd,
+	resolveCmd,
 	eventCmd,
+	corsCmd,
 	watchCmd,
 	policyCmd,
 	adminCmd,
@@ -354,7 +426,7 @@ func registerApp(name string) *cli.App {
 
 		if ctx.Bool("autocompletion") || ctx.GlobalBool("autocompletion") {
 			// Install shell completions
-			installAutoCompletion()
+			installAutoCompletion(ctx.Bool("remote-completion") || ctx.GlobalBool("remote-completion"))
 			return
 		}
 
@@ -362,6 +434,11 @@ func registerApp(name string) *cli.App {
 	}
 
 	app.Before = registerBefore
+	app.After = func(ctx *cli.Context) error {
+		printStats()
+		printProfile()
+		return nil
+	}
 	app.ExtraInfo = func() map[string]string {
 		if globalDebug {
 			return getSystemData()
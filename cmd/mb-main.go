@@ -17,6 +17,8 @@
 package cmd
 
 import (
+	"sync"
+
 	"github.com/fatih/color"
 	"github.com/minio/cli"
 	json "github.com/minio/mc/pkg/colorjson"
@@ -79,6 +81,9 @@ EXAMPLES:
 
   7. Create a new bucket on Amazon S3 cloud storage in region 'us-west-2' with object lock enabled.
      {{.Prompt}} {{.HelpName}} --with-lock --region=us-west-2 s3/myregionbucket
+
+  8. Create a bucket on a MinIO server without any config file, e.g. in a CI job.
+     {{.Prompt}} {{.HelpName}} --no-config --access $ACCESS_KEY --secret $SECRET_KEY --endpoint-url https://minio.example.com minio.example.com/mynewbucket
 `,
 }
 
@@ -109,6 +114,42 @@ func checkMakeBucketSyntax(ctx *cli.Context) {
 	}
 }
 
+// Maximum number of buckets created in parallel.
+const mbMaxWorkers = 16
+
+// doMakeBucket - makes the bucket or directory of a single target.
+func doMakeBucket(targetURL, region string, ignoreExisting, withLock bool) *probe.Error {
+	// Instantiate client for URL.
+	clnt, err := newClient(targetURL)
+	if err != nil {
+		return err.Trace(targetURL)
+	}
+	return clnt.MakeBucket(region, ignoreExisting, withLock)
+}
+
+// makeBuckets - makes all targets concurrently with at most mbMaxWorkers
+// requests in flight; clients of the same host share one transport. The
+// errors are returned in the order of the targets so that output does not
+// depend on scheduling, a failing target does not stop the others.
+func makeBuckets(targetURLs []string, region string, ignoreExisting, withLock bool) []*probe.Error {
+	errs := make([]*probe.Error, len(targetURLs))
+	sem := make(chan struct{}, mbMaxWorkers)
+	var wg sync.WaitGroup
+	for i, targetURL := range targetURLs {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, targetURL string) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			errs[i] = doMakeBucket(targetURL, region, ignoreExisting, withLock)
+		}(i, targetURL)
+	}
+	wg.Wait()
+	return errs
+}
+
 // mainMakeBucket is entry point for mb command.
 func mainMakeBucket(ctx *cli.Context) error {
 
@@ -123,18 +164,9 @@ func mainMakeBucket(ctx *cli.Context) error {
 	ignoreExisting := ctx.Bool("p")
 	withLock := ctx.Bool("l")
 
-	var cErr error
-	for _, targetURL := range ctx.Args() {
-		// Instantiate client for URL.
-		clnt, err := newClient(targetURL)
-		if err != nil {
-			errorIf(err.Trace(targetURL), "Invalid target `"+targetURL+"`.")
-			cErr = exitStatus(globalErrorExitStatus)
-			continue
-		}
-
-		// Make bucket.
-		err = clnt.MakeBucket(region, ignoreExisting, withLock)
+	var result batchResult
+	for i, err := range makeBuckets(ctx.Args(), region, ignoreExisting, withLock) {
+		targetURL := ctx.Args().Get(i)
 		if err != nil {
 			switch err.ToGoError().(type) {
 			case BucketNameEmpty:
@@ -144,12 +176,13 @@ func mainMakeBucket(ctx *cli.Context) error {
 			default:
 				errorIf(err.Trace(targetURL), "Unable to make bucket `"+targetURL+"`.")
 			}
-			cErr = exitStatus(globalErrorExitStatus)
+			result.failure()
 			continue
 		}
 
 		// Successfully created a bucket.
 		printMsg(makeBucketMessage{Status: "success", Bucket: targetURL})
+		result.success()
 	}
-	return cErr
+	return result.exit()
 }
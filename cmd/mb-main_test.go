@@ -0,0 +1,254 @@
+/*
+ * MinIO Client (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/probe"
+)
+
+// makeBucketHandler records bucket creation requests, refusing to create
+// the bucket named "taken".
+type makeBucketHandler struct {
+	mutex   *sync.Mutex
+	buckets *[]string
+}
+
+func (h makeBucketHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	bucket := strings.Trim(r.URL.Path, "/")
+	if r.Method != http.MethodPut || bucket == "" || strings.Contains(bucket, "/") {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	h.mutex.Lock()
+	*h.buckets = append(*h.buckets, bucket)
+	h.mutex.Unlock()
+	if bucket == "taken" {
+		w.WriteHeader(http.StatusConflict)
+		w.Write([]byte("<Error><Code>BucketAlreadyOwnedByYou</Code><Message>Bucket exists</Message></Error>"))
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// setMakeBucketAlias - points the alias `mbtest` at the server.
+func setMakeBucketAlias(server *httptest.Server) func() {
+	return withTestConfig(map[string]hostConfigV9{"mbtest": testHost(server.URL)})
+}
+
+func TestMakeBuckets(t *testing.T) {
+	var buckets []string
+	server := httptest.NewServer(makeBucketHandler{mutex: &sync.Mutex{}, buckets: &buckets})
+	defer server.Close()
+	defer setMakeBucketAlias(server)()
+
+	var targets, expected []string
+	for i := 0; i < 2*mbMaxWorkers; i++ {
+		bucket := fmt.Sprintf("bucket%02d", i)
+		if i == mbMaxWorkers/2 {
+			bucket = "taken"
+		}
+		targets = append(targets, "mbtest/"+bucket)
+		expected = append(expected, bucket)
+	}
+
+	errs := makeBuckets(targets, "us-east-1", false, false)
+	if len(errs) != len(targets) {
+		t.Fatalf("Expected %d results, got %d", len(targets), len(errs))
+	}
+	for i, err := range errs {
+		if (i == mbMaxWorkers/2) != (err != nil) {
+			t.Fatalf("Target %s: unexpected result %v", targets[i], err)
+		}
+	}
+
+	// Every target is attempted despite the failure.
+	sort.Strings(buckets)
+	sort.Strings(expected)
+	if strings.Join(buckets, ",") != strings.Join(expected, ",") {
+		t.Fatalf("Expected buckets %v, got %v", expected, buckets)
+	}
+}
+
+func TestMakeBucketExitStatus(t *testing.T) {
+	var buckets []string
+	server := httptest.NewServer(makeBucketHandler{mutex: &sync.Mutex{}, buckets: &buckets})
+	defer server.Close()
+	defer setMakeBucketAlias(server)()
+
+	savedJSON, savedOutput := globalJSON, color.Output
+	defer func() {
+		globalJSON, color.Output = savedJSON, savedOutput
+	}()
+	globalJSON = true
+
+	testCases := []struct {
+		targets []string
+		failed  int
+	}{
+		{[]string{"mbtest/bucket1", "mbtest/bucket2"}, 0},
+		{[]string{"mbtest/bucket3", "mbtest/taken", "mbtest/bucket4"}, 1},
+		{[]string{"mbtest/taken"}, 1},
+	}
+	for i, testCase := range testCases {
+		set := flag.NewFlagSet(mbCmd.Name, flag.ContinueOnError)
+		for _, f := range mbCmd.Flags {
+			f.Apply(set)
+		}
+		if e := set.Parse(testCase.targets); e != nil {
+			t.Fatal(e)
+		}
+		var buf bytes.Buffer
+		color.Output = &buf
+		e := mainMakeBucket(cli.NewContext(nil, set, nil))
+
+		if testCase.failed == 0 {
+			if e != nil {
+				t.Fatalf("Test %d: Expected success, got %v", i+1, e)
+			}
+			continue
+		}
+		exitErr, ok := e.(cli.ExitCoder)
+		if !ok || exitErr.ExitCode() != globalErrorExitStatus {
+			t.Fatalf("Test %d: Expected exit status %d, got %v", i+1, globalErrorExitStatus, e)
+		}
+
+		// Several targets end with a summary of the failures.
+		var last batchResultMessage
+		decoder := json.NewDecoder(&buf)
+		for {
+			var msg batchResultMessage
+			if e = decoder.Decode(&msg); e == io.EOF {
+				break
+			} else if e != nil {
+				t.Fatalf("Test %d: %v", i+1, e)
+			}
+			last = msg
+		}
+		expected := batchResultMessage{Status: "error", Failed: testCase.failed, Total: len(testCase.targets)}
+		if len(testCase.targets) == 1 {
+			expected = batchResultMessage{Status: "error"}
+		}
+		if last != expected {
+			t.Fatalf("Test %d: Expected summary %+v, got %+v", i+1, expected, last)
+		}
+	}
+}
+
+func TestMakeBucketNoConfig(t *testing.T) {
+	var buckets []string
+	server := httptest.NewServer(makeBucketHandler{mutex: &sync.Mutex{}, buckets: &buckets})
+	defer server.Close()
+
+	defer func(load func() (*configV9, *probe.Error), configDir string) {
+		loadMcConfig, mcCustomConfigDir = load, configDir
+	}(loadMcConfig, mcCustomConfigDir)
+	defer func(noConfig bool, endpointURL, accessKey, secretKey string) {
+		globalNoConfig, globalEndpointURL, globalAccessKey, globalSecretKey = noConfig, endpointURL, accessKey, secretKey
+	}(globalNoConfig, globalEndpointURL, globalAccessKey, globalSecretKey)
+	savedJSON, savedOutput := globalJSON, color.Output
+	defer func() {
+		globalJSON, color.Output = savedJSON, savedOutput
+	}()
+	var buf bytes.Buffer
+	color.Output = &buf
+
+	tmpDir, e := ioutil.TempDir("", "mc-no-config-")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.RemoveAll(tmpDir)
+	configDir := filepath.Join(tmpDir, ".mc")
+
+	// Flags are accepted before and after the command name.
+	alias := strings.TrimPrefix(server.URL, "http://")
+	alias = alias[:strings.Index(alias, ":")]
+	e = registerApp("mc").Run([]string{"mc", "--config-dir", configDir, "--no-config", "--json",
+		"mb", "--endpoint-url", server.URL,
+		"--access", testAccessKey, "--secret", testSecretKey,
+		alias + "/nocfgbucket"})
+	if e != nil {
+		t.Fatal(e)
+	}
+	if strings.Join(buckets, ",") != "nocfgbucket" {
+		t.Fatalf("Expected bucket nocfgbucket to be created, got %v", buckets)
+	}
+	if _, e = os.Stat(configDir); !os.IsNotExist(e) {
+		t.Fatalf("Expected no config folder to be created, got %v", e)
+	}
+	if err := saveMcConfig(newMcConfig()); err == nil {
+		t.Fatal("Expected the config not to be saved with --no-config")
+	}
+}
+
+func TestRemoveBucketExitStatus(t *testing.T) {
+	server := httptest.NewServer(newFakeS3Server(map[string][]byte{"bucket/object": []byte("data")}))
+	defer server.Close()
+	defer setMakeBucketAlias(server)()
+
+	savedJSON, savedOutput := globalJSON, color.Output
+	defer func() {
+		globalJSON, color.Output = savedJSON, savedOutput
+	}()
+	globalJSON = true
+
+	// The bucket which is not empty does not stop the removal of the
+	// other targets, nor their summary.
+	targets := []string{"mbtest/bucket", "mbtest/missing"}
+	set := flag.NewFlagSet(rbCmd.Name, flag.ContinueOnError)
+	for _, f := range rbCmd.Flags {
+		f.Apply(set)
+	}
+	if e := set.Parse(targets); e != nil {
+		t.Fatal(e)
+	}
+	var buf bytes.Buffer
+	color.Output = &buf
+	e := mainRemoveBucket(cli.NewContext(nil, set, nil))
+	if exitErr, ok := e.(cli.ExitCoder); !ok || exitErr.ExitCode() != globalErrorExitStatus {
+		t.Fatalf("Expected exit status %d, got %v", globalErrorExitStatus, e)
+	}
+	var summary batchResultMessage
+	for decoder := json.NewDecoder(&buf); ; {
+		var msg batchResultMessage
+		if e = decoder.Decode(&msg); e == io.EOF {
+			break
+		} else if e != nil {
+			t.Fatal(e)
+		}
+		summary = msg
+	}
+	if expected := (batchResultMessage{Status: "error", Failed: 2, Total: 2}); summary != expected {
+		t.Fatalf("Expected summary %+v, got %+v", expected, summary)
+	}
+}
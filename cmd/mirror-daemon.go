@@ -0,0 +1,306 @@
+/*
+ * MinIO Client (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	json "github.com/minio/mc/pkg/colorjson"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/minio/pkg/console"
+)
+
+// mirrorDaemonEnv - set to the ID of the daemon in the environment of
+// the process started by mirror --daemon.
+const mirrorDaemonEnv = "_MC_MIRROR_DAEMON_ID"
+
+// mirrorDaemonMessage - the state of a daemon started by mirror --daemon.
+type mirrorDaemonMessage struct {
+	op     string
+	Status string `json:"status"`
+	ID     string `json:"id"`
+	PID    int    `json:"pid"`
+	// One of started, running, stale and stopped. Stale daemons exited
+	// without removing their PID file.
+	State  string `json:"state"`
+	Output string `json:"output,omitempty"`
+}
+
+// String colorized mirror daemon message
+func (m mirrorDaemonMessage) String() string {
+	switch m.op {
+	case "start":
+		return console.Colorize("Mirror", fmt.Sprintf("Mirroring in the background as daemon `%s` with PID %d, output in `%s`.", m.ID, m.PID, m.Output))
+	case "stop":
+		if m.State == "stale" {
+			return console.Colorize("Mirror", fmt.Sprintf("Removed the PID file of daemon `%s`, PID %d is not running.", m.ID, m.PID))
+		}
+		return console.Colorize("Mirror", fmt.Sprintf("Stopped daemon `%s` with PID %d.", m.ID, m.PID))
+	}
+	return console.Colorize("Mirror", fmt.Sprintf("%s  %-7s  PID %d", m.ID, m.State, m.PID))
+}
+
+// JSON jsonified mirror daemon message
+func (m mirrorDaemonMessage) JSON() string {
+	m.Status = "success"
+	jsonMessageBytes, e := json.MarshalIndent(m, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(jsonMessageBytes)
+}
+
+// isMirrorDaemon - reports whether this process is a daemon started by
+// mirror --daemon.
+func isMirrorDaemon() bool {
+	return os.Getenv(mirrorDaemonEnv) != ""
+}
+
+// getMirrorDaemonDir - returns the folder of the PID files and outputs
+// of the daemons.
+func getMirrorDaemonDir() string {
+	return filepath.Join(mustGetMcConfigDir(), "mirror")
+}
+
+// mirrorDaemonID - returns the ID of the daemon mirroring srcURL to
+// tgtURL, the same for every run.
+func mirrorDaemonID(srcURL, tgtURL string) string {
+	sum := sha256.Sum256([]byte(srcURL + "\x00" + tgtURL))
+	return hex.EncodeToString(sum[:6])
+}
+
+// isMirrorDaemonID - reports whether id has the form of the IDs of
+// mirrorDaemonID.
+func isMirrorDaemonID(id string) bool {
+	return mirrorDaemonIDRegex.MatchString(id)
+}
+
+var mirrorDaemonIDRegex = regexp.MustCompile("^[0-9a-f]{12}$")
+
+// getMirrorPIDFile - returns the PID file of the daemon id.
+func getMirrorPIDFile(id string) string {
+	return filepath.Join(getMirrorDaemonDir(), id+".pid")
+}
+
+// readMirrorPID - returns the PID of the daemon id.
+func readMirrorPID(id string) (int, *probe.Error) {
+	pidFile := getMirrorPIDFile(id)
+	data, e := ioutil.ReadFile(pidFile)
+	if e != nil {
+		return 0, probe.NewError(e).Trace(pidFile)
+	}
+	pid, e := strconv.Atoi(strings.TrimSpace(string(data)))
+	if e != nil {
+		return 0, probe.NewError(e).Trace(pidFile)
+	}
+	return pid, nil
+}
+
+// writeMirrorPIDFile - writes the PID of this process, the daemon id.
+func writeMirrorPIDFile(id string) *probe.Error {
+	if e := os.MkdirAll(getMirrorDaemonDir(), 0700); e != nil {
+		return probe.NewError(e).Trace(getMirrorDaemonDir())
+	}
+	pidFile := getMirrorPIDFile(id)
+	if e := ioutil.WriteFile(pidFile, []byte(strconv.Itoa(os.Getpid())+"\n"), 0600); e != nil {
+		return probe.NewError(e).Trace(pidFile)
+	}
+	return nil
+}
+
+// removeMirrorPIDFile - removes the PID file of the daemon id.
+func removeMirrorPIDFile(id string) *probe.Error {
+	pidFile := getMirrorPIDFile(id)
+	if e := os.Remove(pidFile); e != nil && !os.IsNotExist(e) {
+		return probe.NewError(e).Trace(pidFile)
+	}
+	return nil
+}
+
+// startMirrorDaemon - starts this program with args in the background
+// as the daemon id, detached from the terminal and with its output
+// appended to a file of the daemon folder. The daemon writes its own
+// PID file, the returned process is not waited for by mc.
+func startMirrorDaemon(id string, args []string) (*os.Process, string, *probe.Error) {
+	if pid, err := readMirrorPID(id); err == nil && isProcessRunning(pid) {
+		return nil, "", probe.NewError(fmt.Errorf("daemon `%s` is already running with PID %d", id, pid))
+	}
+	dir := getMirrorDaemonDir()
+	if e := os.MkdirAll(dir, 0700); e != nil {
+		return nil, "", probe.NewError(e).Trace(dir)
+	}
+	program, e := os.Executable()
+	if e != nil {
+		return nil, "", probe.NewError(e)
+	}
+	outFile := filepath.Join(dir, id+".out")
+	out, e := os.OpenFile(outFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if e != nil {
+		return nil, "", probe.NewError(e).Trace(outFile)
+	}
+	defer out.Close()
+
+	cmd := exec.Command(program, args...)
+	cmd.Env = append(os.Environ(), mirrorDaemonEnv+"="+id)
+	cmd.Stdout, cmd.Stderr = out, out
+	cmd.SysProcAttr = daemonSysProcAttr()
+	if e = cmd.Start(); e != nil {
+		return nil, "", probe.NewError(e).Trace(program)
+	}
+	return cmd.Process, outFile, nil
+}
+
+// listMirrorDaemons - returns the state of every daemon with a PID file.
+func listMirrorDaemons() ([]mirrorDaemonMessage, *probe.Error) {
+	pidFiles, e := filepath.Glob(filepath.Join(getMirrorDaemonDir(), "*.pid"))
+	if e != nil {
+		return nil, probe.NewError(e)
+	}
+	sort.Strings(pidFiles)
+	var daemons []mirrorDaemonMessage
+	for _, pidFile := range pidFiles {
+		id := strings.TrimSuffix(filepath.Base(pidFile), ".pid")
+		pid, err := readMirrorPID(id)
+		if err != nil {
+			// Removed by a daemon which just stopped.
+			if os.IsNotExist(err.ToGoError()) {
+				continue
+			}
+			return nil, err.Trace(id)
+		}
+		state := "running"
+		if !isProcessRunning(pid) {
+			state = "stale"
+		}
+		daemons = append(daemons, mirrorDaemonMessage{
+			op:     "status",
+			ID:     id,
+			PID:    pid,
+			State:  state,
+			Output: filepath.Join(getMirrorDaemonDir(), id+".out"),
+		})
+	}
+	return daemons, nil
+}
+
+// stopMirrorDaemon - asks the daemon to stop and waits for it to remove
+// its PID file, once its transfers are drained. The PID files of stale
+// daemons are removed.
+func stopMirrorDaemon(daemon mirrorDaemonMessage) (mirrorDaemonMessage, *probe.Error) {
+	daemon.op = "stop"
+	if daemon.State == "stale" {
+		return daemon, removeMirrorPIDFile(daemon.ID)
+	}
+	if e := terminateProcess(daemon.PID); e != nil {
+		return daemon, probe.NewError(e).Trace(daemon.ID)
+	}
+	for {
+		if _, e := os.Stat(getMirrorPIDFile(daemon.ID)); os.IsNotExist(e) {
+			break
+		}
+		// The daemon died before removing its PID file.
+		if !isProcessRunning(daemon.PID) {
+			if err := removeMirrorPIDFile(daemon.ID); err != nil {
+				return daemon, err
+			}
+			break
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	daemon.State = "stopped"
+	return daemon, nil
+}
+
+// checkMirrorDaemonSyntax - verifies the flags of mirror --daemon.
+func checkMirrorDaemonSyntax(ctx *cli.Context) {
+	if !ctx.Bool("watch") {
+		fatalIf(errInvalidArgument(), "--daemon requires --watch.")
+	}
+	if ctx.String("log-file") == "" {
+		fatalIf(errInvalidArgument(), "--daemon requires --log-file.")
+	}
+	if ctx.Bool("fake") || ctx.String("multi-master") != "" {
+		fatalIf(errInvalidArgument(), "--daemon cannot be used with --fake or --multi-master.")
+	}
+}
+
+// mainMirrorDaemon - starts this mirror command in the background, the
+// log file is opened relative to the current folder by the daemon too.
+func mainMirrorDaemon(ctx *cli.Context) error {
+	args := ctx.Args()
+	id := mirrorDaemonID(args.Get(0), args.Get(1))
+	proc, outFile, err := startMirrorDaemon(id, os.Args[1:])
+	fatalIf(err, "Unable to start the mirror daemon.")
+	printMsg(mirrorDaemonMessage{op: "start", ID: id, PID: proc.Pid, State: "started", Output: outFile})
+	errorIf(probe.NewError(proc.Release()), "Unable to release the mirror daemon.")
+	return nil
+}
+
+// mainMirrorControl - handles mirror stop and mirror status, of the
+// daemon id only if not empty.
+func mainMirrorControl(op, id string) error {
+	console.SetColor("Mirror", color.New(color.FgGreen, color.Bold))
+	if !mirrorDaemonSupported {
+		fatalIf(probe.NewError(errors.New("mirror daemons are not supported on this platform")), "Unable to "+op+" mirror daemons.")
+	}
+	daemons, err := listMirrorDaemons()
+	fatalIf(err, "Unable to list the mirror daemons.")
+	if id != "" {
+		var matched []mirrorDaemonMessage
+		for _, daemon := range daemons {
+			if daemon.ID == id {
+				matched = append(matched, daemon)
+			}
+		}
+		if len(matched) == 0 {
+			fatalIf(probe.NewError(fmt.Errorf("no mirror daemon `%s`", id)), "Unable to "+op+" daemon `"+id+"`.")
+		}
+		daemons = matched
+	}
+	if len(daemons) == 0 && !globalJSON {
+		console.Infoln("No mirror daemon is running.")
+		return nil
+	}
+	var stopErr *probe.Error
+	for _, daemon := range daemons {
+		if op == "stop" {
+			if daemon, err = stopMirrorDaemon(daemon); err != nil {
+				errorIf(err, "Unable to stop daemon `"+daemon.ID+"`.")
+				stopErr = err
+				continue
+			}
+		}
+		printMsg(daemon)
+	}
+	if stopErr != nil {
+		return exitStatus(globalErrorExitStatus)
+	}
+	return nil
+}
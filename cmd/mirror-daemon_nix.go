@@ -0,0 +1,41 @@
+// +build !windows
+
+/*
+ * MinIO Client (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import "syscall"
+
+// Daemons are detached from the terminal in a session of their own.
+const mirrorDaemonSupported = true
+
+// daemonSysProcAttr - starts daemons in a new session, away from the
+// signals of the terminal.
+func daemonSysProcAttr() *syscall.SysProcAttr {
+	return &syscall.SysProcAttr{Setsid: true}
+}
+
+// isProcessRunning - reports whether the process pid exists.
+func isProcessRunning(pid int) bool {
+	e := syscall.Kill(pid, 0)
+	return e == nil || e == syscall.EPERM
+}
+
+// terminateProcess - sends SIGTERM to the process pid.
+func terminateProcess(pid int) error {
+	return syscall.Kill(pid, syscall.SIGTERM)
+}
@@ -0,0 +1,181 @@
+// +build !windows
+
+/*
+ * MinIO Client (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestMirrorDaemonProcess is the daemon started by TestMirrorDaemon, it
+// runs the mirror command line of MC_TEST_MIRROR_DAEMON_ARGS.
+func TestMirrorDaemonProcess(t *testing.T) {
+	args := os.Getenv("MC_TEST_MIRROR_DAEMON_ARGS")
+	if args == "" || !isMirrorDaemon() {
+		return
+	}
+	if e := registerApp("mc").Run(strings.Split(args, "\n")); e != nil {
+		t.Fatal(e)
+	}
+}
+
+// waitFor - waits up to 10 seconds for done to be true.
+func waitFor(t *testing.T, what string, done func() bool) {
+	for i := 0; !done(); i++ {
+		if i == 100 {
+			t.Fatalf("Timed out waiting for %s", what)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
+
+func TestMirrorDaemon(t *testing.T) {
+	dir, e := ioutil.TempDir("", "mc-mirror-daemon-")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.RemoveAll(dir)
+	srcDir, tgtDir := filepath.Join(dir, "src"), filepath.Join(dir, "tgt")
+	if e = os.Mkdir(srcDir, 0700); e != nil {
+		t.Fatal(e)
+	}
+	if e = ioutil.WriteFile(filepath.Join(srcDir, "a.txt"), []byte("hello"), 0600); e != nil {
+		t.Fatal(e)
+	}
+	configDir := filepath.Join(dir, ".mc")
+	defer setMcConfigDir(mcCustomConfigDir)
+	setMcConfigDir(configDir)
+
+	args := []string{"mc", "--config-dir", configDir, "mirror", "--watch", "--daemon",
+		"--log-file", filepath.Join(dir, "mirror.log"), srcDir, tgtDir}
+	defer os.Unsetenv("MC_TEST_MIRROR_DAEMON_ARGS")
+	os.Setenv("MC_TEST_MIRROR_DAEMON_ARGS", strings.Join(args, "\n"))
+
+	id := mirrorDaemonID(srcDir, tgtDir)
+	proc, _, err := startMirrorDaemon(id, []string{"-test.run=^TestMirrorDaemonProcess$"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer proc.Kill()
+
+	waitFor(t, "the PID file", func() bool {
+		pid, err := readMirrorPID(id)
+		return err == nil && pid == proc.Pid
+	})
+	waitFor(t, "the initial mirror", func() bool {
+		_, e := os.Stat(filepath.Join(tgtDir, "a.txt"))
+		return e == nil
+	})
+	if _, _, err = startMirrorDaemon(id, nil); err == nil {
+		t.Fatal("Expected a second daemon mirroring the same folders to be refused")
+	}
+
+	daemons, err := listMirrorDaemons()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(daemons) != 1 || daemons[0].ID != id || daemons[0].PID != proc.Pid || daemons[0].State != "running" {
+		t.Fatalf("Expected daemon %s to be running with PID %d, got %+v", id, proc.Pid, daemons)
+	}
+
+	stopped, err := stopMirrorDaemon(daemons[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stopped.State != "stopped" {
+		t.Fatalf("Expected the daemon to be stopped, got %+v", stopped)
+	}
+	state, e := proc.Wait()
+	if e != nil {
+		t.Fatal(e)
+	}
+	if !state.Success() {
+		output, _ := ioutil.ReadFile(filepath.Join(getMirrorDaemonDir(), id+".out"))
+		t.Fatalf("Expected the daemon to exit cleanly, got %s:\n%s", state, output)
+	}
+	if _, e = os.Stat(getMirrorPIDFile(id)); !os.IsNotExist(e) {
+		t.Fatalf("Expected the PID file to be removed, got %v", e)
+	}
+	// The log is flushed on shutdown.
+	if entries := readMirrorLog(t, filepath.Join(dir, "mirror.log")); len(entries) != 1 || entries[0].Status != "success" {
+		t.Fatalf("Expected the copy of a.txt to be logged, got %+v", entries)
+	}
+	if daemons, err = listMirrorDaemons(); err != nil || len(daemons) != 0 {
+		t.Fatalf("Expected no daemon, got %+v %v", daemons, err)
+	}
+
+	// PID files left by daemons which died are removed by stop.
+	if err = writeMirrorPIDFile(id); err != nil {
+		t.Fatal(err)
+	}
+	stale := mirrorDaemonMessage{ID: id, PID: proc.Pid, State: "stale"}
+	if _, err = stopMirrorDaemon(stale); err != nil {
+		t.Fatal(err)
+	}
+	if _, e = os.Stat(getMirrorPIDFile(id)); !os.IsNotExist(e) {
+		t.Fatalf("Expected the stale PID file to be removed, got %v", e)
+	}
+}
+
+func TestMirrorDaemonStopID(t *testing.T) {
+	dir, e := ioutil.TempDir("", "mc-mirror-daemon-")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.RemoveAll(dir)
+	defer setMcConfigDir(mcCustomConfigDir)
+	setMcConfigDir(dir)
+
+	// A process which exited, for the daemons to be stale.
+	exited := exec.Command("true")
+	if e = exited.Run(); e != nil {
+		t.Fatal(e)
+	}
+	if e = os.MkdirAll(getMirrorDaemonDir(), 0700); e != nil {
+		t.Fatal(e)
+	}
+	stopped, kept := mirrorDaemonID("src", "tgt"), mirrorDaemonID("src", "other")
+	for _, id := range []string{stopped, kept} {
+		if !isMirrorDaemonID(id) {
+			t.Fatalf("Expected %q to be told as a daemon ID", id)
+		}
+		if e = ioutil.WriteFile(getMirrorPIDFile(id), []byte(strconv.Itoa(exited.Process.Pid)+"\n"), 0600); e != nil {
+			t.Fatal(e)
+		}
+	}
+	if isMirrorDaemonID("backup") {
+		t.Fatal("Expected a folder name not to be told as a daemon ID")
+	}
+
+	if e = mainMirrorControl("stop", stopped); e != nil {
+		t.Fatal(e)
+	}
+	if _, e = os.Stat(getMirrorPIDFile(stopped)); !os.IsNotExist(e) {
+		t.Fatalf("Expected the PID file of %s to be removed, got %v", stopped, e)
+	}
+	if _, e = os.Stat(getMirrorPIDFile(kept)); e != nil {
+		t.Fatalf("Expected the other daemon to be left alone, got %v", e)
+	}
+}
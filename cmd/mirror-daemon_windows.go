@@ -0,0 +1,43 @@
+// +build windows
+
+/*
+ * MinIO Client (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"errors"
+	"syscall"
+)
+
+// Processes cannot be detached nor stopped gracefully, mirror --daemon
+// mirrors in the foreground.
+const mirrorDaemonSupported = false
+
+// daemonSysProcAttr - unused, daemons are not started on windows.
+func daemonSysProcAttr() *syscall.SysProcAttr {
+	return nil
+}
+
+// isProcessRunning - unused, daemons are not started on windows.
+func isProcessRunning(pid int) bool {
+	return false
+}
+
+// terminateProcess - unused, daemons are not started on windows.
+func terminateProcess(pid int) error {
+	return errors.New("not supported on windows")
+}
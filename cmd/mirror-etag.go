@@ -0,0 +1,78 @@
+/*
+ * MinIO Client (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"io"
+	"strings"
+
+	"github.com/minio/mc/pkg/probe"
+)
+
+// mirrorSourceETagKey - metadata of the objects uploaded by mirror
+// --etag-compare, the ETag of their source when they were uploaded.
+const mirrorSourceETagKey = "X-Amz-Meta-Mc-Source-Etag"
+
+// sourceContentETag - returns the ETag of the object content, or the MD5
+// of the file content which has none. Files are read to the end.
+func sourceContentETag(alias string, content *clientContent, encKeyDB map[string][]prefixSSEPair) (string, *probe.Error) {
+	if etag := strings.Trim(content.ETag, "\""); etag != "" {
+		return etag, nil
+	}
+	urlStr := content.URL.String()
+	reader, _, err := getSourceStream(context.Background(), alias, urlStr, false, getSSE(urlStr, encKeyDB[alias]), 0, 0)
+	if err != nil {
+		return "", err.Trace(urlStr)
+	}
+	defer reader.Close()
+	hash := md5.New()
+	if _, e := io.Copy(hash, reader); e != nil {
+		return "", probe.NewError(e).Trace(urlStr)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// etagCompareDiff - compares the current ETag of the source with the one
+// stored in the metadata of the target by an earlier mirror. Targets
+// uploaded without --etag-compare hold none, ok is false for them and
+// they are compared by size and metadata as usual.
+func etagCompareDiff(sourceAlias string, srcContent *clientContent, targetAlias string, tgtContent *clientContent, encKeyDB map[string][]prefixSSEPair) (diff differType, ok bool, err *probe.Error) {
+	tgtURL := tgtContent.URL.String()
+	tgtClnt, err := newClientFromAlias(targetAlias, tgtURL)
+	if err != nil {
+		return differInNone, false, err.Trace(tgtURL)
+	}
+	stat, err := tgtClnt.Stat(false, true, false, getSSE(tgtURL, encKeyDB[targetAlias]))
+	if err != nil {
+		return differInNone, false, err.Trace(tgtURL)
+	}
+	storedETag := stat.Metadata[mirrorSourceETagKey]
+	if storedETag == "" {
+		return differInNone, false, nil
+	}
+	etag, err := sourceContentETag(sourceAlias, srcContent, encKeyDB)
+	if err != nil {
+		return differInNone, false, err
+	}
+	if etag != storedETag {
+		return differInETag, true, nil
+	}
+	return differInNone, true, nil
+}
@@ -31,6 +31,7 @@ import (
 	"github.com/fatih/color"
 	"github.com/minio/cli"
 	json "github.com/minio/mc/pkg/colorjson"
+	"github.com/minio/mc/pkg/oplog"
 	"github.com/minio/mc/pkg/probe"
 	"github.com/minio/minio/pkg/console"
 )
@@ -49,12 +50,16 @@ var (
 		},
 		cli.BoolFlag{
 			Name:  "fake",
-			Usage: "perform a fake mirror operation",
+			Usage: "print the objects a mirror would copy, remove and skip",
 		},
 		cli.BoolFlag{
 			Name:  "watch, w",
 			Usage: "watch and synchronize changes",
 		},
+		cli.BoolFlag{
+			Name:  "daemon",
+			Usage: "with --watch, mirror in the background, controlled with 'mirror stop' and 'mirror status'",
+		},
 		cli.BoolFlag{
 			Name:  "remove",
 			Usage: "remove extraneous object(s) on target",
@@ -88,6 +93,10 @@ var (
 			Name:  "storage-class, sc",
 			Usage: "specify storage class for new object(s) on target",
 		},
+		cli.BoolFlag{
+			Name:  "no-preserve-metadata",
+			Usage: "do not mirror the content type, caching headers, storage class and user metadata of the source objects",
+		},
 		cli.StringFlag{
 			Name:  "encrypt",
 			Usage: "encrypt/decrypt objects (using server-side encryption with server managed keys)",
@@ -96,6 +105,37 @@ var (
 			Name:  "attr",
 			Usage: "add custom metadata for all objects",
 		},
+		cli.StringFlag{
+			Name:  "links",
+			Usage: "handling of symbolic links in local source folders, one of [skip, follow, copy-as-file]",
+			Value: "skip",
+		},
+		cli.StringFlag{
+			Name:  "part-size",
+			Usage: "multipart upload part size, e.g. 64MiB (minimum 5MiB, raised automatically for large objects)",
+		},
+		atomicFlag,
+		attemptsFlag,
+		idleTimeoutFlag,
+		maxObjectSizeFlag,
+		orderFlag,
+		manifestFlag,
+		reportFlag,
+		excludeHiddenFlag,
+		cli.BoolFlag{
+			Name:  "etag-compare",
+			Usage: "store the ETag of the source in the metadata of uploads and compare by it instead of size and metadata, files are read to be hashed",
+		},
+		noSniffFlag,
+		listParallelFlag,
+		cli.StringFlag{
+			Name:  "log-file",
+			Usage: "log every copy and remove as a line of JSON to a file",
+		},
+		cli.StringFlag{
+			Name:  "log-max-size",
+			Usage: "rotate the log file once it reaches this size, e.g. 100MiB",
+		},
 	}
 )
 
@@ -105,12 +145,14 @@ var mirrorCmd = cli.Command{
 	Usage:  "synchronize object(s) to a remote site",
 	Action: mainMirror,
 	Before: setGlobalsFromContext,
-	Flags:  append(append(mirrorFlags, ioFlags...), globalFlags...),
+	Flags:  append(append(append(mirrorFlags, bandwidthLimitFlags...), ioFlags...), globalFlags...),
 	CustomHelpTemplate: `NAME:
   {{.HelpName}} - {{.Usage}}
 
 USAGE:
   {{.HelpName}} [FLAGS] SOURCE TARGET
+  {{.HelpName}} stop [ID]
+  {{.HelpName}} status
 
 FLAGS:
   {{range .VisibleFlags}}{{.}}
@@ -169,6 +211,65 @@ EXAMPLES:
   15. Cross mirror between sites in a multi-master deployment.
       Site-A: {{.Prompt}} {{.HelpName}} --watch --multi-master splunk-smartstore1 siteA siteB
       Site-B: {{.Prompt}} {{.HelpName}} --watch --multi-master splunk-smartstore1 siteB siteA
+
+  16. Mirror a local folder recursively to Amazon S3 cloud storage, storing symbolic links to files as regular objects.
+      {{.Prompt}} {{.HelpName}} --links copy-as-file backup/ s3/archive
+
+  17. Mirror a local folder recursively to Amazon S3 cloud storage, logging every operation to a file rotated at 100MiB.
+      {{.Prompt}} {{.HelpName}} --log-file /var/log/mc-mirror.log --log-max-size 100MiB backup/ s3/archive
+
+  18. Preview which objects would be copied, removed and skipped before mirroring a local folder to Amazon S3 cloud storage.
+      {{.Prompt}} {{.HelpName}} --fake --remove backup/ s3/archive
+
+  19. Mirror a local folder to Amazon S3 cloud storage, uploading to temporary objects moved into place when complete.
+      {{.Prompt}} {{.HelpName}} --atomic backup/ s3/archive
+
+  20. Mirror a bucket with many top-level prefixes to another site, listing 16 prefixes at once.
+      {{.Prompt}} {{.HelpName}} --parallel 16 s3/logs play/logs
+
+  21. Mirror a bucket to a local folder downloading at most 5MiB/s.
+      {{.Prompt}} {{.HelpName}} --limit-download 5MiB s3/archive backup/
+
+  22. Mirror a bucket over a flaky connection, copying each object up to 3 times before giving up.
+      {{.Prompt}} {{.HelpName}} --attempts 3 s3/archive backup/
+
+  23. Mirror a bucket in a nightly job without transferring any object larger than 1GiB.
+      {{.Prompt}} {{.HelpName}} --max-object-size 1GiB s3/archive backup/
+
+  24. Continuously mirror a local folder to Amazon S3 cloud storage in the background, then list and stop the
+      daemons. 'mirror stop' waits for the transfers in progress to complete. On Windows '--daemon' mirrors in
+      the foreground.
+      {{.Prompt}} {{.HelpName}} --watch --daemon --log-file /var/log/mc-mirror.log /var/lib/backups s3/backups
+      {{.Prompt}} {{.HelpName}} status
+      {{.Prompt}} {{.HelpName}} stop
+
+  25. Stop only the daemon with the ID 3f1c0a9b27de, as listed by 'mirror status'.
+      {{.Prompt}} {{.HelpName}} stop 3f1c0a9b27de
+
+  26. Mirror a bucket copying the smallest objects first, for quick progress. The whole listing is held in
+      memory before the first copy starts.
+      {{.Prompt}} {{.HelpName}} --order size-asc s3/archive backup/
+
+  27. Mirror a local folder preserving its attributes, re-uploading only the files whose content changed
+      since the last mirror, even if their modification times drifted.
+      {{.Prompt}} {{.HelpName}} --etag-compare --overwrite -a /var/lib/backups s3/backups
+
+  28. Mirror a build output to a bucket and record the key, size and ETag of every object uploaded to
+      'deploy.json', written only if the mirror succeeds.
+      {{.Prompt}} {{.HelpName}} --overwrite --manifest deploy.json dist/ s3/website
+
+  29. Mirror a local repository without its hidden files and folders, nor its temporary files.
+      {{.Prompt}} {{.HelpName}} --exclude-hidden --exclude "*.tmp" ~/src/project s3/backups/project
+
+  30. Mirror a bucket to a remote site without aborting slow transfers, only transfers stalled for 5 minutes.
+      {{.Prompt}} {{.HelpName}} --idle-timeout 5m s3/archive remote/archive
+
+  31. Mirror a bucket to another cloud without the content type, caching headers, storage class and user
+      metadata of its objects, which are kept by default.
+      {{.Prompt}} {{.HelpName}} --no-preserve-metadata s3/mybucket gcs/mybucket
+
+  32. Mirror a website to a bucket in a CI job and write the totals of the mirror to a JSON report.
+      {{.Prompt}} {{.HelpName}} --overwrite --report mirror-report.json dist/ s3/website
 `,
 }
 
@@ -213,6 +314,27 @@ type mirrorJob struct {
 
 	multiMasterEnable bool
 	multiMasterSTag   string
+
+	// compare objects by the ETag of their source stored in their
+	// metadata, see etagCompareDiff
+	isETagCompare bool
+
+	// upload objects without the metadata and storage class of their
+	// source
+	isNoPreserveMetadata bool
+
+	// log of the operations, nil if not requested
+	opLog *oplog.Logger
+
+	// objects copied, written once the mirror completes, nil if not
+	// requested
+	manifest *transferManifest
+
+	// objects failed, by error category
+	failures *errorSummary
+
+	// totals written once the mirror completes, nil if not requested
+	report *transferReport
 }
 
 // mirrorMessage container for file mirror messages
@@ -301,7 +423,14 @@ func (mj *mirrorJob) doMirror(ctx context.Context, cancelMirror context.CancelFu
 
 	if mj.storageClass != "" {
 		sURLs.TargetContent.Metadata["X-Amz-Storage-Class"] = mj.storageClass
+	} else if !mj.isNoPreserveMetadata && sourceURL.Type == objectStorage && targetURL.Type == objectStorage {
+		// Objects are uploaded to the storage class of their source,
+		// the default one is not set so that the target picks its own.
+		if storageClass := strings.ToUpper(sURLs.SourceContent.StorageClass); storageClass != "" && storageClass != "STANDARD" {
+			sURLs.TargetContent.Metadata["X-Amz-Storage-Class"] = storageClass
+		}
 	}
+	sURLs.replaceMetadata = mj.isNoPreserveMetadata
 
 	if mj.multiMasterEnable {
 		// Set multiMasterETagKey for the target.
@@ -324,6 +453,14 @@ func (mj *mirrorJob) doMirror(ctx context.Context, cancelMirror context.CancelFu
 		}
 	}
 
+	if mj.isETagCompare {
+		etag, err := sourceContentETag(sourceAlias, sURLs.SourceContent, mj.encKeyDB)
+		if err != nil {
+			return sURLs.WithError(err)
+		}
+		sURLs.TargetContent.Metadata[mirrorSourceETagKey] = etag
+	}
+
 	// Initialize additional target user metadata.
 	sURLs.TargetContent.UserMetadata = mj.userMetadata
 
@@ -336,7 +473,7 @@ func (mj *mirrorJob) doMirror(ctx context.Context, cancelMirror context.CancelFu
 		TotalCount: sURLs.TotalCount,
 		TotalSize:  sURLs.TotalSize,
 	})
-	return uploadSourceToTargetURL(ctx, sURLs, mj.status, mj.encKeyDB)
+	return uploadSourceToTargetURLAttempts(ctx, sURLs, mj.status, mj.encKeyDB)
 }
 
 // Update progress status
@@ -349,15 +486,17 @@ func (mj *mirrorJob) monitorMirrorStatus() (errDuringMirror bool) {
 		if sURLs.Error != nil {
 			switch {
 			case sURLs.SourceContent != nil:
-				if !isErrIgnored(sURLs.Error) {
+				if isMirrorFailure(sURLs) {
 					errorIf(sURLs.Error.Trace(sURLs.SourceContent.URL.String()),
 						fmt.Sprintf("Failed to copy `%s`.", sURLs.SourceContent.URL.String()))
+					mj.failures.add(sURLs.SourceContent.URL.String(), sURLs.Error)
 					errDuringMirror = true
 				}
 			case sURLs.TargetContent != nil:
 				// When sURLs.SourceContent is nil, we know that we have an error related to removing
 				errorIf(sURLs.Error.Trace(sURLs.TargetContent.URL.String()),
 					fmt.Sprintf("Failed to remove `%s`.", sURLs.TargetContent.URL.String()))
+				mj.failures.add(sURLs.TargetContent.URL.String(), sURLs.Error)
 				errDuringMirror = true
 			default:
 				errorIf(sURLs.Error.Trace(), "Failed to perform mirroring.")
@@ -369,6 +508,16 @@ func (mj *mirrorJob) monitorMirrorStatus() (errDuringMirror bool) {
 			}
 		}
 
+		mj.logOperation(sURLs)
+		mj.report.add(sURLs)
+
+		if sURLs.Error == nil && sURLs.SourceContent != nil && !mj.isFake {
+			if err := mj.manifest.add(sURLs); err != nil {
+				errorIf(err, "Unable to record `%s` in the manifest.", sURLs.TargetContent.URL.String())
+				errDuringMirror = true
+			}
+		}
+
 		if sURLs.SourceContent != nil {
 		} else if sURLs.TargetContent != nil {
 			// Construct user facing message and path.
@@ -381,6 +530,38 @@ func (mj *mirrorJob) monitorMirrorStatus() (errDuringMirror bool) {
 	return
 }
 
+// logOperation - writes the outcome of a copy or remove to the operation log.
+func (mj *mirrorJob) logOperation(sURLs URLs) {
+	if mj.opLog == nil {
+		return
+	}
+	entry := oplog.Entry{Status: "success"}
+	switch {
+	case sURLs.SourceContent != nil:
+		entry.Action = "copy"
+		entry.Source = mirrorPath(sURLs.SourceAlias, sURLs.SourceContent)
+		entry.Bytes = sURLs.SourceContent.Size
+		if sURLs.TargetContent != nil {
+			entry.Target = mirrorPath(sURLs.TargetAlias, sURLs.TargetContent)
+		}
+	case sURLs.TargetContent != nil:
+		entry.Action = "remove"
+		entry.Target = mirrorPath(sURLs.TargetAlias, sURLs.TargetContent)
+		entry.Bytes = sURLs.TargetContent.Size
+	default:
+		entry.Action = "mirror"
+	}
+	if mj.isFake {
+		entry.Status = "fake"
+	}
+	if sURLs.Error != nil {
+		entry.Status = "error"
+		entry.Bytes = 0
+		entry.Error = sURLs.Error.ToGoError().Error()
+	}
+	errorIf(probe.NewError(mj.opLog.Log(entry)), "Unable to write to the log file.")
+}
+
 // this goroutine will watch for notifications, and add modified objects to the queue
 func (mj *mirrorJob) watchMirror(ctx context.Context, cancelMirror context.CancelFunc) {
 	for {
@@ -427,6 +608,11 @@ func (mj *mirrorJob) watchMirror(ctx context.Context, cancelMirror context.Cance
 			// newClient needs the unexpanded  path, newCLientURL needs the expanded path
 			targetAlias, expandedTargetPath, _ := mustExpandAlias(targetPath)
 			targetURL := newClientURL(expandedTargetPath)
+			_, expandedTargetURL, _ := mustExpandAlias(mj.targetURL)
+			if err := checkTargetPath(expandedTargetURL, expandedTargetPath); err != nil {
+				mj.statusCh <- URLs{Error: err.Trace(eventPath)}
+				continue
+			}
 
 			sourcePath := filepath.ToSlash(filepath.Join(sourceAlias, sourceURL.Path))
 			srcSSE := getSSE(sourcePath, mj.encKeyDB[sourceAlias])
@@ -556,6 +742,58 @@ func (mj *mirrorJob) watchMirror(ctx context.Context, cancelMirror context.Cance
 	}
 }
 
+// mirrorAction is what mirroring does with the URLs of prepareURLs.
+type mirrorAction int
+
+const (
+	mirrorActionCopy mirrorAction = iota
+	mirrorActionRemove
+	mirrorActionSkip
+	// Skipped by --max-object-size, noticed and counted.
+	mirrorActionSkipTooLarge
+	mirrorActionError
+)
+
+// prepareURLs - lists the differences between source and target.
+func (mj *mirrorJob) prepareURLs() <-chan URLs {
+	isMetadata := len(mj.userMetadata) > 0 || mj.isPreserve
+	URLsCh := prepareMirrorURLs(mj.sourceURL, mj.targetURL, mj.isOverwrite, mj.isRemove, isMetadata, mj.isETagCompare, mj.excludeOptions, mj.encKeyDB)
+	return orderCopyURLs(URLsCh, globalCopyOrder)
+}
+
+// isMirrorFailure - reports whether the error of sURLs fails mirroring,
+// the sources with errors which are not critical are skipped. Both the
+// plan of a fake mirror and the actual mirroring use it.
+func isMirrorFailure(sURLs URLs) bool {
+	return sURLs.SourceContent == nil || !isErrIgnored(sURLs.Error)
+}
+
+// action - returns what mirroring does with sURLs, and the reason when
+// it is skipped. Both the plan of a fake mirror and the actual mirroring
+// use it so that they agree.
+func (mj *mirrorJob) action(sURLs URLs) (mirrorAction, string) {
+	switch {
+	case sURLs.Error != nil:
+		return mirrorActionError, ""
+	case sURLs.unchanged:
+		return mirrorActionSkip, "unchanged"
+	case sURLs.SourceContent != nil:
+		if mj.olderThan != "" && isOlder(sURLs.SourceContent.Time, mj.olderThan) {
+			return mirrorActionSkip, "not older than " + mj.olderThan
+		}
+		if mj.newerThan != "" && isNewer(sURLs.SourceContent.Time, mj.newerThan) {
+			return mirrorActionSkip, "not newer than " + mj.newerThan
+		}
+		if isTooLarge(sURLs.SourceContent) {
+			return mirrorActionSkipTooLarge, "larger than --max-object-size " + humanizeSize(globalMaxObjectSize)
+		}
+		return mirrorActionCopy, ""
+	case sURLs.TargetContent != nil && mj.isRemove:
+		return mirrorActionRemove, ""
+	}
+	return mirrorActionSkip, ""
+}
+
 func (mj *mirrorJob) watchURL(sourceClient Client) *probe.Error {
 	return mj.watcher.Join(sourceClient, true)
 }
@@ -566,8 +804,7 @@ func (mj *mirrorJob) startMirror(ctx context.Context, cancelMirror context.Cance
 	mj.m.Lock()
 	defer mj.m.Unlock()
 
-	isMetadata := len(mj.userMetadata) > 0 || mj.isPreserve
-	URLsCh := prepareMirrorURLs(mj.sourceURL, mj.targetURL, mj.isFake, mj.isOverwrite, mj.isRemove, isMetadata, mj.excludeOptions, mj.encKeyDB)
+	URLsCh := mj.prepareURLs()
 
 	for {
 		select {
@@ -578,18 +815,16 @@ func (mj *mirrorJob) startMirror(ctx context.Context, cancelMirror context.Cance
 				}
 				return
 			}
-			if sURLs.Error != nil {
+			action, _ := mj.action(sURLs)
+			switch action {
+			case mirrorActionError:
 				mj.statusCh <- sURLs
 				continue
-			}
-
-			if sURLs.SourceContent != nil {
-				if mj.olderThan != "" && isOlder(sURLs.SourceContent.Time, mj.olderThan) {
-					continue
-				}
-				if mj.newerThan != "" && isNewer(sURLs.SourceContent.Time, mj.newerThan) {
-					continue
-				}
+			case mirrorActionSkip:
+				continue
+			case mirrorActionSkipTooLarge:
+				skipTooLarge(sURLs.SourceAlias, sURLs.SourceContent)
+				continue
 			}
 
 			if sURLs.SourceContent != nil {
@@ -603,11 +838,11 @@ func (mj *mirrorJob) startMirror(ctx context.Context, cancelMirror context.Cance
 			// Save totalSize.
 			sURLs.TotalSize = mj.status.Get()
 
-			if sURLs.SourceContent != nil {
+			if action == mirrorActionCopy {
 				mj.queueCh <- func() URLs {
 					return mj.doMirror(ctx, cancelMirror, sURLs)
 				}
-			} else if sURLs.TargetContent != nil && mj.isRemove {
+			} else {
 				mj.queueCh <- func() URLs {
 					return mj.doRemove(sURLs)
 				}
@@ -619,10 +854,12 @@ func (mj *mirrorJob) startMirror(ctx context.Context, cancelMirror context.Cance
 			cancelMirror()
 			return
 		case <-mj.stopCh:
+			// Stopped mirrors complete the transfers in progress, the
+			// watch stops between events and the context is canceled
+			// once both are done, see mirror.
 			if stopParallel != nil {
 				stopParallel()
 			}
-			cancelMirror()
 			return
 		}
 	}
@@ -657,6 +894,7 @@ func (mj *mirrorJob) mirror(ctx context.Context, cancelMirror context.CancelFunc
 	// Close statusCh when both watch & mirror quits
 	go func() {
 		wg.Wait()
+		cancelMirror()
 		close(mj.statusCh)
 	}()
 
@@ -735,7 +973,7 @@ func copyBucketPolicies(srcClt, dstClt Client, isOverwrite bool) *probe.Error {
 }
 
 // runMirror - mirrors all buckets to another S3 server
-func runMirror(srcURL, dstURL string, ctx *cli.Context, encKeyDB map[string][]prefixSSEPair) bool {
+func runMirror(srcURL, dstURL string, ctx *cli.Context, encKeyDB map[string][]prefixSSEPair, opLog *oplog.Logger) bool {
 	// This is kept for backward compatibility, `--force` means
 	// --overwrite.
 	isOverwrite := ctx.Bool("force")
@@ -789,11 +1027,29 @@ func runMirror(srcURL, dstURL string, ctx *cli.Context, encKeyDB map[string][]pr
 		multiMasterSTag,
 		userMetaMap,
 		encKeyDB)
-
-	go func() {
-		<-mj.trapCh
-		os.Exit(globalErrorExitStatus)
-	}()
+	mj.opLog = opLog
+	mj.isETagCompare = ctx.Bool("etag-compare")
+	mj.isNoPreserveMetadata = ctx.Bool("no-preserve-metadata")
+	mj.manifest = newTransferManifest(ctx.String("manifest"), encKeyDB)
+	mj.failures = newErrorSummary()
+	mj.report = newTransferReport(ctx.String("report"), "mirror")
+
+	if isMirrorDaemon() {
+		// Daemons are stopped with SIGTERM, they stop watching and
+		// drain their transfers instead of exiting at once.
+		trapCh := mj.trapCh
+		mj.trapCh = nil
+		go func() {
+			<-trapCh
+			close(mj.stopCh)
+		}()
+	} else {
+		go func() {
+			<-mj.trapCh
+			errorIf(probe.NewError(mj.opLog.Close()), "Unable to flush the log file.")
+			exit(globalErrorExitStatus)
+		}()
+	}
 
 	if mirrorAllBuckets {
 		// Synchronize buckets using dirDifference function
@@ -897,15 +1153,63 @@ func runMirror(srcURL, dstURL string, ctx *cli.Context, encKeyDB map[string][]pr
 		}
 	}
 
+	if mj.isFake && !mj.isWatch {
+		// Print what mirroring would do instead of faking the copies.
+		plan, errDuringPlan := mj.plan()
+		printMsg(plan)
+		return errDuringPlan
+	}
+
 	ctxt, cancelMirror := context.WithCancel(context.Background())
 	defer cancelMirror()
 
 	// Start mirroring job
-	return mj.mirror(ctxt, cancelMirror)
+	errDuringMirror := mj.mirror(ctxt, cancelMirror)
+	mj.failures.print()
+	// The report is written however the mirror ended.
+	if err := mj.report.save(); err != nil {
+		errorIf(err.Trace(ctx.String("report")), "Unable to write the report `"+ctx.String("report")+"`.")
+		errDuringMirror = true
+	}
+	if !errDuringMirror {
+		if err := mj.manifest.save(); err != nil {
+			errorIf(err.Trace(ctx.String("manifest")), "Unable to write the manifest `"+ctx.String("manifest")+"`.")
+			errDuringMirror = true
+		}
+	}
+	return errDuringMirror
+}
+
+// openMirrorLog - opens the operation log requested with --log-file.
+func openMirrorLog(ctx *cli.Context) *oplog.Logger {
+	logFile := ctx.String("log-file")
+	if logFile == "" {
+		if ctx.String("log-max-size") != "" {
+			fatalIf(errInvalidArgument().Trace(ctx.String("log-max-size")), "--log-max-size requires --log-file.")
+		}
+		return nil
+	}
+	var maxSize int64
+	if ctx.String("log-max-size") != "" {
+		var err *probe.Error
+		maxSize, err = parseSize(ctx.String("log-max-size"))
+		fatalIf(err.Trace(ctx.String("log-max-size")), "Unable to parse --log-max-size.")
+	}
+	opLog, e := oplog.New(logFile, maxSize)
+	fatalIf(probe.NewError(e).Trace(logFile), "Unable to open the log file.")
+	return opLog
 }
 
 // Main entry point for mirror command.
 func mainMirror(ctx *cli.Context) error {
+	// A mirror always has a source and a target, the ID of a daemon
+	// to stop is told from a target by its form.
+	if args := ctx.Args(); len(args) == 1 && (args.First() == "stop" || args.First() == "status") {
+		return mainMirrorControl(args.First(), "")
+	} else if len(args) == 2 && args.First() == "stop" && isMirrorDaemonID(args.Get(1)) {
+		return mainMirrorControl(args.First(), args.Get(1))
+	}
+
 	// Parse encryption keys per command.
 	encKeyDB, err := getEncKeys(ctx)
 	fatalIf(err, "Unable to parse encryption keys.")
@@ -913,9 +1217,43 @@ func mainMirror(ctx *cli.Context) error {
 	// check 'mirror' cli arguments.
 	checkMirrorSyntax(ctx, encKeyDB)
 
+	setGlobalPartSize(ctx.String("part-size"))
+	setGlobalLinks(ctx.String("links"))
+	setGlobalBandwidthLimits(ctx.String("limit-upload"), ctx.String("limit-download"))
+	setGlobalCopyAttempts(ctx)
+	setGlobalIdleTimeout(ctx)
+	setGlobalMaxObjectSize(ctx.String("max-object-size"))
+	setGlobalCopyOrder(ctx.String("order"))
+	globalAtomic = ctx.Bool("atomic")
+	globalExcludeHidden = ctx.Bool("exclude-hidden")
+	globalNoSniff = ctx.Bool("no-sniff")
+	setGlobalListParallel(ctx)
+
 	// Additional command specific theme customization.
 	console.SetColor("Mirror", color.New(color.FgGreen, color.Bold))
 
+	if ctx.Bool("daemon") {
+		checkMirrorDaemonSyntax(ctx)
+		if !mirrorDaemonSupported {
+			errorIf(errInvalidArgument(), "--daemon is not supported on this platform, mirroring in the foreground.")
+		} else if !isMirrorDaemon() {
+			return mainMirrorDaemon(ctx)
+		} else {
+			// Nobody watches the progress of daemons.
+			globalQuiet = true
+			id := os.Getenv(mirrorDaemonEnv)
+			fatalIf(writeMirrorPIDFile(id), "Unable to write the PID file of the mirror daemon.")
+			defer func() {
+				errorIf(removeMirrorPIDFile(id), "Unable to remove the PID file of the mirror daemon.")
+			}()
+		}
+	}
+
+	opLog := openMirrorLog(ctx)
+	defer func() {
+		errorIf(probe.NewError(opLog.Close()), "Unable to flush the log file.")
+	}()
+
 	args := ctx.Args()
 
 	srcURL := args[0]
@@ -923,12 +1261,13 @@ func mainMirror(ctx *cli.Context) error {
 
 	if ctx.String("multi-master") != "" {
 		for {
-			runMirror(srcURL, tgtURL, ctx, encKeyDB)
+			runMirror(srcURL, tgtURL, ctx, encKeyDB, opLog)
+			errorIf(probe.NewError(opLog.Flush()), "Unable to flush the log file.")
 			time.Sleep(time.Second * 2)
 		}
 	}
 
-	if errorDetected := runMirror(srcURL, tgtURL, ctx, encKeyDB); errorDetected {
+	if errorDetected := runMirror(srcURL, tgtURL, ctx, encKeyDB, opLog); errorDetected {
 		return exitStatus(globalErrorExitStatus)
 	}
 
@@ -0,0 +1,500 @@
+/*
+ * MinIO Client (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/oplog"
+)
+
+// newMirrorContext - returns the context of a mirror command line.
+func newMirrorContext(t *testing.T, args ...string) *cli.Context {
+	set := flag.NewFlagSet(mirrorCmd.Name, flag.ContinueOnError)
+	for _, f := range mirrorCmd.Flags {
+		f.Apply(set)
+	}
+	if e := set.Parse(args); e != nil {
+		t.Fatal(e)
+	}
+	ctx := cli.NewContext(nil, set, nil)
+	ctx.Command = mirrorCmd
+	return ctx
+}
+
+// readMirrorLog - returns the entries of an operation log.
+func readMirrorLog(t *testing.T, logFile string) (entries []oplog.Entry) {
+	file, e := os.Open(logFile)
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer file.Close()
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var entry oplog.Entry
+		if e = json.Unmarshal(scanner.Bytes(), &entry); e != nil {
+			t.Fatalf("Invalid log line %q: %s", scanner.Text(), e)
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func TestMirrorLogFile(t *testing.T) {
+	dir, e := ioutil.TempDir("", "mc-mirror-")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.RemoveAll(dir)
+	srcDir := filepath.Join(dir, "src")
+	tgtDir := filepath.Join(dir, "tgt")
+	files := map[string]string{"a.txt": "hello", "b.txt": "hello world"}
+	if e = os.Mkdir(srcDir, 0700); e != nil {
+		t.Fatal(e)
+	}
+	for name, content := range files {
+		if e = ioutil.WriteFile(filepath.Join(srcDir, name), []byte(content), 0600); e != nil {
+			t.Fatal(e)
+		}
+	}
+
+	cfg := newConfigV9()
+	defer withTestConfig(cfg.Hosts)()
+	defer func(quiet bool) { globalQuiet = quiet }(globalQuiet)
+	globalQuiet = true
+
+	logFile := filepath.Join(dir, "mirror.log")
+	ctx := newMirrorContext(t, "--log-file", logFile, srcDir, tgtDir)
+	opLog := openMirrorLog(ctx)
+	if errorDetected := runMirror(srcDir, tgtDir, ctx, nil, opLog); errorDetected {
+		t.Fatal("Expected mirror to succeed")
+	}
+	if e = opLog.Close(); e != nil {
+		t.Fatal(e)
+	}
+
+	logged := make(map[string]oplog.Entry)
+	for _, entry := range readMirrorLog(t, logFile) {
+		logged[filepath.Base(entry.Source)] = entry
+	}
+	if len(logged) != len(files) {
+		t.Fatalf("Expected %d log lines, got %v", len(files), logged)
+	}
+	for name, content := range files {
+		entry := logged[name]
+		if entry.Action != "copy" || entry.Status != "success" || entry.Time.IsZero() {
+			t.Fatalf("%s: Unexpected log entry %+v", name, entry)
+		}
+		if entry.Source != filepath.ToSlash(filepath.Join(srcDir, name)) || entry.Target != filepath.ToSlash(filepath.Join(tgtDir, name)) {
+			t.Fatalf("%s: Unexpected source or target in %+v", name, entry)
+		}
+		if entry.Bytes != int64(len(content)) {
+			t.Fatalf("%s: Expected %d bytes, got %d", name, len(content), entry.Bytes)
+		}
+	}
+}
+
+// writeFiles - creates the files below dir.
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	if e := os.MkdirAll(dir, 0700); e != nil {
+		t.Fatal(e)
+	}
+	for name, content := range files {
+		if e := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0600); e != nil {
+			t.Fatal(e)
+		}
+	}
+}
+
+// Tests the plan of a fake mirror matches what mirroring does.
+func TestMirrorPlan(t *testing.T) {
+	dir, e := ioutil.TempDir("", "mc-mirror-")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.RemoveAll(dir)
+	srcDir := filepath.Join(dir, "src")
+	tgtDir := filepath.Join(dir, "tgt")
+	writeFiles(t, srcDir, map[string]string{"new.txt": "hello", "same.txt": "same", "old.txt": "old"})
+	writeFiles(t, tgtDir, map[string]string{"same.txt": "same", "extra.txt": "extra"})
+	monthAgo := time.Now().AddDate(0, -1, 0)
+	if e = os.Chtimes(filepath.Join(srcDir, "old.txt"), monthAgo, monthAgo); e != nil {
+		t.Fatal(e)
+	}
+
+	cfg := newConfigV9()
+	defer withTestConfig(cfg.Hosts)()
+	defer func(quiet bool) { globalQuiet = quiet }(globalQuiet)
+	globalQuiet = true
+
+	mj := newMirrorJob(srcDir, tgtDir, true, true, false, false, false, false, nil, "", "7d", "", "", nil, nil)
+	plan, errDuringPlan := mj.plan()
+	if errDuringPlan {
+		t.Fatal("Expected plan to succeed")
+	}
+	if len(plan.Copy) != 1 || filepath.Base(plan.Copy[0].Source) != "new.txt" || plan.TotalSize != 5 {
+		t.Fatalf("Unexpected copies %+v of %d bytes", plan.Copy, plan.TotalSize)
+	}
+	if len(plan.Remove) != 1 || filepath.Base(plan.Remove[0].Target) != "extra.txt" {
+		t.Fatalf("Unexpected removes %+v", plan.Remove)
+	}
+	skipped := make(map[string]string)
+	for _, entry := range plan.Skip {
+		skipped[filepath.Base(entry.Source)] = entry.Reason
+	}
+	if len(skipped) != 2 || skipped["same.txt"] != "unchanged" || skipped["old.txt"] != "not newer than 7d" {
+		t.Fatalf("Unexpected skips %+v", plan.Skip)
+	}
+
+	// Mirror for real, logging the operations performed.
+	logFile := filepath.Join(dir, "mirror.log")
+	ctx := newMirrorContext(t, "--remove", "--newer-than", "7d", "--log-file", logFile, srcDir, tgtDir)
+	opLog := openMirrorLog(ctx)
+	if errorDetected := runMirror(srcDir, tgtDir, ctx, nil, opLog); errorDetected {
+		t.Fatal("Expected mirror to succeed")
+	}
+	if e = opLog.Close(); e != nil {
+		t.Fatal(e)
+	}
+	performed := make(map[string][]mirrorPlanEntry)
+	for _, entry := range readMirrorLog(t, logFile) {
+		performed[entry.Action] = append(performed[entry.Action], mirrorPlanEntry{Source: entry.Source, Target: entry.Target, Size: entry.Bytes})
+	}
+	if !reflect.DeepEqual(performed["copy"], plan.Copy) || !reflect.DeepEqual(performed["remove"], plan.Remove) {
+		t.Fatalf("Expected copies %+v and removes %+v, performed %+v", plan.Copy, plan.Remove, performed)
+	}
+}
+
+// Tests targets which cannot be overwritten fail the plan like mirroring.
+func TestMirrorPlanOverwrite(t *testing.T) {
+	srcDir, e := ioutil.TempDir("", "mc-mirror-")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.RemoveAll(srcDir)
+	writeFiles(t, srcDir, map[string]string{"changed.txt": "changed"})
+
+	server := httptest.NewServer(newFakeS3Server(map[string][]byte{"bucket/changed.txt": []byte("old")}))
+	defer server.Close()
+	defer withTestConfig(map[string]hostConfigV9{"myminio": testHost(server.URL)})()
+	defer func(quiet bool) { globalQuiet = quiet }(globalQuiet)
+	globalQuiet = true
+
+	mj := newMirrorJob(srcDir, "myminio/bucket", true, false, false, false, false, false, nil, "", "", "", "", nil, nil)
+	plan, errDuringPlan := mj.plan()
+	if !errDuringPlan || len(plan.Fail) != 1 || filepath.Base(plan.Fail[0].Source) != "changed.txt" || len(plan.Skip) != 0 {
+		t.Fatalf("Expected the plan to fail on changed.txt, got %+v", plan)
+	}
+
+	ctx := newMirrorContext(t, srcDir, "myminio/bucket")
+	if errorDetected := runMirror(srcDir, "myminio/bucket", ctx, nil, nil); !errorDetected {
+		t.Fatal("Expected mirror to fail")
+	}
+}
+
+// Tests objects mirrored with --etag-compare are uploaded again only
+// when the content of their source changed.
+func TestMirrorETagCompare(t *testing.T) {
+	srcDir, e := ioutil.TempDir("", "mc-mirror-")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.RemoveAll(srcDir)
+	writeFiles(t, srcDir, map[string]string{"a.txt": "hello", "b.txt": "world"})
+
+	handler := newFakeS3Server(nil)
+	var uploads []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPut && r.URL.Path != "/bucket/" {
+			uploads = append(uploads, path.Base(r.URL.Path))
+		}
+		handler.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+	defer withTestConfig(map[string]hostConfigV9{"myminio": testHost(server.URL)})()
+	defer func(quiet bool) { globalQuiet = quiet }(globalQuiet)
+	globalQuiet = true
+
+	mirror := func() []string {
+		uploads = nil
+		ctx := newMirrorContext(t, "--etag-compare", "--overwrite", "-a", srcDir, "myminio/bucket")
+		if errorDetected := runMirror(srcDir, "myminio/bucket", ctx, nil, nil); errorDetected {
+			t.Fatal("Expected mirror to succeed")
+		}
+		sort.Strings(uploads)
+		return uploads
+	}
+
+	if uploaded := mirror(); !reflect.DeepEqual(uploaded, []string{"a.txt", "b.txt"}) {
+		t.Fatalf("Expected both files to be uploaded, got %v", uploaded)
+	}
+	// MD5 of "hello".
+	if etag := handler.headers["bucket/a.txt"].Get(mirrorSourceETagKey); etag != "5d41402abc4b2a76b9719d911017c592" {
+		t.Fatalf("Expected the MD5 of the source in the metadata, got %q", etag)
+	}
+
+	// The attributes mirrored by -a change with the modification time,
+	// the content does not.
+	monthAgo := time.Now().AddDate(0, -1, 0)
+	if e = os.Chtimes(filepath.Join(srcDir, "a.txt"), monthAgo, monthAgo); e != nil {
+		t.Fatal(e)
+	}
+	if uploaded := mirror(); len(uploaded) != 0 {
+		t.Fatalf("Expected nothing to be uploaded, got %v", uploaded)
+	}
+
+	// Content of the same size is uploaded again.
+	writeFiles(t, srcDir, map[string]string{"b.txt": "WORLD"})
+	if uploaded := mirror(); !reflect.DeepEqual(uploaded, []string{"b.txt"}) {
+		t.Fatalf("Expected b.txt to be uploaded, got %v", uploaded)
+	}
+}
+
+func TestMirrorTransferManifest(t *testing.T) {
+	srcDir, e := ioutil.TempDir("", "mc-mirror-")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.RemoveAll(srcDir)
+	writeFiles(t, srcDir, map[string]string{"a.txt": "hello", "b.txt": "world"})
+
+	handler := newFakeS3Server(map[string][]byte{"bucket/a.txt": []byte("hello")})
+	handler.md5ETags = true
+	server := httptest.NewServer(handler)
+	defer server.Close()
+	defer withTestConfig(map[string]hostConfigV9{"myminio": testHost(server.URL)})()
+	defer func(quiet bool) { globalQuiet = quiet }(globalQuiet)
+	globalQuiet = true
+
+	// Only b.txt is uploaded, a.txt is already in sync.
+	manifestPath := filepath.Join(srcDir, "..", filepath.Base(srcDir)+".json")
+	defer os.Remove(manifestPath)
+	ctx := newMirrorContext(t, "--manifest", manifestPath, srcDir, "myminio/bucket")
+	if errorDetected := runMirror(srcDir, "myminio/bucket", ctx, nil, nil); errorDetected {
+		t.Fatal("Expected mirror to succeed")
+	}
+	data, e := ioutil.ReadFile(manifestPath)
+	if e != nil {
+		t.Fatal(e)
+	}
+	var manifest transferManifestV1
+	if e = json.Unmarshal(data, &manifest); e != nil {
+		t.Fatal(e)
+	}
+	// MD5 of "world".
+	expected := []transferManifestEntry{{Key: "b.txt", Size: 5, ETag: "7d793037a0760186574b0282f2f435e7"}}
+	if !reflect.DeepEqual(manifest.Objects, expected) {
+		t.Fatalf("Expected the manifest %v, got %v", expected, manifest.Objects)
+	}
+}
+
+func TestMirrorExcludeHidden(t *testing.T) {
+	dir, e := ioutil.TempDir("", "mc-mirror-")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.RemoveAll(dir)
+	srcDir := filepath.Join(dir, "src")
+	writeFiles(t, srcDir, map[string]string{"a.txt": "a", ".env": "secret", "c.tmp": "temp"})
+	writeFiles(t, filepath.Join(srcDir, ".git"), map[string]string{"config": "config"})
+	writeFiles(t, filepath.Join(srcDir, "sub"), map[string]string{"b.txt": "b", ".DS_Store": "store"})
+
+	cfg := newConfigV9()
+	defer withTestConfig(cfg.Hosts)()
+	defer func(quiet bool) { globalQuiet = quiet }(globalQuiet)
+	globalQuiet = true
+	defer func(excludeHidden bool) { globalExcludeHidden = excludeHidden }(globalExcludeHidden)
+
+	mirrored := func(tgtDir string) []string {
+		var files []string
+		filepath.Walk(tgtDir, func(fp string, fi os.FileInfo, e error) error {
+			if e == nil && fi.Mode().IsRegular() {
+				rel, _ := filepath.Rel(tgtDir, fp)
+				files = append(files, filepath.ToSlash(rel))
+			}
+			return nil
+		})
+		sort.Strings(files)
+		return files
+	}
+
+	// Hidden files are mirrored by default.
+	tgtDir := filepath.Join(dir, "all")
+	globalExcludeHidden = false
+	if errorDetected := runMirror(srcDir, tgtDir, newMirrorContext(t, srcDir, tgtDir), nil, nil); errorDetected {
+		t.Fatal("Expected mirror to succeed")
+	}
+	expected := []string{".env", ".git/config", "a.txt", "c.tmp", "sub/.DS_Store", "sub/b.txt"}
+	if files := mirrored(tgtDir); !reflect.DeepEqual(files, expected) {
+		t.Fatalf("Expected %v, got %v", expected, files)
+	}
+
+	// Hidden files and folders are skipped along with the excluded files.
+	tgtDir = filepath.Join(dir, "visible")
+	globalExcludeHidden = true
+	ctx := newMirrorContext(t, "--exclude-hidden", "--exclude", "*.tmp", srcDir, tgtDir)
+	if errorDetected := runMirror(srcDir, tgtDir, ctx, nil, nil); errorDetected {
+		t.Fatal("Expected mirror to succeed")
+	}
+	expected = []string{"a.txt", "sub/b.txt"}
+	if files := mirrored(tgtDir); !reflect.DeepEqual(files, expected) {
+		t.Fatalf("Expected %v, got %v", expected, files)
+	}
+}
+
+func TestMirrorPreserveMetadata(t *testing.T) {
+	newHost := func(handler http.Handler) (*httptest.Server, hostConfigV9) {
+		server := httptest.NewServer(handler)
+		host := testHost(server.URL)
+		// Signature V2 uploads the data unchunked.
+		host.API = "S3v2"
+		return server, host
+	}
+	source := newFakeS3Server(map[string][]byte{"bucket/reports/q1.csv": []byte("quarter,revenue\n1,100\n")})
+	source.headers["bucket/reports/q1.csv"] = http.Header{
+		"Content-Type":        {"text/csv"},
+		"Cache-Control":       {"max-age=60"},
+		"X-Amz-Meta-Owner":    {"alice"},
+		"X-Amz-Storage-Class": {"REDUCED_REDUNDANCY"},
+	}
+	target := newFakeS3Server(nil)
+	srcServer, srcHost := newHost(source)
+	defer srcServer.Close()
+	tgtServer, tgtHost := newHost(target)
+	defer tgtServer.Close()
+	defer withTestConfig(map[string]hostConfigV9{"src": srcHost, "dst": tgtHost})()
+	defer func(quiet bool) { globalQuiet = quiet }(globalQuiet)
+	globalQuiet = true
+
+	expected := http.Header{
+		"Content-Type":        {"text/csv"},
+		"Cache-Control":       {"max-age=60"},
+		"X-Amz-Meta-Owner":    {"alice"},
+		"X-Amz-Storage-Class": {"REDUCED_REDUNDANCY"},
+	}
+	testCases := []struct {
+		args     []string
+		handler  *fakeS3Server
+		object   string
+		expected http.Header
+	}{
+		// Uploaded to another host with the headers of the source.
+		{[]string{"src/bucket", "dst/bucket"}, target, "bucket/reports/q1.csv", expected},
+		// Copied on the server of the same host.
+		{[]string{"src/bucket", "src/copy"}, source, "copy/reports/q1.csv", expected},
+		// The storage class given takes precedence.
+		{[]string{"--storage-class", "STANDARD_IA", "src/bucket", "src/infrequent"}, source, "infrequent/reports/q1.csv", http.Header{
+			"Content-Type":        {"text/csv"},
+			"Cache-Control":       {"max-age=60"},
+			"X-Amz-Meta-Owner":    {"alice"},
+			"X-Amz-Storage-Class": {"STANDARD_IA"},
+		}},
+		{[]string{"--no-preserve-metadata", "src/bucket", "dst/plain"}, target, "plain/reports/q1.csv", http.Header{"Content-Type": {"application/octet-stream"}}},
+		{[]string{"--no-preserve-metadata", "src/bucket", "src/plain"}, source, "plain/reports/q1.csv", http.Header{"Content-Type": {"application/octet-stream"}}},
+	}
+	for i, testCase := range testCases {
+		ctx := newMirrorContext(t, testCase.args...)
+		args := ctx.Args()
+		if errorDetected := runMirror(args[len(args)-2], args[len(args)-1], ctx, nil, nil); errorDetected {
+			t.Fatalf("Test %d: expected mirror to succeed", i+1)
+		}
+		header := testCase.handler.header(testCase.object)
+		if !reflect.DeepEqual(header, testCase.expected) {
+			t.Errorf("Test %d: expected %v, got %v", i+1, testCase.expected, header)
+		}
+	}
+}
+
+// deniedPutHandler - denies the upload of the objects in denied.
+type deniedPutHandler struct {
+	*fakeS3Server
+	denied map[string]bool
+}
+
+func (h deniedPutHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodPut && h.denied[strings.TrimPrefix(r.URL.Path, "/")] {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`<Error><Code>AccessDenied</Code><Message>Access Denied.</Message></Error>`))
+		return
+	}
+	h.fakeS3Server.ServeHTTP(w, r)
+}
+
+// Tests the report of a mirror failing for some objects holds the totals.
+func TestMirrorReport(t *testing.T) {
+	handler := deniedPutHandler{
+		fakeS3Server: newFakeS3Server(nil),
+		denied:       map[string]bool{"bucket/denied.txt": true},
+	}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+	host := testHost(server.URL)
+	host.API = "S3v2"
+	defer withTestConfig(map[string]hostConfigV9{"myminio": host})()
+	defer func(quiet bool) { globalQuiet = quiet }(globalQuiet)
+	globalQuiet = true
+
+	dir, e := ioutil.TempDir("", "mc-mirror-report-")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.RemoveAll(dir)
+	srcDir := filepath.Join(dir, "src")
+	writeFiles(t, srcDir, map[string]string{"allowed.txt": "uploaded", "denied.txt": "never uploaded"})
+
+	reportPath := filepath.Join(dir, "report.json")
+	ctx := newMirrorContext(t, "--report", reportPath, srcDir, "myminio/bucket")
+	if errorDetected := runMirror(srcDir, "myminio/bucket", ctx, nil, nil); !errorDetected {
+		t.Fatal("Expected the upload of denied.txt to fail")
+	}
+
+	data, e := ioutil.ReadFile(reportPath)
+	if e != nil {
+		t.Fatalf("Expected the report to be written: %v", e)
+	}
+	var report transferReportV1
+	if e = json.Unmarshal(data, &report); e != nil {
+		t.Fatal(e)
+	}
+	expected := transferReportObjects{Processed: 2, Succeeded: 1, Failed: 1}
+	if report.Objects != expected {
+		t.Errorf("Expected objects %+v, got %+v", expected, report.Objects)
+	}
+	if report.Bytes != int64(len("uploaded")) {
+		t.Errorf("Expected %d bytes, got %d", len("uploaded"), report.Bytes)
+	}
+	if report.Version != transferReportVersion || report.Command != "mirror" || report.Status != "partial" {
+		t.Errorf("Unexpected report %+v", report)
+	}
+	if report.StartTime.IsZero() || report.DurationSeconds < 0 {
+		t.Errorf("Unexpected duration in report %+v", report)
+	}
+}
@@ -0,0 +1,129 @@
+/*
+ * MinIO Client (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	json "github.com/minio/mc/pkg/colorjson"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/minio/pkg/console"
+)
+
+// mirrorPlanEntry is an object in the plan of a fake mirror.
+type mirrorPlanEntry struct {
+	Source string `json:"source,omitempty"`
+	Target string `json:"target,omitempty"`
+	Size   int64  `json:"size"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// mirrorPlanMessage container for the plan of a fake mirror.
+type mirrorPlanMessage struct {
+	Status    string            `json:"status"`
+	Copy      []mirrorPlanEntry `json:"copy"`
+	Remove    []mirrorPlanEntry `json:"remove"`
+	Skip      []mirrorPlanEntry `json:"skip"`
+	Fail      []mirrorPlanEntry `json:"fail"`
+	TotalSize int64             `json:"totalSize"`
+}
+
+// String colorized mirror plan message.
+func (m mirrorPlanMessage) String() string {
+	var b strings.Builder
+	b.WriteString(console.Colorize("Mirror", fmt.Sprintf("Copy %d object(s), %s:", len(m.Copy), humanizeSize(m.TotalSize))))
+	for _, e := range m.Copy {
+		fmt.Fprintf(&b, "\n   `%s` -> `%s` (%s)", e.Source, e.Target, humanizeSize(e.Size))
+	}
+	b.WriteString("\n" + console.Colorize("Mirror", fmt.Sprintf("Remove %d object(s):", len(m.Remove))))
+	for _, e := range m.Remove {
+		fmt.Fprintf(&b, "\n   `%s`", e.Target)
+	}
+	b.WriteString("\n" + console.Colorize("Mirror", fmt.Sprintf("Skip %d object(s):", len(m.Skip))))
+	for _, e := range m.Skip {
+		fmt.Fprintf(&b, "\n   `%s`: %s", e.Source, e.Reason)
+	}
+	if len(m.Fail) > 0 {
+		b.WriteString("\n" + console.Colorize("Mirror", fmt.Sprintf("Fail %d object(s):", len(m.Fail))))
+	}
+	for _, e := range m.Fail {
+		switch {
+		case e.Source != "":
+			fmt.Fprintf(&b, "\n   `%s`: %s", e.Source, e.Reason)
+		case e.Target != "":
+			fmt.Fprintf(&b, "\n   `%s`: %s", e.Target, e.Reason)
+		default:
+			b.WriteString("\n   " + e.Reason)
+		}
+	}
+	return b.String()
+}
+
+// JSON jsonified mirror plan message.
+func (m mirrorPlanMessage) JSON() string {
+	m.Status = "success"
+	planBytes, e := json.MarshalIndent(m, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(planBytes)
+}
+
+// mirrorPath - returns the user facing path of content.
+func mirrorPath(alias string, content *clientContent) string {
+	return filepath.ToSlash(filepath.Join(alias, content.URL.Path))
+}
+
+// plan - returns what mirroring would copy, remove, skip and fail on,
+// using the same differences, filters and errors as the actual mirroring.
+func (mj *mirrorJob) plan() (plan mirrorPlanMessage, errDuringPlan bool) {
+	plan = mirrorPlanMessage{Copy: []mirrorPlanEntry{}, Remove: []mirrorPlanEntry{}, Skip: []mirrorPlanEntry{}, Fail: []mirrorPlanEntry{}}
+	for sURLs := range mj.prepareURLs() {
+		var entry mirrorPlanEntry
+		if sURLs.SourceContent != nil {
+			entry.Source = mirrorPath(sURLs.SourceAlias, sURLs.SourceContent)
+			entry.Size = sURLs.SourceContent.Size
+		}
+		if sURLs.TargetContent != nil {
+			entry.Target = mirrorPath(sURLs.TargetAlias, sURLs.TargetContent)
+		}
+
+		action, reason := mj.action(sURLs)
+		switch action {
+		case mirrorActionCopy:
+			plan.Copy = append(plan.Copy, entry)
+			plan.TotalSize += entry.Size
+		case mirrorActionRemove:
+			entry.Size = sURLs.TargetContent.Size
+			plan.Remove = append(plan.Remove, entry)
+		case mirrorActionSkip, mirrorActionSkipTooLarge:
+			entry.Reason = reason
+			plan.Skip = append(plan.Skip, entry)
+		case mirrorActionError:
+			entry.Reason = sURLs.Error.ToGoError().Error()
+			if !isMirrorFailure(sURLs) {
+				plan.Skip = append(plan.Skip, entry)
+				continue
+			}
+			// Like those needing --overwrite, which mirroring reports.
+			plan.Fail = append(plan.Fail, entry)
+			errDuringPlan = true
+		}
+	}
+	return plan, errDuringPlan
+}
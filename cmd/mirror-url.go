@@ -68,6 +68,16 @@ func checkMirrorSyntax(ctx *cli.Context, encKeyDB map[string][]prefixSSEPair) {
 		}
 	}
 
+	if ctx.Bool("etag-compare") && destClient.Type == fileSystem {
+		fatalIf(errInvalidArgument().Trace(tgtURL), "--etag-compare requires an object storage target.")
+	}
+
+	// The manifest is written when the mirror completes, watching
+	// mirrors do not.
+	if ctx.String("manifest") != "" && ctx.Bool("watch") {
+		fatalIf(errInvalidArgument().Trace(), "--manifest cannot be used with --watch.")
+	}
+
 	/****** Generic rules *******/
 	if !ctx.Bool("watch") {
 		_, srcContent, err := url2Stat(srcURL, false, false, encKeyDB)
@@ -95,7 +105,7 @@ func matchExcludeOptions(excludeOptions []string, srcSuffix string) bool {
 	return false
 }
 
-func deltaSourceTarget(sourceURL, targetURL string, isFake, isOverwrite, isRemove, isMetadata bool, excludeOptions []string, URLsCh chan<- URLs, encKeyDB map[string][]prefixSSEPair) {
+func deltaSourceTarget(sourceURL, targetURL string, isOverwrite, isRemove, isMetadata, isETagCompare bool, excludeOptions []string, URLsCh chan<- URLs, encKeyDB map[string][]prefixSSEPair) {
 	// source and targets are always directories
 	sourceSeparator := string(newClientURL(sourceURL).Separator)
 	if !strings.HasSuffix(sourceURL, sourceSeparator) {
@@ -125,7 +135,7 @@ func deltaSourceTarget(sourceURL, targetURL string, isFake, isOverwrite, isRemov
 	}
 
 	// List both source and target, compare and return values through channel.
-	for diffMsg := range objectDifference(sourceClnt, targetClnt, sourceURL, targetURL, isMetadata) {
+	for diffMsg := range objectDifference(sourceClnt, targetClnt, sourceURL, targetURL, isMetadata, true) {
 		if diffMsg.Error != nil {
 			// Send all errors through the channel
 			URLsCh <- URLs{Error: diffMsg.Error}
@@ -144,21 +154,51 @@ func deltaSourceTarget(sourceURL, targetURL string, isFake, isOverwrite, isRemov
 			continue
 		}
 
+		// Objects uploaded with --etag-compare are compared by the ETag
+		// of their source only, times and attributes may drift.
+		if isETagCompare && diffMsg.firstContent != nil && diffMsg.secondContent != nil && diffMsg.Diff != differInType {
+			diff, ok, err := etagCompareDiff(sourceAlias, diffMsg.firstContent, targetAlias, diffMsg.secondContent, encKeyDB)
+			if err != nil {
+				URLsCh <- URLs{Error: err.Trace(diffMsg.FirstURL, diffMsg.SecondURL)}
+				continue
+			}
+			if ok {
+				diffMsg.Diff = diff
+			}
+		}
+
 		switch diffMsg.Diff {
 		case differInNone:
-			// No difference, continue.
+			// No difference, sent for the plan of a fake mirror.
+			URLsCh <- URLs{
+				SourceAlias:   sourceAlias,
+				SourceContent: diffMsg.firstContent,
+				TargetAlias:   targetAlias,
+				TargetContent: diffMsg.secondContent,
+				unchanged:     true,
+			}
 		case differInType:
 			URLsCh <- URLs{Error: errInvalidTarget(diffMsg.SecondURL)}
 		case differInSize, differInMetadata, differInETag:
-			if !isOverwrite && !isFake {
+			if !isOverwrite {
 				// Size or time or etag differs but --overwrite not set.
-				URLsCh <- URLs{Error: errOverWriteNotAllowed(diffMsg.SecondURL)}
+				URLsCh <- URLs{
+					SourceAlias:   sourceAlias,
+					SourceContent: diffMsg.firstContent,
+					TargetAlias:   targetAlias,
+					TargetContent: diffMsg.secondContent,
+					Error:         errOverWriteNotAllowed(diffMsg.SecondURL),
+				}
 				continue
 			}
 
 			sourceSuffix := strings.TrimPrefix(diffMsg.FirstURL, sourceURL)
 			// Either available only in source or size differs and force is set
 			targetPath := urlJoinPath(targetURL, sourceSuffix)
+			if err := checkTargetPath(targetURL, targetPath); err != nil {
+				URLsCh <- URLs{Error: err.Trace(diffMsg.FirstURL)}
+				continue
+			}
 			sourceContent := diffMsg.firstContent
 			targetContent := &clientContent{URL: *newClientURL(targetPath)}
 			URLsCh <- URLs{
@@ -171,6 +211,10 @@ func deltaSourceTarget(sourceURL, targetURL string, isFake, isOverwrite, isRemov
 			// Only in first, always copy.
 			sourceSuffix := strings.TrimPrefix(diffMsg.FirstURL, sourceURL)
 			targetPath := urlJoinPath(targetURL, sourceSuffix)
+			if err := checkTargetPath(targetURL, targetPath); err != nil {
+				URLsCh <- URLs{Error: err.Trace(diffMsg.FirstURL)}
+				continue
+			}
 			sourceContent := diffMsg.firstContent
 			targetContent := &clientContent{URL: *newClientURL(targetPath)}
 			URLsCh <- URLs{
@@ -180,7 +224,7 @@ func deltaSourceTarget(sourceURL, targetURL string, isFake, isOverwrite, isRemov
 				TargetContent: targetContent,
 			}
 		case differInSecond:
-			if !isRemove && !isFake {
+			if !isRemove {
 				continue
 			}
 			URLsCh <- URLs{
@@ -196,8 +240,8 @@ func deltaSourceTarget(sourceURL, targetURL string, isFake, isOverwrite, isRemov
 }
 
 // Prepares urls that need to be copied or removed based on requested options.
-func prepareMirrorURLs(sourceURL string, targetURL string, isFake, isOverwrite, isRemove, isMetadata bool, excludeOptions []string, encKeyDB map[string][]prefixSSEPair) <-chan URLs {
+func prepareMirrorURLs(sourceURL string, targetURL string, isOverwrite, isRemove, isMetadata, isETagCompare bool, excludeOptions []string, encKeyDB map[string][]prefixSSEPair) <-chan URLs {
 	URLsCh := make(chan URLs)
-	go deltaSourceTarget(sourceURL, targetURL, isFake, isOverwrite, isRemove, isMetadata, excludeOptions, URLsCh, encKeyDB)
+	go deltaSourceTarget(sourceURL, targetURL, isOverwrite, isRemove, isMetadata, isETagCompare, excludeOptions, URLsCh, encKeyDB)
 	return URLsCh
 }
@@ -0,0 +1,123 @@
+/*
+ * MinIO Client (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"io/ioutil"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/minio/pkg/console"
+)
+
+// templateFlag - prints each result of ls, stat and find with a Go
+// template of the fields of templateItem.
+var templateFlag = cli.StringFlag{
+	Name:  "template",
+	Usage: "print each object with a Go template of Key, Size, LastModified, ETag and StorageClass, e.g. '{{.Key}} {{.Size}}'",
+}
+
+// templateItem - the fields of an object available to --template.
+type templateItem struct {
+	// Key of the object, relative to the listed folder for ls and stat.
+	Key          string
+	Size         int64
+	LastModified time.Time
+	// ETag without quotes, blank on filesystems.
+	ETag string
+	// Storage class, blank if not reported by the server.
+	StorageClass string
+}
+
+// newTemplateItem - returns the fields of a listed or fetched object.
+func newTemplateItem(c *clientContent) templateItem {
+	storageClass := c.StorageClass
+	if storageClass == "" {
+		storageClass = c.Metadata["X-Amz-Storage-Class"]
+	}
+	return templateItem{
+		Key:          getKey(c),
+		Size:         c.Size,
+		LastModified: c.Time.Local(),
+		ETag:         strings.Trim(c.ETag, "\""),
+		StorageClass: storageClass,
+	}
+}
+
+// newOutputTemplate - parses a --template. Templates are executed once
+// on an empty item, for unknown fields to fail before anything is
+// listed rather than on the first result.
+func newOutputTemplate(text string) (*template.Template, *probe.Error) {
+	tmpl, e := template.New("template").Parse(text)
+	if e != nil {
+		return nil, probe.NewError(e).Trace(text)
+	}
+	if e = tmpl.Execute(ioutil.Discard, templateItem{}); e != nil {
+		return nil, probe.NewError(e).Trace(text)
+	}
+	return tmpl, nil
+}
+
+// parseTemplateFlag - returns the template of --template, nil without
+// the flag.
+func parseTemplateFlag(ctx *cli.Context) *template.Template {
+	text := ctx.String("template")
+	if text == "" {
+		return nil
+	}
+	if globalJSON {
+		fatalIf(errInvalidArgument().Trace(text), "--template cannot be used with --json.")
+	}
+	tmpl, err := newOutputTemplate(text)
+	fatalIf(err, "Unable to parse --template.")
+	return tmpl
+}
+
+// renderTemplate - returns item formatted by tmpl.
+func renderTemplate(tmpl *template.Template, item templateItem) (string, *probe.Error) {
+	var buf bytes.Buffer
+	if e := tmpl.Execute(&buf, item); e != nil {
+		return "", probe.NewError(e).Trace(item.Key)
+	}
+	return buf.String(), nil
+}
+
+// printTemplate - prints item formatted by tmpl on a line.
+func printTemplate(tmpl *template.Template, item templateItem) {
+	line, err := renderTemplate(tmpl, item)
+	fatalIf(err, "Unable to execute --template.")
+	console.Println(line)
+}
+
+// templateLister prints the listings of ls with a template.
+type templateLister struct {
+	tmpl *template.Template
+}
+
+// list - prints every entry inside a folder as it arrives from the
+// listing, folders included.
+func (l templateLister) list(clnt Client, isRecursive, isIncomplete bool) error {
+	trimPrefix := trimListPrefix(clnt)
+	return listContents(clnt, isRecursive, isIncomplete, false, func(content *clientContent) {
+		trimPrefix(content)
+		printTemplate(l.tmpl, newTemplateItem(content))
+	})
+}
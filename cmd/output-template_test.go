@@ -0,0 +1,62 @@
+/*
+ * MinIO Client (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/fatih/color"
+)
+
+func TestOutputTemplate(t *testing.T) {
+	// Unknown fields and syntax errors fail before anything is listed.
+	for _, text := range []string{"{{.Name}}", "{{.Key", "{{.Size.Foo}}"} {
+		if _, err := newOutputTemplate(text); err == nil {
+			t.Fatalf("Expected template %q to be invalid", text)
+		}
+	}
+
+	handler := newFakeS3Server(map[string][]byte{
+		"bucket/photos/a.jpg": []byte("jpeg"),
+		"bucket/notes.txt":    []byte("some notes"),
+	})
+	server := httptest.NewServer(handler)
+	defer server.Close()
+	defer withTestConfig(map[string]hostConfigV9{"myminio": testHost(server.URL)})()
+	savedOutput, savedNoColor := color.Output, color.NoColor
+	defer func() { color.Output, color.NoColor = savedOutput, savedNoColor }()
+	var buf bytes.Buffer
+	color.Output, color.NoColor = &buf, true
+
+	tmpl, err := newOutputTemplate(`{{.Key}},{{.Size}},{{.ETag}},{{.LastModified.UTC.Format "2006-01-02"}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	clnt, err := newClient("myminio/bucket/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e := (templateLister{tmpl: tmpl}).list(clnt, true, false); e != nil {
+		t.Fatal(e)
+	}
+	expected := "notes.txt,10,etag,2019-05-21\nphotos/a.jpg,4,etag,2019-05-21\n"
+	if buf.String() != expected {
+		t.Fatalf("Expected the listing\n%s\ngot\n%s", expected, buf.String())
+	}
+}
@@ -54,6 +54,9 @@ type ParallelManager struct {
 	resultCh chan URLs
 
 	stopMonitorCh chan struct{}
+
+	// Limits the tasks running at once while servers ask to slow down.
+	throttle *requestThrottle
 }
 
 // addWorker creates a new worker to process tasks
@@ -80,7 +83,10 @@ func (p *ParallelManager) addWorker() {
 			}
 			// Execute the task and send the result
 			// to result channel.
-			p.resultCh <- fn()
+			p.throttle.acquire()
+			result := fn()
+			p.throttle.release()
+			p.resultCh <- result
 		}
 	}()
 }
@@ -108,6 +114,10 @@ func (p *ParallelManager) monitorProgress() {
 				// Ordered to quit immediately
 				return
 			case <-ticker.C:
+				// More workers would only be throttled.
+				if p.throttle.throttled() {
+					continue
+				}
 				// Compute new bandwidth from counted sent bytes
 				sentBytes := atomic.LoadInt64(&p.sentBytes)
 				bandwidth := sentBytes - prevSentBytes
@@ -148,6 +158,7 @@ func newParallelManager(resultCh chan URLs) (*ParallelManager, chan func() URLs)
 		stopMonitorCh: make(chan struct{}),
 		queueCh:       make(chan func() URLs),
 		resultCh:      resultCh,
+		throttle:      globalThrottle,
 	}
 
 	// Start with runtime.NumCPU().
@@ -0,0 +1,76 @@
+/*
+ * MinIO Client (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	humanize "github.com/dustin/go-humanize"
+	"github.com/minio/mc/pkg/probe"
+)
+
+// S3 multipart upload limits.
+const (
+	minPartSize   = 5 * humanize.MiByte
+	maxPartSize   = 5 * humanize.GiByte
+	maxPartsCount = 10000
+)
+
+// parsePartSize parses a human readable part size such as `64MiB` and
+// validates it against the S3 multipart limits.
+func parsePartSize(partSize string) (uint64, *probe.Error) {
+	size, err := parseSize(partSize)
+	if err != nil {
+		return 0, err
+	}
+	if size < minPartSize {
+		return 0, errInvalidPartSize(partSize, "must be at least "+humanizeSize(minPartSize))
+	}
+	if size > maxPartSize {
+		return 0, errInvalidPartSize(partSize, "must be at most "+humanizeSize(maxPartSize))
+	}
+	return uint64(size), nil
+}
+
+// effectivePartSize returns the part size to use for an upload of the
+// given size. A configured part size which would need more than
+// maxPartsCount parts is raised to the smallest MiB aligned value that
+// fits. Zero is returned when no part size is configured, leaving the
+// choice to minio-go.
+func effectivePartSize(size int64, partSize uint64) uint64 {
+	if partSize == 0 || size <= 0 {
+		return partSize
+	}
+	if uint64(size) <= partSize*maxPartsCount {
+		return partSize
+	}
+	partSize = (uint64(size) + maxPartsCount - 1) / maxPartsCount
+	partSize = (partSize + humanize.MiByte - 1) / humanize.MiByte * humanize.MiByte
+	if partSize > maxPartSize {
+		partSize = maxPartSize
+	}
+	return partSize
+}
+
+// setGlobalPartSize validates the part size requested on the command
+// line and makes it the default for all new clients.
+func setGlobalPartSize(partSize string) {
+	if partSize == "" {
+		return
+	}
+	size, err := parsePartSize(partSize)
+	fatalIf(err, "Unable to set multipart part size.")
+	globalPartSize = size
+}
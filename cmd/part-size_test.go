@@ -0,0 +1,76 @@
+/*
+ * MinIO Client (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"testing"
+
+	humanize "github.com/dustin/go-humanize"
+)
+
+func TestParsePartSize(t *testing.T) {
+	testCases := []struct {
+		partSize string
+		expected uint64
+		success  bool
+	}{
+		{"64MiB", 64 * humanize.MiByte, true},
+		{"5MiB", 5 * humanize.MiByte, true},
+		{"5GiB", 5 * humanize.GiByte, true},
+		{"4MiB", 0, false},
+		{"6GiB", 0, false},
+		{"invalid", 0, false},
+	}
+	for i, testCase := range testCases {
+		partSize, err := parsePartSize(testCase.partSize)
+		if testCase.success != (err == nil) {
+			t.Fatalf("Test %d: expected success %t, got %v", i+1, testCase.success, err)
+		}
+		if partSize != testCase.expected {
+			t.Errorf("Test %d: expected %d, got %d", i+1, testCase.expected, partSize)
+		}
+	}
+}
+
+func TestEffectivePartSize(t *testing.T) {
+	testCases := []struct {
+		size     int64
+		partSize uint64
+		expected uint64
+	}{
+		// No part size configured, leave it to minio-go.
+		{humanize.TiByte, 0, 0},
+		// Unknown size, use the configured part size.
+		{-1, 16 * humanize.MiByte, 16 * humanize.MiByte},
+		// Configured part size is sufficient.
+		{humanize.GiByte, 16 * humanize.MiByte, 16 * humanize.MiByte},
+		{10000 * 5 * humanize.MiByte, 5 * humanize.MiByte, 5 * humanize.MiByte},
+		// Configured part size needs more than 10000 parts.
+		{10000*5*humanize.MiByte + 1, 5 * humanize.MiByte, 6 * humanize.MiByte},
+		{humanize.TiByte, 5 * humanize.MiByte, 105 * humanize.MiByte},
+		{5 * humanize.TiByte, 64 * humanize.MiByte, 525 * humanize.MiByte},
+	}
+	for i, testCase := range testCases {
+		partSize := effectivePartSize(testCase.size, testCase.partSize)
+		if partSize != testCase.expected {
+			t.Errorf("Test %d: expected %d, got %d", i+1, testCase.expected, partSize)
+		}
+		if testCase.size > 0 && partSize > 0 && uint64(testCase.size) > partSize*maxPartsCount {
+			t.Errorf("Test %d: part size %d needs more than %d parts", i+1, partSize, maxPartsCount)
+		}
+	}
+}
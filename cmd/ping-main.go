@@ -0,0 +1,170 @@
+/*
+ * MinIO Client (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	json "github.com/minio/mc/pkg/colorjson"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/minio-go/v6"
+	"github.com/minio/minio/pkg/console"
+)
+
+// Exit statuses of ping telling failures apart, any other failure
+// exits with globalErrorExitStatus.
+const (
+	pingExitDNS     = 2
+	pingExitRefused = 3
+	pingExitTLS     = 4
+	pingExitAuth    = 5
+)
+
+var pingCmd = cli.Command{
+	Name:   "ping",
+	Usage:  "check connectivity and credentials of an alias",
+	Action: mainPing,
+	Before: setGlobalsFromContext,
+	Flags:  globalFlags,
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [FLAGS] ALIAS
+{{if .VisibleFlags}}
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}{{end}}
+EXIT STATUS:
+  0 on success, 2 if the host cannot be resolved, 3 if the connection is refused,
+  4 on TLS errors, 5 if the credentials are rejected and 1 on any other error.
+
+EXAMPLES:
+  1. Verify a newly added alias.
+     {{.Prompt}} {{.HelpName}} myminio
+`,
+}
+
+// pingMessage container for ping results.
+type pingMessage struct {
+	Status   string        `json:"status"`
+	Alias    string        `json:"alias"`
+	Endpoint string        `json:"endpoint"`
+	Latency  time.Duration `json:"latency"`
+}
+
+// String colorized ping message.
+func (p pingMessage) String() string {
+	return console.Colorize("Ping", fmt.Sprintf("`%s` is reachable at %s using the configured credentials, round-trip %s.",
+		p.Alias, p.Endpoint, p.Latency.Round(time.Microsecond)))
+}
+
+// JSON jsonified ping message.
+func (p pingMessage) JSON() string {
+	pingJSONBytes, e := json.MarshalIndent(p, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(pingJSONBytes)
+}
+
+// classifyPingError - returns a description and the exit status of a failed ping.
+func classifyPingError(e error) (string, int) {
+	var dnsErr *net.DNSError
+	if errors.As(e, &dnsErr) {
+		return "Unable to resolve the host", pingExitDNS
+	}
+	if errors.Is(e, syscall.ECONNREFUSED) {
+		return "Connection refused", pingExitRefused
+	}
+	var certErr x509.UnknownAuthorityError
+	var hostErr x509.HostnameError
+	var invalidErr x509.CertificateInvalidError
+	var recordErr tls.RecordHeaderError
+	if errors.As(e, &certErr) || errors.As(e, &hostErr) || errors.As(e, &invalidErr) || errors.As(e, &recordErr) {
+		return "TLS handshake failed", pingExitTLS
+	}
+	var errResponse minio.ErrorResponse
+	if errors.As(e, &errResponse) {
+		switch errResponse.Code {
+		case "InvalidAccessKeyId", "SignatureDoesNotMatch", "AccessDenied", "InvalidToken", "ExpiredToken":
+			return "Credentials rejected", pingExitAuth
+		}
+		if errResponse.StatusCode == http.StatusForbidden || errResponse.StatusCode == http.StatusUnauthorized {
+			return "Credentials rejected", pingExitAuth
+		}
+	}
+	return "Unable to reach the server", globalErrorExitStatus
+}
+
+// pingAlias - pings the server of the alias.
+func pingAlias(alias string) (pingMessage, *probe.Error) {
+	clnt, err := newClient(alias)
+	if err != nil {
+		return pingMessage{}, err.Trace(alias)
+	}
+	return pingClient(alias, clnt)
+}
+
+// pingClient - pings the server of clnt, the host of alias.
+func pingClient(alias string, clnt Client) (pingMessage, *probe.Error) {
+	s3Clnt, ok := clnt.(*s3Client)
+	if !ok {
+		return pingMessage{}, errInvalidAliasedURL(alias).Trace(alias)
+	}
+	latency, err := s3Clnt.Ping()
+	if err != nil {
+		return pingMessage{}, err.Trace(alias)
+	}
+	return pingMessage{
+		Status:   "success",
+		Alias:    alias,
+		Endpoint: s3Clnt.targetURL.Scheme + "://" + s3Clnt.targetURL.Host,
+		Latency:  latency,
+	}, nil
+}
+
+// mainPing is the entry point for ping command.
+func mainPing(ctx *cli.Context) error {
+	if len(ctx.Args()) != 1 {
+		cli.ShowCommandHelpAndExit(ctx, "ping", 1) // last argument is exit code
+	}
+	console.SetColor("Ping", color.New(color.FgGreen, color.Bold))
+
+	alias := strings.TrimSuffix(ctx.Args().Get(0), "/")
+	if _, _, hostCfg, err := expandAlias(alias); err != nil || hostCfg == nil {
+		fatalIf(errNoMatchingHost(alias).Trace(alias), "Unable to ping.")
+	}
+
+	msg, err := pingAlias(alias)
+	if err != nil {
+		reason, status := classifyPingError(err.ToGoError())
+		errorIf(err, reason+" for `"+alias+"`.")
+		return exitStatus(status)
+	}
+	printMsg(msg)
+	return nil
+}
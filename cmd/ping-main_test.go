@@ -0,0 +1,140 @@
+/*
+ * MinIO Client (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// pingHandler accepts requests signed with the test access key only.
+func pingHandler(w http.ResponseWriter, r *http.Request) {
+	if !strings.Contains(r.Header.Get("Authorization"), "Credential="+testAccessKey+"/") {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte("<Error><Code>InvalidAccessKeyId</Code><Message>The access key does not exist</Message></Error>"))
+		return
+	}
+	w.Write([]byte(`<ListAllMyBucketsResult xmlns="http://s3.amazonaws.com/doc/2006-03-01/"><Buckets></Buckets></ListAllMyBucketsResult>`))
+}
+
+func TestPing(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(pingHandler))
+	defer server.Close()
+	tlsServer := httptest.NewTLSServer(http.HandlerFunc(pingHandler))
+	defer tlsServer.Close()
+	closedServer := httptest.NewServer(http.HandlerFunc(pingHandler))
+	closedServer.Close()
+
+	host := func(URL, accessKey string) hostConfigV9 {
+		return hostConfigV9{
+			URL:       URL,
+			AccessKey: accessKey,
+			SecretKey: testSecretKey,
+			API:       "S3v4",
+			Lookup:    "path",
+		}
+	}
+	cfg := newConfigV9()
+	cfg.Hosts["reachable"] = host(server.URL, testAccessKey)
+	cfg.Hosts["badkeys"] = host(server.URL, "UNKNOWNACCESSKEY1234")
+	cfg.Hosts["untrusted"] = host(tlsServer.URL, testAccessKey)
+	cfg.Hosts["refused"] = host(closedServer.URL, testAccessKey)
+	defer withTestConfig(cfg.Hosts)()
+
+	msg, err := pingAlias("reachable")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msg.Endpoint != server.URL || msg.Latency <= 0 {
+		t.Fatalf("Unexpected ping result %+v", msg)
+	}
+
+	testCases := []struct {
+		alias  string
+		status int
+	}{
+		{"badkeys", pingExitAuth},
+		{"untrusted", pingExitTLS},
+		{"refused", pingExitRefused},
+	}
+	for _, testCase := range testCases {
+		_, err := pingAlias(testCase.alias)
+		if err == nil {
+			t.Fatalf("%s: Expected ping to fail", testCase.alias)
+		}
+		if _, status := classifyPingError(err.ToGoError()); status != testCase.status {
+			t.Fatalf("%s: Expected exit status %d, got %d for %s", testCase.alias, testCase.status, status, err.ToGoError())
+		}
+	}
+
+	// Resolving hosts depends on the environment, check the classification only.
+	dnsErr := &url.Error{Op: "Get", URL: "http://nosuchhost.invalid/", Err: &net.OpError{Op: "dial", Err: &net.DNSError{Err: "no such host", Name: "nosuchhost.invalid"}}}
+	if _, status := classifyPingError(dnsErr); status != pingExitDNS {
+		t.Fatalf("Expected exit status %d for DNS failures, got %d", pingExitDNS, status)
+	}
+}
+
+// regionPingHandler accepts requests signed for eu-west-1 only, telling
+// the region of other requests like S3 does.
+func regionPingHandler(w http.ResponseWriter, r *http.Request) {
+	if !strings.Contains(r.Header.Get("Authorization"), "/eu-west-1/s3/") {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte("<Error><Code>AuthorizationHeaderMalformed</Code><Message>The authorization header is malformed; the region 'us-east-1' is wrong; expecting 'eu-west-1'</Message><Region>eu-west-1</Region></Error>"))
+		return
+	}
+	pingHandler(w, r)
+}
+
+func TestPingRegion(t *testing.T) {
+	var regions []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization := r.Header.Get("Authorization")
+		regions = append(regions, strings.Split(authorization[strings.Index(authorization, "Credential="):], "/")[2])
+		regionPingHandler(w, r)
+	}))
+	defer server.Close()
+
+	regional := testHost(server.URL)
+	regional.Region = "eu-west-1"
+	defer withTestConfig(map[string]hostConfigV9{
+		"regional":   regional,
+		"discovered": testHost(server.URL),
+	})()
+
+	testCases := []struct {
+		alias   string
+		regions []string
+	}{
+		// The region of the alias signs the ping.
+		{"regional", []string{"eu-west-1"}},
+		// The region reported by the server signs the ping again.
+		{"discovered", []string{"us-east-1", "eu-west-1"}},
+	}
+	for _, testCase := range testCases {
+		regions = nil
+		if _, err := pingAlias(testCase.alias); err != nil {
+			t.Fatalf("%s: %s", testCase.alias, err)
+		}
+		if strings.Join(regions, ",") != strings.Join(testCase.regions, ",") {
+			t.Fatalf("%s: Expected pings signed for %v, got %v", testCase.alias, testCase.regions, regions)
+		}
+	}
+}
@@ -17,11 +17,15 @@
 package cmd
 
 import (
+	"io"
 	"os"
 	"syscall"
 
 	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/hookreader"
 	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/minio-go/v6/pkg/encrypt"
+	"github.com/minio/minio/pkg/console"
 )
 
 var (
@@ -30,6 +34,15 @@ var (
 			Name:  "encrypt",
 			Usage: "encrypt objects (using server-side encryption with server managed keys)",
 		},
+		cli.StringFlag{
+			Name:  "part-size",
+			Usage: "multipart upload part size, e.g. 64MiB (minimum 5MiB, raised automatically for large objects)",
+		},
+		checksumFlag,
+		ifNotExistsFlag,
+		cacheControlFlag,
+		expiresFlag,
+		noSniffFlag,
 	}
 )
 
@@ -39,7 +52,7 @@ var pipeCmd = cli.Command{
 	Usage:  "stream STDIN to an object",
 	Action: mainPipe,
 	Before: setGlobalsFromContext,
-	Flags:  append(append(pipeFlags, ioFlags...), globalFlags...),
+	Flags:  append(append(append(pipeFlags, compressFlags...), ioFlags...), globalFlags...),
 	CustomHelpTemplate: `NAME:
   {{.HelpName}} - {{.Usage}}
 
@@ -65,10 +78,39 @@ EXAMPLES:
 
   4. Stream MySQL database dump to Amazon S3 directly.
      {{.Prompt}} mysqldump -u root -p ******* accountsdb | {{.HelpName}} s3/sql-backups/backups/accountsdb-oct-9-2015.sql
+
+  5. Stream a large backup to Amazon S3 using 64MiB parts for the multipart upload.
+     {{.Prompt}} tar cf - /var/backups | {{.HelpName}} --part-size 64MiB s3/backups/var.tar
+
+  6. Stream a database dump to Amazon S3 letting the server verify its SHA256 checksum.
+     {{.Prompt}} pg_dump accountsdb | {{.HelpName}} --checksum-algorithm SHA256 s3/sql-backups/accountsdb.sql
+
+  7. Claim a lock object unless another client holds it, exits with status 2 if the object exists.
+     {{.Prompt}} hostname | {{.HelpName}} --if-not-exists s3/locks/nightly-backup
+
+  8. Stream an archive to Amazon S3 without the progress bar, printing only the transferred size.
+     {{.Prompt}} tar cf - /var/backups | {{.HelpName}} --quiet s3/backups/var.tar
+
+  9. Publish a generated feed which caches may serve for an hour.
+     {{.Prompt}} ./gen-feed | {{.HelpName}} --cache-control "public, max-age=3600" --expires 1h s3/website/feed.xml
+
+  10. Stream an encrypted archive as application/octet-stream, skipping the detection of its content type.
+     {{.Prompt}} gpg -c -o - backup.tar | {{.HelpName}} --no-sniff s3/backups/backup.tar.gpg
+
+  11. Stream a database dump to Amazon S3 gzipped on the fly.
+     {{.Prompt}} mysqldump -u root -p ******* accountsdb | {{.HelpName}} --compress s3/sql-backups/accountsdb.sql
 `,
 }
 
-func pipe(targetURL string, encKeyDB map[string][]prefixSSEPair) *probe.Error {
+// pipeStream - writes reader of unknown size to targetURL with metadata,
+// reporting the bytes read to progress. Bytes are counted as they are read from reader
+// rather than as parts are sent, parts retried by multipart uploads would
+// be counted twice otherwise.
+func pipeStream(targetURL string, reader io.Reader, metadata map[string]string, sse encrypt.ServerSide, progress io.Reader) (int64, *probe.Error) {
+	return putTargetStreamWithURL(targetURL, hookreader.NewHook(reader, progress), -1, metadata, sse)
+}
+
+func pipe(targetURL string, metadata map[string]string, encKeyDB map[string][]prefixSSEPair) *probe.Error {
 	if targetURL == "" {
 		// When no target is specified, pipe cat's stdin to stdout.
 		return catOut(os.Stdin, -1).Trace()
@@ -76,10 +118,25 @@ func pipe(targetURL string, encKeyDB map[string][]prefixSSEPair) *probe.Error {
 	alias, _ := url2Alias(targetURL)
 	sseKey := getSSE(targetURL, encKeyDB[alias])
 
+	// Enable progress bar reader only during default mode, the size
+	// of the stream is unknown.
+	var progress io.Reader
+	var pg *progressBar
+	if !globalQuiet && !globalJSON {
+		pg = newProgressBar(-1)
+		pg.SetCaption(targetURL + ": ")
+		progress = pg
+	}
+
 	// Stream from stdin to multiple objects until EOF.
 	// Ignore size, since os.Stat() would not return proper size all the time
 	// for local filesystem for example /proc files.
-	_, err := putTargetStreamWithURL(targetURL, os.Stdin, -1, sseKey)
+	startTime := nowFunc()
+	n, err := pipeStream(targetURL, os.Stdin, metadata, sseKey, progress)
+	if pg != nil {
+		pg.Finish()
+		console.Eraseline()
+	}
 	// TODO: See if this check is necessary.
 	switch e := err.ToGoError().(type) {
 	case *os.PathError:
@@ -88,7 +145,17 @@ func pipe(targetURL string, encKeyDB map[string][]prefixSSEPair) *probe.Error {
 			return nil
 		}
 	}
-	return err.Trace(targetURL)
+	if err != nil {
+		return err.Trace(targetURL)
+	}
+
+	// Print the final size, known once the stream ended.
+	stat := accountStat{Total: n, Transferred: n}
+	if elapsed := nowFunc().Sub(startTime).Seconds(); elapsed > 0 {
+		stat.Speed = float64(n) / elapsed
+	}
+	printMsg(stat)
+	return nil
 }
 
 // check pipe input arguments.
@@ -107,13 +174,33 @@ func mainPipe(ctx *cli.Context) error {
 	// validate pipe input arguments.
 	checkPipeSyntax(ctx)
 
+	setGlobalPartSize(ctx.String("part-size"))
+	setGlobalChecksumAlgorithm(ctx.String("checksum-algorithm"))
+	globalIfNotExists = ctx.Bool("if-not-exists")
+	globalNoSniff = ctx.Bool("no-sniff")
+	setGlobalCompression(ctx.Bool("compress"), false, ctx.String("compress-types"))
+
+	metadata := make(map[string]string)
+	if cacheControl := ctx.String("cache-control"); cacheControl != "" {
+		metadata["Cache-Control"] = cacheControl
+	}
+	if expires := ctx.String("expires"); expires != "" {
+		expiresDate, err := parseExpires(expires)
+		fatalIf(err.Trace(expires), "Unable to parse --expires.")
+		metadata["Expires"] = expiresDate
+	}
+
 	if len(ctx.Args()) == 0 {
-		err = pipe("", nil)
+		err = pipe("", nil, nil)
 		fatalIf(err.Trace("stdout"), "Unable to write to one or more targets.")
 	} else {
 		// extract URLs.
 		URLs := ctx.Args()
-		err = pipe(URLs[0], encKeyDB)
+		err = pipe(URLs[0], metadata, encKeyDB)
+		if _, ok := err.ToGoError().(ObjectPreconditionFailed); ok {
+			errorIf(err.Trace(URLs[0]), "Unable to write to one or more targets.")
+			return exitStatus(globalPreconditionFailedExitStatus)
+		}
 		fatalIf(err.Trace(URLs[0]), "Unable to write to one or more targets.")
 	}
 
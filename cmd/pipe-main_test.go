@@ -0,0 +1,77 @@
+/*
+ * MinIO Client (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/dustin/go-humanize"
+	"github.com/fatih/color"
+)
+
+func TestPipeStreamProgress(t *testing.T) {
+	savedOutput, savedPartSize, savedChecksum := color.Output, globalPartSize, globalChecksumAlgorithm
+	defer func() {
+		color.Output, globalPartSize, globalChecksumAlgorithm = savedOutput, savedPartSize, savedChecksum
+	}()
+	color.Output = ioutil.Discard
+
+	testCases := []struct {
+		algorithm checksumAlgorithm
+		partSize  uint64
+		size      int
+	}{
+		// Multipart uploads of minio-go.
+		{checksumNone, 5 * humanize.MiByte, 5*humanize.MiByte + 3},
+		// Multipart uploads sending the checksum of every part.
+		{checksumSHA256, 4, 11},
+	}
+	for i, testCase := range testCases {
+		var requests []checksumRequest
+		handler := checksumHandler{mutex: &sync.Mutex{}, header: testCase.algorithm.header(), requests: &requests}
+		server := httptest.NewServer(handler)
+		defer withTestConfig(map[string]hostConfigV9{"myminio": testHost(server.URL)})()
+		globalPartSize, globalChecksumAlgorithm = testCase.partSize, testCase.algorithm
+
+		pg := newProgressBar(-1)
+		data := bytes.Repeat([]byte("a"), testCase.size)
+		n, err := pipeStream("myminio/bucket/object", bytes.NewReader(data), nil, nil, pg)
+		pg.Finish()
+		server.Close()
+		if err != nil {
+			t.Fatalf("Test %d: %s", i+1, err)
+		}
+
+		var streamed, parts int
+		for _, req := range requests {
+			if req.query == "part" {
+				streamed += len(req.body)
+				parts++
+			}
+		}
+		if parts < 2 {
+			t.Fatalf("Test %d: Expected a multipart upload, got %d parts", i+1, parts)
+		}
+		if n != int64(streamed) || pg.Get() != int64(streamed) || streamed != testCase.size {
+			t.Fatalf("Test %d: Expected %d bytes, streamed %d, uploaded %d and reported %d", i+1, testCase.size, streamed, n, pg.Get())
+		}
+	}
+}
@@ -0,0 +1,128 @@
+/*
+ * MinIO Client (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"os"
+	"os/signal"
+	"sync"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/mattn/go-isatty"
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/linewriter"
+	"github.com/minio/minio/pkg/console"
+)
+
+const (
+	// Output buffered when piped, written at the latest after the
+	// flush interval.
+	pipedOutputBufferSize    = 64 * 1024
+	pipedOutputFlushInterval = 100 * time.Millisecond
+
+	// Exit status of a shell command killed by SIGINT.
+	interruptExitStatus = 130
+)
+
+// pipedOutput - the buffer of the output written to color.Output, nil
+// when the output is a terminal.
+var pipedOutput *linewriter.Writer
+
+// pipedInterrupts - the interrupts of commands writing piped output,
+// which are only received while no signalTrap waits for them.
+var pipedInterrupts struct {
+	sync.Mutex
+	sigCh chan os.Signal
+	traps int
+}
+
+// bufferPipedOutput - buffers the output written by the console when it
+// is piped to other programs, so that the lines printed by concurrent
+// workers are never mixed up. Errors are written to stderr as before.
+func bufferPipedOutput() {
+	if isatty.IsTerminal(os.Stdout.Fd()) || isatty.IsCygwinTerminal(os.Stdout.Fd()) {
+		return
+	}
+	pipedOutput = linewriter.New(color.Output, pipedOutputBufferSize, pipedOutputFlushInterval)
+	color.Output = pipedOutput
+
+	// Exit statuses returned by commands, and fatal errors.
+	cli.OsExiter = exit
+	for _, fatal := range []*func(...interface{}){&console.Fatal, &console.Fatalln} {
+		fatalPrint := *fatal
+		*fatal = func(data ...interface{}) {
+			flushOutput()
+			fatalPrint(data...)
+		}
+	}
+	fatalf := console.Fatalf
+	console.Fatalf = func(format string, data ...interface{}) {
+		flushOutput()
+		fatalf(format, data...)
+	}
+
+	// Interrupted commands which do not trap SIGINT exit as they would
+	// have without the buffer, once it is written.
+	pipedInterrupts.Lock()
+	defer pipedInterrupts.Unlock()
+	pipedInterrupts.sigCh = make(chan os.Signal, 1)
+	if pipedInterrupts.traps == 0 {
+		signal.Notify(pipedInterrupts.sigCh, os.Interrupt)
+	}
+	go func() {
+		for range pipedInterrupts.sigCh {
+			exit(interruptExitStatus)
+		}
+	}()
+}
+
+// holdPipedInterrupts - leaves the interrupts to a signalTrap until
+// releasePipedInterrupts is called.
+func holdPipedInterrupts() {
+	pipedInterrupts.Lock()
+	defer pipedInterrupts.Unlock()
+	pipedInterrupts.traps++
+	if pipedInterrupts.traps == 1 && pipedInterrupts.sigCh != nil {
+		signal.Stop(pipedInterrupts.sigCh)
+	}
+}
+
+// releasePipedInterrupts - receives the interrupts again once no
+// signalTrap waits for them, a command interrupted again exits.
+func releasePipedInterrupts() {
+	pipedInterrupts.Lock()
+	defer pipedInterrupts.Unlock()
+	pipedInterrupts.traps--
+	if pipedInterrupts.traps == 0 && pipedInterrupts.sigCh != nil {
+		signal.Notify(pipedInterrupts.sigCh, os.Interrupt)
+	}
+}
+
+// flushOutput - writes the output buffered by bufferPipedOutput.
+func flushOutput() {
+	if pipedOutput != nil {
+		pipedOutput.Flush()
+	}
+}
+
+// exit - exits with status once the buffered output is written. Commands
+// exit through it, console.Fatal or by returning an exitStatus.
+func exit(status int) {
+	flushOutput()
+	os.Exit(status)
+}
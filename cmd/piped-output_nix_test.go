@@ -0,0 +1,70 @@
+// +build !windows
+
+/*
+ * MinIO Client (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"os"
+	"os/exec"
+	"os/signal"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// Tests piped output commands interrupted again once their signalTrap
+// was notified are interrupted. The signals are sent to a test process
+// of its own, which has no other traps.
+func TestPipedInterrupts(t *testing.T) {
+	if os.Getenv("MC_TEST_PIPED_INTERRUPTS") == "" {
+		cmd := exec.Command(os.Args[0], "-test.run=^TestPipedInterrupts$")
+		cmd.Env = append(os.Environ(), "MC_TEST_PIPED_INTERRUPTS=1")
+		if out, e := cmd.CombinedOutput(); e != nil {
+			t.Fatalf("%v: %s", e, out)
+		}
+		return
+	}
+
+	sigCh := make(chan os.Signal, 1)
+	pipedInterrupts.sigCh = sigCh
+	signal.Notify(sigCh, os.Interrupt)
+
+	trapCh := signalTrap(os.Interrupt)
+	if e := syscall.Kill(os.Getpid(), syscall.SIGINT); e != nil {
+		t.Fatal(e)
+	}
+	select {
+	case <-trapCh:
+	case <-time.After(10 * time.Second):
+		t.Fatal("Expected the trap to be notified")
+	}
+	select {
+	case <-sigCh:
+		t.Fatal("Expected the interrupt to be left to the trap")
+	default:
+	}
+
+	if e := syscall.Kill(os.Getpid(), syscall.SIGINT); e != nil {
+		t.Fatal(e)
+	}
+	select {
+	case <-sigCh:
+	case <-time.After(10 * time.Second):
+		t.Fatal("Expected the second interrupt to be received")
+	}
+}
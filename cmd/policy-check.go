@@ -0,0 +1,163 @@
+/*
+ * MinIO Client (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/minio-go/v6/pkg/policy"
+	"github.com/minio/minio/pkg/console"
+	"github.com/minio/minio/pkg/wildcard"
+)
+
+const (
+	// Grantee URI of the anonymous users group in object ACLs.
+	allUsersGroupURI = "http://acs.amazonaws.com/groups/global/AllUsers"
+	// Resource prefix of bucket policy resources.
+	awsResourcePrefix = "arn:aws:s3:::"
+
+	accessVerdictPublic  = "PUBLIC"
+	accessVerdictPrivate = "PRIVATE"
+)
+
+// policyCheckMessage is container for policy check command.
+type policyCheckMessage struct {
+	Status string `json:"status"`
+	URL    string `json:"url"`
+	Access string `json:"access"`
+	Reason string `json:"reason"`
+}
+
+// String colorized policy check message.
+func (s policyCheckMessage) String() string {
+	return console.Colorize("Policy", "`"+s.URL+"` is "+s.Access+": "+s.Reason)
+}
+
+// JSON jsonified policy check message.
+func (s policyCheckMessage) JSON() string {
+	policyJSONBytes, e := json.MarshalIndent(s, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(policyJSONBytes)
+}
+
+// statementMatchesRead returns true if statement applies to anonymous
+// object reads of resource.
+func statementMatchesRead(statement policy.Statement, resource string) bool {
+	if !statement.Principal.AWS.Contains("*") {
+		return false
+	}
+	if !statement.Actions.Contains("s3:GetObject") &&
+		!statement.Actions.Contains("s3:*") &&
+		!statement.Actions.Contains("*") {
+		return false
+	}
+	for r := range statement.Resources {
+		if wildcard.Match(strings.TrimPrefix(r, awsResourcePrefix), resource) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkPublicRead evaluates a bucket policy and an object ACL for
+// anonymous read access to bucket/object. Explicit deny statements
+// take precedence, statements with conditions are not considered
+// public since anonymous access depends on the request.
+func checkPublicRead(policyStr string, grants []aclGrant, bucket, object string) (public bool, reason string) {
+	resource := bucket + "/" + object
+	var conditional string
+	if policyStr != "" {
+		var p policy.BucketAccessPolicy
+		if e := json.Unmarshal([]byte(policyStr), &p); e == nil {
+			var allowed string
+			for _, statement := range p.Statements {
+				if !statementMatchesRead(statement, resource) {
+					continue
+				}
+				switch {
+				case statement.Effect == "Deny":
+					return false, "bucket policy denies anonymous read on `" + resource + "`"
+				case len(statement.Conditions) > 0:
+					conditional = "bucket policy allows anonymous read on `" + resource + "` only under conditions"
+				case statement.Effect == "Allow" && allowed == "":
+					allowed = "bucket policy allows anonymous read on `" + strings.Join(statement.Resources.ToSlice(), ", ") + "`"
+				}
+			}
+			if allowed != "" {
+				return true, allowed
+			}
+		}
+	}
+	for _, grant := range grants {
+		if grant.GranteeURI != allUsersGroupURI {
+			continue
+		}
+		if grant.Permission == "READ" || grant.Permission == "FULL_CONTROL" {
+			return true, "object ACL grants " + grant.Permission + " to all users"
+		}
+	}
+	if conditional != "" {
+		return false, conditional
+	}
+	return false, "no bucket policy statement or object ACL grant allows anonymous read"
+}
+
+// Run policy check command
+func runPolicyCheckCmd(args cli.Args) {
+	targetURL := args.First()
+	clnt, err := newClient(targetURL)
+	fatalIf(err.Trace(targetURL), "Unable to initialize target `"+targetURL+"`.")
+
+	_, policyStr, err := clnt.GetAccess()
+	if err != nil {
+		switch err.ToGoError().(type) {
+		case APINotImplemented:
+			fatalIf(err.Trace(), "Unable to check policy of a non S3 url `"+targetURL+"`.")
+		default:
+			fatalIf(err.Trace(targetURL), "Unable to get policy of target `"+targetURL+"`.")
+		}
+	}
+
+	clntURL := clnt.GetURL()
+	tokens := splitStr(clntURL.Path, string(clntURL.Separator), 3)
+	bucket, object := tokens[1], tokens[2]
+	var grants []aclGrant
+	if object != "" {
+		grants, err = clnt.GetObjectACL()
+		if err != nil {
+			// Object ACLs are not supported by all servers.
+			if _, ok := err.ToGoError().(APINotImplemented); !ok {
+				fatalIf(err.Trace(targetURL), "Unable to get ACL of target `"+targetURL+"`.")
+			}
+		}
+	}
+
+	public, reason := checkPublicRead(policyStr, grants, bucket, object)
+	access := accessVerdictPrivate
+	if public {
+		access = accessVerdictPublic
+	}
+	printMsg(policyCheckMessage{
+		Status: "success",
+		URL:    targetURL,
+		Access: access,
+		Reason: reason,
+	})
+}
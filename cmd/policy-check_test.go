@@ -0,0 +1,69 @@
+/*
+ * MinIO Client (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import "testing"
+
+func TestCheckPublicRead(t *testing.T) {
+	const publicReadPolicy = `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Principal":{"AWS":["*"]},"Action":["s3:GetBucketLocation","s3:ListBucket"],"Resource":["arn:aws:s3:::shared"]},{"Effect":"Allow","Principal":{"AWS":["*"]},"Action":["s3:GetObject"],"Resource":["arn:aws:s3:::shared/*"]}]}`
+	const prefixPolicy = `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Principal":"*","Action":["s3:GetObject"],"Resource":["arn:aws:s3:::shared/images/*"]}]}`
+	const denyPolicy = `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Principal":"*","Action":["s3:GetObject"],"Resource":["arn:aws:s3:::shared/*"]},{"Effect":"Deny","Principal":"*","Action":["s3:*"],"Resource":["arn:aws:s3:::shared/secret/*"]}]}`
+	const conditionalPolicy = `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Principal":"*","Action":["s3:GetObject"],"Resource":["arn:aws:s3:::shared/*"],"Condition":{"IpAddress":{"aws:SourceIp":["10.0.0.0/8"]}}}]}`
+	const uploadPolicy = `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Principal":"*","Action":["s3:PutObject"],"Resource":["arn:aws:s3:::shared/*"]}]}`
+
+	publicACL := []aclGrant{
+		{GranteeID: "owner", Permission: "FULL_CONTROL"},
+		{GranteeURI: allUsersGroupURI, Permission: "READ"},
+	}
+	privateACL := []aclGrant{
+		{GranteeID: "owner", Permission: "FULL_CONTROL"},
+	}
+
+	testCases := []struct {
+		policy string
+		grants []aclGrant
+		object string
+		public bool
+	}{
+		// Private bucket and private object.
+		{"", privateACL, "report.pdf", false},
+		// Public-read bucket policy.
+		{publicReadPolicy, privateACL, "report.pdf", true},
+		{publicReadPolicy, nil, "images/logo.png", true},
+		// Prefix scoped policy.
+		{prefixPolicy, nil, "images/logo.png", true},
+		{prefixPolicy, nil, "report.pdf", false},
+		// Public-read object ACL.
+		{"", publicACL, "report.pdf", true},
+		// Explicit deny wins over allow statements and ACLs.
+		{denyPolicy, nil, "report.pdf", true},
+		{denyPolicy, publicACL, "secret/keys.txt", false},
+		// Conditional statements are not public.
+		{conditionalPolicy, nil, "report.pdf", false},
+		// Write-only policy does not allow reads.
+		{uploadPolicy, nil, "report.pdf", false},
+	}
+	for i, testCase := range testCases {
+		public, reason := checkPublicRead(testCase.policy, testCase.grants, "shared", testCase.object)
+		if public != testCase.public {
+			t.Errorf("Test %d: expected public %t, got %t (%s)", i+1, testCase.public, public, reason)
+		}
+		if reason == "" {
+			t.Errorf("Test %d: expected a reason", i+1)
+		}
+	}
+}
@@ -56,6 +56,7 @@ USAGE:
   {{.HelpName}} get [FLAGS] TARGET
   {{.HelpName}} get-json [FLAGS] TARGET
   {{.HelpName}} list [FLAGS] TARGET
+  {{.HelpName}} check [FLAGS] TARGET
 {{if .VisibleFlags}}
 FLAGS:
   {{range .VisibleFlags}}{{.}}
@@ -93,6 +94,9 @@ EXAMPLES:
 
    9. List public object URLs recursively.
       {{.Prompt}} {{.HelpName}} --recursive links s3/shared/
+
+  10. Check if an object is publicly readable through the bucket policy or its ACL.
+      {{.Prompt}} {{.HelpName}} check s3/shared/report.pdf
 `,
 }
 
@@ -229,6 +233,11 @@ func checkPolicySyntax(ctx *cli.Context) {
 		if argsLength != 2 {
 			cli.ShowCommandHelpAndExit(ctx, "policy", 1)
 		}
+	case "check":
+		// Always expect an argument after check cmd
+		if argsLength != 2 {
+			cli.ShowCommandHelpAndExit(ctx, "policy", 1)
+		}
 	default:
 		cli.ShowCommandHelpAndExit(ctx, "policy", 1)
 	}
@@ -485,6 +494,9 @@ func mainPolicy(ctx *cli.Context) error {
 	case "links":
 		// policy links alias/bucket/prefix
 		runPolicyLinksCmd(ctx.Args().Tail(), ctx.Bool("recursive"))
+	case "check":
+		// policy check alias/bucket/prefix/object
+		runPolicyCheckCmd(ctx.Args().Tail())
 	default:
 		// Shows command example and exit
 		cli.ShowCommandHelpAndExit(ctx, "policy", 1)
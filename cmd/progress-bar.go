@@ -32,7 +32,8 @@ type progressBar struct {
 	*pb.ProgressBar
 }
 
-// newProgressBar - instantiate a progress bar.
+// newProgressBar - instantiate a progress bar, a negative total is an
+// unknown size showing the transferred bytes and the speed only.
 func newProgressBar(total int64) *progressBar {
 	// Progress bar speific theme customization.
 	console.SetColor("Bar", color.New(color.FgGreen, color.Bold))
@@ -41,6 +42,12 @@ func newProgressBar(total int64) *progressBar {
 
 	// get the new original progress bar.
 	bar := pb.New64(total)
+	if total < 0 {
+		bar.Total = 0
+		bar.ShowBar = false
+		bar.ShowPercent = false
+		bar.ShowTimeLeft = false
+	}
 
 	// Set new human friendly print units.
 	bar.SetUnits(pb.U_BYTES)
@@ -97,9 +104,10 @@ func (p *progressBar) Set64(length int64) *progressBar {
 func (p *progressBar) Read(buf []byte) (n int, err error) {
 	defer func() {
 		// After updating the internal progress bar, make sure that its
-		// current progress doesn't exceed the specified total progress
+		// current progress doesn't exceed the specified total progress,
+		// unknown sizes have no total to stop at.
 		currentProgress := p.ProgressBar.Get()
-		if currentProgress > p.ProgressBar.Total {
+		if p.ProgressBar.Total > 0 && currentProgress > p.ProgressBar.Total {
 			p.ProgressBar.Set64(p.ProgressBar.Total)
 		}
 	}()
@@ -111,9 +119,40 @@ func (p *progressBar) SetTotal(total int64) {
 	p.ProgressBar.Total = total
 }
 
-// cursorAnimate - returns a animated rune through read channel for every read.
-func cursorAnimate() <-chan string {
-	cursorCh := make(chan string)
+// progressSpinner is implemented by progress readers able to show that
+// a transfer reporting no progress until it completes is ongoing.
+type progressSpinner interface {
+	// spin - animates the progress until stop is called.
+	spin() (stop func())
+}
+
+// spin - animates a cursor after the bar.
+func (p *progressBar) spin() func() {
+	doneCh := make(chan struct{})
+	stoppedCh := make(chan struct{})
+	go func() {
+		defer close(stoppedCh)
+		ticker := time.NewTicker(p.ProgressBar.RefreshRate)
+		defer ticker.Stop()
+		cursors := []rune(cursorRunes())
+		for i := 0; ; i++ {
+			p.ProgressBar.Postfix(" " + string(cursors[i%len(cursors)]))
+			select {
+			case <-doneCh:
+				p.ProgressBar.Postfix("")
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	return func() {
+		close(doneCh)
+		<-stoppedCh
+	}
+}
+
+// cursorRunes - returns the frames of animated cursors.
+func cursorRunes() string {
 	var cursors string
 
 	switch runtime.GOOS {
@@ -130,6 +169,13 @@ func cursorAnimate() <-chan string {
 	default:
 		cursors = "|/-\\"
 	}
+	return cursors
+}
+
+// cursorAnimate - returns a animated rune through read channel for every read.
+func cursorAnimate() <-chan string {
+	cursorCh := make(chan string)
+	cursors := cursorRunes()
 	go func() {
 		for {
 			for _, cursor := range cursors {
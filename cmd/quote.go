@@ -0,0 +1,101 @@
+/*
+ * MinIO Client (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/minio/cli"
+	"github.com/minio/minio/pkg/console"
+)
+
+// nullFlag - prints the keys listed by ls and find terminated by NUL
+// instead of newlines and unquoted, for xargs -0.
+var nullFlag = cli.BoolFlag{
+	Name:  "null, 0",
+	Usage: "print only the keys, unquoted and terminated by NUL instead of newlines, for 'xargs -0'",
+}
+
+// isShellSafe - reports whether r needs no quoting in a shell word.
+func isShellSafe(r rune) bool {
+	if r < unicode.MaxASCII {
+		return r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' ||
+			strings.ContainsRune("-_./+=:,@%^", r)
+	}
+	// Printable non-ASCII characters are left alone, names in other
+	// scripts would be unreadable otherwise.
+	return unicode.IsPrint(r)
+}
+
+// quoteKey - quotes a key with spaces, control or shell characters for
+// it to be read back as a single word by a shell. Control characters
+// are escaped with $'...', other keys are single quoted. Keys needing
+// no quotes are returned as is.
+func quoteKey(key string) string {
+	if key == "" {
+		return key
+	}
+	var hasControl, needsQuotes bool
+	for _, r := range key {
+		if !isShellSafe(r) {
+			needsQuotes = true
+			if unicode.IsControl(r) || r == utf8.RuneError {
+				hasControl = true
+			}
+		}
+	}
+	if !needsQuotes {
+		return key
+	}
+	if !hasControl {
+		return "'" + strings.Replace(key, "'", `'\''`, -1) + "'"
+	}
+
+	var b strings.Builder
+	b.WriteString("$'")
+	for i := 0; i < len(key); {
+		r, size := utf8.DecodeRuneInString(key[i:])
+		switch {
+		case r == '\n':
+			b.WriteString(`\n`)
+		case r == '\t':
+			b.WriteString(`\t`)
+		case r == '\r':
+			b.WriteString(`\r`)
+		case r == '\\' || r == '\'':
+			b.WriteString(`\` + string(r))
+		case r == utf8.RuneError || unicode.IsControl(r):
+			// Invalid UTF-8 and control characters byte by byte.
+			for _, c := range []byte(key[i : i+size]) {
+				fmt.Fprintf(&b, `\x%02x`, c)
+			}
+		default:
+			b.WriteString(key[i : i+size])
+		}
+		i += size
+	}
+	b.WriteString("'")
+	return b.String()
+}
+
+// printNull - prints key terminated by NUL, for --null.
+func printNull(key string) {
+	console.Print(key + "\x00")
+}
@@ -206,13 +206,13 @@ func mainRemoveBucket(ctx *cli.Context) error {
 	// Additional command specific theme customization.
 	console.SetColor("RemoveBucket", color.New(color.FgGreen, color.Bold))
 
-	var cErr error
+	var result batchResult
 	for _, targetURL := range ctx.Args() {
 		// Instantiate client for URL.
 		clnt, err := newClient(targetURL)
 		if err != nil {
 			errorIf(err.Trace(targetURL), "Invalid target `"+targetURL+"`.")
-			cErr = exitStatus(globalErrorExitStatus)
+			result.failure()
 			continue
 		}
 		_, err = clnt.Stat(false, false, false, nil)
@@ -221,7 +221,7 @@ func mainRemoveBucket(ctx *cli.Context) error {
 			case BucketNameEmpty:
 			default:
 				errorIf(err.Trace(targetURL), "Unable to validate target `"+targetURL+"`.")
-				cErr = exitStatus(globalErrorExitStatus)
+				result.failure()
 				continue
 
 			}
@@ -233,17 +233,23 @@ func mainRemoveBucket(ctx *cli.Context) error {
 		}
 		// For all recursive operations make sure to check for 'force' flag.
 		if !isForce && !isEmpty {
-			fatalIf(errDummy().Trace(), "`"+targetURL+"` is not empty. Retry this command with ‘--force’ flag if you want to remove `"+targetURL+"` and all its contents")
+			errorIf(errDummy().Trace(), "`"+targetURL+"` is not empty. Retry this command with ‘--force’ flag if you want to remove `"+targetURL+"` and all its contents")
+			result.failure()
+			continue
 		}
 
-		e := deleteBucket(targetURL)
-		fatalIf(e.Trace(targetURL), "Failed to remove `"+targetURL+"`.")
+		if e := deleteBucket(targetURL); e != nil {
+			errorIf(e.Trace(targetURL), "Failed to remove `"+targetURL+"`.")
+			result.failure()
+			continue
+		}
 
 		if !isNamespaceRemoval(targetURL) {
 			printMsg(removeBucketMessage{
 				Bucket: targetURL, Status: "success",
 			})
 		}
+		result.success()
 	}
-	return cErr
+	return result.exit()
 }
@@ -0,0 +1,224 @@
+/*
+ * MinIO Client (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	json "github.com/minio/mc/pkg/colorjson"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/minio/pkg/console"
+)
+
+var resolveCmd = cli.Command{
+	Name:   "resolve",
+	Usage:  "show the endpoint, bucket and key a target resolves to",
+	Action: mainResolve,
+	Before: setGlobalsFromContext,
+	Flags:  globalFlags,
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [FLAGS] TARGET
+
+  TARGET is resolved as by any other command, through the alias it starts
+  with, an MC_HOST_<alias> environment variable or the host matching the
+  endpoint of a URL. Targets matching no host are local paths. Secret keys
+  are not shown.
+{{if .VisibleFlags}}
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}{{end}}
+EXAMPLES:
+  1. Show the endpoint and bucket an object of the alias "s3" is read from.
+     {{.Prompt}} {{.HelpName}} s3/mybucket/photos/2020/january.jpg
+
+  2. Show which host a URL is matched with, for its credentials.
+     {{.Prompt}} {{.HelpName}} https://play.min.io/mybucket
+
+  3. Show where an alias points with --endpoint-url.
+     {{.Prompt}} {{.HelpName}} --endpoint-url http://localhost:9001 myminio/mybucket
+`,
+}
+
+// Sources of the config of a host.
+const (
+	hostSourceConfig = "config"
+	hostSourceEnv    = "env"
+	hostSourceURL    = "url"
+)
+
+// resolveMessage container for the resolution of a target.
+type resolveMessage struct {
+	Status string `json:"status"`
+	Target string `json:"target"`
+	// "object-storage" or "filesystem".
+	Type string `json:"type"`
+	// Alias of the host used, empty for local paths.
+	Alias string `json:"alias,omitempty"`
+	// The config file, an environment variable or the URL of a host
+	// matching the endpoint of the target.
+	HostSource string `json:"hostSource,omitempty"`
+	URL        string `json:"url"`
+	Scheme     string `json:"scheme,omitempty"`
+	// Endpoint requests are sent to, which may be the regional endpoint
+	// a bucket was redirected to.
+	Endpoint string `json:"endpoint,omitempty"`
+	Region   string `json:"region,omitempty"`
+	Bucket   string `json:"bucket,omitempty"`
+	Key      string `json:"key,omitempty"`
+	// "dns" or "path".
+	Lookup string `json:"lookup,omitempty"`
+	// Path of local targets.
+	Path string `json:"path,omitempty"`
+	// With the secret key redacted.
+	HostConfig *hostConfigV9 `json:"hostConfig,omitempty"`
+}
+
+// String colorized resolution message.
+func (r resolveMessage) String() string {
+	var lines [][2]string
+	add := func(name, value string) {
+		if value != "" {
+			lines = append(lines, [2]string{name, value})
+		}
+	}
+	add("Target", r.Target)
+	add("Type", r.Type)
+	switch r.HostSource {
+	case hostSourceConfig:
+		add("Alias", r.Alias+" (config file)")
+	case hostSourceEnv:
+		add("Alias", r.Alias+" (environment variable "+mcEnvHostPrefix+r.Alias+")")
+	case hostSourceURL:
+		add("Alias", r.Alias+" (matching the URL of the host)")
+	}
+	add("URL", r.URL)
+	add("Scheme", r.Scheme)
+	add("Endpoint", r.Endpoint)
+	add("Region", r.Region)
+	add("Bucket", r.Bucket)
+	add("Key", r.Key)
+	add("Lookup", r.Lookup)
+	add("Path", r.Path)
+	if r.HostConfig != nil {
+		add("AccessKey", r.HostConfig.AccessKey)
+		add("SecretKey", r.HostConfig.SecretKey)
+		add("API", r.HostConfig.API)
+	}
+
+	var msg []string
+	for _, line := range lines {
+		msg = append(msg, console.Colorize("ResolveName", fmt.Sprintf("%-10s:", line[0]))+" "+console.Colorize("ResolveValue", line[1]))
+	}
+	return strings.Join(msg, "\n")
+}
+
+// JSON jsonified resolution message.
+func (r resolveMessage) JSON() string {
+	r.Status = "success"
+	resolveJSONBytes, e := json.MarshalIndent(r, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(resolveJSONBytes)
+}
+
+// resolveTarget - resolves target through expandAlias and newClient,
+// as every command does, and describes the client created.
+func resolveTarget(target string) (resolveMessage, *probe.Error) {
+	alias, _, hostCfg, err := expandAlias(target)
+	if err != nil {
+		return resolveMessage{}, err.Trace(target)
+	}
+	clnt, err := newClient(target)
+	if err != nil {
+		return resolveMessage{}, err.Trace(target)
+	}
+
+	msg := resolveMessage{Target: target, URL: clnt.GetURL().String()}
+	switch c := clnt.(type) {
+	case *s3Client:
+		msg.Type = "object-storage"
+		msg.Scheme = c.targetURL.Scheme
+		msg.Endpoint = c.targetURL.Scheme + "://" + c.hostName
+		redirect := c.getRedirect()
+		if redirect.Endpoint != "" {
+			msg.Endpoint = c.targetURL.Scheme + "://" + redirect.Endpoint
+		}
+		msg.Region = redirect.Region
+		msg.Bucket, msg.Key = c.url2BucketAndObject()
+		msg.Lookup = "path"
+		if c.getVirtualStyle() {
+			msg.Lookup = "dns"
+		}
+	case *fsClient:
+		msg.Type = "filesystem"
+		msg.Path = c.PathURL.Path
+		return msg, nil
+	}
+
+	_, fromEnv := os.LookupEnv(mcEnvHostPrefix + alias)
+	if !fromEnv {
+		_, fromEnv = os.LookupEnv(mcEnvHostsDeprecatedPrefix + alias)
+	}
+	msg.Alias = alias
+	switch {
+	case urlRgx.MatchString(target) && alias == hostCfg.URL:
+		// Matched by the endpoint of the URL, see getHostConfigByURL.
+		mcCfg, err := loadMcConfig()
+		if err != nil {
+			return resolveMessage{}, err.Trace(target)
+		}
+		msg.Alias, msg.HostSource = matchHostAlias(mcCfg, newClientURL(target)), hostSourceURL
+	case fromEnv:
+		msg.HostSource = hostSourceEnv
+	default:
+		msg.HostSource = hostSourceConfig
+	}
+
+	redacted := *hostCfg
+	if redacted.SecretKey != "" {
+		redacted.SecretKey = redactedSecretKey
+	}
+	if redacted.SessionToken != "" {
+		redacted.SessionToken = redactedSecretKey
+	}
+	redacted.creds = nil
+	msg.HostConfig = &redacted
+	return msg, nil
+}
+
+// mainResolve is the entry point for resolve command.
+func mainResolve(ctx *cli.Context) error {
+	if len(ctx.Args()) != 1 {
+		cli.ShowCommandHelpAndExit(ctx, "resolve", 1) // last argument is exit code
+	}
+	console.SetColor("ResolveName", color.New(color.Bold))
+	console.SetColor("ResolveValue", color.New(color.FgGreen))
+
+	target := ctx.Args().Get(0)
+	msg, err := resolveTarget(target)
+	fatalIf(err, "Unable to resolve `"+target+"`.")
+
+	printMsg(msg)
+	return nil
+}
@@ -0,0 +1,142 @@
+/*
+ * MinIO Client (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	json "github.com/minio/mc/pkg/colorjson"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/minio/pkg/console"
+)
+
+var (
+	restoreFlags = []cli.Flag{
+		cli.IntFlag{
+			Name:  "days",
+			Value: 1,
+			Usage: "number of days the restored copy is kept",
+		},
+		cli.StringFlag{
+			Name:  "tier",
+			Value: "Standard",
+			Usage: "retrieval tier, one of [Standard, Bulk, Expedited]",
+		},
+	}
+)
+
+var restoreCmd = cli.Command{
+	Name:   "restore",
+	Usage:  "restore a temporary copy of archived objects",
+	Action: mainRestore,
+	Before: setGlobalsFromContext,
+	Flags:  append(restoreFlags, globalFlags...),
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [FLAGS] TARGET [TARGET...]
+{{if .VisibleFlags}}
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}{{end}}
+EXAMPLES:
+  1. Restore an object archived in GLACIER for 3 days.
+     {{.Prompt}} {{.HelpName}} --days 3 s3/mybucket/archive.tar
+
+  2. Restore an object archived in DEEP_ARCHIVE using the cheapest retrieval tier.
+     {{.Prompt}} {{.HelpName}} --tier Bulk s3/mybucket/archive.tar
+
+  3. Check the progress of a restore.
+     {{.Prompt}} mc stat s3/mybucket/archive.tar
+`,
+}
+
+// restoreMessage container for restore request messages.
+type restoreMessage struct {
+	Status string `json:"status"`
+	URL    string `json:"url"`
+	Days   int    `json:"days"`
+	Tier   string `json:"tier"`
+}
+
+// String colorized restore message.
+func (r restoreMessage) String() string {
+	return console.Colorize("Restore", fmt.Sprintf("Restore of `%s` requested for %d days using the %s tier.", r.URL, r.Days, r.Tier))
+}
+
+// JSON jsonified restore message.
+func (r restoreMessage) JSON() string {
+	r.Status = "success"
+	restoreJSONBytes, e := json.MarshalIndent(r, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(restoreJSONBytes)
+}
+
+// parseRestoreTier - validates a retrieval tier, the case does not matter.
+func parseRestoreTier(tier string) (string, *probe.Error) {
+	for _, t := range []string{"Standard", "Bulk", "Expedited"} {
+		if strings.EqualFold(tier, t) {
+			return t, nil
+		}
+	}
+	return "", errInvalidArgument().Trace(tier)
+}
+
+// checkRestoreSyntax - validate all the passed arguments
+func checkRestoreSyntax(ctx *cli.Context) {
+	if !ctx.Args().Present() {
+		cli.ShowCommandHelpAndExit(ctx, "restore", 1) // last argument is exit code
+	}
+	if ctx.Int("days") < 1 {
+		fatalIf(errInvalidArgument().Trace(ctx.String("days")), "Number of days must be at least 1.")
+	}
+	_, err := parseRestoreTier(ctx.String("tier"))
+	fatalIf(err, "Invalid retrieval tier. Valid options are `[Standard, Bulk, Expedited]`.")
+}
+
+// mainRestore is the entry point for restore command.
+func mainRestore(ctx *cli.Context) error {
+	checkRestoreSyntax(ctx)
+
+	console.SetColor("Restore", color.New(color.FgGreen, color.Bold))
+
+	days := ctx.Int("days")
+	tier, _ := parseRestoreTier(ctx.String("tier"))
+
+	var result batchResult
+	for _, targetURL := range ctx.Args() {
+		clnt, err := newClient(targetURL)
+		if err != nil {
+			errorIf(err.Trace(targetURL), "Invalid target `"+targetURL+"`.")
+			result.failure()
+			continue
+		}
+		if err = clnt.RestoreObject(days, tier); err != nil {
+			errorIf(err.Trace(targetURL), "Unable to restore `"+targetURL+"`.")
+			result.failure()
+			continue
+		}
+		printMsg(restoreMessage{URL: targetURL, Days: days, Tier: tier})
+		result.success()
+	}
+	return result.exit()
+}
@@ -0,0 +1,147 @@
+/*
+ * MinIO Client (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// restoreHandler answers restore requests of the archived object
+// "archived", which can be stat'ed but not read until restored.
+type restoreHandler struct {
+	requests *[]string
+}
+
+func (h restoreHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	if _, ok := query["location"]; ok {
+		w.Write([]byte(`<LocationConstraint xmlns="http://s3.amazonaws.com/doc/2006-03-01/"></LocationConstraint>`))
+		return
+	}
+	if r.URL.Path != "/bucket/archived" {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("<Error><Code>NoSuchKey</Code><Message>The specified key does not exist.</Message></Error>"))
+		return
+	}
+	if _, ok := query["restore"]; ok && r.Method == http.MethodPost {
+		var restore struct {
+			Days int    `xml:"Days"`
+			Tier string `xml:"GlacierJobParameters>Tier"`
+		}
+		if e := xml.NewDecoder(r.Body).Decode(&restore); e != nil || r.Header.Get("Content-Md5") == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		*h.requests = append(*h.requests, restore.Tier)
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+	if r.Method == http.MethodHead {
+		w.Header().Set("Content-Length", "10")
+		w.Header().Set("ETag", `"etag"`)
+		w.Header().Set("Last-Modified", "Wed, 01 Jan 2020 00:00:00 GMT")
+		w.Header().Set("X-Amz-Storage-Class", "GLACIER")
+		return
+	}
+	w.WriteHeader(http.StatusForbidden)
+	w.Write([]byte("<Error><Code>InvalidObjectState</Code><Message>The operation is not valid for the object's storage class</Message></Error>"))
+}
+
+func TestRestoreObject(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(restoreHandler{requests: &requests})
+	defer server.Close()
+
+	newRestoreClient := func(object string) Client {
+		conf := newTestS3Config(server.URL + "/bucket/" + object)
+		clnt, err := s3New(conf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return clnt
+	}
+
+	if err := newRestoreClient("archived").RestoreObject(3, "Bulk"); err != nil {
+		t.Fatal(err)
+	}
+	if len(requests) != 1 || requests[0] != "Bulk" {
+		t.Fatalf("Expected a single restore request with the Bulk tier, got %v", requests)
+	}
+	err := newRestoreClient("missing").RestoreObject(3, "Bulk")
+	if _, ok := err.ToGoError().(ObjectMissing); !ok {
+		t.Fatalf("Expected a missing object error, got %v", err)
+	}
+
+	// Reading an archived object tells so instead of denying access.
+	_, err = newRestoreClient("archived").Get(nil)
+	if _, ok := err.ToGoError().(ObjectOnGlacier); !ok {
+		t.Fatalf("Expected an archived object error, got %v", err)
+	}
+}
+
+func TestParseRestoreTier(t *testing.T) {
+	testCases := []struct {
+		tier     string
+		expected string
+		success  bool
+	}{
+		{"Standard", "Standard", true},
+		{"bulk", "Bulk", true},
+		{"EXPEDITED", "Expedited", true},
+		{"fast", "", false},
+		{"", "", false},
+	}
+	for i, testCase := range testCases {
+		tier, err := parseRestoreTier(testCase.tier)
+		if testCase.success != (err == nil) || tier != testCase.expected {
+			t.Fatalf("Test %d: Expected %q, got %q (%v)", i+1, testCase.expected, tier, err)
+		}
+	}
+}
+
+func TestParseRestoreStatus(t *testing.T) {
+	testCases := []struct {
+		header   string
+		expected *restoreStatus
+	}{
+		{`ongoing-request="true"`, &restoreStatus{OngoingRequest: true}},
+		{`ongoing-request="false", expiry-date="Fri, 23 Dec 2012 00:00:00 GMT"`,
+			&restoreStatus{ExpiryDate: time.Date(2012, 12, 23, 0, 0, 0, 0, time.UTC)}},
+		{`ongoing-request="maybe"`, nil},
+		{`expiry-date="Fri, 23 Dec 2012 00:00:00 GMT"`, nil},
+		{`ongoing-request="false", expiry-date="tomorrow"`, nil},
+	}
+	for i, testCase := range testCases {
+		status, err := parseRestoreStatus(testCase.header)
+		if testCase.expected == nil {
+			if err == nil {
+				t.Fatalf("Test %d: Expected %q to fail", i+1, testCase.header)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("Test %d: %v", i+1, err)
+		}
+		if status.OngoingRequest != testCase.expected.OngoingRequest || !status.ExpiryDate.Equal(testCase.expected.ExpiryDate) {
+			t.Fatalf("Test %d: Expected %+v, got %+v", i+1, testCase.expected, status)
+		}
+	}
+}
@@ -37,11 +37,15 @@ var retentionCmd = cli.Command{
 	Action: mainRetention,
 	Before: setGlobalsFromContext,
 	Flags:  globalFlags,
+	Subcommands: []cli.Command{
+		retentionSetCmd,
+	},
 	CustomHelpTemplate: `NAME:
   {{.HelpName}} - {{.Usage}}
 
 USAGE:
   {{.HelpName}} [FLAGS] TARGET [governance | compliance] [VALIDITY]
+  {{.HelpName}} set --mode MODE --retain-until DATE TARGET
 
 FLAGS:
   {{range .VisibleFlags}}{{.}}
@@ -52,6 +56,9 @@ VALIDITY:
 EXAMPLES:
    1. Set object retention for objects in a given prefix
      $ {{.HelpName}} myminio/mybucket/prefix compliance 30d
+
+   2. Set object retention in governance mode until a given date
+     $ {{.HelpName}} set --mode GOVERNANCE --retain-until 2025-01-01 myminio/mybucket/myobject
 `,
 }
 
@@ -79,8 +86,44 @@ func (m retentionCmdMessage) JSON() string {
 	return string(msgBytes)
 }
 
+// retentionUntil - returns the retain until date for a validity period from now.
+func retentionUntil(validity uint, unit minio.ValidityUnit) time.Time {
+	t := UTCNow()
+	if unit == minio.Years {
+		t = t.AddDate(int(validity), 0, 0)
+	} else {
+		t = t.AddDate(0, 0, int(validity))
+	}
+	return t.Truncate(time.Second)
+}
+
+// parseRetentionMode - parses and validates an object retention mode.
+func parseRetentionMode(modeStr string) (minio.RetentionMode, *probe.Error) {
+	mode := minio.RetentionMode(strings.ToUpper(modeStr))
+	if !mode.IsValid() {
+		return "", errInvalidArgument().Trace(modeStr)
+	}
+	return mode, nil
+}
+
+// parseRetainUntil - parses a retain until date, either as a day
+// such as 2025-01-01 or as RFC3339. The date must be in the future.
+func parseRetainUntil(dateStr string) (time.Time, *probe.Error) {
+	t, e := time.Parse(time.RFC3339, dateStr)
+	if e != nil {
+		t, e = time.Parse("2006-01-02", dateStr)
+	}
+	if e != nil {
+		return timeSentinel, probe.NewError(e).Trace(dateStr)
+	}
+	if !t.After(UTCNow()) {
+		return timeSentinel, probe.NewError(errors.New("retain until date must be in the future")).Trace(dateStr)
+	}
+	return t.UTC(), nil
+}
+
 // setRetention - Set Retention for all objects within a given prefix.
-func setRetention(urlStr string, mode *minio.RetentionMode, validity *uint, unit *minio.ValidityUnit) error {
+func setRetention(urlStr string, mode minio.RetentionMode, retainUntil time.Time, validity *string) error {
 	clnt, err := newClient(urlStr)
 	if err != nil {
 		fatalIf(err.Trace(), "Cannot parse the provided url.")
@@ -93,38 +136,6 @@ func setRetention(urlStr string, mode *minio.RetentionMode, validity *uint, unit
 	}
 
 	alias, _, _ := mustExpandAlias(urlStr)
-	retainUntilDate := func() (time.Time, error) {
-		if validity == nil {
-			return timeSentinel, fmt.Errorf("invalid validity '%v'", validity)
-		}
-		t := UTCNow()
-		if *unit == minio.Years {
-			t = t.AddDate(int(*validity), 0, 0)
-		} else {
-			t = t.AddDate(0, 0, int(*validity))
-		}
-		timeStr := t.Format(time.RFC3339)
-
-		t1, e := time.Parse(
-			time.RFC3339,
-			timeStr)
-		if e != nil {
-			return timeSentinel, e
-		}
-		return t1, nil
-	}
-	validityStr := func() *string {
-		if validity == nil {
-			return nil
-		}
-
-		unitStr := "d"
-		if *unit == minio.Years {
-			unitStr = "y"
-		}
-		s := fmt.Sprint(*validity, unitStr)
-		return &s
-	}
 
 	var cErr error
 	errorsFound := false
@@ -134,22 +145,17 @@ func setRetention(urlStr string, mode *minio.RetentionMode, validity *uint, unit
 			cErr = exitStatus(globalErrorExitStatus) // Set the exit status.
 			continue
 		}
-		retainUntil, err := retainUntilDate()
-		if err != nil {
-			errorIf(content.Err.Trace(clnt.GetURL().String()), "Invalid retention date")
-			continue
-		}
 		newClnt, perr := newClientFromAlias(alias, content.URL.String())
 		if perr != nil {
-			errorIf(content.Err.Trace(clnt.GetURL().String()), "Invalid URL")
+			errorIf(perr.Trace(clnt.GetURL().String()), "Invalid URL")
 			continue
 		}
-		probeErr := newClnt.PutObjectRetention(mode, &retainUntil)
+		probeErr := newClnt.PutObjectRetention(&mode, &retainUntil)
 		if probeErr != nil {
 			errorsFound = true
 			printMsg(retentionCmdMessage{
-				Mode:     *mode,
-				Validity: validityStr(),
+				Mode:     mode,
+				Validity: validity,
 				Status:   "failure",
 				URLPath:  content.URL.Path,
 				Err:      probeErr.ToGoError(),
@@ -157,8 +163,8 @@ func setRetention(urlStr string, mode *minio.RetentionMode, validity *uint, unit
 		} else {
 			if globalJSON {
 				printMsg(retentionCmdMessage{
-					Mode:     *mode,
-					Validity: validityStr(),
+					Mode:     mode,
+					Validity: validity,
 					Status:   "success",
 					URLPath:  content.URL.Path,
 				})
@@ -233,5 +239,11 @@ func mainRetention(ctx *cli.Context) error {
 	default:
 		cli.ShowCommandHelpAndExit(ctx, "retention", 1)
 	}
-	return setRetention(urlStr, mode, validity, unit)
+
+	unitStr := "d"
+	if *unit == minio.Years {
+		unitStr = "y"
+	}
+	validityStr := fmt.Sprint(*validity, unitStr)
+	return setRetention(urlStr, *mode, retentionUntil(*validity, *unit), &validityStr)
 }
@@ -0,0 +1,149 @@
+/*
+ * MinIO Client (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/xml"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// lockRequest is a PUT request recorded by lockHandler.
+type lockRequest struct {
+	query      string
+	contentMD5 string
+	body       []byte
+}
+
+// lockHandler records object retention and legal hold requests.
+type lockHandler struct {
+	mutex    *sync.Mutex
+	requests *[]lockRequest
+}
+
+func (h lockHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	if _, ok := query["location"]; ok {
+		w.Write([]byte(`<LocationConstraint xmlns="http://s3.amazonaws.com/doc/2006-03-01/"></LocationConstraint>`))
+		return
+	}
+	if r.Method != http.MethodPut || r.URL.Path != "/bucket/object" {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	body, _ := ioutil.ReadAll(r.Body)
+	h.mutex.Lock()
+	for key := range query {
+		if key == "retention" || key == "legal-hold" {
+			*h.requests = append(*h.requests, lockRequest{query: key, contentMD5: r.Header.Get("Content-Md5"), body: body})
+		}
+	}
+	h.mutex.Unlock()
+	w.WriteHeader(http.StatusOK)
+}
+
+func TestPutObjectRetentionAndLegalHold(t *testing.T) {
+	var requests []lockRequest
+	server := httptest.NewServer(lockHandler{mutex: &sync.Mutex{}, requests: &requests})
+	defer server.Close()
+
+	conf := newTestS3Config(server.URL + "/bucket/object")
+	clnt, err := s3New(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mode, err := parseRetentionMode("governance")
+	if err != nil {
+		t.Fatal(err)
+	}
+	retainUntil := time.Date(2099, 1, 1, 0, 0, 0, 0, time.UTC)
+	if err = clnt.PutObjectRetention(&mode, &retainUntil); err != nil {
+		t.Fatal(err)
+	}
+	if err = clnt.PutObjectLegalHold("ON"); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(requests) != 2 {
+		t.Fatalf("Expected 2 requests, got %d", len(requests))
+	}
+	for _, req := range requests {
+		sum := md5.Sum(req.body)
+		if req.contentMD5 != base64.StdEncoding.EncodeToString(sum[:]) {
+			t.Fatalf("Expected Content-Md5 of the %s body, got %q", req.query, req.contentMD5)
+		}
+	}
+
+	var retention struct {
+		XMLName         xml.Name `xml:"Retention"`
+		Mode            string
+		RetainUntilDate time.Time
+	}
+	if e := xml.Unmarshal(requests[0].body, &retention); e != nil {
+		t.Fatal(e)
+	}
+	if requests[0].query != "retention" || retention.Mode != "GOVERNANCE" || !retention.RetainUntilDate.Equal(retainUntil) {
+		t.Fatalf("Unexpected retention request %s: %s", requests[0].query, requests[0].body)
+	}
+
+	var legalHold struct {
+		XMLName xml.Name `xml:"LegalHold"`
+		Status  string
+	}
+	if e := xml.Unmarshal(requests[1].body, &legalHold); e != nil {
+		t.Fatal(e)
+	}
+	if requests[1].query != "legal-hold" || legalHold.Status != "ON" {
+		t.Fatalf("Unexpected legal hold request %s: %s", requests[1].query, requests[1].body)
+	}
+}
+
+func TestParseRetention(t *testing.T) {
+	if _, err := parseRetentionMode("forever"); err == nil {
+		t.Fatal("Expected invalid retention mode to fail")
+	}
+	if mode, err := parseRetentionMode("compliance"); err != nil || mode != "COMPLIANCE" {
+		t.Fatalf("Expected COMPLIANCE, got %s %v", mode, err)
+	}
+
+	testCases := []struct {
+		date     string
+		expected time.Time
+		success  bool
+	}{
+		{"2099-01-01", time.Date(2099, 1, 1, 0, 0, 0, 0, time.UTC), true},
+		{"2099-01-01T12:30:00+02:00", time.Date(2099, 1, 1, 10, 30, 0, 0, time.UTC), true},
+		{"2001-01-01", timeSentinel, false},
+		{"01/01/2099", timeSentinel, false},
+	}
+	for i, testCase := range testCases {
+		retainUntil, err := parseRetainUntil(testCase.date)
+		if testCase.success != (err == nil) {
+			t.Fatalf("Test %d: Expected success %v, got %v", i+1, testCase.success, err)
+		}
+		if !retainUntil.Equal(testCase.expected) {
+			t.Fatalf("Test %d: Expected %s, got %s", i+1, testCase.expected, retainUntil)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+/*
+ * MinIO Client (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	"github.com/minio/minio/pkg/console"
+)
+
+var retentionSetFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:  "mode",
+		Usage: "retention mode, one of [GOVERNANCE, COMPLIANCE]",
+	},
+	cli.StringFlag{
+		Name:  "retain-until",
+		Usage: "retain objects until this date, e.g. 2025-01-01 or 2025-01-01T00:00:00Z",
+	},
+}
+
+var retentionSetCmd = cli.Command{
+	Name:   "set",
+	Usage:  "set object retention until a given date",
+	Action: mainRetentionSet,
+	Before: setGlobalsFromContext,
+	Flags:  append(retentionSetFlags, globalFlags...),
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} --mode MODE --retain-until DATE TARGET
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+EXAMPLES:
+   1. Retain an object in governance mode until the first day of 2025.
+     $ {{.HelpName}} --mode GOVERNANCE --retain-until 2025-01-01 myminio/mybucket/myobject
+
+   2. Retain all objects with a given prefix in compliance mode.
+     $ {{.HelpName}} --mode COMPLIANCE --retain-until 2025-01-01T12:00:00Z myminio/mybucket/prefix
+`,
+}
+
+// mainRetentionSet is the handle for "mc retention set" command.
+func mainRetentionSet(ctx *cli.Context) error {
+	console.SetColor("RetentionSuccess", color.New(color.FgGreen, color.Bold))
+	console.SetColor("RetentionPartialFailure", color.New(color.FgRed, color.Bold))
+	console.SetColor("RetentionMessageFailure", color.New(color.FgYellow))
+
+	if len(ctx.Args()) != 1 || ctx.String("mode") == "" || ctx.String("retain-until") == "" {
+		cli.ShowCommandHelpAndExit(ctx, "set", 1) // last argument is exit code
+	}
+
+	mode, err := parseRetentionMode(ctx.String("mode"))
+	fatalIf(err, "Invalid retention mode. Valid options are `[GOVERNANCE, COMPLIANCE]`.")
+
+	retainUntil, err := parseRetainUntil(ctx.String("retain-until"))
+	fatalIf(err, "Invalid retain until date.")
+
+	return setRetention(ctx.Args().Get(0), mode, retainUntil, nil)
+}
@@ -27,6 +27,7 @@ import (
 	"github.com/minio/cli"
 	json "github.com/minio/mc/pkg/colorjson"
 	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/minio-go/v6"
 	"github.com/minio/minio/pkg/console"
 )
 
@@ -45,6 +46,10 @@ var (
 			Name:  "dangerous",
 			Usage: "allow site-wide removal of objects",
 		},
+		cli.BoolFlag{
+			Name:  "all",
+			Usage: "allow a recursive remove of all objects of a bucket, after confirming their count",
+		},
 		cli.BoolFlag{
 			Name:  "incomplete, I",
 			Usage: "remove incomplete uploads",
@@ -57,6 +62,18 @@ var (
 			Name:  "stdin",
 			Usage: "read object names from STDIN",
 		},
+		cli.BoolFlag{
+			Name:  "trash",
+			Usage: "move objects to the trash instead of deleting them, see 'mc trash'",
+		},
+		cli.BoolFlag{
+			Name:  "bypass-governance",
+			Usage: "remove objects retained in GOVERNANCE mode, requires the s3:BypassGovernanceRetention permission",
+		},
+		cli.StringFlag{
+			Name:  "from-file",
+			Usage: "remove the objects whose keys, relative to the target, are listed one per line in FILE",
+		},
 		cli.StringFlag{
 			Name:  "older-than",
 			Usage: "remove objects older than L days, M hours and N minutes",
@@ -101,7 +118,7 @@ EXAMPLES:
       {{.Prompt}} {{.HelpName}} --recursive --force --older-than 90d s3/jazz-songs/louis/
 
   05. Remove all objects newer than 7 days and 10 hours recursively from bucket 'pop-songs'
-      {{.Prompt}} {{.HelpName}} --recursive --force --newer-than 7d10h s3/pop-songs/
+      {{.Prompt}} {{.HelpName}} --recursive --force --all --newer-than 7d10h s3/pop-songs/
 
   06. Remove all objects read from STDIN.
       {{.Prompt}} {{.HelpName}} --force --stdin
@@ -117,6 +134,18 @@ EXAMPLES:
 
   10. Remove an encrypted object from Amazon S3 cloud storage.
       {{.Prompt}} {{.HelpName}} --encrypt-key "s3/sql-backups/=32byteslongsecretkeymustbegiven1" s3/sql-backups/1999/old-backup.tgz
+
+  11. Remove the objects of a bucket whose keys are listed in a file, skipping blank lines and '#' comments.
+      {{.Prompt}} {{.HelpName}} --force --from-file keys.txt s3/jazz-songs
+
+  12. Move old backups to the trash of the bucket, to be restored with 'mc trash restore' if needed.
+      {{.Prompt}} {{.HelpName}} --recursive --force --trash s3/sql-backups/1999/
+
+  13. Remove an object retained in GOVERNANCE mode before its retention expires.
+      {{.Prompt}} {{.HelpName}} --bypass-governance s3/locked-bucket/1999/old-backup.tgz
+
+  14. Remove all objects of bucket 'jazz-songs', confirming the count of objects removed first.
+      {{.Prompt}} {{.HelpName}} --recursive --force --all s3/jazz-songs
 `,
 }
 
@@ -165,6 +194,21 @@ func checkRmSyntax(ctx *cli.Context, encKeyDB map[string][]prefixSSEPair) {
 		cli.ShowCommandHelpAndExit(ctx, "rm", exitCode)
 	}
 
+	if ctx.Bool("trash") && (ctx.Bool("incomplete") || ctx.String("from-file") != "") {
+		fatalIf(errInvalidArgument().Trace(ctx.Args()...), "--trash cannot be used with --incomplete or --from-file.")
+	}
+
+	if ctx.String("from-file") != "" {
+		if len(ctx.Args()) != 1 || isRecursive || isStdin {
+			fatalIf(errInvalidArgument().Trace(ctx.Args()...),
+				"--from-file requires a single target and cannot be used with --recursive or --stdin.")
+		}
+		if !isForce {
+			fatalIf(errDummy().Trace(),
+				"Removal requires --force flag. This operation is *IRREVERSIBLE*. Please review carefully before performing this *DANGEROUS* operation.")
+		}
+	}
+
 	// For all recursive operations make sure to check for 'force' flag.
 	if (isRecursive || isStdin) && !isForce {
 		if isNamespaceRemoval {
@@ -178,9 +222,78 @@ func checkRmSyntax(ctx *cli.Context, encKeyDB map[string][]prefixSSEPair) {
 		fatalIf(errDummy().Trace(),
 			"This operation results in site-wide removal of objects. If you are really sure, retry this command with ‘--dangerous’ and ‘--force’ flags.")
 	}
+	// The answer to the confirmation is read from STDIN.
+	if ctx.Bool("all") && isStdin {
+		fatalIf(errInvalidArgument().Trace(ctx.Args()...), "--all cannot be used with --stdin.")
+	}
+	// No object is removed unless all targets pass the check.
+	if isRecursive && !ctx.Bool("incomplete") {
+		for _, url := range ctx.Args() {
+			fatalIf(checkRemoveAll(url, ctx.Bool("all")), "Unable to remove `"+url+"`.")
+		}
+	}
+}
+
+// isBucketRoot - returns if url is a bucket of object storage, not an
+// object or prefix of the bucket.
+func isBucketRoot(url string) bool {
+	clnt, err := newClient(url)
+	if err != nil {
+		return false
+	}
+	s3Clnt, ok := clnt.(*s3Client)
+	if !ok {
+		return false
+	}
+	bucket, object := s3Clnt.url2BucketAndObject()
+	return bucket != "" && object == ""
+}
+
+// checkRemoveAll - returns an error if url is the root of a bucket and
+// --all is not set. Recursive removals of prefixes are always allowed.
+func checkRemoveAll(url string, isAll bool) *probe.Error {
+	if !isAll && isBucketRoot(url) {
+		return errRemoveAllRequired(url)
+	}
+	return nil
 }
 
-func removeSingle(url string, isIncomplete bool, isFake, isForce bool, olderThan, newerThan string, encKeyDB map[string][]prefixSSEPair) error {
+// countRemoveRecursive - returns the count of objects removed
+// recursively from url, as removeRecursive lists them.
+func countRemoveRecursive(url string, isIncomplete bool, olderThan, newerThan string) (int64, *probe.Error) {
+	clnt, err := newClient(url)
+	if err != nil {
+		return 0, err.Trace(url)
+	}
+	var count int64
+	for content := range clnt.List(true, isIncomplete, false, DirNone) {
+		if content.Err != nil {
+			return 0, content.Err.Trace(url)
+		}
+		if content.Time.IsZero() || content.Type.IsDir() {
+			continue
+		}
+		if olderThan != "" && isOlder(content.Time, olderThan) {
+			continue
+		}
+		if newerThan != "" && isNewer(content.Time, newerThan) {
+			continue
+		}
+		count++
+	}
+	return count, nil
+}
+
+// confirmRemoveAll - asks the user to confirm the removal of count
+// objects of the bucket url.
+func confirmRemoveAll(url string, count int64) bool {
+	fmt.Fprintf(os.Stderr, "Remove all %d objects of bucket `%s`? This operation is *IRREVERSIBLE* [y/N]: ", count, url)
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
+
+func removeSingle(url string, isIncomplete bool, isFake, isForce, isTrash bool, olderThan, newerThan string, encKeyDB map[string][]prefixSSEPair) error {
 	isRecursive := false
 	contents, pErr := statURL(url, isIncomplete, isRecursive, encKeyDB)
 	if pErr != nil {
@@ -212,6 +325,14 @@ func removeSingle(url string, isIncomplete bool, isFake, isForce bool, olderThan
 		Size: content.Size,
 	})
 
+	if !isFake && isTrash {
+		if pErr := moveToTrash(url, content, encKeyDB); pErr != nil {
+			errorIf(pErr.Trace(url), "Failed to move `"+url+"` to the trash.")
+			return exitStatus(globalErrorExitStatus)
+		}
+		return nil
+	}
+
 	if !isFake {
 		targetAlias, targetURL, _ := mustExpandAlias(url)
 		clnt, pErr := newClientFromAlias(targetAlias, targetURL)
@@ -243,7 +364,7 @@ func removeSingle(url string, isIncomplete bool, isFake, isForce bool, olderThan
 	return nil
 }
 
-func removeRecursive(url string, isIncomplete bool, isFake bool, olderThan, newerThan string, encKeyDB map[string][]prefixSSEPair) error {
+func removeRecursive(url string, isIncomplete bool, isFake, isTrash bool, olderThan, newerThan string, encKeyDB map[string][]prefixSSEPair, failures *errorSummary) error {
 	targetAlias, targetURL, _ := mustExpandAlias(url)
 	clnt, pErr := newClientFromAlias(targetAlias, targetURL)
 	if pErr != nil {
@@ -255,10 +376,14 @@ func removeRecursive(url string, isIncomplete bool, isFake bool, olderThan, newe
 
 	errorCh := clnt.Remove(isIncomplete, isRemoveBucket, contentCh)
 
+	// Objects failing to move to the trash are left in place, the
+	// others are still moved.
+	trashFailed := false
 	isRecursive := true
 	for content := range clnt.List(isRecursive, isIncomplete, false, DirLast) {
 		if content.Err != nil {
 			errorIf(content.Err.Trace(url), "Failed to remove `"+url+"` recursively.")
+			failures.add(url, content.Err)
 			switch content.Err.ToGoError().(type) {
 			case PathInsufficientPermission:
 				// Ignore Permission error.
@@ -284,11 +409,25 @@ func removeRecursive(url string, isIncomplete bool, isFake bool, olderThan, newe
 			continue
 		}
 
+		// Objects in the trash stay there.
+		if isTrash && isTrashURL(targetAlias+urlString) {
+			continue
+		}
+
 		printMsg(rmMessage{
 			Key:  targetAlias + urlString,
 			Size: content.Size,
 		})
 
+		if !isFake && isTrash {
+			if pErr := moveToTrash(targetAlias+urlString, content, encKeyDB); pErr != nil {
+				errorIf(pErr.Trace(urlString), "Failed to move `"+urlString+"` to the trash.")
+				failures.add(targetAlias+urlString, pErr)
+				trashFailed = true
+			}
+			continue
+		}
+
 		if !isFake {
 			sent := false
 			for !sent {
@@ -297,6 +436,7 @@ func removeRecursive(url string, isIncomplete bool, isFake bool, olderThan, newe
 					sent = true
 				case pErr := <-errorCh:
 					errorIf(pErr.Trace(urlString), "Failed to remove `"+urlString+"`.")
+					failures.add(targetAlias+urlString, pErr)
 					switch pErr.ToGoError().(type) {
 					case PathInsufficientPermission:
 						// Ignore Permission error.
@@ -312,6 +452,7 @@ func removeRecursive(url string, isIncomplete bool, isFake bool, olderThan, newe
 	close(contentCh)
 	for pErr := range errorCh {
 		errorIf(pErr.Trace(url), "Failed to remove `"+url+"` recursively.")
+		failures.add(url, pErr)
 		switch pErr.ToGoError().(type) {
 		case PathInsufficientPermission:
 			// Ignore Permission error.
@@ -320,9 +461,95 @@ func removeRecursive(url string, isIncomplete bool, isFake bool, olderThan, newe
 		return exitStatus(globalErrorExitStatus)
 	}
 
+	if trashFailed {
+		return exitStatus(globalErrorExitStatus)
+	}
 	return nil
 }
 
+// readKeysFile - returns the keys listed in keysFile, one per line. Lines
+// are trimmed, blank lines and lines starting with '#' are skipped.
+func readKeysFile(keysFile string) ([]string, *probe.Error) {
+	file, e := os.Open(keysFile)
+	if e != nil {
+		return nil, probe.NewError(e)
+	}
+	defer file.Close()
+
+	var keys []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		key := strings.TrimSpace(scanner.Text())
+		if key == "" || strings.HasPrefix(key, "#") {
+			continue
+		}
+		keys = append(keys, key)
+	}
+	if e = scanner.Err(); e != nil {
+		return nil, probe.NewError(e)
+	}
+	return keys, nil
+}
+
+// removeFromFile - removes the objects of url listed in keysFile with
+// batched multi-object deletes. Failures are reported per key and do not
+// stop the removal of the other keys.
+func removeFromFile(keysFile, url string, isIncomplete, isFake bool) error {
+	keys, pErr := readKeysFile(keysFile)
+	if pErr != nil {
+		errorIf(pErr.Trace(keysFile), "Unable to read keys from `"+keysFile+"`.")
+		return exitStatus(globalErrorExitStatus)
+	}
+
+	targetAlias, targetURL, _ := mustExpandAlias(url)
+	clnt, pErr := newClientFromAlias(targetAlias, targetURL)
+	if pErr != nil {
+		errorIf(pErr.Trace(url), "Invalid argument `"+url+"`.")
+		return exitStatus(globalErrorExitStatus)
+	}
+	separator := string(clnt.GetURL().Separator)
+	if !strings.HasSuffix(targetURL, separator) {
+		targetURL = targetURL + separator
+	}
+	if !strings.HasSuffix(url, separator) {
+		url = url + separator
+	}
+
+	var rerr error
+	reportErr := func(pErr *probe.Error) {
+		failed := url
+		if errResponse, ok := pErr.ToGoError().(minio.ErrorResponse); ok && errResponse.Key != "" {
+			failed = errResponse.Key
+		}
+		errorIf(pErr.Trace(failed), "Failed to remove `"+failed+"`.")
+		rerr = exitStatus(globalErrorExitStatus)
+	}
+
+	contentCh := make(chan *clientContent)
+	isRemoveBucket := false
+	errorCh := clnt.Remove(isIncomplete, isRemoveBucket, contentCh)
+	for _, key := range keys {
+		printMsg(rmMessage{Key: url + key})
+		if isFake {
+			continue
+		}
+		content := &clientContent{URL: *newClientURL(targetURL + key)}
+		for sent := false; !sent; {
+			select {
+			case contentCh <- content:
+				sent = true
+			case pErr := <-errorCh:
+				reportErr(pErr)
+			}
+		}
+	}
+	close(contentCh)
+	for pErr := range errorCh {
+		reportErr(pErr)
+	}
+	return rerr
+}
+
 // main for rm command.
 func mainRm(ctx *cli.Context) error {
 	// Parse encryption keys per command.
@@ -340,42 +567,64 @@ func mainRm(ctx *cli.Context) error {
 	olderThan := ctx.String("older-than")
 	newerThan := ctx.String("newer-than")
 	isForce := ctx.Bool("force")
+	isTrash := ctx.Bool("trash")
+	isAll := ctx.Bool("all")
+	globalBypassGovernance = ctx.Bool("bypass-governance")
 
 	// Set color.
 	console.SetColor("Remove", color.New(color.FgGreen, color.Bold))
 
-	var rerr error
-	var e error
-	// Support multiple targets.
-	for _, url := range ctx.Args() {
-		if isRecursive {
-			e = removeRecursive(url, isIncomplete, isFake, olderThan, newerThan, encKeyDB)
-		} else {
-			e = removeSingle(url, isIncomplete, isFake, isForce, olderThan, newerThan, encKeyDB)
-		}
+	if keysFile := ctx.String("from-file"); keysFile != "" {
+		return removeFromFile(keysFile, ctx.Args().Get(0), isIncomplete, isFake)
+	}
 
-		if rerr == nil {
-			rerr = e
+	var failures *errorSummary
+	if isRecursive {
+		failures = newErrorSummary()
+		defer failures.print()
+	}
+
+	remove := func(url string) error {
+		if !isRecursive {
+			return removeSingle(url, isIncomplete, isFake, isForce, isTrash, olderThan, newerThan, encKeyDB)
+		}
+		// Whole buckets are removed with --all only, once the count of
+		// their objects is confirmed.
+		if !isIncomplete && isBucketRoot(url) {
+			if pErr := checkRemoveAll(url, isAll); pErr != nil {
+				errorIf(pErr.Trace(url), "Unable to remove `"+url+"`.")
+				return exitStatus(globalErrorExitStatus)
+			}
+			if !isFake {
+				count, pErr := countRemoveRecursive(url, isIncomplete, olderThan, newerThan)
+				if pErr != nil {
+					errorIf(pErr.Trace(url), "Failed to remove `"+url+"` recursively.")
+					return exitStatus(globalErrorExitStatus)
+				}
+				if !confirmRemoveAll(url, count) {
+					console.Infoln("Removal of `" + url + "` cancelled.")
+					return nil
+				}
+			}
 		}
+		return removeRecursive(url, isIncomplete, isFake, isTrash, olderThan, newerThan, encKeyDB, failures)
+	}
+
+	var result batchResult
+	// Support multiple targets.
+	for _, url := range ctx.Args() {
+		result.record(remove(url))
 	}
 
 	if !isStdin {
-		return rerr
+		return result.exit()
 	}
 
 	scanner := bufio.NewScanner(os.Stdin)
 	for scanner.Scan() {
 		url := scanner.Text()
-		if isRecursive {
-			e = removeRecursive(url, isIncomplete, isFake, olderThan, newerThan, encKeyDB)
-		} else {
-			e = removeSingle(url, isIncomplete, isFake, isForce, olderThan, newerThan, encKeyDB)
-		}
-
-		if rerr == nil {
-			rerr = e
-		}
+		result.record(remove(url))
 	}
 
-	return rerr
+	return result.exit()
 }
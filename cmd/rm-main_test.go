@@ -0,0 +1,351 @@
+/*
+ * MinIO Client (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"flag"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/probe"
+)
+
+// multiDeleteHandler answers multi-object deletes, failing the keys in
+// locked and recording the others as deleted.
+type multiDeleteHandler struct {
+	mutex   *sync.Mutex
+	locked  map[string]bool
+	deleted *[]string
+}
+
+func (h multiDeleteHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if _, ok := r.URL.Query()["location"]; ok {
+		w.Write([]byte(`<LocationConstraint xmlns="http://s3.amazonaws.com/doc/2006-03-01/"></LocationConstraint>`))
+		return
+	}
+	if _, ok := r.URL.Query()["delete"]; !ok || r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusNotImplemented)
+		return
+	}
+	var request struct {
+		Objects []struct {
+			Key string
+		} `xml:"Object"`
+	}
+	if e := xml.NewDecoder(r.Body).Decode(&request); e != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	result := "<DeleteResult>"
+	for _, object := range request.Objects {
+		if h.locked[object.Key] {
+			result += "<Error><Key>" + object.Key + "</Key><Code>AccessDenied</Code><Message>Access Denied</Message></Error>"
+			continue
+		}
+		h.mutex.Lock()
+		*h.deleted = append(*h.deleted, object.Key)
+		h.mutex.Unlock()
+		result += "<Deleted><Key>" + object.Key + "</Key></Deleted>"
+	}
+	w.Write([]byte(result + "</DeleteResult>"))
+}
+
+func TestRemoveFromFile(t *testing.T) {
+	var deleted []string
+	server := httptest.NewServer(multiDeleteHandler{
+		mutex:   &sync.Mutex{},
+		locked:  map[string]bool{"dir/locked": true},
+		deleted: &deleted,
+	})
+	defer server.Close()
+
+	defer withTestConfig(map[string]hostConfigV9{"myminio": testHost(server.URL)})()
+
+	dir, e := ioutil.TempDir("", "mc-rm-")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.RemoveAll(dir)
+	keysFile := filepath.Join(dir, "keys.txt")
+	manifest := "# stale uploads\n  dir/object1  \n\ndir/locked\n# done\ndir/object2\n"
+	if e = ioutil.WriteFile(keysFile, []byte(manifest), 0600); e != nil {
+		t.Fatal(e)
+	}
+
+	savedOutput := color.Output
+	defer func() { color.Output = savedOutput }()
+	var buf strings.Builder
+	color.Output = &buf
+
+	// A fake removal only prints the keys.
+	if e = removeFromFile(keysFile, "myminio/bucket", false, true); e != nil {
+		t.Fatal(e)
+	}
+	if len(deleted) != 0 {
+		t.Fatalf("Expected a fake removal to delete nothing, got %v", deleted)
+	}
+	if !strings.Contains(buf.String(), "myminio/bucket/dir/object2") {
+		t.Fatalf("Expected the keys to be printed, got %s", buf.String())
+	}
+
+	// Failing keys fail the removal, the others are still deleted.
+	if e = removeFromFile(keysFile, "myminio/bucket", false, false); e == nil {
+		t.Fatal("Expected the removal of a locked key to fail")
+	}
+	if expected := []string{"dir/object1", "dir/object2"}; !reflect.DeepEqual(deleted, expected) {
+		t.Fatalf("Expected %v to be deleted, got %v", expected, deleted)
+	}
+
+	// Keys of a file without failing keys are all deleted.
+	deleted = nil
+	if e = ioutil.WriteFile(keysFile, []byte("dir/object1\n"), 0600); e != nil {
+		t.Fatal(e)
+	}
+	if e = removeFromFile(keysFile, "myminio/bucket/", false, false); e != nil {
+		t.Fatal(e)
+	}
+	if len(deleted) != 1 || deleted[0] != "dir/object1" {
+		t.Fatalf("Expected dir/object1 to be deleted, got %v", deleted)
+	}
+}
+
+// governanceHandler denies deletes of objects retained in GOVERNANCE
+// mode unless the retention is bypassed, recording the bypass header
+// of every delete.
+type governanceHandler struct {
+	mutex    *sync.Mutex
+	bypassed map[string]bool
+}
+
+func (h governanceHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	if _, ok := r.URL.Query()["location"]; ok {
+		w.Write([]byte(`<LocationConstraint xmlns="http://s3.amazonaws.com/doc/2006-03-01/"></LocationConstraint>`))
+		return
+	}
+	bypass := strings.EqualFold(r.Header.Get("X-Amz-Bypass-Governance-Retention"), "true")
+	locked := "<Code>AccessDenied</Code><Message>Access Denied because object protected by object lock.</Message>"
+	switch {
+	case r.Method == http.MethodPost:
+		var request struct {
+			Objects []struct {
+				Key string
+			} `xml:"Object"`
+		}
+		xml.NewDecoder(r.Body).Decode(&request)
+		result := "<DeleteResult>"
+		for _, object := range request.Objects {
+			h.bypassed[object.Key] = bypass
+			if !bypass {
+				result += "<Error><Key>" + object.Key + "</Key>" + locked + "</Error>"
+			}
+		}
+		w.Write([]byte(result + "</DeleteResult>"))
+	case r.Method == http.MethodDelete:
+		key := strings.TrimPrefix(r.URL.Path, "/bucket/")
+		h.bypassed[key] = bypass
+		if !bypass {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte("<Error>" + locked + "</Error>"))
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusNotImplemented)
+	}
+}
+
+func TestRemoveBypassGovernance(t *testing.T) {
+	handler := governanceHandler{mutex: &sync.Mutex{}, bypassed: make(map[string]bool)}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+	defer withTestConfig(map[string]hostConfigV9{"myminio": testHost(server.URL)})()
+	defer func(bypass bool) { globalBypassGovernance = bypass }(globalBypassGovernance)
+
+	remove := func(key string) []*probe.Error {
+		clnt, err := newClient("myminio/bucket")
+		if err != nil {
+			t.Fatal(err)
+		}
+		contentCh := make(chan *clientContent, 1)
+		contentCh <- &clientContent{URL: *newClientURL("/bucket/" + key)}
+		close(contentCh)
+		isIncomplete, isRemoveBucket := false, false
+		var errs []*probe.Error
+		for err := range clnt.Remove(isIncomplete, isRemoveBucket, contentCh) {
+			errs = append(errs, err)
+		}
+		return errs
+	}
+
+	// Without the flag the lock is reported as such.
+	globalBypassGovernance = false
+	errs := remove("retained")
+	if len(errs) != 1 {
+		t.Fatalf("Expected the removal of a retained object to fail, got %v", errs)
+	}
+	if _, ok := errs[0].ToGoError().(ObjectLocked); !ok {
+		t.Fatalf("Expected an object locked error, got %v", errs[0].ToGoError())
+	}
+	if bypassed, ok := handler.bypassed["retained"]; !ok || bypassed {
+		t.Fatalf("Expected a delete without the bypass header, got %v", handler.bypassed)
+	}
+
+	globalBypassGovernance = true
+	if errs = remove("governed"); len(errs) != 0 {
+		t.Fatalf("Expected the retention to be bypassed, got %v", errs)
+	}
+	if !handler.bypassed["governed"] {
+		t.Fatalf("Expected a delete with the bypass header, got %v", handler.bypassed)
+	}
+}
+
+func TestRemoveAllGuard(t *testing.T) {
+	savedQuiet, savedOutput := globalQuiet, color.Output
+	defer func() {
+		globalQuiet, color.Output = savedQuiet, savedOutput
+	}()
+	globalQuiet, color.Output = true, ioutil.Discard
+
+	handler := newFakeS3Server(map[string][]byte{
+		"bucket/a":        []byte("data"),
+		"bucket/prefix/b": []byte("data"),
+		"bucket/prefix/c": []byte("data"),
+	})
+	server := httptest.NewServer(handler)
+	defer server.Close()
+	defer withTestConfig(map[string]hostConfigV9{"myminio": testHost(server.URL)})()
+
+	dir, e := ioutil.TempDir("", "mc-rm-all-")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.RemoveAll(dir)
+
+	testCases := []struct {
+		url     string
+		isAll   bool
+		success bool
+	}{
+		// The root of a bucket requires --all.
+		{"myminio/bucket", false, false},
+		{"myminio/bucket/", false, false},
+		{"myminio/bucket", true, true},
+		// Prefixes and local folders do not.
+		{"myminio/bucket/prefix/", false, true},
+		{"myminio/bucket/prefix", false, true},
+		{dir, false, true},
+	}
+	for i, testCase := range testCases {
+		err := checkRemoveAll(testCase.url, testCase.isAll)
+		if testCase.success != (err == nil) {
+			t.Fatalf("Test %d: expected success %t for %s, got %v", i+1, testCase.success, testCase.url, err)
+		}
+		if err != nil && !strings.Contains(err.ToGoError().Error(), "--all") {
+			t.Fatalf("Test %d: expected the error to mention --all, got %v", i+1, err)
+		}
+	}
+
+	// The confirmation shows the count of all objects of the bucket.
+	count, err := countRemoveRecursive("myminio/bucket", false, "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 3 {
+		t.Fatalf("Expected 3 objects to be removed, got %d", count)
+	}
+
+	if e := removeRecursive("myminio/bucket/prefix/", false, false, false, "", "", nil, nil); e != nil {
+		t.Fatal(e)
+	}
+	if expected := map[string][]byte{"bucket/a": []byte("data")}; !reflect.DeepEqual(handler.objects, expected) {
+		t.Fatalf("Expected only the objects of the prefix to be removed, got %v", handler.objects)
+	}
+}
+
+func TestRemoveExitStatus(t *testing.T) {
+	savedJSON, savedOutput := globalJSON, color.Output
+	defer func() {
+		globalJSON, color.Output = savedJSON, savedOutput
+	}()
+	globalJSON = true
+	defer withTestConfig(nil)()
+
+	dir, e := ioutil.TempDir("", "mc-rm-batch-")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.RemoveAll(dir)
+	var targets []string
+	for _, name := range []string{"a", "missing", "b"} {
+		target := filepath.Join(dir, name)
+		if name != "missing" {
+			if e = ioutil.WriteFile(target, []byte("data"), 0600); e != nil {
+				t.Fatal(e)
+			}
+		}
+		targets = append(targets, target)
+	}
+
+	set := flag.NewFlagSet(rmCmd.Name, flag.ContinueOnError)
+	for _, f := range rmCmd.Flags {
+		f.Apply(set)
+	}
+	if e = set.Parse(targets); e != nil {
+		t.Fatal(e)
+	}
+	var buf bytes.Buffer
+	color.Output = &buf
+	e = mainRm(cli.NewContext(nil, set, nil))
+	if exitErr, ok := e.(cli.ExitCoder); !ok || exitErr.ExitCode() != globalErrorExitStatus {
+		t.Fatalf("Expected exit status %d, got %v", globalErrorExitStatus, e)
+	}
+
+	// The target which failed does not stop the removal of the others.
+	for _, target := range targets {
+		if _, e = os.Stat(target); !os.IsNotExist(e) {
+			t.Fatalf("Expected %s to be removed, got %v", target, e)
+		}
+	}
+	var summary batchResultMessage
+	for decoder := json.NewDecoder(&buf); ; {
+		var msg batchResultMessage
+		if e = decoder.Decode(&msg); e == io.EOF {
+			break
+		} else if e != nil {
+			t.Fatal(e)
+		}
+		summary = msg
+	}
+	if expected := (batchResultMessage{Status: "error", Failed: 1, Total: 3}); summary != expected {
+		t.Fatalf("Expected summary %+v, got %+v", expected, summary)
+	}
+}
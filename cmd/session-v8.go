@@ -251,6 +251,7 @@ func (s *sessionV8) setGlobals() {
 	s.Header.GlobalBoolFlags["json"] = globalJSON
 	s.Header.GlobalBoolFlags["noColor"] = globalNoColor
 	s.Header.GlobalBoolFlags["insecure"] = globalInsecure
+	s.Header.GlobalBoolFlags["stats"] = globalStats
 }
 
 // IsModified - returns if in memory session header has changed from
@@ -76,7 +76,7 @@ func (s *shareDBV1) Delete(objectURL string) {
 // Delete all expired uploads.
 func (s *shareDBV1) deleteAllExpired() {
 	for shareURL, share := range s.Shares {
-		if (share.Expiry - time.Since(share.Date)) <= 0 {
+		if (share.Expiry - UTCNow().Sub(share.Date)) <= 0 {
 			// Expired entry. Safe to drop.
 			delete(s.Shares, shareURL)
 		}
@@ -82,12 +82,7 @@ func checkShareDownloadSyntax(ctx *cli.Context, encKeyDB map[string][]prefixSSEP
 	}
 
 	// Validate expiry.
-	if expiry.Seconds() < 1 {
-		fatalIf(errDummy().Trace(expiry.String()), "Expiry cannot be lesser than 1 second.")
-	}
-	if expiry.Seconds() > 604800 {
-		fatalIf(errDummy().Trace(expiry.String()), "Expiry cannot be larger than 7 days.")
-	}
+	checkShareExpiry(ctx, expiry)
 
 	// Validate if object exists only if the `--recursive` flag was NOT specified
 	isRecursive := ctx.Bool("recursive")
@@ -18,7 +18,6 @@ package cmd
 
 import (
 	"fmt"
-	"time"
 
 	"github.com/minio/cli"
 	"github.com/minio/mc/pkg/probe"
@@ -94,7 +93,7 @@ func doShareList(cmd string) *probe.Error {
 		printMsg(shareMesssage{
 			ObjectURL:   share.URL,
 			ShareURL:    shareURL,
-			TimeLeft:    share.Expiry - time.Since(share.Date),
+			TimeLeft:    share.Expiry - UTCNow().Sub(share.Date),
 			ContentType: share.ContentType,
 		})
 	}
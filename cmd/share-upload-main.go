@@ -87,14 +87,7 @@ func checkShareUploadSyntax(ctx *cli.Context) {
 	}
 
 	// Validate expiry.
-	if expiry.Seconds() < 1 {
-		fatalIf(errDummy().Trace(expiry.String()),
-			"Expiry cannot be lesser than 1 second.")
-	}
-	if expiry.Seconds() > 604800 {
-		fatalIf(errDummy().Trace(expiry.String()),
-			"Expiry cannot be larger than 7 days.")
-	}
+	checkShareExpiry(ctx, expiry)
 
 	for _, targetURL := range ctx.Args() {
 		url := newClientURL(targetURL)
@@ -34,6 +34,13 @@ import (
 const (
 	// Default expiry is 7 days (168h).
 	shareDefaultExpiry = time.Duration(604800) * time.Second
+
+	// Maximum expiry of presigned URLs and upload policies, the same
+	// for both signature versions.
+	shareMaxExpiry = 7 * 24 * time.Hour
+
+	// Expiries set closer to the maximum are warned about.
+	shareExpiryWarnMargin = time.Hour
 )
 
 // Upload specific flags.
@@ -201,3 +208,19 @@ func initShareConfig() {
 		}
 	}
 }
+
+// checkShareExpiry - verifies the expiry of the URLs to share, warning
+// about expiries set close to the maximum. Servers reject URLs signed
+// by a clock behind their own before the expiry, unless the offset of
+// the clock was learnt from an earlier request.
+func checkShareExpiry(ctx *cli.Context, expiry time.Duration) {
+	if expiry.Seconds() < 1 {
+		fatalIf(errDummy().Trace(expiry.String()), "Expiry cannot be lesser than 1 second.")
+	}
+	if expiry > shareMaxExpiry {
+		fatalIf(errDummy().Trace(expiry.String()), "Expiry cannot be larger than 7 days.")
+	}
+	if ctx.IsSet("expire") && expiry > shareMaxExpiry-shareExpiryWarnMargin {
+		errorIf(errInvalidArgument().Trace(expiry.String()), "Expiry is close to the maximum of 7 days, the URLs may expire sooner if the local clock is off.")
+	}
+}
@@ -23,24 +23,41 @@ import (
 
 // signalTrap traps the registered signals and notifies the caller.
 func signalTrap(sig ...os.Signal) <-chan bool {
+	interrupt := false
+	for _, s := range sig {
+		interrupt = interrupt || s == os.Interrupt
+	}
+
 	// channel to notify the caller.
 	trapCh := make(chan bool, 1)
 
+	// channel to receive signals.
+	sigCh := make(chan os.Signal, 1)
+
+	// `signal.Notify` registers the given channel to receive
+	// notifications of the specified signals, before the piped
+	// output stops receiving them.
+	signal.Notify(sigCh, sig...)
+	if interrupt {
+		// The caller exits by itself when interrupted.
+		holdPipedInterrupts()
+	}
+
 	go func(chan<- bool) {
-		// channel to receive signals.
-		sigCh := make(chan os.Signal, 1)
 		defer close(sigCh)
 
-		// `signal.Notify` registers the given channel to
-		// receive notifications of the specified signals.
-		signal.Notify(sigCh, sig...)
-
 		// Wait for the signal.
 		<-sigCh
 
 		// Once signal has been received stop signal Notify handler.
+		if interrupt {
+			releasePipedInterrupts()
+		}
 		signal.Stop(sigCh)
 
+		// The caller usually exits, with the output written.
+		flushOutput()
+
 		// Notify the caller.
 		trapCh <- true
 	}(trapCh)
@@ -0,0 +1,91 @@
+/*
+ * MinIO Client (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/dustin/go-humanize"
+	"github.com/minio/mc/pkg/probe"
+)
+
+// Units of the sizes accepted by parseSize, in lower case. Metric
+// units such as KB are powers of 1000, IEC units such as KiB powers of
+// 1024. The trailing "b" is optional.
+var sizeUnits = map[string]uint64{
+	"":  humanize.Byte,
+	"b": humanize.Byte,
+	"k": humanize.KByte, "kb": humanize.KByte, "ki": humanize.KiByte, "kib": humanize.KiByte,
+	"m": humanize.MByte, "mb": humanize.MByte, "mi": humanize.MiByte, "mib": humanize.MiByte,
+	"g": humanize.GByte, "gb": humanize.GByte, "gi": humanize.GiByte, "gib": humanize.GiByte,
+	"t": humanize.TByte, "tb": humanize.TByte, "ti": humanize.TiByte, "tib": humanize.TiByte,
+	"p": humanize.PByte, "pb": humanize.PByte, "pi": humanize.PiByte, "pib": humanize.PiByte,
+}
+
+// parseSize parses a human readable size such as `64MiB`, `1.5GB` or
+// `4096`, the unit is case-insensitive and defaults to bytes.
+func parseSize(size string) (int64, *probe.Error) {
+	s := strings.TrimSpace(size)
+	if strings.HasPrefix(s, "-") {
+		return 0, errInvalidSize(size, "a size cannot be negative")
+	}
+	number := s
+	if i := strings.IndexFunc(s, func(r rune) bool { return (r < '0' || r > '9') && r != '.' }); i >= 0 {
+		number = s[:i]
+	}
+	if number == "" || strings.Count(number, ".") > 1 || number == "." {
+		return 0, errInvalidSize(size, "expected a number followed by a unit such as KiB, MiB, GiB, KB, MB or GB")
+	}
+	unit := strings.ToLower(strings.TrimSpace(s[len(number):]))
+	multiplier, ok := sizeUnits[unit]
+	if !ok {
+		return 0, errInvalidSize(size, "unknown unit `"+s[len(number):]+"`, expected one of KiB, MiB, GiB, TiB, KB, MB, GB or TB")
+	}
+
+	if !strings.Contains(number, ".") {
+		n, e := strconv.ParseUint(number, 10, 64)
+		if e != nil || n > math.MaxInt64/multiplier {
+			return 0, errInvalidSize(size, "a size cannot exceed "+humanizeSize(math.MaxInt64))
+		}
+		return int64(n * multiplier), nil
+	}
+	f, e := strconv.ParseFloat(number, 64)
+	if e != nil {
+		return 0, errInvalidSize(size, e.Error())
+	}
+	f = math.Round(f * float64(multiplier))
+	if f >= math.MaxInt64 {
+		return 0, errInvalidSize(size, "a size cannot exceed "+humanizeSize(math.MaxInt64))
+	}
+	return int64(f), nil
+}
+
+// humanizeSize formats a size in bytes with IEC units, such as `1.5 MiB`.
+func humanizeSize(size int64) string {
+	if size < 0 {
+		return "-" + humanize.IBytes(uint64(-size))
+	}
+	return humanize.IBytes(uint64(size))
+}
+
+// humanizeSizeColumn formats a size like humanizeSize without the space
+// between the number and the unit, for columns of sizes.
+func humanizeSizeColumn(size int64) string {
+	return strings.Replace(humanizeSize(size), " ", "", 1)
+}
@@ -0,0 +1,104 @@
+/*
+ * MinIO Client (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"testing"
+
+	humanize "github.com/dustin/go-humanize"
+)
+
+func TestParseSize(t *testing.T) {
+	testCases := []struct {
+		size     string
+		expected int64
+		success  bool
+	}{
+		{"0", 0, true},
+		{"4096", 4096, true},
+		{"4096B", 4096, true},
+		{"1KiB", humanize.KiByte, true},
+		{"1KB", humanize.KByte, true},
+		{"1k", humanize.KByte, true},
+		{"64MiB", 64 * humanize.MiByte, true},
+		{"64mib", 64 * humanize.MiByte, true},
+		{"64MB", 64 * humanize.MByte, true},
+		{"64 MiB", 64 * humanize.MiByte, true},
+		{"1.5GiB", 3 * humanize.GiByte / 2, true},
+		{"1.5GB", 3 * humanize.GByte / 2, true},
+		{"5TiB", 5 * humanize.TiByte, true},
+		{"5TB", 5 * humanize.TByte, true},
+		{"2PiB", 2 * humanize.PiByte, true},
+		{"", 0, false},
+		{"MiB", 0, false},
+		{"-1", 0, false},
+		{"-64MiB", 0, false},
+		{"64XB", 0, false},
+		{"64MiBs", 0, false},
+		{"1.2.3MB", 0, false},
+		{".", 0, false},
+		{"9223372036854775808", 0, false},
+		{"100000PiB", 0, false},
+	}
+	for i, testCase := range testCases {
+		size, err := parseSize(testCase.size)
+		if testCase.success != (err == nil) {
+			t.Fatalf("Test %d: expected success %t for %q, got %v", i+1, testCase.success, testCase.size, err)
+		}
+		if size != testCase.expected {
+			t.Errorf("Test %d: expected %d for %q, got %d", i+1, testCase.expected, testCase.size, size)
+		}
+	}
+}
+
+func TestHumanizeSize(t *testing.T) {
+	testCases := []struct {
+		size     int64
+		expected string
+		column   string
+	}{
+		{0, "0 B", "0B"},
+		{1023, "1023 B", "1023B"},
+		{humanize.KiByte, "1.0 KiB", "1.0KiB"},
+		{3 * humanize.MiByte / 2, "1.5 MiB", "1.5MiB"},
+		{64 * humanize.MiByte, "64 MiB", "64MiB"},
+		{5 * humanize.GiByte, "5.0 GiB", "5.0GiB"},
+		{-humanize.KiByte, "-1.0 KiB", "-1.0KiB"},
+	}
+	for i, testCase := range testCases {
+		if s := humanizeSize(testCase.size); s != testCase.expected {
+			t.Errorf("Test %d: expected %q, got %q", i+1, testCase.expected, s)
+		}
+		if s := humanizeSizeColumn(testCase.size); s != testCase.column {
+			t.Errorf("Test %d: expected %q, got %q", i+1, testCase.column, s)
+		}
+	}
+}
+
+func TestSizeRoundTrip(t *testing.T) {
+	for i, size := range []int64{0, 512, humanize.KiByte, 3 * humanize.MiByte / 2, 64 * humanize.MiByte, 5 * humanize.GiByte, 7 * humanize.TiByte} {
+		for _, s := range []string{humanizeSize(size), humanizeSizeColumn(size)} {
+			parsed, err := parseSize(s)
+			if err != nil {
+				t.Fatalf("Test %d: unable to parse %q: %v", i+1, s, err)
+			}
+			if parsed != size {
+				t.Errorf("Test %d: expected %d after parsing %q, got %d", i+1, size, s, parsed)
+			}
+		}
+	}
+}
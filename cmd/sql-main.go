@@ -58,6 +58,18 @@ var (
 			Name:  "compression",
 			Usage: "input compression type",
 		},
+		cli.StringFlag{
+			Name:  "csv-header",
+			Usage: "header line of csv input, one of [USE, IGNORE, NONE]",
+		},
+		cli.BoolFlag{
+			Name:  "json-lines",
+			Usage: "read the input as json lines, one json document per line",
+		},
+		cli.BoolFlag{
+			Name:  "input-gzip",
+			Usage: "read the input as gzip compressed",
+		},
 		cli.StringFlag{
 			Name:  "csv-output",
 			Usage: "csv output serialization option",
@@ -121,6 +133,13 @@ EXAMPLES:
      {{.Prompt}} {{.HelpName}} --compression GZIP --csv-input "rd=\n,fh=USE,fd=;" \
            --csv-output "rd=\n" --csv-output-header "device_id,uptime,lat,lon" \
            --query "select * from S3Object" myminio/iot-devices/data.csv
+
+  7. Run a query on a gzip compressed csv object of any name, using the names of its header as columns.
+     {{.Prompt}} {{.HelpName}} --csv-header USE --input-gzip \
+           --query "select * from S3Object where age > 30" s3/bucket/data
+
+  8. Run a query on an object of json lines.
+     {{.Prompt}} {{.HelpName}} --json-lines --query "select s.name from S3Object s" s3/bucket/users.log
 `,
 }
 
@@ -264,6 +283,30 @@ func getInputSerializationOpts(ctx *cli.Context) map[string]map[string]string {
 		m["json"] = kv
 	}
 
+	// Shorthands of the most common serialization options.
+	if ctx.IsSet("csv-header") {
+		if jsonType || ctx.Bool("json-lines") {
+			fatalIf(errInvalidArgument(), "--csv-header cannot be used with json input")
+		}
+		hdr := strings.ToUpper(ctx.String("csv-header"))
+		if hdr != "USE" && hdr != "IGNORE" && hdr != "NONE" {
+			fatalIf(errInvalidArgument().Trace(hdr), "--csv-header must be one of [USE, IGNORE, NONE]")
+		}
+		if m["csv"] == nil {
+			m["csv"] = make(map[string]string)
+		}
+		m["csv"][fileHeaderType] = hdr
+	}
+	if ctx.Bool("json-lines") {
+		if csvType {
+			fatalIf(errInvalidArgument(), "--json-lines cannot be used with --csv-input")
+		}
+		if m["json"] == nil {
+			m["json"] = make(map[string]string)
+		}
+		m["json"][typeJSONType] = string(minio.JSONLinesType)
+	}
+
 	return m
 }
 
@@ -363,10 +406,18 @@ func getSQLOpts(ctx *cli.Context, csvHdrs []string) (s SelectObjectOpts) {
 	is := getInputSerializationOpts(ctx)
 	os := getOutputSerializationOpts(ctx, csvHdrs)
 
+	compression := minio.SelectCompressionType(ctx.String("compression"))
+	if ctx.Bool("input-gzip") {
+		if compression != "" && !strings.EqualFold(string(compression), string(minio.SelectCompressionGZIP)) {
+			fatalIf(errInvalidArgument(), "--input-gzip cannot be used with --compression "+string(compression))
+		}
+		compression = minio.SelectCompressionGZIP
+	}
+
 	return SelectObjectOpts{
 		InputSerOpts:    is,
 		OutputSerOpts:   os,
-		CompressionType: minio.SelectCompressionType(ctx.String("compression")),
+		CompressionType: compression,
 	}
 }
 
@@ -17,8 +17,18 @@
 package cmd
 
 import (
+	"bytes"
+	"encoding/binary"
+	"flag"
+	"hash/crc32"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
+
+	"github.com/minio/cli"
 )
 
 var testParseKVArgsCases = []struct {
@@ -138,3 +148,109 @@ func TestParseSerializationOpts(t *testing.T) {
 		}
 	}
 }
+
+// writeSelectEvent - writes a message of the event stream of S3 Select
+// to w, all headers are strings.
+func writeSelectEvent(w io.Writer, eventType, contentType, payload string) {
+	var headers bytes.Buffer
+	for _, h := range [][2]string{{":event-type", eventType}, {":content-type", contentType}, {":message-type", "event"}} {
+		if h[1] == "" {
+			continue
+		}
+		headers.WriteByte(byte(len(h[0])))
+		headers.WriteString(h[0])
+		headers.WriteByte(7)
+		binary.Write(&headers, binary.BigEndian, uint16(len(h[1])))
+		headers.WriteString(h[1])
+	}
+	var msg bytes.Buffer
+	binary.Write(&msg, binary.BigEndian, uint32(16+headers.Len()+len(payload)))
+	binary.Write(&msg, binary.BigEndian, uint32(headers.Len()))
+	binary.Write(&msg, binary.BigEndian, crc32.ChecksumIEEE(msg.Bytes()))
+	msg.Write(headers.Bytes())
+	msg.WriteString(payload)
+	binary.Write(&msg, binary.BigEndian, crc32.ChecksumIEEE(msg.Bytes()))
+	w.Write(msg.Bytes())
+}
+
+// selectHandler - answers S3 Select requests with a recorded event
+// stream and keeps the last request.
+type selectHandler struct {
+	stream  []byte
+	request *bytes.Buffer
+}
+
+func (h selectHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if _, ok := r.URL.Query()["location"]; ok {
+		w.Write([]byte(`<LocationConstraint xmlns="http://s3.amazonaws.com/doc/2006-03-01/"></LocationConstraint>`))
+		return
+	}
+	if _, ok := r.URL.Query()["select"]; r.Method != http.MethodPost || !ok {
+		w.WriteHeader(http.StatusNotImplemented)
+		return
+	}
+	h.request.Reset()
+	io.Copy(h.request, r.Body)
+	w.Write(h.stream)
+}
+
+func TestSQLSelectEventStream(t *testing.T) {
+	var stream bytes.Buffer
+	// Records are split anywhere, even inside a line.
+	writeSelectEvent(&stream, "Records", "application/octet-stream", "alice,31\nbo")
+	writeSelectEvent(&stream, "Progress", "text/xml", "<Progress><BytesScanned>20</BytesScanned><BytesProcessed>20</BytesProcessed><BytesReturned>11</BytesReturned></Progress>")
+	writeSelectEvent(&stream, "Records", "application/octet-stream", "b,42\n")
+	writeSelectEvent(&stream, "Stats", "text/xml", "<Stats><BytesScanned>40</BytesScanned><BytesProcessed>40</BytesProcessed><BytesReturned>14</BytesReturned></Stats>")
+	writeSelectEvent(&stream, "End", "", "")
+
+	handler := selectHandler{stream: stream.Bytes(), request: &bytes.Buffer{}}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+	defer withTestConfig(map[string]hostConfigV9{"myminio": testHost(server.URL)})()
+
+	// The shorthands of the serialization options.
+	set := flag.NewFlagSet(sqlCmd.Name, flag.ContinueOnError)
+	for _, f := range sqlCmd.Flags {
+		f.Apply(set)
+	}
+	if e := set.Parse([]string{"--csv-header", "use", "--input-gzip"}); e != nil {
+		t.Fatal(e)
+	}
+	selOpts := getSQLOpts(cli.NewContext(nil, set, nil), nil)
+
+	clnt, err := newClient("myminio/bucket/data")
+	if err != nil {
+		t.Fatal(err)
+	}
+	reader, err := clnt.Select("select name, age from S3Object where age > 30", nil, selOpts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reader.Close()
+	output, e := ioutil.ReadAll(reader)
+	if e != nil {
+		t.Fatal(e)
+	}
+	if string(output) != "alice,31\nbob,42\n" {
+		t.Fatalf("Expected the records of both events, got %q", output)
+	}
+	for _, option := range []string{"<CompressionType>GZIP</CompressionType>", "<FileHeaderInfo>USE</FileHeaderInfo>"} {
+		if !strings.Contains(handler.request.String(), option) {
+			t.Errorf("Expected %s in the request, got %s", option, handler.request.String())
+		}
+	}
+
+	// A corrupted message fails instead of returning partial records.
+	corrupted := append([]byte{}, stream.Bytes()...)
+	corrupted[20] ^= 0xff
+	handler.stream = corrupted
+	server.Config.Handler = handler
+	reader, err = clnt.Select("select * from S3Object", nil, selOpts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reader.Close()
+	if _, e = ioutil.ReadAll(reader); e == nil {
+		t.Fatal("Expected a corrupted event stream to fail")
+	}
+}
@@ -31,6 +31,16 @@ var (
 			Name:  "recursive, r",
 			Usage: "stat all objects recursively",
 		},
+		cli.BoolFlag{
+			Name:  "summary",
+			Usage: "report the count, size, types and largest of all objects recursively instead of each object",
+		},
+		cli.IntFlag{
+			Name:  "top",
+			Value: 10,
+			Usage: "number of the largest objects reported by --summary",
+		},
+		templateFlag,
 	}
 )
 
@@ -69,6 +79,12 @@ EXAMPLES:
   5. Stat encrypted files on Amazon S3 cloud storage. In case the encryption key contains non-printable character like tab, pass the
      base64 encoded string as key.
      {{.Prompt}} {{.HelpName}} --encrypt-key "s3/personal-document/=MzJieXRlc2xvbmdzZWNyZWFiY2RlZmcJZ2l2ZW5uMjE=" s3/personal-document/2019-account_report.docx
+
+  6. Profile the contents of a bucket, its size by extension and content type and its 5 largest objects.
+     {{.Prompt}} {{.HelpName}} --summary --top 5 s3/mybucket/
+
+  7. Print the ETag and storage class of all objects in mybucket.
+     {{.Prompt}} {{.HelpName}} --recursive --template '{{"{{"}}.Key{{"}}"}} {{"{{"}}.ETag{{"}}"}} {{"{{"}}.StorageClass{{"}}"}}' s3/mybucket/
 `,
 }
 
@@ -111,11 +127,20 @@ func mainStat(ctx *cli.Context) error {
 	encKeyDB, err := getEncKeys(ctx)
 	fatalIf(err, "Unable to parse encryption keys.")
 
+	// Templates are verified before anything is listed.
+	tmpl := parseTemplateFlag(ctx)
+	if tmpl != nil && ctx.Bool("summary") {
+		fatalIf(errInvalidArgument(), "--template cannot be used with --summary.")
+	}
+
 	// check 'stat' cli arguments.
 	checkStatSyntax(ctx, encKeyDB)
 
 	// Set command flags from context.
 	isRecursive := ctx.Bool("recursive")
+	if ctx.Int("top") < 0 {
+		fatalIf(errInvalidArgument().Trace(), "`--top` cannot be negative.")
+	}
 
 	args := ctx.Args()
 	// mimic operating system tool behavior.
@@ -123,13 +148,30 @@ func mainStat(ctx *cli.Context) error {
 		args = []string{"."}
 	}
 
-	var cErr error
+	var result batchResult
 	for _, targetURL := range args {
+		if ctx.Bool("summary") {
+			summary, err := summarizeURL(targetURL, ctx.Int("top"))
+			if err != nil {
+				errorIf(err, "Unable to summarize `"+targetURL+"`.")
+				result.failure()
+				continue
+			}
+			printMsg(summary)
+			result.success()
+			continue
+		}
 		stats, err := statURL(targetURL, false, isRecursive, encKeyDB)
 		if err != nil {
-			fatalIf(err, "Unable to stat `"+targetURL+"`.")
+			errorIf(err, "Unable to stat `"+targetURL+"`.")
+			result.failure()
+			continue
 		}
 		for _, stat := range stats {
+			if tmpl != nil {
+				printTemplate(tmpl, newTemplateItem(stat))
+				continue
+			}
 			st := parseStat(stat)
 			if !globalJSON {
 				printStat(st)
@@ -137,7 +179,7 @@ func mainStat(ctx *cli.Context) error {
 				console.Println(st.JSON())
 			}
 		}
+		result.success()
 	}
-	return cErr
-
+	return result.exit()
 }
@@ -0,0 +1,227 @@
+/*
+ * MinIO Client (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"container/heap"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	json "github.com/minio/mc/pkg/colorjson"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/minio/pkg/console"
+)
+
+// Name of the group of objects without extension.
+const statSummaryNoExtension = "(none)"
+
+// statSummaryGroup - count and size of the objects of an extension or
+// content type.
+type statSummaryGroup struct {
+	Name  string `json:"name"`
+	Count int64  `json:"count"`
+	Size  int64  `json:"size"`
+}
+
+// statSummaryObject - an object reported by the summary.
+type statSummaryObject struct {
+	Key          string    `json:"key"`
+	Size         int64     `json:"size"`
+	LastModified time.Time `json:"lastModified"`
+}
+
+// statSummaryMessage container for the aggregate statistics of all
+// objects under a prefix.
+type statSummaryMessage struct {
+	Status  string `json:"status"`
+	Target  string `json:"target"`
+	Objects int64  `json:"objects"`
+	Size    int64  `json:"size"`
+	// Sorted by size, largest first. Content types are guessed from the
+	// extension of keys, objects are not requested one by one.
+	Extensions   []statSummaryGroup  `json:"extensions"`
+	ContentTypes []statSummaryGroup  `json:"contentTypes"`
+	Largest      []statSummaryObject `json:"largest"`
+	Oldest       *statSummaryObject  `json:"oldest,omitempty"`
+	Newest       *statSummaryObject  `json:"newest,omitempty"`
+}
+
+// String colorized summary message.
+func (s statSummaryMessage) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s\n", console.Colorize("Name", fmt.Sprintf("%-10s: %s", "Target", s.Target)))
+	fmt.Fprintf(&b, "%-10s: %d\n", "Objects", s.Objects)
+	fmt.Fprintf(&b, "%-10s: %s\n", "Size", humanizeSize(s.Size))
+	if s.Oldest != nil {
+		fmt.Fprintf(&b, "%-10s: %s %s\n", "Oldest", s.Oldest.LastModified.Local().Format(printDate), s.Oldest.Key)
+	}
+	if s.Newest != nil {
+		fmt.Fprintf(&b, "%-10s: %s %s\n", "Newest", s.Newest.LastModified.Local().Format(printDate), s.Newest.Key)
+	}
+	printGroups := func(title string, groups []statSummaryGroup) {
+		if len(groups) == 0 {
+			return
+		}
+		fmt.Fprintf(&b, "%-10s:\n", title)
+		width := 0
+		for _, g := range groups {
+			if len(g.Name) > width {
+				width = len(g.Name)
+			}
+		}
+		for _, g := range groups {
+			fmt.Fprintf(&b, "  %-*s %8d objects %10s %5.1f%%\n", width, g.Name, g.Count,
+				console.Colorize("Size", humanizeSize(g.Size)), percentOf(g.Size, s.Size))
+		}
+	}
+	printGroups("Extension", s.Extensions)
+	printGroups("Type", s.ContentTypes)
+	if len(s.Largest) > 0 {
+		fmt.Fprintf(&b, "%-10s:\n", "Largest")
+		for _, o := range s.Largest {
+			fmt.Fprintf(&b, "  %10s %s\n", console.Colorize("Size", humanizeSize(o.Size)), o.Key)
+		}
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// JSON jsonified summary message.
+func (s statSummaryMessage) JSON() string {
+	s.Status = "success"
+	jsonMessageBytes, e := json.MarshalIndent(s, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(jsonMessageBytes)
+}
+
+// percentOf - returns part as a percentage of total.
+func percentOf(part, total int64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(part) * 100 / float64(total)
+}
+
+// largestObjects - min-heap of the largest objects seen, the smallest
+// of them on top to be replaced by larger objects.
+type largestObjects []statSummaryObject
+
+func (h largestObjects) Len() int { return len(h) }
+func (h largestObjects) Less(i, j int) bool {
+	if h[i].Size != h[j].Size {
+		return h[i].Size < h[j].Size
+	}
+	// Of objects of the same size, those listed first are kept.
+	return h[i].Key > h[j].Key
+}
+func (h largestObjects) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *largestObjects) Push(x interface{}) { *h = append(*h, x.(statSummaryObject)) }
+func (h *largestObjects) Pop() interface{} {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return x
+}
+
+// sortedGroups - returns the groups largest first.
+func sortedGroups(groups map[string]*statSummaryGroup) []statSummaryGroup {
+	sorted := make([]statSummaryGroup, 0, len(groups))
+	for _, g := range groups {
+		sorted = append(sorted, *g)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Size != sorted[j].Size {
+			return sorted[i].Size > sorted[j].Size
+		}
+		return sorted[i].Name < sorted[j].Name
+	})
+	return sorted
+}
+
+// summarizeURL - lists all objects under targetURL and returns their
+// aggregate statistics with the top largest objects. Objects are
+// accounted for as they are listed, only the largest are kept.
+func summarizeURL(targetURL string, top int) (statSummaryMessage, *probe.Error) {
+	clnt, err := newClient(targetURL)
+	if err != nil {
+		return statSummaryMessage{}, err.Trace(targetURL)
+	}
+	prefixPath := clnt.GetURL().Path
+	separator := string(clnt.GetURL().Separator)
+	if !strings.HasSuffix(prefixPath, separator) {
+		prefixPath = prefixPath[:strings.LastIndex(prefixPath, separator)+1]
+	}
+
+	summary := statSummaryMessage{Target: targetURL}
+	extensions := make(map[string]*statSummaryGroup)
+	contentTypes := make(map[string]*statSummaryGroup)
+	add := func(groups map[string]*statSummaryGroup, name string, size int64) {
+		g, ok := groups[name]
+		if !ok {
+			g = &statSummaryGroup{Name: name}
+			groups[name] = g
+		}
+		g.Count++
+		g.Size += size
+	}
+	largest := &largestObjects{}
+	for content := range clnt.List(true, false, false, DirNone) {
+		if content.Err != nil {
+			return statSummaryMessage{}, content.Err.Trace(clnt.GetURL().String())
+		}
+		if content.Type.IsDir() {
+			continue
+		}
+		key := strings.TrimPrefix(filepath.ToSlash(content.URL.Path), filepath.ToSlash(prefixPath))
+		object := statSummaryObject{Key: key, Size: content.Size, LastModified: content.Time}
+
+		summary.Objects++
+		summary.Size += content.Size
+		ext := strings.ToLower(filepath.Ext(content.URL.Path))
+		if ext == "" {
+			ext = statSummaryNoExtension
+		}
+		add(extensions, ext, content.Size)
+		add(contentTypes, guessURLContentType(content.URL.Path), content.Size)
+
+		if summary.Oldest == nil || object.LastModified.Before(summary.Oldest.LastModified) {
+			oldest := object
+			summary.Oldest = &oldest
+		}
+		if summary.Newest == nil || object.LastModified.After(summary.Newest.LastModified) {
+			newest := object
+			summary.Newest = &newest
+		}
+		if top > 0 {
+			heap.Push(largest, object)
+			if largest.Len() > top {
+				heap.Pop(largest)
+			}
+		}
+	}
+
+	summary.Extensions = sortedGroups(extensions)
+	summary.ContentTypes = sortedGroups(contentTypes)
+	summary.Largest = make([]statSummaryObject, largest.Len())
+	for i := len(summary.Largest) - 1; i >= 0; i-- {
+		summary.Largest[i] = heap.Pop(largest).(statSummaryObject)
+	}
+	return summary, nil
+}
@@ -0,0 +1,84 @@
+/*
+ * MinIO Client (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestSummarizeURL(t *testing.T) {
+	objects := map[string][]byte{
+		"bucket/photos/a.jpg":     bytes.Repeat([]byte("j"), 300),
+		"bucket/photos/b.JPG":     bytes.Repeat([]byte("j"), 100),
+		"bucket/docs/notes.txt":   bytes.Repeat([]byte("t"), 50),
+		"bucket/docs/report.pdf":  bytes.Repeat([]byte("p"), 500),
+		"bucket/docs/README":      bytes.Repeat([]byte("r"), 10),
+		"bucket/other/photo.jpeg": bytes.Repeat([]byte("j"), 40),
+	}
+	server := httptest.NewServer(newFakeS3Server(objects))
+	defer server.Close()
+	defer withTestConfig(map[string]hostConfigV9{"myminio": testHost(server.URL)})()
+
+	summary, err := summarizeURL("myminio/bucket/", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if summary.Objects != 6 || summary.Size != 1000 {
+		t.Fatalf("Expected 6 objects of 1000 bytes, got %d objects of %d bytes", summary.Objects, summary.Size)
+	}
+	expectedExtensions := []statSummaryGroup{
+		{Name: ".pdf", Count: 1, Size: 500},
+		{Name: ".jpg", Count: 2, Size: 400},
+		{Name: ".txt", Count: 1, Size: 50},
+		{Name: ".jpeg", Count: 1, Size: 40},
+		{Name: statSummaryNoExtension, Count: 1, Size: 10},
+	}
+	if !reflect.DeepEqual(summary.Extensions, expectedExtensions) {
+		t.Fatalf("Expected extensions %v, got %v", expectedExtensions, summary.Extensions)
+	}
+	expectedTypes := []statSummaryGroup{
+		{Name: "application/pdf", Count: 1, Size: 500},
+		{Name: "image/jpeg", Count: 3, Size: 440},
+		{Name: "text/plain", Count: 1, Size: 50},
+		{Name: "application/octet-stream", Count: 1, Size: 10},
+	}
+	if !reflect.DeepEqual(summary.ContentTypes, expectedTypes) {
+		t.Fatalf("Expected content types %v, got %v", expectedTypes, summary.ContentTypes)
+	}
+	var largest []string
+	for _, object := range summary.Largest {
+		largest = append(largest, object.Key)
+	}
+	if expected := []string{"docs/report.pdf", "photos/a.jpg"}; !reflect.DeepEqual(largest, expected) {
+		t.Fatalf("Expected the largest objects %v, got %v", expected, largest)
+	}
+	if summary.Oldest == nil || summary.Newest == nil {
+		t.Fatal("Expected the oldest and newest objects to be reported")
+	}
+
+	// The summary of a prefix only counts its objects.
+	summary, err = summarizeURL("myminio/bucket/docs/", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if summary.Objects != 3 || summary.Size != 560 || len(summary.Largest) != 3 || summary.Largest[2].Key != "README" {
+		t.Fatalf("Expected 3 objects of 560 bytes under docs/, got %d objects of %d bytes, largest %v", summary.Objects, summary.Size, summary.Largest)
+	}
+}
@@ -18,11 +18,13 @@ package cmd
 
 import (
 	"fmt"
+	"net/http"
 	"path/filepath"
+	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
-	humanize "github.com/dustin/go-humanize"
 	json "github.com/minio/mc/pkg/colorjson"
 	"github.com/minio/mc/pkg/probe"
 	"github.com/minio/minio/pkg/console"
@@ -39,6 +41,44 @@ type statMessage struct {
 	Expires           time.Time         `json:"expires"`
 	EncryptionHeaders map[string]string `json:"encryption,omitempty"`
 	Metadata          map[string]string `json:"metadata"`
+	Restore           *restoreStatus    `json:"restore,omitempty"`
+}
+
+// restoreStatus - restore status of an archived object.
+type restoreStatus struct {
+	OngoingRequest bool      `json:"ongoingRequest"`
+	ExpiryDate     time.Time `json:"expiryDate"`
+}
+
+// restoreHeaderRegex matches the key="value" pairs of x-amz-restore.
+var restoreHeaderRegex = regexp.MustCompile(`([a-z-]+)="([^"]*)"`)
+
+// parseRestoreStatus - parses the x-amz-restore header of an object, e.g.
+// ongoing-request="false", expiry-date="Fri, 23 Dec 2012 00:00:00 GMT".
+func parseRestoreStatus(header string) (*restoreStatus, *probe.Error) {
+	status := &restoreStatus{}
+	var ongoing bool
+	for _, match := range restoreHeaderRegex.FindAllStringSubmatch(header, -1) {
+		switch match[1] {
+		case "ongoing-request":
+			ongoingRequest, e := strconv.ParseBool(match[2])
+			if e != nil {
+				return nil, probe.NewError(e).Trace(header)
+			}
+			status.OngoingRequest = ongoingRequest
+			ongoing = true
+		case "expiry-date":
+			expiryDate, e := time.Parse(http.TimeFormat, match[2])
+			if e != nil {
+				return nil, probe.NewError(e).Trace(header)
+			}
+			status.ExpiryDate = expiryDate
+		}
+	}
+	if !ongoing {
+		return nil, errInvalidArgument().Trace(header)
+	}
+	return status, nil
 }
 
 // String colorized string message.
@@ -47,7 +87,7 @@ func printStat(stat statMessage) {
 	stat.Key = fmt.Sprintf("%-10s: %s", "Name", stat.Key)
 	console.Println(console.Colorize("Name", stat.Key))
 	console.Println(fmt.Sprintf("%-10s: %s ", "Date", stat.Date.Format(printDate)))
-	console.Println(fmt.Sprintf("%-10s: %-6s ", "Size", humanize.IBytes(uint64(stat.Size))))
+	console.Println(fmt.Sprintf("%-10s: %-6s ", "Size", humanizeSize(stat.Size)))
 	if stat.ETag != "" {
 		console.Println(fmt.Sprintf("%-10s: %s ", "ETag", stat.ETag))
 	}
@@ -55,6 +95,13 @@ func printStat(stat statMessage) {
 	if !stat.Expires.IsZero() {
 		console.Println(fmt.Sprintf("%-10s: %s ", "Expires", stat.Expires.Format(printDate)))
 	}
+	if stat.Restore != nil {
+		if stat.Restore.OngoingRequest {
+			console.Println(fmt.Sprintf("%-10s: %s ", "Restore", "in progress"))
+		} else {
+			console.Println(fmt.Sprintf("%-10s: available until %s ", "Restore", stat.Restore.ExpiryDate.Local().Format(printDate)))
+		}
+	}
 	var maxKey = 0
 	for k := range stat.Metadata {
 		if len(k) > maxKey {
@@ -109,6 +156,9 @@ func parseStat(c *clientContent) statMessage {
 	content.ETag = strings.TrimSuffix(content.ETag, "\"")
 	content.Expires = c.Expires
 	content.EncryptionHeaders = c.EncryptionHeaders
+	if header, ok := c.Metadata["X-Amz-Restore"]; ok {
+		content.Restore, _ = parseRestoreStatus(header)
+	}
 	return content
 }
 
@@ -156,10 +206,6 @@ func statURL(targetURL string, isIncomplete, isRecursive bool, encKeyDB map[stri
 			continue
 		}
 
-		if content.StorageClass == s3StorageClassGlacier {
-			continue
-		}
-
 		url := targetAlias + getKey(content)
 		standardizedURL := getStandardizedURL(targetURL)
 
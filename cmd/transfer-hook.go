@@ -0,0 +1,92 @@
+/*
+ * MinIO Client (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/minio/pkg/console"
+)
+
+// transferHook runs the shell command of --on-complete after each
+// object copied, the copy is described to the command by MC_SRC, MC_DST
+// and MC_SIZE. Commands run one at a time, parallel copies wait for the
+// command of the previous copy to exit.
+type transferHook struct {
+	mutex   *sync.Mutex
+	command string
+	// Fail copies whose command failed.
+	strict bool
+}
+
+// newTransferHook - returns the hook running command, nil if there is
+// no command.
+func newTransferHook(command string, strict bool) *transferHook {
+	if command == "" {
+		return nil
+	}
+	return &transferHook{mutex: &sync.Mutex{}, command: command, strict: strict}
+}
+
+// run - runs the command after the successful copy of cpURLs. Failures
+// of the command are logged, unless strict they do not fail the copy.
+func (h *transferHook) run(cpURLs URLs) URLs {
+	if h == nil || cpURLs.Error != nil || cpURLs.SourceContent.Type.IsDir() {
+		return cpURLs
+	}
+	shell, flag := "sh", "-c"
+	if runtime.GOOS == "windows" {
+		shell, flag = "cmd", "/C"
+	}
+	cmd := exec.Command(shell, flag, h.command)
+	cmd.Env = append(os.Environ(),
+		"MC_SRC="+filepath.ToSlash(filepath.Join(cpURLs.SourceAlias, cpURLs.SourceContent.URL.Path)),
+		"MC_DST="+filepath.ToSlash(filepath.Join(cpURLs.TargetAlias, cpURLs.TargetContent.URL.Path)),
+		"MC_SIZE="+strconv.FormatInt(cpURLs.SourceContent.Size, 10),
+	)
+
+	h.mutex.Lock()
+	out, e := cmd.CombinedOutput()
+	h.mutex.Unlock()
+	if e == nil {
+		return cpURLs
+	}
+
+	if output := strings.TrimSpace(string(out)); output != "" {
+		e = errors.New(e.Error() + ": " + output)
+	}
+	err := probe.NewError(e).Trace(h.command)
+	if h.strict {
+		cpURLs.Error = err
+		return cpURLs
+	}
+	// Print in new line and adjust to top so that we don't print over
+	// the ongoing progress bar.
+	if !globalQuiet && !globalJSON {
+		console.Eraseline()
+	}
+	errorIf(err, "Command of --on-complete failed for `%s`.", cpURLs.SourceContent.URL.String())
+	return cpURLs
+}
@@ -0,0 +1,86 @@
+/*
+ * MinIO Client (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/fatih/color"
+)
+
+func TestCopyOnComplete(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the hook of the test is a POSIX shell command")
+	}
+	savedQuiet, savedJSON, savedOutput := globalQuiet, globalJSON, color.Output
+	defer func() {
+		globalQuiet, globalJSON, color.Output = savedQuiet, savedJSON, savedOutput
+	}()
+	globalQuiet, globalJSON, color.Output = true, true, ioutil.Discard
+	defer withTestConfig(nil)()
+
+	dir, e := ioutil.TempDir("", "mc-cp-on-complete-")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.RemoveAll(dir)
+	source, target, log := filepath.Join(dir, "source"), filepath.Join(dir, "target"), filepath.Join(dir, "hook.log")
+	files := map[string]string{"a": "first", "sub/b": "second!"}
+	for name, data := range files {
+		if e = os.MkdirAll(filepath.Dir(filepath.Join(source, name)), 0700); e != nil {
+			t.Fatal(e)
+		}
+		if e = ioutil.WriteFile(filepath.Join(source, name), []byte(data), 0600); e != nil {
+			t.Fatal(e)
+		}
+	}
+
+	hook := `echo "$MC_SRC $MC_DST $MC_SIZE" >> "` + log + `"`
+	args := []string{"--recursive", "--on-complete", hook, source + string(os.PathSeparator), target + string(os.PathSeparator)}
+	if e = doCopySession(newCopyContext(t, args...), nil, nil); e != nil {
+		t.Fatal(e)
+	}
+	data, e := ioutil.ReadFile(log)
+	if e != nil {
+		t.Fatal(e)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	sort.Strings(lines)
+	expected := []string{
+		filepath.ToSlash(filepath.Join(source, "a")) + " " + filepath.ToSlash(filepath.Join(target, "a")) + " 5",
+		filepath.ToSlash(filepath.Join(source, "sub", "b")) + " " + filepath.ToSlash(filepath.Join(target, "sub", "b")) + " 7",
+	}
+	if strings.Join(lines, "\n") != strings.Join(expected, "\n") {
+		t.Fatalf("Expected the hook to run with\n%s\ngot\n%s", strings.Join(expected, "\n"), strings.Join(lines, "\n"))
+	}
+
+	// Failures of the hook fail the copy only with --hook-strict.
+	args = []string{"--on-complete", "exit 3", filepath.Join(source, "a"), filepath.Join(target, "a")}
+	if e = doCopySession(newCopyContext(t, args...), nil, nil); e != nil {
+		t.Fatalf("Expected the copy to succeed despite the hook, got %v", e)
+	}
+	args = append([]string{"--hook-strict"}, args...)
+	if e = doCopySession(newCopyContext(t, args...), nil, nil); e == nil {
+		t.Fatal("Expected the copy to fail with the hook")
+	}
+}
@@ -0,0 +1,149 @@
+/*
+ * MinIO Client (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/minio/mc/pkg/probe"
+)
+
+const transferManifestVersion = "1"
+
+// transferManifestEntry - an object transferred by cp or mirror with
+// --manifest, as found at the target once transferred.
+type transferManifestEntry struct {
+	Key  string `json:"key"`
+	Size int64  `json:"size"`
+	ETag string `json:"etag,omitempty"`
+}
+
+// transferManifestV1 - the manifest written by --manifest.
+type transferManifestV1 struct {
+	Version string                  `json:"version"`
+	Objects []transferManifestEntry `json:"objects"`
+}
+
+// transferManifest collects the objects transferred by a recursive cp
+// or a mirror, written to the file requested with --manifest once all
+// of them are transferred. Unlike copyManifest it is not meant to
+// resume the transfer but to verify or to download the same objects
+// later, it holds the key of the objects in their bucket.
+type transferManifest struct {
+	mutex    sync.Mutex
+	path     string
+	encKeyDB map[string][]prefixSSEPair
+	objects  map[string]transferManifestEntry
+}
+
+// newTransferManifest - returns the manifest to write to path, nil if
+// no manifest is requested.
+func newTransferManifest(path string, encKeyDB map[string][]prefixSSEPair) *transferManifest {
+	if path == "" {
+		return nil
+	}
+	return &transferManifest{
+		path:     path,
+		encKeyDB: encKeyDB,
+		objects:  make(map[string]transferManifestEntry),
+	}
+}
+
+// add - records the target of cpURLs, its ETag is the one returned by
+// the target after the transfer.
+func (m *transferManifest) add(cpURLs URLs) *probe.Error {
+	if m == nil {
+		return nil
+	}
+	targetURL := cpURLs.TargetContent.URL.String()
+	clnt, err := newClientFromAlias(cpURLs.TargetAlias, targetURL)
+	if err != nil {
+		return err.Trace(targetURL)
+	}
+	content, err := clnt.Stat(false, false, false, getSSE(targetURL, m.encKeyDB[cpURLs.TargetAlias]))
+	if err != nil {
+		return err.Trace(targetURL)
+	}
+	key := strings.TrimPrefix(cpURLs.TargetContent.URL.Path, string(cpURLs.TargetContent.URL.Separator))
+	if s3Clnt, ok := clnt.(*s3Client); ok {
+		_, key = s3Clnt.url2BucketAndObject()
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.objects[targetURL] = transferManifestEntry{
+		Key:  key,
+		Size: content.Size,
+		ETag: strings.Trim(content.ETag, "\""),
+	}
+	return nil
+}
+
+// save - writes the manifest, sorted by key. It is written to a
+// temporary file renamed at the end, a manifest is either complete or
+// not there.
+func (m *transferManifest) save() *probe.Error {
+	if m == nil {
+		return nil
+	}
+	m.mutex.Lock()
+	manifest := transferManifestV1{
+		Version: transferManifestVersion,
+		Objects: make([]transferManifestEntry, 0, len(m.objects)),
+	}
+	for _, entry := range m.objects {
+		manifest.Objects = append(manifest.Objects, entry)
+	}
+	m.mutex.Unlock()
+	sort.Slice(manifest.Objects, func(i, j int) bool {
+		return manifest.Objects[i].Key < manifest.Objects[j].Key
+	})
+
+	data, e := json.MarshalIndent(manifest, "", " ")
+	if e != nil {
+		return probe.NewError(e)
+	}
+	return writeFileAtomic(m.path, append(data, '\n'))
+}
+
+// writeFileAtomic - writes data to a temporary file renamed to path at
+// the end, the file at path is either complete or not there.
+func writeFileAtomic(path string, data []byte) *probe.Error {
+	tmpFile, e := ioutil.TempFile(filepath.Dir(path), "."+filepath.Base(path)+"-")
+	if e != nil {
+		return probe.NewError(e)
+	}
+	if _, e = tmpFile.Write(data); e == nil {
+		e = tmpFile.Close()
+	} else {
+		tmpFile.Close()
+	}
+	if e == nil {
+		e = os.Rename(tmpFile.Name(), path)
+	}
+	if e != nil {
+		os.Remove(tmpFile.Name())
+		return probe.NewError(e)
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+/*
+ * MinIO Client (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package cmd
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/minio/mc/pkg/probe"
+)
+
+const transferReportVersion = "1"
+
+// transferReportObjects - the objects counted by a transfer report.
+type transferReportObjects struct {
+	Processed int64 `json:"processed"`
+	Succeeded int64 `json:"succeeded"`
+	Failed    int64 `json:"failed"`
+	// Objects removed from the target by mirror --remove.
+	Removed int64 `json:"removed,omitempty"`
+}
+
+// transferReportV1 - the report written by --report.
+type transferReportV1 struct {
+	Version   string                `json:"version"`
+	Command   string                `json:"command"`
+	Status    string                `json:"status"`
+	Objects   transferReportObjects `json:"objects"`
+	Bytes     int64                 `json:"bytes"`
+	StartTime time.Time             `json:"startTime"`
+	// Duration of the transfer and bytes transferred per second on
+	// average, for CI dashboards.
+	DurationSeconds float64 `json:"durationSeconds"`
+	BytesPerSecond  float64 `json:"bytesPerSecond"`
+}
+
+// transferReport counts the objects transferred by cp or mirror, the
+// totals are written to the file requested with --report once the
+// command completes, failed or not. Unlike the operation log of
+// --log-file it holds no entry per object. Objects are added by the
+// goroutine receiving the copy statuses only.
+type transferReport struct {
+	path   string
+	start  time.Time
+	report transferReportV1
+}
+
+// newTransferReport - returns the report of command to write to path,
+// nil if no report is requested.
+func newTransferReport(path, command string) *transferReport {
+	if path == "" {
+		return nil
+	}
+	start := nowFunc()
+	return &transferReport{
+		path:  path,
+		start: start,
+		report: transferReportV1{
+			Version:   transferReportVersion,
+			Command:   command,
+			StartTime: start.UTC(),
+		},
+	}
+}
+
+// add - counts the copy or the removal of urls.
+func (r *transferReport) add(urls URLs) {
+	if r == nil {
+		return
+	}
+	objects := &r.report.Objects
+	objects.Processed++
+	switch {
+	case urls.Error != nil:
+		objects.Failed++
+	case urls.SourceContent == nil:
+		objects.Removed++
+		objects.Succeeded++
+	default:
+		objects.Succeeded++
+		r.report.Bytes += urls.SourceContent.Size
+	}
+}
+
+// save - writes the report with the totals so far, atomically.
+func (r *transferReport) save() *probe.Error {
+	if r == nil {
+		return nil
+	}
+	report := r.report
+	duration := nowFunc().Sub(r.start)
+	report.DurationSeconds = duration.Seconds()
+	if duration > 0 {
+		report.BytesPerSecond = float64(report.Bytes) / duration.Seconds()
+	}
+	switch {
+	case report.Objects.Failed == 0:
+		report.Status = "success"
+	case report.Objects.Succeeded > 0:
+		report.Status = "partial"
+	default:
+		report.Status = "error"
+	}
+
+	data, e := json.MarshalIndent(report, "", " ")
+	if e != nil {
+		return probe.NewError(e)
+	}
+	return writeFileAtomic(r.path, append(data, '\n'))
+}
@@ -0,0 +1,92 @@
+/*
+ * MinIO Client (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	"github.com/minio/minio/pkg/console"
+)
+
+var (
+	trashEmptyFlags = []cli.Flag{
+		cli.BoolFlag{
+			Name:  "force",
+			Usage: "allow deleting the objects in the trash",
+		},
+	}
+)
+
+var trashEmptyCmd = cli.Command{
+	Name:   "empty",
+	Usage:  "permanently delete objects removed with 'rm --trash'",
+	Action: mainTrashEmpty,
+	Before: setGlobalsFromContext,
+	Flags:  append(trashEmptyFlags, globalFlags...),
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} --force TARGET [TARGET ...]
+
+  TARGET is the location objects were removed from, the trash of
+  objects removed under it is emptied.
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+EXAMPLES:
+  1. Empty the trash of a bucket.
+     {{.Prompt}} {{.HelpName}} --force s3/sql-backups
+
+  2. Empty the trash of local files removed from a folder.
+     {{.Prompt}} {{.HelpName}} --force /home/user/projects
+`,
+}
+
+// emptyTrash - deletes the objects in the trash under aliasedURL.
+func emptyTrash(aliasedURL string) error {
+	trash, err := trashURL(aliasedURL)
+	if err != nil {
+		errorIf(err.Trace(aliasedURL), "Failed to empty the trash of `"+aliasedURL+"`.")
+		return exitStatus(globalErrorExitStatus)
+	}
+	if !isURLPrefixExists(trash, false) {
+		errorIf(errTrashEmpty(aliasedURL).Trace(aliasedURL), "Failed to empty the trash of `"+aliasedURL+"`.")
+		return exitStatus(globalErrorExitStatus)
+	}
+	isIncomplete, isFake, isTrash := false, false, false
+	return removeRecursive(trash, isIncomplete, isFake, isTrash, "", "", nil, nil)
+}
+
+// mainTrashEmpty is the handle for "mc trash empty" command.
+func mainTrashEmpty(ctx *cli.Context) error {
+	if !ctx.Args().Present() {
+		cli.ShowCommandHelpAndExit(ctx, "empty", 1) // last argument is exit code
+	}
+	if !ctx.Bool("force") {
+		fatalIf(errDummy().Trace(),
+			"Emptying the trash requires --force flag. This operation is *IRREVERSIBLE*.")
+	}
+	console.SetColor("Remove", color.New(color.FgGreen, color.Bold))
+
+	var result batchResult
+	for _, url := range ctx.Args() {
+		result.record(emptyTrash(url))
+	}
+	return result.exit()
+}
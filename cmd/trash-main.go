@@ -0,0 +1,43 @@
+/*
+ * MinIO Client (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import "github.com/minio/cli"
+
+var (
+	trashFlags = []cli.Flag{}
+)
+
+var trashCmd = cli.Command{
+	Name:            "trash",
+	Usage:           "restore or empty objects removed with 'rm --trash'",
+	HideHelpCommand: true,
+	Action:          mainTrash,
+	Before:          setGlobalsFromContext,
+	Flags:           append(trashFlags, globalFlags...),
+	Subcommands: []cli.Command{
+		trashRestoreCmd,
+		trashEmptyCmd,
+	},
+}
+
+// mainTrash is the handle for "mc trash" command.
+func mainTrash(ctx *cli.Context) error {
+	cli.ShowCommandHelp(ctx, ctx.Args().First())
+	return nil
+	// Sub-commands like "restore", "empty" have their own main.
+}
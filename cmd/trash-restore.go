@@ -0,0 +1,116 @@
+/*
+ * MinIO Client (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	json "github.com/minio/mc/pkg/colorjson"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/minio/pkg/console"
+)
+
+var (
+	trashRestoreFlags = []cli.Flag{
+		cli.BoolFlag{
+			Name:  "force",
+			Usage: "allow overwriting existing objects with the objects in the trash",
+		},
+	}
+)
+
+var trashRestoreCmd = cli.Command{
+	Name:   "restore",
+	Usage:  "move objects removed with 'rm --trash' back",
+	Action: mainTrashRestore,
+	Before: setGlobalsFromContext,
+	Flags:  append(append(trashRestoreFlags, ioFlags...), globalFlags...),
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} TARGET [TARGET ...]
+
+  TARGET is the location objects were removed from, objects removed
+  under it are restored. Existing objects are not overwritten unless
+  --force is set.
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+EXAMPLES:
+  1. Restore an object removed by mistake.
+     {{.Prompt}} {{.HelpName}} s3/sql-backups/1999/old-backup.tgz
+
+  2. Restore all objects removed from a prefix.
+     {{.Prompt}} {{.HelpName}} s3/sql-backups/1999/
+
+  3. Restore a local folder.
+     {{.Prompt}} {{.HelpName}} /home/user/projects/old
+
+  4. Restore an object encrypted with a customer provided key, overwriting the object uploaded since.
+     {{.Prompt}} {{.HelpName}} --force --encrypt-key "s3/sql-backups/=32byteslongsecretkeymustbegiven1" s3/sql-backups/1999/old-backup.tgz
+`,
+}
+
+// trashRestoreMessage container for restored objects.
+type trashRestoreMessage struct {
+	Status string `json:"status"`
+	Key    string `json:"key"`
+}
+
+// String colorized restore message.
+func (t trashRestoreMessage) String() string {
+	return console.Colorize("Restore", fmt.Sprintf("Restored `%s` from the trash.", t.Key))
+}
+
+// JSON jsonified restore message.
+func (t trashRestoreMessage) JSON() string {
+	t.Status = "success"
+	msgBytes, e := json.MarshalIndent(t, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(msgBytes)
+}
+
+// mainTrashRestore is the handle for "mc trash restore" command.
+func mainTrashRestore(ctx *cli.Context) error {
+	if !ctx.Args().Present() {
+		cli.ShowCommandHelpAndExit(ctx, "restore", 1) // last argument is exit code
+	}
+	console.SetColor("Restore", color.New(color.FgGreen, color.Bold))
+
+	// Parse encryption keys per command.
+	encKeyDB, err := getEncKeys(ctx)
+	fatalIf(err, "Unable to parse encryption keys.")
+
+	isForce := ctx.Bool("force")
+	var result batchResult
+	for _, url := range ctx.Args() {
+		err := restoreFromTrash(url, isForce, encKeyDB, func(restored string) {
+			printMsg(trashRestoreMessage{Key: restored})
+		})
+		if err != nil {
+			errorIf(err.Trace(url), "Failed to restore `"+url+"` from the trash.")
+			result.failure()
+			continue
+		}
+		result.success()
+	}
+	return result.exit()
+}
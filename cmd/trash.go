@@ -0,0 +1,228 @@
+/*
+ * MinIO Client (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/minio-go/v6/pkg/encrypt"
+)
+
+// Objects removed with `rm --trash` are moved to the trash instead of
+// being deleted, keeping their path so that `mc trash restore` can move
+// them back: objects of a bucket under the trashPrefix of the same
+// bucket, local files under the trash folder of the config folder.
+const trashPrefix = ".mc-trash/"
+
+// getTrashDir - returns the trash folder of local files.
+func getTrashDir() (string, *probe.Error) {
+	configDir, err := getMcConfigDir()
+	if err != nil {
+		return "", err.Trace()
+	}
+	return filepath.Join(configDir, globalTrashDir), nil
+}
+
+// trashURL - returns where aliasedURL is kept in the trash, an aliased
+// URL for objects and a path for local files.
+func trashURL(aliasedURL string) (string, *probe.Error) {
+	alias, urlStr, hostCfg, err := expandAlias(aliasedURL)
+	if err != nil {
+		return "", err.Trace(aliasedURL)
+	}
+	if hostCfg == nil {
+		trashDir, err := getTrashDir()
+		if err != nil {
+			return "", err.Trace(aliasedURL)
+		}
+		absPath, e := filepath.Abs(urlStr)
+		if e != nil {
+			return "", probe.NewError(e).Trace(aliasedURL)
+		}
+		return filepath.Join(trashDir, strings.TrimPrefix(absPath, filepath.VolumeName(absPath))), nil
+	}
+	_, path := url2Alias(aliasedURL)
+	tokens := splitStr(strings.TrimPrefix(filepath.ToSlash(path), "/"), "/", 2)
+	if tokens[0] == "" {
+		return "", errInvalidArgument().Trace(aliasedURL)
+	}
+	return alias + "/" + tokens[0] + "/" + trashPrefix + tokens[1], nil
+}
+
+// isTrashURL - returns true if aliasedURL is in the trash.
+func isTrashURL(aliasedURL string) bool {
+	_, urlStr, hostCfg, err := expandAlias(aliasedURL)
+	if err != nil {
+		return false
+	}
+	if hostCfg == nil {
+		trashDir, err := getTrashDir()
+		if err != nil {
+			return false
+		}
+		absPath, e := filepath.Abs(urlStr)
+		return e == nil && (absPath == trashDir || strings.HasPrefix(absPath, trashDir+string(filepath.Separator)))
+	}
+	_, path := url2Alias(aliasedURL)
+	tokens := splitStr(strings.TrimPrefix(filepath.ToSlash(path), "/"), "/", 2)
+	return strings.HasPrefix(tokens[1], trashPrefix) || tokens[1]+"/" == trashPrefix
+}
+
+// moveTo - moves the object or local file aliasedURL, described by
+// content, to the aliased URL target of the same alias, with a server
+// side copy and a removal for objects, encrypted with sse at both ends.
+// Local folders are created at target and removed, the files they held
+// being moved first.
+func moveTo(content *clientContent, aliasedURL, target string, sse encrypt.ServerSide) *probe.Error {
+	clnt, err := newClient(aliasedURL)
+	if err != nil {
+		return err.Trace(aliasedURL)
+	}
+	// Listings may report paths relative to the listed prefix.
+	sourceURL := clnt.GetURL()
+	if sourceURL.Type == fileSystem {
+		if content.Type.IsDir() {
+			if e := os.MkdirAll(target, 0700); e != nil {
+				return probe.NewError(e)
+			}
+			return probe.NewError(os.Remove(sourceURL.Path))
+		}
+		if e := os.MkdirAll(filepath.Dir(target), 0700); e != nil {
+			return probe.NewError(e)
+		}
+		return probe.NewError(os.Rename(sourceURL.Path, target))
+	}
+	// Prefixes go away with the objects they hold.
+	if content.Type.IsDir() {
+		return nil
+	}
+
+	targetClnt, err := newClient(target)
+	if err != nil {
+		return err.Trace(target)
+	}
+	if err = targetClnt.Copy(sourceURL.Path, content.Size, nil, sse, sse, nil); err != nil {
+		return err.Trace(aliasedURL, target)
+	}
+	contentCh := make(chan *clientContent, 1)
+	contentCh <- &clientContent{URL: sourceURL}
+	close(contentCh)
+	isIncomplete, isRemoveBucket := false, false
+	for err = range clnt.Remove(isIncomplete, isRemoveBucket, contentCh) {
+		return err.Trace(aliasedURL)
+	}
+	return nil
+}
+
+// moveToTrash - moves the object or local file aliasedURL, described by
+// content, to the trash, keeping the encryption of aliasedURL.
+func moveToTrash(aliasedURL string, content *clientContent, encKeyDB map[string][]prefixSSEPair) *probe.Error {
+	if isTrashURL(aliasedURL) {
+		return errInvalidArgument().Trace(aliasedURL)
+	}
+	target, err := trashURL(aliasedURL)
+	if err != nil {
+		return err.Trace(aliasedURL)
+	}
+	alias, _ := url2Alias(aliasedURL)
+	return moveTo(content, aliasedURL, target, getSSE(aliasedURL, encKeyDB[alias]))
+}
+
+// isRestoreOverwrite - reports whether restoring content to restored
+// would overwrite an existing object or local file.
+func isRestoreOverwrite(content *clientContent, restored string, sse encrypt.ServerSide) bool {
+	if content.Type.IsDir() {
+		return false
+	}
+	clnt, err := newClient(restored)
+	if err != nil {
+		return false
+	}
+	_, err = clnt.Stat(false, false, false, sse)
+	return err == nil
+}
+
+// restoreFromTrash - moves the objects or local files in the trash under
+// aliasedURL back, calling fn with each restored location. Existing
+// objects are not overwritten unless isForce is set.
+func restoreFromTrash(aliasedURL string, isForce bool, encKeyDB map[string][]prefixSSEPair, fn func(restored string)) *probe.Error {
+	trash, err := trashURL(aliasedURL)
+	if err != nil {
+		return err.Trace(aliasedURL)
+	}
+	alias, _ := url2Alias(aliasedURL)
+	if _, _, hostCfg, _ := expandAlias(aliasedURL); hostCfg == nil {
+		alias = ""
+	}
+
+	clnt, err := newClient(trash)
+	if err != nil {
+		return err.Trace(trash)
+	}
+	found := false
+	isRecursive, isIncomplete := true, false
+	for content := range clnt.List(isRecursive, isIncomplete, false, DirNone) {
+		if content.Err != nil {
+			if _, ok := content.Err.ToGoError().(PathNotFound); ok {
+				break
+			}
+			return content.Err.Trace(trash)
+		}
+		var restored string
+		if alias == "" {
+			trashDir, err := getTrashDir()
+			if err != nil {
+				return err.Trace(aliasedURL)
+			}
+			rel, e := filepath.Rel(trashDir, content.URL.Path)
+			if e != nil {
+				return probe.NewError(e).Trace(content.URL.Path)
+			}
+			restored = string(filepath.Separator) + rel
+			if absPath, e := filepath.Abs(aliasedURL); e == nil {
+				restored = filepath.VolumeName(absPath) + restored
+			}
+		} else {
+			objectPath := strings.Replace(filepath.ToSlash(content.URL.Path), "/"+trashPrefix, "/", 1)
+			restored = alias + objectPath
+		}
+		restoredFrom := content.URL.Path
+		if alias != "" {
+			restoredFrom = alias + filepath.ToSlash(content.URL.Path)
+		}
+		sse := getSSE(restored, encKeyDB[alias])
+		if !isForce && isRestoreOverwrite(content, restored, sse) {
+			return errRestoreOverwrite(restored).Trace(restoredFrom)
+		}
+		if err = moveTo(content, restoredFrom, restored, sse); err != nil {
+			return err.Trace(restoredFrom, restored)
+		}
+		found = true
+		fn(restored)
+	}
+	if !found {
+		return errTrashEmpty(aliasedURL).Trace(aliasedURL)
+	}
+	if alias == "" {
+		// Drop the folders left empty in the trash.
+		os.RemoveAll(trash)
+	}
+	return nil
+}
@@ -0,0 +1,224 @@
+/*
+ * MinIO Client (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/fatih/color"
+)
+
+func TestTrashLocalRoundTrip(t *testing.T) {
+	dir, e := ioutil.TempDir("", "mc-trash-")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.RemoveAll(dir)
+	savedDir, savedOutput := mcCustomConfigDir, color.Output
+	defer func() { mcCustomConfigDir, color.Output = savedDir, savedOutput }()
+	mcCustomConfigDir, color.Output = filepath.Join(dir, "config"), ioutil.Discard
+	defer withTestConfig(nil)()
+
+	data := filepath.Join(dir, "data")
+	files := map[string]string{"a.txt": "first", filepath.Join("sub", "b.txt"): "second"}
+	for name, content := range files {
+		if e = os.MkdirAll(filepath.Dir(filepath.Join(data, name)), 0700); e != nil {
+			t.Fatal(e)
+		}
+		if e = ioutil.WriteFile(filepath.Join(data, name), []byte(content), 0600); e != nil {
+			t.Fatal(e)
+		}
+	}
+
+	isIncomplete, isFake, isTrash := false, false, true
+	if e = removeRecursive(data, isIncomplete, isFake, isTrash, "", "", nil, nil); e != nil {
+		t.Fatal(e)
+	}
+	for name := range files {
+		if _, e = os.Stat(filepath.Join(data, name)); !os.IsNotExist(e) {
+			t.Fatalf("Expected %s to be removed: %v", name, e)
+		}
+	}
+	trash, err := trashURL(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if content, e := ioutil.ReadFile(filepath.Join(trash, "sub", "b.txt")); e != nil || string(content) != "second" {
+		t.Fatalf("Expected the file to be in the trash, got %q: %v", content, e)
+	}
+
+	// Files created since are not overwritten unless forced.
+	if e = ioutil.WriteFile(filepath.Join(data, "a.txt"), []byte("newer"), 0600); e != nil {
+		t.Fatal(e)
+	}
+	if err = restoreFromTrash(data, false, nil, func(string) {}); err == nil {
+		t.Fatal("Expected restoring over an existing file to fail")
+	}
+
+	var restored []string
+	if err = restoreFromTrash(data, true, nil, func(path string) { restored = append(restored, path) }); err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(restored)
+	if expected := []string{filepath.Join(data, "a.txt"), filepath.Join(data, "sub", "b.txt")}; !reflect.DeepEqual(restored, expected) {
+		t.Fatalf("Expected %v to be restored, got %v", expected, restored)
+	}
+	for name, content := range files {
+		if got, e := ioutil.ReadFile(filepath.Join(data, name)); e != nil || string(got) != content {
+			t.Fatalf("%s: Expected %q, got %q: %v", name, content, got, e)
+		}
+	}
+	if _, e = os.Stat(trash); !os.IsNotExist(e) {
+		t.Fatalf("Expected the trash to be cleaned up: %v", e)
+	}
+	if err = restoreFromTrash(data, false, nil, func(string) {}); err == nil {
+		t.Fatal("Expected restoring an empty trash to fail")
+	}
+}
+
+func TestTrashObjectRoundTrip(t *testing.T) {
+	savedOutput := color.Output
+	defer func() { color.Output = savedOutput }()
+	color.Output = ioutil.Discard
+
+	handler := copyRequestRecorder{fakeS3Server: newFakeS3Server(map[string][]byte{
+		"bucket/docs/a": []byte("first"),
+		"bucket/docs/b": []byte("second"),
+		"bucket/keep":   []byte("third"),
+	}), copies: map[string]http.Header{}}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+	defer withTestConfig(map[string]hostConfigV9{"myminio": testHost(server.URL)})()
+
+	keys := func() []string {
+		var keys []string
+		for key := range handler.objects {
+			keys = append(keys, strings.TrimPrefix(key, "bucket/"))
+		}
+		sort.Strings(keys)
+		return keys
+	}
+
+	encKeyDB, err := parseAndValidateEncryptionKeys("myminio/bucket/docs/a=32byteslongsecretkeymustbegiven1", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	isIncomplete, isFake, isForce, isTrash := false, false, false, true
+	if e := removeSingle("myminio/bucket/docs/a", isIncomplete, isFake, isForce, isTrash, "", "", encKeyDB); e != nil {
+		t.Fatal(e)
+	}
+	// Encrypted objects stay encrypted with their key in the trash.
+	copied := handler.copies["bucket/.mc-trash/docs/a"]
+	if copied.Get("X-Amz-Server-Side-Encryption-Customer-Algorithm") == "" || copied.Get("X-Amz-Copy-Source-Server-Side-Encryption-Customer-Algorithm") == "" {
+		t.Fatalf("Expected the copy to the trash to be encrypted with the key, got %v", copied)
+	}
+	// Objects already in the trash are not moved again.
+	if e := removeRecursive("myminio/bucket", isIncomplete, isFake, isTrash, "", "", nil, nil); e != nil {
+		t.Fatal(e)
+	}
+	if expected := []string{".mc-trash/docs/a", ".mc-trash/docs/b", ".mc-trash/keep"}; !reflect.DeepEqual(keys(), expected) {
+		t.Fatalf("Expected %v, got %v", expected, keys())
+	}
+
+	// Objects uploaded since are not overwritten unless forced.
+	handler.objects["bucket/docs/a"] = []byte("newer")
+	if err = restoreFromTrash("myminio/bucket/docs/", false, encKeyDB, func(string) {}); err == nil {
+		t.Fatal("Expected restoring over an existing object to fail")
+	}
+	if string(handler.objects["bucket/docs/a"]) != "newer" {
+		t.Fatalf("Expected the existing object to be kept, got %q", handler.objects["bucket/docs/a"])
+	}
+	if err = restoreFromTrash("myminio/bucket/docs/", true, encKeyDB, func(string) {}); err != nil {
+		t.Fatal(err)
+	}
+	if expected := []string{".mc-trash/keep", "docs/a", "docs/b"}; !reflect.DeepEqual(keys(), expected) {
+		t.Fatalf("Expected %v, got %v", expected, keys())
+	}
+	if string(handler.objects["bucket/docs/a"]) != "first" {
+		t.Fatalf("Expected the restored object to keep its data, got %q", handler.objects["bucket/docs/a"])
+	}
+
+	if e := emptyTrash("myminio/bucket"); e != nil {
+		t.Fatal(e)
+	}
+	if expected := []string{"docs/a", "docs/b"}; !reflect.DeepEqual(keys(), expected) {
+		t.Fatalf("Expected %v, got %v", expected, keys())
+	}
+}
+
+// Tests that files failing to move to the trash are reported and do not
+// stop the others from being moved.
+func TestTrashRecursiveFailures(t *testing.T) {
+	dir, e := ioutil.TempDir("", "mc-trash-")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer os.RemoveAll(dir)
+	savedDir, savedOutput := mcCustomConfigDir, color.Output
+	defer func() { mcCustomConfigDir, color.Output = savedDir, savedOutput }()
+	mcCustomConfigDir, color.Output = filepath.Join(dir, "config"), ioutil.Discard
+	defer withTestConfig(nil)()
+
+	data := filepath.Join(dir, "data")
+	files := []string{filepath.Join("a", "x.txt"), "b.txt"}
+	for _, name := range files {
+		if e = os.MkdirAll(filepath.Dir(filepath.Join(data, name)), 0700); e != nil {
+			t.Fatal(e)
+		}
+		if e = ioutil.WriteFile(filepath.Join(data, name), []byte(name), 0600); e != nil {
+			t.Fatal(e)
+		}
+	}
+	trash, err := trashURL(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A file in the way of the directory of a/x.txt in the trash.
+	if e = os.MkdirAll(trash, 0700); e != nil {
+		t.Fatal(e)
+	}
+	if e = ioutil.WriteFile(filepath.Join(trash, "a"), nil, 0600); e != nil {
+		t.Fatal(e)
+	}
+
+	failures := newErrorSummary()
+	isIncomplete, isFake, isTrash := false, false, true
+	if e = removeRecursive(data, isIncomplete, isFake, isTrash, "", "", nil, failures); e == nil {
+		t.Fatal("Expected the failure to move a/x.txt to the trash to be reported")
+	}
+	// The directory a is left in place with the file.
+	if msg := failures.message(); msg.Failed != 2 {
+		t.Fatalf("Expected a/x.txt and a to fail, got %d failures", msg.Failed)
+	}
+	if _, e = os.Stat(filepath.Join(data, "a", "x.txt")); e != nil {
+		t.Fatalf("Expected a/x.txt to be left in place: %v", e)
+	}
+	if _, e = os.Stat(filepath.Join(data, "b.txt")); !os.IsNotExist(e) {
+		t.Fatalf("Expected b.txt to be moved to the trash: %v", e)
+	}
+	if content, e := ioutil.ReadFile(filepath.Join(trash, "b.txt")); e != nil || string(content) != "b.txt" {
+		t.Fatalf("Expected b.txt to be in the trash, got %q: %v", content, e)
+	}
+}
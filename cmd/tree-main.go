@@ -275,7 +275,7 @@ func mainTree(ctx *cli.Context) error {
 			}
 			clnt, err := newClientFromAlias(targetAlias, targetURL)
 			fatalIf(err.Trace(targetURL), "Unable to initialize target `"+targetURL+"`.")
-			if e := doList(clnt, true, false); e != nil {
+			if e := doList(clnt, true, false, false); e != nil {
 				cErr = e
 			}
 		}
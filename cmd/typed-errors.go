@@ -119,9 +119,97 @@ var errSourceIsDir = func(URL string) *probe.Error {
 	return probe.NewError(sourceIsDirErr(errors.New(msg))).Untrace()
 }
 
+type pathTraversalErr error
+
+var errPathTraversal = func(URL string) *probe.Error {
+	msg := "Target `" + URL + "` resolves outside of the target folder, the object key is not a safe local path."
+	return probe.NewError(pathTraversalErr(errors.New(msg))).Untrace()
+}
+
+type trashEmptyErr error
+
+var errTrashEmpty = func(URL string) *probe.Error {
+	msg := "Nothing removed from `" + URL + "` is in the trash."
+	return probe.NewError(trashEmptyErr(errors.New(msg))).Untrace()
+}
+
+type restoreOverwriteErr error
+
+var errRestoreOverwrite = func(URL string) *probe.Error {
+	msg := "Restoring `" + URL + "` would overwrite an existing object. Use `--force` to override this behavior."
+	return probe.NewError(restoreOverwriteErr(errors.New(msg))).Untrace()
+}
+
 type conflictSSEErr error
 
 var errConflictSSE = func(sseServer, sseKeys string) *probe.Error {
 	err := fmt.Errorf("SSE alias '%s' overlaps with SSE-C aliases '%s'", sseServer, sseKeys)
 	return probe.NewError(conflictSSEErr(err)).Untrace()
 }
+
+type invalidPartSizeErr error
+
+var errInvalidPartSize = func(partSize string, reason string) *probe.Error {
+	msg := "Invalid part size `" + partSize + "`, " + reason + "."
+	return probe.NewError(invalidPartSizeErr(errors.New(msg))).Untrace()
+}
+
+type invalidSizeErr error
+
+var errInvalidSize = func(size string, reason string) *probe.Error {
+	msg := "Invalid size `" + size + "`, " + reason + "."
+	return probe.NewError(invalidSizeErr(errors.New(msg))).Untrace()
+}
+
+type invalidClientCertErr error
+
+var errInvalidClientCert = func(certFile, keyFile, reason string) *probe.Error {
+	msg := "Invalid client certificate `" + certFile + "` with key `" + keyFile + "`, " + reason + "."
+	return probe.NewError(invalidClientCertErr(errors.New(msg))).Untrace()
+}
+
+type nameCollisionErr error
+
+var errNameCollision = func(source, name string) *probe.Error {
+	msg := "Name `" + name + "` of `" + source + "` is already used in the target folder."
+	return probe.NewError(nameCollisionErr(errors.New(msg))).Untrace()
+}
+
+type invalidRangeErr error
+
+var errInvalidRange = func(offset, length, size int64) *probe.Error {
+	msg := fmt.Sprintf("Requested range (offset %d, length %d) is out of bounds", offset, length)
+	if size >= 0 {
+		msg += fmt.Sprintf(" of %d bytes", size)
+	}
+	msg += "."
+	return probe.NewError(invalidRangeErr(errors.New(msg))).Untrace()
+}
+
+type configDisabledErr error
+
+var errConfigDisabled = func() *probe.Error {
+	msg := "No config file is used with `--no-config`."
+	return probe.NewError(configDisabledErr(errors.New(msg))).Untrace()
+}
+
+// envVarNotSetErr is a struct, unlike the error types above, so that
+// it can be told apart with a type assertion.
+type envVarNotSetErr struct {
+	name, alias string
+}
+
+func (e envVarNotSetErr) Error() string {
+	return "Environment variable `" + e.name + "` referenced by the config of alias `" + e.alias + "` is not set."
+}
+
+var errEnvVarNotSet = func(name, alias string) *probe.Error {
+	return probe.NewError(envVarNotSetErr{name: name, alias: alias}).Untrace()
+}
+
+type removeAllRequiredErr error
+
+var errRemoveAllRequired = func(url string) *probe.Error {
+	msg := "Removing `" + url + "` recursively removes all objects of the bucket. If you are really sure, retry this command with `--all`."
+	return probe.NewError(removeAllRequiredErr(errors.New(msg))).Untrace()
+}
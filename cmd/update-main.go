@@ -455,7 +455,7 @@ func mainUpdate(ctx *cli.Context) {
 	updateMsg, sha256Hex, _, latestReleaseTime, err := getUpdateInfo(10 * time.Second)
 	if err != nil {
 		errorIf(err, "Unable to update ‘mc’.")
-		os.Exit(-1)
+		exit(-1)
 	}
 
 	// Nothing to update running the latest release.
@@ -465,7 +465,7 @@ func mainUpdate(ctx *cli.Context) {
 			Status:  "success",
 			Message: colorGreenBold("You are already running the most recent version of ‘mc’."),
 		})
-		os.Exit(0)
+		exit(0)
 	}
 
 	printMsg(updateMessage{
@@ -480,9 +480,9 @@ func mainUpdate(ctx *cli.Context) {
 		updateStatusMsg, err = doUpdate(sha256Hex, latestReleaseTime, true)
 		if err != nil {
 			errorIf(err, "Unable to update ‘mc’.")
-			os.Exit(-1)
+			exit(-1)
 		}
 		printMsg(updateMessage{Status: "success", Message: updateStatusMsg})
-		os.Exit(1)
+		exit(1)
 	}
 }
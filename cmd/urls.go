@@ -30,6 +30,18 @@ type URLs struct {
 	TotalSize     int64
 	encKeyDB      map[string][]prefixSSEPair
 	Error         *probe.Error `json:"-"`
+
+	// Slice of the source to copy, set by cp --offset and --length.
+	sourceOffset int64
+	sourceLength int64
+
+	// Source and target are identical, set by mirror.
+	unchanged bool
+
+	// Set only the metadata of the target instead of preserving the
+	// metadata of the source, set by cp --metadata-directive REPLACE
+	// and --no-preserve-metadata.
+	replaceMetadata bool
 }
 
 // WithError sets the error and returns object
@@ -43,7 +43,7 @@ func isErrIgnored(err *probe.Error) (ignored bool) {
 	case BrokenSymlink, TooManyLevelsSymlink, PathNotFound:
 		ignored = true
 	// Handle these specifically for object storage related errors.
-	case BucketNameEmpty, ObjectMissing, ObjectAlreadyExists:
+	case BucketNameEmpty, ObjectMissing, ObjectAlreadyExists, ObjectPreconditionFailed:
 		ignored = true
 	case ObjectAlreadyExistsAsDirectory, BucketDoesNotExist, BucketInvalid:
 		ignored = true
@@ -60,9 +60,16 @@ const (
 	letterIdxMax  = 63 / letterIdxBits   // # of letter indices fitting in 63 bits
 )
 
-// UTCNow - returns current UTC time.
+// nowFunc - returns the current time. All time dependent code must go
+// through nowFunc, or UTCNow, so that tests can freeze the clock. Its
+// monotonic clock reading is the one to measure durations with.
+var nowFunc = time.Now
+
+// UTCNow - returns current UTC time, for timestamps. UTC strips the
+// monotonic clock reading, durations between them follow the changes of
+// the wall clock.
 func UTCNow() time.Time {
-	return time.Now().UTC()
+	return nowFunc().UTC()
 }
 
 var letters = []rune("abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ")
@@ -140,10 +147,42 @@ func newS3Config(urlStr string, hostCfg *hostConfigV9) *Config {
 	s3Config.Insecure = globalInsecure
 
 	s3Config.HostURL = urlStr
+	s3Config.PartSize = globalPartSize
+	s3Config.Checksum = globalChecksumAlgorithm
+	s3Config.IfNotExists = globalIfNotExists
+	s3Config.Verify = globalVerify
+	s3Config.BypassGovernance = globalBypassGovernance
+	s3Config.SOCKS5Proxy = globalSOCKS5Proxy
+	s3Config.UserAgent = globalUserAgent
+	s3Config.Accelerate = globalAccelerate
+	s3Config.RequestPayer = globalRequestPayer
+	s3Config.MaxIdleConns = globalMaxIdleConns
+	s3Config.MaxConnsPerHost = globalMaxConnsPerHost
+	s3Config.ListParallel = globalListParallel
+	s3Config.ClientCert, s3Config.ClientKey = globalClientCert, globalClientKey
 	if hostCfg != nil {
-		s3Config.AccessKey = hostCfg.AccessKey
-		s3Config.SecretKey = hostCfg.SecretKey
+		// Requests are not signed without credentials.
+		if !globalAnonymous && !hostCfg.Anonymous {
+			s3Config.AccessKey = hostCfg.AccessKey
+			s3Config.SecretKey = hostCfg.SecretKey
+			s3Config.SessionToken = hostCfg.SessionToken
+			s3Config.Creds = hostCfg.creds
+		}
 		s3Config.Signature = hostCfg.API
+		s3Config.Region = hostCfg.Region
+		s3Config.Accelerate = s3Config.Accelerate || hostCfg.Accelerate
+		s3Config.RequestPayer = s3Config.RequestPayer || hostCfg.RequestPayer
+		if s3Config.UserAgent == "" {
+			s3Config.UserAgent = hostCfg.UserAgent
+		}
+		if s3Config.ClientCert == "" {
+			s3Config.ClientCert, s3Config.ClientKey = hostCfg.ClientCert, hostCfg.ClientKey
+		}
+		// Command line part size takes precedence over the host default,
+		// host part size is already validated while loading the config.
+		if s3Config.PartSize == 0 && hostCfg.PartSize != "" {
+			s3Config.PartSize, _ = parsePartSize(hostCfg.PartSize)
+		}
 	}
 	s3Config.Lookup = getLookupType(hostCfg.Lookup)
 	return s3Config
@@ -23,7 +23,6 @@ import (
 	"sync"
 	"syscall"
 
-	humanize "github.com/dustin/go-humanize"
 	"github.com/fatih/color"
 	"github.com/minio/cli"
 	json "github.com/minio/mc/pkg/colorjson"
@@ -122,7 +121,7 @@ func (u watchMessage) JSON() string {
 func (u watchMessage) String() string {
 	msg := console.Colorize("Time", fmt.Sprintf("[%s] ", u.Event.Time))
 	if u.Event.Type == EventCreate {
-		msg += console.Colorize("Size", fmt.Sprintf("%6s ", humanize.IBytes(uint64(u.Event.Size))))
+		msg += console.Colorize("Size", fmt.Sprintf("%6s ", humanizeSize(u.Event.Size)))
 	} else {
 		msg += fmt.Sprintf("%6s ", "")
 	}
@@ -0,0 +1,105 @@
+/*
+ * MinIO Client (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package linewriter buffers the output written by concurrent goroutines
+// and passes it on in whole lines, so that lines written at the same
+// time are never mixed up.
+package linewriter
+
+import (
+	"bytes"
+	"io"
+	"sync"
+	"time"
+)
+
+// Writer serializes writes into a buffer. The complete lines of a full
+// buffer are written to the underlying writer at once, the rest is held
+// back until its line is complete. The whole buffer is written at the
+// latest after the flush interval, prompts included.
+//
+// Each Write is kept whole, a line printed by a goroutine with a single
+// Write is never interleaved with the output of other goroutines.
+type Writer struct {
+	mutex    sync.Mutex
+	w        io.Writer
+	buf      []byte
+	size     int
+	interval time.Duration
+	timer    *time.Timer
+	err      error
+}
+
+// New returns a Writer writing to w, buffering up to size bytes for at
+// most interval.
+func New(w io.Writer, size int, interval time.Duration) *Writer {
+	return &Writer{w: w, size: size, interval: interval}
+}
+
+// Write appends p to the buffer. The error of an earlier write to the
+// underlying writer is returned, the output is discarded from then on.
+func (l *Writer) Write(p []byte) (int, error) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if l.err != nil {
+		return 0, l.err
+	}
+	l.buf = append(l.buf, p...)
+	if len(l.buf) >= l.size {
+		if err := l.writeLines(); err != nil {
+			return 0, err
+		}
+	}
+	if len(l.buf) > 0 && l.timer == nil {
+		l.timer = time.AfterFunc(l.interval, func() { l.Flush() })
+	}
+	return len(p), nil
+}
+
+// writeLines writes the complete lines of the buffer.
+func (l *Writer) writeLines() error {
+	end := bytes.LastIndexByte(l.buf, '\n') + 1
+	if end == 0 {
+		// A single line larger than the buffer is written as it is.
+		if len(l.buf) < l.size {
+			return nil
+		}
+		end = len(l.buf)
+	}
+	return l.write(end)
+}
+
+// write writes the first n bytes of the buffer.
+func (l *Writer) write(n int) error {
+	_, l.err = l.w.Write(l.buf[:n])
+	l.buf = l.buf[:copy(l.buf, l.buf[n:])]
+	return l.err
+}
+
+// Flush writes the whole buffer, the last line included even if it is
+// not complete.
+func (l *Writer) Flush() error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if l.timer != nil {
+		l.timer.Stop()
+		l.timer = nil
+	}
+	if l.err != nil || len(l.buf) == 0 {
+		return l.err
+	}
+	return l.write(len(l.buf))
+}
@@ -0,0 +1,116 @@
+/*
+ * MinIO Client (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package linewriter
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	. "gopkg.in/check.v1"
+)
+
+func Test(t *testing.T) { TestingT(t) }
+
+type MySuite struct{}
+
+var _ = Suite(&MySuite{})
+
+// chunkWriter records every write, like the writes to a pipe.
+type chunkWriter struct {
+	mutex  sync.Mutex
+	chunks []string
+}
+
+func (w *chunkWriter) Write(p []byte) (int, error) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.chunks = append(w.chunks, string(p))
+	return len(p), nil
+}
+
+func (w *chunkWriter) output() string {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	return strings.Join(w.chunks, "")
+}
+
+func (s *MySuite) TestConcurrentLines(c *C) {
+	out := &chunkWriter{}
+	w := New(out, 256, time.Hour)
+
+	const workers, lines = 16, 200
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < lines; j++ {
+				fmt.Fprintf(w, "worker %d line %d %s\n", i, j, strings.Repeat("x", j%50))
+			}
+		}(i)
+	}
+	wg.Wait()
+	c.Assert(w.Flush(), IsNil)
+
+	// Every write holds whole lines only, none is split between writes.
+	for _, chunk := range out.chunks {
+		c.Assert(strings.HasSuffix(chunk, "\n"), Equals, true, Commentf("chunk %q", chunk))
+	}
+	expected := make(map[string]bool)
+	for i := 0; i < workers; i++ {
+		for j := 0; j < lines; j++ {
+			expected[fmt.Sprintf("worker %d line %d %s", i, j, strings.Repeat("x", j%50))] = true
+		}
+	}
+	seen := make(map[string]bool)
+	for _, line := range strings.Split(strings.TrimSuffix(out.output(), "\n"), "\n") {
+		c.Assert(expected[line], Equals, true, Commentf("interleaved line %q", line))
+		seen[line] = true
+	}
+	c.Assert(len(seen), Equals, workers*lines)
+}
+
+func (s *MySuite) TestFlush(c *C) {
+	out := &chunkWriter{}
+	w := New(out, 1024, time.Hour)
+
+	// Nothing is written before the buffer is full or flushed.
+	fmt.Fprint(w, "first\nsecond")
+	c.Assert(out.output(), Equals, "")
+	c.Assert(w.Flush(), IsNil)
+	c.Assert(out.output(), Equals, "first\nsecond")
+
+	// A full buffer writes its complete lines, the incomplete one waits.
+	w = New(out, 8, time.Hour)
+	out.chunks = nil
+	fmt.Fprint(w, "0123\n4567\n89")
+	c.Assert(out.output(), Equals, "0123\n4567\n")
+	c.Assert(w.Flush(), IsNil)
+	c.Assert(out.output(), Equals, "0123\n4567\n89")
+
+	// Prompts are written after the interval.
+	w = New(out, 1024, 10*time.Millisecond)
+	out.chunks = nil
+	fmt.Fprint(w, "Enter secret: ")
+	for deadline := time.Now().Add(5 * time.Second); out.output() == "" && time.Now().Before(deadline); {
+		time.Sleep(time.Millisecond)
+	}
+	c.Assert(out.output(), Equals, "Enter secret: ")
+}
@@ -0,0 +1,155 @@
+/*
+ * MinIO Client (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package oplog writes a log of operations, one JSON object per line,
+// to a file which is rotated once it reaches a maximum size.
+package oplog
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"sync"
+	"time"
+)
+
+// Entry is a single logged operation.
+type Entry struct {
+	Time   time.Time `json:"time"`
+	Action string    `json:"action"`
+	Source string    `json:"source,omitempty"`
+	Target string    `json:"target,omitempty"`
+	Status string    `json:"status"`
+	Bytes  int64     `json:"bytes"`
+	Error  string    `json:"error,omitempty"`
+}
+
+// Logger appends entries to a log file. A nil Logger discards all
+// entries, so callers need not check whether logging is enabled.
+type Logger struct {
+	mutex   sync.Mutex
+	path    string
+	maxSize int64
+	file    *os.File
+	writer  *bufio.Writer
+	size    int64
+}
+
+// New opens the log file at path for appending. Once writing an entry
+// would grow the file beyond maxSize bytes it is renamed to `path.1`,
+// replacing any previous one, and a new file is started. A maxSize of
+// zero disables rotation.
+func New(path string, maxSize int64) (*Logger, error) {
+	l := &Logger{path: path, maxSize: maxSize}
+	if err := l.open(); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+func (l *Logger) open() error {
+	file, err := os.OpenFile(l.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return err
+	}
+	st, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	l.file = file
+	l.writer = bufio.NewWriter(file)
+	l.size = st.Size()
+	return nil
+}
+
+func (l *Logger) rotate() error {
+	if err := l.closeFile(); err != nil {
+		return err
+	}
+	if err := os.Rename(l.path, l.path+".1"); err != nil {
+		return err
+	}
+	return l.open()
+}
+
+func (l *Logger) closeFile() error {
+	if err := l.writer.Flush(); err != nil {
+		l.file.Close()
+		return err
+	}
+	return l.file.Close()
+}
+
+// Log appends an entry, its time is set to now when zero. Entries
+// logged once the logger is closed fail with os.ErrClosed.
+func (l *Logger) Log(entry Entry) error {
+	if l == nil {
+		return nil
+	}
+	if entry.Time.IsZero() {
+		entry.Time = time.Now().UTC()
+	}
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if l.file == nil {
+		return os.ErrClosed
+	}
+	if l.maxSize > 0 && l.size > 0 && l.size+int64(len(line)) > l.maxSize {
+		if err = l.rotate(); err != nil {
+			return err
+		}
+	}
+	n, err := l.writer.Write(line)
+	l.size += int64(n)
+	return err
+}
+
+// Flush writes buffered entries to the file.
+func (l *Logger) Flush() error {
+	if l == nil {
+		return nil
+	}
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if l.file == nil {
+		return nil
+	}
+	return l.writer.Flush()
+}
+
+// Close flushes buffered entries and closes the file. Closing again
+// does nothing, so that an interrupted mirror and its deferred
+// cleanup may both close the logger.
+func (l *Logger) Close() error {
+	if l == nil {
+		return nil
+	}
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if l.file == nil {
+		return nil
+	}
+	err := l.closeFile()
+	l.file, l.writer = nil, nil
+	return err
+}
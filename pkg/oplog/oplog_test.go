@@ -0,0 +1,91 @@
+/*
+ * MinIO Client (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package oplog
+
+import (
+	"bufio"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "gopkg.in/check.v1"
+)
+
+func Test(t *testing.T) { TestingT(t) }
+
+type MySuite struct{}
+
+var _ = Suite(&MySuite{})
+
+// readEntries - returns the entries logged to the file.
+func readEntries(c *C, path string) []Entry {
+	file, err := os.Open(path)
+	c.Assert(err, IsNil)
+	defer file.Close()
+	var entries []Entry
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var entry Entry
+		c.Assert(json.Unmarshal(scanner.Bytes(), &entry), IsNil)
+		entries = append(entries, entry)
+	}
+	c.Assert(scanner.Err(), IsNil)
+	return entries
+}
+
+// Tests entries are buffered until flushed and files are rotated by size.
+func (s *MySuite) TestLogger(c *C) {
+	dir, err := ioutil.TempDir("", "oplog-")
+	c.Assert(err, IsNil)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "mirror.log")
+
+	entry := Entry{Time: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), Action: "copy", Source: "src/a", Target: "dst/a", Status: "success", Bytes: 5}
+	line, err := json.Marshal(entry)
+	c.Assert(err, IsNil)
+	l, err := New(path, int64(2*(len(line)+1)))
+	c.Assert(err, IsNil)
+
+	c.Assert(l.Log(entry), IsNil)
+	c.Assert(readEntries(c, path), HasLen, 0)
+	c.Assert(l.Flush(), IsNil)
+	entries := readEntries(c, path)
+	c.Assert(entries, HasLen, 1)
+	c.Assert(entries[0], DeepEquals, entry)
+
+	// The third entry does not fit anymore, its time is set as it is missing.
+	c.Assert(l.Log(entry), IsNil)
+	entry.Time = time.Time{}
+	c.Assert(l.Log(entry), IsNil)
+	c.Assert(l.Close(), IsNil)
+	// Closing again does nothing, later entries are refused.
+	c.Assert(l.Close(), IsNil)
+	c.Assert(l.Flush(), IsNil)
+	c.Assert(l.Log(entry), Equals, os.ErrClosed)
+	c.Assert(readEntries(c, path+".1"), HasLen, 2)
+	entries = readEntries(c, path)
+	c.Assert(entries, HasLen, 1)
+	c.Assert(entries[0].Time.IsZero(), Equals, false)
+
+	// A nil logger discards entries.
+	var nilLogger *Logger
+	c.Assert(nilLogger.Log(entry), IsNil)
+	c.Assert(nilLogger.Close(), IsNil)
+}